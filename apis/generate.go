@@ -0,0 +1,28 @@
+// +build generate
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// NOTE(negz): See the below link for details on what is happening here.
+// https://github.com/golang/go/wiki/Modules#how-can-i-track-tool-dependencies-for-a-module
+
+// Generate deepcopy methodsets
+//go:generate controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./...
+
+// Generate CRD manifests
+//go:generate controller-gen crd:trivialVersions=true,preserveUnknownFields=false paths=./... output:artifacts:config=../config/crd
+
+package apis
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A DeadLetter is an object that is no longer reconciled because reconciling
+// it failed repeatedly. It is reconciled again once its spec changes or an
+// immediate reconcile of it is requested.
+type DeadLetter struct {
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Failures is the number of consecutive failed reconciles of the object.
+	Failures int `json:"failures"`
+
+	// Message of the object's last failed reconcile.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Since is the time at which the object became a dead letter.
+	Since metav1.Time `json:"since"`
+}
+
+// A DeadLetterReportStatus represents the observed state of a
+// DeadLetterReport.
+type DeadLetterReportStatus struct {
+	// DeadLetters in the report's namespace.
+	// +optional
+	DeadLetters []DeadLetter `json:"deadLetters,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DeadLetterReport aggregates the objects in a namespace that are no longer
+// reconciled because reconciling them failed repeatedly.
+// +kubebuilder:resource:categories={crossplane,oam}
+type DeadLetterReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status DeadLetterReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DeadLetterReportList contains a list of DeadLetterReport.
+type DeadLetterReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeadLetterReport `json:"items"`
+}
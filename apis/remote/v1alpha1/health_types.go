@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// An ObjectHealth is the observed health of a workload or trait.
+type ObjectHealth struct {
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Ready is the status of the object's Ready condition, if any.
+	// +optional
+	Ready corev1.ConditionStatus `json:"ready,omitempty"`
+
+	// Synced is the status of the object's Synced condition, if any.
+	// +optional
+	Synced corev1.ConditionStatus `json:"synced,omitempty"`
+
+	// Degraded is the status of the object's Degraded condition, if any.
+	// +optional
+	Degraded corev1.ConditionStatus `json:"degraded,omitempty"`
+
+	// Message of the condition that makes the object unhealthy, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ComponentHealth is the observed health of the workload and traits of a
+// component of an ApplicationConfiguration.
+type ComponentHealth struct {
+	// ComponentName of the component.
+	ComponentName string `json:"componentName"`
+
+	// Workload of the component.
+	Workload ObjectHealth `json:"workload"`
+
+	// Traits of the component's workload.
+	// +optional
+	Traits []ObjectHealth `json:"traits,omitempty"`
+}
+
+// An ApplicationHealthStatus represents the observed state of an
+// ApplicationHealth.
+type ApplicationHealthStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Components of the ApplicationConfiguration.
+	// +optional
+	Components []ComponentHealth `json:"components,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An ApplicationHealth rolls the Ready, Synced, and Degraded conditions of the
+// workloads and traits of an ApplicationConfiguration up into a single status.
+// It is named after, and controlled by, its ApplicationConfiguration.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DEGRADED",type="string",JSONPath=".status.conditions[?(@.type=='Degraded')].status"
+type ApplicationHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ApplicationHealthStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationHealthList contains a list of ApplicationHealth.
+type ApplicationHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationHealth `json:"items"`
+}
+
+// GetCondition of this ApplicationHealth.
+func (h *ApplicationHealth) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return h.Status.GetCondition(ct)
+}
+
+// SetConditions of this ApplicationHealth.
+func (h *ApplicationHealth) SetConditions(c ...runtimev1alpha1.Condition) {
+	h.Status.SetConditions(c...)
+}
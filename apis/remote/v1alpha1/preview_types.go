@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// An EnvOverride overrides an environment variable of the containers of a
+// preview environment's workload.
+type EnvOverride struct {
+	// Name of the environment variable.
+	Name string `json:"name"`
+
+	// Value of the environment variable.
+	Value string `json:"value"`
+
+	// Containers whose environment variable should be overridden. The
+	// environment variable is overridden in all containers if none are
+	// specified.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+}
+
+// A PreviewEnvironmentSpec defines the desired state of a PreviewEnvironment.
+type PreviewEnvironmentSpec struct {
+	// TemplateReference to the ContainerizedWorkload that is copied into the
+	// preview environment. The workload must be in the same namespace as the
+	// PreviewEnvironment.
+	TemplateReference corev1.LocalObjectReference `json:"templateRef"`
+
+	// Suffix appended to the name of the template workload to name its copy,
+	// for example the name of a branch or the number of a pull request.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Suffix string `json:"suffix"`
+
+	// TargetNamespace in which the copy of the template workload is created.
+	// Defaults to the namespace of the PreviewEnvironment.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// Env overrides the environment variables of the copy's containers.
+	// +optional
+	Env []EnvOverride `json:"env,omitempty"`
+
+	// TTL after which the copy's package is torn down, measured from the time
+	// the copy was created.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// A PreviewWorkloadReference refers to the copy of a template workload.
+type PreviewWorkloadReference struct {
+	// Namespace of the copy.
+	Namespace string `json:"namespace"`
+
+	// Name of the copy.
+	Name string `json:"name"`
+}
+
+// A PreviewEnvironmentStatus represents the observed state of a
+// PreviewEnvironment.
+type PreviewEnvironmentStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// WorkloadReference to the copy of the template workload.
+	// +optional
+	WorkloadReference *PreviewWorkloadReference `json:"workloadRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PreviewEnvironment stamps a copy of a template workload, for example a
+// preview of a branch or pull request, optionally with a TTL after which the
+// copy's package is torn down.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type PreviewEnvironment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PreviewEnvironmentSpec   `json:"spec,omitempty"`
+	Status PreviewEnvironmentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PreviewEnvironmentList contains a list of PreviewEnvironment.
+type PreviewEnvironmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PreviewEnvironment `json:"items"`
+}
+
+// GetCondition of this PreviewEnvironment.
+func (pe *PreviewEnvironment) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return pe.Status.GetCondition(ct)
+}
+
+// SetConditions of this PreviewEnvironment.
+func (pe *PreviewEnvironment) SetConditions(c ...runtimev1alpha1.Condition) {
+	pe.Status.SetConditions(c...)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// Package type metadata.
+const (
+	Group   = "remote.oam.crossplane.io"
+	Version = "v1alpha1"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: SchemeGroupVersion}
+)
+
+// VolumeMountTrait type metadata.
+var (
+	VolumeMountTraitKind             = reflect.TypeOf(VolumeMountTrait{}).Name()
+	VolumeMountTraitGroupKind        = schema.GroupKind{Group: Group, Kind: VolumeMountTraitKind}.String()
+	VolumeMountTraitKindAPIVersion   = VolumeMountTraitKind + "." + SchemeGroupVersion.String()
+	VolumeMountTraitGroupVersionKind = SchemeGroupVersion.WithKind(VolumeMountTraitKind)
+)
+
+// BundleTrait type metadata.
+var (
+	BundleTraitKind             = reflect.TypeOf(BundleTrait{}).Name()
+	BundleTraitGroupKind        = schema.GroupKind{Group: Group, Kind: BundleTraitKind}.String()
+	BundleTraitKindAPIVersion   = BundleTraitKind + "." + SchemeGroupVersion.String()
+	BundleTraitGroupVersionKind = SchemeGroupVersion.WithKind(BundleTraitKind)
+)
+
+// PatchTrait type metadata.
+var (
+	PatchTraitKind             = reflect.TypeOf(PatchTrait{}).Name()
+	PatchTraitGroupKind        = schema.GroupKind{Group: Group, Kind: PatchTraitKind}.String()
+	PatchTraitKindAPIVersion   = PatchTraitKind + "." + SchemeGroupVersion.String()
+	PatchTraitGroupVersionKind = SchemeGroupVersion.WithKind(PatchTraitKind)
+)
+
+// SidecarInjectionTrait type metadata.
+var (
+	SidecarInjectionTraitKind             = reflect.TypeOf(SidecarInjectionTrait{}).Name()
+	SidecarInjectionTraitGroupKind        = schema.GroupKind{Group: Group, Kind: SidecarInjectionTraitKind}.String()
+	SidecarInjectionTraitKindAPIVersion   = SidecarInjectionTraitKind + "." + SchemeGroupVersion.String()
+	SidecarInjectionTraitGroupVersionKind = SchemeGroupVersion.WithKind(SidecarInjectionTraitKind)
+)
+
+// TrafficSplitTrait type metadata.
+var (
+	TrafficSplitTraitKind             = reflect.TypeOf(TrafficSplitTrait{}).Name()
+	TrafficSplitTraitGroupKind        = schema.GroupKind{Group: Group, Kind: TrafficSplitTraitKind}.String()
+	TrafficSplitTraitKindAPIVersion   = TrafficSplitTraitKind + "." + SchemeGroupVersion.String()
+	TrafficSplitTraitGroupVersionKind = SchemeGroupVersion.WithKind(TrafficSplitTraitKind)
+)
+
+// ResourceQuotaTrait type metadata.
+var (
+	ResourceQuotaTraitKind             = reflect.TypeOf(ResourceQuotaTrait{}).Name()
+	ResourceQuotaTraitGroupKind        = schema.GroupKind{Group: Group, Kind: ResourceQuotaTraitKind}.String()
+	ResourceQuotaTraitKindAPIVersion   = ResourceQuotaTraitKind + "." + SchemeGroupVersion.String()
+	ResourceQuotaTraitGroupVersionKind = SchemeGroupVersion.WithKind(ResourceQuotaTraitKind)
+)
+
+// SecurityContextTrait type metadata.
+var (
+	SecurityContextTraitKind             = reflect.TypeOf(SecurityContextTrait{}).Name()
+	SecurityContextTraitGroupKind        = schema.GroupKind{Group: Group, Kind: SecurityContextTraitKind}.String()
+	SecurityContextTraitKindAPIVersion   = SecurityContextTraitKind + "." + SchemeGroupVersion.String()
+	SecurityContextTraitGroupVersionKind = SchemeGroupVersion.WithKind(SecurityContextTraitKind)
+)
+
+// CronScalerTrait type metadata.
+var (
+	CronScalerTraitKind             = reflect.TypeOf(CronScalerTrait{}).Name()
+	CronScalerTraitGroupKind        = schema.GroupKind{Group: Group, Kind: CronScalerTraitKind}.String()
+	CronScalerTraitKindAPIVersion   = CronScalerTraitKind + "." + SchemeGroupVersion.String()
+	CronScalerTraitGroupVersionKind = SchemeGroupVersion.WithKind(CronScalerTraitKind)
+)
+
+// PodDisruptionBudgetTrait type metadata.
+var (
+	PodDisruptionBudgetTraitKind             = reflect.TypeOf(PodDisruptionBudgetTrait{}).Name()
+	PodDisruptionBudgetTraitGroupKind        = schema.GroupKind{Group: Group, Kind: PodDisruptionBudgetTraitKind}.String()
+	PodDisruptionBudgetTraitKindAPIVersion   = PodDisruptionBudgetTraitKind + "." + SchemeGroupVersion.String()
+	PodDisruptionBudgetTraitGroupVersionKind = SchemeGroupVersion.WithKind(PodDisruptionBudgetTraitKind)
+)
+
+// AnnotationsAndLabelsTrait type metadata.
+var (
+	AnnotationsAndLabelsTraitKind             = reflect.TypeOf(AnnotationsAndLabelsTrait{}).Name()
+	AnnotationsAndLabelsTraitGroupKind        = schema.GroupKind{Group: Group, Kind: AnnotationsAndLabelsTraitKind}.String()
+	AnnotationsAndLabelsTraitKindAPIVersion   = AnnotationsAndLabelsTraitKind + "." + SchemeGroupVersion.String()
+	AnnotationsAndLabelsTraitGroupVersionKind = SchemeGroupVersion.WithKind(AnnotationsAndLabelsTraitKind)
+)
+
+// Bundle type metadata.
+var (
+	BundleKind             = reflect.TypeOf(Bundle{}).Name()
+	BundleGroupKind        = schema.GroupKind{Group: Group, Kind: BundleKind}.String()
+	BundleKindAPIVersion   = BundleKind + "." + SchemeGroupVersion.String()
+	BundleGroupVersionKind = SchemeGroupVersion.WithKind(BundleKind)
+)
+
+// DeadLetterReport type metadata.
+var (
+	DeadLetterReportKind             = reflect.TypeOf(DeadLetterReport{}).Name()
+	DeadLetterReportGroupKind        = schema.GroupKind{Group: Group, Kind: DeadLetterReportKind}.String()
+	DeadLetterReportKindAPIVersion   = DeadLetterReportKind + "." + SchemeGroupVersion.String()
+	DeadLetterReportGroupVersionKind = SchemeGroupVersion.WithKind(DeadLetterReportKind)
+)
+
+// PreviewEnvironment type metadata.
+var (
+	PreviewEnvironmentKind             = reflect.TypeOf(PreviewEnvironment{}).Name()
+	PreviewEnvironmentGroupKind        = schema.GroupKind{Group: Group, Kind: PreviewEnvironmentKind}.String()
+	PreviewEnvironmentKindAPIVersion   = PreviewEnvironmentKind + "." + SchemeGroupVersion.String()
+	PreviewEnvironmentGroupVersionKind = SchemeGroupVersion.WithKind(PreviewEnvironmentKind)
+)
+
+// ApplicationHealth type metadata.
+var (
+	ApplicationHealthKind             = reflect.TypeOf(ApplicationHealth{}).Name()
+	ApplicationHealthGroupKind        = schema.GroupKind{Group: Group, Kind: ApplicationHealthKind}.String()
+	ApplicationHealthKindAPIVersion   = ApplicationHealthKind + "." + SchemeGroupVersion.String()
+	ApplicationHealthGroupVersionKind = SchemeGroupVersion.WithKind(ApplicationHealthKind)
+)
+
+// NetworkScope type metadata.
+var (
+	NetworkScopeKind             = reflect.TypeOf(NetworkScope{}).Name()
+	NetworkScopeGroupKind        = schema.GroupKind{Group: Group, Kind: NetworkScopeKind}.String()
+	NetworkScopeKindAPIVersion   = NetworkScopeKind + "." + SchemeGroupVersion.String()
+	NetworkScopeGroupVersionKind = SchemeGroupVersion.WithKind(NetworkScopeKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&VolumeMountTrait{}, &VolumeMountTraitList{})
+	SchemeBuilder.Register(&BundleTrait{}, &BundleTraitList{})
+	SchemeBuilder.Register(&PatchTrait{}, &PatchTraitList{})
+	SchemeBuilder.Register(&SidecarInjectionTrait{}, &SidecarInjectionTraitList{})
+	SchemeBuilder.Register(&TrafficSplitTrait{}, &TrafficSplitTraitList{})
+	SchemeBuilder.Register(&ResourceQuotaTrait{}, &ResourceQuotaTraitList{})
+	SchemeBuilder.Register(&SecurityContextTrait{}, &SecurityContextTraitList{})
+	SchemeBuilder.Register(&CronScalerTrait{}, &CronScalerTraitList{})
+	SchemeBuilder.Register(&PodDisruptionBudgetTrait{}, &PodDisruptionBudgetTraitList{})
+	SchemeBuilder.Register(&AnnotationsAndLabelsTrait{}, &AnnotationsAndLabelsTraitList{})
+	SchemeBuilder.Register(&Bundle{}, &BundleList{})
+	SchemeBuilder.Register(&DeadLetterReport{}, &DeadLetterReportList{})
+	SchemeBuilder.Register(&PreviewEnvironment{}, &PreviewEnvironmentList{})
+	SchemeBuilder.Register(&ApplicationHealth{}, &ApplicationHealthList{})
+	SchemeBuilder.Register(&NetworkScope{}, &NetworkScopeList{})
+}
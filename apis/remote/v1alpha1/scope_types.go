@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+// A NetworkScopeSpec defines the desired state of a NetworkScope.
+type NetworkScopeSpec struct {
+	// RemoteNamespace shared by the workloads in the scope. Defaults to the
+	// name of the NetworkScope.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	RemoteNamespace string `json:"remoteNamespace,omitempty"`
+}
+
+// A NetworkScopeStatus represents the observed state of a NetworkScope.
+type NetworkScopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Workloads in the scope.
+	// +optional
+	Workloads []oamv1alpha2.WorkloadReference `json:"workloads,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A NetworkScope groups the workloads of the components that reference it in
+// an ApplicationConfiguration into a shared remote namespace, in which they
+// accept traffic from each other but from nothing else.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.remoteNamespace"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+type NetworkScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkScopeSpec   `json:"spec,omitempty"`
+	Status NetworkScopeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkScopeList contains a list of NetworkScope.
+type NetworkScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkScope `json:"items"`
+}
+
+// GetCondition of this NetworkScope.
+func (s *NetworkScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return s.Status.GetCondition(ct)
+}
+
+// SetConditions of this NetworkScope.
+func (s *NetworkScope) SetConditions(c ...runtimev1alpha1.Condition) {
+	s.Status.SetConditions(c...)
+}
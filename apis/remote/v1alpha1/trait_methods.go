@@ -0,0 +1,381 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+// GetCondition of this VolumeMountTrait.
+func (tr *VolumeMountTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this VolumeMountTrait.
+func (tr *VolumeMountTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this VolumeMountTrait.
+func (tr *VolumeMountTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this VolumeMountTrait.
+func (tr *VolumeMountTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this VolumeMountTrait.
+func (tr *VolumeMountTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this VolumeMountTrait.
+func (tr *VolumeMountTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this VolumeMountTrait.
+func (tr *VolumeMountTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this BundleTrait.
+func (tr *BundleTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this BundleTrait.
+func (tr *BundleTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this BundleTrait.
+func (tr *BundleTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this BundleTrait.
+func (tr *BundleTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this BundleTrait.
+func (tr *BundleTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// GetWorkloadReferences of this BundleTrait.
+func (tr *BundleTrait) GetWorkloadReferences() []oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReferences
+}
+
+// SetTargetStatuses of this BundleTrait.
+func (tr *BundleTrait) SetTargetStatuses(s map[string]TargetStatus) {
+	tr.Status.Targets = s
+}
+
+// SetChangelog of this BundleTrait.
+func (tr *BundleTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this BundleTrait.
+func (tr *BundleTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this PatchTrait.
+func (tr *PatchTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this PatchTrait.
+func (tr *PatchTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this PatchTrait.
+func (tr *PatchTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this PatchTrait.
+func (tr *PatchTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this PatchTrait.
+func (tr *PatchTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this PatchTrait.
+func (tr *PatchTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this PatchTrait.
+func (tr *PatchTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this SidecarInjectionTrait.
+func (tr *SidecarInjectionTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this TrafficSplitTrait.
+func (tr *TrafficSplitTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this ResourceQuotaTrait.
+func (tr *ResourceQuotaTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this SecurityContextTrait.
+func (tr *SecurityContextTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this SecurityContextTrait.
+func (tr *SecurityContextTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this SecurityContextTrait.
+func (tr *SecurityContextTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this SecurityContextTrait.
+func (tr *SecurityContextTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this SecurityContextTrait.
+func (tr *SecurityContextTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this SecurityContextTrait.
+func (tr *SecurityContextTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this SecurityContextTrait.
+func (tr *SecurityContextTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this CronScalerTrait.
+func (tr *CronScalerTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this CronScalerTrait.
+func (tr *CronScalerTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this CronScalerTrait.
+func (tr *CronScalerTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this CronScalerTrait.
+func (tr *CronScalerTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this CronScalerTrait.
+func (tr *CronScalerTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this CronScalerTrait.
+func (tr *CronScalerTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this CronScalerTrait.
+func (tr *CronScalerTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this PodDisruptionBudgetTrait.
+func (tr *PodDisruptionBudgetTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
+
+// GetCondition of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) SetWorkloadReference(r oamv1alpha2.WorkloadReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// SetModifications of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) SetModifications(m []string) {
+	tr.Status.Modifications = m
+}
+
+// SetChangelog of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) SetChangelog(c []ChangelogEntry) {
+	tr.Status.Changelog = c
+}
+
+// SetObservations of this AnnotationsAndLabelsTrait.
+func (tr *AnnotationsAndLabelsTrait) SetObservations(o []RemoteObservation) {
+	tr.Status.Observed = o
+}
@@ -0,0 +1,804 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+// A Volume is a PersistentVolumeClaim that is mounted into the containers of a
+// workload.
+type Volume struct {
+	// Name of the volume. The PersistentVolumeClaim created for the volume is
+	// named after both the workload and the volume.
+	Name string `json:"name"`
+
+	// MountPath at which the volume should be mounted.
+	MountPath string `json:"mountPath"`
+
+	// Containers into which the volume should be mounted. The volume is
+	// mounted into all containers if none are specified.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// ReadOnly mounts the volume read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// StorageClassName of the PersistentVolumeClaim. The remote cluster's
+	// default storage class is used if omitted.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size of the PersistentVolumeClaim.
+	Size resource.Quantity `json:"size"`
+
+	// AccessMode of the PersistentVolumeClaim.
+	// +optional
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany
+	// +kubebuilder:default=ReadWriteOnce
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// A VolumeMountTraitSpec defines the desired state of a VolumeMountTrait.
+type VolumeMountTraitSpec struct {
+	// Volumes to create and mount.
+	Volumes []Volume `json:"volumes"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A VolumeMountTraitStatus represents the observed state of a
+// VolumeMountTrait.
+type VolumeMountTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A VolumeMountTrait creates PersistentVolumeClaims in the remote cluster and
+// mounts them into the containers of a workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type VolumeMountTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeMountTraitSpec   `json:"spec,omitempty"`
+	Status VolumeMountTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeMountTraitList contains a list of VolumeMountTrait.
+type VolumeMountTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VolumeMountTrait `json:"items"`
+}
+
+// A BundleTraitSpec defines the desired state of a BundleTrait.
+type BundleTraitSpec struct {
+	// BundleReference to the Bundle that should be applied to the workload.
+	// The Bundle must be in the same namespace as the trait.
+	BundleReference corev1.LocalObjectReference `json:"bundleRef"`
+
+	// Containers to which the Bundle's environment variables and volume
+	// mounts should be applied. The Bundle is applied to all containers if
+	// none are specified.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+
+	// WorkloadReferences to the workloads this trait applies to, for example
+	// the frontend and backend components of an application. The Bundle is
+	// applied to all or none of the referenced workloads. The
+	// WorkloadReference is ignored when WorkloadReferences are specified.
+	// +optional
+	WorkloadReferences []oamv1alpha2.WorkloadReference `json:"workloadRefs,omitempty"`
+}
+
+// A RemoteObservation is the observed state of a remote object that a trait
+// modifies, for example the replicas of a scaled Deployment.
+type RemoteObservation struct {
+	// APIVersion of the remote object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the remote object.
+	Kind string `json:"kind"`
+
+	// Name of the remote object.
+	Name string `json:"name"`
+
+	// Status of the remote object, as most recently observed.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Status runtime.RawExtension `json:"status,omitempty"`
+}
+
+// A TargetStatus represents the observed state of a trait's modification of
+// one of the workloads it applies to.
+type TargetStatus struct {
+	// Synced is true if the trait's modification was applied to the
+	// workload's translation.
+	Synced bool `json:"synced"`
+
+	// Message explaining why the modification was not applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+}
+
+// A ChangelogEntry records a change to the spec of an object.
+type ChangelogEntry struct {
+	// Generation of the object after the change.
+	Generation int64 `json:"generation"`
+
+	// Time at which the change was observed.
+	Time metav1.Time `json:"time"`
+
+	// Changes to the object's spec, formatted as "field: old -> new".
+	Changes []string `json:"changes"`
+}
+
+// A BundleTraitStatus represents the observed state of a BundleTrait.
+type BundleTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Targets are the observed states of the trait's modifications, keyed by
+	// workload name. Targets are only recorded when the trait references
+	// multiple workloads.
+	// +optional
+	Targets map[string]TargetStatus `json:"targets,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A BundleTrait applies the environment variables, volumes, and volume mounts
+// of a Bundle to the pods of a workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type BundleTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BundleTraitSpec   `json:"spec,omitempty"`
+	Status BundleTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BundleTraitList contains a list of BundleTrait.
+type BundleTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BundleTrait `json:"items"`
+}
+
+// A PatchType is a type of patch.
+type PatchType string
+
+// Types of patch.
+const (
+	// PatchTypeJSON patches are RFC 6902 JSON patches.
+	PatchTypeJSON PatchType = "JSONPatch"
+
+	// PatchTypeStrategicMerge patches are Kubernetes strategic merge
+	// patches. Templates of kinds that do not support strategic merge are
+	// patched using an RFC 7386 JSON merge patch.
+	PatchTypeStrategicMerge PatchType = "StrategicMergePatch"
+)
+
+// A PatchTarget selects the resource templates of a workload's translation to
+// which a patch applies.
+type PatchTarget struct {
+	// APIVersion of the templates to patch.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the templates to patch.
+	Kind string `json:"kind"`
+
+	// Name of the template to patch. All templates of the specified kind are
+	// patched if omitted.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// A Patch is applied to the resource templates of a workload's translation.
+type Patch struct {
+	// Type of the patch.
+	// +kubebuilder:validation:Enum=JSONPatch;StrategicMergePatch
+	Type PatchType `json:"type"`
+
+	// Target templates of the patch.
+	Target PatchTarget `json:"target"`
+
+	// Patch to apply. A JSONPatch is a list of operations, while a
+	// StrategicMergePatch is a partial object.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
+}
+
+// A PatchTraitSpec defines the desired state of a PatchTrait.
+type PatchTraitSpec struct {
+	// Patches to apply, in order.
+	Patches []Patch `json:"patches"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A PatchTraitStatus represents the observed state of a PatchTrait.
+type PatchTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PatchTrait applies JSON or strategic merge patches to the resource
+// templates of a workload, allowing simple traits to be defined declaratively.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type PatchTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PatchTraitSpec   `json:"spec,omitempty"`
+	Status PatchTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PatchTraitList contains a list of PatchTrait.
+type PatchTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PatchTrait `json:"items"`
+}
+
+// A SidecarInjectionTraitSpec defines the desired state of a
+// SidecarInjectionTrait.
+type SidecarInjectionTraitSpec struct {
+	// Sidecars to add to the pods of the workload, for example log shippers
+	// or proxies. A sidecar replaces any existing container of the same name.
+	Sidecars []corev1.Container `json:"sidecars"`
+
+	// Volumes to add to the pods of the workload, for example an emptyDir
+	// shared by the workload and a sidecar. Sidecars may also mount the
+	// workload's own volumes.
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A SidecarInjectionTraitStatus represents the observed state of a
+// SidecarInjectionTrait.
+type SidecarInjectionTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SidecarInjectionTrait adds sidecar containers to the packaged Deployment
+// of a workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type SidecarInjectionTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SidecarInjectionTraitSpec   `json:"spec,omitempty"`
+	Status SidecarInjectionTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SidecarInjectionTraitList contains a list of SidecarInjectionTrait.
+type SidecarInjectionTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SidecarInjectionTrait `json:"items"`
+}
+
+// A TrafficSplitMesh is a service mesh that implements a TrafficSplitTrait.
+type TrafficSplitMesh string
+
+// Supported service meshes.
+const (
+	// TrafficSplitMeshSMI splits traffic using a Service Mesh Interface
+	// TrafficSplit.
+	TrafficSplitMeshSMI TrafficSplitMesh = "SMI"
+
+	// TrafficSplitMeshIstio splits traffic using an Istio VirtualService.
+	TrafficSplitMeshIstio TrafficSplitMesh = "Istio"
+)
+
+// A TrafficSplitBackend is a Service that receives a share of the traffic
+// sent to the root Service of a TrafficSplitTrait.
+type TrafficSplitBackend struct {
+	// Service that receives traffic, for example the Service of a canary
+	// workload.
+	Service string `json:"service"`
+
+	// Weight of the traffic sent to this backend. The weights of Istio
+	// backends must sum to 100.
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight"`
+}
+
+// A TrafficSplitTraitSpec defines the desired state of a TrafficSplitTrait.
+type TrafficSplitTraitSpec struct {
+	// Mesh that splits traffic in the remote cluster.
+	// +kubebuilder:validation:Enum=SMI;Istio
+	Mesh TrafficSplitMesh `json:"mesh"`
+
+	// Service whose traffic is split. Defaults to the Service of the
+	// referenced workload, which is named after the workload.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// Backends between which traffic is split.
+	// +kubebuilder:validation:MinItems=1
+	Backends []TrafficSplitBackend `json:"backends"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A TrafficSplitTraitStatus represents the observed state of a
+// TrafficSplitTrait.
+type TrafficSplitTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TrafficSplitTrait splits the traffic sent to a workload's Service between
+// several backend Services, for example to shift weight to a canary, using a
+// service mesh in the remote cluster.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="MESH",type="string",JSONPath=".spec.mesh"
+type TrafficSplitTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrafficSplitTraitSpec   `json:"spec,omitempty"`
+	Status TrafficSplitTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrafficSplitTraitList contains a list of TrafficSplitTrait.
+type TrafficSplitTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrafficSplitTrait `json:"items"`
+}
+
+// A ResourceQuotaTraitSpec defines the desired state of a ResourceQuotaTrait.
+type ResourceQuotaTraitSpec struct {
+	// Quota enforced on the aggregate resource consumption of the remote
+	// namespace of the workload.
+	// +optional
+	Quota *corev1.ResourceQuotaSpec `json:"quota,omitempty"`
+
+	// Limits enforced on, and defaults applied to, each pod and container in
+	// the remote namespace of the workload.
+	// +optional
+	Limits []corev1.LimitRangeItem `json:"limits,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A ResourceQuotaTraitStatus represents the observed state of a
+// ResourceQuotaTrait.
+type ResourceQuotaTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ResourceQuotaTrait limits the resources that may be consumed in the remote
+// namespace of a workload, using a ResourceQuota and a LimitRange.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type ResourceQuotaTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourceQuotaTraitSpec   `json:"spec,omitempty"`
+	Status ResourceQuotaTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceQuotaTraitList contains a list of ResourceQuotaTrait.
+type ResourceQuotaTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceQuotaTrait `json:"items"`
+}
+
+// A SecurityContextTraitSpec defines the desired state of a
+// SecurityContextTrait.
+type SecurityContextTraitSpec struct {
+	// RunAsNonRoot requires the pods of the workload to run as a non-root
+	// user.
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of each container of
+	// the workload read-only.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// SeccompProfile of the pods of the workload, for example
+	// "runtime/default" or "localhost/<profile>".
+	// +optional
+	SeccompProfile *string `json:"seccompProfile,omitempty"`
+
+	// DropCapabilities of each container of the workload, for example "ALL".
+	// +optional
+	DropCapabilities []corev1.Capability `json:"dropCapabilities,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A SecurityContextTraitStatus represents the observed state of a
+// SecurityContextTrait.
+type SecurityContextTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SecurityContextTrait sets the pod and container security contexts of the
+// packaged Deployment of a workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type SecurityContextTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecurityContextTraitSpec   `json:"spec,omitempty"`
+	Status SecurityContextTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecurityContextTraitList contains a list of SecurityContextTrait.
+type SecurityContextTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityContextTrait `json:"items"`
+}
+
+// A ReplicaSchedule scales a workload to a number of replicas each time a cron
+// schedule fires.
+type ReplicaSchedule struct {
+	// Schedule in cron format, for example "0 8 * * 1-5" for 08:00 on
+	// weekdays. Schedules are evaluated in UTC.
+	Schedule string `json:"schedule"`
+
+	// Replicas to which the workload is scaled when the schedule fires.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+// A CronScalerTraitSpec defines the desired state of a CronScalerTrait.
+type CronScalerTraitSpec struct {
+	// Schedules of replicas. The workload is scaled to the replicas of the
+	// schedule that fired most recently. Its replicas are not changed until
+	// one of the schedules has fired.
+	Schedules []ReplicaSchedule `json:"schedules"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A CronScalerTraitStatus represents the observed state of a CronScalerTrait.
+type CronScalerTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Replicas to which the workload is currently scheduled to be scaled.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// NextScheduleTime is the time at which one of the schedules fires next.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A CronScalerTrait scales the packaged Deployment of a workload according to
+// a schedule, for example to follow predictable daily traffic.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="NEXT",type="date",JSONPath=".status.nextScheduleTime"
+type CronScalerTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronScalerTraitSpec   `json:"spec,omitempty"`
+	Status CronScalerTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CronScalerTraitList contains a list of CronScalerTrait.
+type CronScalerTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronScalerTrait `json:"items"`
+}
+
+// A PodDisruptionBudgetTraitSpec defines the desired state of a
+// PodDisruptionBudgetTrait. Exactly one of MinAvailable and MaxUnavailable
+// must be specified.
+type PodDisruptionBudgetTraitSpec struct {
+	// MinAvailable pods of the workload, as a number or a percentage, that
+	// must remain available during voluntary disruptions such as node drains.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable pods of the workload, as a number or a percentage, that
+	// may be unavailable during voluntary disruptions such as node drains.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// A PodDisruptionBudgetTraitStatus represents the observed state of a
+// PodDisruptionBudgetTrait.
+type PodDisruptionBudgetTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PodDisruptionBudgetTrait protects the pods of a workload's packaged
+// Deployment from voluntary disruptions, such as node drains, using a
+// PodDisruptionBudget.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type PodDisruptionBudgetTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodDisruptionBudgetTraitSpec   `json:"spec,omitempty"`
+	Status PodDisruptionBudgetTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PodDisruptionBudgetTraitList contains a list of PodDisruptionBudgetTrait.
+type PodDisruptionBudgetTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodDisruptionBudgetTrait `json:"items"`
+}
+
+// An AnnotationsAndLabelsTarget selects the resource templates of a workload's
+// translation to which labels and annotations are added.
+type AnnotationsAndLabelsTarget struct {
+	// APIVersion of the templates to select. Templates of any API version
+	// are selected if omitted.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the templates to select. Templates of any kind are selected if
+	// omitted.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the templates to select, as a glob pattern such as 'web-*'.
+	// Templates of any name are selected if omitted.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// An AnnotationsAndLabelsTraitSpec defines the desired state of an
+// AnnotationsAndLabelsTrait.
+type AnnotationsAndLabelsTraitSpec struct {
+	// Labels to add to the selected templates and their pod templates.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations to add to the selected templates and their pod templates.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Targets selects the templates to which labels and annotations are
+	// added. A template is selected if it matches any target. All templates
+	// are selected if omitted.
+	// +optional
+	Targets []AnnotationsAndLabelsTarget `json:"targets,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference oamv1alpha2.WorkloadReference `json:"workloadRef"`
+}
+
+// An AnnotationsAndLabelsTraitStatus represents the observed state of an
+// AnnotationsAndLabelsTrait.
+type AnnotationsAndLabelsTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Modifications made to the workload's translation by this trait.
+	// +optional
+	Modifications []string `json:"modifications,omitempty"`
+
+	// Changelog of the most recent changes to this trait's spec.
+	// +optional
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Observed states of the remote objects this trait modifies.
+	// +optional
+	Observed []RemoteObservation `json:"observed,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An AnnotationsAndLabelsTrait adds labels and annotations, such as cost
+// centers, owning teams, or scrape configurations, to selected resource
+// templates of a workload's translation and to their pod templates.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type AnnotationsAndLabelsTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AnnotationsAndLabelsTraitSpec   `json:"spec,omitempty"`
+	Status AnnotationsAndLabelsTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AnnotationsAndLabelsTraitList contains a list of AnnotationsAndLabelsTrait.
+type AnnotationsAndLabelsTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AnnotationsAndLabelsTrait `json:"items"`
+}
@@ -20,42 +20,237 @@ import (
 	"os"
 	"path/filepath"
 
+	uzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"gopkg.in/alecthomas/kingpin.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
-	crossplaneapis "github.com/crossplane/crossplane/apis"
 
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/config"
 	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/probe"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/drain"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trace"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 func main() {
 	var (
-		app        = kingpin.New(filepath.Base(os.Args[0]), "Run an OAM containerized workload on a remote Kubernetes cluster.").DefaultEnvars()
-		debug      = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		syncPeriod = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		app          = kingpin.New(filepath.Base(os.Args[0]), "Run an OAM containerized workload on a remote Kubernetes cluster.").DefaultEnvars()
+		debug        = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		verbosity    = app.Flag("verbosity", "Verbosity of logging. One or more enables debug logging.").Default("0").Int()
+		syncPeriod   = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		sourceNS     = app.Flag("source-remote-namespaces", "Create workloads that do not specify a remote namespace in the remote namespace named after their own.").Default("false").Bool()
+		janitor      = app.Flag("namespace-janitor", "Delete each remote namespace once the last workload in it is removed. Requires --source-remote-namespaces.").Default("false").Bool()
+		namespaces   = app.Flag("namespace", "Only watch and reconcile objects in this namespace. May be repeated. Objects in all namespaces are reconciled if omitted.").Strings()
+		selector     = app.Flag("selector", "Only reconcile workloads and traits whose labels match this label selector, for example tenant=a.").String()
+		kubeConfig   = app.Flag("provider-kubernetes-config", "Package workloads as provider-kubernetes Objects that use this ProviderConfig instead of as KubernetesApplications.").String()
+		remoteKube   = app.Flag("remote-kubeconfig-secret", "Apply workloads directly to the remote cluster whose kubeconfig is stored in this namespace/name Secret instead of packaging them.").String()
+		pkgFormat    = app.Flag("package-format", "Format in which workloads are packaged. Ignored if a provider-kubernetes config or remote kubeconfig Secret is specified.").Default(string(options.PackageFormatKubernetesApplication)).Enum(string(options.PackageFormatKubernetesApplication), string(options.PackageFormatManifestWork), string(options.PackageFormatSecret))
+		pkgSink      = app.Flag("package-sink", "Store the package of each workload in this sink before it is applied, for example so that it may be reviewed.").Enum(string(options.PackageSinkConfigMap))
+		maxPkgBytes  = app.Flag("max-package-bytes", "Maximum size of each KubernetesApplication a workload is packaged as. Larger packages are split across several KubernetesApplications. Packages are never split if zero.").Default("1048576").Int()
+		adoption     = app.Flag("adoption-policy", "How to handle packages that already exist but have no controller, for example because they were created by a previous version of the addon.").Default(string(workload.AdoptionPolicyFail)).Enum(string(workload.AdoptionPolicyFail), string(workload.AdoptionPolicyAdopt), string(workload.AdoptionPolicyOrphan))
+		skipApply    = app.Flag("skip-apply", "Store packages in the package sink instead of applying them.").Default("false").Bool()
+		otlpEndpoint = app.Flag("otlp-endpoint", "OTLP/HTTP endpoint of an OpenTelemetry collector to which spans of each reconcile are exported.").String()
+		remoteSchema = app.Flag("remote-schema", "Path to the OpenAPI v2 document of the remote cluster, against which workload translations are validated.").String()
+		oamRuntime   = app.Flag("oam-runtime-interop", "Propagate the labels of workloads rendered by the OAM Kubernetes runtime to their packages.").Default("false").Bool()
+		maxReconcile = app.Flag("max-concurrent-reconciles", "Maximum number of reconciles each controller may run concurrently.").Default("1").Int()
+		maxApply     = app.Flag("max-concurrent-applies", "Maximum number of objects of a workload's translation that may be applied concurrently.").Default("1").Int()
+		deadLetter   = app.Flag("dead-letter-after", "Stop reconciling objects after this many consecutive failed reconciles. Objects are always reconciled again if zero.").Default("0").Int()
+		baseDelay    = app.Flag("rate-limit-base-delay", "Delay before an object whose reconcile failed is reconciled again, doubling with each consecutive failure. Failed reconciles are not delayed if zero.").Default("0s").Duration()
+		maxDelay     = app.Flag("rate-limit-max-delay", "Maximum delay before an object whose reconcile failed is reconciled again.").Default("1000s").Duration()
+		qps          = app.Flag("rate-limit-qps", "Reconciles per second each controller may start once its bucket is empty. Reconciles are not limited if zero.").Default("0").Float64()
+		bucket       = app.Flag("rate-limit-bucket", "Number of reconciles each controller may start in a burst.").Default("100").Int()
+		liveReads    = app.Flag("live-finalizer-reads", "Read packages from the API server rather than the cache before deleting remote namespaces.").Default("false").Bool()
+		pinDigests   = app.Flag("pin-image-digests", "Pin the images of ContainerizedWorkloads to the digests their tags resolve to before packaging them.").Default("false").Bool()
+		cacheTrans   = app.Flag("cache-translations", "Reuse the translation of each ContainerizedWorkload until it changes. Changes to the Secrets and targets a workload references are not seen until its reconcile is requested.").Default("false").Bool()
+		verifyKey    = app.Flag("image-verification-key", "Path to a PEM encoded cosign public key. Images are only pinned to digests signed by this key.").String()
+		mirrorRefs   = app.Flag("mirror-references", "Mirror the Secrets and ConfigMaps referenced by annotated ContainerizedWorkloads into their packages.").Default("false").Bool()
+		defaulter    = app.Flag("containerized-workload-defaulter", "Serve a mutating webhook that defaults the ports and resource requests of ContainerizedWorkloads.").Default("false").Bool()
+		validator    = app.Flag("containerized-workload-validator", "Serve a validating webhook that rejects ContainerizedWorkloads with invalid names, images, ports, or extended resources.").Default("false").Bool()
+		conflicts    = app.Flag("trait-conflict-webhook", "Serve a validating webhook that rejects traits that modify the same fields of a workload.").Default("false").Bool()
+		previews     = app.Flag("preview-environments", "Stamp copies of template workloads into PreviewEnvironments.").Default("false").Bool()
+		appHealth    = app.Flag("application-health", "Roll the status of the workloads and traits of each ApplicationConfiguration up into an ApplicationHealth.").Default("false").Bool()
+		discovery    = app.Flag("definition-discovery", "Start generic controllers for the kinds referenced by WorkloadDefinitions and TraitDefinitions.").Default("false").Bool()
+		netScopes    = app.Flag("network-scopes", "Move the workloads of components that reference a NetworkScope into its shared, isolated remote namespace.").Default("false").Bool()
+		webhookPort  = app.Flag("webhook-port", "Port at which admission webhooks are served.").Default("9443").Int()
+		certDir      = app.Flag("webhook-cert-dir", "Directory containing the tls.crt and tls.key used to serve admission webhooks.").String()
+		metricsAddr  = app.Flag("metrics-bind-address", "Address at which metrics are served, or 0 to disable them.").Default(":8080").String()
+		healthAddr   = app.Flag("health-probe-bind-address", "Address at which the /healthz and /readyz probes are served, or 0 to disable them.").Default(":8081").String()
+		gracePeriod  = app.Flag("shutdown-grace-period", "Duration to wait for reconciles in progress to finish before exiting.").Default("30s").Duration()
+		configFile   = app.Flag("config", "Configuration file. Fields specified in the file take precedence over flags.").ExistingFile()
+
+		leaderElection          = app.Flag("leader-election", "Use leader election so that only one replica reconciles at a time.").Short('l').Default("false").Envar("LEADER_ELECTION").Bool()
+		leaderElectionNamespace = app.Flag("leader-election-namespace", "Namespace in which to hold the leader election lock. Defaults to the namespace the addon runs in.").Envar("POD_NAMESPACE").String()
+		leaderElectionID        = app.Flag("leader-election-id", "Name of the leader election lock.").Default("addon-oam-kubernetes-remote-leader-election").String()
+		leaseDuration           = app.Flag("leader-election-lease-duration", "Duration non-leader replicas wait before acquiring an unrenewed lock.").Default("15s").Duration()
+		renewDeadline           = app.Flag("leader-election-renew-deadline", "Duration the leader retries renewing its lock before giving it up.").Default("10s").Duration()
+		retryPeriod             = app.Flag("leader-election-retry-period", "Duration replicas wait between attempts to acquire or renew the lock.").Default("2s").Duration()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	zl := zap.New(zap.UseDevMode(*debug))
+	controllers := append([]string{}, config.DefaultControllers...)
+	if *janitor {
+		controllers = append(controllers, config.ControllerNamespaceJanitor)
+	}
+	if *conflicts {
+		controllers = append(controllers, config.ControllerTraitConflictWebhook)
+	}
+	if *defaulter {
+		controllers = append(controllers, config.ControllerContainerizedWorkloadDefaulter)
+	}
+	if *validator {
+		controllers = append(controllers, config.ControllerContainerizedWorkloadValidator)
+	}
+	if *previews {
+		controllers = append(controllers, config.ControllerPreviewEnvironment)
+	}
+	if *appHealth {
+		controllers = append(controllers, config.ControllerApplicationHealth)
+	}
+	if *discovery {
+		controllers = append(controllers, config.ControllerDefinitionDiscovery)
+	}
+	if *netScopes {
+		controllers = append(controllers, config.ControllerNetworkScope)
+	}
+	gates := []string{}
+	if *oamRuntime {
+		gates = append(gates, config.FeatureOAMRuntimeInterop)
+	}
+	if *liveReads {
+		gates = append(gates, config.FeatureLiveFinalizerReads)
+	}
+	if *mirrorRefs {
+		gates = append(gates, config.FeatureReferenceMirroring)
+	}
+	if *pinDigests {
+		gates = append(gates, config.FeatureImageDigestPinning)
+	}
+	if *cacheTrans {
+		gates = append(gates, config.FeatureTranslationCache)
+	}
+
+	rateLimit := config.ControllerRateLimit{
+		BaseDelay: metav1.Duration{Duration: *baseDelay},
+		MaxDelay:  metav1.Duration{Duration: *maxDelay},
+		QPS:       *qps,
+		Bucket:    *bucket,
+	}
+
+	// Command line flags are the defaults of fields that are omitted from the
+	// configuration file, if any.
+	base := &config.Config{
+		Debug:                    *debug,
+		Verbosity:                *verbosity,
+		SyncPeriod:               metav1.Duration{Duration: *syncPeriod},
+		FeatureGates:             gates,
+		Controllers:              controllers,
+		Namespaces:               *namespaces,
+		Selector:                 *selector,
+		Concurrency:              config.Concurrency{Reconciles: *maxReconcile, Applies: *maxApply},
+		RateLimit:                config.RateLimit{ControllerRateLimit: rateLimit},
+		DeadLetterAfter:          *deadLetter,
+		PackageFormat:            options.PackageFormat(*pkgFormat),
+		PackageSink:              options.PackageSink(*pkgSink),
+		MaxPackageBytes:          *maxPkgBytes,
+		AdoptionPolicy:           workload.AdoptionPolicy(*adoption),
+		SkipApply:                *skipApply,
+		RemoteSchema:             *remoteSchema,
+		SourceRemoteNamespaces:   *sourceNS,
+		ImageVerificationKey:     *verifyKey,
+		ProviderKubernetesConfig: *kubeConfig,
+		RemoteKubeconfig:         *remoteKube,
+		Metrics:                  config.Metrics{BindAddress: *metricsAddr},
+		Health:                   config.Health{BindAddress: *healthAddr},
+		Tracing:                  config.Tracing{OTLPEndpoint: *otlpEndpoint},
+		Webhook:                  config.Webhook{Port: *webhookPort, CertDir: *certDir},
+		LeaderElection: config.LeaderElection{
+			Enabled:       *leaderElection,
+			Namespace:     *leaderElectionNamespace,
+			ID:            *leaderElectionID,
+			LeaseDuration: metav1.Duration{Duration: *leaseDuration},
+			RenewDeadline: metav1.Duration{Duration: *renewDeadline},
+			RetryPeriod:   metav1.Duration{Duration: *retryPeriod},
+		},
+		ShutdownGracePeriod: metav1.Duration{Duration: *gracePeriod},
+	}
+	kingpin.FatalIfError(base.Validate(), "Invalid configuration flags")
+	c := base
+	if *configFile != "" {
+		var err error
+		c, err = config.Load(*configFile, base)
+		kingpin.FatalIfError(err, "Cannot load configuration file")
+	}
+
+	lvl := uzap.NewAtomicLevelAt(level(c.LogVerbosity()))
+	zl := zap.New(zap.UseDevMode(c.LogVerbosity() > 0), zap.Level(&lvl))
 	log := logging.NewLogrLogger(zl.WithName("addon-oam-kubernetes-remote"))
-	if *debug {
+	if c.LogVerbosity() > 0 {
 		// The controller-runtime runs with a no-op logger by default. It is
 		// *very* verbose even at info level, so we only provide it a real
 		// logger when we're running in debug mode.
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting", "sync-period", c.SyncPeriod.Duration.String(), "leader-election", c.LeaderElection.Enabled)
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{SyncPeriod: syncPeriod})
+	// Each replica identifies itself by its hostname (i.e. its pod name) when
+	// leader election is enabled. Controllers are only started
+	// once a replica becomes leader, so packages are never reconciled by more
+	// than one replica. A replica that is shut down stops renewing its lock,
+	// and another replica takes over once the lease duration elapses.
+	mgr, err := ctrl.NewManager(cfg, c.ManagerOptions())
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
-	kingpin.FatalIfError(crossplaneapis.AddToScheme(mgr.GetScheme()), "Cannot add core Crossplane APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup OAM Kubernetes Remote controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	kingpin.FatalIfError(controller.AddToScheme(mgr.GetScheme()), "Cannot add OAM Kubernetes Remote APIs to scheme")
+
+	// The addon is live as long as it serves probes. It is ready once its
+	// cache has synced and, if it serves admission webhooks, once their
+	// serving certificate has been mounted.
+	synced := probe.NewCacheSync(mgr.GetCache())
+	kingpin.FatalIfError(mgr.Add(synced), "Cannot add cache sync probe to controller manager")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("ping", healthz.Ping), "Cannot add liveness probe")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("cache-sync", synced.Check), "Cannot add cache sync readiness probe")
+	if c.ServesWebhooks() {
+		kingpin.FatalIfError(mgr.AddReadyzCheck("webhook-certs", probe.Certificates(c.Webhook.CertDir)), "Cannot add webhook certificate readiness probe")
+	}
+
+	o, err := c.Options(log)
+	kingpin.FatalIfError(err, "Cannot build controller options")
+	inFlight := drain.NewTracker()
+	o.InFlight = inFlight
+	if c.Tracing.OTLPEndpoint != "" {
+		t := trace.NewOTLPTracer(c.Tracing.OTLPEndpoint, trace.WithLogger(log))
+		kingpin.FatalIfError(mgr.Add(t), "Cannot add span exporter to controller manager")
+		o.Tracer = t
+	}
+	kingpin.FatalIfError(controller.Setup(mgr, o, c.Setups()...), "Cannot setup OAM Kubernetes Remote controllers")
+
+	stop := ctrl.SetupSignalHandler()
+	if *configFile != "" {
+		w := config.NewWatcher(*configFile, base, c, config.WithLogger(log))
+		go w.Watch(stop, func(c *config.Config) { lvl.SetLevel(level(c.LogVerbosity())) })
+	}
+	kingpin.FatalIfError(mgr.Start(stop), "Cannot start controller manager")
+
+	// The controller manager returns as soon as it is stopped, without
+	// waiting for reconciles in progress to finish.
+	if !inFlight.Drain(c.ShutdownGracePeriod.Duration) {
+		log.Info("Reconciles did not finish within the shutdown grace period", "shutdown-grace-period", c.ShutdownGracePeriod.Duration.String())
+	}
+}
+
+// level returns the zap level of the supplied verbosity. Debug logs are
+// emitted at verbosity one, which zap considers level -1.
+func level(verbosity int) zapcore.Level {
+	return zapcore.Level(-verbosity)
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/containerizedworkload"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+)
+
+func main() {
+	var (
+		app = kingpin.New(filepath.Base(os.Args[0]), "Run the OAM Kubernetes Remote addon's translations offline.").DefaultEnvars()
+
+		render          = app.Command("render", "Render the KubernetesApplication a ContainerizedWorkload and its traits would be packaged as.")
+		renderNamespace = render.Flag("namespace", "Namespace of objects that do not specify one.").Default("default").String()
+		renderTraits    = render.Flag("trait", "File containing traits to apply to the workload. May be specified multiple times.").ExistingFiles()
+		renderFile      = render.Arg("file", "File containing a ContainerizedWorkload, and any traits or objects it references. Defaults to stdin.").File()
+	)
+
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case render.FullCommand():
+		s := runtime.NewScheme()
+		kingpin.FatalIfError(clientgoscheme.AddToScheme(s), "Cannot add Kubernetes APIs to scheme")
+		kingpin.FatalIfError(controller.AddToScheme(s), "Cannot add OAM Kubernetes Remote APIs to scheme")
+
+		objs, err := read(input(*renderFile))
+		kingpin.FatalIfError(err, "Cannot read workload")
+		for _, f := range *renderTraits {
+			r, err := os.Open(filepath.Clean(f))
+			kingpin.FatalIfError(err, "Cannot open traits")
+			t, err := read(r)
+			kingpin.FatalIfError(err, "Cannot read traits")
+			objs = append(objs, t...)
+		}
+
+		cw, traits, others, err := classify(s, *renderNamespace, objs)
+		kingpin.FatalIfError(err, "Cannot render workload")
+
+		a, err := containerizedworkload.Render(context.Background(), fake.NewFakeClientWithScheme(s, others...), s, cw, traits...)
+		kingpin.FatalIfError(err, "Cannot render workload")
+		kingpin.FatalIfError(write(os.Stdout, a), "Cannot write KubernetesApplication")
+	}
+}
+
+const (
+	errConvert    = "cannot convert object"
+	errNoWorkload = "no ContainerizedWorkload found"
+	errWorkloads  = "only one ContainerizedWorkload may be rendered at a time"
+)
+
+// classify the supplied objects as the ContainerizedWorkload to render, the
+// traits to apply to it, and any other objects they may reference.
+func classify(s *runtime.Scheme, namespace string, objs []*unstructured.Unstructured) (*oamv1alpha2.ContainerizedWorkload, []trait.Trait, []runtime.Object, error) {
+	modifiers := containerizedworkload.Modifiers(nil, s)
+
+	var cw *oamv1alpha2.ContainerizedWorkload
+	traits := []trait.Trait{}
+	others := []runtime.Object{}
+	for _, u := range objs {
+		if u.GetNamespace() == "" {
+			u.SetNamespace(namespace)
+		}
+
+		gvk := u.GroupVersionKind()
+		o, err := s.New(gvk)
+		if err != nil {
+			// Objects of kinds the scheme does not know can only be
+			// referenced as unstructured objects.
+			others = append(others, u)
+			continue
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), o); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "%s %s", errConvert, u.GetName())
+		}
+
+		if w, ok := o.(*oamv1alpha2.ContainerizedWorkload); ok {
+			if cw != nil {
+				return nil, nil, nil, errors.New(errWorkloads)
+			}
+			cw = w
+			continue
+		}
+		if t, ok := o.(trait.Trait); ok {
+			if _, ok := modifiers[gvk]; ok {
+				traits = append(traits, t)
+				continue
+			}
+		}
+		others = append(others, o)
+	}
+	if cw == nil {
+		return nil, nil, nil, errors.New(errNoWorkload)
+	}
+	return cw, traits, others, nil
+}
+
+func input(f *os.File) io.Reader {
+	if f == nil {
+		return os.Stdin
+	}
+	return f
+}
+
+// read each object of a multi document YAML stream.
+func read(r io.Reader) ([]*unstructured.Unstructured, error) {
+	d := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	objs := []*unstructured.Unstructured{}
+	for {
+		u := &unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
+
+// write the supplied object as a YAML document.
+func write(w io.Writer, o interface{}) error {
+	b, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, bytes.NewReader(append([]byte("---\n"), b...)))
+	return err
+}
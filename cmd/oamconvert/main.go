@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/convert"
+)
+
+func main() {
+	var (
+		app = kingpin.New(filepath.Base(os.Args[0]), "Convert between OAM workloads and traits and KubeVela Applications.").DefaultEnvars()
+
+		toApp     = app.Command("to-application", "Convert workloads and traits to an Application.")
+		toAppName = toApp.Flag("name", "Name of the Application.").Required().String()
+		toAppNS   = toApp.Flag("namespace", "Namespace of the Application.").Default("default").String()
+		toAppFile = toApp.Arg("file", "File containing workloads and traits. Defaults to stdin.").File()
+
+		fromApp     = app.Command("from-application", "Convert an Application to workloads and traits.")
+		fromAppFile = fromApp.Arg("file", "File containing an Application. Defaults to stdin.").File()
+	)
+
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case toApp.FullCommand():
+		objs, err := read(input(*toAppFile))
+		kingpin.FatalIfError(err, "Cannot read workloads and traits")
+		a, err := convert.ToApplication(*toAppName, *toAppNS, objs, convert.DefaultTypes)
+		kingpin.FatalIfError(err, "Cannot convert workloads and traits")
+		kingpin.FatalIfError(write(os.Stdout, a), "Cannot write Application")
+
+	case fromApp.FullCommand():
+		b, err := ioutil.ReadAll(input(*fromAppFile))
+		kingpin.FatalIfError(err, "Cannot read Application")
+		a := &convert.Application{}
+		kingpin.FatalIfError(yaml.Unmarshal(b, a), "Cannot parse Application")
+		objs, err := convert.FromApplication(a, convert.DefaultTypes)
+		kingpin.FatalIfError(err, "Cannot convert Application")
+		for _, o := range objs {
+			kingpin.FatalIfError(write(os.Stdout, o.Object), "Cannot write workloads and traits")
+		}
+	}
+}
+
+func input(f *os.File) io.Reader {
+	if f == nil {
+		return os.Stdin
+	}
+	return f
+}
+
+// read each object of a multi document YAML stream.
+func read(r io.Reader) ([]*unstructured.Unstructured, error) {
+	d := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	objs := []*unstructured.Unstructured{}
+	for {
+		u := &unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
+
+// write the supplied object as a YAML document.
+func write(w io.Writer, o interface{}) error {
+	b, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, bytes.NewReader(append([]byte("---\n"), b...)))
+	return err
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e is an end-to-end suite that exercises the OAM Kubernetes Remote
+// controllers against real clusters. It stands up a host and a remote kind
+// cluster, runs the controllers against the host cluster, applies the sample
+// workloads and traits in the manifests directory, and asserts on the objects
+// that are produced in the remote cluster.
+//
+// The suite doubles as documentation of how the addon is used, and as the
+// acceptance test run before each release. It requires docker, kind, kubectl,
+// and helm, and only builds with the e2e build tag:
+//
+//	go test -tags e2e -timeout 30m ./examples/e2e/...
+//
+// Set E2E_KEEP_CLUSTERS to keep the kind clusters once the suite finishes, and
+// E2E_REUSE_CLUSTERS to run the suite against clusters that were kept.
+package e2e
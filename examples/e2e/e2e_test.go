@@ -0,0 +1,174 @@
+// +build e2e
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/internal/kind"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+)
+
+const (
+	hostName   = "oam-remote-e2e-host"
+	remoteName = "oam-remote-e2e-remote"
+
+	crossplaneRepo    = "https://charts.crossplane.io/alpha"
+	crossplaneChart   = "crossplane-alpha/crossplane"
+	crossplaneVersion = "0.8.0"
+
+	// The secret that contains the kubeconfig of the remote cluster. It is
+	// referenced by manifests/target.yaml.
+	remoteSecret = "e2e-remote"
+
+	timeout  = 5 * time.Minute
+	interval = 2 * time.Second
+)
+
+var suite = &kind.Suite{
+	HostName:   hostName,
+	RemoteName: remoteName,
+	Setup:      setup,
+	Controllers: func(mgr ctrl.Manager, _ *kind.Suite) error {
+		return controller.SetupAll(mgr, controller.Options{Logger: logging.NewNopLogger()})
+	},
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(suite.Run(m, 15*time.Minute))
+}
+
+// setup installs Crossplane in the host cluster and registers the remote
+// cluster as a KubernetesTarget.
+func setup(ctx context.Context, s *kind.Suite) error {
+	if _, err := s.Host.Helm(ctx, "repo", "add", "crossplane-alpha", crossplaneRepo); err != nil {
+		return err
+	}
+	if _, err := s.Host.Helm(ctx, "upgrade", "--install", "crossplane", crossplaneChart,
+		"--namespace", "crossplane-system", "--create-namespace",
+		"--version", crossplaneVersion, "--wait"); err != nil {
+		return err
+	}
+
+	kc, err := s.Remote.InternalKubeconfig(ctx)
+	if err != nil {
+		return err
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: remoteSecret},
+		Data:       map[string][]byte{"kubeconfig": kc},
+	}
+	if err := s.HostClient.Create(ctx, sec); err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "cannot create remote cluster connection secret")
+	}
+
+	return s.Host.Apply(ctx, "manifests/target.yaml")
+}
+
+// eventually calls the supplied function until it returns nil, failing the
+// test if it does not do so before the suite's timeout.
+func eventually(t *testing.T, reason string, fn func() error) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		err := fn()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: %v", reason, err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func TestContainerizedWorkload(t *testing.T) {
+	ctx := context.Background()
+	nn := types.NamespacedName{Namespace: "default", Name: "e2e-nginx"}
+
+	if err := suite.Host.Apply(ctx, "manifests/workload.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "The workload should be reconciled successfully.", func() error {
+		w := &oamv1alpha2.ContainerizedWorkload{}
+		if err := suite.HostClient.Get(ctx, nn, w); err != nil {
+			return err
+		}
+		if c := w.GetCondition(v1alpha1.TypeSynced); c.Reason != v1alpha1.ReasonReconcileSuccess {
+			return errors.Errorf("workload is not synced: %s", c.Message)
+		}
+		return nil
+	})
+
+	eventually(t, "The workload should be packaged as a KubernetesApplication.", func() error {
+		return suite.HostClient.Get(ctx, nn, &workloadv1alpha1.KubernetesApplication{})
+	})
+
+	eventually(t, "The workload's Deployment should exist in the remote cluster.", func() error {
+		return suite.RemoteClient.Get(ctx, nn, &appsv1.Deployment{})
+	})
+}
+
+func TestManualScalerTrait(t *testing.T) {
+	ctx := context.Background()
+	nn := types.NamespacedName{Namespace: "default", Name: "e2e-nginx"}
+
+	if err := suite.Host.Apply(ctx, "manifests/workload.yaml", "manifests/manualscaler.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "The trait should be reconciled successfully.", func() error {
+		tr := &oamv1alpha2.ManualScalerTrait{}
+		if err := suite.HostClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "e2e-nginx-scaler"}, tr); err != nil {
+			return err
+		}
+		if c := tr.GetCondition(v1alpha1.TypeSynced); c.Reason != v1alpha1.ReasonReconcileSuccess {
+			return errors.Errorf("trait is not synced: %s", c.Message)
+		}
+		return nil
+	})
+
+	eventually(t, "The workload's remote Deployment should be scaled by the trait.", func() error {
+		d := &appsv1.Deployment{}
+		if err := suite.RemoteClient.Get(ctx, nn, d); err != nil {
+			return err
+		}
+		if d.Spec.Replicas == nil || *d.Spec.Replicas != 3 {
+			return errors.Errorf("remote deployment has %v replicas, want 3", d.Spec.Replicas)
+		}
+		return nil
+	})
+}
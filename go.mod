@@ -8,8 +8,11 @@ require (
 	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/google/go-cmp v0.3.1
 	github.com/pkg/errors v0.8.1
+	github.com/prometheus/client_golang v1.1.0
+	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 	k8s.io/api v0.17.3
 	k8s.io/apimachinery v0.17.3
 	sigs.k8s.io/controller-runtime v0.4.0
+	sigs.k8s.io/yaml v1.1.0
 )
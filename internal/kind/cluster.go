@@ -0,0 +1,129 @@
+// +build e2e
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kind
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A Cluster is a kind cluster.
+type Cluster struct {
+	// Name of the cluster.
+	Name string
+
+	// Kubeconfig is the path to a kubeconfig file that may be used to
+	// connect to the cluster from the machine running the suite.
+	Kubeconfig string
+}
+
+// CreateCluster creates a kind cluster with the supplied name, writing its
+// kubeconfig to the supplied directory. An existing cluster is reused if reuse
+// is true.
+func CreateCluster(ctx context.Context, name, dir string, reuse bool) (*Cluster, error) {
+	c := &Cluster{Name: name, Kubeconfig: filepath.Join(dir, name+".kubeconfig")}
+
+	if reuse {
+		out, err := run(ctx, "kind", "get", "clusters")
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range strings.Fields(out) {
+			if n == name {
+				_, err := run(ctx, "kind", "export", "kubeconfig", "--name", name, "--kubeconfig", c.Kubeconfig)
+				return c, err
+			}
+		}
+	}
+
+	_, err := run(ctx, "kind", "create", "cluster", "--name", name, "--kubeconfig", c.Kubeconfig, "--wait", "5m")
+	return c, err
+}
+
+// Delete the cluster.
+func (c *Cluster) Delete(ctx context.Context) error {
+	_, err := run(ctx, "kind", "delete", "cluster", "--name", c.Name)
+	return err
+}
+
+// InternalKubeconfig returns a kubeconfig that may be used to connect to the
+// cluster from another kind cluster.
+func (c *Cluster) InternalKubeconfig(ctx context.Context) ([]byte, error) {
+	out, err := run(ctx, "kind", "get", "kubeconfig", "--name", c.Name, "--internal")
+	return []byte(out), err
+}
+
+// Kubectl runs kubectl against the cluster with the supplied arguments.
+func (c *Cluster) Kubectl(ctx context.Context, args ...string) (string, error) {
+	return run(ctx, "kubectl", append([]string{"--kubeconfig", c.Kubeconfig}, args...)...)
+}
+
+// Helm runs helm against the cluster with the supplied arguments.
+func (c *Cluster) Helm(ctx context.Context, args ...string) (string, error) {
+	return run(ctx, "helm", append([]string{"--kubeconfig", c.Kubeconfig}, args...)...)
+}
+
+// Apply the supplied manifests to the cluster.
+func (c *Cluster) Apply(ctx context.Context, manifests ...string) error {
+	for _, m := range manifests {
+		if _, err := c.Kubectl(ctx, "apply", "-f", m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Client returns a client for the cluster that uses the supplied scheme.
+func (c *Cluster) Client(s *runtime.Scheme) (client.Client, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", c.Kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load kubeconfig of cluster %s", c.Name)
+	}
+	return client.New(cfg, client.Options{Scheme: s})
+}
+
+// TempDir returns a directory in which the suite may write files, and a
+// function that removes it.
+func TempDir() (string, func(), error) {
+	dir, err := ioutil.TempDir("", "oam-remote-e2e")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cannot create temporary directory")
+	}
+	return dir, func() { _ = os.RemoveAll(dir) }, nil
+}
+
+func run(ctx context.Context, name string, args ...string) (string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "%s %s: %s", name, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
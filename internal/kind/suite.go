@@ -0,0 +1,153 @@
+// +build e2e
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kind
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+)
+
+// A Suite of end-to-end tests that run against a host and a remote kind
+// cluster.
+type Suite struct {
+	// HostName is the name of the host cluster, against which the
+	// controllers run.
+	HostName string
+
+	// RemoteName is the name of the remote cluster, to which workloads are
+	// applied.
+	RemoteName string
+
+	// Setup is called once both clusters exist, before the controllers are
+	// started, for example to install CRDs in the host cluster.
+	Setup func(ctx context.Context, s *Suite) error
+
+	// Controllers adds the controllers that should run against the host
+	// cluster to the supplied manager.
+	Controllers func(mgr ctrl.Manager, s *Suite) error
+
+	Host         *Cluster
+	Remote       *Cluster
+	HostClient   client.Client
+	RemoteClient client.Client
+}
+
+// Run stands up the suite's clusters, calls its Setup, starts its controllers,
+// and runs the tests. Clusters must be stood up and the controllers started
+// within the supplied timeout. It returns the suite's exit code.
+//
+// Set E2E_KEEP_CLUSTERS to keep the kind clusters once the suite finishes, and
+// E2E_REUSE_CLUSTERS to run the suite against clusters that were kept.
+func (s *Suite) Run(m *testing.M, timeout time.Duration) int {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dir, cleanup, err := TempDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer cleanup()
+
+	_, reuse := os.LookupEnv("E2E_REUSE_CLUSTERS")
+	_, keep := os.LookupEnv("E2E_KEEP_CLUSTERS")
+
+	if s.Host, err = CreateCluster(ctx, s.HostName, dir, reuse); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !keep {
+		defer func() { _ = s.Host.Delete(context.Background()) }()
+	}
+
+	if s.Remote, err = CreateCluster(ctx, s.RemoteName, dir, reuse); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !keep {
+		defer func() { _ = s.Remote.Delete(context.Background()) }()
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if err := s.start(ctx, stop); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// start creates clients for the suite's clusters, calls its Setup, and runs
+// its controllers against the host cluster until the supplied channel is
+// closed.
+func (s *Suite) start(ctx context.Context, stop <-chan struct{}) error {
+	sch := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(sch); err != nil {
+		return errors.Wrap(err, "cannot add Kubernetes types to scheme")
+	}
+	if err := controller.AddToScheme(sch); err != nil {
+		return errors.Wrap(err, "cannot add addon types to scheme")
+	}
+
+	var err error
+	if s.HostClient, err = s.Host.Client(sch); err != nil {
+		return err
+	}
+	if s.RemoteClient, err = s.Remote.Client(sch); err != nil {
+		return err
+	}
+
+	if s.Setup != nil {
+		if err := s.Setup(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", s.Host.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "cannot load host cluster kubeconfig")
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: sch, MetricsBindAddress: "0"})
+	if err != nil {
+		return errors.Wrap(err, "cannot create controller manager")
+	}
+	if err := s.Controllers(mgr, s); err != nil {
+		return errors.Wrap(err, "cannot setup controllers")
+	}
+
+	go func() {
+		if err := mgr.Start(stop); err != nil {
+			fmt.Fprintln(os.Stderr, errors.Wrap(err, "cannot start controller manager"))
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,626 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the configuration of the OAM Kubernetes Remote addon
+// from a declarative configuration file.
+package config
+
+import (
+	"io/ioutil"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/message"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errReadConfig         = "cannot read configuration file"
+	errParseConfig        = "cannot parse configuration file"
+	errUnknownController  = "unknown controller"
+	errUnknownFeatureGate = "unknown feature gate"
+	errUnknownFormat      = "unknown package format"
+	errUnknownSink        = "unknown package sink"
+	errSkipApplyNoSink    = "packages cannot skip being applied unless a package sink is configured"
+	errKeyWithoutPinning  = "images cannot be verified unless the ImageDigestPinning feature gate is enabled"
+	errNegativeVerbosity  = "verbosity cannot be negative"
+	errNegativeRateLimit  = "rate limit cannot be negative"
+	errMaxDelayBelowBase  = "rate limit max delay cannot be less than its base delay"
+	errNegativePackageMax = "max package bytes cannot be negative"
+	errNegativeGrace      = "shutdown grace period cannot be negative"
+	errUnknownAdoption    = "unknown adoption policy"
+	errParseSelector      = "cannot parse label selector"
+	errParseKubeconfig    = "cannot parse remote kubeconfig Secret reference"
+	errJanitorNoSource    = "the " + ControllerNamespaceJanitor + " controller cannot be enabled unless source remote namespaces are"
+)
+
+// Kind of a configuration file.
+const Kind = "AddonConfiguration"
+
+// Controllers that may be enabled.
+const (
+	ControllerContainerizedWorkload          = "ContainerizedWorkload"
+	ControllerManualScalerTrait              = "ManualScalerTrait"
+	ControllerVolumeMountTrait               = "VolumeMountTrait"
+	ControllerBundleTrait                    = "BundleTrait"
+	ControllerPatchTrait                     = "PatchTrait"
+	ControllerSidecarInjectionTrait          = "SidecarInjectionTrait"
+	ControllerTrafficSplitTrait              = "TrafficSplitTrait"
+	ControllerResourceQuotaTrait             = "ResourceQuotaTrait"
+	ControllerSecurityContextTrait           = "SecurityContextTrait"
+	ControllerCronScalerTrait                = "CronScalerTrait"
+	ControllerPodDisruptionBudgetTrait       = "PodDisruptionBudgetTrait"
+	ControllerAnnotationsAndLabelsTrait      = "AnnotationsAndLabelsTrait"
+	ControllerNamespaceJanitor               = "NamespaceJanitor"
+	ControllerTraitConflictWebhook           = "TraitConflictWebhook"
+	ControllerContainerizedWorkloadDefaulter = "ContainerizedWorkloadDefaulter"
+	ControllerContainerizedWorkloadValidator = "ContainerizedWorkloadValidator"
+	ControllerPreviewEnvironment             = "PreviewEnvironment"
+	ControllerApplicationHealth              = "ApplicationHealth"
+	ControllerDefinitionDiscovery            = "DefinitionDiscovery"
+	ControllerNetworkScope                   = "NetworkScope"
+)
+
+// Feature gates that may be enabled.
+const (
+	FeatureOAMRuntimeInterop  = "OAMRuntimeInterop"
+	FeatureLiveFinalizerReads = "LiveFinalizerReads"
+	FeatureReferenceMirroring = "ReferenceMirroring"
+	FeatureImageDigestPinning = "ImageDigestPinning"
+	FeatureTranslationCache   = "TranslationCache"
+)
+
+var setups = map[string]controller.SetupFn{
+	ControllerContainerizedWorkload:          controller.SetupContainerizedWorkload,
+	ControllerManualScalerTrait:              controller.SetupManualScalerTrait,
+	ControllerVolumeMountTrait:               controller.SetupVolumeMountTrait,
+	ControllerBundleTrait:                    controller.SetupBundleTrait,
+	ControllerPatchTrait:                     controller.SetupPatchTrait,
+	ControllerSidecarInjectionTrait:          controller.SetupSidecarInjectionTrait,
+	ControllerTrafficSplitTrait:              controller.SetupTrafficSplitTrait,
+	ControllerResourceQuotaTrait:             controller.SetupResourceQuotaTrait,
+	ControllerSecurityContextTrait:           controller.SetupSecurityContextTrait,
+	ControllerCronScalerTrait:                controller.SetupCronScalerTrait,
+	ControllerPodDisruptionBudgetTrait:       controller.SetupPodDisruptionBudgetTrait,
+	ControllerAnnotationsAndLabelsTrait:      controller.SetupAnnotationsAndLabelsTrait,
+	ControllerNamespaceJanitor:               controller.SetupNamespaceJanitor,
+	ControllerTraitConflictWebhook:           controller.SetupTraitConflictWebhook,
+	ControllerContainerizedWorkloadDefaulter: controller.SetupContainerizedWorkloadDefaulter,
+	ControllerContainerizedWorkloadValidator: controller.SetupContainerizedWorkloadValidator,
+	ControllerPreviewEnvironment:             controller.SetupPreviewEnvironment,
+	ControllerApplicationHealth:              controller.SetupApplicationHealth,
+	ControllerDefinitionDiscovery:            controller.SetupDefinitionDiscovery,
+	ControllerNetworkScope:                   controller.SetupNetworkScope,
+}
+
+// DefaultControllers are the controllers that are enabled if none are
+// specified.
+var DefaultControllers = []string{
+	ControllerContainerizedWorkload,
+	ControllerManualScalerTrait,
+	ControllerVolumeMountTrait,
+	ControllerBundleTrait,
+	ControllerPatchTrait,
+	ControllerSidecarInjectionTrait,
+	ControllerTrafficSplitTrait,
+	ControllerResourceQuotaTrait,
+	ControllerSecurityContextTrait,
+	ControllerCronScalerTrait,
+	ControllerPodDisruptionBudgetTrait,
+	ControllerAnnotationsAndLabelsTrait,
+}
+
+// A Config configures the OAM Kubernetes Remote addon. Fields that are omitted
+// from a configuration file keep the values of the command line flags.
+type Config struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Debug enables debug logging. It may be changed without a restart.
+	Debug bool `json:"debug"`
+
+	// Verbosity of logging. Debug logging is enabled if it is one or more,
+	// and higher values include the more verbose logs of the controller
+	// runtime. It may be changed without a restart.
+	Verbosity int `json:"verbosity,omitempty"`
+
+	// SyncPeriod at which all watched objects are reconciled.
+	SyncPeriod metav1.Duration `json:"syncPeriod"`
+
+	// FeatureGates that are enabled.
+	FeatureGates []string `json:"featureGates,omitempty"`
+
+	// Controllers that are enabled.
+	Controllers []string `json:"controllers,omitempty"`
+
+	// Namespaces whose objects are watched and reconciled. Objects in all
+	// namespaces are reconciled if it is empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector is a label selector, e.g. "tenant=a", that selects the
+	// workloads and traits that are reconciled. All of them are reconciled if
+	// it is empty.
+	Selector string `json:"selector,omitempty"`
+
+	// Concurrency of the enabled controllers.
+	Concurrency Concurrency `json:"concurrency"`
+
+	// RateLimit of the enabled controllers.
+	RateLimit RateLimit `json:"rateLimit"`
+
+	// DeadLetterAfter is the number of consecutive failed reconciles after
+	// which objects are no longer reconciled. Objects are always reconciled
+	// again if it is zero.
+	DeadLetterAfter int `json:"deadLetterAfter"`
+
+	// PackageFormat in which workloads are packaged.
+	PackageFormat options.PackageFormat `json:"packageFormat"`
+
+	// PackageSink in which packages are stored before they are applied.
+	PackageSink options.PackageSink `json:"packageSink,omitempty"`
+
+	// MaxPackageBytes is the maximum size of each KubernetesApplication a
+	// workload is packaged as. Larger packages are split across several
+	// KubernetesApplications. Packages are never split if it is zero.
+	MaxPackageBytes int `json:"maxPackageBytes"`
+
+	// AdoptionPolicy determines how packages that already exist but have no
+	// controller are handled: Fail, Adopt, or Orphan.
+	AdoptionPolicy workload.AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// SkipApply stores packages in the PackageSink instead of applying them.
+	SkipApply bool `json:"skipApply"`
+
+	// RemoteSchema is the path to the OpenAPI v2 document of the remote
+	// cluster against which workload translations are validated.
+	RemoteSchema string `json:"remoteSchema,omitempty"`
+
+	// SourceRemoteNamespaces creates the translation of each workload that
+	// does not specify a remote namespace in the remote namespace named after
+	// the workload's namespace. The NamespaceJanitor controller requires it.
+	SourceRemoteNamespaces bool `json:"sourceRemoteNamespaces,omitempty"`
+
+	// ImageVerificationKey is the path to a PEM encoded cosign public key.
+	// If set, images are only pinned to digests that have a cosign signature
+	// made by this key.
+	ImageVerificationKey string `json:"imageVerificationKey,omitempty"`
+
+	// ProviderKubernetesConfig packages workloads as provider-kubernetes
+	// Objects that use this ProviderConfig if it is set.
+	ProviderKubernetesConfig string `json:"providerKubernetesConfig,omitempty"`
+
+	// RemoteKubeconfig applies workloads directly to the remote cluster whose
+	// kubeconfig is stored in this namespace/name Secret if it is set.
+	RemoteKubeconfig string `json:"remoteKubeconfig,omitempty"`
+
+	// Metrics configures the metrics endpoint.
+	Metrics Metrics `json:"metrics"`
+
+	// Health configures the health probe endpoints.
+	Health Health `json:"health"`
+
+	// Tracing configures the export of spans of each reconcile.
+	Tracing Tracing `json:"tracing,omitempty"`
+
+	// Webhook configures the admission webhook server.
+	Webhook Webhook `json:"webhook"`
+
+	// LeaderElection configures leader election.
+	LeaderElection LeaderElection `json:"leaderElection"`
+
+	// ShutdownGracePeriod is how long the addon waits for reconciles in
+	// progress to finish once it is asked to exit. Reconciles that start
+	// while it waits are requeued instead.
+	ShutdownGracePeriod metav1.Duration `json:"shutdownGracePeriod"`
+
+	// Messages replace the reasons and messages of the events and conditions
+	// emitted by the workload and trait controllers. Keys are the default
+	// reasons and messages, for example "ReconcileSuccess".
+	Messages map[string]string `json:"messages,omitempty"`
+}
+
+// Concurrency configures the concurrency of the enabled controllers.
+type Concurrency struct {
+	// Reconciles is the maximum number of reconciles each controller may
+	// run concurrently.
+	Reconciles int `json:"reconciles"`
+
+	// Applies is the maximum number of objects of a workload's translation
+	// that may be applied concurrently.
+	Applies int `json:"applies"`
+
+	// PerController overrides the maximum number of concurrent reconciles of
+	// the named controllers.
+	PerController map[string]int `json:"perController,omitempty"`
+}
+
+// A ControllerRateLimit limits how often a controller reconciles each object.
+type ControllerRateLimit struct {
+	// BaseDelay before an object whose reconcile failed is reconciled again.
+	// It doubles with each consecutive failure. Failed reconciles are not
+	// delayed if it is zero.
+	BaseDelay metav1.Duration `json:"baseDelay"`
+
+	// MaxDelay before an object whose reconcile failed is reconciled again.
+	MaxDelay metav1.Duration `json:"maxDelay"`
+
+	// QPS is the number of reconciles per second a controller may start once
+	// its bucket is empty. Reconciles are not limited if it is zero.
+	QPS float64 `json:"qps"`
+
+	// Bucket is the number of reconciles a controller may start in a burst.
+	Bucket int `json:"bucket"`
+}
+
+// Options returns the rate limit options of a controller.
+func (rl ControllerRateLimit) Options() options.RateLimit {
+	return options.RateLimit{
+		BaseDelay: rl.BaseDelay.Duration,
+		MaxDelay:  rl.MaxDelay.Duration,
+		QPS:       rl.QPS,
+		Bucket:    rl.Bucket,
+	}
+}
+
+func (rl ControllerRateLimit) validate() error {
+	if rl.BaseDelay.Duration < 0 || rl.MaxDelay.Duration < 0 || rl.QPS < 0 || rl.Bucket < 0 {
+		return errors.New(errNegativeRateLimit)
+	}
+	if rl.MaxDelay.Duration > 0 && rl.MaxDelay.Duration < rl.BaseDelay.Duration {
+		return errors.New(errMaxDelayBelowBase)
+	}
+	return nil
+}
+
+// RateLimit configures how often the enabled controllers reconcile each
+// object.
+type RateLimit struct {
+	ControllerRateLimit `json:",inline"`
+
+	// PerController overrides the rate limit of the named controllers.
+	PerController map[string]ControllerRateLimit `json:"perController,omitempty"`
+}
+
+// Metrics configures the metrics endpoint.
+type Metrics struct {
+	// BindAddress of the metrics endpoint, or "0" to disable it.
+	BindAddress string `json:"bindAddress"`
+}
+
+// Health configures the health probe endpoints.
+type Health struct {
+	// BindAddress of the /healthz and /readyz endpoints, or "0" to disable
+	// them.
+	BindAddress string `json:"bindAddress"`
+}
+
+// Tracing configures the export of spans of each reconcile.
+type Tracing struct {
+	// OTLPEndpoint of the OpenTelemetry collector to which spans are
+	// exported using OTLP/HTTP, for example http://collector:4318. Spans are
+	// not recorded if it is empty.
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+}
+
+// Webhook configures the admission webhook server.
+type Webhook struct {
+	// Port at which admission webhooks are served.
+	Port int `json:"port"`
+
+	// CertDir containing the tls.crt and tls.key used to serve admission
+	// webhooks.
+	CertDir string `json:"certDir,omitempty"`
+}
+
+// LeaderElection configures leader election.
+type LeaderElection struct {
+	// Enabled configures replicas to use leader election so that only one
+	// replica reconciles at a time.
+	Enabled bool `json:"enabled"`
+
+	// Namespace in which to hold the leader election lock.
+	Namespace string `json:"namespace,omitempty"`
+
+	// ID is the name of the leader election lock.
+	ID string `json:"id"`
+
+	// LeaseDuration non-leader replicas wait before acquiring an unrenewed
+	// lock.
+	LeaseDuration metav1.Duration `json:"leaseDuration"`
+
+	// RenewDeadline is the duration the leader retries renewing its lock
+	// before giving it up.
+	RenewDeadline metav1.Duration `json:"renewDeadline"`
+
+	// RetryPeriod replicas wait between attempts to acquire or renew the
+	// lock.
+	RetryPeriod metav1.Duration `json:"retryPeriod"`
+}
+
+// Load the configuration file at the supplied path. Fields that are omitted
+// from the file keep the values of the supplied base configuration.
+func Load(path string, base *Config) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadConfig)
+	}
+	return Parse(b, base)
+}
+
+// Parse the supplied configuration. Fields that are omitted keep the values of
+// the supplied base configuration.
+func Parse(b []byte, base *Config) (*Config, error) {
+	c := base.DeepCopy()
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, errors.Wrap(err, errParseConfig)
+	}
+	return c, errors.Wrap(c.Validate(), errParseConfig)
+}
+
+// Validate the configuration.
+func (c *Config) Validate() error {
+	for _, name := range c.Controllers {
+		if _, ok := setups[name]; !ok {
+			return errors.Errorf("%s: %s", errUnknownController, name)
+		}
+	}
+	for name := range c.Concurrency.PerController {
+		if _, ok := setups[name]; !ok {
+			return errors.Errorf("%s: %s", errUnknownController, name)
+		}
+	}
+	if err := c.RateLimit.validate(); err != nil {
+		return err
+	}
+	for name, rl := range c.RateLimit.PerController {
+		if _, ok := setups[name]; !ok {
+			return errors.Errorf("%s: %s", errUnknownController, name)
+		}
+		if err := rl.validate(); err != nil {
+			return errors.Wrap(err, name)
+		}
+	}
+	for _, g := range c.FeatureGates {
+		if g != FeatureOAMRuntimeInterop && g != FeatureLiveFinalizerReads && g != FeatureReferenceMirroring && g != FeatureImageDigestPinning && g != FeatureTranslationCache {
+			return errors.Errorf("%s: %s", errUnknownFeatureGate, g)
+		}
+	}
+	if _, err := labels.Parse(c.Selector); err != nil {
+		return errors.Wrap(err, errParseSelector)
+	}
+	if c.RemoteKubeconfig != "" {
+		if _, err := workload.ParseSecretReference(c.RemoteKubeconfig); err != nil {
+			return errors.Wrap(err, errParseKubeconfig)
+		}
+	}
+	switch c.PackageFormat {
+	case "", options.PackageFormatKubernetesApplication, options.PackageFormatManifestWork, options.PackageFormatSecret:
+	default:
+		return errors.Errorf("%s: %s", errUnknownFormat, c.PackageFormat)
+	}
+	switch c.PackageSink {
+	case "", options.PackageSinkConfigMap:
+	default:
+		return errors.Errorf("%s: %s", errUnknownSink, c.PackageSink)
+	}
+	switch c.AdoptionPolicy {
+	case "", workload.AdoptionPolicyFail, workload.AdoptionPolicyAdopt, workload.AdoptionPolicyOrphan:
+	default:
+		return errors.Errorf("%s: %s", errUnknownAdoption, c.AdoptionPolicy)
+	}
+	if c.SkipApply && c.PackageSink == "" {
+		return errors.New(errSkipApplyNoSink)
+	}
+	if c.enabledController(ControllerNamespaceJanitor) && !c.SourceRemoteNamespaces {
+		return errors.New(errJanitorNoSource)
+	}
+	if c.ImageVerificationKey != "" && !c.Enabled(FeatureImageDigestPinning) {
+		return errors.New(errKeyWithoutPinning)
+	}
+	if c.Verbosity < 0 {
+		return errors.New(errNegativeVerbosity)
+	}
+	if c.MaxPackageBytes < 0 {
+		return errors.New(errNegativePackageMax)
+	}
+	if c.ShutdownGracePeriod.Duration < 0 {
+		return errors.New(errNegativeGrace)
+	}
+	return nil
+}
+
+// LogVerbosity returns the verbosity of logging. Enabling debug logging
+// implies a verbosity of at least one.
+func (c *Config) LogVerbosity() int {
+	if c.Debug && c.Verbosity < 1 {
+		return 1
+	}
+	return c.Verbosity
+}
+
+// DeepCopy returns a deep copy of the configuration.
+func (c *Config) DeepCopy() *Config {
+	out := *c
+	out.FeatureGates = append([]string(nil), c.FeatureGates...)
+	out.Controllers = append([]string(nil), c.Controllers...)
+	if c.Concurrency.PerController != nil {
+		out.Concurrency.PerController = make(map[string]int, len(c.Concurrency.PerController))
+		for k, v := range c.Concurrency.PerController {
+			out.Concurrency.PerController[k] = v
+		}
+	}
+	if c.RateLimit.PerController != nil {
+		out.RateLimit.PerController = make(map[string]ControllerRateLimit, len(c.RateLimit.PerController))
+		for k, v := range c.RateLimit.PerController {
+			out.RateLimit.PerController[k] = v
+		}
+	}
+	if c.Messages != nil {
+		out.Messages = make(map[string]string, len(c.Messages))
+		for k, v := range c.Messages {
+			out.Messages[k] = v
+		}
+	}
+	return &out
+}
+
+// Enabled returns true if the supplied feature gate is enabled.
+func (c *Config) Enabled(gate string) bool {
+	for _, g := range c.FeatureGates {
+		if g == gate {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledController returns true if the supplied controller is enabled.
+func (c *Config) enabledController(name string) bool {
+	names := c.Controllers
+	if len(names) == 0 {
+		names = DefaultControllers
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ServesWebhooks returns true if any admission webhooks are enabled.
+func (c *Config) ServesWebhooks() bool {
+	for _, name := range c.Controllers {
+		switch name {
+		case ControllerTraitConflictWebhook, ControllerContainerizedWorkloadDefaulter, ControllerContainerizedWorkloadValidator:
+			return true
+		}
+	}
+	return false
+}
+
+// ManagerOptions returns the options of the controller manager.
+func (c *Config) ManagerOptions() ctrl.Options {
+	duration := func(d metav1.Duration) *time.Duration { return &d.Duration }
+	o := ctrl.Options{
+		SyncPeriod:              duration(c.SyncPeriod),
+		MetricsBindAddress:      c.Metrics.BindAddress,
+		HealthProbeBindAddress:  c.Health.BindAddress,
+		Port:                    c.Webhook.Port,
+		CertDir:                 c.Webhook.CertDir,
+		LeaderElection:          c.LeaderElection.Enabled,
+		LeaderElectionNamespace: c.LeaderElection.Namespace,
+		LeaderElectionID:        c.LeaderElection.ID,
+		LeaseDuration:           duration(c.LeaderElection.LeaseDuration),
+		RenewDeadline:           duration(c.LeaderElection.RenewDeadline),
+		RetryPeriod:             duration(c.LeaderElection.RetryPeriod),
+	}
+
+	// The cache of a manager restricted to several namespaces runs an
+	// informer per namespace.
+	switch len(c.Namespaces) {
+	case 0:
+	case 1:
+		o.Namespace = c.Namespaces[0]
+	default:
+		o.NewCache = cache.MultiNamespacedCacheBuilder(c.Namespaces)
+	}
+	return o
+}
+
+// Options returns the options of the controllers.
+func (c *Config) Options(l logging.Logger) (controller.Options, error) {
+	s, err := labels.Parse(c.Selector)
+	if err != nil {
+		return controller.Options{}, errors.Wrap(err, errParseSelector)
+	}
+	return controller.Options{
+		Logger:                   l,
+		MaxConcurrentReconciles:  c.Concurrency.Reconciles,
+		MaxConcurrentApplies:     c.Concurrency.Applies,
+		RateLimit:                c.RateLimit.Options(),
+		DeadLetterLimit:          c.DeadLetterAfter,
+		Selector:                 s,
+		OAMRuntimeInterop:        c.Enabled(FeatureOAMRuntimeInterop),
+		ProviderKubernetesConfig: c.ProviderKubernetesConfig,
+		RemoteKubeconfig:         c.RemoteKubeconfig,
+		PackageFormat:            c.PackageFormat,
+		PackageSink:              c.PackageSink,
+		MaxPackageBytes:          c.MaxPackageBytes,
+		AdoptionPolicy:           c.AdoptionPolicy,
+		SkipApply:                c.SkipApply,
+		RemoteSchema:             c.RemoteSchema,
+		SourceRemoteNamespaces:   c.SourceRemoteNamespaces,
+		LiveFinalizerReads:       c.Enabled(FeatureLiveFinalizerReads),
+		MirrorReferences:         c.Enabled(FeatureReferenceMirroring),
+		CacheTranslations:        c.Enabled(FeatureTranslationCache),
+		PinImageDigests:          c.Enabled(FeatureImageDigestPinning),
+		ImageVerificationKey:     c.ImageVerificationKey,
+		Messages:                 message.Catalog(c.Messages),
+	}, nil
+}
+
+// Setups returns the setup functions of the enabled controllers. Each
+// controller is set up with its configured concurrency and rate limit.
+func (c *Config) Setups() []controller.SetupFn {
+	names := c.Controllers
+	if len(names) == 0 {
+		names = DefaultControllers
+	}
+
+	fns := make([]controller.SetupFn, 0, len(names))
+	for _, name := range names {
+		fn := setups[name]
+		n, concurrent := c.Concurrency.PerController[name]
+		rl, limited := c.RateLimit.PerController[name]
+		if !concurrent && !limited {
+			fns = append(fns, fn)
+			continue
+		}
+		fns = append(fns, func(mgr ctrl.Manager, o controller.Options) error {
+			if concurrent {
+				o.MaxConcurrentReconciles = n
+			}
+			if limited {
+				o.RateLimit = rl.Options()
+			}
+			return fn(mgr, o)
+		})
+	}
+	return fns
+}
+
+// RequiresRestart returns true if the supplied configuration differs from
+// this one in fields that cannot be changed without a restart.
+func (c *Config) RequiresRestart(other *Config) bool {
+	a, b := c.DeepCopy(), other.DeepCopy()
+
+	// Fields that may be changed without a restart.
+	a.Debug, b.Debug = false, false
+	a.Verbosity, b.Verbosity = 0, 0
+
+	return !reflect.DeepEqual(a, b)
+}
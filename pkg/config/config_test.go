@@ -0,0 +1,409 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func TestParse(t *testing.T) {
+	base := &Config{
+		SyncPeriod:    metav1.Duration{Duration: time.Hour},
+		Controllers:   DefaultControllers,
+		Concurrency:   Concurrency{Reconciles: 1, Applies: 1},
+		PackageFormat: options.PackageFormatKubernetesApplication,
+		Metrics:       Metrics{BindAddress: ":8080"},
+	}
+
+	type want struct {
+		c   *Config
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		b      string
+		want   want
+	}{
+		"OmittedFieldsKeepBase": {
+			reason: "Fields omitted from the configuration file should keep the values of the base configuration.",
+			b: `
+apiVersion: remote.oam.crossplane.io/v1alpha1
+kind: AddonConfiguration
+debug: true
+syncPeriod: 5m
+concurrency:
+  reconciles: 3
+  perController:
+    ManualScalerTrait: 5
+featureGates: [LiveFinalizerReads]
+`,
+			want: want{c: &Config{
+				TypeMeta:      metav1.TypeMeta{APIVersion: "remote.oam.crossplane.io/v1alpha1", Kind: Kind},
+				Debug:         true,
+				SyncPeriod:    metav1.Duration{Duration: 5 * time.Minute},
+				FeatureGates:  []string{FeatureLiveFinalizerReads},
+				Controllers:   DefaultControllers,
+				Concurrency:   Concurrency{Reconciles: 3, Applies: 1, PerController: map[string]int{ControllerManualScalerTrait: 5}},
+				PackageFormat: options.PackageFormatKubernetesApplication,
+				Metrics:       Metrics{BindAddress: ":8080"},
+			}},
+		},
+		"UnknownField": {
+			reason: "Unknown fields should be rejected.",
+			b:      "syncPerod: 5m",
+			want:   want{err: errors.New(errParseConfig)},
+		},
+		"UnknownController": {
+			reason: "Unknown controllers should be rejected.",
+			b:      "controllers: [CoolTrait]",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownController, "CoolTrait"), errParseConfig)},
+		},
+		"UnknownPerController": {
+			reason: "Concurrency of unknown controllers should be rejected.",
+			b:      "concurrency: {perController: {CoolTrait: 2}}",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownController, "CoolTrait"), errParseConfig)},
+		},
+		"RateLimit": {
+			reason: "Rate limits should be parsed, including those of individual controllers.",
+			b: `
+rateLimit:
+  baseDelay: 1s
+  maxDelay: 5m
+  qps: 10
+  bucket: 100
+  perController:
+    ContainerizedWorkload:
+      qps: 50
+      bucket: 500
+`,
+			want: want{c: &Config{
+				SyncPeriod:  metav1.Duration{Duration: time.Hour},
+				Controllers: DefaultControllers,
+				Concurrency: Concurrency{Reconciles: 1, Applies: 1},
+				RateLimit: RateLimit{
+					ControllerRateLimit: ControllerRateLimit{
+						BaseDelay: metav1.Duration{Duration: time.Second},
+						MaxDelay:  metav1.Duration{Duration: 5 * time.Minute},
+						QPS:       10,
+						Bucket:    100,
+					},
+					PerController: map[string]ControllerRateLimit{
+						ControllerContainerizedWorkload: {QPS: 50, Bucket: 500},
+					},
+				},
+				PackageFormat: options.PackageFormatKubernetesApplication,
+				Metrics:       Metrics{BindAddress: ":8080"},
+			}},
+		},
+		"UnknownRateLimitController": {
+			reason: "Rate limits of unknown controllers should be rejected.",
+			b:      "rateLimit: {perController: {CoolTrait: {qps: 2}}}",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownController, "CoolTrait"), errParseConfig)},
+		},
+		"NegativeRateLimit": {
+			reason: "Rate limits should not be negative.",
+			b:      "rateLimit: {qps: -1}",
+			want:   want{err: errors.Wrap(errors.New(errNegativeRateLimit), errParseConfig)},
+		},
+		"MaxDelayBelowBase": {
+			reason: "The max delay of a rate limit should not be less than its base delay.",
+			b:      "rateLimit: {perController: {ContainerizedWorkload: {baseDelay: 1m, maxDelay: 1s}}}",
+			want:   want{err: errors.Wrap(errors.Wrap(errors.New(errMaxDelayBelowBase), ControllerContainerizedWorkload), errParseConfig)},
+		},
+		"UnknownFeatureGate": {
+			reason: "Unknown feature gates should be rejected.",
+			b:      "featureGates: [Cool]",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownFeatureGate, "Cool"), errParseConfig)},
+		},
+		"UnknownFormat": {
+			reason: "Unknown package formats should be rejected.",
+			b:      "packageFormat: Cool",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownFormat, "Cool"), errParseConfig)},
+		},
+		"UnknownSink": {
+			reason: "Unknown package sinks should be rejected.",
+			b:      "packageSink: Cool",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownSink, "Cool"), errParseConfig)},
+		},
+		"SkipApplyWithoutSink": {
+			reason: "Packages should not skip being applied unless they are stored somewhere.",
+			b:      "skipApply: true",
+			want:   want{err: errors.Wrap(errors.New(errSkipApplyNoSink), errParseConfig)},
+		},
+		"VerificationKeyWithoutPinning": {
+			reason: "Images should not be verified unless they are pinned to digests.",
+			b:      "imageVerificationKey: /cosign.pub",
+			want:   want{err: errors.Wrap(errors.New(errKeyWithoutPinning), errParseConfig)},
+		},
+		"NegativeVerbosity": {
+			reason: "Verbosity should not be negative.",
+			b:      "verbosity: -1",
+			want:   want{err: errors.Wrap(errors.New(errNegativeVerbosity), errParseConfig)},
+		},
+		"NegativeMaxPackageBytes": {
+			reason: "The maximum size of a package should not be negative.",
+			b:      "maxPackageBytes: -1",
+			want:   want{err: errors.Wrap(errors.New(errNegativePackageMax), errParseConfig)},
+		},
+		"UnknownAdoptionPolicy": {
+			reason: "Only known adoption policies may be configured.",
+			b:      "adoptionPolicy: Steal",
+			want:   want{err: errors.Wrap(errors.Errorf("%s: %s", errUnknownAdoption, "Steal"), errParseConfig)},
+		},
+		"InvalidSelector": {
+			reason: "The label selector should be valid.",
+			b:      "selector: 'tenant in'",
+			want: want{err: errors.Wrap(errors.Wrap(func() error {
+				_, err := labels.Parse("tenant in")
+				return err
+			}(), errParseSelector), errParseConfig)},
+		},
+		"NegativeShutdownGracePeriod": {
+			reason: "The shutdown grace period should not be negative.",
+			b:      "shutdownGracePeriod: -1s",
+			want:   want{err: errors.Wrap(errors.New(errNegativeGrace), errParseConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := Parse([]byte(tc.b), base)
+
+			// Errors returned by the YAML parser are not worth comparing.
+			if tc.want.err != nil && tc.want.err.Error() == errParseConfig {
+				if err == nil {
+					t.Errorf("\nReason: %s\nParse(...): want error, got nil", tc.reason)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.c, c); diff != "" {
+				t.Errorf("\nReason: %s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *Config
+		want   error
+	}{
+		"Valid": {
+			reason: "A configuration built from default flags should be valid.",
+			c:      &Config{},
+		},
+		"SkipApplyWithSink": {
+			reason: "Packages may skip being applied if they are stored in a package sink.",
+			c:      &Config{SkipApply: true, PackageSink: options.PackageSinkConfigMap},
+		},
+		"SkipApplyWithoutSink": {
+			reason: "Packages should not skip being applied unless a package sink is configured, even if the configuration was built from flags.",
+			c:      &Config{SkipApply: true},
+			want:   errors.New(errSkipApplyNoSink),
+		},
+		"JanitorWithSourceNamespaces": {
+			reason: "The namespace janitor may be enabled if workloads are created in remote namespaces named after their own.",
+			c:      &Config{Controllers: []string{ControllerNamespaceJanitor}, SourceRemoteNamespaces: true},
+		},
+		"JanitorWithoutSourceNamespaces": {
+			reason: "The namespace janitor should not be enabled unless workloads are created in remote namespaces named after their own.",
+			c:      &Config{Controllers: []string{ControllerNamespaceJanitor}},
+			want:   errors.New(errJanitorNoSource),
+		},
+		"RemoteKubeconfig": {
+			reason: "A remote kubeconfig Secret reference of the form namespace/name should be valid.",
+			c:      &Config{RemoteKubeconfig: "cool-ns/cool-kubeconfig"},
+		},
+		"InvalidRemoteKubeconfig": {
+			reason: "A malformed remote kubeconfig Secret reference should be rejected, even if the configuration was built from flags.",
+			c:      &Config{RemoteKubeconfig: "cool-kubeconfig"},
+			want: errors.Wrap(func() error {
+				_, err := workload.ParseSecretReference("cool-kubeconfig")
+				return err
+			}(), errParseKubeconfig),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nValidate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	type want struct {
+		selector string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *Config
+		want   want
+	}{
+		"Selector": {
+			reason: "The configured label selector should select the reconciled objects.",
+			c:      &Config{Selector: "tenant=cool"},
+			want:   want{selector: "tenant=cool"},
+		},
+		"InvalidSelector": {
+			reason: "An invalid label selector should be returned as an error rather than selecting nothing.",
+			c:      &Config{Selector: "tenant in"},
+			want: want{err: errors.Wrap(func() error {
+				_, err := labels.Parse("tenant in")
+				return err
+			}(), errParseSelector)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := tc.c.Options(nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nOptions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.selector, o.Selector.String()); diff != "" {
+				t.Errorf("\nReason: %s\nOptions(...): -want selector, +got selector:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetups(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *Config
+		want   int
+	}{
+		"Default": {
+			reason: "The default controllers should be set up if none are specified.",
+			c:      &Config{},
+			want:   len(DefaultControllers),
+		},
+		"Specified": {
+			reason: "Only the specified controllers should be set up.",
+			c: &Config{
+				Controllers: []string{ControllerContainerizedWorkload, ControllerNamespaceJanitor},
+				Concurrency: Concurrency{PerController: map[string]int{ControllerNamespaceJanitor: 2}},
+				RateLimit:   RateLimit{PerController: map[string]ControllerRateLimit{ControllerContainerizedWorkload: {QPS: 5}}},
+			},
+			want: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := len(tc.c.Setups())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.Setups(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequiresRestart(t *testing.T) {
+	current := &Config{SyncPeriod: metav1.Duration{Duration: time.Hour}}
+
+	cases := map[string]struct {
+		reason string
+		other  *Config
+		want   bool
+	}{
+		"DebugChanged": {
+			reason: "Debug logging may be changed without a restart.",
+			other:  &Config{Debug: true, SyncPeriod: metav1.Duration{Duration: time.Hour}},
+			want:   false,
+		},
+		"VerbosityChanged": {
+			reason: "Verbosity may be changed without a restart.",
+			other:  &Config{Verbosity: 2, SyncPeriod: metav1.Duration{Duration: time.Hour}},
+			want:   false,
+		},
+		"SyncPeriodChanged": {
+			reason: "The sync period may not be changed without a restart.",
+			other:  &Config{SyncPeriod: metav1.Duration{Duration: time.Minute}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := current.RequiresRestart(tc.other)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.RequiresRestart(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLogVerbosity(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *Config
+		want   int
+	}{
+		"Default": {
+			reason: "Logging should not be verbose by default.",
+			c:      &Config{},
+			want:   0,
+		},
+		"Debug": {
+			reason: "Debug logging should imply a verbosity of one.",
+			c:      &Config{Debug: true},
+			want:   1,
+		},
+		"Verbosity": {
+			reason: "An explicit verbosity should take precedence over debug logging.",
+			c:      &Config{Debug: true, Verbosity: 3},
+			want:   3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.c.LogVerbosity()); diff != "" {
+				t.Errorf("\nReason: %s\nc.LogVerbosity(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
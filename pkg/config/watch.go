@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultReloadInterval is the default interval at which a Watcher checks its
+// configuration file for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// A ReloadFn is called with a configuration that was reloaded.
+type ReloadFn func(c *Config)
+
+// A WatcherOption configures a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithLogger specifies how the Watcher should log messages.
+func WithLogger(l logging.Logger) WatcherOption {
+	return func(w *Watcher) {
+		w.log = l
+	}
+}
+
+// WithInterval specifies the interval at which the Watcher checks its
+// configuration file for changes.
+func WithInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+// A Watcher reloads a configuration file when it changes. Changes to fields
+// that may be changed without a restart are passed to a ReloadFn. Changes to
+// other fields are logged and ignored until the addon is restarted.
+type Watcher struct {
+	path     string
+	base     *Config
+	current  *Config
+	interval time.Duration
+	log      logging.Logger
+}
+
+// NewWatcher returns a Watcher of the configuration file at the supplied path,
+// which was loaded as the supplied current configuration on top of the
+// supplied base configuration.
+func NewWatcher(path string, base, current *Config, o ...WatcherOption) *Watcher {
+	w := &Watcher{
+		path:     path,
+		base:     base,
+		current:  current,
+		interval: DefaultReloadInterval,
+		log:      logging.NewNopLogger(),
+	}
+	for _, wo := range o {
+		wo(w)
+	}
+	return w
+}
+
+// Watch the configuration file until the supplied channel is closed.
+func (w *Watcher) Watch(stop <-chan struct{}, fn ReloadFn) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	last, _ := ioutil.ReadFile(w.path)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+
+		b, err := ioutil.ReadFile(w.path)
+		if err != nil {
+			w.log.Info("Cannot read configuration file", "path", w.path, "error", err)
+			continue
+		}
+		if bytes.Equal(b, last) {
+			continue
+		}
+		last = b
+		w.reload(b, fn)
+	}
+}
+
+func (w *Watcher) reload(b []byte, fn ReloadFn) {
+	c, err := Parse(b, w.base)
+	if err != nil {
+		w.log.Info("Cannot reload configuration file", "path", w.path, "error", err)
+		return
+	}
+	if w.current.RequiresRestart(c) {
+		w.log.Info("Configuration file changed fields that require a restart; only fields that can be changed without a restart were reloaded", "path", w.path)
+	}
+
+	// Only fields that may be changed without a restart are reloaded.
+	reloaded := w.current.DeepCopy()
+	reloaded.Debug = c.Debug
+	reloaded.Verbosity = c.Verbosity
+	w.current = reloaded
+
+	w.log.Debug("Reloaded configuration file", "path", w.path)
+	fn(reloaded)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotAnnotationsAndLabelsTrait  = "trait is not an annotations and labels trait"
+	errMatchTargetName               = "cannot match template name against target name pattern"
+	errNoTemplateForAnnotationsLabel = "no resource template matches the targets of the annotations and labels trait"
+	errAddAnnotationsAndLabels       = "cannot add annotations and labels to resource template"
+)
+
+// podTemplatePaths are the fields at which templates of each kind embed a pod
+// template.
+var podTemplatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:  {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}: {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:   {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:  {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:        {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:    {"spec", "jobTemplate", "spec", "template"},
+}
+
+// SetupAnnotationsAndLabelsTrait adds a controller that reconciles
+// AnnotationsAndLabelsTraits that reference a ContainerizedWorkload.
+func SetupAnnotationsAndLabelsTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.AnnotationsAndLabelsTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.AnnotationsAndLabelsTrait{}, remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.ModifyFn(annotationsAndLabelsModifier)),
+		))))
+}
+
+// annotationsAndLabelsModifier adds the labels and annotations of an
+// AnnotationsAndLabelsTrait to the resource templates of a
+// KubernetesApplication that its targets select, and to their pod templates.
+// Existing labels and annotations with the same keys are replaced, except pod
+// template labels that the template's selector matches on, which are left
+// unchanged so that the trait cannot orphan the template's pods.
+func annotationsAndLabelsModifier(_ context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	alt, ok := t.(*remotev1alpha1.AnnotationsAndLabelsTrait)
+	if !ok {
+		return errors.New(errNotAnnotationsAndLabelsTrait)
+	}
+
+	templates, err := trait.Templates(a)
+	if err != nil {
+		return err
+	}
+
+	selected := false
+	for _, u := range templates {
+		ok, err := annotationsAndLabelsTargets(alt.Spec.Targets, u)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := addAnnotationsAndLabels(u, alt.Spec.Annotations, alt.Spec.Labels); err != nil {
+			return errors.Wrap(err, errAddAnnotationsAndLabels)
+		}
+		selected = true
+	}
+	if !selected {
+		// Shards of a sharded package need not contain the selected
+		// templates.
+		return trait.NewNoTargetError(errNoTemplateForAnnotationsLabel)
+	}
+
+	return trait.SetTemplates(a, templates)
+}
+
+// annotationsAndLabelsTargets returns true if any of the supplied targets
+// selects the supplied resource template, or if there are no targets.
+func annotationsAndLabelsTargets(targets []remotev1alpha1.AnnotationsAndLabelsTarget, u *unstructured.Unstructured) (bool, error) {
+	if len(targets) == 0 {
+		return true, nil
+	}
+	for _, t := range targets {
+		if t.APIVersion != "" && t.APIVersion != u.GetAPIVersion() {
+			continue
+		}
+		if t.Kind != "" && t.Kind != u.GetKind() {
+			continue
+		}
+		if t.Name == "" {
+			return true, nil
+		}
+		ok, err := path.Match(t.Name, u.GetName())
+		if err != nil {
+			return false, errors.Wrapf(err, "%s %q", errMatchTargetName, t.Name)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addAnnotationsAndLabels adds the supplied annotations and labels to the
+// supplied resource template and, if it embeds one, to its pod template.
+func addAnnotationsAndLabels(u *unstructured.Unstructured, annotations, labels map[string]string) error {
+	if len(annotations) > 0 {
+		u.SetAnnotations(mergeMetadata(u.GetAnnotations(), annotations, nil))
+	}
+	if len(labels) > 0 {
+		u.SetLabels(mergeMetadata(u.GetLabels(), labels, nil))
+	}
+
+	pt, ok := podTemplatePaths[u.GroupVersionKind().GroupKind()]
+	if !ok {
+		return nil
+	}
+	_, found, err := unstructured.NestedMap(u.Object, pt...)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	selector, _, err := unstructured.NestedStringMap(u.Object, "spec", "selector", "matchLabels")
+	if err != nil {
+		return err
+	}
+	if err := addPodTemplateMetadata(u, pt, "annotations", annotations, nil); err != nil {
+		return err
+	}
+	return addPodTemplateMetadata(u, pt, "labels", labels, selector)
+}
+
+// addPodTemplateMetadata adds the supplied labels or annotations, except those
+// whose keys are in skip, to the metadata field of the pod template at the
+// supplied path of the supplied resource template.
+func addPodTemplateMetadata(u *unstructured.Unstructured, pt []string, field string, add, skip map[string]string) error {
+	if len(add) == 0 {
+		return nil
+	}
+	fields := append(append([]string{}, pt...), "metadata", field)
+	existing, _, err := unstructured.NestedStringMap(u.Object, fields...)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedStringMap(u.Object, mergeMetadata(existing, add, skip), fields...)
+}
+
+// mergeMetadata returns the supplied existing labels or annotations with the
+// supplied additions, except those whose keys are in skip.
+func mergeMetadata(existing, add, skip map[string]string) map[string]string {
+	out := make(map[string]string, len(existing)+len(add))
+	for k, v := range existing {
+		out[k] = v
+	}
+	for k, v := range add {
+		if _, ok := skip[k]; ok {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestAnnotationsAndLabelsModifier(t *testing.T) {
+	labels := map[string]string{"team": "payments", "app": "other"}
+	annotations := map[string]string{"prometheus.io/scrape": "true"}
+
+	deployment := func(meta, podMeta map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": deploymentAPIVersion,
+			"kind":       deploymentKind,
+			"metadata":   meta,
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "cool"}},
+				"template": map[string]interface{}{"metadata": podMeta},
+			},
+		}
+	}
+	custom := func(meta map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "Cache",
+			"metadata":   meta,
+		}
+	}
+	kubeApp := func(templates ...interface{}) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, t := range templates {
+			b, _ := json.Marshal(t)
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: b}},
+			})
+		}
+		return a
+	}
+	alt := func(targets ...remotev1alpha1.AnnotationsAndLabelsTarget) *remotev1alpha1.AnnotationsAndLabelsTrait {
+		return &remotev1alpha1.AnnotationsAndLabelsTrait{Spec: remotev1alpha1.AnnotationsAndLabelsTraitSpec{
+			Labels:      labels,
+			Annotations: annotations,
+			Targets:     targets,
+		}}
+	}
+
+	// The pod template's app label is matched on by the Deployment's
+	// selector, and thus must not be replaced.
+	unlabelled := func(name string) map[string]interface{} {
+		return deployment(
+			map[string]interface{}{"name": name},
+			map[string]interface{}{"labels": map[string]interface{}{"app": "cool"}},
+		)
+	}
+	labelled := func(name string) map[string]interface{} {
+		return deployment(
+			map[string]interface{}{
+				"name":        name,
+				"labels":      map[string]interface{}{"team": "payments", "app": "other"},
+				"annotations": map[string]interface{}{"prometheus.io/scrape": "true"},
+			},
+			map[string]interface{}{
+				"labels":      map[string]interface{}{"team": "payments", "app": "cool"},
+				"annotations": map[string]interface{}{"prometheus.io/scrape": "true"},
+			},
+		)
+	}
+
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to modifier that is not a KubernetesApplication should return error.",
+			args:   args{o: &appsv1.Deployment{}, t: alt()},
+			want:   want{o: &appsv1.Deployment{}, err: errors.New(errNotKubeApp)},
+		},
+		"ErrorTraitNotAnnotationsAndLabelsTrait": {
+			reason: "Trait passed to modifier that is not an AnnotationsAndLabelsTrait should return error.",
+			args:   args{o: kubeApp(), t: &traitfake.Trait{}},
+			want:   want{o: kubeApp(), err: errors.New(errNotAnnotationsAndLabelsTrait)},
+		},
+		"ErrorBadNamePattern": {
+			reason: "A target whose name is not a valid glob pattern should return error.",
+			args:   args{o: kubeApp(unlabelled("web")), t: alt(remotev1alpha1.AnnotationsAndLabelsTarget{Name: "["})},
+			want:   want{o: kubeApp(unlabelled("web")), err: errors.Wrapf(path.ErrBadPattern, "%s %q", errMatchTargetName, "[")},
+		},
+		"ErrorNoTemplateSelected": {
+			reason: "Targets that select no template should return an error indicating the package has no target.",
+			args:   args{o: kubeApp(unlabelled("web")), t: alt(remotev1alpha1.AnnotationsAndLabelsTarget{Kind: "StatefulSet"})},
+			want:   want{o: kubeApp(unlabelled("web")), err: trait.NewNoTargetError(errNoTemplateForAnnotationsLabel)},
+		},
+		"AllTemplates": {
+			reason: "Every template and pod template should be labelled and annotated if the trait has no targets.",
+			args: args{
+				o: kubeApp(unlabelled("web"), custom(map[string]interface{}{"name": "cache"})),
+				t: alt(),
+			},
+			want: want{o: kubeApp(labelled("web"), custom(map[string]interface{}{
+				"name":        "cache",
+				"labels":      map[string]interface{}{"team": "payments", "app": "other"},
+				"annotations": map[string]interface{}{"prometheus.io/scrape": "true"},
+			}))},
+		},
+		"SelectedTemplates": {
+			reason: "Only templates whose kind and name match a target should be labelled and annotated.",
+			args: args{
+				o: kubeApp(unlabelled("web-frontend"), unlabelled("api"), custom(map[string]interface{}{"name": "web-cache"})),
+				t: alt(remotev1alpha1.AnnotationsAndLabelsTarget{APIVersion: deploymentAPIVersion, Kind: deploymentKind, Name: "web-*"}),
+			},
+			want: want{o: kubeApp(labelled("web-frontend"), unlabelled("api"), custom(map[string]interface{}{"name": "web-cache"}))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := annotationsAndLabelsModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nannotationsAndLabelsModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nannotationsAndLabelsModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotBundleTrait       = "trait is not a bundle trait"
+	errGetBundle            = "cannot get bundle"
+	errNoContainerForBundle = "no container found for bundle"
+)
+
+// SetupBundleTrait adds a controller that reconciles BundleTraits that
+// reference a ContainerizedWorkload.
+func SetupBundleTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.BundleTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.BundleTrait{}, remotev1alpha1.BundleTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.BundleTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.BundleTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(&bundleModifier{client: mgr.GetClient()}),
+		))))
+}
+
+// A bundleModifier applies the Bundle referenced by a BundleTrait to the
+// Deployment packaged in a KubernetesApplication.
+type bundleModifier struct {
+	client client.Reader
+}
+
+func (m *bundleModifier) Modify(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	bt, ok := t.(*remotev1alpha1.BundleTrait)
+	if !ok {
+		return errors.New(errNotBundleTrait)
+	}
+
+	b := &remotev1alpha1.Bundle{}
+	nn := types.NamespacedName{Namespace: bt.GetNamespace(), Name: bt.Spec.BundleReference.Name}
+	if err := m.client.Get(ctx, nn, b); err != nil {
+		return errors.Wrap(err, errGetBundle)
+	}
+
+	return trait.DeploymentFromKubeAppAccessor(ctx, obj, bt, applyBundle(b))
+}
+
+// applyBundle returns a ModifyFn that adds the environment variables, volumes,
+// and volume mounts of the supplied Bundle to a Deployment. Entries of the
+// Bundle replace any existing entries of the same name.
+func applyBundle(b *remotev1alpha1.Bundle) trait.ModifyFn {
+	return func(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+		d, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return errors.New(errNotDeployment)
+		}
+
+		bt, ok := t.(*remotev1alpha1.BundleTrait)
+		if !ok {
+			return errors.New(errNotBundleTrait)
+		}
+
+		for _, v := range b.Spec.Volumes {
+			setPodVolume(&d.Spec.Template.Spec, v)
+		}
+
+		applied := false
+		for i := range d.Spec.Template.Spec.Containers {
+			c := &d.Spec.Template.Spec.Containers[i]
+			if len(bt.Spec.Containers) > 0 && !contains(bt.Spec.Containers, c.Name) {
+				continue
+			}
+			for _, e := range b.Spec.Env {
+				setEnv(c, e)
+			}
+			for _, m := range b.Spec.VolumeMounts {
+				setVolumeMount(c, m)
+			}
+			applied = true
+		}
+		if !applied {
+			return errors.Errorf("%s: %s", errNoContainerForBundle, b.GetName())
+		}
+
+		return nil
+	}
+}
+
+// setEnv adds or replaces the supplied environment variable in the container.
+func setEnv(c *corev1.Container, e corev1.EnvVar) {
+	for i := range c.Env {
+		if c.Env[i].Name == e.Name {
+			c.Env[i] = e
+			return
+		}
+	}
+	c.Env = append(c.Env, e)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestApplyBundle(t *testing.T) {
+	type args struct {
+		b *remotev1alpha1.Bundle
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	proxy := corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}
+	ca := corev1.Volume{
+		Name: "ca-certs",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "ca-certs"}},
+		},
+	}
+	caMount := corev1.VolumeMount{Name: "ca-certs", MountPath: "/etc/ssl/certs", ReadOnly: true}
+
+	b := &remotev1alpha1.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "corporate"},
+		Spec: remotev1alpha1.BundleSpec{
+			Env:          []corev1.EnvVar{proxy},
+			Volumes:      []corev1.Volume{ca},
+			VolumeMounts: []corev1.VolumeMount{caMount},
+		},
+	}
+
+	bt := &remotev1alpha1.BundleTrait{
+		Spec: remotev1alpha1.BundleTraitSpec{
+			BundleReference: corev1.LocalObjectReference{Name: "corporate"},
+			Containers:      []string{"cool-container"},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to modifier that is not a Deployment should return error.",
+			args: args{
+				b: b,
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotBundleTrait": {
+			reason: "Trait passed to modifier that is not a BundleTrait should return error.",
+			args: args{
+				b: b,
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotBundleTrait)},
+		},
+		"ErrorNoContainer": {
+			reason: "A bundle that targets no container in the Deployment should return error.",
+			args: args{
+				b: b,
+				o: deployment(dmWithContainer(corev1.Container{Name: "other-container"})),
+				t: bt,
+			},
+			want: want{
+				o: deployment(dmWithContainer(corev1.Container{Name: "other-container"}), func(d *appsv1.Deployment) {
+					d.Spec.Template.Spec.Volumes = []corev1.Volume{ca}
+				}),
+				err: errors.Errorf("%s: %s", errNoContainerForBundle, "corporate"),
+			},
+		},
+		"Success": {
+			reason: "The bundle's env vars and mounts should be applied to the named containers, replacing existing entries of the same name.",
+			args: args{
+				b: b,
+				o: deployment(
+					dmWithContainer(corev1.Container{
+						Name: "cool-container",
+						Env:  []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "stale"}, {Name: "KEEP", Value: "me"}},
+					}),
+					dmWithContainer(corev1.Container{Name: "other-container"}),
+				),
+				t: bt,
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{
+						Name:         "cool-container",
+						Env:          []corev1.EnvVar{proxy, {Name: "KEEP", Value: "me"}},
+						VolumeMounts: []corev1.VolumeMount{caMount},
+					}),
+					dmWithContainer(corev1.Container{Name: "other-container"}),
+					func(d *appsv1.Deployment) {
+						d.Spec.Template.Spec.Volumes = []corev1.Volume{ca}
+					},
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := applyBundle(tc.args.b)(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\napplyBundle(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\napplyBundle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBundleModifier(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		t      trait.Trait
+		want   error
+	}{
+		"ErrorTraitNotBundleTrait": {
+			reason: "Trait passed to modifier that is not a BundleTrait should return error.",
+			t:      &traitfake.Trait{},
+			want:   errors.New(errNotBundleTrait),
+		},
+		"ErrorGetBundle": {
+			reason: "Errors getting the referenced Bundle should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			t:      &remotev1alpha1.BundleTrait{},
+			want:   errors.Wrap(errBoom, errGetBundle),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &bundleModifier{client: tc.c}
+			err := m.Modify(context.Background(), &appsv1.Deployment{}, tc.t)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nm.Modify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -27,18 +27,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
 
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/interop/oamruntime"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/expiry"
 	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 // Reconcile error strings.
 const (
 	errNotContainerizedWorkload = "object is not a containerized workload"
+	errAddExpiryWheel           = "cannot add expiry timer wheel to manager"
+	errLoadRemoteSchema         = "cannot load remote cluster schema"
+	errParseRemoteKubeconfig    = "cannot parse remote kubeconfig secret reference"
+	errIndexMirroredReferences  = "cannot index mirrored references of containerized workloads"
 )
 
 const labelKey = "containerizedworkload.oam.crossplane.io"
@@ -49,23 +57,173 @@ var (
 )
 
 // SetupContainerizedWorkload adds a controller that reconciles ContainerizedWorkloads.
-func SetupContainerizedWorkload(mgr ctrl.Manager, l logging.Logger) error {
+func SetupContainerizedWorkload(mgr ctrl.Manager, o options.Options) error {
 	name := "oam/" + strings.ToLower(oamv1alpha2.ContainerizedWorkloadGroupKind)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	wrappers := translationWrappers(mgr.GetClient())
+
+	// Workloads are reconciled when their TTL elapses, regardless of the sync
+	// period.
+	wheel := expiry.NewWheel()
+	if err := mgr.Add(wheel); err != nil {
+		return errors.Wrap(err, errAddExpiryWheel)
+	}
+
+	ro := []workload.ReconcilerOption{
+		workload.WithLogger(o.Logger.WithValues("controller", name)),
+		workload.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		workload.WithRevisionTracker(workload.NewControllerRevisionTracker(mgr.GetClient(), mgr.GetScheme(), workload.DefaultRevisionHistoryLimit)),
+		workload.WithMaxConcurrentApplies(o.MaxConcurrentApplies),
+		workload.WithChangelog(),
+		workload.WithMessageCatalog(o.Messages),
+		workload.WithSelector(o.Selector),
+		workload.WithTracer(o.Tracer),
+		workload.WithPackageKinds(),
+		workload.WithExpiryScheduler(wheel),
+		workload.WithDefinitionHasher(workload.NewWorkloadDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+	}
+
+	resolvers := workload.NewResolverChain(
+		workload.InheritTarget(mgr.GetClient()),
+		workload.NewParameterSubstitutor(workload.ApplicationConfigurationParameters(mgr.GetClient())),
+	)
+
+	// Secrets and ConfigMaps referenced by the pods of workloads that opt in
+	// are mirrored into their packages. Workloads are reconciled and
+	// translated again when an object they mirror changes.
+	var mirror *ReferenceMirror
+	if o.MirrorReferences {
+		if err := AddMirroredReferenceIndex(mgr.GetFieldIndexer()); err != nil {
+			return errors.Wrap(err, errIndexMirroredReferences)
+		}
+		mirror = NewReferenceMirror(mgr.GetClient())
+		wrappers = append(wrappers, mirror.Wrap)
+	}
+
+	// Images are pinned to the digests their tags resolve to, so that remote
+	// clusters never run an image other than the one that was resolved.
+	if o.PinImageDigests {
+		r, err := NewImageResolver(o.ImageVerificationKey)
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, NewImageDigestPinner(mgr.GetClient(), r))
+	}
+	ro = append(ro, workload.WithParameterResolver(resolvers))
+
+	// Translations are only cached if the cache was opted in to, because it
+	// does not see changes to the Secrets and targets workloads reference.
+	var tc workload.TranslationCache = workload.NopTranslationCache{}
+	if o.CacheTranslations {
+		tc = workload.NewGenerationCache()
+		ro = append(ro, workload.WithTranslationCache(tc))
+	}
+
+	// Packages may be stored for review in addition to, or instead of, being
+	// applied.
+	if o.PackageSink == options.PackageSinkConfigMap {
+		ro = append(ro, workload.WithPackageSink(workload.NewConfigMapPackageSink(mgr.GetClient())))
+	}
+	if o.SkipApply {
+		ro = append(ro, workload.WithoutApply())
+	}
+
+	// Integrators may run their own logic around packaging and applying.
+	ro = append(ro, o.Hooks()...)
+
+	// Translations that the remote cluster's version cannot accept are
+	// rejected before they are packaged.
+	if o.RemoteSchema != "" {
+		v, err := workload.NewSchemaValidatorFromFile(o.RemoteSchema)
+		if err != nil {
+			return errors.Wrap(err, errLoadRemoteSchema)
+		}
+		ro = append(ro, workload.WithValidator(v))
+	}
+
+	var p workload.Packager
+	kubeApps := false
+	switch {
+	case o.ProviderKubernetesConfig != "":
+		// Objects are cluster scoped, so they cannot be garbage collected
+		// when their workload is deleted.
+		p = workload.PackageFn(workload.ObjectWrapper(o.ProviderKubernetesConfig))
+		ro = append(ro,
+			workload.WithApplyOptions(workload.ObjectsMustMatch(o.AdoptionPolicy), workload.PreserveRenderInputs()),
+			workload.WithObjectNamer(workload.NameObjects),
+			workload.WithPackageDeleter(workload.NewObjectDeleter(mgr.GetClient())),
+		)
+	case o.RemoteKubeconfig != "":
+		// Objects are applied to the remote cluster as they were translated,
+		// and deleted from it by the same applicator because they cannot be
+		// garbage collected.
+		ref, err := workload.ParseSecretReference(o.RemoteKubeconfig)
+		if err != nil {
+			return errors.Wrap(err, errParseRemoteKubeconfig)
+		}
+		a := workload.NewKubeconfigApplicator(mgr.GetClient(), mgr.GetScheme(), ref)
+		p = workload.PackageFn(workload.NoopPackage)
+		ro = append(ro,
+			workload.WithApplicator(a),
+			workload.WithApplyOptions(workload.PreserveRenderInputs()),
+			workload.WithObjectNamer(workload.NameRemoteObjects),
+			workload.WithPackageDeleter(a),
+		)
+	case o.PackageFormat == options.PackageFormatManifestWork:
+		p = workload.PackageFn(workload.ManifestWorkWrapper)
+		ro = append(ro, workload.WithApplyOptions(workload.ControllersMustMatch(o.AdoptionPolicy), workload.PreserveRenderInputs()))
+	case o.PackageFormat == options.PackageFormatSecret:
+		p = workload.PackageFn(workload.SecretWrapper)
+		ro = append(ro, workload.WithApplyOptions(workload.ControllersMustMatch(o.AdoptionPolicy), workload.PreserveRenderInputs()))
+	default:
+		kubeApps = true
+		p = workload.NewPackagerWithWrappers(workload.PackageFn(workload.KubeAppWrapper), workload.PinTarget(mgr.GetClient()), workload.ShardKubeApps(o.MaxPackageBytes))
+		ro = append(ro,
+			workload.WithApplyOptions(workload.ControllersMustMatch(o.AdoptionPolicy), workload.KubeAppApplyOption(), workload.ClaimTemplates(name), workload.PreserveRenderInputs()),
+			workload.WithConnectionPublisher(workload.NewAPIServiceEndpointPublisher(mgr.GetClient())),
+			workload.WithRolloutObserver(workload.NewKubeAppRolloutObserver(mgr.GetClient())),
+			workload.WithRemoteNamespace(o.RemoteNamespacer()),
+			workload.WithWaveGate(),
+		)
+	}
+
+	// Only KubernetesApplications can propagate the Secrets of a workload's
+	// namespace to the remote cluster without copying them into its package.
+	if !kubeApps {
+		wrappers = append(wrappers, workload.RejectSecretPropagation)
+	}
+
+	// Labels of workloads rendered by the OAM Kubernetes runtime are
+	// propagated to both the remote objects and the package that contains
+	// them.
+	if o.OAMRuntimeInterop {
+		wrappers = append(wrappers, oamruntime.PropagateLabels)
+		p = workload.NewPackagerWithWrappers(p, oamruntime.PropagateLabels)
+	}
+	// Objects are ordered last, once every wrapper has added its objects.
+	wrappers = append(wrappers, workload.OrderByWave)
+	ro = append(ro,
+		workload.WithTranslator(workload.NewObjectTranslatorWithWrappers(containerizedWorkloadTranslator, wrappers...)),
+		workload.WithPackager(p),
+	)
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForController()).
 		For(&oamv1alpha2.ContainerizedWorkload{}).
-		Complete(workload.NewReconciler(mgr,
-			workload.Kind(oamv1alpha2.ContainerizedWorkloadGroupVersionKind),
-			workload.WithLogger(l.WithValues("controller", name)),
-			workload.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			workload.WithApplyOptions(resource.ControllersMustMatch(), workload.KubeAppApplyOption()),
-			workload.WithTranslator(workload.NewObjectTranslatorWithWrappers(
-				containerizedWorkloadTranslator,
-				workload.ServiceInjector,
-				workload.KubeAppWrapper,
-			)),
-		))
+		Watches(wheel.Source(), &handler.EnqueueRequestForObject{})
+	if mirror != nil {
+		b = b.
+			Watches(&source.Kind{Type: &corev1.Secret{}}, EnqueueRequestsForMirroringWorkloads(mgr.GetClient(), tc)).
+			Watches(&source.Kind{Type: &corev1.ConfigMap{}}, EnqueueRequestsForMirroringWorkloads(mgr.GetClient(), tc))
+	}
+	return b.Complete(o.Wrap(withDeadLetters(mgr, o, name, oamv1alpha2.ContainerizedWorkloadGroupVersionKind, workload.NewReconciler(mgr, workload.Kind(oamv1alpha2.ContainerizedWorkloadGroupVersionKind), ro...))))
+}
+
+// translationWrappers returns the wrappers that complete the translation of a
+// ContainerizedWorkload.
+func translationWrappers(c client.Reader) []workload.TranslationWrapper {
+	return []workload.TranslationWrapper{workload.ServiceInjector, workload.NetworkScopeWrapper, workload.SuspendWrapper, workload.ProgressDeadlineWrapper, NewImagePullSecretCopier(c), workload.RawTemplateWrapper}
 }
 
 func containerizedWorkloadTranslator(ctx context.Context, w workload.Workload) ([]workload.Object, error) {
@@ -97,26 +255,19 @@ func containerizedWorkloadTranslator(ctx context.Context, w workload.Workload) (
 			},
 		},
 	}
+	// Remote clusters may have nodes of mixed operating systems and
+	// architectures, so we constrain pods to nodes that can run them.
 	if cw.Spec.OperatingSystem != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
-		}
-		d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = string(*cw.Spec.OperatingSystem)
+		setNodeSelector(&d.Spec.Template.Spec, corev1.LabelOSStable, string(*cw.Spec.OperatingSystem))
 	}
 
 	if cw.Spec.CPUArchitecture != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
-		}
-		d.Spec.Template.Spec.NodeSelector["kubernetes.io/arch"] = string(*cw.Spec.CPUArchitecture)
+		setNodeSelector(&d.Spec.Template.Spec, corev1.LabelArchStable, string(*cw.Spec.CPUArchitecture))
 	}
 
+	setImagePullSecrets(&d.Spec.Template.Spec, imagePullSecrets(cw))
+
 	for _, container := range cw.Spec.Containers {
-		if container.ImagePullSecret != nil {
-			d.Spec.Template.Spec.ImagePullSecrets = append(d.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{
-				Name: *container.ImagePullSecret,
-			})
-		}
 		kubernetesContainer := corev1.Container{
 			Name:    container.Name,
 			Image:   container.Image,
@@ -125,12 +276,11 @@ func containerizedWorkloadTranslator(ctx context.Context, w workload.Workload) (
 		}
 
 		if container.Resources != nil {
-			kubernetesContainer.Resources = corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceCPU:    container.Resources.CPU.Required,
-					corev1.ResourceMemory: container.Resources.Memory.Required,
-				},
+			rr, err := resourceRequirements(container.Resources)
+			if err != nil {
+				return nil, err
 			}
+			kubernetesContainer.Resources = rr
 			for _, v := range container.Resources.Volumes {
 				mount := corev1.VolumeMount{
 					Name:      v.Name,
@@ -259,5 +409,83 @@ func containerizedWorkloadTranslator(ctx context.Context, w workload.Workload) (
 		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, kubernetesContainer)
 	}
 
+	src, err := envSources(cw)
+	if err != nil {
+		return nil, err
+	}
+	if err := setEnvSources(&d.Spec.Template.Spec, src); err != nil {
+		return nil, err
+	}
+
+	limits, err := resourceLimits(cw)
+	if err != nil {
+		return nil, err
+	}
+	if err := setResourceLimits(&d.Spec.Template.Spec, limits); err != nil {
+		return nil, err
+	}
+
+	l, err := lifecycles(cw)
+	if err != nil {
+		return nil, err
+	}
+	if err := setLifecycles(&d.Spec.Template.Spec, l); err != nil {
+		return nil, err
+	}
+
+	grace, err := terminationGracePeriod(cw)
+	if err != nil {
+		return nil, err
+	}
+	d.Spec.Template.Spec.TerminationGracePeriodSeconds = grace
+
+	dn, err := dns(cw)
+	if err != nil {
+		return nil, err
+	}
+	setDNS(&d.Spec.Template.Spec, dn)
+
+	sc, err := scheduling(cw)
+	if err != nil {
+		return nil, err
+	}
+	setScheduling(&d.Spec.Template.Spec, sc)
+
+	sp, err := spread(cw)
+	if err != nil {
+		return nil, err
+	}
+	setSpread(&d.Spec.Template.Spec, sp, d.Spec.Selector)
+
+	r, err := replicas(cw)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := mode(cw)
+	if err != nil {
+		return nil, err
+	}
+	if m == ModeDaemonSet {
+		if r != nil {
+			return nil, errors.New(errDaemonSetReplicas)
+		}
+		if !sp.empty() {
+			return nil, errors.New(errDaemonSetSpread)
+		}
+		return []workload.Object{daemonSet(d)}, nil
+	}
+
+	setReplicas(d, r)
+
 	return []workload.Object{d}, nil
 }
+
+// setNodeSelector constrains the pods of the supplied pod spec to nodes with
+// the supplied label.
+func setNodeSelector(ps *corev1.PodSpec, k, v string) {
+	if ps.NodeSelector == nil {
+		ps.NodeSelector = map[string]string{}
+	}
+	ps.NodeSelector[k] = v
+}
@@ -47,7 +47,16 @@ func dmWithOS(os string) deploymentModifier {
 		if d.Spec.Template.Spec.NodeSelector == nil {
 			d.Spec.Template.Spec.NodeSelector = map[string]string{}
 		}
-		d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = os
+		d.Spec.Template.Spec.NodeSelector["kubernetes.io/os"] = os
+	}
+}
+
+func dmWithArch(arch string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Spec.NodeSelector == nil {
+			d.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		d.Spec.Template.Spec.NodeSelector["kubernetes.io/arch"] = arch
 	}
 }
 
@@ -98,12 +107,25 @@ func cwWithOS(os string) cwModifier {
 	}
 }
 
+func cwWithArch(arch string) cwModifier {
+	return func(cw *oamv1alpha2.ContainerizedWorkload) {
+		oamArch := oamv1alpha2.CPUArchitecture(arch)
+		cw.Spec.CPUArchitecture = &oamArch
+	}
+}
+
 func cwWithContainer(c oamv1alpha2.Container) cwModifier {
 	return func(cw *oamv1alpha2.ContainerizedWorkload) {
 		cw.Spec.Containers = append(cw.Spec.Containers, c)
 	}
 }
 
+func cwWithAnnotations(a map[string]string) cwModifier {
+	return func(cw *oamv1alpha2.ContainerizedWorkload) {
+		cw.SetAnnotations(a)
+	}
+}
+
 func containerizedWorkload(mod ...cwModifier) *oamv1alpha2.ContainerizedWorkload {
 	cw := &oamv1alpha2.ContainerizedWorkload{
 		ObjectMeta: metav1.ObjectMeta{
@@ -156,6 +178,13 @@ func TestContainerizedWorkloadTranslator(t *testing.T) {
 			},
 			want: want{result: []workload.Object{deployment(dmWithOS("test"))}},
 		},
+		"SuccessfulOSAndArch": {
+			reason: "A ContainerizedWorkload's OS and CPU architecture should be translated into node selectors.",
+			args: args{
+				w: containerizedWorkload(cwWithOS("linux"), cwWithArch("arm64")),
+			},
+			want: want{result: []workload.Object{deployment(dmWithOS("linux"), dmWithArch("arm64"))}},
+		},
 		"SuccessfulContainers": {
 			reason: "A ContainerizedWorkload should be successfully translated into a deployment.",
 			args: args{
@@ -209,6 +238,64 @@ func TestContainerizedWorkloadTranslator(t *testing.T) {
 				},
 			}))}},
 		},
+		"CommandOnly": {
+			reason: "A container's command should not be translated into its args.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:    "cool-container",
+					Image:   "cool/image:latest",
+					Command: []string{"run", "--coolflag"},
+				})),
+			},
+			want: want{result: []workload.Object{deployment(dmWithContainer(corev1.Container{
+				Name:    "cool-container",
+				Image:   "cool/image:latest",
+				Command: []string{"run", "--coolflag"},
+			}))}},
+		},
+		"ArgumentsOnly": {
+			reason: "A container's arguments should be passed to its image's entrypoint, not replace it.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:      "cool-container",
+					Image:     "cool/image:latest",
+					Arguments: []string{"--coolflag"},
+				})),
+			},
+			want: want{result: []workload.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Args:  []string{"--coolflag"},
+			}))}},
+		},
+		"DaemonSet": {
+			reason: "A ContainerizedWorkload in DaemonSet mode should be translated into a DaemonSet.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotations(map[string]string{AnnotationMode: ModeDaemonSet})),
+			},
+			want: want{result: []workload.Object{daemonSet(deployment())}},
+		},
+		"DaemonSetReplicas": {
+			reason: "A ContainerizedWorkload in DaemonSet mode should not specify a replica count.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotations(map[string]string{AnnotationMode: ModeDaemonSet, AnnotationReplicas: "3"})),
+			},
+			want: want{err: errors.New(errDaemonSetReplicas)},
+		},
+		"DaemonSetSpread": {
+			reason: "A ContainerizedWorkload in DaemonSet mode should not spread its pods.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotations(map[string]string{AnnotationMode: ModeDaemonSet, AnnotationAntiAffinity: AntiAffinitySoft})),
+			},
+			want: want{err: errors.New(errDaemonSetSpread)},
+		},
+		"UnknownMode": {
+			reason: "A ContainerizedWorkload in an unknown mode should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotations(map[string]string{AnnotationMode: "StatefulSet"})),
+			},
+			want: want{err: errors.Errorf("%s: %s", errUnknownMode, "StatefulSet")},
+		},
 	}
 
 	for name, tc := range cases {
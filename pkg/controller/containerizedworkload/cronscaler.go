@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/cron"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotCronScalerTrait = "trait is not a cron scaler trait"
+	errParseSchedule      = "cannot parse replica schedule"
+)
+
+// SetupCronScalerTrait adds a controller that reconciles CronScalerTraits that
+// reference a ContainerizedWorkload.
+func SetupCronScalerTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.CronScalerTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.CronScalerTrait{}, remotev1alpha1.CronScalerTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.CronScalerTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.CronScalerTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithScheduler(cronScalerSchedule),
+			trait.WithModifier(newCronScalerModifier(time.Now)),
+		))))
+}
+
+// newCronScalerModifier returns a modifier that scales the packaged Deployment
+// of a workload per the schedule that fired most recently, and restores its
+// replica count when the trait is deleted.
+func newCronScalerModifier(now func() time.Time) trait.Modifier {
+	return trait.NewRevertibleModifier(
+		trait.NewWorkloadModifierWithAccessor(cronScalerModifier(now), trait.DeploymentFromKubeAppAccessor),
+		trait.NewWorkloadModifierWithAccessor(cronScalerReverter, trait.DeploymentFromKubeAppAccessor),
+	)
+}
+
+func cronScalerModifier(now func() time.Time) trait.ModifyFn {
+	return func(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+		d, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return errors.New(errNotDeployment)
+		}
+
+		cs, ok := t.(*remotev1alpha1.CronScalerTrait)
+		if !ok {
+			return errors.New(errNotCronScalerTrait)
+		}
+
+		r, _, err := scheduledReplicas(cs.Spec.Schedules, now())
+		if err != nil {
+			return err
+		}
+
+		// The Deployment of a suspended workload stays scaled to zero until
+		// the workload is resumed. Its replicas are left as they are until
+		// one of the schedules has fired.
+		if workload.Suspended(d) || r == nil {
+			return nil
+		}
+		d.Spec.Replicas = r
+
+		return nil
+	}
+}
+
+// cronScalerReverter restores the replica count of the packaged Deployment to
+// that of the workload's translation.
+func cronScalerReverter(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New(errNotDeployment)
+	}
+
+	if _, ok := t.(*remotev1alpha1.CronScalerTrait); !ok {
+		return errors.New(errNotCronScalerTrait)
+	}
+
+	if workload.Suspended(d) {
+		return nil
+	}
+	r, err := replicas(d)
+	if err != nil {
+		return err
+	}
+	d.Spec.Replicas = r
+
+	return nil
+}
+
+// cronScalerSchedule records the replicas a CronScalerTrait currently scales
+// its workload to, and the time at which one of its schedules fires next, in
+// its status. It returns that time so that the trait is reconciled then.
+func cronScalerSchedule(t trait.Trait, now time.Time) time.Time {
+	cs, ok := t.(*remotev1alpha1.CronScalerTrait)
+	if !ok {
+		return time.Time{}
+	}
+
+	// Schedules that cannot be parsed cause the trait's reconcile to fail
+	// before it is scheduled.
+	r, next, err := scheduledReplicas(cs.Spec.Schedules, now)
+	if err != nil {
+		return time.Time{}
+	}
+
+	cs.Status.Replicas = r
+	cs.Status.NextScheduleTime = nil
+	if !next.IsZero() {
+		cs.Status.NextScheduleTime = &metav1.Time{Time: next}
+	}
+	return next
+}
+
+// scheduledReplicas returns the replicas of the schedule that fired most
+// recently at the supplied time, if any, and the next time at which any of the
+// schedules fires. Schedules are evaluated in UTC. The first schedule wins if
+// several fired at the same time.
+func scheduledReplicas(schedules []remotev1alpha1.ReplicaSchedule, now time.Time) (*int32, time.Time, error) {
+	now = now.UTC()
+
+	var replicas *int32
+	var prev, next time.Time
+	for i := range schedules {
+		s, err := cron.Parse(schedules[i].Schedule)
+		if err != nil {
+			return nil, time.Time{}, errors.Wrapf(err, "%s %q", errParseSchedule, schedules[i].Schedule)
+		}
+
+		if p := s.Prev(now); !p.IsZero() && p.After(prev) {
+			r := schedules[i].Replicas
+			prev, replicas = p, &r
+		}
+		if n := s.Next(now); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return replicas, next, nil
+}
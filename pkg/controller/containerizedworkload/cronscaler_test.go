@@ -0,0 +1,196 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+// Friday the 20th of March 2020, at 09:30 UTC.
+var cronNow = time.Date(2020, 3, 20, 9, 30, 0, 0, time.UTC)
+
+var workdays = []remotev1alpha1.ReplicaSchedule{
+	{Schedule: "0 8 * * 1-5", Replicas: 10},
+	{Schedule: "0 20 * * 1-5", Replicas: 2},
+}
+
+func TestCronScalerModifier(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	scaled := int32(10)
+	suspended := int32(0)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to modifier that is not a Deployment should return error.",
+			args: args{
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotCronScaler": {
+			reason: "Trait passed to modifier that is not a CronScalerTrait should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotCronScalerTrait)},
+		},
+		"ErrorInvalidSchedule": {
+			reason: "A schedule that cannot be parsed should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &remotev1alpha1.CronScalerTrait{
+					Spec: remotev1alpha1.CronScalerTraitSpec{
+						Schedules: []remotev1alpha1.ReplicaSchedule{{Schedule: "never", Replicas: 1}},
+					},
+				},
+			},
+			want: want{
+				o:   &appsv1.Deployment{},
+				err: errors.Wrapf(errors.New("schedule must have five space separated fields: minute, hour, day of month, month, and day of week"), "%s %q", errParseSchedule, "never"),
+			},
+		},
+		"Suspended": {
+			reason: "The Deployment of a suspended workload should not be scaled.",
+			args: args{
+				o: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+					Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+				},
+				t: &remotev1alpha1.CronScalerTrait{Spec: remotev1alpha1.CronScalerTraitSpec{Schedules: workdays}},
+			},
+			want: want{o: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+				Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+			}},
+		},
+		"NotYetFired": {
+			reason: "A Deployment should not be scaled if none of the schedules have fired.",
+			args: args{
+				o: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &startingReplicas}},
+				t: &remotev1alpha1.CronScalerTrait{Spec: remotev1alpha1.CronScalerTraitSpec{
+					Schedules: []remotev1alpha1.ReplicaSchedule{{Schedule: "0 0 31 2 *", Replicas: 10}},
+				}},
+			},
+			want: want{o: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &startingReplicas}}},
+		},
+		"Success": {
+			reason: "A Deployment should be scaled to the replicas of the schedule that fired most recently.",
+			args: args{
+				o: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &startingReplicas}},
+				t: &remotev1alpha1.CronScalerTrait{Spec: remotev1alpha1.CronScalerTraitSpec{Schedules: workdays}},
+			},
+			want: want{o: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &scaled}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := cronScalerModifier(func() time.Time { return cronNow })(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ncronScalerModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\ncronScalerModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCronScalerSchedule(t *testing.T) {
+	type want struct {
+		next   time.Time
+		status remotev1alpha1.CronScalerTraitStatus
+	}
+
+	replicas := int32(10)
+	next := time.Date(2020, 3, 20, 20, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		t      trait.Trait
+		want   want
+	}{
+		"NotCronScaler": {
+			reason: "Traits that are not CronScalerTraits should not be scheduled.",
+			t:      &traitfake.Trait{},
+		},
+		"InvalidSchedule": {
+			reason: "Traits whose schedules cannot be parsed should not be scheduled.",
+			t: &remotev1alpha1.CronScalerTrait{Spec: remotev1alpha1.CronScalerTraitSpec{
+				Schedules: []remotev1alpha1.ReplicaSchedule{{Schedule: "never"}},
+			}},
+		},
+		"Scheduled": {
+			reason: "The current replicas and the time the next schedule fires should be recorded and returned.",
+			t:      &remotev1alpha1.CronScalerTrait{Spec: remotev1alpha1.CronScalerTraitSpec{Schedules: workdays}},
+			want: want{
+				next: next,
+				status: remotev1alpha1.CronScalerTraitStatus{
+					Replicas:         &replicas,
+					NextScheduleTime: &metav1.Time{Time: next},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cronScalerSchedule(tc.t, cronNow)
+			if diff := cmp.Diff(tc.want.next, got); diff != "" {
+				t.Errorf("\nReason: %s\ncronScalerSchedule(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			cs, ok := tc.t.(*remotev1alpha1.CronScalerTrait)
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tc.want.status, cs.Status); diff != "" {
+				t.Errorf("\nReason: %s\ncronScalerSchedule(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
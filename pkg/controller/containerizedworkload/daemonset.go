@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errUnknownMode       = "unknown workload mode"
+	errParseTolerations  = "cannot parse tolerations"
+	errParseHostNetwork  = "cannot parse host network"
+	errDaemonSetReplicas = "a ContainerizedWorkload in DaemonSet mode runs one pod per node, and must not specify a replica count"
+)
+
+// AnnotationMode may be set on a ContainerizedWorkload to choose how its pods
+// are run. Workloads in Deployment mode, the default, are translated to a
+// Deployment. Workloads in DaemonSet mode are translated to a DaemonSet that
+// runs one pod on each node of the remote cluster, which suits per-node
+// agents such as log shippers and monitoring exporters.
+const AnnotationMode = "containerizedworkload.oam.crossplane.io/mode"
+
+// Modes of a ContainerizedWorkload.
+const (
+	ModeDeployment = "Deployment"
+	ModeDaemonSet  = "DaemonSet"
+)
+
+// AnnotationTolerations may be set on a ContainerizedWorkload to allow its
+// pods to be scheduled to tainted nodes, for example so that a per-node agent
+// also runs on control plane nodes. Its value is a JSON array of Kubernetes
+// Tolerations.
+const AnnotationTolerations = "containerizedworkload.oam.crossplane.io/tolerations"
+
+// AnnotationHostNetwork may be set to "true" on a ContainerizedWorkload to run
+// its pods in the network namespace of their node. Pods that use the host
+// network resolve cluster DNS names unless a DNS policy is specified.
+const AnnotationHostNetwork = "containerizedworkload.oam.crossplane.io/host-network"
+
+var (
+	daemonSetKind       = reflect.TypeOf(appsv1.DaemonSet{}).Name()
+	daemonSetAPIVersion = appsv1.SchemeGroupVersion.String()
+)
+
+// mode returns the mode of the supplied workload.
+func mode(o metav1.Object) (string, error) {
+	switch m := o.GetAnnotations()[AnnotationMode]; m {
+	case "", ModeDeployment:
+		return ModeDeployment, nil
+	case ModeDaemonSet:
+		return ModeDaemonSet, nil
+	default:
+		return "", errors.Errorf("%s: %s", errUnknownMode, m)
+	}
+}
+
+// A podScheduling is the node scheduling settings of the pods of a workload.
+type podScheduling struct {
+	tolerations []corev1.Toleration
+	hostNetwork bool
+}
+
+// scheduling returns the node scheduling settings of the pods of the supplied
+// workload.
+func scheduling(o metav1.Object) (podScheduling, error) {
+	a := o.GetAnnotations()
+	s := podScheduling{}
+
+	if raw, ok := a[AnnotationTolerations]; ok {
+		if err := json.Unmarshal([]byte(raw), &s.tolerations); err != nil {
+			return podScheduling{}, errors.Wrap(err, errParseTolerations)
+		}
+	}
+
+	switch a[AnnotationHostNetwork] {
+	case "", "false":
+	case "true":
+		s.hostNetwork = true
+	default:
+		return podScheduling{}, errors.Errorf("%s: %q is not true or false", errParseHostNetwork, a[AnnotationHostNetwork])
+	}
+
+	return s, nil
+}
+
+// setScheduling sets the node scheduling settings of the supplied pod spec.
+// It must be called after setDNS, so that pods that use the host network
+// keep resolving cluster DNS names unless they specify a DNS policy.
+func setScheduling(ps *corev1.PodSpec, s podScheduling) {
+	ps.Tolerations = s.tolerations
+	ps.HostNetwork = s.hostNetwork
+	if s.hostNetwork && ps.DNSPolicy == "" {
+		ps.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+}
+
+// daemonSet returns a DaemonSet that runs the pods of the supplied Deployment
+// on each node.
+func daemonSet(d *appsv1.Deployment) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       daemonSetKind,
+			APIVersion: daemonSetAPIVersion,
+		},
+		ObjectMeta: d.ObjectMeta,
+		Spec: appsv1.DaemonSetSpec{
+			Selector: d.Spec.Selector,
+			Template: d.Spec.Template,
+		},
+	}
+}
+
+// podTemplate returns the pod template of the supplied Deployment or DaemonSet.
+func podTemplate(o workload.Object) (*corev1.PodTemplateSpec, bool) {
+	switch d := o.(type) {
+	case *appsv1.Deployment:
+		return &d.Spec.Template, true
+	case *appsv1.DaemonSet:
+		return &d.Spec.Template, true
+	default:
+		return nil, false
+	}
+}
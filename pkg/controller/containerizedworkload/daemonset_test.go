@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestScheduling(t *testing.T) {
+	type want struct {
+		s   podScheduling
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotations": {
+			reason: "A workload without the annotations should tolerate no taints and use the pod network.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseTolerationsError": {
+			reason: "A tolerations annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationTolerations: "["}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseTolerations)},
+		},
+		"ParseHostNetworkError": {
+			reason: "A host network annotation that is not a boolean should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationHostNetwork: "yes"}},
+			want:   want{err: errors.Errorf("%s: %q is not true or false", errParseHostNetwork, "yes")},
+		},
+		"Success": {
+			reason: "Tolerations and host network should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationTolerations: `[{"key":"node-role.kubernetes.io/master","operator":"Exists","effect":"NoSchedule"}]`,
+				AnnotationHostNetwork: "true",
+			}},
+			want: want{s: podScheduling{
+				tolerations: []corev1.Toleration{{
+					Key:      "node-role.kubernetes.io/master",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}},
+				hostNetwork: true,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := scheduling(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nscheduling(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.s, got, cmp.AllowUnexported(podScheduling{})); diff != "" {
+				t.Errorf("\nReason: %s\nscheduling(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetScheduling(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ps     *corev1.PodSpec
+		s      podScheduling
+		want   *corev1.PodSpec
+	}{
+		"HostNetworkDefaultDNS": {
+			reason: "Pods that use the host network should resolve cluster DNS names by default.",
+			ps:     &corev1.PodSpec{},
+			s:      podScheduling{hostNetwork: true},
+			want:   &corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+		},
+		"HostNetworkExplicitDNS": {
+			reason: "Pods that use the host network should keep an explicitly specified DNS policy.",
+			ps:     &corev1.PodSpec{DNSPolicy: corev1.DNSDefault},
+			s:      podScheduling{hostNetwork: true},
+			want:   &corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSDefault},
+		},
+		"PodNetwork": {
+			reason: "Pods that use the pod network should not have a DNS policy set.",
+			ps:     &corev1.PodSpec{},
+			s:      podScheduling{tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+			want:   &corev1.PodSpec{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setScheduling(tc.ps, tc.s)
+
+			if diff := cmp.Diff(tc.want, tc.ps); diff != "" {
+				t.Errorf("\nReason: %s\nsetScheduling(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
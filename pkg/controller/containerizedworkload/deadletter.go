@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/deadletter"
+)
+
+// withDeadLetters wraps the supplied reconciler of the supplied kind so that
+// objects that repeatedly fail to reconcile become dead letters, if enabled.
+func withDeadLetters(mgr ctrl.Manager, o options.Options, name string, of schema.GroupVersionKind, r reconcile.Reconciler) reconcile.Reconciler {
+	if o.DeadLetterLimit <= 0 {
+		return r
+	}
+	return deadletter.NewReconciler(mgr, of, r,
+		deadletter.WithFailureLimit(o.DeadLetterLimit),
+		deadletter.WithLogger(o.Logger.WithValues("controller", name)),
+		deadletter.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+}
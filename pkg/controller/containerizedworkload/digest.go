@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/registry"
+)
+
+const (
+	errReadVerificationKey = "cannot read image verification key"
+	errReadImagePullSecret = "cannot read image pull secret"
+	errParseImage          = "cannot parse image"
+	errResolveImage        = "cannot resolve image"
+)
+
+// NewImageResolver returns a Resolver that resolves images using the Docker
+// Registry HTTP API V2. If the supplied path to a PEM encoded cosign public
+// key is not empty images only resolve if they are signed by that key.
+func NewImageResolver(keyPath string) (registry.Resolver, error) {
+	if keyPath == "" {
+		return registry.NewClient(), nil
+	}
+	b, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadVerificationKey)
+	}
+	k, err := registry.ParsePublicKey(b)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadVerificationKey)
+	}
+	return registry.NewClient(registry.WithVerificationKey(k)), nil
+}
+
+// NewImageDigestPinner returns a TranslationWrapper that replaces the image of
+// each container of the pods of a ContainerizedWorkload's translation with a
+// reference to the digest its tag currently resolves to, so that the remote
+// cluster runs exactly the image that was resolved even if the tag is later
+// moved. Images are resolved using the credentials of the workload's image
+// pull secrets. Images that are already pinned to a digest keep it, though a
+// Resolver that verifies signatures still verifies them.
+func NewImageDigestPinner(c client.Reader, r registry.Resolver) workload.TranslationWrapper {
+	return func(ctx context.Context, w workload.Workload, objs []workload.Object) ([]workload.Object, error) {
+		cw, ok := w.(*oamv1alpha2.ContainerizedWorkload)
+		if !ok {
+			return objs, nil
+		}
+
+		k, err := keychain(ctx, c, cw)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each image is resolved once, however many containers run it.
+		pinned := map[string]string{}
+		pin := func(image string) (string, error) {
+			if p, ok := pinned[image]; ok {
+				return p, nil
+			}
+			ref, err := registry.ParseReference(image)
+			if err != nil {
+				return "", errors.Wrap(err, errParseImage)
+			}
+			d, err := r.Resolve(ctx, ref, k)
+			if err != nil {
+				return "", errors.Wrapf(err, "%s %s", errResolveImage, image)
+			}
+			pinned[image] = ref.Pinned(d)
+			return pinned[image], nil
+		}
+
+		for _, o := range objs {
+			pt, ok := podTemplate(o)
+			if !ok {
+				continue
+			}
+			for _, containers := range [][]corev1.Container{pt.Spec.InitContainers, pt.Spec.Containers} {
+				for i := range containers {
+					p, err := pin(containers[i].Image)
+					if err != nil {
+						return nil, err
+					}
+					containers[i].Image = p
+				}
+			}
+		}
+		return objs, nil
+	}
+}
+
+// keychain returns the registry credentials found in the image pull secrets
+// of the supplied workload.
+func keychain(ctx context.Context, c client.Reader, cw *oamv1alpha2.ContainerizedWorkload) (registry.Keychain, error) {
+	k := registry.Keychain{}
+	for _, n := range imagePullSecrets(cw) {
+		s := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: cw.GetNamespace(), Name: n}, s); err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errGetImagePullSecret, n)
+		}
+		b, ok := s.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+		if err := k.AddDockerConfigJSON(b); err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errReadImagePullSecret, n)
+		}
+	}
+	return k, nil
+}
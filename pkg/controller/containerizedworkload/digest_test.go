@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/registry"
+)
+
+func TestImageDigestPinner(t *testing.T) {
+	errBoom := errors.New("boom")
+	creds := "creds"
+	digest := "sha256:cool"
+	config := []byte(`{"auths":{"registry.example.com":{"username":"cool-user","password":"cool-password"}}}`)
+
+	cw := func() *oamv1alpha2.ContainerizedWorkload {
+		return &oamv1alpha2.ContainerizedWorkload{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "coolns"},
+			Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{ImagePullSecret: &creds}},
+			},
+		}
+	}
+	deployment := func(init string, images ...string) *appsv1.Deployment {
+		d := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion}}
+		if init != "" {
+			d.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "init", Image: init}}
+		}
+		for _, i := range images {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "c", Image: i})
+		}
+		return d
+	}
+	daemonSet := func(image string) *appsv1.DaemonSet {
+		d := &appsv1.DaemonSet{TypeMeta: metav1.TypeMeta{Kind: daemonSetKind, APIVersion: daemonSetAPIVersion}}
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "c", Image: image}}
+		return d
+	}
+	secret := func(data map[string][]byte) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.Secret).Data = data
+			return nil
+		})
+	}
+
+	type args struct {
+		c    *test.MockClient
+		r    registry.Resolver
+		w    workload.Workload
+		objs []workload.Object
+	}
+	type want struct {
+		objs []workload.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotContainerizedWorkload": {
+			reason: "Images of workloads that are not ContainerizedWorkloads should not be resolved.",
+			args: args{
+				w:    &workloadfake.Workload{},
+				objs: []workload.Object{deployment("", "nginx")},
+			},
+			want: want{objs: []workload.Object{deployment("", "nginx")}},
+		},
+		"GetSecretError": {
+			reason: "Errors getting an image pull secret should be returned.",
+			args: args{
+				c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				w:    cw(),
+				objs: []workload.Object{deployment("", "nginx")},
+			},
+			want: want{err: errors.Wrapf(errBoom, "%s %s", errGetImagePullSecret, creds)},
+		},
+		"ResolveError": {
+			reason: "Errors resolving an image should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: secret(nil)},
+				r: registry.ResolverFn(func(_ context.Context, _ registry.Reference, _ registry.Keychain) (string, error) {
+					return "", errBoom
+				}),
+				w:    cw(),
+				objs: []workload.Object{deployment("", "nginx")},
+			},
+			want: want{err: errors.Wrapf(errBoom, "%s %s", errResolveImage, "nginx")},
+		},
+		"Pinned": {
+			reason: "The image of each container and init container of each pod template should be pinned to its digest, resolved using the workload's image pull secrets.",
+			args: args{
+				c: &test.MockClient{MockGet: secret(map[string][]byte{corev1.DockerConfigJsonKey: config})},
+				r: registry.ResolverFn(func(_ context.Context, ref registry.Reference, k registry.Keychain) (string, error) {
+					want := registry.Keychain{"registry.example.com": {Username: "cool-user", Password: "cool-password"}}
+					if diff := cmp.Diff(want, k); diff != "" {
+						return "", errors.Errorf("keychain: -want, +got:\n%s", diff)
+					}
+					if ref.Digest != "" {
+						return ref.Digest, nil
+					}
+					return digest, nil
+				}),
+				w: cw(),
+				objs: []workload.Object{
+					deployment("busybox", "registry.example.com/team/app:v1", "nginx@sha256:pinned"),
+					daemonSet("nginx:1.17"),
+					&corev1.Service{},
+				},
+			},
+			want: want{objs: []workload.Object{
+				deployment("busybox@"+digest, "registry.example.com/team/app@"+digest, "nginx@sha256:pinned"),
+				daemonSet("nginx@" + digest),
+				&corev1.Service{},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewImageDigestPinner(tc.args.c, tc.args.r)(context.Background(), tc.args.w, tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewImageDigestPinner(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nNewImageDigestPinner(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errParseHostAliases   = "cannot parse host aliases"
+	errParseDNSConfig     = "cannot parse DNS config"
+	errUnknownDNSPolicy   = "unknown DNS policy"
+	errDNSConfigNoServers = "DNS policy None requires DNS config with at least one nameserver"
+)
+
+// AnnotationHostAliases may be set on a ContainerizedWorkload to add entries
+// to the hosts files of its pods, which the ContainerizedWorkload schema does
+// not support. Its value is a JSON array of Kubernetes HostAliases.
+const AnnotationHostAliases = "containerizedworkload.oam.crossplane.io/host-aliases"
+
+// AnnotationDNSPolicy may be set on a ContainerizedWorkload to specify the DNS
+// policy of its pods, for example ClusterFirst or None.
+const AnnotationDNSPolicy = "containerizedworkload.oam.crossplane.io/dns-policy"
+
+// AnnotationDNSConfig may be set on a ContainerizedWorkload to specify the DNS
+// parameters of its pods. Its value is a JSON Kubernetes PodDNSConfig.
+const AnnotationDNSConfig = "containerizedworkload.oam.crossplane.io/dns-config"
+
+// A podDNS is the DNS settings of the pods of a workload.
+type podDNS struct {
+	hostAliases []corev1.HostAlias
+	policy      corev1.DNSPolicy
+	config      *corev1.PodDNSConfig
+}
+
+// dns returns the DNS settings of the pods of the supplied workload.
+func dns(o metav1.Object) (podDNS, error) {
+	a := o.GetAnnotations()
+	d := podDNS{policy: corev1.DNSPolicy(a[AnnotationDNSPolicy])}
+
+	if raw, ok := a[AnnotationHostAliases]; ok {
+		if err := json.Unmarshal([]byte(raw), &d.hostAliases); err != nil {
+			return podDNS{}, errors.Wrap(err, errParseHostAliases)
+		}
+	}
+
+	if raw, ok := a[AnnotationDNSConfig]; ok {
+		d.config = &corev1.PodDNSConfig{}
+		if err := json.Unmarshal([]byte(raw), d.config); err != nil {
+			return podDNS{}, errors.Wrap(err, errParseDNSConfig)
+		}
+	}
+
+	switch d.policy {
+	case "", corev1.DNSClusterFirstWithHostNet, corev1.DNSClusterFirst, corev1.DNSDefault:
+	case corev1.DNSNone:
+		// Pods that ignore the cluster's DNS settings would otherwise be
+		// rejected by the remote cluster, long after they were translated.
+		if d.config == nil || len(d.config.Nameservers) == 0 {
+			return podDNS{}, errors.New(errDNSConfigNoServers)
+		}
+	default:
+		return podDNS{}, errors.Errorf("%s: %s", errUnknownDNSPolicy, d.policy)
+	}
+
+	return d, nil
+}
+
+// setDNS sets the DNS settings of the supplied pod spec.
+func setDNS(ps *corev1.PodSpec, d podDNS) {
+	ps.HostAliases = d.hostAliases
+	ps.DNSPolicy = d.policy
+	ps.DNSConfig = d.config
+}
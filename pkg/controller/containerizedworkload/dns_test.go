@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDNS(t *testing.T) {
+	type want struct {
+		d   podDNS
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotations": {
+			reason: "A workload without the annotations should use the remote cluster's default DNS settings.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseHostAliasesError": {
+			reason: "A host aliases annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationHostAliases: "["}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseHostAliases)},
+		},
+		"ParseDNSConfigError": {
+			reason: "A DNS config annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationDNSConfig: "{"}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseDNSConfig)},
+		},
+		"UnknownDNSPolicy": {
+			reason: "A DNS policy that Kubernetes does not support should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationDNSPolicy: "Sometimes"}},
+			want:   want{err: errors.Errorf("%s: %s", errUnknownDNSPolicy, "Sometimes")},
+		},
+		"NoneWithoutNameservers": {
+			reason: "The None DNS policy should require at least one nameserver.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationDNSPolicy: "None"}},
+			want:   want{err: errors.New(errDNSConfigNoServers)},
+		},
+		"Success": {
+			reason: "Host aliases, DNS policy, and DNS config should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationHostAliases: `[{"ip":"10.0.0.1","hostnames":["legacy.example.org"]}]`,
+				AnnotationDNSPolicy:   "None",
+				AnnotationDNSConfig:   `{"nameservers":["10.0.0.53"],"searches":["example.org"]}`,
+			}},
+			want: want{d: podDNS{
+				hostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"legacy.example.org"}}},
+				policy:      corev1.DNSNone,
+				config:      &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.53"}, Searches: []string{"example.org"}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := dns(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ndns(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.d, got, cmp.AllowUnexported(podDNS{})); diff != "" {
+				t.Errorf("\nReason: %s\ndns(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
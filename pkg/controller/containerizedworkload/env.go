@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errParseEnvSources   = "cannot parse environment variable sources"
+	errInvalidEnvSource  = "environment variable must have exactly one of fieldRef, resourceFieldRef, secretKeyRef, or configMapKeyRef"
+	errNoContainerForEnv = "no container found for environment variables"
+)
+
+// AnnotationEnvSources may be set on a ContainerizedWorkload to specify
+// environment variables whose values are sourced from the downward API or
+// from Secrets and ConfigMaps, which the ContainerizedWorkload schema does not
+// support. Its value is a JSON object mapping container names to arrays of
+// Kubernetes EnvVars. Referenced Secrets and ConfigMaps must exist in the
+// remote namespace the workload is scheduled to.
+const AnnotationEnvSources = "containerizedworkload.oam.crossplane.io/env-sources"
+
+// envSources returns the sourced environment variables of each container of
+// the supplied workload.
+func envSources(o metav1.Object) (map[string][]corev1.EnvVar, error) {
+	raw, ok := o.GetAnnotations()[AnnotationEnvSources]
+	if !ok {
+		return nil, nil
+	}
+
+	src := map[string][]corev1.EnvVar{}
+	if err := json.Unmarshal([]byte(raw), &src); err != nil {
+		return nil, errors.Wrap(err, errParseEnvSources)
+	}
+
+	for _, env := range src {
+		for _, e := range env {
+			if !validEnvSource(e) {
+				return nil, errors.Errorf("%s: %s", errInvalidEnvSource, e.Name)
+			}
+		}
+	}
+	return src, nil
+}
+
+func validEnvSource(e corev1.EnvVar) bool {
+	if e.Value != "" || e.ValueFrom == nil {
+		return false
+	}
+	n := 0
+	for _, set := range []bool{
+		e.ValueFrom.FieldRef != nil,
+		e.ValueFrom.ResourceFieldRef != nil,
+		e.ValueFrom.SecretKeyRef != nil,
+		e.ValueFrom.ConfigMapKeyRef != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n == 1
+}
+
+// setEnvSources adds the supplied sourced environment variables to the
+// containers of the supplied pod spec, replacing any literal variables of the
+// same name.
+func setEnvSources(ps *corev1.PodSpec, src map[string][]corev1.EnvVar) error {
+	for name, env := range src {
+		found := false
+		for i := range ps.Containers {
+			c := &ps.Containers[i]
+			if c.Name != name {
+				continue
+			}
+			for _, e := range env {
+				setEnv(c, e)
+			}
+			found = true
+		}
+		if !found {
+			return errors.Errorf("%s: %s", errNoContainerForEnv, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestEnvSources(t *testing.T) {
+	type want struct {
+		src map[string][]corev1.EnvVar
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "A workload without the annotation should have no sourced environment variables.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseError": {
+			reason: "An annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationEnvSources: "{"}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseEnvSources)},
+		},
+		"LiteralValue": {
+			reason: "A literal environment variable should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationEnvSources: `{"cool":[{"name":"COOL","value":"literal"}]}`}},
+			want:   want{err: errors.Errorf("%s: %s", errInvalidEnvSource, "COOL")},
+		},
+		"Success": {
+			reason: "Environment variables sourced from Secrets, ConfigMaps, and the downward API should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{AnnotationEnvSources: `{"cool":[
+				{"name":"PASSWORD","valueFrom":{"secretKeyRef":{"name":"db","key":"password"}}},
+				{"name":"MODE","valueFrom":{"configMapKeyRef":{"name":"cfg","key":"mode"}}},
+				{"name":"POD","valueFrom":{"fieldRef":{"fieldPath":"metadata.name"}}}
+			]}`}},
+			want: want{src: map[string][]corev1.EnvVar{"cool": {
+				{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password",
+				}}},
+				{Name: "MODE", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cfg"}, Key: "mode",
+				}}},
+				{Name: "POD", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := envSources(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nenvSources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.src, got); diff != "" {
+				t.Errorf("\nReason: %s\nenvSources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetEnvSources(t *testing.T) {
+	type args struct {
+		ps  *corev1.PodSpec
+		src map[string][]corev1.EnvVar
+	}
+
+	type want struct {
+		ps  *corev1.PodSpec
+		err error
+	}
+
+	pod := corev1.EnvVar{Name: "POD", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoContainer": {
+			reason: "Environment variables for a container that does not exist should return an error.",
+			args: args{
+				ps:  &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				src: map[string][]corev1.EnvVar{"cool": {pod}},
+			},
+			want: want{
+				ps:  &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				err: errors.Errorf("%s: %s", errNoContainerForEnv, "cool"),
+			},
+		},
+		"ReplaceLiteral": {
+			reason: "A sourced environment variable should replace a literal variable of the same name.",
+			args: args{
+				ps:  &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Env: []corev1.EnvVar{{Name: "POD", Value: "literal"}}}}},
+				src: map[string][]corev1.EnvVar{"cool": {pod}},
+			},
+			want: want{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Env: []corev1.EnvVar{pod}}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setEnvSources(tc.args.ps, tc.args.src)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsetEnvSources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.ps, tc.args.ps); diff != "" {
+				t.Errorf("\nReason: %s\nsetEnvSources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
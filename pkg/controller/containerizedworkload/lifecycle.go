@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errParseLifecycle          = "cannot parse container lifecycle hooks"
+	errNoContainerForLifecycle = "no container found for lifecycle hooks"
+	errParseGracePeriod        = "cannot parse termination grace period"
+	errNegativeGracePeriod     = "termination grace period must not be negative"
+)
+
+// AnnotationLifecycle may be set on a ContainerizedWorkload to specify the
+// postStart and preStop hooks of its containers, which the
+// ContainerizedWorkload schema does not support. Its value is a JSON object
+// mapping container names to Kubernetes Lifecycles.
+const AnnotationLifecycle = "containerizedworkload.oam.crossplane.io/lifecycle"
+
+// AnnotationTerminationGracePeriod may be set on a ContainerizedWorkload to
+// specify the number of seconds its pods are given to shut down gracefully
+// before they are killed.
+const AnnotationTerminationGracePeriod = "containerizedworkload.oam.crossplane.io/termination-grace-period-seconds"
+
+// lifecycles returns the lifecycle hooks of each container of the supplied
+// workload.
+func lifecycles(o metav1.Object) (map[string]*corev1.Lifecycle, error) {
+	raw, ok := o.GetAnnotations()[AnnotationLifecycle]
+	if !ok {
+		return nil, nil
+	}
+
+	l := map[string]*corev1.Lifecycle{}
+	if err := json.Unmarshal([]byte(raw), &l); err != nil {
+		return nil, errors.Wrap(err, errParseLifecycle)
+	}
+	return l, nil
+}
+
+// terminationGracePeriod returns the termination grace period of the pods of
+// the supplied workload, or nil if it does not specify one.
+func terminationGracePeriod(o metav1.Object) (*int64, error) {
+	raw, ok := o.GetAnnotations()[AnnotationTerminationGracePeriod]
+	if !ok {
+		return nil, nil
+	}
+
+	s, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseGracePeriod)
+	}
+	if s < 0 {
+		return nil, errors.New(errNegativeGracePeriod)
+	}
+	return &s, nil
+}
+
+// setLifecycles sets the lifecycle hooks of the containers of the supplied pod
+// spec.
+func setLifecycles(ps *corev1.PodSpec, l map[string]*corev1.Lifecycle) error {
+	for name, lc := range l {
+		found := false
+		for i := range ps.Containers {
+			if ps.Containers[i].Name != name {
+				continue
+			}
+			ps.Containers[i].Lifecycle = lc
+			found = true
+		}
+		if !found {
+			return errors.Errorf("%s: %s", errNoContainerForLifecycle, name)
+		}
+	}
+	return nil
+}
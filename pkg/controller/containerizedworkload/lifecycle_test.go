@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestLifecycles(t *testing.T) {
+	type want struct {
+		l   map[string]*corev1.Lifecycle
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "A workload without the annotation should have no lifecycle hooks.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseError": {
+			reason: "An annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationLifecycle: "{"}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseLifecycle)},
+		},
+		"Success": {
+			reason: "The postStart and preStop hooks of each container should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{AnnotationLifecycle: `{"cool":{
+				"postStart":{"exec":{"command":["warm"]}},
+				"preStop":{"httpGet":{"path":"/drain","port":8080}}
+			}}`}},
+			want: want{l: map[string]*corev1.Lifecycle{"cool": {
+				PostStart: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"warm"}}},
+				PreStop:   &corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/drain", Port: intstr.FromInt(8080)}},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := lifecycles(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nlifecycles(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.l, got); diff != "" {
+				t.Errorf("\nReason: %s\nlifecycles(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerminationGracePeriod(t *testing.T) {
+	thirty := int64(30)
+
+	type want struct {
+		s   *int64
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "A workload without the annotation should not specify a termination grace period.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseError": {
+			reason: "An annotation that is not an integer should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationTerminationGracePeriod: "30s"}},
+			want:   want{err: errors.Wrap(&strconv.NumError{Func: "ParseInt", Num: "30s", Err: strconv.ErrSyntax}, errParseGracePeriod)},
+		},
+		"Negative": {
+			reason: "A negative termination grace period should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationTerminationGracePeriod: "-1"}},
+			want:   want{err: errors.New(errNegativeGracePeriod)},
+		},
+		"Success": {
+			reason: "The termination grace period should be returned.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationTerminationGracePeriod: "30"}},
+			want:   want{s: &thirty},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := terminationGracePeriod(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nterminationGracePeriod(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\nReason: %s\nterminationGracePeriod(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetLifecycles(t *testing.T) {
+	type args struct {
+		ps *corev1.PodSpec
+		l  map[string]*corev1.Lifecycle
+	}
+
+	type want struct {
+		ps  *corev1.PodSpec
+		err error
+	}
+
+	lc := &corev1.Lifecycle{PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"drain"}}}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoContainer": {
+			reason: "Lifecycle hooks for a container that does not exist should return an error.",
+			args: args{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				l:  map[string]*corev1.Lifecycle{"cool": lc},
+			},
+			want: want{
+				ps:  &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				err: errors.Errorf("%s: %s", errNoContainerForLifecycle, "cool"),
+			},
+		},
+		"Success": {
+			reason: "Lifecycle hooks should be set on the named container.",
+			args: args{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool"}, {Name: "other"}}},
+				l:  map[string]*corev1.Lifecycle{"cool": lc},
+			},
+			want: want{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Lifecycle: lc}, {Name: "other"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setLifecycles(tc.args.ps, tc.args.l)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsetLifecycles(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.ps, tc.args.ps); diff != "" {
+				t.Errorf("\nReason: %s\nsetLifecycles(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
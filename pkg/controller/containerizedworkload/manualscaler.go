@@ -18,21 +18,55 @@ package containerizedworkload
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
 	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
 
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
 	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
+// TypeScaled indicates whether the observed ready replicas of a
+// ManualScalerTrait's workload match its replica count.
+const TypeScaled runtimev1alpha1.ConditionType = "Scaled"
+
+// Reasons a ManualScalerTrait's workload is or is not scaled.
+const (
+	ReasonReplicasReady    runtimev1alpha1.ConditionReason = "ReplicasReady"
+	ReasonReplicasNotReady runtimev1alpha1.ConditionReason = "ReplicasNotReady"
+)
+
+// Scaled returns a condition that indicates whether the supplied number of
+// ready replicas matches the desired number.
+func Scaled(desired, ready int32) runtimev1alpha1.Condition {
+	c := runtimev1alpha1.Condition{
+		Type:               TypeScaled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReplicasReady,
+		Message:            fmt.Sprintf("%d of %d replicas are ready", ready, desired),
+	}
+	if ready != desired {
+		c.Status = corev1.ConditionFalse
+		c.Reason = ReasonReplicasNotReady
+	}
+	return c
+}
+
 const (
 	errNotDeployment        = "object to be modified is not a deployment"
 	errNotManualScalerTrait = "trait is not a manual scaler"
@@ -40,19 +74,43 @@ const (
 
 // SetupManualScalerTrait adds a controller that reconciles ManualScalers that
 // reference a ContainerizedWorkload.
-func SetupManualScalerTrait(mgr ctrl.Manager, l logging.Logger) error {
+func SetupManualScalerTrait(mgr ctrl.Manager, o options.Options) error {
 	name := "oam/" + strings.ToLower(oamv1alpha2.ManualScalerTraitGroupKind)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b, err := newTraitControllerBuilder(mgr, &oamv1alpha2.ManualScalerTrait{}, oamv1alpha2.ManualScalerTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
 		Named(name).
-		For(&oamv1alpha2.ManualScalerTrait{}).
-		Complete(trait.NewReconciler(mgr,
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, oamv1alpha2.ManualScalerTraitGroupVersionKind, trait.NewReconciler(mgr,
 			trait.Kind(oamv1alpha2.ManualScalerTraitGroupVersionKind),
 			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
-			trait.WithLogger(l.WithValues("controller", name)),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
 			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			trait.WithModifier(trait.NewWorkloadModifierWithAccessor(manualScalerModifier, trait.DeploymentFromKubeAppAccessor)),
-		))
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithObservationHandler(manualScalerObservations),
+			trait.WithModifier(newManualScalerModifier()),
+		))))
+}
+
+// newManualScalerModifier returns a modifier that scales the packaged
+// Deployment of a workload, and restores its replica count when the trait is
+// deleted.
+func newManualScalerModifier() trait.Modifier {
+	return trait.NewRevertibleModifier(
+		trait.NewWorkloadModifierWithAccessor(manualScalerModifier, trait.DeploymentFromKubeAppAccessor),
+		trait.NewWorkloadModifierWithAccessor(manualScalerReverter, trait.DeploymentFromKubeAppAccessor),
+	)
 }
 
 func manualScalerModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
@@ -65,7 +123,74 @@ func manualScalerModifier(ctx context.Context, obj runtime.Object, t trait.Trait
 	if !ok {
 		return errors.New(errNotManualScalerTrait)
 	}
+
+	// The Deployment of a suspended workload stays scaled to zero until the
+	// workload is resumed.
+	if workload.Suspended(d) {
+		return nil
+	}
 	d.Spec.Replicas = &ms.Spec.ReplicaCount
 
 	return nil
 }
+
+// manualScalerReverter restores the replica count of the packaged Deployment
+// to that of the workload's translation, which is the Deployment's default
+// unless the workload specifies one.
+func manualScalerReverter(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New(errNotDeployment)
+	}
+
+	if _, ok := t.(*oamv1alpha2.ManualScalerTrait); !ok {
+		return errors.New(errNotManualScalerTrait)
+	}
+
+	if workload.Suspended(d) {
+		return nil
+	}
+	r, err := replicas(d)
+	if err != nil {
+		return err
+	}
+	d.Spec.Replicas = r
+
+	return nil
+}
+
+// manualScalerObservations reflects the observed ready replicas of the remote
+// Deployments a ManualScalerTrait scales in the trait's conditions. A trait
+// that scales several workloads is scaled once the Deployments of all of them
+// are.
+// The ManualScalerTrait schema has no fields in which to record observations,
+// so the Scaled condition's message reports the ready and desired replicas.
+func manualScalerObservations(t trait.Trait, obs []remotev1alpha1.RemoteObservation) {
+	ms, ok := t.(*oamv1alpha2.ManualScalerTrait)
+	if !ok {
+		return
+	}
+	var desired, ready int32
+	found := false
+	for _, o := range obs {
+		if o.Kind != deploymentKind {
+			continue
+		}
+		found = true
+
+		// A Deployment whose remote status has not yet been observed has no
+		// ready replicas.
+		desired += ms.Spec.ReplicaCount
+		if len(o.Status.Raw) == 0 {
+			continue
+		}
+		s := &appsv1.DeploymentStatus{}
+		if err := json.Unmarshal(o.Status.Raw, s); err != nil {
+			continue
+		}
+		ready += s.ReadyReplicas
+	}
+	if found {
+		ms.SetConditions(Scaled(desired, ready))
+	}
+}
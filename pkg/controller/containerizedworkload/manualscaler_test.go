@@ -23,10 +23,15 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
 	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
 	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
 )
@@ -42,9 +47,13 @@ func TestManualScalerModifier(t *testing.T) {
 	}
 
 	type want struct {
+		o   runtime.Object
 		err error
 	}
 
+	replicas := int32(3)
+	suspended := int32(0)
+
 	cases := map[string]struct {
 		reason string
 		args   args
@@ -55,7 +64,7 @@ func TestManualScalerModifier(t *testing.T) {
 			args: args{
 				o: &appsv1.DaemonSet{},
 			},
-			want: want{err: errors.New(errNotDeployment)},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
 		},
 		"ErrorTraitNotManualScaler": {
 			reason: "Trait passed to modifier that is not a ManualScalerTrait should return error.",
@@ -63,7 +72,25 @@ func TestManualScalerModifier(t *testing.T) {
 				o: &appsv1.Deployment{},
 				t: &traitfake.Trait{},
 			},
-			want: want{err: errors.New(errNotManualScalerTrait)},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotManualScalerTrait)},
+		},
+		"Suspended": {
+			reason: "The Deployment of a suspended workload should not be scaled.",
+			args: args{
+				o: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+					Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+				},
+				t: &oamv1alpha2.ManualScalerTrait{
+					Spec: oamv1alpha2.ManualScalerTraitSpec{
+						ReplicaCount: 3,
+					},
+				},
+			},
+			want: want{o: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+				Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+			}},
 		},
 		"Success": {
 			reason: "A Deployment should have its replicas field changed on successful modification.",
@@ -79,7 +106,11 @@ func TestManualScalerModifier(t *testing.T) {
 					},
 				},
 			},
-			want: want{},
+			want: want{o: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+				},
+			}},
 		},
 	}
 
@@ -90,6 +121,174 @@ func TestManualScalerModifier(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\nReason: %s\nmanualScalerModifier(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nmanualScalerModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManualScalerReverter(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	suspended := int32(0)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to reverter that is not a Deployment should return error.",
+			args: args{
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotManualScaler": {
+			reason: "Trait passed to reverter that is not a ManualScalerTrait should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotManualScalerTrait)},
+		},
+		"Suspended": {
+			reason: "The Deployment of a suspended workload should stay scaled to zero.",
+			args: args{
+				o: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+					Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+				},
+				t: &oamv1alpha2.ManualScalerTrait{},
+			},
+			want: want{o: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationSuspend: "true"}},
+				Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+			}},
+		},
+		"Success": {
+			reason: "A Deployment should have its replicas field restored to that of the workload's translation.",
+			args: args{
+				o: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Replicas: &startingReplicas,
+					},
+				},
+				t: &oamv1alpha2.ManualScalerTrait{},
+			},
+			want: want{o: &appsv1.Deployment{}},
+		},
+		"SuccessWorkloadReplicas": {
+			reason: "A Deployment should have its replicas field restored to the replica count the workload was translated with.",
+			args: args{
+				o: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationReplicas: "2"}},
+					Spec:       appsv1.DeploymentSpec{Replicas: &suspended},
+				},
+				t: &oamv1alpha2.ManualScalerTrait{},
+			},
+			want: want{o: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationReplicas: "2"}},
+				Spec:       appsv1.DeploymentSpec{Replicas: &startingReplicas},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := manualScalerReverter(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nmanualScalerReverter(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nmanualScalerReverter(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManualScalerObservations(t *testing.T) {
+	type args struct {
+		t   trait.Trait
+		obs []remotev1alpha1.RemoteObservation
+	}
+
+	ms := func() *oamv1alpha2.ManualScalerTrait {
+		return &oamv1alpha2.ManualScalerTrait{Spec: oamv1alpha2.ManualScalerTraitSpec{ReplicaCount: 3}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   runtimev1alpha1.Condition
+	}{
+		"NoDeployment": {
+			reason: "A trait whose workload has no observed Deployment should not be marked as scaled.",
+			args: args{
+				t:   ms(),
+				obs: []remotev1alpha1.RemoteObservation{{Kind: "Service", Status: runtime.RawExtension{Raw: []byte(`{}`)}}},
+			},
+			want: runtimev1alpha1.Condition{Type: TypeScaled, Status: "Unknown"},
+		},
+		"ReplicasNotReady": {
+			reason: "A trait whose Deployment has fewer ready replicas than its replica count should not be scaled.",
+			args: args{
+				t:   ms(),
+				obs: []remotev1alpha1.RemoteObservation{{Kind: deploymentKind, Status: runtime.RawExtension{Raw: []byte(`{"readyReplicas":1}`)}}},
+			},
+			want: Scaled(3, 1),
+		},
+		"ReplicasReady": {
+			reason: "A trait whose Deployment has as many ready replicas as its replica count should be scaled.",
+			args: args{
+				t:   ms(),
+				obs: []remotev1alpha1.RemoteObservation{{Kind: deploymentKind, Status: runtime.RawExtension{Raw: []byte(`{"readyReplicas":3}`)}}},
+			},
+			want: Scaled(3, 3),
+		},
+		"SeveralDeployments": {
+			reason: "A trait that scales several Deployments should be scaled once all of their replicas are ready.",
+			args: args{
+				t: ms(),
+				obs: []remotev1alpha1.RemoteObservation{
+					{Kind: deploymentKind, Status: runtime.RawExtension{Raw: []byte(`{"readyReplicas":3}`)}},
+					{Kind: deploymentKind, Status: runtime.RawExtension{Raw: []byte(`{"readyReplicas":2}`)}},
+				},
+			},
+			want: Scaled(6, 5),
+		},
+		"UnobservedDeployment": {
+			reason: "A Deployment whose remote status has not yet been observed should have no ready replicas.",
+			args: args{
+				t: ms(),
+				obs: []remotev1alpha1.RemoteObservation{
+					{Kind: deploymentKind, Status: runtime.RawExtension{Raw: []byte(`{"readyReplicas":3}`)}},
+					{Kind: deploymentKind},
+				},
+			},
+			want: Scaled(6, 3),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			manualScalerObservations(tc.args.t, tc.args.obs)
+
+			if diff := cmp.Diff(tc.want, tc.args.t.GetCondition(TypeScaled)); diff != "" {
+				t.Errorf("\nReason: %s\nmanualScalerObservations(...): -want, +got:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,348 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const errGetMirroredObject = "cannot get mirrored object"
+
+var (
+	configMapKind       = reflect.TypeOf(corev1.ConfigMap{}).Name()
+	configMapAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// AnnotationMirrorReferences may be set to "true" on a ContainerizedWorkload to
+// mirror the Secrets and ConfigMaps its pods reference from its namespace to
+// the remote cluster. ConfigMaps are copied into the package sent to the
+// remote cluster, while Secrets are propagated by reference so that their data
+// is never copied into it; see workload.RemoteSecretName. Referenced objects
+// that do not exist in the workload's namespace are assumed to exist in the
+// remote namespace.
+const AnnotationMirrorReferences = "containerizedworkload.oam.crossplane.io/mirror-references"
+
+// AnnotationMirrorHash is set to a hash of the objects a ContainerizedWorkload
+// mirrors on the pod template of its Deployment, so that mirrored objects that
+// change replace the workload's remote pods.
+const AnnotationMirrorHash = "containerizedworkload.oam.crossplane.io/mirror-hash"
+
+// IndexMirroredReferences is the name of a field index of the Secrets and
+// ConfigMaps a ContainerizedWorkload mirrors, keyed by kind and name.
+const IndexMirroredReferences = "mirroredReferences"
+
+// A reference to a Secret or ConfigMap.
+type reference struct {
+	kind string
+	name string
+}
+
+// key returns the IndexMirroredReferences key of the reference.
+func (r reference) key() string {
+	return r.kind + "/" + r.name
+}
+
+// references returns the Secrets and ConfigMaps referenced by the supplied
+// pod spec, sorted by kind and name, without duplicates.
+func references(ps *corev1.PodSpec) []reference {
+	seen := map[reference]bool{}
+	add := func(kind, name string) {
+		if name != "" {
+			seen[reference{kind: kind, name: name}] = true
+		}
+	}
+
+	for _, s := range ps.ImagePullSecrets {
+		add(secretKind, s.Name)
+	}
+	for _, v := range ps.Volumes {
+		if v.Secret != nil {
+			add(secretKind, v.Secret.SecretName)
+		}
+		if v.ConfigMap != nil {
+			add(configMapKind, v.ConfigMap.Name)
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, p := range v.Projected.Sources {
+			if p.Secret != nil {
+				add(secretKind, p.Secret.Name)
+			}
+			if p.ConfigMap != nil {
+				add(configMapKind, p.ConfigMap.Name)
+			}
+		}
+	}
+	containers := append(append([]corev1.Container{}, ps.InitContainers...), ps.Containers...)
+	for _, c := range containers {
+		for _, e := range c.EnvFrom {
+			if e.SecretRef != nil {
+				add(secretKind, e.SecretRef.Name)
+			}
+			if e.ConfigMapRef != nil {
+				add(configMapKind, e.ConfigMapRef.Name)
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if e.ValueFrom.SecretKeyRef != nil {
+				add(secretKind, e.ValueFrom.SecretKeyRef.Name)
+			}
+			if e.ValueFrom.ConfigMapKeyRef != nil {
+				add(configMapKind, e.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+
+	out := make([]reference, 0, len(seen))
+	for r := range seen {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].kind != out[j].kind {
+			return out[i].kind < out[j].kind
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+// mirrorsReferences returns true if the supplied workload is a
+// ContainerizedWorkload annotated with AnnotationMirrorReferences.
+func mirrorsReferences(w workload.Workload) bool {
+	_, ok := w.(*oamv1alpha2.ContainerizedWorkload)
+	return ok && w.GetAnnotations()[AnnotationMirrorReferences] == "true"
+}
+
+// MirroredReferenceKeys returns a key for each Secret and ConfigMap referenced
+// by the pods of the supplied ContainerizedWorkload if it is annotated with
+// AnnotationMirrorReferences, for use as the IndexMirroredReferences field
+// index.
+func MirroredReferenceKeys(o runtime.Object) []string {
+	cw, ok := o.(*oamv1alpha2.ContainerizedWorkload)
+	if !ok || !mirrorsReferences(cw) {
+		return nil
+	}
+
+	// The objects a workload's pods reference are only known once it has
+	// been translated. Translation does not read the API server, and the
+	// index is only updated when the workload changes.
+	objs, err := containerizedWorkloadTranslator(context.Background(), cw)
+	if err != nil {
+		return nil
+	}
+	keys := []string{}
+	for _, o := range objs {
+		if pt, ok := podTemplate(o); ok {
+			for _, r := range references(&pt.Spec) {
+				keys = append(keys, r.key())
+			}
+		}
+	}
+	return keys
+}
+
+// AddMirroredReferenceIndex adds the IndexMirroredReferences field index of
+// ContainerizedWorkloads to the supplied indexer.
+func AddMirroredReferenceIndex(i client.FieldIndexer) error {
+	return i.IndexField(&oamv1alpha2.ContainerizedWorkload{}, IndexMirroredReferences, MirroredReferenceKeys)
+}
+
+// A ReferenceMirror mirrors the Secrets and ConfigMaps referenced by the pods
+// of ContainerizedWorkloads annotated with AnnotationMirrorReferences into
+// their translations.
+type ReferenceMirror struct {
+	client client.Reader
+}
+
+// NewReferenceMirror returns a ReferenceMirror that reads the objects it
+// mirrors using the supplied client.
+func NewReferenceMirror(c client.Reader) *ReferenceMirror {
+	return &ReferenceMirror{client: c}
+}
+
+// Wrap adds a copy of each ConfigMap referenced by the pods of the supplied
+// workload's Deployment or DaemonSet that exists in its namespace to its
+// translation, and configures the Deployment or DaemonSet to propagate each
+// Secret they reference that exists there. Objects of the same kind and name
+// as an object the translation already contains are not mirrored. It is a
+// TranslationWrapper.
+func (m *ReferenceMirror) Wrap(ctx context.Context, w workload.Workload, objs []workload.Object) ([]workload.Object, error) {
+	if !mirrorsReferences(w) {
+		return objs, nil
+	}
+
+	exists := map[reference]bool{}
+	for _, o := range objs {
+		exists[reference{kind: o.GetObjectKind().GroupVersionKind().Kind, name: o.GetName()}] = true
+	}
+
+	out := objs
+	for _, o := range objs {
+		pt, ok := podTemplate(o)
+		if !ok {
+			continue
+		}
+		refs := []reference{}
+		for _, r := range references(&pt.Spec) {
+			if !exists[r] {
+				refs = append(refs, r)
+			}
+		}
+		mirrored, secrets, hash, err := m.mirror(ctx, w.GetNamespace(), refs)
+		if err != nil {
+			return nil, err
+		}
+		if hash == "" {
+			continue
+		}
+		meta.AddAnnotations(pt, map[string]string{AnnotationMirrorHash: hash})
+		propagateSecrets(o, pt, secrets)
+		for _, mo := range mirrored {
+			exists[reference{kind: mo.GetObjectKind().GroupVersionKind().Kind, name: mo.GetName()}] = true
+		}
+		out = append(out, mirrored...)
+	}
+	return out, nil
+}
+
+// mirror returns a copy of each ConfigMap and the name of each Secret of the
+// supplied references that exists in the supplied namespace, and a hash of
+// their contents. The hash is empty if none of them exist.
+func (m *ReferenceMirror) mirror(ctx context.Context, namespace string, refs []reference) ([]workload.Object, []string, string, error) {
+	objs := []workload.Object{}
+	secrets := []string{}
+	h := fnv.New64a()
+	for _, r := range refs {
+		nn := types.NamespacedName{Namespace: namespace, Name: r.name}
+		switch r.kind {
+		case secretKind:
+			s := &corev1.Secret{}
+			if err := m.client.Get(ctx, nn, s); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, "", errors.Wrapf(err, "%s %s %s", errGetMirroredObject, r.kind, r.name)
+			}
+			secrets = append(secrets, s.GetName())
+			fmt.Fprintf(h, "%s/%s/%s\n", r.kind, r.name, s.Type)
+			hashData(h, s.Data)
+		case configMapKind:
+			cm := &corev1.ConfigMap{}
+			if err := m.client.Get(ctx, nn, cm); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, "", errors.Wrapf(err, "%s %s %s", errGetMirroredObject, r.kind, r.name)
+			}
+			objs = append(objs, &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       configMapKind,
+					APIVersion: configMapAPIVersion,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: cm.GetName(),
+				},
+				Data:       cm.Data,
+				BinaryData: cm.BinaryData,
+			})
+			fmt.Fprintf(h, "%s/%s\n", r.kind, r.name)
+			data := make(map[string][]byte, len(cm.Data))
+			for k, v := range cm.Data {
+				data[k] = []byte(v)
+			}
+			hashData(h, data)
+			hashData(h, cm.BinaryData)
+		}
+	}
+	if len(objs) == 0 && len(secrets) == 0 {
+		return nil, nil, "", nil
+	}
+	return objs, secrets, fmt.Sprintf("%x", h.Sum64()), nil
+}
+
+// hashData writes the supplied data to the supplied hash, sorted by key.
+func hashData(h io.Writer, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%d:", k, len(data[k]))
+		_, _ = h.Write(data[k])
+		fmt.Fprint(h, "\n")
+	}
+	fmt.Fprint(h, "\n")
+}
+
+// EnqueueRequestsForMirroringWorkloads returns an event handler that enqueues
+// a reconcile of each ContainerizedWorkload that mirrors a Secret or
+// ConfigMap, so that changes to the objects they mirror are propagated to the
+// remote cluster. The cached translations of the workloads are forgotten, so
+// that they are translated again. Workloads are listed using the
+// IndexMirroredReferences field index, which must have been added.
+func EnqueueRequestsForMirroringWorkloads(c client.Reader, tc workload.TranslationCache) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			kind := secretKind
+			if _, ok := o.Object.(*corev1.ConfigMap); ok {
+				kind = configMapKind
+			}
+			r := reference{kind: kind, name: o.Meta.GetName()}
+
+			// An event handler has no way to return errors. Workloads that
+			// cannot be listed pick up changes to the objects they mirror
+			// when they are next translated.
+			l := &oamv1alpha2.ContainerizedWorkloadList{}
+			if err := c.List(context.Background(), l, client.InNamespace(o.Meta.GetNamespace()), client.MatchingFields{IndexMirroredReferences: r.key()}); err != nil {
+				return nil
+			}
+
+			reqs := make([]reconcile.Request, 0, len(l.Items))
+			for i := range l.Items {
+				nn := types.NamespacedName{Namespace: l.Items[i].GetNamespace(), Name: l.Items[i].GetName()}
+				tc.Forget(nn)
+				reqs = append(reqs, reconcile.Request{NamespacedName: nn})
+			}
+			return reqs
+		}),
+	}
+}
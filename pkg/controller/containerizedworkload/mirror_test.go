@@ -0,0 +1,373 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+var _ workload.TranslationWrapper = NewReferenceMirror(nil).Wrap
+
+func TestReferences(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ps     *corev1.PodSpec
+		want   []reference
+	}{
+		"None": {
+			reason: "A pod spec that references nothing should have no references.",
+			ps:     &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool"}}},
+			want:   []reference{},
+		},
+		"All": {
+			reason: "Each referenced Secret and ConfigMap should be returned once, sorted by kind and name.",
+			ps: &corev1.PodSpec{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "pull"}},
+				Volumes: []corev1.Volume{
+					{VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "vol"}}},
+					{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "vol"}}}},
+					{VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+						{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "projected"}}},
+						{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "projected"}}},
+					}}}},
+				},
+				InitContainers: []corev1.Container{{
+					EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "init"}}}},
+				}},
+				Containers: []corev1.Container{{
+					EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}}}},
+					Env: []corev1.EnvVar{
+						{Name: "LITERAL", Value: "cool"},
+						{Name: "SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pull"}}}},
+						{Name: "CONFIG", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "key"}}}},
+					},
+				}},
+			},
+			want: []reference{
+				{kind: configMapKind, name: "env"},
+				{kind: configMapKind, name: "key"},
+				{kind: configMapKind, name: "projected"},
+				{kind: configMapKind, name: "vol"},
+				{kind: secretKind, name: "init"},
+				{kind: secretKind, name: "projected"},
+				{kind: secretKind, name: "pull"},
+				{kind: secretKind, name: "vol"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := references(tc.ps)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(reference{})); diff != "" {
+				t.Errorf("\nReason: %s\nreferences(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorHash(t *testing.T) {
+	refs := []reference{{kind: secretKind, name: "creds"}}
+	secret := func(v string) client.Client {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			s := obj.(*corev1.Secret)
+			s.SetName("creds")
+			s.Data = map[string][]byte{"password": []byte(v)}
+			return nil
+		})}
+	}
+	hash := func(c client.Client) string {
+		_, _, h, err := NewReferenceMirror(c).mirror(context.Background(), "coolns", refs)
+		if err != nil {
+			t.Fatalf("mirror(...): %s", err)
+		}
+		return h
+	}
+
+	if hash(secret("cool")) != hash(secret("cool")) {
+		t.Errorf("mirror(...): the hash of unchanged objects should not change")
+	}
+	if hash(secret("cool")) == hash(secret("cooler")) {
+		t.Errorf("mirror(...): the hash of changed objects should change")
+	}
+	if h := hash(&test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "creds"))}); h != "" {
+		t.Errorf("mirror(...): the hash of objects that do not exist should be empty, got %q", h)
+	}
+}
+
+func TestReferenceMirrorWrap(t *testing.T) {
+	errBoom := errors.New("boom")
+	data := map[string]string{"cool": "very"}
+
+	mirroring := func() *oamv1alpha2.ContainerizedWorkload {
+		return &oamv1alpha2.ContainerizedWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "coolns",
+				Annotations: map[string]string{AnnotationMirrorReferences: "true"},
+			},
+		}
+	}
+	deployment := func(annotations map[string]string) *appsv1.Deployment {
+		d := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion}}
+		d.Spec.Template.SetAnnotations(annotations)
+		d.Spec.Template.Spec.Containers = []corev1.Container{{
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}}},
+		}}
+		return d
+	}
+	configMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: configMapKind, APIVersion: configMapAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+			Data:       data,
+		}
+	}
+	getConfigMap := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		cm := obj.(*corev1.ConfigMap)
+		cm.SetName("config")
+		cm.SetNamespace("coolns")
+		cm.SetResourceVersion("1")
+		cm.Data = data
+		return nil
+	})
+	_, _, hash, _ := NewReferenceMirror(&test.MockClient{MockGet: getConfigMap}).mirror(context.Background(), "coolns", []reference{{kind: configMapKind, name: "config"}})
+
+	secretDeployment := func(secret string, annotations, templateAnnotations map[string]string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations},
+		}
+		d.Spec.Template.SetAnnotations(templateAnnotations)
+		d.Spec.Template.Spec.Containers = []corev1.Container{{
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}}}},
+		}}
+		return d
+	}
+	getSecret := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetName("creds")
+		s.SetNamespace("coolns")
+		s.Data = map[string][]byte{"password": []byte("very")}
+		return nil
+	})
+	_, _, secretHash, _ := NewReferenceMirror(&test.MockClient{MockGet: getSecret}).mirror(context.Background(), "coolns", []reference{{kind: secretKind, name: "creds"}})
+
+	type want struct {
+		objs []workload.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      workload.Workload
+		objs   []workload.Object
+		want   want
+	}{
+		"NotAnnotated": {
+			reason: "Objects referenced by a workload that is not annotated should not be mirrored.",
+			w:      &oamv1alpha2.ContainerizedWorkload{},
+			objs:   []workload.Object{deployment(nil)},
+			want:   want{objs: []workload.Object{deployment(nil)}},
+		},
+		"GetError": {
+			reason: "Errors getting a referenced object should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      mirroring(),
+			objs:   []workload.Object{deployment(nil)},
+			want:   want{err: errors.Wrapf(errBoom, "%s %s %s", errGetMirroredObject, configMapKind, "config")},
+		},
+		"NotFound": {
+			reason: "Referenced objects that do not exist in the workload's namespace should not be mirrored.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "config"))},
+			w:      mirroring(),
+			objs:   []workload.Object{deployment(nil)},
+			want:   want{objs: []workload.Object{deployment(nil)}},
+		},
+		"AlreadyTranslated": {
+			reason: "Referenced objects that are already part of the translation should not be mirrored.",
+			w:      mirroring(),
+			objs:   []workload.Object{deployment(nil), configMap()},
+			want:   want{objs: []workload.Object{deployment(nil), configMap()}},
+		},
+		"Mirrored": {
+			reason: "Referenced objects should be added to the translation, and their hash to the pod template.",
+			c:      &test.MockClient{MockGet: getConfigMap},
+			w:      mirroring(),
+			objs:   []workload.Object{deployment(nil)},
+			want: want{objs: []workload.Object{
+				deployment(map[string]string{AnnotationMirrorHash: hash}),
+				configMap(),
+			}},
+		},
+		"MirroredSecret": {
+			reason: "Referenced Secrets should be propagated by reference rather than added to the translation, and their hash added to the pod template.",
+			c:      &test.MockClient{MockGet: getSecret},
+			w:      mirroring(),
+			objs:   []workload.Object{secretDeployment("creds", nil, nil)},
+			want: want{objs: []workload.Object{
+				secretDeployment("web-deployment-creds",
+					map[string]string{workload.AnnotationPropagateSecrets: "creds"},
+					map[string]string{AnnotationMirrorHash: secretHash},
+				),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewReferenceMirror(tc.c).Wrap(context.Background(), tc.w, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirroredReferenceKeys(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      runtime.Object
+		want   []string
+	}{
+		"NotAContainerizedWorkload": {
+			reason: "Objects that are not ContainerizedWorkloads mirror nothing.",
+			o:      &corev1.Secret{},
+		},
+		"NotMirroring": {
+			reason: "Workloads that are not annotated should not be indexed.",
+			o: &oamv1alpha2.ContainerizedWorkload{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{AnnotationImagePullSecrets: "creds"},
+			}},
+		},
+		"Mirroring": {
+			reason: "The kind and name of each object referenced by the pods of an annotated workload should be returned.",
+			o: &oamv1alpha2.ContainerizedWorkload{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{AnnotationMirrorReferences: "true", AnnotationImagePullSecrets: "creds"},
+			}},
+			want: []string{secretKind + "/creds"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MirroredReferenceKeys(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nMirroredReferenceKeys(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A forgetfulCache records the workloads whose translations were forgotten.
+type forgetfulCache struct {
+	workload.NopTranslationCache
+	forgot []types.NamespacedName
+}
+
+func (c *forgetfulCache) Forget(nn types.NamespacedName) { c.forgot = append(c.forgot, nn) }
+
+func TestEnqueueRequestsForMirroringWorkloads(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	nn := types.NamespacedName{Namespace: "coolns", Name: "cool-workload"}
+	list := func(key string) func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "coolns" || lo.FieldSelector.String() != IndexMirroredReferences+"="+key {
+				return errBoom
+			}
+			obj.(*oamv1alpha2.ContainerizedWorkloadList).Items = []oamv1alpha2.ContainerizedWorkload{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name}},
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		reqs   []reconcile.Request
+		forgot []types.NamespacedName
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		o      runtime.Object
+		want   want
+	}{
+		"ListError": {
+			reason: "Workloads that cannot be listed should not be enqueued.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			o:      &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "creds"}},
+		},
+		"Secret": {
+			reason: "Workloads that mirror a Secret should be enqueued, and their cached translations forgotten.",
+			c:      &test.MockClient{MockList: list(secretKind + "/creds")},
+			o:      &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "creds"}},
+			want: want{
+				reqs:   []reconcile.Request{{NamespacedName: nn}},
+				forgot: []types.NamespacedName{nn},
+			},
+		},
+		"ConfigMap": {
+			reason: "Workloads that mirror a ConfigMap should be enqueued, and their cached translations forgotten.",
+			c:      &test.MockClient{MockList: list(configMapKind + "/config")},
+			o:      &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "config"}},
+			want: want{
+				reqs:   []reconcile.Request{{NamespacedName: nn}},
+				forgot: []types.NamespacedName{nn},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &forgetfulCache{}
+			h := EnqueueRequestsForMirroringWorkloads(tc.c, c).(*handler.EnqueueRequestsFromMapFunc)
+			got := h.ToRequests.Map(handler.MapObject{Meta: tc.o.(metav1.Object), Object: tc.o})
+			if diff := cmp.Diff(tc.want.reqs, got); diff != "" {
+				t.Errorf("\nReason: %s\nEnqueueRequestsForMirroringWorkloads(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.forgot, c.forgot); diff != "" {
+				t.Errorf("\nReason: %s\nEnqueueRequestsForMirroringWorkloads(...): -want forgotten, +got forgotten:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
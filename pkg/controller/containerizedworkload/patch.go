@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotPatchTrait       = "trait is not a patch trait"
+	errUnmarshalTemplate   = "cannot unmarshal resource template"
+	errApplyPatch          = "cannot apply patch"
+	errNoTemplateForPatch  = "no resource template found for patch"
+	errUnknownPatchType    = "unknown patch type"
+	errDecodeJSONPatch     = "cannot decode JSON patch"
+	errNewPatchDataStruct  = "cannot create object to determine strategic merge patch semantics"
+	errApplyStrategicMerge = "cannot apply strategic merge patch"
+)
+
+// SetupPatchTrait adds a controller that reconciles PatchTraits that reference
+// a ContainerizedWorkload.
+func SetupPatchTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.PatchTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.PatchTrait{}, remotev1alpha1.PatchTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.PatchTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.PatchTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(&patchModifier{types: mgr.GetScheme()}),
+		))))
+}
+
+// A patchModifier applies the patches of a PatchTrait to the resource
+// templates of a KubernetesApplication.
+type patchModifier struct {
+	// types are used to determine the strategic merge semantics of the
+	// patched templates.
+	types runtime.ObjectCreater
+}
+
+func (m *patchModifier) Modify(_ context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	pt, ok := t.(*remotev1alpha1.PatchTrait)
+	if !ok {
+		return errors.New(errNotPatchTrait)
+	}
+
+	for i, p := range pt.Spec.Patches {
+		patched := false
+		for j := range a.Spec.ResourceTemplates {
+			raw := a.Spec.ResourceTemplates[j].Spec.Template.Raw
+			u := &unstructured.Unstructured{}
+			if err := json.Unmarshal(raw, u); err != nil {
+				return errors.Wrap(err, errUnmarshalTemplate)
+			}
+			if !patchTargets(p.Target, u) {
+				continue
+			}
+			b, err := m.patch(u.GroupVersionKind(), raw, p)
+			if err != nil {
+				return errors.Wrapf(err, "%s %d", errApplyPatch, i)
+			}
+			a.Spec.ResourceTemplates[j].Spec.Template = runtime.RawExtension{Raw: b}
+			patched = true
+		}
+		if !patched {
+			return errors.Errorf("%s %d", errNoTemplateForPatch, i)
+		}
+	}
+
+	return nil
+}
+
+// patch the supplied template, which is of the supplied kind.
+func (m *patchModifier) patch(gvk schema.GroupVersionKind, template []byte, p remotev1alpha1.Patch) ([]byte, error) {
+	switch p.Type {
+	case remotev1alpha1.PatchTypeJSON:
+		jp, err := jsonpatch.DecodePatch(p.Patch.Raw)
+		if err != nil {
+			return nil, errors.Wrap(err, errDecodeJSONPatch)
+		}
+		return jp.Apply(template)
+	case remotev1alpha1.PatchTypeStrategicMerge:
+		ds, err := m.types.New(gvk)
+		if runtime.IsNotRegisteredError(err) {
+			// Kinds we don't know the Go type of, for example custom
+			// resources, have no strategic merge semantics.
+			return jsonpatch.MergePatch(template, p.Patch.Raw)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errNewPatchDataStruct)
+		}
+		b, err := strategicpatch.StrategicMergePatch(template, p.Patch.Raw, ds)
+		return b, errors.Wrap(err, errApplyStrategicMerge)
+	}
+	return nil, errors.Errorf("%s: %s", errUnknownPatchType, p.Type)
+}
+
+// patchTargets returns true if the supplied patch target selects the supplied
+// resource template.
+func patchTargets(pt remotev1alpha1.PatchTarget, u *unstructured.Unstructured) bool {
+	if u.GetAPIVersion() != pt.APIVersion || u.GetKind() != pt.Kind {
+		return false
+	}
+	return pt.Name == "" || u.GetName() == pt.Name
+}
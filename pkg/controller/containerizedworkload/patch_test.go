@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestPatchModifier(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := appsv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	deployment := func(replicas int32, c ...corev1.Container) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: deploymentAPIVersion, Kind: deploymentKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-workload"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: c}},
+			},
+		}
+	}
+	custom := func(size string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "Cache",
+			"metadata":   map[string]interface{}{"name": "cool-workload"},
+			"spec":       map[string]interface{}{"size": size, "engine": "redis"},
+		}
+	}
+	kubeApp := func(templates ...interface{}) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, t := range templates {
+			b, _ := json.Marshal(t)
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: b}},
+			})
+		}
+		return a
+	}
+	patchTrait := func(p ...remotev1alpha1.Patch) *remotev1alpha1.PatchTrait {
+		return &remotev1alpha1.PatchTrait{Spec: remotev1alpha1.PatchTraitSpec{Patches: p}}
+	}
+	deploymentTarget := remotev1alpha1.PatchTarget{APIVersion: deploymentAPIVersion, Kind: deploymentKind}
+
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to modifier that is not a KubernetesApplication should return error.",
+			args:   args{o: &appsv1.Deployment{}, t: patchTrait()},
+			want:   want{o: &appsv1.Deployment{}, err: errors.New(errNotKubeApp)},
+		},
+		"ErrorTraitNotPatchTrait": {
+			reason: "Trait passed to modifier that is not a PatchTrait should return error.",
+			args:   args{o: kubeApp(), t: &traitfake.Trait{}},
+			want:   want{o: kubeApp(), err: errors.New(errNotPatchTrait)},
+		},
+		"ErrorNoTemplateForPatch": {
+			reason: "Patches that do not target any template should return error.",
+			args: args{
+				o: kubeApp(deployment(1)),
+				t: patchTrait(remotev1alpha1.Patch{
+					Type:   remotev1alpha1.PatchTypeJSON,
+					Target: remotev1alpha1.PatchTarget{APIVersion: deploymentAPIVersion, Kind: deploymentKind, Name: "other"},
+					Patch:  runtime.RawExtension{Raw: []byte(`[]`)},
+				}),
+			},
+			want: want{o: kubeApp(deployment(1)), err: errors.Errorf("%s %d", errNoTemplateForPatch, 0)},
+		},
+		"ErrorUnknownPatchType": {
+			reason: "Patches of an unknown type should return error.",
+			args: args{
+				o: kubeApp(deployment(1)),
+				t: patchTrait(remotev1alpha1.Patch{Type: "Cool", Target: deploymentTarget}),
+			},
+			want: want{
+				o:   kubeApp(deployment(1)),
+				err: errors.Wrapf(errors.Errorf("%s: %s", errUnknownPatchType, "Cool"), "%s %d", errApplyPatch, 0),
+			},
+		},
+		"JSONPatch": {
+			reason: "JSON patches should be applied to the targeted templates.",
+			args: args{
+				o: kubeApp(deployment(1), custom("small")),
+				t: patchTrait(remotev1alpha1.Patch{
+					Type:   remotev1alpha1.PatchTypeJSON,
+					Target: deploymentTarget,
+					Patch:  runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/spec/replicas","value":3}]`)},
+				}),
+			},
+			want: want{o: kubeApp(deployment(3), custom("small"))},
+		},
+		"StrategicMergePatch": {
+			reason: "Strategic merge patches should merge lists of known kinds by their merge key.",
+			args: args{
+				o: kubeApp(deployment(1, corev1.Container{Name: "a", Image: "a:1"}, corev1.Container{Name: "b", Image: "b:1"})),
+				t: patchTrait(remotev1alpha1.Patch{
+					Type:   remotev1alpha1.PatchTypeStrategicMerge,
+					Target: deploymentTarget,
+					Patch:  runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"b","image":"b:2"}]}}}}`)},
+				}),
+			},
+			want: want{o: kubeApp(deployment(1, corev1.Container{Name: "a", Image: "a:1"}, corev1.Container{Name: "b", Image: "b:2"}))},
+		},
+		"MergePatchUnknownKind": {
+			reason: "Strategic merge patches of unknown kinds should be applied as JSON merge patches.",
+			args: args{
+				o: kubeApp(custom("small")),
+				t: patchTrait(remotev1alpha1.Patch{
+					Type:   remotev1alpha1.PatchTypeStrategicMerge,
+					Target: remotev1alpha1.PatchTarget{APIVersion: "example.org/v1", Kind: "Cache", Name: "cool-workload"},
+					Patch:  runtime.RawExtension{Raw: []byte(`{"spec":{"size":"large"}}`)},
+				}),
+			},
+			want: want{o: kubeApp(custom("large"))},
+		},
+	}
+
+	// Templates are compared as JSON values so that field order is ignored.
+	templates := cmp.Transformer("Templates", func(r runtime.RawExtension) interface{} {
+		var v interface{}
+		_ = json.Unmarshal(r.Raw, &v)
+		return v
+	})
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &patchModifier{types: s}
+			err := m.Modify(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nm.Modify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o, templates); diff != "" {
+				t.Errorf("\nReason: %s\nm.Modify(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotPodDisruptionBudgetTrait = "trait is not a pod disruption budget trait"
+	errPodDisruptionBudgetBounds   = "pod disruption budget trait must specify exactly one of minAvailable and maxUnavailable"
+	errNoDeploymentSelector        = "cannot find the selector of the packaged Deployment"
+	errSetPodDisruptionBudget      = "cannot add pod disruption budget to KubernetesApplication"
+)
+
+var (
+	podDisruptionBudgetKind       = reflect.TypeOf(policyv1beta1.PodDisruptionBudget{}).Name()
+	podDisruptionBudgetAPIVersion = policyv1beta1.SchemeGroupVersion.String()
+)
+
+// SetupPodDisruptionBudgetTrait adds a controller that reconciles
+// PodDisruptionBudgetTraits that reference a ContainerizedWorkload.
+func SetupPodDisruptionBudgetTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.PodDisruptionBudgetTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.PodDisruptionBudgetTrait{}, remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(newPodDisruptionBudgetModifier()),
+		))))
+}
+
+// newPodDisruptionBudgetModifier returns a modifier that adds a
+// PodDisruptionBudget to the KubernetesApplication of a workload, and removes
+// it when the trait is deleted.
+func newPodDisruptionBudgetModifier() trait.Modifier {
+	return trait.NewRevertibleModifier(
+		trait.ModifyFn(podDisruptionBudgetModifier),
+		trait.ModifyFn(podDisruptionBudgetReverter),
+	)
+}
+
+// podDisruptionBudgetModifier adds a PodDisruptionBudget that selects the pods
+// of the packaged Deployment to the KubernetesApplication. The budget is
+// named after the trait and created in the KubernetesApplication's remote
+// namespace because it does not specify one.
+func podDisruptionBudgetModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	pdbt, ok := t.(*remotev1alpha1.PodDisruptionBudgetTrait)
+	if !ok {
+		return errors.New(errNotPodDisruptionBudgetTrait)
+	}
+
+	if (pdbt.Spec.MinAvailable == nil) == (pdbt.Spec.MaxUnavailable == nil) {
+		return errors.New(errPodDisruptionBudgetBounds)
+	}
+
+	sel, err := deploymentSelector(a)
+	if err != nil {
+		return err
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       podDisruptionBudgetKind,
+			APIVersion: podDisruptionBudgetAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pdbt.GetName(),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   pdbt.Spec.MinAvailable,
+			MaxUnavailable: pdbt.Spec.MaxUnavailable,
+			Selector:       sel,
+		},
+	}
+
+	if err := trait.SetKubeAppTemplate(a, pdb); err != nil {
+		return errors.Wrap(err, errSetPodDisruptionBudget)
+	}
+
+	return nil
+}
+
+// podDisruptionBudgetReverter removes the PodDisruptionBudget added by the
+// trait from the KubernetesApplication.
+func podDisruptionBudgetReverter(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       podDisruptionBudgetKind,
+			APIVersion: podDisruptionBudgetAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: t.GetName(),
+		},
+	}
+	trait.RemoveKubeAppTemplate(a, pdb)
+
+	return nil
+}
+
+// deploymentSelector returns the selector of the Deployment packaged in the
+// supplied KubernetesApplication.
+func deploymentSelector(a *workloadv1alpha1.KubernetesApplication) (*metav1.LabelSelector, error) {
+	templates, err := trait.Templates(a)
+	if err != nil {
+		return nil, errors.Wrap(err, errNoDeploymentSelector)
+	}
+	for _, t := range templates {
+		if t.GetKind() != deploymentKind {
+			continue
+		}
+		d := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.UnstructuredContent(), d); err != nil {
+			return nil, errors.Wrap(err, errNoDeploymentSelector)
+		}
+		if d.Spec.Selector == nil {
+			break
+		}
+		return d.Spec.Selector, nil
+	}
+	return nil, errors.New(errNoDeploymentSelector)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestPodDisruptionBudgetModifier(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(1)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cool"}}
+
+	pdbt := func(min, max *intstr.IntOrString) *remotev1alpha1.PodDisruptionBudgetTrait {
+		return &remotev1alpha1.PodDisruptionBudgetTrait{
+			ObjectMeta: metav1.ObjectMeta{Name: "budgeted"},
+			Spec: remotev1alpha1.PodDisruptionBudgetTraitSpec{
+				MinAvailable:      min,
+				MaxUnavailable:    max,
+				WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+			},
+		}
+	}
+
+	kubeApp := func(objs ...trait.Object) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, o := range objs {
+			_ = trait.SetKubeAppTemplate(a, o)
+		}
+		return a
+	}
+	d := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: cwName},
+		Spec:       appsv1.DeploymentSpec{Selector: selector},
+	}
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   metav1.TypeMeta{Kind: podDisruptionBudgetKind, APIVersion: podDisruptionBudgetAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: "budgeted"},
+		Spec:       policyv1beta1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable, Selector: selector},
+	}
+
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to modifier that is not a KubernetesApplication should return error.",
+			args:   args{o: &appsv1.Deployment{}},
+			want:   want{o: &appsv1.Deployment{}, err: errors.New(errNotKubeApp)},
+		},
+		"ErrorTraitNotPodDisruptionBudgetTrait": {
+			reason: "Trait passed to modifier that is not a PodDisruptionBudgetTrait should return error.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: &traitfake.Trait{}},
+			want:   want{o: &workloadv1alpha1.KubernetesApplication{}, err: errors.New(errNotPodDisruptionBudgetTrait)},
+		},
+		"ErrorNoBounds": {
+			reason: "A trait that specifies neither minAvailable nor maxUnavailable should return error.",
+			args:   args{o: kubeApp(d), t: pdbt(nil, nil)},
+			want:   want{o: kubeApp(d), err: errors.New(errPodDisruptionBudgetBounds)},
+		},
+		"ErrorBothBounds": {
+			reason: "A trait that specifies both minAvailable and maxUnavailable should return error.",
+			args:   args{o: kubeApp(d), t: pdbt(&minAvailable, &maxUnavailable)},
+			want:   want{o: kubeApp(d), err: errors.New(errPodDisruptionBudgetBounds)},
+		},
+		"ErrorNoDeployment": {
+			reason: "A KubernetesApplication that packages no Deployment should return error.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: pdbt(&minAvailable, nil)},
+			want:   want{o: &workloadv1alpha1.KubernetesApplication{}, err: errors.New(errNoDeploymentSelector)},
+		},
+		"Success": {
+			reason: "A PodDisruptionBudget that selects the Deployment's pods should be added.",
+			args:   args{o: kubeApp(d), t: pdbt(&minAvailable, nil)},
+			want:   want{o: kubeApp(d, pdb)},
+		},
+		"Replaced": {
+			reason: "A PodDisruptionBudget added by a previous modification should be replaced rather than duplicated.",
+			args:   args{o: kubeApp(d, pdb), t: pdbt(&minAvailable, nil)},
+			want:   want{o: kubeApp(d, pdb)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := podDisruptionBudgetModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\npodDisruptionBudgetModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\npodDisruptionBudgetModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPodDisruptionBudgetReverter(t *testing.T) {
+	d := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: cwName},
+	}
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta:   metav1.TypeMeta{Kind: podDisruptionBudgetKind, APIVersion: podDisruptionBudgetAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: "budgeted"},
+	}
+	kubeApp := func(objs ...trait.Object) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, o := range objs {
+			_ = trait.SetKubeAppTemplate(a, o)
+		}
+		return a
+	}
+	pdbt := &remotev1alpha1.PodDisruptionBudgetTrait{ObjectMeta: metav1.ObjectMeta{Name: "budgeted"}}
+
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to reverter that is not a KubernetesApplication should return error.",
+			args:   args{o: &appsv1.Deployment{}, t: pdbt},
+			want:   want{o: &appsv1.Deployment{}, err: errors.New(errNotKubeApp)},
+		},
+		"Removed": {
+			reason: "The PodDisruptionBudget added by the trait should be removed.",
+			args:   args{o: kubeApp(d, pdb), t: pdbt},
+			want:   want{o: kubeApp(d)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := podDisruptionBudgetReverter(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\npodDisruptionBudgetReverter(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\npodDisruptionBudgetReverter(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
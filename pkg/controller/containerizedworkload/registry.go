@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const errGetImagePullSecret = "cannot get image pull secret"
+
+var secretKind = reflect.TypeOf(corev1.Secret{}).Name()
+
+// AnnotationImagePullSecrets may be set on a ContainerizedWorkload to specify
+// additional image pull secrets for its pods, beyond those of its containers.
+// Its value is a comma separated list of Secret names.
+const AnnotationImagePullSecrets = "containerizedworkload.oam.crossplane.io/image-pull-secrets"
+
+// AnnotationCopyImagePullSecrets may be set to "true" on a
+// ContainerizedWorkload to copy its image pull secrets from its namespace into
+// the package sent to the remote cluster, so that private images may be pulled
+// without first creating the Secrets there.
+const AnnotationCopyImagePullSecrets = "containerizedworkload.oam.crossplane.io/copy-image-pull-secrets"
+
+// imagePullSecrets returns the names of the image pull secrets of the supplied
+// workload, without duplicates, in the order they are specified.
+func imagePullSecrets(cw *oamv1alpha2.ContainerizedWorkload) []string {
+	names := []string{}
+	for _, c := range cw.Spec.Containers {
+		if c.ImagePullSecret != nil {
+			names = append(names, *c.ImagePullSecret)
+		}
+	}
+	if raw := cw.GetAnnotations()[AnnotationImagePullSecrets]; raw != "" {
+		for _, n := range strings.Split(raw, ",") {
+			names = append(names, strings.TrimSpace(n))
+		}
+	}
+
+	seen := map[string]bool{}
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
+
+// setImagePullSecrets references the supplied Secrets as image pull secrets of
+// the supplied pod spec.
+func setImagePullSecrets(ps *corev1.PodSpec, names []string) {
+	for _, n := range names {
+		ps.ImagePullSecrets = append(ps.ImagePullSecrets, corev1.LocalObjectReference{Name: n})
+	}
+}
+
+// renameSecrets renames each reference to the supplied Secrets made by the
+// supplied pod spec.
+func renameSecrets(ps *corev1.PodSpec, names map[string]string) {
+	rename := func(n *string) {
+		if to, ok := names[*n]; ok {
+			*n = to
+		}
+	}
+
+	for i := range ps.ImagePullSecrets {
+		rename(&ps.ImagePullSecrets[i].Name)
+	}
+	for i := range ps.Volumes {
+		v := &ps.Volumes[i]
+		if v.Secret != nil {
+			rename(&v.Secret.SecretName)
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for j := range v.Projected.Sources {
+			if p := v.Projected.Sources[j].Secret; p != nil {
+				rename(&p.Name)
+			}
+		}
+	}
+	for _, cs := range [][]corev1.Container{ps.InitContainers, ps.Containers} {
+		for i := range cs {
+			c := &cs[i]
+			for j := range c.EnvFrom {
+				if r := c.EnvFrom[j].SecretRef; r != nil {
+					rename(&r.Name)
+				}
+			}
+			for j := range c.Env {
+				if vf := c.Env[j].ValueFrom; vf != nil && vf.SecretKeyRef != nil {
+					rename(&vf.SecretKeyRef.Name)
+				}
+			}
+		}
+	}
+}
+
+// propagateSecrets configures the supplied object to propagate the supplied
+// Secrets from its workload's namespace to the remote cluster, and renames the
+// references its pods make to them to the names they are given there.
+func propagateSecrets(o workload.Object, pt *corev1.PodTemplateSpec, secrets []string) {
+	if len(secrets) == 0 {
+		return
+	}
+	names := make(map[string]string, len(secrets))
+	for _, n := range secrets {
+		names[n] = workload.RemoteSecretName(o, n)
+	}
+	renameSecrets(&pt.Spec, names)
+	workload.PropagateSecrets(o, secrets...)
+}
+
+// NewImagePullSecretCopier returns a TranslationWrapper that copies each image
+// pull secret of a ContainerizedWorkload annotated with
+// AnnotationCopyImagePullSecrets from its namespace to the remote cluster.
+// Secrets are referenced by the workload's package rather than copied into
+// it, so that their data is only ever read by the controller that propagates
+// them, and are renamed in the remote cluster; see workload.RemoteSecretName.
+func NewImagePullSecretCopier(c client.Reader) workload.TranslationWrapper {
+	return func(ctx context.Context, w workload.Workload, objs []workload.Object) ([]workload.Object, error) {
+		cw, ok := w.(*oamv1alpha2.ContainerizedWorkload)
+		if !ok {
+			return objs, nil
+		}
+		if cw.GetAnnotations()[AnnotationCopyImagePullSecrets] != "true" {
+			return objs, nil
+		}
+
+		// Secrets that do not exist would never be propagated, so we
+		// check for them here rather than fail remotely.
+		names := imagePullSecrets(cw)
+		for _, n := range names {
+			if err := c.Get(ctx, types.NamespacedName{Namespace: cw.GetNamespace(), Name: n}, &corev1.Secret{}); err != nil {
+				return nil, errors.Wrapf(err, "%s %s", errGetImagePullSecret, n)
+			}
+		}
+
+		for _, o := range objs {
+			if pt, ok := podTemplate(o); ok {
+				propagateSecrets(o, pt, names)
+			}
+		}
+		return objs, nil
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func TestImagePullSecrets(t *testing.T) {
+	cool := "cool"
+	creds := "creds"
+
+	cases := map[string]struct {
+		reason string
+		cw     *oamv1alpha2.ContainerizedWorkload
+		want   []string
+	}{
+		"None": {
+			reason: "A workload without image pull secrets should have none.",
+			cw:     &oamv1alpha2.ContainerizedWorkload{},
+			want:   []string{},
+		},
+		"Containers": {
+			reason: "The image pull secrets of each container should be returned once.",
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{ImagePullSecret: &cool}, {ImagePullSecret: &cool}, {ImagePullSecret: &creds}},
+			}},
+			want: []string{cool, creds},
+		},
+		"Annotation": {
+			reason: "Image pull secrets from the annotation should follow those of the containers.",
+			cw: &oamv1alpha2.ContainerizedWorkload{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationImagePullSecrets: "creds, extra,,cool"}},
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{{ImagePullSecret: &cool}},
+				},
+			},
+			want: []string{cool, creds, "extra"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := imagePullSecrets(tc.cw)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nimagePullSecrets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImagePullSecretCopier(t *testing.T) {
+	errBoom := errors.New("boom")
+	creds := "creds"
+
+	deploy := func(pullSecret string, annotations map[string]string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: deploymentKind, APIVersion: deploymentAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations},
+		}
+		d.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: pullSecret}}
+		return d
+	}
+
+	copied := func() *oamv1alpha2.ContainerizedWorkload {
+		return &oamv1alpha2.ContainerizedWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "coolns",
+				Annotations: map[string]string{AnnotationCopyImagePullSecrets: "true"},
+			},
+			Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{ImagePullSecret: &creds}},
+			},
+		}
+	}
+
+	type want struct {
+		objs []workload.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      workload.Workload
+		want   want
+	}{
+		"NotAnnotated": {
+			reason: "Image pull secrets of a workload that is not annotated should not be copied.",
+			w: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{ImagePullSecret: &creds}},
+			}},
+			want: want{objs: []workload.Object{deploy(creds, nil)}},
+		},
+		"GetError": {
+			reason: "Errors getting an image pull secret should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      copied(),
+			want:   want{err: errors.Wrapf(errBoom, "%s %s", errGetImagePullSecret, creds)},
+		},
+		"Copied": {
+			reason: "Image pull secrets of an annotated workload should be propagated by reference rather than added to its translation.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			w:      copied(),
+			want: want{objs: []workload.Object{
+				deploy("web-deployment-creds", map[string]string{workload.AnnotationPropagateSecrets: creds}),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewImagePullSecretCopier(tc.c)(context.Background(), tc.w, []workload.Object{deploy(creds, nil)})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewImagePullSecretCopier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nNewImagePullSecretCopier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errRenderTranslate = "cannot translate workload"
+	errRenderPackage   = "cannot package workload translation"
+	errRenderNoKubeApp = "workload was not packaged as a KubernetesApplication"
+	errRenderTraitKind = "cannot determine kind of trait"
+	errRenderNoTrait   = "trait kind does not modify ContainerizedWorkloads"
+	errRenderModify    = "cannot apply trait modification"
+)
+
+// Modifiers returns the modifiers the trait controllers of this package use
+// to modify the KubernetesApplication of a ContainerizedWorkload, keyed by
+// trait kind. Objects the modifiers reference, such as Bundles, are read from
+// the supplied client.
+func Modifiers(c client.Reader, s runtime.ObjectCreater) map[schema.GroupVersionKind]trait.Modifier {
+	return map[schema.GroupVersionKind]trait.Modifier{
+		oamv1alpha2.ManualScalerTraitGroupVersionKind:            newManualScalerModifier(),
+		remotev1alpha1.VolumeMountTraitGroupVersionKind:          trait.ModifyFn(volumeMountModifier),
+		remotev1alpha1.BundleTraitGroupVersionKind:               &bundleModifier{client: c},
+		remotev1alpha1.PatchTraitGroupVersionKind:                &patchModifier{types: s},
+		remotev1alpha1.SidecarInjectionTraitGroupVersionKind:     trait.NewWorkloadModifierWithAccessor(sidecarInjectionModifier, trait.DeploymentFromKubeAppAccessor),
+		remotev1alpha1.TrafficSplitTraitGroupVersionKind:         trait.ModifyFn(trafficSplitModifier),
+		remotev1alpha1.ResourceQuotaTraitGroupVersionKind:        trait.ModifyFn(resourceQuotaModifier),
+		remotev1alpha1.SecurityContextTraitGroupVersionKind:      trait.NewWorkloadModifierWithAccessor(securityContextModifier, trait.DeploymentFromKubeAppAccessor),
+		remotev1alpha1.CronScalerTraitGroupVersionKind:           newCronScalerModifier(time.Now),
+		remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind:  newPodDisruptionBudgetModifier(),
+		remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind: trait.ModifyFn(annotationsAndLabelsModifier),
+	}
+}
+
+// Render the KubernetesApplication the controllers of this package would
+// produce for the supplied ContainerizedWorkload and traits, without a
+// cluster. The workload is translated and packaged, then modified by each
+// trait in the order supplied. Objects the translation or traits reference,
+// such as image pull secrets, Bundles, or KubernetesTargets, are read from the
+// supplied client. Owner references, render inputs, and field ownership are
+// not recorded.
+func Render(ctx context.Context, c client.Reader, s *runtime.Scheme, cw *oamv1alpha2.ContainerizedWorkload, traits ...trait.Trait) (*workloadv1alpha1.KubernetesApplication, error) {
+	objs, err := workload.NewObjectTranslatorWithWrappers(containerizedWorkloadTranslator, append(translationWrappers(c), workload.OrderByWave)...).Translate(ctx, cw)
+	if err != nil {
+		return nil, errors.Wrap(err, errRenderTranslate)
+	}
+	objs, err = workload.NewPackagerWithWrappers(workload.PackageFn(workload.KubeAppWrapper), workload.PinTarget(c)).Package(ctx, cw, objs)
+	if err != nil {
+		return nil, errors.Wrap(err, errRenderPackage)
+	}
+
+	var a *workloadv1alpha1.KubernetesApplication
+	for _, o := range objs {
+		if ka, ok := o.(*workloadv1alpha1.KubernetesApplication); ok {
+			a = ka
+		}
+	}
+	if a == nil {
+		return nil, errors.New(errRenderNoKubeApp)
+	}
+	workload.NameAfterWorkload(cw, a)
+
+	modifiers := Modifiers(c, s)
+	for _, t := range traits {
+		gvks, _, err := s.ObjectKinds(t)
+		if err != nil {
+			return nil, errors.Wrap(err, errRenderTraitKind)
+		}
+		m, ok := modifiers[gvks[0]]
+		if !ok {
+			return nil, errors.Errorf("%s: %s", errRenderNoTrait, gvks[0].Kind)
+		}
+		if err := m.Modify(ctx, a, t); err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errRenderModify, t.GetName())
+		}
+	}
+
+	return a, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestRender(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := oamv1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := &traitfake.Trait{}
+	_, _, errUnknown := s.ObjectKinds(unknown)
+
+	type args struct {
+		cw     *oamv1alpha2.ContainerizedWorkload
+		traits []trait.Trait
+	}
+
+	type want struct {
+		replicas *int32
+		err      error
+	}
+
+	replicas := int32(3)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownTraitKind": {
+			reason: "Traits of kinds unknown to the scheme should return an error.",
+			args: args{
+				cw:     containerizedWorkload(),
+				traits: []trait.Trait{unknown},
+			},
+			want: want{err: errors.Wrap(errUnknown, errRenderTraitKind)},
+		},
+		"NoTraits": {
+			reason: "A workload without traits should be rendered as its translation.",
+			args: args{
+				cw: containerizedWorkload(),
+			},
+			want: want{},
+		},
+		"ManualScalerTrait": {
+			reason: "Traits should modify the rendered translation.",
+			args: args{
+				cw: containerizedWorkload(),
+				traits: []trait.Trait{&oamv1alpha2.ManualScalerTrait{
+					Spec: oamv1alpha2.ManualScalerTraitSpec{ReplicaCount: replicas},
+				}},
+			},
+			want: want{replicas: &replicas},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := Render(context.Background(), fake.NewFakeClientWithScheme(s), s, tc.args.cw, tc.args.traits...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRender(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if a.GetName() != cwName {
+				t.Errorf("\nReason: %s\nRender(...): want name %q, got %q", tc.reason, cwName, a.GetName())
+			}
+
+			var got *int32
+			_ = trait.DeploymentFromKubeAppAccessor(context.Background(), a, nil, func(_ context.Context, o runtime.Object, _ trait.Trait) error {
+				got = o.(*appsv1.Deployment).Spec.Replicas
+				return nil
+			})
+			if diff := cmp.Diff(tc.want.replicas, got); diff != "" {
+				t.Errorf("\nReason: %s\nRender(...): -want replicas, +got replicas:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	errParseReplicas    = "cannot parse replica count"
+	errNegativeReplicas = "replica count must not be negative"
+)
+
+// AnnotationReplicas may be set on a ContainerizedWorkload to specify the
+// replica count of its Deployment, which the ContainerizedWorkload schema does
+// not support. Its value is typically a parameter reference, so that each
+// ApplicationConfiguration may choose the replica count. The annotation is
+// copied to the Deployment so that the replica count can be restored when a
+// trait that scaled the Deployment is deleted.
+const AnnotationReplicas = "containerizedworkload.oam.crossplane.io/replicas"
+
+// replicas returns the replica count specified by the supplied object, or nil
+// if it does not specify one.
+func replicas(o metav1.Object) (*int32, error) {
+	raw, ok := o.GetAnnotations()[AnnotationReplicas]
+	if !ok {
+		return nil, nil
+	}
+
+	r, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseReplicas)
+	}
+	if r < 0 {
+		return nil, errors.New(errNegativeReplicas)
+	}
+	r32 := int32(r)
+	return &r32, nil
+}
+
+// setReplicas sets the replica count of the supplied Deployment, and records
+// it on the Deployment.
+func setReplicas(d *appsv1.Deployment, r *int32) {
+	d.Spec.Replicas = r
+	if r != nil {
+		meta.AddAnnotations(d, map[string]string{AnnotationReplicas: strconv.Itoa(int(*r))})
+	}
+}
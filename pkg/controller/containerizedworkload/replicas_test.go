@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestReplicas(t *testing.T) {
+	three := int32(3)
+
+	type want struct {
+		r   *int32
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "A workload without the annotation should not specify a replica count.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseError": {
+			reason: "An annotation that is not an integer should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationReplicas: "[fromParam(replicas)]"}},
+			want:   want{err: errors.Wrap(&strconv.NumError{Func: "ParseInt", Num: "[fromParam(replicas)]", Err: strconv.ErrSyntax}, errParseReplicas)},
+		},
+		"Negative": {
+			reason: "A negative replica count should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationReplicas: "-1"}},
+			want:   want{err: errors.New(errNegativeReplicas)},
+		},
+		"Success": {
+			reason: "The replica count should be returned.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationReplicas: "3"}},
+			want:   want{r: &three},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := replicas(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nreplicas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\nReason: %s\nreplicas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
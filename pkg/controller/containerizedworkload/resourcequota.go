@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotResourceQuotaTrait = "trait is not a resource quota trait"
+	errNoQuotaOrLimits       = "resource quota trait specifies neither a quota nor limits"
+	errSetQuotaTemplate      = "cannot add resource quota to KubernetesApplication"
+	errSetLimitRangeTemplate = "cannot add limit range to KubernetesApplication"
+)
+
+var (
+	resourceQuotaKind       = reflect.TypeOf(corev1.ResourceQuota{}).Name()
+	resourceQuotaAPIVersion = corev1.SchemeGroupVersion.String()
+	limitRangeKind          = reflect.TypeOf(corev1.LimitRange{}).Name()
+	limitRangeAPIVersion    = corev1.SchemeGroupVersion.String()
+)
+
+// SetupResourceQuotaTrait adds a controller that reconciles ResourceQuotaTraits
+// that reference a ContainerizedWorkload.
+func SetupResourceQuotaTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.ResourceQuotaTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.ResourceQuotaTrait{}, remotev1alpha1.ResourceQuotaTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.ResourceQuotaTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.ResourceQuotaTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.ModifyFn(resourceQuotaModifier)),
+		))))
+}
+
+// resourceQuotaModifier adds templates to the KubernetesApplication that
+// enforce the quota and limits of the trait in the remote namespace the
+// KubernetesApplication's resources are created in.
+func resourceQuotaModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	rqt, ok := t.(*remotev1alpha1.ResourceQuotaTrait)
+	if !ok {
+		return errors.New(errNotResourceQuotaTrait)
+	}
+
+	if rqt.Spec.Quota == nil && len(rqt.Spec.Limits) == 0 {
+		return errors.New(errNoQuotaOrLimits)
+	}
+
+	// Both objects are named after the trait. Templates are created in the
+	// KubernetesApplication's remote namespace because they do not specify
+	// one.
+	if rqt.Spec.Quota != nil {
+		rq := &corev1.ResourceQuota{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       resourceQuotaKind,
+				APIVersion: resourceQuotaAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: rqt.GetName(),
+			},
+			Spec: *rqt.Spec.Quota,
+		}
+		if err := trait.SetKubeAppTemplate(a, rq); err != nil {
+			return errors.Wrap(err, errSetQuotaTemplate)
+		}
+	}
+
+	if len(rqt.Spec.Limits) > 0 {
+		lr := &corev1.LimitRange{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       limitRangeKind,
+				APIVersion: limitRangeAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: rqt.GetName(),
+			},
+			Spec: corev1.LimitRangeSpec{Limits: rqt.Spec.Limits},
+		}
+		if err := trait.SetKubeAppTemplate(a, lr); err != nil {
+			return errors.Wrap(err, errSetLimitRangeTemplate)
+		}
+	}
+
+	return nil
+}
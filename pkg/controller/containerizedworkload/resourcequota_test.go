@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestResourceQuotaModifier(t *testing.T) {
+	quota := &corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}
+	limits := []corev1.LimitRangeItem{{
+		Type:           corev1.LimitTypeContainer,
+		DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}}
+
+	rqt := func(q *corev1.ResourceQuotaSpec, l []corev1.LimitRangeItem) *remotev1alpha1.ResourceQuotaTrait {
+		return &remotev1alpha1.ResourceQuotaTrait{
+			ObjectMeta: metav1.ObjectMeta{Name: "limited"},
+			Spec: remotev1alpha1.ResourceQuotaTraitSpec{
+				Quota:             q,
+				Limits:            l,
+				WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+			},
+		}
+	}
+
+	kubeApp := func(objs ...trait.Object) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, o := range objs {
+			_ = trait.SetKubeAppTemplate(a, o)
+		}
+		return a
+	}
+	rq := &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{Kind: resourceQuotaKind, APIVersion: resourceQuotaAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: "limited"},
+		Spec:       *quota,
+	}
+	lr := &corev1.LimitRange{
+		TypeMeta:   metav1.TypeMeta{Kind: limitRangeKind, APIVersion: limitRangeAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: "limited"},
+		Spec:       corev1.LimitRangeSpec{Limits: limits},
+	}
+
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to modifier that is not a KubernetesApplication should return error.",
+			args:   args{o: &appsv1.Deployment{}},
+			want:   want{o: &appsv1.Deployment{}, err: errors.New(errNotKubeApp)},
+		},
+		"ErrorTraitNotResourceQuotaTrait": {
+			reason: "Trait passed to modifier that is not a ResourceQuotaTrait should return error.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: &traitfake.Trait{}},
+			want:   want{o: &workloadv1alpha1.KubernetesApplication{}, err: errors.New(errNotResourceQuotaTrait)},
+		},
+		"ErrorNoQuotaOrLimits": {
+			reason: "A trait that specifies neither a quota nor limits should return error.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: rqt(nil, nil)},
+			want:   want{o: &workloadv1alpha1.KubernetesApplication{}, err: errors.New(errNoQuotaOrLimits)},
+		},
+		"QuotaOnly": {
+			reason: "A trait that specifies only a quota should add only a ResourceQuota.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: rqt(quota, nil)},
+			want:   want{o: kubeApp(rq)},
+		},
+		"QuotaAndLimits": {
+			reason: "A trait that specifies a quota and limits should add a ResourceQuota and a LimitRange.",
+			args:   args{o: &workloadv1alpha1.KubernetesApplication{}, t: rqt(quota, limits)},
+			want:   want{o: kubeApp(rq, lr)},
+		},
+		"Replaced": {
+			reason: "Templates added by a previous modification should be replaced rather than duplicated.",
+			args:   args{o: kubeApp(rq, lr), t: rqt(quota, limits)},
+			want:   want{o: kubeApp(rq, lr)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := resourceQuotaModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nresourceQuotaModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nresourceQuotaModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
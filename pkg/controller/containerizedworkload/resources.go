@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/validation"
+)
+
+const (
+	errParseResourceLimits  = "cannot parse resource limits"
+	errExtendedResource     = "extended resource must be a quantity"
+	errNoContainerForLimits = "no container found for resource limits"
+)
+
+// ResourceGPU is the extended resource GPUs required by a ContainerizedWorkload
+// are requested as.
+const ResourceGPU corev1.ResourceName = "nvidia.com/gpu"
+
+// AnnotationResourceLimits may be set on a ContainerizedWorkload to specify
+// the resource limits of its containers, which the ContainerizedWorkload
+// schema does not support. Its value is a JSON object mapping container names
+// to Kubernetes ResourceLists, for example {"web":{"cpu":"1","memory":"1Gi"}}.
+const AnnotationResourceLimits = "containerizedworkload.oam.crossplane.io/resource-limits"
+
+// resourceRequirements returns the Kubernetes resource requirements of the
+// supplied OAM container resources. Kubernetes does not allow extended
+// resources to be overcommitted, so they are limited to what is required.
+func resourceRequirements(r *oamv1alpha2.ContainerResources) (corev1.ResourceRequirements, error) {
+	rr := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    r.CPU.Required,
+			corev1.ResourceMemory: r.Memory.Required,
+		},
+	}
+
+	extended := corev1.ResourceList{}
+	if r.GPU != nil {
+		extended[ResourceGPU] = r.GPU.Required
+	}
+	for _, e := range r.Extended {
+		q, err := validation.ParseQuantity(e.Required)
+		if err != nil {
+			return corev1.ResourceRequirements{}, errors.Wrapf(err, "%s: %s", errExtendedResource, e.Name)
+		}
+		extended[corev1.ResourceName(e.Name)] = q
+	}
+	if len(extended) == 0 {
+		return rr, nil
+	}
+
+	rr.Limits = corev1.ResourceList{}
+	for name, q := range extended {
+		rr.Requests[name] = q
+		rr.Limits[name] = q
+	}
+	return rr, nil
+}
+
+// resourceLimits returns the resource limits of each container of the
+// supplied workload.
+func resourceLimits(o metav1.Object) (map[string]corev1.ResourceList, error) {
+	raw, ok := o.GetAnnotations()[AnnotationResourceLimits]
+	if !ok {
+		return nil, nil
+	}
+
+	limits := map[string]corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(raw), &limits); err != nil {
+		return nil, errors.Wrap(err, errParseResourceLimits)
+	}
+	return limits, nil
+}
+
+// setResourceLimits adds the supplied resource limits to the containers of the
+// supplied pod spec, replacing any existing limits of the same resource.
+func setResourceLimits(ps *corev1.PodSpec, limits map[string]corev1.ResourceList) error {
+	for name, l := range limits {
+		found := false
+		for i := range ps.Containers {
+			c := &ps.Containers[i]
+			if c.Name != name {
+				continue
+			}
+			if c.Resources.Limits == nil {
+				c.Resources.Limits = corev1.ResourceList{}
+			}
+			for r, q := range l {
+				c.Resources.Limits[r] = q
+			}
+			found = true
+		}
+		if !found {
+			return errors.Errorf("%s: %s", errNoContainerForLimits, name)
+		}
+	}
+	return nil
+}
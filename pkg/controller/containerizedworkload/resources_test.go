@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+func TestResourceRequirements(t *testing.T) {
+	type want struct {
+		rr  corev1.ResourceRequirements
+		err error
+	}
+
+	cpu := resource.MustParse("500m")
+	mem := resource.MustParse("1Gi")
+	one := resource.MustParse("1")
+
+	cases := map[string]struct {
+		reason string
+		r      *oamv1alpha2.ContainerResources
+		want   want
+	}{
+		"CPUAndMemory": {
+			reason: "Required CPU and memory should be requested, but not limited.",
+			r: &oamv1alpha2.ContainerResources{
+				CPU:    oamv1alpha2.CPUResources{Required: cpu},
+				Memory: oamv1alpha2.MemoryResources{Required: mem},
+			},
+			want: want{rr: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: cpu, corev1.ResourceMemory: mem},
+			}},
+		},
+		"GPU": {
+			reason: "Required GPUs should be both requested and limited.",
+			r: &oamv1alpha2.ContainerResources{
+				CPU:    oamv1alpha2.CPUResources{Required: cpu},
+				Memory: oamv1alpha2.MemoryResources{Required: mem},
+				GPU:    &oamv1alpha2.GPUResources{Required: one},
+			},
+			want: want{rr: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: cpu, corev1.ResourceMemory: mem, ResourceGPU: one},
+				Limits:   corev1.ResourceList{ResourceGPU: one},
+			}},
+		},
+		"Extended": {
+			reason: "Required extended resources should be both requested and limited.",
+			r: &oamv1alpha2.ContainerResources{
+				CPU:      oamv1alpha2.CPUResources{Required: cpu},
+				Memory:   oamv1alpha2.MemoryResources{Required: mem},
+				Extended: []oamv1alpha2.ExtendedResource{{Name: "example.com/widget", Required: intstr.FromInt(1)}},
+			},
+			want: want{rr: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: cpu, corev1.ResourceMemory: mem, "example.com/widget": one},
+				Limits:   corev1.ResourceList{"example.com/widget": one},
+			}},
+		},
+		"ExtendedNotQuantity": {
+			reason: "Extended resources that are not quantities should return an error.",
+			r: &oamv1alpha2.ContainerResources{
+				Extended: []oamv1alpha2.ExtendedResource{{Name: "example.com/widget", Required: intstr.FromString("very-cool-widget")}},
+			},
+			want: want{err: errors.Wrapf(resource.ErrFormatWrong, "%s: %s", errExtendedResource, "example.com/widget")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resourceRequirements(tc.r)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nresourceRequirements(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.rr, got); diff != "" {
+				t.Errorf("\nReason: %s\nresourceRequirements(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	type want struct {
+		limits map[string]corev1.ResourceList
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "A workload without the annotation should have no resource limits.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"ParseError": {
+			reason: "An annotation that is not valid JSON should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationResourceLimits: "{"}},
+			want:   want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseResourceLimits)},
+		},
+		"Success": {
+			reason: "The resource limits of each container should be returned.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationResourceLimits: `{"cool":{"cpu":"1","memory":"1Gi"}}`}},
+			want: want{limits: map[string]corev1.ResourceList{"cool": {
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resourceLimits(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nresourceLimits(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.limits, got); diff != "" {
+				t.Errorf("\nReason: %s\nresourceLimits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetResourceLimits(t *testing.T) {
+	type args struct {
+		ps     *corev1.PodSpec
+		limits map[string]corev1.ResourceList
+	}
+
+	type want struct {
+		ps  *corev1.PodSpec
+		err error
+	}
+
+	one := resource.MustParse("1")
+	two := resource.MustParse("2")
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoContainer": {
+			reason: "Resource limits for a container that does not exist should return an error.",
+			args: args{
+				ps:     &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				limits: map[string]corev1.ResourceList{"cool": {corev1.ResourceCPU: one}},
+			},
+			want: want{
+				ps:  &corev1.PodSpec{Containers: []corev1.Container{{Name: "other"}}},
+				err: errors.Errorf("%s: %s", errNoContainerForLimits, "cool"),
+			},
+		},
+		"MergeLimits": {
+			reason: "Resource limits should be added to any existing limits, replacing limits of the same resource.",
+			args: args{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: one, ResourceGPU: one},
+				}}}},
+				limits: map[string]corev1.ResourceList{"cool": {corev1.ResourceCPU: two, corev1.ResourceMemory: one}},
+			},
+			want: want{
+				ps: &corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: two, corev1.ResourceMemory: one, ResourceGPU: one},
+				}}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setResourceLimits(tc.args.ps, tc.args.limits)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsetResourceLimits(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.ps, tc.args.ps); diff != "" {
+				t.Errorf("\nReason: %s\nsetResourceLimits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotSecurityContextTrait = "trait is not a security context trait"
+)
+
+// SetupSecurityContextTrait adds a controller that reconciles
+// SecurityContextTraits that reference a ContainerizedWorkload.
+func SetupSecurityContextTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.SecurityContextTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.SecurityContextTrait{}, remotev1alpha1.SecurityContextTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.SecurityContextTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.SecurityContextTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.NewWorkloadModifierWithAccessor(securityContextModifier, trait.DeploymentFromKubeAppAccessor)),
+		))))
+}
+
+// securityContextModifier sets the security contexts of the pods and of every
+// container of the packaged Deployment. Containers are set too, because their
+// security contexts take precedence over those of their pods.
+func securityContextModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New(errNotDeployment)
+	}
+
+	st, ok := t.(*remotev1alpha1.SecurityContextTrait)
+	if !ok {
+		return errors.New(errNotSecurityContextTrait)
+	}
+
+	ps := &d.Spec.Template.Spec
+	if st.Spec.RunAsNonRoot != nil {
+		if ps.SecurityContext == nil {
+			ps.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		ps.SecurityContext.RunAsNonRoot = boolPtr(*st.Spec.RunAsNonRoot)
+	}
+
+	// Kubernetes 1.17 has no seccompProfile field, so seccomp profiles are set
+	// using annotations.
+	if st.Spec.SeccompProfile != nil {
+		meta.AddAnnotations(&d.Spec.Template, map[string]string{corev1.SeccompPodAnnotationKey: *st.Spec.SeccompProfile})
+	}
+
+	for _, cs := range [][]corev1.Container{ps.InitContainers, ps.Containers} {
+		for i := range cs {
+			setContainerSecurityContext(&cs[i], st.Spec)
+			if st.Spec.SeccompProfile != nil {
+				meta.AddAnnotations(&d.Spec.Template, map[string]string{corev1.SeccompContainerAnnotationKeyPrefix + cs[i].Name: *st.Spec.SeccompProfile})
+			}
+		}
+	}
+
+	return nil
+}
+
+func setContainerSecurityContext(c *corev1.Container, s remotev1alpha1.SecurityContextTraitSpec) {
+	if s.RunAsNonRoot == nil && s.ReadOnlyRootFilesystem == nil && len(s.DropCapabilities) == 0 {
+		return
+	}
+	if c.SecurityContext == nil {
+		c.SecurityContext = &corev1.SecurityContext{}
+	}
+	sc := c.SecurityContext
+
+	if s.RunAsNonRoot != nil {
+		sc.RunAsNonRoot = boolPtr(*s.RunAsNonRoot)
+	}
+	if s.ReadOnlyRootFilesystem != nil {
+		sc.ReadOnlyRootFilesystem = boolPtr(*s.ReadOnlyRootFilesystem)
+	}
+	if len(s.DropCapabilities) == 0 {
+		return
+	}
+	if sc.Capabilities == nil {
+		sc.Capabilities = &corev1.Capabilities{}
+	}
+	for _, cp := range s.DropCapabilities {
+		if !hasCapability(sc.Capabilities.Drop, cp) {
+			sc.Capabilities.Drop = append(sc.Capabilities.Drop, cp)
+		}
+	}
+}
+
+func hasCapability(caps []corev1.Capability, c corev1.Capability) bool {
+	for _, cp := range caps {
+		if cp == c {
+			return true
+		}
+	}
+	return false
+}
+
+func boolPtr(b bool) *bool { return &b }
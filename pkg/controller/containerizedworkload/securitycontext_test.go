@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestSecurityContextModifier(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	profile := "runtime/default"
+	st := &remotev1alpha1.SecurityContextTrait{
+		Spec: remotev1alpha1.SecurityContextTraitSpec{
+			WorkloadReference:      oamv1alpha2.WorkloadReference{Name: cwName},
+			RunAsNonRoot:           boolPtr(true),
+			ReadOnlyRootFilesystem: boolPtr(true),
+			SeccompProfile:         &profile,
+			DropCapabilities:       []corev1.Capability{"ALL"},
+		},
+	}
+
+	baseline := &corev1.SecurityContext{
+		RunAsNonRoot:           boolPtr(true),
+		ReadOnlyRootFilesystem: boolPtr(true),
+		Capabilities:           &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to modifier that is not a Deployment should return error.",
+			args: args{
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotSecurityContextTrait": {
+			reason: "Trait passed to modifier that is not a SecurityContextTrait should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotSecurityContextTrait)},
+		},
+		"Success": {
+			reason: "The security contexts of the pod and its containers should be set.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+				t: st,
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{Name: "cool-container", SecurityContext: baseline}),
+					func(d *appsv1.Deployment) {
+						d.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsNonRoot: boolPtr(true)}
+						d.Spec.Template.SetAnnotations(map[string]string{
+							corev1.SeccompPodAnnotationKey:                                profile,
+							corev1.SeccompContainerAnnotationKeyPrefix + "cool-container": profile,
+						})
+					},
+				),
+			},
+		},
+		"MergeExisting": {
+			reason: "Existing security contexts should be overridden, and capabilities they drop should be kept.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{
+					Name: "cool-container",
+					SecurityContext: &corev1.SecurityContext{
+						RunAsNonRoot: boolPtr(false),
+						Capabilities: &corev1.Capabilities{
+							Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+							Drop: []corev1.Capability{"NET_RAW", "ALL"},
+						},
+					},
+				})),
+				t: &remotev1alpha1.SecurityContextTrait{
+					Spec: remotev1alpha1.SecurityContextTraitSpec{
+						RunAsNonRoot:     boolPtr(true),
+						DropCapabilities: []corev1.Capability{"ALL"},
+					},
+				},
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{
+						Name: "cool-container",
+						SecurityContext: &corev1.SecurityContext{
+							RunAsNonRoot: boolPtr(true),
+							Capabilities: &corev1.Capabilities{
+								Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+								Drop: []corev1.Capability{"NET_RAW", "ALL"},
+							},
+						},
+					}),
+					func(d *appsv1.Deployment) {
+						d.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsNonRoot: boolPtr(true)}
+					},
+				),
+			},
+		},
+		"NothingToSet": {
+			reason: "A trait that sets nothing should leave the Deployment unchanged.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+				t: &remotev1alpha1.SecurityContextTrait{},
+			},
+			want: want{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := securityContextModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsecurityContextModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nsecurityContextModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
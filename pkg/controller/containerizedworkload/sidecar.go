@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotSidecarInjectionTrait = "trait is not a sidecar injection trait"
+	errNoVolumeForSidecar       = "no volume found for sidecar volume mount"
+)
+
+// SetupSidecarInjectionTrait adds a controller that reconciles
+// SidecarInjectionTraits that reference a ContainerizedWorkload.
+func SetupSidecarInjectionTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.SidecarInjectionTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.SidecarInjectionTrait{}, remotev1alpha1.SidecarInjectionTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.SidecarInjectionTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.SidecarInjectionTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.NewWorkloadModifierWithAccessor(sidecarInjectionModifier, trait.DeploymentFromKubeAppAccessor)),
+		))))
+}
+
+// sidecarInjectionModifier adds the volumes and sidecar containers of the
+// trait to the packaged Deployment. Sidecars replace any existing container of
+// the same name, so that injecting them again is idempotent.
+func sidecarInjectionModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New(errNotDeployment)
+	}
+
+	st, ok := t.(*remotev1alpha1.SidecarInjectionTrait)
+	if !ok {
+		return errors.New(errNotSidecarInjectionTrait)
+	}
+
+	ps := &d.Spec.Template.Spec
+	for _, v := range st.Spec.Volumes {
+		setPodVolume(ps, v)
+	}
+
+	for _, c := range st.Spec.Sidecars {
+		for _, m := range c.VolumeMounts {
+			if !hasPodVolume(ps, m.Name) {
+				return errors.Errorf("%s: %s", errNoVolumeForSidecar, m.Name)
+			}
+		}
+		setContainer(ps, c)
+	}
+
+	return nil
+}
+
+func hasPodVolume(ps *corev1.PodSpec, name string) bool {
+	for _, v := range ps.Volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setContainer adds or replaces the supplied container in the pod spec.
+func setContainer(ps *corev1.PodSpec, c corev1.Container) {
+	for i := range ps.Containers {
+		if ps.Containers[i].Name == c.Name {
+			ps.Containers[i] = c
+			return
+		}
+	}
+	ps.Containers = append(ps.Containers, c)
+}
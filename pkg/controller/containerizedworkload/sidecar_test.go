@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestSidecarInjectionModifier(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	logs := corev1.Volume{Name: "logs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	shipper := corev1.Container{
+		Name:         "log-shipper",
+		Image:        "fluent-bit:1.4",
+		VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/var/log/app"}},
+	}
+
+	st := &remotev1alpha1.SidecarInjectionTrait{
+		Spec: remotev1alpha1.SidecarInjectionTraitSpec{
+			WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+			Sidecars:          []corev1.Container{shipper},
+			Volumes:           []corev1.Volume{logs},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to modifier that is not a Deployment should return error.",
+			args: args{
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotSidecarInjectionTrait": {
+			reason: "Trait passed to modifier that is not a SidecarInjectionTrait should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotSidecarInjectionTrait)},
+		},
+		"ErrorNoVolume": {
+			reason: "A sidecar that mounts a volume the pod does not have should return error.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+				t: &remotev1alpha1.SidecarInjectionTrait{
+					Spec: remotev1alpha1.SidecarInjectionTraitSpec{Sidecars: []corev1.Container{shipper}},
+				},
+			},
+			want: want{
+				o:   deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+				err: errors.Errorf("%s: %s", errNoVolumeForSidecar, "logs"),
+			},
+		},
+		"Success": {
+			reason: "Volumes and sidecars should be added to the pod.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"})),
+				t: st,
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{Name: "cool-container"}),
+					dmWithContainer(shipper),
+					func(d *appsv1.Deployment) { d.Spec.Template.Spec.Volumes = []corev1.Volume{logs} },
+				),
+			},
+		},
+		"ReplaceExisting": {
+			reason: "A sidecar should replace an existing container of the same name.",
+			args: args{
+				o: deployment(
+					dmWithContainer(corev1.Container{Name: "cool-container"}),
+					dmWithContainer(corev1.Container{Name: "log-shipper", Image: "fluent-bit:1.3"}),
+					func(d *appsv1.Deployment) { d.Spec.Template.Spec.Volumes = []corev1.Volume{logs} },
+				),
+				t: st,
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{Name: "cool-container"}),
+					dmWithContainer(shipper),
+					func(d *appsv1.Deployment) { d.Spec.Template.Spec.Volumes = []corev1.Volume{logs} },
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := sidecarInjectionModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsidecarInjectionModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nsidecarInjectionModifier(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errUnknownSpread       = "unknown spread topology"
+	errUnknownAntiAffinity = "unknown anti-affinity"
+	errDaemonSetSpread     = "a ContainerizedWorkload in DaemonSet mode runs one pod per node, and must not specify a spread or anti-affinity"
+)
+
+// AnnotationSpread may be set on a ContainerizedWorkload to a comma separated
+// list of the topologies, zone and host, across which its pods should be
+// spread evenly. Pods are spread on a best effort basis; the remote cluster
+// schedules them even if it cannot spread them.
+const AnnotationSpread = "containerizedworkload.oam.crossplane.io/spread"
+
+// AnnotationAntiAffinity may be set on a ContainerizedWorkload to avoid
+// scheduling more than one of its pods to the same node. Soft anti-affinity
+// is a preference, while hard anti-affinity leaves pods unscheduled rather
+// than sharing a node.
+const AnnotationAntiAffinity = "containerizedworkload.oam.crossplane.io/anti-affinity"
+
+// Topologies across which the pods of a ContainerizedWorkload may be spread.
+const (
+	SpreadZone = "zone"
+	SpreadHost = "host"
+)
+
+// Anti-affinities of the pods of a ContainerizedWorkload.
+const (
+	AntiAffinitySoft = "soft"
+	AntiAffinityHard = "hard"
+)
+
+// labelZone is the well-known label of the zone of a node.
+const labelZone = "topology.kubernetes.io/zone"
+
+var spreadTopologyKeys = map[string]string{
+	SpreadZone: labelZone,
+	SpreadHost: corev1.LabelHostname,
+}
+
+// A podSpread is the settings that spread the pods of a workload across the
+// nodes of the remote cluster.
+type podSpread struct {
+	topologyKeys []string
+	antiAffinity string
+}
+
+// spread returns the settings that spread the pods of the supplied workload.
+func spread(o metav1.Object) (podSpread, error) {
+	a := o.GetAnnotations()
+	s := podSpread{}
+
+	for _, t := range strings.Split(a[AnnotationSpread], ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		k, ok := spreadTopologyKeys[t]
+		if !ok {
+			return podSpread{}, errors.Errorf("%s: %s", errUnknownSpread, t)
+		}
+		s.topologyKeys = append(s.topologyKeys, k)
+	}
+
+	switch aa := a[AnnotationAntiAffinity]; aa {
+	case "", AntiAffinitySoft, AntiAffinityHard:
+		s.antiAffinity = aa
+	default:
+		return podSpread{}, errors.Errorf("%s: %s", errUnknownAntiAffinity, aa)
+	}
+
+	return s, nil
+}
+
+// empty returns true if the supplied settings do not spread pods.
+func (s podSpread) empty() bool {
+	return len(s.topologyKeys) == 0 && s.antiAffinity == ""
+}
+
+// setSpread sets the settings that spread the pods of the supplied pod spec.
+// Pods are spread relative to the other pods matched by the supplied
+// selector, i.e. the other pods of the same workload.
+func setSpread(ps *corev1.PodSpec, s podSpread, selector *metav1.LabelSelector) {
+	for _, k := range s.topologyKeys {
+		ps.TopologySpreadConstraints = append(ps.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       k,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     selector.DeepCopy(),
+		})
+	}
+
+	term := corev1.PodAffinityTerm{LabelSelector: selector.DeepCopy(), TopologyKey: corev1.LabelHostname}
+	switch s.antiAffinity {
+	case AntiAffinitySoft:
+		ps.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}},
+		}}
+	case AntiAffinityHard:
+		ps.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSpread(t *testing.T) {
+	type want struct {
+		s   podSpread
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   want
+	}{
+		"NoAnnotations": {
+			reason: "A workload without the annotations should not spread its pods.",
+			o:      &metav1.ObjectMeta{},
+			want:   want{},
+		},
+		"UnknownSpread": {
+			reason: "A spread annotation that names an unknown topology should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationSpread: "zone,rack"}},
+			want:   want{err: errors.Errorf("%s: %s", errUnknownSpread, "rack")},
+		},
+		"UnknownAntiAffinity": {
+			reason: "An anti-affinity annotation that is not soft or hard should return an error.",
+			o:      &metav1.ObjectMeta{Annotations: map[string]string{AnnotationAntiAffinity: "strict"}},
+			want:   want{err: errors.Errorf("%s: %s", errUnknownAntiAffinity, "strict")},
+		},
+		"Success": {
+			reason: "The topology keys and anti-affinity of the workload should be returned.",
+			o: &metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationSpread:       "zone, host",
+				AnnotationAntiAffinity: AntiAffinitySoft,
+			}},
+			want: want{s: podSpread{
+				topologyKeys: []string{labelZone, corev1.LabelHostname},
+				antiAffinity: AntiAffinitySoft,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := spread(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nspread(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, got, cmp.AllowUnexported(podSpread{})); diff != "" {
+				t.Errorf("\nReason: %s\nspread(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetSpread(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{labelKey: "cool-uid"}}
+	term := corev1.PodAffinityTerm{LabelSelector: selector, TopologyKey: corev1.LabelHostname}
+
+	cases := map[string]struct {
+		reason string
+		s      podSpread
+		want   *corev1.PodSpec
+	}{
+		"NoSpread": {
+			reason: "Pods that are not spread should be left unchanged.",
+			want:   &corev1.PodSpec{},
+		},
+		"Spread": {
+			reason: "Pods should be spread across each topology on a best effort basis.",
+			s:      podSpread{topologyKeys: []string{labelZone}},
+			want: &corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       labelZone,
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     selector,
+			}}},
+		},
+		"SoftAntiAffinity": {
+			reason: "Pods with soft anti-affinity should prefer not to share a node.",
+			s:      podSpread{antiAffinity: AntiAffinitySoft},
+			want: &corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}},
+			}}},
+		},
+		"HardAntiAffinity": {
+			reason: "Pods with hard anti-affinity should be required not to share a node.",
+			s:      podSpread{antiAffinity: AntiAffinityHard},
+			want: &corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ps := &corev1.PodSpec{}
+			setSpread(ps, tc.s, selector)
+
+			if diff := cmp.Diff(tc.want, ps); diff != "" {
+				t.Errorf("\nReason: %s\nsetSpread(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
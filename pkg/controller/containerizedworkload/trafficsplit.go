@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotTrafficSplitTrait = "trait is not a traffic split trait"
+	errNoTrafficSplitRoot   = "no service to split traffic for"
+	errNoBackends           = "no backends to split traffic between"
+	errNegativeWeight       = "backend weight must not be negative"
+	errIstioWeights         = "Istio backend weights must sum to 100"
+	errUnknownMesh          = "unknown service mesh"
+	errSetSplitTemplate     = "cannot add traffic split to KubernetesApplication"
+)
+
+// The remote kinds produced by the traffic split trait.
+var (
+	smiTrafficSplitGroupVersionKind     = schema.GroupVersionKind{Group: "split.smi-spec.io", Version: "v1alpha2", Kind: "TrafficSplit"}
+	istioVirtualServiceGroupVersionKind = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"}
+)
+
+// SetupTrafficSplitTrait adds a controller that reconciles TrafficSplitTraits
+// that reference a ContainerizedWorkload.
+func SetupTrafficSplitTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.TrafficSplitTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.TrafficSplitTrait{}, remotev1alpha1.TrafficSplitTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.TrafficSplitTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.TrafficSplitTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.ModifyFn(trafficSplitModifier)),
+		))))
+}
+
+// trafficSplitModifier adds a template to the KubernetesApplication that
+// splits the traffic sent to the workload's Service between the backends of
+// the trait, using either an SMI TrafficSplit or an Istio VirtualService.
+func trafficSplitModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	ts, ok := t.(*remotev1alpha1.TrafficSplitTrait)
+	if !ok {
+		return errors.New(errNotTrafficSplitTrait)
+	}
+
+	split, err := trafficSplit(ts)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(trait.SetKubeAppTemplate(a, split), errSetSplitTemplate)
+}
+
+// trafficSplit returns the remote object that implements the supplied trait.
+// The object is named after the trait.
+func trafficSplit(ts *remotev1alpha1.TrafficSplitTrait) (*unstructured.Unstructured, error) {
+	root := ts.Spec.Service
+	if root == "" {
+		root = ts.Spec.WorkloadReference.Name
+	}
+	if root == "" {
+		return nil, errors.New(errNoTrafficSplitRoot)
+	}
+	if len(ts.Spec.Backends) == 0 {
+		return nil, errors.New(errNoBackends)
+	}
+
+	var total int32
+	for _, b := range ts.Spec.Backends {
+		if b.Weight < 0 {
+			return nil, errors.Errorf("%s: %s", errNegativeWeight, b.Service)
+		}
+		total += b.Weight
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	switch ts.Spec.Mesh {
+	case remotev1alpha1.TrafficSplitMeshSMI:
+		backends := make([]interface{}, len(ts.Spec.Backends))
+		for i, b := range ts.Spec.Backends {
+			backends[i] = map[string]interface{}{
+				"service": b.Service,
+				"weight":  int64(b.Weight),
+			}
+		}
+		u.SetGroupVersionKind(smiTrafficSplitGroupVersionKind)
+		u.Object["spec"] = map[string]interface{}{
+			"service":  root,
+			"backends": backends,
+		}
+	case remotev1alpha1.TrafficSplitMeshIstio:
+		if total != 100 {
+			return nil, errors.New(errIstioWeights)
+		}
+		routes := make([]interface{}, len(ts.Spec.Backends))
+		for i, b := range ts.Spec.Backends {
+			routes[i] = map[string]interface{}{
+				"destination": map[string]interface{}{"host": b.Service},
+				"weight":      int64(b.Weight),
+			}
+		}
+		u.SetGroupVersionKind(istioVirtualServiceGroupVersionKind)
+		u.Object["spec"] = map[string]interface{}{
+			"hosts": []interface{}{root},
+			"http":  []interface{}{map[string]interface{}{"route": routes}},
+		}
+	default:
+		return nil, errors.Errorf("%s: %q", errUnknownMesh, ts.Spec.Mesh)
+	}
+
+	u.SetName(ts.GetName())
+	return u, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func trafficSplitTrait(mesh remotev1alpha1.TrafficSplitMesh, b ...remotev1alpha1.TrafficSplitBackend) *remotev1alpha1.TrafficSplitTrait {
+	return &remotev1alpha1.TrafficSplitTrait{
+		ObjectMeta: metav1.ObjectMeta{Name: "canary"},
+		Spec: remotev1alpha1.TrafficSplitTraitSpec{
+			Mesh:              mesh,
+			Backends:          b,
+			WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+		},
+	}
+}
+
+func TestTrafficSplitModifier(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ErrorObjectNotKubeApp": {
+			reason: "Object passed to modifier that is not a KubernetesApplication should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+			},
+			want: errors.New(errNotKubeApp),
+		},
+		"ErrorTraitNotTrafficSplitTrait": {
+			reason: "Trait passed to modifier that is not a TrafficSplitTrait should return error.",
+			args: args{
+				o: &workloadv1alpha1.KubernetesApplication{},
+				t: &traitfake.Trait{},
+			},
+			want: errors.New(errNotTrafficSplitTrait),
+		},
+		"ErrorInvalidTrait": {
+			reason: "A trait that cannot be translated into a traffic split should return error.",
+			args: args{
+				o: &workloadv1alpha1.KubernetesApplication{},
+				t: trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI),
+			},
+			want: errors.New(errNoBackends),
+		},
+		"Success": {
+			reason: "A valid trait should be added to the KubernetesApplication.",
+			args: args{
+				o: &workloadv1alpha1.KubernetesApplication{},
+				t: trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI, remotev1alpha1.TrafficSplitBackend{Service: cwName, Weight: 1}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := trafficSplitModifier(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntrafficSplitModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTrafficSplit(t *testing.T) {
+	type want struct {
+		u   *unstructured.Unstructured
+		err error
+	}
+
+	canary := remotev1alpha1.TrafficSplitBackend{Service: "canary", Weight: 10}
+	stable := remotev1alpha1.TrafficSplitBackend{Service: cwName, Weight: 90}
+
+	cases := map[string]struct {
+		reason string
+		ts     *remotev1alpha1.TrafficSplitTrait
+		want   want
+	}{
+		"ErrorNoRoot": {
+			reason: "A trait with no service and no workload reference should return error.",
+			ts: func() *remotev1alpha1.TrafficSplitTrait {
+				ts := trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI, stable)
+				ts.Spec.WorkloadReference = oamv1alpha2.WorkloadReference{}
+				return ts
+			}(),
+			want: want{err: errors.New(errNoTrafficSplitRoot)},
+		},
+		"ErrorNoBackends": {
+			reason: "A trait with no backends should return error.",
+			ts:     trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI),
+			want:   want{err: errors.New(errNoBackends)},
+		},
+		"ErrorNegativeWeight": {
+			reason: "A backend with a negative weight should return error.",
+			ts:     trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI, remotev1alpha1.TrafficSplitBackend{Service: "canary", Weight: -1}),
+			want:   want{err: errors.Errorf("%s: %s", errNegativeWeight, "canary")},
+		},
+		"ErrorIstioWeights": {
+			reason: "Istio backend weights that do not sum to 100 should return error.",
+			ts:     trafficSplitTrait(remotev1alpha1.TrafficSplitMeshIstio, canary),
+			want:   want{err: errors.New(errIstioWeights)},
+		},
+		"ErrorUnknownMesh": {
+			reason: "A trait for an unknown mesh should return error.",
+			ts:     trafficSplitTrait("Linkerd", stable),
+			want:   want{err: errors.Errorf("%s: %q", errUnknownMesh, "Linkerd")},
+		},
+		"SMI": {
+			reason: "An SMI trait should produce a TrafficSplit for the workload's Service.",
+			ts:     trafficSplitTrait(remotev1alpha1.TrafficSplitMeshSMI, stable, canary),
+			want: want{u: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "split.smi-spec.io/v1alpha2",
+				"kind":       "TrafficSplit",
+				"metadata":   map[string]interface{}{"name": "canary"},
+				"spec": map[string]interface{}{
+					"service": cwName,
+					"backends": []interface{}{
+						map[string]interface{}{"service": cwName, "weight": int64(90)},
+						map[string]interface{}{"service": "canary", "weight": int64(10)},
+					},
+				},
+			}}},
+		},
+		"Istio": {
+			reason: "An Istio trait should produce a VirtualService for the named Service.",
+			ts: func() *remotev1alpha1.TrafficSplitTrait {
+				ts := trafficSplitTrait(remotev1alpha1.TrafficSplitMeshIstio, stable, canary)
+				ts.Spec.Service = "frontend"
+				return ts
+			}(),
+			want: want{u: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "networking.istio.io/v1alpha3",
+				"kind":       "VirtualService",
+				"metadata":   map[string]interface{}{"name": "canary"},
+				"spec": map[string]interface{}{
+					"hosts": []interface{}{"frontend"},
+					"http": []interface{}{map[string]interface{}{"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": cwName}, "weight": int64(90)},
+						map[string]interface{}{"destination": map[string]interface{}{"host": "canary"}, "weight": int64(10)},
+					}}},
+				},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := trafficSplit(tc.ts)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntrafficSplit(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.u, u); diff != "" {
+				t.Errorf("\nReason: %s\ntrafficSplit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
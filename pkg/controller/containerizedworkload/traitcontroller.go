@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+)
+
+const (
+	errIndexTraits   = "cannot index traits by workload reference"
+	errIndexPackages = "cannot index packages by workload"
+)
+
+// packageIndexes records the managers whose caches index
+// KubernetesApplications by the workload that controls them. Every trait
+// controller looks packages up in the index, but it may only be added to each
+// manager once.
+var packageIndexes = struct {
+	sync.Mutex
+	added map[ctrl.Manager]bool
+}{added: map[ctrl.Manager]bool{}}
+
+// indexPackages adds the trait.IndexPackageControllers field index of
+// KubernetesApplications to the supplied manager, unless it was already
+// added.
+func indexPackages(mgr ctrl.Manager) error {
+	packageIndexes.Lock()
+	defer packageIndexes.Unlock()
+	if packageIndexes.added[mgr] {
+		return nil
+	}
+	if err := trait.AddPackageControllerIndex(mgr.GetFieldIndexer(), &workloadv1alpha1.KubernetesApplication{}); err != nil {
+		return errors.Wrap(err, errIndexPackages)
+	}
+	packageIndexes.added[mgr] = true
+	return nil
+}
+
+// newTraitControllerBuilder returns a builder for a controller that reconciles
+// the supplied kind of trait when either the trait or the KubernetesApplication
+// its workload is packaged as changes. The controller's reconciler may find
+// packages using the trait.WithPackageIndex option.
+func newTraitControllerBuilder(mgr ctrl.Manager, t trait.Trait, of schema.GroupVersionKind) (*builder.Builder, error) {
+	if err := trait.AddWorkloadReferenceIndex(mgr.GetFieldIndexer(), t); err != nil {
+		return nil, errors.Wrap(err, errIndexTraits)
+	}
+	if err := indexPackages(mgr); err != nil {
+		return nil, err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(t).
+		Watches(&source.Kind{Type: &workloadv1alpha1.KubernetesApplication{}}, trait.EnqueueRequestsForReferencingTraits(mgr.GetClient(), mgr.GetScheme(), trait.Kind(of))), nil
+}
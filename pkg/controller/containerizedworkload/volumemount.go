@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotKubeApp           = "object to be modified is not a KubernetesApplication"
+	errNotVolumeMountTrait  = "trait is not a volume mount trait"
+	errSetClaimTemplate     = "cannot add persistent volume claim to KubernetesApplication"
+	errNoContainerForVolume = "no container found for volume"
+)
+
+var (
+	pvcKind       = reflect.TypeOf(corev1.PersistentVolumeClaim{}).Name()
+	pvcAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// SetupVolumeMountTrait adds a controller that reconciles VolumeMountTraits
+// that reference a ContainerizedWorkload.
+func SetupVolumeMountTrait(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.VolumeMountTraitGroupKind)
+
+	b, err := newTraitControllerBuilder(mgr, &remotev1alpha1.VolumeMountTrait{}, remotev1alpha1.VolumeMountTraitGroupVersionKind)
+	if err != nil {
+		return err
+	}
+
+	return b.
+		Named(name).
+		WithOptions(o.ForController()).
+		Complete(o.Wrap(withDeadLetters(mgr, o, name, remotev1alpha1.VolumeMountTraitGroupVersionKind, trait.NewReconciler(mgr,
+			trait.Kind(remotev1alpha1.VolumeMountTraitGroupVersionKind),
+			trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+			trait.WithLogger(o.Logger.WithValues("controller", name)),
+			trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			trait.WithChangelog(),
+			trait.WithMessageCatalog(o.Messages),
+			trait.WithSelector(o.Selector),
+			trait.WithTracer(o.Tracer),
+			trait.WithPackageIndex(),
+			trait.WithObserver(trait.NewKubeAppObserver(mgr.GetClient())),
+			trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+			trait.WithModifier(trait.ModifyFn(volumeMountModifier)),
+		))))
+}
+
+// volumeMountModifier adds a PersistentVolumeClaim template to the
+// KubernetesApplication for each volume of the trait, and mounts it into the
+// packaged Deployment.
+func volumeMountModifier(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	vt, ok := t.(*remotev1alpha1.VolumeMountTrait)
+	if !ok {
+		return errors.New(errNotVolumeMountTrait)
+	}
+
+	if err := trait.DeploymentFromKubeAppAccessor(ctx, a, vt, mountVolumes); err != nil {
+		return err
+	}
+
+	for _, v := range vt.Spec.Volumes {
+		if err := trait.SetKubeAppTemplate(a, claim(vt, v)); err != nil {
+			return errors.Wrap(err, errSetClaimTemplate)
+		}
+	}
+
+	return nil
+}
+
+func mountVolumes(ctx context.Context, obj runtime.Object, t trait.Trait) error {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New(errNotDeployment)
+	}
+
+	vt, ok := t.(*remotev1alpha1.VolumeMountTrait)
+	if !ok {
+		return errors.New(errNotVolumeMountTrait)
+	}
+
+	for _, v := range vt.Spec.Volumes {
+		claimName := claimName(vt, v)
+		setPodVolume(&d.Spec.Template.Spec, corev1.Volume{
+			Name: v.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+					ReadOnly:  v.ReadOnly,
+				},
+			},
+		})
+
+		mounted := false
+		for i := range d.Spec.Template.Spec.Containers {
+			c := &d.Spec.Template.Spec.Containers[i]
+			if len(v.Containers) > 0 && !contains(v.Containers, c.Name) {
+				continue
+			}
+			setVolumeMount(c, corev1.VolumeMount{
+				Name:      v.Name,
+				MountPath: v.MountPath,
+				ReadOnly:  v.ReadOnly,
+			})
+			mounted = true
+		}
+		if !mounted {
+			return errors.Errorf("%s: %s", errNoContainerForVolume, v.Name)
+		}
+	}
+
+	return nil
+}
+
+func claimName(vt *remotev1alpha1.VolumeMountTrait, v remotev1alpha1.Volume) string {
+	return fmt.Sprintf("%s-%s", vt.GetWorkloadReference().Name, v.Name)
+}
+
+func claim(vt *remotev1alpha1.VolumeMountTrait, v remotev1alpha1.Volume) *corev1.PersistentVolumeClaim {
+	mode := v.AccessMode
+	if mode == "" {
+		mode = corev1.ReadWriteOnce
+	}
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       pvcKind,
+			APIVersion: pvcAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: claimName(vt, v),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{mode},
+			StorageClassName: v.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: v.Size,
+				},
+			},
+		},
+	}
+}
+
+// setPodVolume adds or replaces the supplied volume in the pod spec.
+func setPodVolume(ps *corev1.PodSpec, v corev1.Volume) {
+	for i := range ps.Volumes {
+		if ps.Volumes[i].Name == v.Name {
+			ps.Volumes[i] = v
+			return
+		}
+	}
+	ps.Volumes = append(ps.Volumes, v)
+}
+
+// setVolumeMount adds or replaces the supplied volume mount in the container.
+func setVolumeMount(c *corev1.Container, m corev1.VolumeMount) {
+	for i := range c.VolumeMounts {
+		if c.VolumeMounts[i].Name == m.Name {
+			c.VolumeMounts[i] = m
+			return
+		}
+	}
+	c.VolumeMounts = append(c.VolumeMounts, m)
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
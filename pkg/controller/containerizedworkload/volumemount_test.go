@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestMountVolumes(t *testing.T) {
+	type args struct {
+		o runtime.Object
+		t trait.Trait
+	}
+
+	type want struct {
+		o   runtime.Object
+		err error
+	}
+
+	vt := &remotev1alpha1.VolumeMountTrait{
+		Spec: remotev1alpha1.VolumeMountTraitSpec{
+			WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+			Volumes: []remotev1alpha1.Volume{{
+				Name:       "data",
+				MountPath:  "/data",
+				Containers: []string{"cool-container"},
+				Size:       resource.MustParse("1Gi"),
+			}},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorObjectNotDeployment": {
+			reason: "Object passed to modifier that is not a Deployment should return error.",
+			args: args{
+				o: &appsv1.DaemonSet{},
+			},
+			want: want{o: &appsv1.DaemonSet{}, err: errors.New(errNotDeployment)},
+		},
+		"ErrorTraitNotVolumeMountTrait": {
+			reason: "Trait passed to modifier that is not a VolumeMountTrait should return error.",
+			args: args{
+				o: &appsv1.Deployment{},
+				t: &traitfake.Trait{},
+			},
+			want: want{o: &appsv1.Deployment{}, err: errors.New(errNotVolumeMountTrait)},
+		},
+		"ErrorNoContainer": {
+			reason: "A volume that targets no container in the Deployment should return error.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "other-container"})),
+				t: vt,
+			},
+			want: want{
+				o: deployment(dmWithContainer(corev1.Container{Name: "other-container"}), func(d *appsv1.Deployment) {
+					d.Spec.Template.Spec.Volumes = []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cwName + "-data"},
+						},
+					}}
+				}),
+				err: errors.Errorf("%s: %s", errNoContainerForVolume, "data"),
+			},
+		},
+		"Success": {
+			reason: "A volume should be added to the pod and mounted into the named containers.",
+			args: args{
+				o: deployment(dmWithContainer(corev1.Container{Name: "cool-container"}), dmWithContainer(corev1.Container{Name: "other-container"})),
+				t: vt,
+			},
+			want: want{
+				o: deployment(
+					dmWithContainer(corev1.Container{
+						Name:         "cool-container",
+						VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					}),
+					dmWithContainer(corev1.Container{Name: "other-container"}),
+					func(d *appsv1.Deployment) {
+						d.Spec.Template.Spec.Volumes = []corev1.Volume{{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cwName + "-data"},
+							},
+						}}
+					},
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mountVolumes(context.Background(), tc.args.o, tc.args.t)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nmountVolumes(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\nmountVolumes(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClaim(t *testing.T) {
+	vt := &remotev1alpha1.VolumeMountTrait{
+		Spec: remotev1alpha1.VolumeMountTraitSpec{
+			WorkloadReference: oamv1alpha2.WorkloadReference{Name: cwName},
+		},
+	}
+	v := remotev1alpha1.Volume{Name: "data", Size: resource.MustParse("1Gi")}
+
+	got := claim(vt, v)
+
+	if diff := cmp.Diff(cwName+"-data", got.GetName()); diff != "" {
+		t.Errorf("claim(...): -want name, +got name:\n%s", diff)
+	}
+	if diff := cmp.Diff([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, got.Spec.AccessModes); diff != "" {
+		t.Errorf("claim(...): -want access modes, +got access modes:\n%s", diff)
+	}
+}
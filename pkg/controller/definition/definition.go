@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definition implements controllers that start generic workload and
+// trait controllers for the kinds referenced by OAM WorkloadDefinitions and
+// TraitDefinitions.
+package definition
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/definition"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const errUnsupportedPackaging = "definition discovery only supports packaging workloads as KubernetesApplications"
+
+// Kinds of OAM definitions.
+var (
+	WorkloadDefinitionGroupVersionKind = oamv1alpha2.SchemeGroupVersion.WithKind("WorkloadDefinition")
+	TraitDefinitionGroupVersionKind    = oamv1alpha2.SchemeGroupVersion.WithKind("TraitDefinition")
+)
+
+// SetupDefinitionDiscovery adds controllers that start a generic workload or
+// trait controller for the kind referenced by each WorkloadDefinition and
+// TraitDefinition. Generic workload controllers render workloads using the
+// translation template of their WorkloadDefinition, or forward them to the
+// remote cluster as is if it has none, while generic trait controllers add the
+// trait to its workload's KubernetesApplication.
+func SetupDefinitionDiscovery(mgr ctrl.Manager, o options.Options) error {
+	if o.ProviderKubernetesConfig != "" || o.RemoteKubeconfig != "" || (o.PackageFormat != "" && o.PackageFormat != options.PackageFormatKubernetesApplication) {
+		return errors.New(errUnsupportedPackaging)
+	}
+
+	defs := map[schema.GroupVersionKind]newReconcilerFn{
+		WorkloadDefinitionGroupVersionKind: newWorkloadReconciler,
+		TraitDefinitionGroupVersionKind:    newTraitReconciler,
+	}
+
+	for gvk, fn := range defs {
+		name := "oam/" + strings.ToLower(gvk.GroupKind().String())
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			WithOptions(o.ForController()).
+			For(u).
+			Complete(o.Wrap(definition.NewReconciler(mgr, definition.Kind(gvk),
+				definition.WithLogger(o.Logger.WithValues("controller", name)),
+				definition.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+				definition.WithEngine(newEngine(mgr, o, fn)),
+			)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A newReconcilerFn returns a reconciler for the supplied kind.
+type newReconcilerFn func(mgr ctrl.Manager, o options.Options, name string, k schema.GroupVersionKind) reconcile.Reconciler
+
+func newWorkloadReconciler(mgr ctrl.Manager, o options.Options, name string, k schema.GroupVersionKind) reconcile.Reconciler {
+	ro := []workload.ReconcilerOption{
+		workload.WithUnstructured(),
+		workload.WithLogger(o.Logger.WithValues("controller", name)),
+		workload.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		workload.WithMaxConcurrentApplies(o.MaxConcurrentApplies),
+		workload.WithMessageCatalog(o.Messages),
+		workload.WithSelector(o.Selector),
+		workload.WithTracer(o.Tracer),
+		workload.WithTranslator(workload.NewObjectTranslatorWithWrappers(workload.NewDefinitionTemplateTranslator(mgr.GetClient(), mgr.GetRESTMapper(), workload.Forward).Translate, workload.SuspendWrapper, workload.RawTemplateWrapper, workload.OrderByWave)),
+		workload.WithPackager(workload.NewPackagerWithWrappers(workload.PackageFn(workload.KubeAppWrapper), workload.ShardKubeApps(o.MaxPackageBytes))),
+		workload.WithApplyOptions(workload.ControllersMustMatch(o.AdoptionPolicy), workload.KubeAppApplyOption(), workload.ClaimTemplates(name), workload.PreserveRenderInputs()),
+		workload.WithRemoteNamespace(o.RemoteNamespacer()),
+		workload.WithWaveGate(),
+		workload.WithDefinitionHasher(workload.NewWorkloadDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+	}
+	return workload.NewReconciler(mgr, workload.Kind(k), append(ro, o.Hooks()...)...)
+}
+
+func newTraitReconciler(mgr ctrl.Manager, o options.Options, name string, k schema.GroupVersionKind) reconcile.Reconciler {
+	return trait.NewReconciler(mgr, trait.Kind(k), trait.Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+		trait.WithUnstructured(),
+		trait.WithLogger(o.Logger.WithValues("controller", name)),
+		trait.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		trait.WithMessageCatalog(o.Messages),
+		trait.WithSelector(o.Selector),
+		trait.WithTracer(o.Tracer),
+		trait.WithApplicabilityChecker(trait.NewDefinitionApplicabilityChecker(mgr.GetClient(), mgr.GetRESTMapper())),
+		trait.WithDefinitionHasher(workload.NewTraitDefinitionHasher(mgr.GetClient(), mgr.GetRESTMapper())),
+		trait.WithModifier(trait.ModifyFn(trait.ForwardModifier)),
+	)
+}
+
+// An engine starts a controller for each kind referenced by a definition.
+// Controllers cannot be removed from a manager once added, so stopping a
+// controller closes a gate that causes it to ignore reconcile requests until
+// it is started again.
+type engine struct {
+	mgr           ctrl.Manager
+	o             options.Options
+	newReconciler newReconcilerFn
+
+	mu      sync.Mutex
+	gates   map[schema.GroupVersionKind]*gate
+	defines map[string]schema.GroupVersionKind
+}
+
+func newEngine(mgr ctrl.Manager, o options.Options, fn newReconcilerFn) *engine {
+	return &engine{
+		mgr:           mgr,
+		o:             o,
+		newReconciler: fn,
+		gates:         map[schema.GroupVersionKind]*gate{},
+		defines:       map[string]schema.GroupVersionKind{},
+	}
+}
+
+// Start a controller for the supplied kind on behalf of the named definition.
+func (e *engine) Start(def string, k schema.GroupVersionKind) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if prev, ok := e.defines[def]; ok && prev != k {
+		e.stop(def)
+	}
+
+	if g, ok := e.gates[k]; ok {
+		e.defines[def] = k
+		g.Open()
+		return nil
+	}
+
+	name := "oam/" + strings.ToLower(k.GroupKind().String())
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(k)
+
+	g := &gate{Reconciler: e.newReconciler(e.mgr, e.o, name, k), open: true}
+	if err := ctrl.NewControllerManagedBy(e.mgr).Named(name).WithOptions(e.o.ForController()).For(u).Complete(e.o.Wrap(g)); err != nil {
+		return err
+	}
+	e.gates[k] = g
+	e.defines[def] = k
+	return nil
+}
+
+// Stop the controller started on behalf of the named definition, unless
+// another definition references the same kind.
+func (e *engine) Stop(def string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stop(def)
+}
+
+func (e *engine) stop(def string) {
+	k, ok := e.defines[def]
+	if !ok {
+		return
+	}
+	delete(e.defines, def)
+
+	for _, other := range e.defines {
+		if other == k {
+			return
+		}
+	}
+	e.gates[k].Close()
+}
+
+// A gate passes reconcile requests to the wrapped reconciler while it is open,
+// and ignores them while it is closed.
+type gate struct {
+	reconcile.Reconciler
+
+	mu   sync.RWMutex
+	open bool
+}
+
+// Open the gate.
+func (g *gate) Open() {
+	g.mu.Lock()
+	g.open = true
+	g.mu.Unlock()
+}
+
+// Close the gate.
+func (g *gate) Close() {
+	g.mu.Lock()
+	g.open = false
+	g.mu.Unlock()
+}
+
+// Reconcile the supplied request if the gate is open.
+func (g *gate) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	g.mu.RLock()
+	open := g.open
+	g.mu.RUnlock()
+
+	if !open {
+		return reconcile.Result{}, nil
+	}
+	return g.Reconciler.Reconcile(req)
+}
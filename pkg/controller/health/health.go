@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health implements a controller that rolls the status of the
+// workloads and traits of each ApplicationConfiguration up into an
+// ApplicationHealth.
+package health
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/health"
+)
+
+// SetupApplicationHealth adds a controller that rolls the status of the
+// workloads and traits of each ApplicationConfiguration up into an
+// ApplicationHealth.
+func SetupApplicationHealth(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.ApplicationHealthGroupKind)
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForController()).
+		For(&oamv1alpha2.ApplicationConfiguration{}).
+		Owns(&remotev1alpha1.ApplicationHealth{})
+
+	// Changes to the status of the workloads and traits this addon reconciles
+	// trigger a reconcile of the ApplicationConfiguration that owns them.
+	owned := []runtime.Object{
+		&oamv1alpha2.ContainerizedWorkload{},
+		&oamv1alpha2.ManualScalerTrait{},
+		&remotev1alpha1.VolumeMountTrait{},
+		&remotev1alpha1.BundleTrait{},
+		&remotev1alpha1.PatchTrait{},
+		&remotev1alpha1.SidecarInjectionTrait{},
+		&remotev1alpha1.TrafficSplitTrait{},
+		&remotev1alpha1.ResourceQuotaTrait{},
+		&remotev1alpha1.SecurityContextTrait{},
+		&remotev1alpha1.CronScalerTrait{},
+		&remotev1alpha1.PodDisruptionBudgetTrait{},
+		&remotev1alpha1.AnnotationsAndLabelsTrait{},
+	}
+	for _, obj := range owned {
+		b = b.Watches(&source.Kind{Type: obj}, &handler.EnqueueRequestForOwner{OwnerType: &oamv1alpha2.ApplicationConfiguration{}})
+	}
+
+	return b.Complete(o.Wrap(health.NewReconciler(mgr,
+		health.WithLogger(o.Logger.WithValues("controller", name)),
+		health.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)))
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/namespace"
+)
+
+// SetupNamespaceJanitor adds a controller that deletes remote namespaces once
+// the last workload package in them is removed.
+func SetupNamespaceJanitor(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/namespacejanitor"
+
+	ro := []namespace.ReconcilerOption{namespace.WithLogger(o.Logger.WithValues("controller", name))}
+	if o.LiveFinalizerReads {
+		ro = append(ro, namespace.WithLiveReader(mgr.GetAPIReader()))
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForController()).
+		For(&workloadv1alpha1.KubernetesApplication{}).
+		Complete(o.Wrap(namespace.NewReconciler(mgr, ro...)))
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package options contains configuration shared by all OAM Kubernetes Remote
+// controllers.
+package options
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/drain"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/message"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/ratelimit"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trace"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+// A PackageFormat is a format in which workloads are packaged.
+type PackageFormat string
+
+// Package formats.
+const (
+	// PackageFormatKubernetesApplication packages workloads as Crossplane
+	// KubernetesApplications. This is the default.
+	PackageFormatKubernetesApplication PackageFormat = "KubernetesApplication"
+
+	// PackageFormatManifestWork packages workloads as Open Cluster Management
+	// ManifestWorks.
+	PackageFormatManifestWork PackageFormat = "ManifestWork"
+
+	// PackageFormatSecret packages workloads as Secrets of YAML manifests.
+	PackageFormatSecret PackageFormat = "Secret"
+)
+
+// A PackageSink is a store in which the package of each workload is stored,
+// for example so that it may be reviewed before it is applied.
+type PackageSink string
+
+// Package sinks.
+const (
+	// PackageSinkConfigMap stores the package of each workload as YAML
+	// manifests in a ConfigMap in the workload's namespace.
+	PackageSinkConfigMap PackageSink = "ConfigMap"
+)
+
+// A RateLimit limits how often a controller reconciles each object.
+type RateLimit struct {
+	// BaseDelay before an object whose reconcile failed is reconciled again.
+	// It doubles with each consecutive failure. Failed reconciles are not
+	// delayed if it is zero.
+	BaseDelay time.Duration
+
+	// MaxDelay before an object whose reconcile failed is reconciled again.
+	MaxDelay time.Duration
+
+	// QPS is the number of reconciles per second the controller may start
+	// once its bucket is empty. Reconciles are not limited if it is zero.
+	QPS float64
+
+	// Bucket is the number of reconciles the controller may start in a burst.
+	Bucket int
+}
+
+// Options configures an OAM Kubernetes Remote controller.
+type Options struct {
+	// Logger used by the controller.
+	Logger logging.Logger
+
+	// MaxConcurrentReconciles is the maximum number of reconciles the
+	// controller may run concurrently. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// MaxConcurrentApplies is the maximum number of objects of a workload's
+	// translation that may be applied concurrently. Defaults to 1.
+	MaxConcurrentApplies int
+
+	// RateLimit limits how often the controller reconciles each object.
+	RateLimit RateLimit
+
+	// DeadLetterLimit is the number of consecutive failed reconciles after
+	// which an object is no longer reconciled until its spec changes or an
+	// immediate reconcile of it is requested. Objects are always reconciled
+	// again if it is zero.
+	DeadLetterLimit int
+
+	// Selector selects the workloads and traits that are reconciled. Those
+	// whose labels do not match it are left for another instance of the
+	// addon. All workloads and traits are reconciled if it is nil.
+	Selector labels.Selector
+
+	// OAMRuntimeInterop configures the controller to honor the conventions of
+	// workloads rendered by the upstream OAM Kubernetes runtime.
+	OAMRuntimeInterop bool
+
+	// ProviderKubernetesConfig is the name of a provider-kubernetes
+	// ProviderConfig. If set, workloads are packaged as provider-kubernetes
+	// Objects that use it instead of as KubernetesApplications. Traits that
+	// modify KubernetesApplications have no effect on these workloads.
+	ProviderKubernetesConfig string
+
+	// RemoteKubeconfig is the namespace/name of a Secret containing the
+	// kubeconfig of a remote cluster. If set, and ProviderKubernetesConfig is
+	// not, workloads are applied directly to the remote cluster instead of
+	// being packaged. Traits that modify KubernetesApplications have no effect
+	// on these workloads.
+	RemoteKubeconfig string
+
+	// PackageFormat in which workloads are packaged. Ignored if
+	// ProviderKubernetesConfig or RemoteKubeconfig is set. Traits that modify
+	// KubernetesApplications have no effect on workloads packaged in other
+	// formats.
+	PackageFormat PackageFormat
+
+	// PackageSink in which the package of each workload is stored before it
+	// is applied. Packages are not stored if it is empty.
+	PackageSink PackageSink
+
+	// MaxPackageBytes is the maximum size of the JSON encoding of each
+	// KubernetesApplication a workload is packaged as. Larger packages are
+	// split across several KubernetesApplications. Packages are never split
+	// if it is zero.
+	MaxPackageBytes int
+
+	// AdoptionPolicy determines how packages that already exist but have no
+	// controller, for example because they were created by a previous
+	// version of the addon, are handled. Defaults to
+	// workload.AdoptionPolicyFail.
+	AdoptionPolicy workload.AdoptionPolicy
+
+	// SkipApply configures the controller to store packages in the
+	// PackageSink instead of applying them, so that they may be reviewed and
+	// applied by a GitOps tool.
+	SkipApply bool
+
+	// RemoteSchema is the path to the OpenAPI v2 document of the remote
+	// cluster, as served by its API server at /openapi/v2. If set, workload
+	// translations are validated against it before they are packaged.
+	RemoteSchema string
+
+	// SourceRemoteNamespaces configures the controller to create the
+	// translation of each workload that does not specify a remote namespace
+	// in the remote namespace named after the workload's namespace, rather
+	// than in the remote default namespace. The namespace janitor requires
+	// it.
+	SourceRemoteNamespaces bool
+
+	// LiveFinalizerReads configures the controller to read packages from the
+	// API server rather than its cache before it deletes anything they
+	// depend on.
+	LiveFinalizerReads bool
+
+	// MirrorReferences configures the controller to mirror the Secrets and
+	// ConfigMaps referenced by the pods of workloads that opt in into their
+	// packages.
+	MirrorReferences bool
+
+	// CacheTranslations configures the controller to reuse the translation
+	// of each workload until the workload changes. Changes to the Secrets and
+	// targets a workload references are not seen until the reconcile of the
+	// workload is explicitly requested.
+	CacheTranslations bool
+
+	// PinImageDigests configures the controller to replace the tag of each
+	// image of a ContainerizedWorkload with the digest it resolves to before
+	// the workload is packaged, so that remote clusters run exactly the image
+	// that was resolved.
+	PinImageDigests bool
+
+	// ImageVerificationKey is the path to a PEM encoded cosign public key.
+	// If set, and PinImageDigests is true, images are only pinned to digests
+	// that have a cosign signature made by this key.
+	ImageVerificationKey string
+
+	// PrePackageHooks are called with the translation of each workload
+	// before it is packaged, for example to check it against a policy.
+	PrePackageHooks []workload.PrePackageHook
+
+	// PostApplyHooks are called with the package of each workload once it
+	// has been applied, for example to send a notification.
+	PostApplyHooks []workload.PostApplyHook
+
+	// Messages replace the reasons and messages of the events and conditions
+	// emitted by the controller.
+	Messages message.Catalog
+
+	// Tracer records spans of the phases of each reconcile. Spans are not
+	// recorded if it is nil.
+	Tracer trace.Tracer
+
+	// InFlight tracks the reconciles of the controller that are in progress,
+	// so that they may finish before the addon exits. Reconciles are not
+	// tracked if it is nil.
+	InFlight *drain.Tracker
+}
+
+// ForController returns the options of a controller-runtime controller.
+func (o Options) ForController() controller.Options {
+	return controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+}
+
+// Hooks returns the workload reconciler options that call the PrePackageHooks
+// and PostApplyHooks of the controller.
+func (o Options) Hooks() []workload.ReconcilerOption {
+	ro := make([]workload.ReconcilerOption, 0, len(o.PrePackageHooks)+len(o.PostApplyHooks))
+	for _, h := range o.PrePackageHooks {
+		ro = append(ro, workload.WithPrePackageHook(h))
+	}
+	for _, h := range o.PostApplyHooks {
+		ro = append(ro, workload.WithPostApplyHook(h))
+	}
+	return ro
+}
+
+// RemoteNamespacer returns how the controller chooses the remote namespace of
+// each workload's translation.
+func (o Options) RemoteNamespacer() workload.RemoteNamespacer {
+	if o.SourceRemoteNamespaces {
+		return workload.AnnotatedNamespace(workload.SourceNamespace)
+	}
+	return workload.AnnotatedNamespace(workload.FixedNamespace(""))
+}
+
+// RateLimited wraps the supplied reconciler so that it honors the RateLimit of
+// the controller. It is returned unchanged if the controller is not rate
+// limited.
+func (o Options) RateLimited(r reconcile.Reconciler) reconcile.Reconciler {
+	ro := []ratelimit.ReconcilerOption{}
+	if o.RateLimit.BaseDelay > 0 {
+		ro = append(ro, ratelimit.WithFailureBackoff(o.RateLimit.BaseDelay, o.RateLimit.MaxDelay))
+	}
+	if o.RateLimit.QPS > 0 {
+		ro = append(ro, ratelimit.WithBucket(o.RateLimit.QPS, o.RateLimit.Bucket))
+	}
+	if len(ro) == 0 {
+		return r
+	}
+	return ratelimit.NewReconciler(r, ro...)
+}
+
+// Wrap the supplied reconciler so that it honors the RateLimit of the
+// controller, and so that its reconciles are tracked by InFlight.
+func (o Options) Wrap(r reconcile.Reconciler) reconcile.Reconciler {
+	r = o.RateLimited(r)
+	if o.InFlight == nil {
+		return r
+	}
+	return drain.NewReconciler(r, o.InFlight)
+}
@@ -13,27 +13,95 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
-
 package controller
 
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
-
 	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/containerizedworkload"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/definition"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/health"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/namespace"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/preview"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/scope"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/webhook"
+)
+
+// Options configures the OAM Kubernetes Remote controllers.
+type Options = options.Options
+
+// A SetupFn adds a controller to the supplied manager.
+type SetupFn func(ctrl.Manager, Options) error
+
+// Setup functions for each OAM Kubernetes Remote controller. Downstream addon
+// distributions may use these to enable a subset of controllers.
+var (
+	SetupContainerizedWorkload     SetupFn = containerizedworkload.SetupContainerizedWorkload
+	SetupManualScalerTrait         SetupFn = containerizedworkload.SetupManualScalerTrait
+	SetupVolumeMountTrait          SetupFn = containerizedworkload.SetupVolumeMountTrait
+	SetupBundleTrait               SetupFn = containerizedworkload.SetupBundleTrait
+	SetupPatchTrait                SetupFn = containerizedworkload.SetupPatchTrait
+	SetupSidecarInjectionTrait     SetupFn = containerizedworkload.SetupSidecarInjectionTrait
+	SetupTrafficSplitTrait         SetupFn = containerizedworkload.SetupTrafficSplitTrait
+	SetupResourceQuotaTrait        SetupFn = containerizedworkload.SetupResourceQuotaTrait
+	SetupSecurityContextTrait      SetupFn = containerizedworkload.SetupSecurityContextTrait
+	SetupCronScalerTrait           SetupFn = containerizedworkload.SetupCronScalerTrait
+	SetupPodDisruptionBudgetTrait  SetupFn = containerizedworkload.SetupPodDisruptionBudgetTrait
+	SetupAnnotationsAndLabelsTrait SetupFn = containerizedworkload.SetupAnnotationsAndLabelsTrait
+
+	// SetupNamespaceJanitor is opt-in; it is not enabled by SetupAll.
+	SetupNamespaceJanitor SetupFn = namespace.SetupNamespaceJanitor
+
+	// SetupTraitConflictWebhook is opt-in; it is not enabled by SetupAll.
+	SetupTraitConflictWebhook SetupFn = webhook.SetupTraitConflictWebhook
+
+	// SetupContainerizedWorkloadDefaulter is opt-in; it is not enabled by
+	// SetupAll.
+	SetupContainerizedWorkloadDefaulter SetupFn = webhook.SetupContainerizedWorkloadDefaulter
+
+	// SetupContainerizedWorkloadValidator is opt-in; it is not enabled by
+	// SetupAll.
+	SetupContainerizedWorkloadValidator SetupFn = webhook.SetupContainerizedWorkloadValidator
+
+	// SetupPreviewEnvironment is opt-in; it is not enabled by SetupAll.
+	SetupPreviewEnvironment SetupFn = preview.SetupPreviewEnvironment
+
+	// SetupApplicationHealth is opt-in; it is not enabled by SetupAll.
+	SetupApplicationHealth SetupFn = health.SetupApplicationHealth
+
+	// SetupDefinitionDiscovery is opt-in; it is not enabled by SetupAll.
+	SetupDefinitionDiscovery SetupFn = definition.SetupDefinitionDiscovery
+
+	// SetupNetworkScope is opt-in; it is not enabled by SetupAll.
+	SetupNetworkScope SetupFn = scope.SetupNetworkScope
 )
 
-// Setup creates all Kubernetes Remote controllers with the supplied logger and
-// adds them to the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		containerizedworkload.SetupContainerizedWorkload,
-		containerizedworkload.SetupManualScalerTrait,
-	} {
-		if err := setup(mgr, l); err != nil {
+// Setup the supplied controllers with the supplied options.
+func Setup(mgr ctrl.Manager, o Options, fns ...SetupFn) error {
+	for _, setup := range fns {
+		if err := setup(mgr, o); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SetupAll creates all default Kubernetes Remote controllers with the supplied
+// options and adds them to the supplied manager.
+func SetupAll(mgr ctrl.Manager, o Options) error {
+	return Setup(mgr, o,
+		SetupContainerizedWorkload,
+		SetupManualScalerTrait,
+		SetupVolumeMountTrait,
+		SetupBundleTrait,
+		SetupPatchTrait,
+		SetupSidecarInjectionTrait,
+		SetupTrafficSplitTrait,
+		SetupResourceQuotaTrait,
+		SetupSecurityContextTrait,
+		SetupCronScalerTrait,
+		SetupPodDisruptionBudgetTrait,
+		SetupAnnotationsAndLabelsTrait,
+	)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scope implements controllers for OAM scopes.
+package scope
+
+import (
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/scope"
+)
+
+// SetupNetworkScope adds a controller that moves the workloads of the
+// components that reference each NetworkScope into its shared remote
+// namespace.
+func SetupNetworkScope(mgr ctrl.Manager, o options.Options) error {
+	name := "oam/" + strings.ToLower(remotev1alpha1.NetworkScopeGroupKind)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForController()).
+		For(&remotev1alpha1.NetworkScope{}).
+		Owns(&workloadv1alpha1.KubernetesApplication{}).
+		Watches(&source.Kind{Type: &oamv1alpha2.ApplicationConfiguration{}}, scope.EnqueueRequestsForNetworkScopes()).
+		Complete(o.Wrap(scope.NewReconciler(mgr,
+			scope.WithLogger(o.Logger.WithValues("controller", name)),
+			scope.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		)))
+}
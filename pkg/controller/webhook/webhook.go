@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook sets up OAM Kubernetes Remote admission webhooks.
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller/options"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/webhook/containerizedworkload"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/webhook/trait"
+)
+
+// TraitConflictPath is the path at which the trait conflict webhook is served.
+const TraitConflictPath = "/validate-oam-trait-conflicts"
+
+// ContainerizedWorkloadDefaultsPath is the path at which the
+// ContainerizedWorkload defaulting webhook is served.
+const ContainerizedWorkloadDefaultsPath = "/mutate-oam-containerizedworkload-defaults"
+
+// ContainerizedWorkloadValidationPath is the path at which the
+// ContainerizedWorkload validating webhook is served.
+const ContainerizedWorkloadValidationPath = "/validate-oam-containerizedworkload"
+
+// SetupTraitConflictWebhook adds a validating webhook that rejects traits that
+// modify the same fields of a workload as another trait bound to it. Existing
+// traits are read from the API server rather than the cache so that recently
+// created traits are considered.
+func SetupTraitConflictWebhook(mgr ctrl.Manager, o options.Options) error {
+	mgr.GetWebhookServer().Register(TraitConflictPath, &webhook.Admission{
+		Handler: trait.NewConflictValidator(mgr.GetAPIReader(), []schema.GroupVersionKind{
+			oamv1alpha2.ManualScalerTraitGroupVersionKind,
+			remotev1alpha1.VolumeMountTraitGroupVersionKind,
+			remotev1alpha1.BundleTraitGroupVersionKind,
+			remotev1alpha1.PatchTraitGroupVersionKind,
+			remotev1alpha1.SidecarInjectionTraitGroupVersionKind,
+			remotev1alpha1.TrafficSplitTraitGroupVersionKind,
+			remotev1alpha1.ResourceQuotaTraitGroupVersionKind,
+			remotev1alpha1.SecurityContextTraitGroupVersionKind,
+			remotev1alpha1.CronScalerTraitGroupVersionKind,
+			remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind,
+			remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind,
+		}),
+	})
+	return nil
+}
+
+// SetupContainerizedWorkloadDefaulter adds a mutating webhook that fills in the
+// unspecified ports and resource requests of ContainerizedWorkloads, so that
+// stored workloads are fully specified and always translated the same way.
+func SetupContainerizedWorkloadDefaulter(mgr ctrl.Manager, o options.Options) error {
+	mgr.GetWebhookServer().Register(ContainerizedWorkloadDefaultsPath, &webhook.Admission{
+		Handler: containerizedworkload.NewDefaulter(),
+	})
+	return nil
+}
+
+// SetupContainerizedWorkloadValidator adds a validating webhook that rejects
+// ContainerizedWorkloads whose names, images, ports, or extended resources are
+// invalid, so that they are surfaced when they are created rather than when
+// they are reconciled.
+func SetupContainerizedWorkloadValidator(mgr ctrl.Manager, o options.Options) error {
+	mgr.GetWebhookServer().Register(ContainerizedWorkloadValidationPath, &webhook.Admission{
+		Handler: containerizedworkload.NewValidator(),
+	})
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package convert converts between the OAM workloads and traits reconciled by
+// this addon and KubeVela style v1beta1 Applications.
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+)
+
+const (
+	errUnknownKind     = "object is not a known workload or trait kind"
+	errUnknownType     = "unknown component or trait type"
+	errNoWorkloadRef   = "trait does not reference a workload"
+	errNoComponent     = "trait references a workload that is not a component of the application"
+	errEncodeSpec      = "cannot encode spec"
+	errDecodeSpec      = "cannot decode properties"
+	errWorkloadRefPath = "cannot get workload reference"
+)
+
+// ApplicationGroupVersionKind is the GroupVersionKind of a KubeVela
+// Application.
+var ApplicationGroupVersionKind = schema.GroupVersionKind{Group: "core.oam.dev", Version: "v1beta1", Kind: "Application"}
+
+// An Application is a KubeVela v1beta1 Application.
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationSpec `json:"spec"`
+}
+
+// An ApplicationSpec is the spec of a KubeVela v1beta1 Application.
+type ApplicationSpec struct {
+	Components []ApplicationComponent `json:"components"`
+}
+
+// An ApplicationComponent is a component of an Application.
+type ApplicationComponent struct {
+	Name       string               `json:"name"`
+	Type       string               `json:"type"`
+	Properties runtime.RawExtension `json:"properties,omitempty"`
+	Traits     []ApplicationTrait   `json:"traits,omitempty"`
+}
+
+// An ApplicationTrait is a trait of an ApplicationComponent.
+type ApplicationTrait struct {
+	Type       string               `json:"type"`
+	Properties runtime.RawExtension `json:"properties,omitempty"`
+}
+
+// Types maps the component and trait types of an Application to the kinds of
+// workload and trait they are converted to and from.
+type Types struct {
+	Workloads map[string]schema.GroupVersionKind
+	Traits    map[string]schema.GroupVersionKind
+}
+
+// DefaultTypes are the workload and trait kinds reconciled by this addon.
+var DefaultTypes = Types{
+	Workloads: map[string]schema.GroupVersionKind{
+		"containerized": oamv1alpha2.ContainerizedWorkloadGroupVersionKind,
+	},
+	Traits: map[string]schema.GroupVersionKind{
+		"scaler":       oamv1alpha2.ManualScalerTraitGroupVersionKind,
+		"volumemount":  remotev1alpha1.VolumeMountTraitGroupVersionKind,
+		"bundle":       remotev1alpha1.BundleTraitGroupVersionKind,
+		"patch":        remotev1alpha1.PatchTraitGroupVersionKind,
+		"sidecar":      remotev1alpha1.SidecarInjectionTraitGroupVersionKind,
+		"trafficsplit": remotev1alpha1.TrafficSplitTraitGroupVersionKind,
+		"quota":        remotev1alpha1.ResourceQuotaTraitGroupVersionKind,
+		"security":     remotev1alpha1.SecurityContextTraitGroupVersionKind,
+		"cronscaler":   remotev1alpha1.CronScalerTraitGroupVersionKind,
+		"pdb":          remotev1alpha1.PodDisruptionBudgetTraitGroupVersionKind,
+		"metadata":     remotev1alpha1.AnnotationsAndLabelsTraitGroupVersionKind,
+	},
+}
+
+func typeOf(types map[string]schema.GroupVersionKind, gvk schema.GroupVersionKind) (string, bool) {
+	for t, k := range types {
+		if k == gvk {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// ToApplication converts the supplied workloads and traits into an Application
+// with the supplied name and namespace. Each workload becomes a component of
+// the same name, and each trait is added to the component of the workload it
+// references.
+func ToApplication(name, namespace string, objs []*unstructured.Unstructured, t Types) (*Application, error) {
+	app := &Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: ApplicationGroupVersionKind.GroupVersion().String(), Kind: ApplicationGroupVersionKind.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+
+	index := map[string]int{}
+	for _, o := range objs {
+		ct, ok := typeOf(t.Workloads, o.GroupVersionKind())
+		if !ok {
+			continue
+		}
+		props, err := properties(o)
+		if err != nil {
+			return nil, err
+		}
+		index[o.GetName()] = len(app.Spec.Components)
+		app.Spec.Components = append(app.Spec.Components, ApplicationComponent{Name: o.GetName(), Type: ct, Properties: props})
+	}
+
+	for _, o := range objs {
+		if _, ok := typeOf(t.Workloads, o.GroupVersionKind()); ok {
+			continue
+		}
+		tt, ok := typeOf(t.Traits, o.GroupVersionKind())
+		if !ok {
+			return nil, errors.Errorf("%s: %s", errUnknownKind, o.GroupVersionKind())
+		}
+		ref, found, err := unstructured.NestedString(o.Object, "spec", "workloadRef", "name")
+		if err != nil {
+			return nil, errors.Wrap(err, errWorkloadRefPath)
+		}
+		if !found {
+			return nil, errors.Errorf("%s: %s", errNoWorkloadRef, o.GetName())
+		}
+		i, ok := index[ref]
+		if !ok {
+			return nil, errors.Errorf("%s: %s", errNoComponent, ref)
+		}
+		props, err := properties(o, "workloadRef")
+		if err != nil {
+			return nil, err
+		}
+		app.Spec.Components[i].Traits = append(app.Spec.Components[i].Traits, ApplicationTrait{Type: tt, Properties: props})
+	}
+
+	return app, nil
+}
+
+// FromApplication converts the supplied Application into workloads and
+// traits. Each component becomes a workload of the same name, and each trait
+// of a component becomes a trait that references its workload.
+func FromApplication(app *Application, t Types) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	for _, c := range app.Spec.Components {
+		wgvk, ok := t.Workloads[c.Type]
+		if !ok {
+			return nil, errors.Errorf("%s: %s", errUnknownType, c.Type)
+		}
+		w, err := object(wgvk, c.Name, app.GetNamespace(), c.Properties)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, w)
+
+		for _, ct := range c.Traits {
+			tgvk, ok := t.Traits[ct.Type]
+			if !ok {
+				return nil, errors.Errorf("%s: %s", errUnknownType, ct.Type)
+			}
+			tr, err := object(tgvk, fmt.Sprintf("%s-%s", c.Name, ct.Type), app.GetNamespace(), ct.Properties)
+			if err != nil {
+				return nil, err
+			}
+			ref := map[string]interface{}{
+				"apiVersion": wgvk.GroupVersion().String(),
+				"kind":       wgvk.Kind,
+				"name":       c.Name,
+			}
+			if err := unstructured.SetNestedMap(tr.Object, ref, "spec", "workloadRef"); err != nil {
+				return nil, errors.Wrap(err, errDecodeSpec)
+			}
+			objs = append(objs, tr)
+		}
+	}
+	return objs, nil
+}
+
+// properties returns the spec of the supplied object, without the supplied
+// fields.
+func properties(o *unstructured.Unstructured, omit ...string) (runtime.RawExtension, error) {
+	spec, _, err := unstructured.NestedMap(o.Object, "spec")
+	if err != nil {
+		return runtime.RawExtension{}, errors.Wrap(err, errEncodeSpec)
+	}
+	for _, f := range omit {
+		delete(spec, f)
+	}
+	if len(spec) == 0 {
+		return runtime.RawExtension{}, nil
+	}
+	b, err := json.Marshal(spec)
+	return runtime.RawExtension{Raw: b}, errors.Wrap(err, errEncodeSpec)
+}
+
+func object(gvk schema.GroupVersionKind, name, namespace string, props runtime.RawExtension) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(name)
+	u.SetNamespace(namespace)
+
+	spec := map[string]interface{}{}
+	if len(props.Raw) > 0 {
+		if err := json.Unmarshal(props.Raw, &spec); err != nil {
+			return nil, errors.Wrap(err, errDecodeSpec)
+		}
+	}
+	u.Object["spec"] = spec
+	return u, nil
+}
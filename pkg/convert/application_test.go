@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestToApplication(t *testing.T) {
+	type want struct {
+		app *Application
+		err error
+	}
+
+	workload := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.oam.crossplane.io/v1alpha2",
+		"kind":       "ContainerizedWorkload",
+		"metadata":   map[string]interface{}{"name": "frontend", "namespace": "default"},
+		"spec":       map[string]interface{}{"osType": "linux"},
+	}}
+	scaler := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.oam.crossplane.io/v1alpha2",
+		"kind":       "ManualScalerTrait",
+		"metadata":   map[string]interface{}{"name": "frontend-scaler", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicaCount": int64(3),
+			"workloadRef": map[string]interface{}{
+				"apiVersion": "core.oam.crossplane.io/v1alpha2",
+				"kind":       "ContainerizedWorkload",
+				"name":       "frontend",
+			},
+		},
+	}}
+	orphan := scaler.DeepCopy()
+	_ = unstructured.SetNestedField(orphan.Object, "backend", "spec", "workloadRef", "name")
+
+	app := &Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "core.oam.dev/v1beta1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-app", Namespace: "default"},
+		Spec: ApplicationSpec{Components: []ApplicationComponent{{
+			Name:       "frontend",
+			Type:       "containerized",
+			Properties: runtime.RawExtension{Raw: []byte(`{"osType":"linux"}`)},
+			Traits: []ApplicationTrait{{
+				Type:       "scaler",
+				Properties: runtime.RawExtension{Raw: []byte(`{"replicaCount":3}`)},
+			}},
+		}}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []*unstructured.Unstructured
+		want   want
+	}{
+		"Success": {
+			reason: "Workloads should become components, and traits should be added to the component of the workload they reference.",
+			objs:   []*unstructured.Unstructured{scaler, workload},
+			want:   want{app: app},
+		},
+		"TraitWithoutComponent": {
+			reason: "A trait that references a workload that is not converted should return an error.",
+			objs:   []*unstructured.Unstructured{workload, orphan},
+			want:   want{err: errors.Errorf("%s: %s", errNoComponent, "backend")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToApplication("cool-app", "default", tc.objs, DefaultTypes)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nToApplication(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.app, got); diff != "" {
+				t.Errorf("\nReason: %s\nToApplication(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFromApplication(t *testing.T) {
+	type want struct {
+		objs []*unstructured.Unstructured
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		app    *Application
+		want   want
+	}{
+		"UnknownType": {
+			reason: "A component of an unknown type should return an error.",
+			app:    &Application{Spec: ApplicationSpec{Components: []ApplicationComponent{{Name: "frontend", Type: "webservice"}}}},
+			want:   want{err: errors.Errorf("%s: %s", errUnknownType, "webservice")},
+		},
+		"Success": {
+			reason: "Components should become workloads, and their traits should reference them.",
+			app: &Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-app", Namespace: "default"},
+				Spec: ApplicationSpec{Components: []ApplicationComponent{{
+					Name:   "frontend",
+					Type:   "containerized",
+					Traits: []ApplicationTrait{{Type: "scaler", Properties: runtime.RawExtension{Raw: []byte(`{"replicaCount":3}`)}}},
+				}}},
+			},
+			want: want{objs: []*unstructured.Unstructured{
+				{Object: map[string]interface{}{
+					"apiVersion": "core.oam.crossplane.io/v1alpha2",
+					"kind":       "ContainerizedWorkload",
+					"metadata":   map[string]interface{}{"name": "frontend", "namespace": "default"},
+					"spec":       map[string]interface{}{},
+				}},
+				{Object: map[string]interface{}{
+					"apiVersion": "core.oam.crossplane.io/v1alpha2",
+					"kind":       "ManualScalerTrait",
+					"metadata":   map[string]interface{}{"name": "frontend-scaler", "namespace": "default"},
+					"spec": map[string]interface{}{
+						"replicaCount": float64(3),
+						"workloadRef": map[string]interface{}{
+							"apiVersion": "core.oam.crossplane.io/v1alpha2",
+							"kind":       "ContainerizedWorkload",
+							"name":       "frontend",
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := FromApplication(tc.app, DefaultTypes)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nFromApplication(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nFromApplication(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
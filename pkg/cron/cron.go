@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron parses standard five field cron schedules, and computes the
+// times at which they fire.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errFieldCount = "schedule must have five space separated fields: minute, hour, day of month, month, and day of week"
+	errParseField = "cannot parse schedule field"
+	errOutOfRange = "value out of range"
+	errBadStep    = "step must be a positive integer"
+)
+
+// Schedules are searched at most this far from the supplied time for a time
+// at which they fire. Every valid schedule fires at least once in any span of
+// five years, including those that only fire on the 29th of February.
+const searchYears = 5
+
+// Descriptors that may be used in place of five fields.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minutes = bounds{name: "minute", min: 0, max: 59}
+	hours   = bounds{name: "hour", min: 0, max: 23}
+	days    = bounds{name: "day of month", min: 1, max: 31}
+	months  = bounds{name: "month", min: 1, max: 12}
+
+	// Both 0 and 7 are Sunday.
+	weekdays = bounds{name: "day of week", min: 0, max: 7}
+)
+
+// A Schedule is a parsed cron schedule. Schedules fire at the start of each
+// minute that matches all of their fields. Like cron, a day matches if it
+// matches either the day of month or the day of week field when both are
+// restricted.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// Whether the day of month and day of week fields are unrestricted.
+	domAny, dowAny bool
+}
+
+// Parse the supplied cron schedule. Each of its five fields may be a *, a
+// value, a range such as 1-5, or a comma separated list of these, and values
+// and ranges may be followed by a step such as */15. Descriptors such as
+// @daily are also accepted.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[spec]; ok {
+		spec = d
+	}
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, errors.New(errFieldCount)
+	}
+
+	s := &Schedule{domAny: strings.HasPrefix(f[2], "*"), dowAny: strings.HasPrefix(f[4], "*")}
+	for _, p := range []struct {
+		field string
+		b     bounds
+		bits  *uint64
+	}{
+		{field: f[0], b: minutes, bits: &s.minute},
+		{field: f[1], b: hours, bits: &s.hour},
+		{field: f[2], b: days, bits: &s.dom},
+		{field: f[3], b: months, bits: &s.month},
+		{field: f[4], b: weekdays, bits: &s.dow},
+	} {
+		bits, err := parseField(p.field, p.b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s %q", errParseField, p.b.name, p.field)
+		}
+		*p.bits = bits
+	}
+
+	// Sunday may be written as 7, but time.Weekday numbers it 0.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, errors.New(errBadStep)
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := b.min, b.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(rng[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = strconv.Atoi(rng[i+1:]); err != nil {
+				return 0, err
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = n, n
+			// A single value with a step, such as 5/15, runs to the
+			// end of the field's range.
+			if step > 1 {
+				hi = b.max
+			}
+		}
+		if lo < b.min || hi > b.max || lo > hi {
+			return 0, errors.Errorf("%s: %d-%d is not within %d-%d", errOutOfRange, lo, hi, b.min, b.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after the supplied time at which the schedule
+// fires, in the supplied time's location. It returns the zero time if the
+// schedule does not fire within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchYears, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !has(s.month, int(m)):
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.day(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time at or before the supplied time at which the
+// schedule fired, in the supplied time's location. It returns the zero time if
+// the schedule did not fire within five years.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(-searchYears, 0, 0)
+	for !t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !has(s.month, int(m)):
+			t = time.Date(y, m, 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !s.day(t):
+			t = time.Date(y, m, d, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(s.hour, t.Hour()):
+			t = time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(-time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) day(t time.Time) bool {
+	dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
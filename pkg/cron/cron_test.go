@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04", s)
+	return t
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		spec    string
+		wantErr bool
+	}{
+		"Standard": {
+			reason: "A standard five field schedule should be parsed.",
+			spec:   "0 8 * * 1-5",
+		},
+		"ListsRangesAndSteps": {
+			reason: "Lists, ranges, and steps should be parsed.",
+			spec:   "*/15 8-18/2 1,15 1-6 *",
+		},
+		"Descriptor": {
+			reason: "Descriptors should be parsed.",
+			spec:   "@daily",
+		},
+		"TooFewFields": {
+			reason:  "Schedules must have five fields.",
+			spec:    "0 8 * *",
+			wantErr: true,
+		},
+		"OutOfRange": {
+			reason:  "Values must be within the range of their field.",
+			spec:    "60 * * * *",
+			wantErr: true,
+		},
+		"BadStep": {
+			reason:  "Steps must be positive integers.",
+			spec:    "*/0 * * * *",
+			wantErr: true,
+		},
+		"NotANumber": {
+			reason:  "Values must be numbers.",
+			spec:    "0 noon * * *",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.spec)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\nReason: %s\nParse(%q): want error %t, got %v", tc.reason, tc.spec, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNextAndPrev(t *testing.T) {
+	type want struct {
+		next time.Time
+		prev time.Time
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   string
+		t      time.Time
+		want   want
+	}{
+		"Weekdays": {
+			reason: "A weekday schedule should skip the weekend.",
+			spec:   "0 8 * * 1-5",
+			t:      date("2020-03-20 09:30"), // A Friday.
+			want: want{
+				next: date("2020-03-23 08:00"),
+				prev: date("2020-03-20 08:00"),
+			},
+		},
+		"AtBoundary": {
+			reason: "A schedule fires at, but not after, the supplied time.",
+			spec:   "30 * * * *",
+			t:      date("2020-03-20 09:30"),
+			want: want{
+				next: date("2020-03-20 10:30"),
+				prev: date("2020-03-20 09:30"),
+			},
+		},
+		"Steps": {
+			reason: "A stepped schedule should fire at each step.",
+			spec:   "*/15 * * * *",
+			t:      date("2020-03-20 09:31"),
+			want: want{
+				next: date("2020-03-20 09:45"),
+				prev: date("2020-03-20 09:30"),
+			},
+		},
+		"SundaySeven": {
+			reason: "Sunday may be written as 7.",
+			spec:   "0 0 * * 7",
+			t:      date("2020-03-20 09:30"),
+			want: want{
+				next: date("2020-03-22 00:00"),
+				prev: date("2020-03-15 00:00"),
+			},
+		},
+		"DayOfMonthOrWeek": {
+			reason: "A day should match either restricted day field.",
+			spec:   "0 0 1 * 1",
+			t:      date("2020-03-27 12:00"), // A Friday.
+			want: want{
+				next: date("2020-03-30 00:00"),
+				prev: date("2020-03-23 00:00"),
+			},
+		},
+		"LeapDay": {
+			reason: "A schedule that fires only on leap days should be found.",
+			spec:   "0 0 29 2 *",
+			t:      date("2020-03-20 09:30"),
+			want: want{
+				next: date("2024-02-29 00:00"),
+				prev: date("2020-02-29 00:00"),
+			},
+		},
+		"Never": {
+			reason: "A schedule that never fires should return the zero time.",
+			spec:   "0 0 31 2 *",
+			t:      date("2020-03-20 09:30"),
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(tc.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %s", tc.spec, err)
+			}
+			if diff := cmp.Diff(tc.want.next, s.Next(tc.t)); diff != "" {
+				t.Errorf("\nReason: %s\ns.Next(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.prev, s.Prev(tc.t)); diff != "" {
+				t.Errorf("\nReason: %s\ns.Prev(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
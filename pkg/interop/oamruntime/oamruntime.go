@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oamruntime supports running alongside the upstream OAM Kubernetes
+// runtime (oam-kubernetes-runtime), whose ApplicationConfiguration controller
+// emits the workloads and traits that this addon schedules remotely.
+//
+// The runtime controls each workload it renders, and renders a new workload
+// for each revision of a Component when revisions are enabled. The packages
+// this addon produces are controlled by their workload, so they are garbage
+// collected along with the workload revisions the runtime deletes.
+package oamruntime
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+// Labels the OAM Kubernetes runtime adds to the workloads and traits it
+// renders from an ApplicationConfiguration.
+const (
+	// LabelAppName is the name of the ApplicationConfiguration.
+	LabelAppName = "app.oam.dev/name"
+
+	// LabelAppComponent is the name of the Component.
+	LabelAppComponent = "app.oam.dev/component"
+
+	// LabelAppComponentRevision is the name of the ComponentRevision.
+	LabelAppComponentRevision = "app.oam.dev/revision"
+
+	// LabelPrefix is the prefix shared by all labels the runtime adds.
+	LabelPrefix = "app.oam.dev/"
+)
+
+// Labels returns the labels of the supplied object that were added by the OAM
+// Kubernetes runtime.
+func Labels(o metav1.Object) map[string]string {
+	l := map[string]string{}
+	for k, v := range o.GetLabels() {
+		if strings.HasPrefix(k, LabelPrefix) {
+			l[k] = v
+		}
+	}
+	return l
+}
+
+var _ workload.TranslationWrapper = PropagateLabels
+
+// PropagateLabels adds the labels the OAM Kubernetes runtime added to a
+// workload to each object of its translation, so that the objects produced
+// for an ApplicationConfiguration and its Component revisions can be found by
+// the same labels the runtime uses.
+func PropagateLabels(ctx context.Context, w workload.Workload, objs []workload.Object) ([]workload.Object, error) {
+	l := Labels(w)
+	if len(l) == 0 {
+		return objs, nil
+	}
+	for _, o := range objs {
+		meta.AddLabels(o, l)
+	}
+	return objs, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oamruntime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestPropagateLabels(t *testing.T) {
+	type args struct {
+		w workload.Workload
+		o []workload.Object
+	}
+
+	type want struct {
+		result []workload.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotRenderedByRuntime": {
+			reason: "Objects translated from a workload without runtime labels should be unchanged.",
+			args: args{
+				w: &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "label"}}},
+				o: []workload.Object{&appsv1.Deployment{}},
+			},
+			want: want{result: []workload.Object{&appsv1.Deployment{}}},
+		},
+		"RenderedByRuntime": {
+			reason: "Only the runtime's labels should be propagated to each translated object.",
+			args: args{
+				w: &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"cool":                    "label",
+					LabelAppName:              "cool-app",
+					LabelAppComponent:         "cool-component",
+					LabelAppComponentRevision: "cool-component-v2",
+				}}},
+				o: []workload.Object{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"existing": "label"}}}},
+			},
+			want: want{result: []workload.Object{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				"existing":                "label",
+				LabelAppName:              "cool-app",
+				LabelAppComponent:         "cool-component",
+				LabelAppComponentRevision: "cool-component-v2",
+			}}}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PropagateLabels(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPropagateLabels(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nPropagateLabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
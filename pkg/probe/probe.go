@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package probe implements the readiness probes of the addon.
+package probe
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	errCacheNotSynced  = "cache has not synced"
+	errLoadCertificate = "cannot load webhook serving certificate"
+)
+
+// Names of the serving certificate and key within a webhook certificate
+// directory.
+const (
+	CertName = "tls.crt"
+	KeyName  = "tls.key"
+)
+
+// DefaultCertDir is the directory from which the controller-runtime webhook
+// server loads its serving certificate if none is specified.
+var DefaultCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+
+// A CacheWaiter waits for a cache to sync.
+type CacheWaiter interface {
+	WaitForCacheSync(stop <-chan struct{}) bool
+}
+
+// A CacheSync probe reports whether a cache has synced. It must be added to
+// the controller manager, which starts it once the cache is started.
+type CacheSync struct {
+	cache  CacheWaiter
+	synced int32
+}
+
+// NewCacheSync returns a CacheSync probe of the supplied cache.
+func NewCacheSync(c CacheWaiter) *CacheSync {
+	return &CacheSync{cache: c}
+}
+
+// Start waiting for the cache to sync.
+func (p *CacheSync) Start(stop <-chan struct{}) error {
+	if p.cache.WaitForCacheSync(stop) {
+		atomic.StoreInt32(&p.synced, 1)
+	}
+	<-stop
+	return nil
+}
+
+// NeedLeaderElection returns false; the cache is started by all replicas,
+// whether or not they are the leader.
+func (p *CacheSync) NeedLeaderElection() bool {
+	return false
+}
+
+// Check returns an error until the cache has synced.
+func (p *CacheSync) Check(_ *http.Request) error {
+	if atomic.LoadInt32(&p.synced) == 0 {
+		return errors.New(errCacheNotSynced)
+	}
+	return nil
+}
+
+// Certificates returns a checker that returns an error until the webhook
+// serving certificate and key in the supplied directory can be loaded.
+// DefaultCertDir is checked if the directory is empty.
+func Certificates(dir string) healthz.Checker {
+	if dir == "" {
+		dir = DefaultCertDir
+	}
+	return func(_ *http.Request) error {
+		_, err := tls.LoadX509KeyPair(filepath.Join(dir, CertName), filepath.Join(dir, KeyName))
+		return errors.Wrap(err, errLoadCertificate)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type waitFn func(stop <-chan struct{}) bool
+
+func (fn waitFn) WaitForCacheSync(stop <-chan struct{}) bool { return fn(stop) }
+
+func TestCacheSync(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		synced bool
+		want   bool
+	}{
+		"Synced": {
+			reason: "The probe should pass once the cache has synced.",
+			synced: true,
+			want:   true,
+		},
+		"NotSynced": {
+			reason: "The probe should fail if the cache did not sync.",
+			synced: false,
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewCacheSync(waitFn(func(_ <-chan struct{}) bool { return tc.synced }))
+
+			if err := p.Check(nil); err == nil {
+				t.Errorf("\nReason: %s\np.Check(...): want error before Start, got nil", tc.reason)
+			}
+
+			stop := make(chan struct{})
+			close(stop)
+			if err := p.Start(stop); err != nil {
+				t.Errorf("\nReason: %s\np.Start(...): %s", tc.reason, err)
+			}
+
+			if got := p.Check(nil) == nil; got != tc.want {
+				t.Errorf("\nReason: %s\np.Check(...) == nil: want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "probe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	check := Certificates(dir)
+	if err := check(nil); err == nil {
+		t.Errorf("check(...): want error before the certificate is written, got nil")
+	}
+
+	if err := writeCertificate(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := check(nil); err != nil {
+		t.Errorf("check(...): want nil once the certificate is written, got %s", err)
+	}
+}
+
+func writeCertificate(dir string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	kb, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, CertName), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		return errors.Wrap(err, "cannot write certificate")
+	}
+	return errors.Wrap(ioutil.WriteFile(filepath.Join(dir, KeyName), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0600), "cannot write key")
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package annotations contains well-known annotations that are honored by the
+// OAM Kubernetes Remote reconcilers.
+package annotations
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const errParsePackagedObjects = "cannot parse " + PackagedObjects + " annotation"
+
+// ReconcileNow requests that an object be reconciled immediately. Its value is
+// typically the time the reconcile was requested. The annotation is cleared
+// once the object is reconciled.
+const ReconcileNow = "oam.crossplane.io/reconcile-now"
+
+// ReconcileRequested returns true if an immediate reconcile of the supplied
+// object has been requested.
+func ReconcileRequested(o metav1.Object) bool {
+	_, ok := o.GetAnnotations()[ReconcileNow]
+	return ok
+}
+
+// ClearReconcileRequest removes any request for an immediate reconcile from
+// the supplied object.
+func ClearReconcileRequest(o metav1.Object) {
+	a := o.GetAnnotations()
+	delete(a, ReconcileNow)
+	o.SetAnnotations(a)
+}
+
+// Paused pauses the reconciliation of an object while its value is "true".
+// The remote package of a paused object is left untouched until the
+// annotation is removed, for example during a maintenance window.
+const Paused = "oam.crossplane.io/paused"
+
+// IsPaused returns true if the reconciliation of the supplied object is
+// paused.
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[Paused] == "true"
+}
+
+// DeadLetter marks an object that is no longer reconciled because reconciling
+// it failed repeatedly. Its value is the generation of the object when it
+// became a dead letter. The object is reconciled again once its generation
+// changes or an immediate reconcile of it is requested.
+const DeadLetter = "oam.crossplane.io/dead-letter"
+
+// DeadLettered returns the generation at which the supplied object became a
+// dead letter, and true if it is a dead letter.
+func DeadLettered(o metav1.Object) (int64, bool) {
+	v, ok := o.GetAnnotations()[DeadLetter]
+	if !ok {
+		return 0, false
+	}
+	gen, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		// An unparseable generation can never match the object's generation,
+		// so the object will be reconciled again.
+		return -1, true
+	}
+	return gen, true
+}
+
+// SetDeadLetter marks the supplied object as a dead letter at its current
+// generation.
+func SetDeadLetter(o metav1.Object) {
+	meta.AddAnnotations(o, map[string]string{DeadLetter: strconv.FormatInt(o.GetGeneration(), 10)})
+}
+
+// ClearDeadLetter removes any dead letter mark from the supplied object.
+func ClearDeadLetter(o metav1.Object) {
+	a := o.GetAnnotations()
+	delete(a, DeadLetter)
+	o.SetAnnotations(a)
+}
+
+// Modifies declares the fields of a workload that a trait modifies, for
+// example "replicas,resources". Traits that are bound to the same workload may
+// not modify the same fields.
+const Modifies = "oam.crossplane.io/modifies"
+
+// ModifiedFields returns the fields the supplied trait declares it modifies.
+func ModifiedFields(o metav1.Object) []string {
+	v := o.GetAnnotations()[Modifies]
+	fields := []string{}
+	for _, f := range strings.Split(v, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// PackagedAs records the kinds of the top-level objects a workload was most
+// recently packaged as, for example "kubernetesapplication.workload.crossplane.io".
+// Multiple kinds are comma separated.
+const PackagedAs = "oam.crossplane.io/packaged-as"
+
+// PackageKinds returns the kinds the supplied workload was most recently
+// packaged as, and true if they are known. The kinds of a workload that was
+// packaged as no objects are unknown.
+func PackageKinds(o metav1.Object) ([]string, bool) {
+	v, ok := o.GetAnnotations()[PackagedAs]
+	if !ok {
+		return nil, false
+	}
+	kinds := []string{}
+	for _, k := range strings.Split(v, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds, len(kinds) > 0
+}
+
+// SetPackageKinds records the kinds the supplied workload was packaged as. It
+// returns true if the recorded kinds changed.
+func SetPackageKinds(o metav1.Object, kinds []string) bool {
+	unique := map[string]bool{}
+	for _, k := range kinds {
+		unique[k] = true
+	}
+	sorted := make([]string, 0, len(unique))
+	for k := range unique {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	v := strings.Join(sorted, ",")
+	if existing, ok := o.GetAnnotations()[PackagedAs]; ok && existing == v {
+		return false
+	}
+	meta.AddAnnotations(o, map[string]string{PackagedAs: v})
+	return true
+}
+
+// PackagedObjects records references to the top-level objects a workload was
+// most recently packaged as, encoded as a JSON array of object references.
+// The objects are deleted using these references, so that a workload whose
+// package can no longer be rendered can still be deleted.
+const PackagedObjects = "oam.crossplane.io/packaged-objects"
+
+// PackageReferences returns references to the top-level objects the supplied
+// workload was most recently packaged as. A workload whose package was never
+// recorded has no references.
+func PackageReferences(o metav1.Object) ([]corev1.ObjectReference, error) {
+	v, ok := o.GetAnnotations()[PackagedObjects]
+	if !ok {
+		return nil, nil
+	}
+	refs := []corev1.ObjectReference{}
+	if err := json.Unmarshal([]byte(v), &refs); err != nil {
+		return nil, errors.Wrap(err, errParsePackagedObjects)
+	}
+	return refs, nil
+}
+
+// SetPackageReferences records references to the top-level objects the
+// supplied workload was packaged as. It returns true if the recorded
+// references changed.
+func SetPackageReferences(o metav1.Object, refs []corev1.ObjectReference) bool {
+	// Marshalling a slice of structs cannot fail.
+	b, _ := json.Marshal(refs)
+
+	v := string(b)
+	if existing, ok := o.GetAnnotations()[PackagedObjects]; ok && existing == v {
+		return false
+	}
+	meta.AddAnnotations(o, map[string]string{PackagedObjects: v})
+	return true
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package changelog records a human readable changelog of the changes users
+// make to the specs of OAM workloads and traits.
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+)
+
+const (
+	errGetSpec        = "cannot get spec"
+	errMarshalSpec    = "cannot marshal spec"
+	errMarshalEntries = "cannot marshal changelog"
+)
+
+// AnnotationLastSpec records the spec of an object as of its last reconcile.
+const AnnotationLastSpec = "oam.crossplane.io/last-reconciled-spec"
+
+// AnnotationChangelog records the most recent changes to the spec of an
+// object as a JSON array of changelog entries.
+const AnnotationChangelog = "oam.crossplane.io/changelog"
+
+// MaxEntries is the maximum number of entries recorded in a changelog. The
+// oldest entries are discarded first.
+const MaxEntries = 10
+
+// An Object that may have its spec changes recorded.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// A Recorder is an object that records its changelog in its status.
+type Recorder interface {
+	SetChangelog(c []remotev1alpha1.ChangelogEntry)
+}
+
+// Get returns the changelog recorded on the supplied object. An unparseable
+// changelog is treated as an empty one.
+func Get(o metav1.Object) []remotev1alpha1.ChangelogEntry {
+	raw, ok := o.GetAnnotations()[AnnotationChangelog]
+	if !ok {
+		return nil
+	}
+	c := []remotev1alpha1.ChangelogEntry{}
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		return nil
+	}
+	return c
+}
+
+// Record the changes to the spec of the supplied object since its spec was
+// last recorded. The changes are added to the object's changelog and
+// returned. Record returns true if the object's annotations were updated and
+// must be persisted. Nothing is added to the changelog the first time the
+// object's spec is recorded, or if its spec has not changed.
+func Record(o Object) (*remotev1alpha1.ChangelogEntry, bool, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetSpec)
+	}
+	spec, _ := u["spec"].(map[string]interface{})
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errMarshalSpec)
+	}
+
+	raw, ok := o.GetAnnotations()[AnnotationLastSpec]
+	if ok && raw == string(b) {
+		return nil, false, nil
+	}
+
+	last := map[string]interface{}{}
+	if !ok || json.Unmarshal([]byte(raw), &last) != nil {
+		// We don't know what the spec used to be, so we can't tell what
+		// changed. We just record it as it is now.
+		meta.AddAnnotations(o, map[string]string{AnnotationLastSpec: string(b)})
+		return nil, true, nil
+	}
+
+	// Specs are compared after a JSON round trip so that numbers are
+	// compared consistently.
+	current := map[string]interface{}{}
+	_ = json.Unmarshal(b, &current)
+
+	e := remotev1alpha1.ChangelogEntry{
+		Generation: o.GetGeneration(),
+		Time:       metav1.Now(),
+		Changes:    Diff("spec", last, current),
+	}
+	if len(e.Changes) == 0 {
+		meta.AddAnnotations(o, map[string]string{AnnotationLastSpec: string(b)})
+		return nil, true, nil
+	}
+
+	c := append(Get(o), e)
+	if len(c) > MaxEntries {
+		c = c[len(c)-MaxEntries:]
+	}
+	cb, err := json.Marshal(c)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errMarshalEntries)
+	}
+
+	meta.AddAnnotations(o, map[string]string{AnnotationLastSpec: string(b), AnnotationChangelog: string(cb)})
+	return &e, true, nil
+}
+
+// Diff returns the leaf fields that differ between before and after, sorted
+// by path and formatted as "path: old -> new". Fields that were added or
+// removed are formatted as "path: added new" and "path: removed".
+func Diff(prefix string, before, after map[string]interface{}) []string {
+	diff := []string{}
+	for k, av := range after {
+		path := prefix + "." + k
+		bv, ok := before[k]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("%s: added %s", path, format(av)))
+			continue
+		}
+		am, aok := av.(map[string]interface{})
+		bm, bok := bv.(map[string]interface{})
+		if aok && bok {
+			diff = append(diff, Diff(path, bm, am)...)
+			continue
+		}
+		if !reflect.DeepEqual(av, bv) {
+			diff = append(diff, fmt.Sprintf("%s: %s -> %s", path, format(bv), format(av)))
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			diff = append(diff, fmt.Sprintf("%s: removed", prefix+"."+k))
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+func format(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, string:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changelog
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+)
+
+func TestRecord(t *testing.T) {
+	withSpec := func(spec string, changelog string) *corev1.PersistentVolumeClaim {
+		a := map[string]string{}
+		if spec != "" {
+			a[AnnotationLastSpec] = spec
+		}
+		if changelog != "" {
+			a[AnnotationChangelog] = changelog
+		}
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2, Annotations: a},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "cool", StorageClassName: nil},
+		}
+	}
+
+	type want struct {
+		e         *remotev1alpha1.ChangelogEntry
+		updated   bool
+		lastSpec  string
+		changelog []remotev1alpha1.ChangelogEntry
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      Object
+		want   want
+	}{
+		"FirstRecord": {
+			reason: "The spec of an object that has never been recorded should be recorded without a changelog entry.",
+			o:      withSpec("", ""),
+			want: want{
+				updated:  true,
+				lastSpec: `{"resources":{},"volumeName":"cool"}`,
+			},
+		},
+		"Unchanged": {
+			reason: "Nothing should be recorded if an object's spec has not changed.",
+			o:      withSpec(`{"resources":{},"volumeName":"cool"}`, ""),
+			want: want{
+				lastSpec: `{"resources":{},"volumeName":"cool"}`,
+			},
+		},
+		"Changed": {
+			reason: "Changes to an object's spec should be added to its changelog.",
+			o:      withSpec(`{"resources":{},"volumeName":"old","volumeMode":"Block"}`, `[{"generation":1,"time":null,"changes":["spec.volumeName: added \"old\""]}]`),
+			want: want{
+				e: &remotev1alpha1.ChangelogEntry{
+					Generation: 2,
+					Changes:    []string{`spec.volumeMode: removed`, `spec.volumeName: "old" -> "cool"`},
+				},
+				updated:  true,
+				lastSpec: `{"resources":{},"volumeName":"cool"}`,
+				changelog: []remotev1alpha1.ChangelogEntry{
+					{Generation: 1, Changes: []string{`spec.volumeName: added "old"`}},
+					{Generation: 2, Changes: []string{`spec.volumeMode: removed`, `spec.volumeName: "old" -> "cool"`}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, updated, err := Record(tc.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRecord(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			ignoreTime := cmpopts.IgnoreFields(remotev1alpha1.ChangelogEntry{}, "Time")
+
+			if diff := cmp.Diff(tc.want.e, e, ignoreTime); diff != "" {
+				t.Errorf("\nReason: %s\nRecord(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\nReason: %s\nRecord(...): -want updated, +got updated:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.lastSpec, tc.o.GetAnnotations()[AnnotationLastSpec]); diff != "" {
+				t.Errorf("\nReason: %s\nRecord(...): -want last spec, +got last spec:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.changelog, Get(tc.o), ignoreTime); diff != "" {
+				t.Errorf("\nReason: %s\nRecord(...): -want changelog, +got changelog:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	type args struct {
+		before map[string]interface{}
+		after  map[string]interface{}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Unchanged": {
+			reason: "Identical specs should have no differences.",
+			args: args{
+				before: map[string]interface{}{"replicas": 1.0},
+				after:  map[string]interface{}{"replicas": 1.0},
+			},
+			want: []string{},
+		},
+		"Nested": {
+			reason: "Nested fields should be compared individually and reported by path.",
+			args: args{
+				before: map[string]interface{}{"template": map[string]interface{}{"image": "nginx:1", "port": 80.0}},
+				after:  map[string]interface{}{"template": map[string]interface{}{"image": "nginx:2", "cpu": "1"}},
+			},
+			want: []string{
+				`spec.template.cpu: added "1"`,
+				`spec.template.image: "nginx:1" -> "nginx:2"`,
+				`spec.template.port: removed`,
+			},
+		},
+		"List": {
+			reason: "Lists should be compared as a whole.",
+			args: args{
+				before: map[string]interface{}{"args": []interface{}{"a"}},
+				after:  map[string]interface{}{"args": []interface{}{"a", "b"}},
+			},
+			want: []string{`spec.args: ["a"] -> ["a","b"]`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Diff("spec", tc.args.before, tc.args.after)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deadletter stops reconciling objects that repeatedly fail to
+// reconcile, so that a few broken objects do not consume worker capacity
+// indefinitely.
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+// DefaultFailureLimit is the default number of consecutive failed reconciles
+// after which an object becomes a dead letter.
+const DefaultFailureLimit = 10
+
+// ReportName is the name of the DeadLetterReport in each namespace.
+const ReportName = "oam-dead-letters"
+
+// Reconcile error strings.
+const (
+	errUpdateDeadLetter = "cannot update dead letter annotation"
+	errGetReport        = "cannot get dead letter report"
+	errUpdateReport     = "cannot update dead letter report"
+)
+
+// Reconcile event reasons.
+const (
+	reasonDeadLetter = "DeadLettered"
+	reasonRequeue    = "DeadLetterRequeued"
+)
+
+// Objects is the number of dead letters in each namespace.
+var Objects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "oam_dead_letter_objects",
+	Help: "Number of objects that are no longer reconciled because reconciling them failed repeatedly.",
+}, []string{"namespace"})
+
+func init() {
+	metrics.Registry.MustRegister(Objects)
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithFailureLimit specifies the number of consecutive failed reconciles after
+// which an object becomes a dead letter.
+func WithFailureLimit(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.limit = n
+	}
+}
+
+// An Object is a Kubernetes object that reports whether it was successfully
+// reconciled using a Synced condition.
+type Object interface {
+	resource.Conditioned
+	metav1.Object
+	runtime.Object
+}
+
+// A Reconciler wraps another reconciler. Objects that the wrapped reconciler
+// fails to reconcile too many times in a row become dead letters. Dead letters
+// are not passed to the wrapped reconciler, and are recorded in the
+// DeadLetterReport of their namespace, until their spec changes or an
+// immediate reconcile of them is requested.
+//
+// Failed reconciles are counted in memory, so the count is reset when the
+// controller restarts.
+type Reconciler struct {
+	client    client.Client
+	kind      schema.GroupVersionKind
+	newObject func() Object
+	wrapped   reconcile.Reconciler
+	limit     int
+
+	mx       sync.Mutex
+	failures map[types.NamespacedName]int
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler that stops passing objects of the
+// supplied kind to the supplied reconciler once reconciling them fails
+// repeatedly.
+func NewReconciler(m ctrl.Manager, of schema.GroupVersionKind, wrapped reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	no := func() Object {
+		return resource.MustCreateObject(of, m.GetScheme()).(Object)
+	}
+
+	r := &Reconciler{
+		client:    m.GetClient(),
+		kind:      of,
+		newObject: no,
+		wrapped:   wrapped,
+		limit:     DefaultFailureLimit,
+		failures:  map[types.NamespacedName]int{},
+		log:       logging.NewNopLogger(),
+		record:    event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile the supplied object using the wrapped reconciler, unless it is a
+// dead letter.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	o := r.newObject()
+	if err := r.client.Get(ctx, req.NamespacedName, o); err != nil {
+		// The wrapped reconciler is responsible for handling objects that
+		// cannot be found.
+		if kerrors.IsNotFound(err) {
+			r.reset(req.NamespacedName)
+			if err := r.report(ctx, req.NamespacedName, nil); err != nil {
+				log.Debug("Cannot remove object from dead letter report", "error", err)
+			}
+		}
+		return r.wrapped.Reconcile(req)
+	}
+
+	if gen, ok := annotations.DeadLettered(o); ok {
+		if !annotations.ReconcileRequested(o) && o.GetGeneration() == gen {
+			log.Debug("Skipping dead letter", "generation", gen)
+			return reconcile.Result{}, nil
+		}
+
+		log.Debug("Requeueing dead letter", "generation", o.GetGeneration())
+		annotations.ClearDeadLetter(o)
+		if err := r.client.Update(ctx, o); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errUpdateDeadLetter)
+		}
+		r.record.Event(o, event.Normal(reasonRequeue, "Reconciling dead letter again"))
+		if err := r.report(ctx, req.NamespacedName, nil); err != nil {
+			log.Debug("Cannot remove object from dead letter report", "error", err)
+		}
+	}
+
+	result, err := r.wrapped.Reconcile(req)
+	if err != nil {
+		// Errors returned by the wrapped reconciler are typically transient
+		// API server errors, which are retried with backoff as usual.
+		return result, err
+	}
+
+	if err := r.client.Get(ctx, req.NamespacedName, o); err != nil {
+		return result, nil
+	}
+
+	c := o.GetCondition(v1alpha1.TypeSynced)
+	if c.Reason != v1alpha1.ReasonReconcileError {
+		r.reset(req.NamespacedName)
+		return result, nil
+	}
+
+	n := r.fail(req.NamespacedName)
+	if n < r.limit {
+		return result, nil
+	}
+
+	log.Debug("Object is now a dead letter", "failures", n, "generation", o.GetGeneration())
+	annotations.SetDeadLetter(o)
+	if err := r.client.Update(ctx, o); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateDeadLetter)
+	}
+	r.reset(req.NamespacedName)
+	r.record.Event(o, event.Warning(reasonDeadLetter, errors.Errorf("stopped reconciling after %d consecutive failures", n)))
+
+	dl := &remotev1alpha1.DeadLetter{
+		APIVersion: r.kind.GroupVersion().String(),
+		Kind:       r.kind.Kind,
+		Name:       o.GetName(),
+		Failures:   n,
+		Message:    c.Message,
+		Since:      metav1.Now(),
+	}
+	if err := r.report(ctx, req.NamespacedName, dl); err != nil {
+		log.Debug("Cannot add object to dead letter report", "error", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *Reconciler) fail(nn types.NamespacedName) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.failures[nn]++
+	return r.failures[nn]
+}
+
+func (r *Reconciler) reset(nn types.NamespacedName) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	delete(r.failures, nn)
+}
+
+// report adds the supplied dead letter to the DeadLetterReport of the supplied
+// object's namespace, or removes the object from the report if the supplied
+// dead letter is nil.
+func (r *Reconciler) report(ctx context.Context, nn types.NamespacedName, dl *remotev1alpha1.DeadLetter) error {
+	if nn.Namespace == "" {
+		return nil
+	}
+
+	rep := &remotev1alpha1.DeadLetterReport{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: ReportName}, rep)
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetReport)
+	}
+	exists := err == nil
+	if !exists && dl == nil {
+		return nil
+	}
+
+	id := func(d remotev1alpha1.DeadLetter) string { return fmt.Sprintf("%s/%s/%s", d.APIVersion, d.Kind, d.Name) }
+	want := remotev1alpha1.DeadLetter{APIVersion: r.kind.GroupVersion().String(), Kind: r.kind.Kind, Name: nn.Name}
+
+	dls := make([]remotev1alpha1.DeadLetter, 0, len(rep.Status.DeadLetters)+1)
+	for _, d := range rep.Status.DeadLetters {
+		if id(d) != id(want) {
+			dls = append(dls, d)
+		}
+	}
+	if dl == nil && len(dls) == len(rep.Status.DeadLetters) {
+		return nil
+	}
+	if dl != nil {
+		dls = append(dls, *dl)
+	}
+	rep.Status.DeadLetters = dls
+
+	if !exists {
+		rep.SetNamespace(nn.Namespace)
+		rep.SetName(ReportName)
+		err = r.client.Create(ctx, rep)
+	} else {
+		err = r.client.Update(ctx, rep)
+	}
+	if err != nil {
+		return errors.Wrap(err, errUpdateReport)
+	}
+
+	Objects.WithLabelValues(nn.Namespace).Set(float64(len(dls)))
+	return nil
+}
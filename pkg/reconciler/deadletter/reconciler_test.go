@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	requeue := reconcile.Result{RequeueAfter: 30 * time.Second}
+
+	type args struct {
+		c       client.Client
+		wrapped reconcile.Reconciler
+		o       []ReconcilerOption
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	// getWorkload returns a MockGetFn that populates workloads using the
+	// supplied function, and reports that no DeadLetterReport exists.
+	getWorkload := func(fn func(w *workloadfake.Workload)) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			w, ok := obj.(*workloadfake.Workload)
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{}, "")
+			}
+			fn(w)
+			return nil
+		}
+	}
+
+	wrapped := reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) { return requeue, nil })
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SkipDeadLetter": {
+			reason: "A dead letter whose generation has not changed should not be passed to the wrapped reconciler.",
+			args: args{
+				c: &test.MockClient{MockGet: getWorkload(func(w *workloadfake.Workload) {
+					w.SetGeneration(1)
+					annotations.SetDeadLetter(w)
+				})},
+				wrapped: reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, errBoom }),
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"RequeueDeadLetterError": {
+			reason: "Errors clearing the dead letter mark of an object whose generation changed should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: getWorkload(func(w *workloadfake.Workload) {
+						annotations.SetDeadLetter(w)
+						w.SetGeneration(2)
+					}),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				wrapped: wrapped,
+			},
+			want: want{err: errors.Wrap(errBoom, errUpdateDeadLetter)},
+		},
+		"RequeueDeadLetter": {
+			reason: "A dead letter that an immediate reconcile was requested for should be passed to the wrapped reconciler.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: getWorkload(func(w *workloadfake.Workload) {
+						annotations.SetDeadLetter(w)
+						w.SetAnnotations(map[string]string{annotations.DeadLetter: "0", annotations.ReconcileNow: "now"})
+					}),
+					MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						if _, ok := annotations.DeadLettered(obj.(*workloadfake.Workload)); ok {
+							return errors.New("dead letter mark was not cleared")
+						}
+						return nil
+					},
+				},
+				wrapped: wrapped,
+			},
+			want: want{result: requeue},
+		},
+		"BelowFailureLimit": {
+			reason: "An object that has not failed to reconcile too many times should be requeued as usual.",
+			args: args{
+				c: &test.MockClient{MockGet: getWorkload(func(w *workloadfake.Workload) {
+					w.SetConditions(v1alpha1.ReconcileError(errBoom))
+				})},
+				wrapped: wrapped,
+				o:       []ReconcilerOption{WithFailureLimit(2)},
+			},
+			want: want{result: requeue},
+		},
+		"DeadLettered": {
+			reason: "An object that has failed to reconcile too many times should become a dead letter and be reported.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: getWorkload(func(w *workloadfake.Workload) {
+						w.SetNamespace("cool")
+						w.SetName("broken")
+						w.SetConditions(v1alpha1.ReconcileError(errBoom))
+					}),
+					MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						if _, ok := annotations.DeadLettered(obj.(*workloadfake.Workload)); !ok {
+							return errors.New("object was not marked as a dead letter")
+						}
+						return nil
+					},
+					MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+						r := obj.(*remotev1alpha1.DeadLetterReport)
+						if len(r.Status.DeadLetters) != 1 || r.Status.DeadLetters[0].Name != "broken" {
+							return errors.Errorf("unexpected dead letters: %v", r.Status.DeadLetters)
+						}
+						return nil
+					},
+				},
+				wrapped: wrapped,
+				o:       []ReconcilerOption{WithFailureLimit(1)},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &fake.Manager{Client: tc.args.c, Scheme: fake.SchemeWith(&workloadfake.Workload{})}
+			r := NewReconciler(m, fake.GVK(&workloadfake.Workload{}), tc.args.wrapped, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "cool", Name: "broken"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
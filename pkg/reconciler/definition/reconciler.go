@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definition discovers the workload and trait kinds referenced by OAM
+// WorkloadDefinitions and TraitDefinitions, and starts controllers for them.
+package definition
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetDefinition   = "cannot get definition"
+	errNoReference     = "definition does not reference a CustomResourceDefinition"
+	errGetCRD          = "cannot get referenced CustomResourceDefinition"
+	errNoCRDVersion    = "referenced CustomResourceDefinition has no storage version"
+	errStartController = "cannot start controller for defined kind"
+)
+
+// Reconcile event reasons.
+const (
+	reasonCannotStart = "CannotStartController"
+	reasonStarted     = "StartedController"
+	reasonStopped     = "StoppedController"
+)
+
+// CustomResourceDefinitionGroupVersionKind is the kind of the CRDs that
+// definitions reference.
+var CustomResourceDefinitionGroupVersionKind = schema.GroupVersionKind{
+	Group:   "apiextensions.k8s.io",
+	Version: "v1beta1",
+	Kind:    "CustomResourceDefinition",
+}
+
+// Kind is a kind of OAM definition.
+type Kind schema.GroupVersionKind
+
+// An Engine starts and stops the controller of the kind referenced by each
+// definition.
+type Engine interface {
+	// Start a controller for the supplied kind on behalf of the named
+	// definition. Starting a kind that is already started is a no-op.
+	Start(definition string, k schema.GroupVersionKind) error
+
+	// Stop the controller started on behalf of the named definition, if any.
+	Stop(definition string)
+}
+
+// A NopEngine does not start or stop controllers.
+type NopEngine struct{}
+
+// Start does nothing.
+func (e NopEngine) Start(_ string, _ schema.GroupVersionKind) error { return nil }
+
+// Stop does nothing.
+func (e NopEngine) Stop(_ string) {}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithEngine specifies how the Reconciler should start and stop controllers.
+func WithEngine(e Engine) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.engine = e
+	}
+}
+
+// A Reconciler reconciles OAM definitions by starting a controller for the
+// kind each references.
+type Reconciler struct {
+	client client.Client
+	kind   Kind
+	known  runtime.ObjectTyper
+	engine Engine
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler that reconciles OAM definitions of the
+// supplied kind. Kinds that are registered with the manager's scheme are
+// assumed to have dedicated controllers, and are ignored.
+func NewReconciler(m ctrl.Manager, definition Kind, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		kind:   definition,
+		known:  m.GetScheme(),
+		engine: NopEngine{},
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile an OAM definition by starting a controller for the kind it
+// references, or stopping that controller if the definition was deleted.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	d := &unstructured.Unstructured{}
+	d.SetGroupVersionKind(schema.GroupVersionKind(r.kind))
+	if err := r.client.Get(ctx, req.NamespacedName, d); err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			log.Debug("Definition was deleted; stopping its controller")
+			r.engine.Stop(req.Name)
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetDefinition)
+	}
+
+	if d.GetDeletionTimestamp() != nil {
+		log.Debug("Definition is being deleted; stopping its controller")
+		r.engine.Stop(req.Name)
+		r.record.Event(d, event.Normal(reasonStopped, "Stopped controller for defined kind"))
+		return reconcile.Result{}, nil
+	}
+
+	name, _, _ := unstructured.NestedString(d.Object, "spec", "reference", "name")
+	if name == "" {
+		// There is nothing to start until the definition is updated, which
+		// will trigger a new reconcile.
+		log.Debug(errNoReference)
+		r.record.Event(d, event.Warning(reasonCannotStart, errors.New(errNoReference)))
+		return reconcile.Result{}, nil
+	}
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(CustomResourceDefinitionGroupVersionKind)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		log.Debug("Cannot get referenced CustomResourceDefinition", "error", err, "crd", name)
+		r.record.Event(d, event.Warning(reasonCannotStart, errors.Wrap(err, errGetCRD)))
+		return reconcile.Result{}, errors.Wrap(err, errGetCRD)
+	}
+
+	gvk, err := DefinedKind(crd)
+	if err != nil {
+		log.Debug("Cannot determine defined kind", "error", err, "crd", name)
+		r.record.Event(d, event.Warning(reasonCannotStart, err))
+		return reconcile.Result{}, nil
+	}
+	log = log.WithValues("kind", gvk.String())
+
+	if r.known.Recognizes(gvk) {
+		log.Debug("Defined kind has a dedicated controller")
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.engine.Start(req.Name, gvk); err != nil {
+		log.Debug("Cannot start controller for defined kind", "error", err)
+		r.record.Event(d, event.Warning(reasonCannotStart, errors.Wrap(err, errStartController)))
+		return reconcile.Result{}, errors.Wrap(err, errStartController)
+	}
+
+	log.Debug("Started controller for defined kind")
+	r.record.Event(d, event.Normal(reasonStarted, "Started controller for "+strings.ToLower(gvk.GroupKind().String())))
+	return reconcile.Result{}, nil
+}
+
+// DefinedKind returns the kind defined by the supplied unstructured
+// CustomResourceDefinition, at its storage version.
+func DefinedKind(crd *unstructured.Unstructured) (schema.GroupVersionKind, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	version, _, _ := unstructured.NestedString(crd.Object, "spec", "version")
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _, _ := unstructured.NestedBool(m, "storage"); storage {
+			version, _, _ = unstructured.NestedString(m, "name")
+			break
+		}
+	}
+
+	if version == "" {
+		return schema.GroupVersionKind{}, errors.New(errNoCRDVersion)
+	}
+	return schema.GroupVersionKind{Group: group, Version: version, Kind: kind}, nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type mockEngine struct {
+	started schema.GroupVersionKind
+	stopped bool
+	err     error
+}
+
+func (e *mockEngine) Start(_ string, k schema.GroupVersionKind) error {
+	e.started = k
+	return e.err
+}
+
+func (e *mockEngine) Stop(_ string) { e.stopped = true }
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	defined := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	known := fake.GVK(&fake.Managed{})
+
+	// get returns a MockGetFn that reads a definition referencing the
+	// supplied CRD, which defines the supplied kind.
+	get := func(ref string, k schema.GroupVersionKind, crd error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			if u.GetKind() == CustomResourceDefinitionGroupVersionKind.Kind {
+				u.Object["spec"] = map[string]interface{}{
+					"group":    k.Group,
+					"names":    map[string]interface{}{"kind": k.Kind},
+					"versions": []interface{}{map[string]interface{}{"name": k.Version, "storage": true}},
+				}
+				return crd
+			}
+			u.Object["spec"] = map[string]interface{}{"reference": map[string]interface{}{"name": ref}}
+			return nil
+		}
+	}
+
+	type args struct {
+		c client.Client
+		e *mockEngine
+	}
+
+	type want struct {
+		result  reconcile.Result
+		err     error
+		started schema.GroupVersionKind
+		stopped bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefinitionNotFound": {
+			reason: "The controller of a deleted definition should be stopped.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				e: &mockEngine{},
+			},
+			want: want{stopped: true},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the definition should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				e: &mockEngine{},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetDefinition)},
+		},
+		"DefinitionDeleted": {
+			reason: "The controller of a definition that is being deleted should be stopped.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					now := metav1.Now()
+					obj.(*unstructured.Unstructured).SetDeletionTimestamp(&now)
+					return nil
+				})},
+				e: &mockEngine{},
+			},
+			want: want{stopped: true},
+		},
+		"NoReference": {
+			reason: "Definitions that reference no CRD should be ignored until they are updated.",
+			args: args{
+				c: &test.MockClient{MockGet: get("", defined, nil)},
+				e: &mockEngine{},
+			},
+			want: want{},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the referenced CRD should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: get("cools.example.org", defined, errBoom)},
+				e: &mockEngine{},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetCRD)},
+		},
+		"KnownKind": {
+			reason: "Kinds registered with the manager's scheme should not be started.",
+			args: args{
+				c: &test.MockClient{MockGet: get("managed.example.org", known, nil)},
+				e: &mockEngine{},
+			},
+			want: want{},
+		},
+		"StartError": {
+			reason: "Errors starting a controller for the defined kind should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: get("cools.example.org", defined, nil)},
+				e: &mockEngine{err: errBoom},
+			},
+			want: want{err: errors.Wrap(errBoom, errStartController), started: defined},
+		},
+		"Started": {
+			reason: "A controller for the defined kind should be started.",
+			args: args{
+				c: &test.MockClient{MockGet: get("cools.example.org", defined, nil)},
+				e: &mockEngine{},
+			},
+			want: want{started: defined},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &fake.Manager{Client: tc.args.c, Scheme: fake.SchemeWith(&fake.Managed{})}
+			r := NewReconciler(m, Kind{Group: "core.oam.dev", Version: "v1alpha2", Kind: "WorkloadDefinition"}, WithEngine(tc.args.e))
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.started, tc.args.e.started); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want started, +got started:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stopped, tc.args.e.stopped); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want stopped, +got stopped:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefinedKind(t *testing.T) {
+	type want struct {
+		gvk schema.GroupVersionKind
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   map[string]interface{}
+		want   want
+	}{
+		"NoVersion": {
+			reason: "A CRD with no version should return an error.",
+			spec:   map[string]interface{}{"group": "example.org", "names": map[string]interface{}{"kind": "Cool"}},
+			want:   want{err: errors.New(errNoCRDVersion)},
+		},
+		"Version": {
+			reason: "The version of a CRD with a single version should be used.",
+			spec:   map[string]interface{}{"group": "example.org", "version": "v1", "names": map[string]interface{}{"kind": "Cool"}},
+			want:   want{gvk: schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}},
+		},
+		"StorageVersion": {
+			reason: "The storage version of a CRD with multiple versions should be used.",
+			spec: map[string]interface{}{
+				"group": "example.org",
+				"names": map[string]interface{}{"kind": "Cool"},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1alpha1", "storage": false},
+					map[string]interface{}{"name": "v1beta1", "storage": true},
+				},
+			},
+			want: want{gvk: schema.GroupVersionKind{Group: "example.org", Version: "v1beta1", Kind: "Cool"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := DefinedKind(&unstructured.Unstructured{Object: map[string]interface{}{"spec": tc.spec}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nDefinedKind(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.gvk, got); diff != "" {
+				t.Errorf("\nReason: %s\nDefinedKind(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain tracks the reconciles that are in progress, so that they may
+// finish before the addon exits.
+package drain
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// A Tracker tracks the reconciles of one or more Reconcilers that are in
+// progress.
+type Tracker struct {
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	idle     chan struct{}
+}
+
+// NewTracker returns a Tracker with no reconciles in progress.
+func NewTracker() *Tracker {
+	return &Tracker{idle: make(chan struct{})}
+}
+
+// start a reconcile. It returns false if the Tracker is draining.
+func (t *Tracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.inFlight++
+	return true
+}
+
+// done finishes a reconcile.
+func (t *Tracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight--
+	if t.draining && t.inFlight == 0 {
+		close(t.idle)
+	}
+}
+
+// Drain stops the Tracker starting reconciles, then waits up to the supplied
+// timeout for the reconciles in progress to finish. It returns false if they
+// did not finish in time.
+func (t *Tracker) Drain(timeout time.Duration) bool {
+	t.mu.Lock()
+	if !t.draining {
+		t.draining = true
+		if t.inFlight == 0 {
+			close(t.idle)
+		}
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.idle:
+		return true
+	default:
+	}
+
+	tm := time.NewTimer(timeout)
+	defer tm.Stop()
+	select {
+	case <-t.idle:
+		return true
+	case <-tm.C:
+		return false
+	}
+}
+
+// A Reconciler wraps another reconciler so that its reconciles are tracked.
+// Reconciles are requeued instead of started once its Tracker is draining.
+type Reconciler struct {
+	wrapped reconcile.Reconciler
+	tracker *Tracker
+}
+
+// NewReconciler returns a Reconciler that tracks the reconciles of the supplied
+// reconciler using the supplied Tracker.
+func NewReconciler(r reconcile.Reconciler, t *Tracker) *Reconciler {
+	return &Reconciler{wrapped: r, tracker: t}
+}
+
+// Reconcile the supplied request using the wrapped reconciler.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if !r.tracker.start() {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	defer r.tracker.done()
+	return r.wrapped.Reconcile(req)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+func TestDrainIdle(t *testing.T) {
+	if !NewTracker().Drain(0) {
+		t.Errorf("Drain(...): want true for a Tracker with no reconciles in progress, got false")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	tracker := NewTracker()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	called := 0
+	r := NewReconciler(reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+		called++
+		close(started)
+		<-release
+		return reconcile.Result{}, nil
+	}), tracker)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = r.Reconcile(reconcile.Request{})
+		close(done)
+	}()
+	<-started
+
+	if tracker.Drain(10 * time.Millisecond) {
+		t.Errorf("Drain(...): want false while a reconcile is in progress, got true")
+	}
+
+	got, err := r.Reconcile(reconcile.Request{})
+	if err != nil {
+		t.Errorf("r.Reconcile(...): %s", err)
+	}
+	if diff := cmp.Diff(reconcile.Result{Requeue: true}, got); diff != "" {
+		t.Errorf("r.Reconcile(...): reconciles should be requeued once draining: -want, +got:\n%s", diff)
+	}
+
+	close(release)
+	if !tracker.Drain(time.Second) {
+		t.Errorf("Drain(...): want true once the reconcile in progress finished, got false")
+	}
+	<-done
+
+	if called != 1 {
+		t.Errorf("r.Reconcile(...): want 1 call to the wrapped reconciler, got %d", called)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expiry schedules reconciles of objects at the time they expire.
+package expiry
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Defaults of a Wheel.
+const (
+	DefaultTick  = 1 * time.Second
+	DefaultSlots = 512
+)
+
+// A Scheduler schedules reconciles of objects at the time they expire.
+type Scheduler interface {
+	// Schedule a reconcile of the supplied object at the supplied time,
+	// replacing any reconcile that was previously scheduled.
+	Schedule(nn types.NamespacedName, at time.Time)
+
+	// Cancel any reconcile of the supplied object that was scheduled.
+	Cancel(nn types.NamespacedName)
+}
+
+// A NopScheduler does not schedule reconciles.
+type NopScheduler struct{}
+
+// Schedule does nothing.
+func (s NopScheduler) Schedule(_ types.NamespacedName, _ time.Time) {}
+
+// Cancel does nothing.
+func (s NopScheduler) Cancel(_ types.NamespacedName) {}
+
+// A WheelOption configures a Wheel.
+type WheelOption func(*Wheel)
+
+// WithTick specifies the interval at which a Wheel advances. Objects expire at
+// most one tick after the time at which they were scheduled.
+func WithTick(d time.Duration) WheelOption {
+	return func(w *Wheel) {
+		w.tick = d
+	}
+}
+
+// WithSlots specifies the number of slots of a Wheel. A Wheel with more slots
+// inspects fewer objects each tick.
+func WithSlots(n int) WheelOption {
+	return func(w *Wheel) {
+		w.slots = make([]map[types.NamespacedName]time.Time, n)
+	}
+}
+
+// A Wheel is a hashed timer wheel. Objects are scheduled into the slot of the
+// tick at which they expire. The Wheel advances one slot each tick, and emits
+// an event for each object in the current slot that has expired. Objects that
+// expire more than one revolution of the Wheel in the future remain in their
+// slot until they expire, so a Wheel need not be large enough to cover the
+// longest time an object may be scheduled.
+type Wheel struct {
+	tick time.Duration
+
+	mx    sync.Mutex
+	slots []map[types.NamespacedName]time.Time
+	index map[types.NamespacedName]int
+	pos   int
+	last  time.Time
+
+	events chan event.GenericEvent
+}
+
+// NewWheel returns a Wheel that emits an event for each scheduled object once
+// it expires. The Wheel must be started, typically by adding it to a
+// controller manager, and its Source watched by a controller.
+func NewWheel(o ...WheelOption) *Wheel {
+	w := &Wheel{
+		tick:   DefaultTick,
+		slots:  make([]map[types.NamespacedName]time.Time, DefaultSlots),
+		index:  map[types.NamespacedName]int{},
+		last:   time.Now(),
+		events: make(chan event.GenericEvent, DefaultSlots),
+	}
+	for _, wo := range o {
+		wo(w)
+	}
+	return w
+}
+
+// Source returns a source of events for objects that have expired.
+func (w *Wheel) Source() source.Source {
+	return &source.Channel{Source: w.events}
+}
+
+// Schedule the supplied object to expire at the supplied time, replacing any
+// time at which it was previously scheduled to expire.
+func (w *Wheel) Schedule(nn types.NamespacedName, at time.Time) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.cancel(nn)
+
+	ticks := int(at.Sub(w.last) / w.tick)
+	if at.Sub(w.last)%w.tick != 0 {
+		ticks++
+	}
+	if ticks < 1 {
+		ticks = 1
+	}
+	s := (w.pos + ticks) % len(w.slots)
+	if w.slots[s] == nil {
+		w.slots[s] = map[types.NamespacedName]time.Time{}
+	}
+	w.slots[s][nn] = at
+	w.index[nn] = s
+}
+
+// Cancel the expiry of the supplied object.
+func (w *Wheel) Cancel(nn types.NamespacedName) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	w.cancel(nn)
+}
+
+func (w *Wheel) cancel(nn types.NamespacedName) {
+	s, ok := w.index[nn]
+	if !ok {
+		return
+	}
+	delete(w.slots[s], nn)
+	delete(w.index, nn)
+}
+
+// Start advancing the Wheel until the supplied channel is closed.
+func (w *Wheel) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(w.tick)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-t.C:
+			for _, nn := range w.advance(now) {
+				select {
+				case w.events <- event.GenericEvent{Meta: &metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name}}:
+				case <-stop:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// advance the Wheel by one slot, returning the objects in that slot that have
+// expired as of the supplied time.
+func (w *Wheel) advance(now time.Time) []types.NamespacedName {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.pos = (w.pos + 1) % len(w.slots)
+	w.last = now
+
+	next := (w.pos + 1) % len(w.slots)
+	expired := make([]types.NamespacedName, 0)
+	for nn, at := range w.slots[w.pos] {
+		// Ticks may fire slightly early. Objects that expire before the
+		// next tick are moved to the next slot rather than waiting for the
+		// Wheel to complete a revolution.
+		if at.After(now) {
+			if at.Sub(now) <= w.tick && next != w.pos {
+				delete(w.slots[w.pos], nn)
+				if w.slots[next] == nil {
+					w.slots[next] = map[types.NamespacedName]time.Time{}
+				}
+				w.slots[next][nn] = at
+				w.index[nn] = next
+			}
+			continue
+		}
+		expired = append(expired, nn)
+		delete(w.slots[w.pos], nn)
+		delete(w.index, nn)
+	}
+	return expired
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ Scheduler = &Wheel{}
+var _ Scheduler = NopScheduler{}
+
+func TestWheel(t *testing.T) {
+	start := time.Now()
+	a := types.NamespacedName{Namespace: "cool", Name: "a"}
+	b := types.NamespacedName{Namespace: "cool", Name: "b"}
+
+	type schedule struct {
+		nn    types.NamespacedName
+		after time.Duration
+	}
+
+	cases := map[string]struct {
+		reason   string
+		schedule []schedule
+		cancel   []types.NamespacedName
+		ticks    int
+		want     []types.NamespacedName
+	}{
+		"NotYetExpired": {
+			reason:   "Objects should not be emitted before they expire.",
+			schedule: []schedule{{nn: a, after: 3 * time.Second}},
+			ticks:    2,
+			want:     []types.NamespacedName{},
+		},
+		"Expired": {
+			reason:   "Objects should be emitted once they expire.",
+			schedule: []schedule{{nn: a, after: 3 * time.Second}, {nn: b, after: 5 * time.Second}},
+			ticks:    3,
+			want:     []types.NamespacedName{a},
+		},
+		"AlreadyExpired": {
+			reason:   "Objects that have already expired should be emitted at the next tick.",
+			schedule: []schedule{{nn: a, after: -1 * time.Hour}},
+			ticks:    1,
+			want:     []types.NamespacedName{a},
+		},
+		"BeyondOneRevolution": {
+			reason:   "Objects that expire more than one revolution in the future should not be emitted until they expire.",
+			schedule: []schedule{{nn: a, after: 6 * time.Second}},
+			ticks:    6,
+			want:     []types.NamespacedName{a},
+		},
+		"Rescheduled": {
+			reason:   "Rescheduling an object should replace the time at which it expires.",
+			schedule: []schedule{{nn: a, after: 1 * time.Second}, {nn: a, after: 10 * time.Second}},
+			ticks:    3,
+			want:     []types.NamespacedName{},
+		},
+		"Cancelled": {
+			reason:   "Cancelled objects should not be emitted.",
+			schedule: []schedule{{nn: a, after: 1 * time.Second}, {nn: b, after: 1 * time.Second}},
+			cancel:   []types.NamespacedName{a},
+			ticks:    1,
+			want:     []types.NamespacedName{b},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := NewWheel(WithTick(time.Second), WithSlots(4))
+			w.last = start
+
+			for _, s := range tc.schedule {
+				w.Schedule(s.nn, start.Add(s.after))
+			}
+			for _, nn := range tc.cancel {
+				w.Cancel(nn)
+			}
+
+			got := []types.NamespacedName{}
+			for i := 1; i <= tc.ticks; i++ {
+				got = append(got, w.advance(start.Add(time.Duration(i)*time.Second))...)
+			}
+
+			less := func(i, j types.NamespacedName) bool { return i.String() < j.String() }
+			if diff := cmp.Diff(tc.want, got, cmpopts.SortSlices(less)); diff != "" {
+				t.Errorf("\nReason: %s\nw.advance(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health rolls the status of the workloads and traits of each
+// ApplicationConfiguration up into an ApplicationHealth.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetAppConfig   = "cannot get application configuration"
+	errParseAppConfig = "cannot parse application configuration status"
+	errGetHealth      = "cannot get application health"
+	errUpdateHealth   = "cannot update application health"
+	errGetObject      = "cannot get object"
+	errParseObject    = "cannot parse object status"
+)
+
+// Reconcile event reasons.
+const (
+	reasonCannotRollUp = "CannotRollUpHealth"
+)
+
+// Reasons an application is or is not ready, synced, or degraded.
+const (
+	ReasonReady        v1alpha1.ConditionReason = "AllComponentsReady"
+	ReasonNotReady     v1alpha1.ConditionReason = "ComponentsNotReady"
+	ReasonSynced       v1alpha1.ConditionReason = "AllComponentsSynced"
+	ReasonNotSynced    v1alpha1.ConditionReason = "ComponentsNotSynced"
+	ReasonHealthy      v1alpha1.ConditionReason = "NoComponentsDegraded"
+	ReasonDegraded     v1alpha1.ConditionReason = "ComponentsDegraded"
+	ReasonNoComponents v1alpha1.ConditionReason = "NoComponents"
+)
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// A Reconciler reconciles ApplicationConfigurations by rolling the status of
+// their workloads and traits up into an ApplicationHealth.
+type Reconciler struct {
+	client client.Client
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler that reconciles ApplicationConfigurations.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// A reference to a workload or trait in the status of an
+// ApplicationConfiguration.
+type reference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// The parts of an ApplicationConfiguration's status that reference the
+// workloads and traits it rendered.
+type appConfigStatus struct {
+	Workloads []struct {
+		ComponentName string    `json:"componentName"`
+		Reference     reference `json:"workloadRef"`
+		Traits        []struct {
+			Reference reference `json:"traitRef"`
+		} `json:"traits,omitempty"`
+	} `json:"workloads,omitempty"`
+}
+
+// Reconcile an ApplicationConfiguration by rolling the status of its workloads
+// and traits up into an ApplicationHealth of the same name.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	ac := &unstructured.Unstructured{}
+	ac.SetGroupVersionKind(oamv1alpha2.ApplicationConfigurationGroupVersionKind)
+	if err := r.client.Get(ctx, req.NamespacedName, ac); err != nil {
+		// The ApplicationHealth of a deleted ApplicationConfiguration is
+		// garbage collected.
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetAppConfig)
+	}
+
+	s := appConfigStatus{}
+	if err := convert(ac.Object["status"], &s); err != nil {
+		log.Debug("Cannot parse application configuration status", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotRollUp, err))
+		return reconcile.Result{}, errors.Wrap(err, errParseAppConfig)
+	}
+
+	components := make([]remotev1alpha1.ComponentHealth, 0, len(s.Workloads))
+	for _, w := range s.Workloads {
+		c := remotev1alpha1.ComponentHealth{
+			ComponentName: w.ComponentName,
+			Workload:      r.observe(ctx, req.Namespace, w.Reference),
+		}
+		for _, t := range w.Traits {
+			c.Traits = append(c.Traits, r.observe(ctx, req.Namespace, t.Reference))
+		}
+		components = append(components, c)
+	}
+
+	h := &remotev1alpha1.ApplicationHealth{}
+	err := r.client.Get(ctx, req.NamespacedName, h)
+	if resource.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.Wrap(err, errGetHealth)
+	}
+	exists := err == nil
+	before := h.Status.DeepCopy()
+
+	h.Status.Components = components
+	h.SetConditions(RollUp(components)...)
+
+	switch {
+	case !exists:
+		h.SetNamespace(req.Namespace)
+		h.SetName(req.Name)
+		meta.AddOwnerReference(h, *metav1.NewControllerRef(ac, oamv1alpha2.ApplicationConfigurationGroupVersionKind))
+		err = r.client.Create(ctx, h)
+	case !reflect.DeepEqual(before, &h.Status):
+		err = r.client.Update(ctx, h)
+	}
+	if err != nil {
+		log.Debug("Cannot update application health", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotRollUp, err))
+		return reconcile.Result{}, errors.Wrap(err, errUpdateHealth)
+	}
+
+	// Workloads and traits of kinds that are not watched do not trigger a
+	// reconcile when their status changes, so we check them periodically.
+	return reconcile.Result{RequeueAfter: longWait}, nil
+}
+
+// observe the health of the referenced workload or trait.
+func (r *Reconciler) observe(ctx context.Context, namespace string, ref reference) remotev1alpha1.ObjectHealth {
+	h := remotev1alpha1.ObjectHealth{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name}
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, u); err != nil {
+		h.Message = errors.Wrap(err, errGetObject).Error()
+		return h
+	}
+
+	cs := v1alpha1.ConditionedStatus{}
+	if err := convert(u.Object["status"], &cs); err != nil {
+		h.Message = errors.Wrap(err, errParseObject).Error()
+		return h
+	}
+
+	ready, synced, degraded := cs.GetCondition(v1alpha1.TypeReady), cs.GetCondition(v1alpha1.TypeSynced), cs.GetCondition(trait.TypeDegraded)
+	h.Ready, h.Synced, h.Degraded = ready.Status, synced.Status, degraded.Status
+
+	switch {
+	case degraded.Status == corev1.ConditionTrue:
+		h.Message = degraded.Message
+	case synced.Status == corev1.ConditionFalse:
+		h.Message = synced.Message
+	case ready.Status == corev1.ConditionFalse:
+		h.Message = ready.Message
+	}
+	return h
+}
+
+// RollUp the health of the supplied components into Ready, Synced, and
+// Degraded conditions. An application is synced if all of its workloads and
+// traits are synced, and degraded if any of them are degraded. It is ready if
+// it is synced, is not degraded, and none of its workloads and traits are not
+// ready. Workloads and traits that do not report whether they are ready are
+// assumed to be ready.
+func RollUp(components []remotev1alpha1.ComponentHealth) []v1alpha1.Condition {
+	if len(components) == 0 {
+		return []v1alpha1.Condition{
+			condition(v1alpha1.TypeReady, corev1.ConditionFalse, ReasonNoComponents, nil),
+			condition(v1alpha1.TypeSynced, corev1.ConditionFalse, ReasonNoComponents, nil),
+			condition(trait.TypeDegraded, corev1.ConditionFalse, ReasonNoComponents, nil),
+		}
+	}
+
+	var notReady, notSynced, degraded []string
+	for _, c := range components {
+		for _, o := range append([]remotev1alpha1.ObjectHealth{c.Workload}, c.Traits...) {
+			id := fmt.Sprintf("%s/%s", o.Kind, o.Name)
+			if o.Ready == corev1.ConditionFalse {
+				notReady = append(notReady, id)
+			}
+			if o.Synced != corev1.ConditionTrue {
+				notSynced = append(notSynced, id)
+			}
+			if o.Degraded == corev1.ConditionTrue {
+				degraded = append(degraded, id)
+			}
+		}
+	}
+
+	cs := make([]v1alpha1.Condition, 0, 3)
+
+	switch {
+	case len(notReady) > 0:
+		cs = append(cs, condition(v1alpha1.TypeReady, corev1.ConditionFalse, ReasonNotReady, notReady))
+	case len(notSynced) > 0 || len(degraded) > 0:
+		cs = append(cs, condition(v1alpha1.TypeReady, corev1.ConditionFalse, ReasonNotReady, nil))
+	default:
+		cs = append(cs, condition(v1alpha1.TypeReady, corev1.ConditionTrue, ReasonReady, nil))
+	}
+
+	if len(notSynced) > 0 {
+		cs = append(cs, condition(v1alpha1.TypeSynced, corev1.ConditionFalse, ReasonNotSynced, notSynced))
+	} else {
+		cs = append(cs, condition(v1alpha1.TypeSynced, corev1.ConditionTrue, ReasonSynced, nil))
+	}
+
+	if len(degraded) > 0 {
+		cs = append(cs, condition(trait.TypeDegraded, corev1.ConditionTrue, ReasonDegraded, degraded))
+	} else {
+		cs = append(cs, condition(trait.TypeDegraded, corev1.ConditionFalse, ReasonHealthy, nil))
+	}
+
+	return cs
+}
+
+func condition(ct v1alpha1.ConditionType, s corev1.ConditionStatus, r v1alpha1.ConditionReason, ids []string) v1alpha1.Condition {
+	c := v1alpha1.Condition{
+		Type:               ct,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+	}
+	if len(ids) > 0 {
+		c.Message = strings.Join(ids, ", ")
+	}
+	return c
+}
+
+// convert the supplied unstructured status into the supplied struct.
+func convert(status interface{}, into interface{}) error {
+	if status == nil {
+		return nil
+	}
+	b, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, into)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func TestReconciler(t *testing.T) {
+	type args struct {
+		c client.Client
+		o []ReconcilerOption
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	// get returns a MockGetFn that reads an ApplicationConfiguration with one
+	// component, whose workload is synced and whose trait is degraded.
+	get := func(health error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *remotev1alpha1.ApplicationHealth:
+				return health
+			case *unstructured.Unstructured:
+				switch o.GetKind() {
+				case "ApplicationConfiguration":
+					o.Object["status"] = map[string]interface{}{
+						"workloads": []interface{}{map[string]interface{}{
+							"componentName": "web",
+							"workloadRef":   map[string]interface{}{"apiVersion": "core.oam.dev/v1alpha2", "kind": "ContainerizedWorkload", "name": "web"},
+							"traits": []interface{}{map[string]interface{}{
+								"traitRef": map[string]interface{}{"apiVersion": "core.oam.dev/v1alpha2", "kind": "ManualScalerTrait", "name": "web-scaler"},
+							}},
+						}},
+					}
+				case "ContainerizedWorkload":
+					o.Object["status"] = map[string]interface{}{"conditions": []interface{}{
+						map[string]interface{}{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"},
+					}}
+				case "ManualScalerTrait":
+					o.Object["status"] = map[string]interface{}{"conditions": []interface{}{
+						map[string]interface{}{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"},
+						map[string]interface{}{"type": "Degraded", "status": "True", "reason": "PersistentFailure", "message": "failed"},
+					}}
+				}
+			}
+			return nil
+		}
+	}
+
+	wantHealth := func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+		h := obj.(*remotev1alpha1.ApplicationHealth)
+		want := []remotev1alpha1.ComponentHealth{{
+			ComponentName: "web",
+			Workload: remotev1alpha1.ObjectHealth{
+				APIVersion: "core.oam.dev/v1alpha2",
+				Kind:       "ContainerizedWorkload",
+				Name:       "web",
+				Ready:      corev1.ConditionUnknown,
+				Synced:     corev1.ConditionTrue,
+				Degraded:   corev1.ConditionUnknown,
+			},
+			Traits: []remotev1alpha1.ObjectHealth{{
+				APIVersion: "core.oam.dev/v1alpha2",
+				Kind:       "ManualScalerTrait",
+				Name:       "web-scaler",
+				Ready:      corev1.ConditionUnknown,
+				Synced:     corev1.ConditionTrue,
+				Degraded:   corev1.ConditionTrue,
+				Message:    "failed",
+			}},
+		}}
+		if diff := cmp.Diff(want, h.Status.Components); diff != "" {
+			return errors.Errorf("MockCreate: -want, +got: %s", diff)
+		}
+		if diff := cmp.Diff(ReasonDegraded, h.GetCondition(trait.TypeDegraded).Reason); diff != "" {
+			return errors.Errorf("MockCreate: -want, +got: %s", diff)
+		}
+		if len(h.GetOwnerReferences()) != 1 {
+			return errors.New("MockCreate: want a controller reference to the application configuration")
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AppConfigNotFound": {
+			reason: "Not found errors encountered while getting the application configuration should be ignored.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"GetAppConfigError": {
+			reason: "Errors getting the application configuration should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetAppConfig)},
+		},
+		"GetHealthError": {
+			reason: "Errors getting the application health should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: get(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetHealth)},
+		},
+		"CreateHealthError": {
+			reason: "Errors creating the application health should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    get(notFound),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errUpdateHealth)},
+		},
+		"CreateHealth": {
+			reason: "The health of each component should be rolled up into a new application health.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    get(notFound),
+					MockCreate: wantHealth,
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"UpdateHealthError": {
+			reason: "Errors updating an existing application health should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    get(nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errUpdateHealth)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{Client: tc.args.c}, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "cool", Name: "app"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	healthy := remotev1alpha1.ObjectHealth{Kind: "ContainerizedWorkload", Name: "web", Synced: corev1.ConditionTrue}
+
+	type want struct {
+		ready    v1alpha1.Condition
+		synced   v1alpha1.Condition
+		degraded v1alpha1.Condition
+	}
+
+	cases := map[string]struct {
+		reason     string
+		components []remotev1alpha1.ComponentHealth
+		want       want
+	}{
+		"NoComponents": {
+			reason: "An application without components should be neither ready nor synced.",
+			want: want{
+				ready:    condition(v1alpha1.TypeReady, corev1.ConditionFalse, ReasonNoComponents, nil),
+				synced:   condition(v1alpha1.TypeSynced, corev1.ConditionFalse, ReasonNoComponents, nil),
+				degraded: condition(trait.TypeDegraded, corev1.ConditionFalse, ReasonNoComponents, nil),
+			},
+		},
+		"Healthy": {
+			reason: "An application whose workloads and traits are synced and not degraded should be ready.",
+			components: []remotev1alpha1.ComponentHealth{{
+				ComponentName: "web",
+				Workload:      healthy,
+				Traits:        []remotev1alpha1.ObjectHealth{{Kind: "ManualScalerTrait", Name: "web", Synced: corev1.ConditionTrue, Degraded: corev1.ConditionFalse}},
+			}},
+			want: want{
+				ready:    condition(v1alpha1.TypeReady, corev1.ConditionTrue, ReasonReady, nil),
+				synced:   condition(v1alpha1.TypeSynced, corev1.ConditionTrue, ReasonSynced, nil),
+				degraded: condition(trait.TypeDegraded, corev1.ConditionFalse, ReasonHealthy, nil),
+			},
+		},
+		"Unhealthy": {
+			reason: "An application with workloads or traits that are not ready, not synced, or degraded should report them.",
+			components: []remotev1alpha1.ComponentHealth{{
+				ComponentName: "web",
+				Workload:      remotev1alpha1.ObjectHealth{Kind: "ContainerizedWorkload", Name: "web", Ready: corev1.ConditionFalse, Synced: corev1.ConditionFalse},
+				Traits:        []remotev1alpha1.ObjectHealth{{Kind: "ManualScalerTrait", Name: "web", Synced: corev1.ConditionTrue, Degraded: corev1.ConditionTrue}},
+			}},
+			want: want{
+				ready:    condition(v1alpha1.TypeReady, corev1.ConditionFalse, ReasonNotReady, []string{"ContainerizedWorkload/web"}),
+				synced:   condition(v1alpha1.TypeSynced, corev1.ConditionFalse, ReasonNotSynced, []string{"ContainerizedWorkload/web"}),
+				degraded: condition(trait.TypeDegraded, corev1.ConditionTrue, ReasonDegraded, []string{"ManualScalerTrait/web"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RollUp(tc.components)
+			want := []v1alpha1.Condition{tc.want.ready, tc.want.synced, tc.want.degraded}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\nReason: %s\nRollUp(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package message allows the user-facing strings emitted by OAM Kubernetes
+// Remote reconcilers to be customized.
+package message
+
+import (
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// A Catalog replaces the reasons and messages of the events and conditions
+// emitted by a reconciler, for example to localize them or to standardize
+// them across controllers. Its keys are the default reasons and messages, for
+// example "ReconcileSuccess" or "Successfully translated workload", and its
+// values are their replacements. Strings that are not in the catalog are
+// emitted unchanged. A nil Catalog replaces nothing.
+type Catalog map[string]string
+
+// Get the replacement for the supplied string, or the string itself if the
+// catalog has no replacement for it.
+func (c Catalog) Get(s string) string {
+	if r, ok := c[s]; ok {
+		return r
+	}
+	return s
+}
+
+// Event returns the supplied event with its reason and message replaced.
+func (c Catalog) Event(e event.Event) event.Event {
+	e.Reason = event.Reason(c.Get(string(e.Reason)))
+	e.Message = c.Get(e.Message)
+	return e
+}
+
+// Conditions returns the supplied conditions with their reasons and messages
+// replaced.
+func (c Catalog) Conditions(cs ...v1alpha1.Condition) []v1alpha1.Condition {
+	out := make([]v1alpha1.Condition, len(cs))
+	for i, cd := range cs {
+		cd.Reason = v1alpha1.ConditionReason(c.Get(string(cd.Reason)))
+		cd.Message = c.Get(cd.Message)
+		out[i] = cd
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package message
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+func TestEvent(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      Catalog
+		e      event.Event
+		want   event.Event
+	}{
+		"NilCatalog": {
+			reason: "A nil catalog should replace nothing.",
+			e:      event.Normal("WorkloadTranslated", "Successfully translated workload"),
+			want:   event.Normal("WorkloadTranslated", "Successfully translated workload"),
+		},
+		"Replaced": {
+			reason: "Reasons and messages in the catalog should be replaced.",
+			c: Catalog{
+				"WorkloadTranslated":               "Translated",
+				"Successfully translated workload": "Workload wurde übersetzt",
+			},
+			e:    event.Normal("WorkloadTranslated", "Successfully translated workload"),
+			want: event.Normal("Translated", "Workload wurde übersetzt"),
+		},
+		"WarningMessageNotInCatalog": {
+			reason: "Messages that are not in the catalog should be emitted unchanged.",
+			c:      Catalog{"CannotTranslateWorkload": "TranslationFailed"},
+			e:      event.Warning("CannotTranslateWorkload", errors.New("boom")),
+			want:   event.Warning("TranslationFailed", errors.New("boom")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.c.Event(tc.e)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.Event(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      Catalog
+		cs     []v1alpha1.Condition
+		want   []v1alpha1.Condition
+	}{
+		"NilCatalog": {
+			reason: "A nil catalog should replace nothing.",
+			cs:     []v1alpha1.Condition{v1alpha1.ReconcileSuccess()},
+			want:   []v1alpha1.Condition{v1alpha1.ReconcileSuccess()},
+		},
+		"Replaced": {
+			reason: "Reasons in the catalog should be replaced.",
+			c:      Catalog{string(v1alpha1.ReasonReconcileSuccess): "Synced"},
+			cs:     []v1alpha1.Condition{v1alpha1.ReconcileSuccess(), v1alpha1.Available()},
+			want: []v1alpha1.Condition{
+				func() v1alpha1.Condition { c := v1alpha1.ReconcileSuccess(); c.Reason = "Synced"; return c }(),
+				v1alpha1.Available(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.c.Conditions(tc.cs...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.Conditions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errListPackages           = "cannot list packages"
+	errApplyNamespacePackage  = "cannot apply namespace package"
+	errDeleteNamespacePackage = "cannot delete namespace package"
+	errRenderNamespace        = "cannot render remote namespace template"
+	errInspectRemoteNamespace = "cannot inspect remote namespace"
+)
+
+// PackageName is the prefix of the name of each KubernetesApplication that
+// packages the remote namespace for all workloads in a namespace. Packages
+// are suffixed with the name of the target they are pinned to.
+const PackageName = "oam-remote-namespace"
+
+// LabelNamespacePackage identifies a KubernetesApplication as the package of a
+// remote namespace. Its value is the name of the remote namespace.
+const LabelNamespacePackage = "namespace.oam.crossplane.io/package"
+
+// LabelNamespaceTarget is the name of the target to which the package of a
+// remote namespace is pinned.
+const LabelNamespaceTarget = "namespace.oam.crossplane.io/target"
+
+var (
+	namespaceKind       = reflect.TypeOf(corev1.Namespace{}).Name()
+	namespaceAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithApplicator specifies how the Reconciler should apply the namespace
+// package.
+func WithApplicator(a resource.Applicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applicator = a
+	}
+}
+
+// WithLiveReader specifies a reader that bypasses the controller's cache. The
+// Reconciler uses it to confirm that no workload packages remain in a
+// namespace before it deletes the namespace package, so that it does not act
+// on a stale cache after workloads are rapidly created and deleted.
+func WithLiveReader(rd client.Reader) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.live = rd
+	}
+}
+
+// WithRemoteInspector specifies how the Reconciler should confirm that a
+// remote namespace contains only objects created by controllers before it
+// deletes the namespace.
+func WithRemoteInspector(i RemoteInspector) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.remote = i
+	}
+}
+
+// A Reconciler is a janitor for remote namespaces. It packages a remote
+// namespace on each target to which a workload package in a namespace has
+// been scheduled, and deletes that namespace package once the last workload
+// package leaves the target.
+//
+// Objects in a KubernetesApplication are created in the remote namespace
+// their template specifies, or in the remote default namespace if it
+// specifies none. The janitor packages the remote namespace with the same
+// name as the namespace of the KubernetesApplication, so workloads must be
+// translated into that remote namespace for the janitor to be of use.
+type Reconciler struct {
+	client     client.Client
+	live       client.Reader
+	applicator resource.Applicator
+	remote     RemoteInspector
+
+	log logging.Logger
+}
+
+// NewReconciler returns a Reconciler that cleans up remote namespaces.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:     m.GetClient(),
+		applicator: resource.ApplyFn(resource.Apply),
+		remote:     NewTargetInspector(m.GetClient()),
+		log:        logging.NewNopLogger(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile the remote namespaces of the supplied package's namespace.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	l := &workloadv1alpha1.KubernetesApplicationList{}
+	if err := r.client.List(ctx, l, client.InNamespace(req.Namespace)); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListPackages)
+	}
+	u := summarize(l)
+
+	// Each namespace package is pinned to the target that the workload
+	// packages that need it were scheduled to. Workload packages that have
+	// not yet been scheduled are considered once they have been.
+	result := reconcile.Result{}
+	for _, target := range u.targets() {
+		desired, err := Package(req.Namespace, &workloadv1alpha1.KubernetesTargetReference{Name: target})
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errRenderNamespace)
+		}
+		log.Debug("Ensuring remote namespace exists", "target", target)
+		if err := r.applicator.Apply(ctx, r.client, desired); err != nil {
+			return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(err, errApplyNamespacePackage)
+		}
+		result.RequeueAfter = longWait
+	}
+
+	// We only delete a remote namespace when everything that was packaged
+	// into the namespace was created by a controller. Packages created by
+	// hand may rely on the remote namespace continuing to exist, and
+	// unscheduled packages may yet be scheduled to its target.
+	unused := u.unused()
+	if len(unused) == 0 || u.uncontrolled > 0 || u.unscheduled > 0 {
+		return result, nil
+	}
+
+	// The cache may not yet have observed packages that were created shortly
+	// before the last one was deleted. Deleting the namespace package then
+	// would orphan their remote resources, so we confirm that the namespace
+	// is unused with a live read if we can.
+	if r.live != nil {
+		live := &workloadv1alpha1.KubernetesApplicationList{}
+		if err := r.live.List(ctx, live, client.InNamespace(req.Namespace)); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errListPackages)
+		}
+		lu := summarize(live)
+		if lu.uncontrolled > 0 || lu.unscheduled > 0 {
+			log.Debug("Remote namespace may still be in use")
+			return reconcile.Result{Requeue: true}, nil
+		}
+		unused = lu.unused()
+	}
+
+	for _, ns := range unused {
+		// Objects may have been created in the remote namespace by hand, or
+		// by another tool. Deleting the namespace would delete them too.
+		if t := ns.Spec.Target; t != nil {
+			found, err := r.remote.Uncontrolled(ctx, types.NamespacedName{Namespace: req.Namespace, Name: t.Name}, req.Namespace)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errInspectRemoteNamespace)
+			}
+			if len(found) > 0 {
+				log.Debug("Remote namespace contains objects that were not created by a controller", "target", t.Name, "objects", strings.Join(found, ", "))
+				result.RequeueAfter = longWait
+				continue
+			}
+		}
+
+		log.Debug("Deleting remote namespace", "name", ns.GetName())
+		if err := resource.IgnoreNotFound(r.client.Delete(ctx, ns)); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errDeleteNamespacePackage)
+		}
+	}
+	return result, nil
+}
+
+// A usage summarises how the packages in a namespace use its remote
+// namespaces.
+type usage struct {
+	// namespaces are the namespace packages, by the name of their target.
+	// Unscheduled namespace packages have an empty target name.
+	namespaces map[string]*workloadv1alpha1.KubernetesApplication
+
+	// used are the targets that controlled packages are scheduled to.
+	used map[string]bool
+
+	// unscheduled is the number of controlled packages that have not been
+	// scheduled to a target.
+	unscheduled int
+
+	// uncontrolled is the number of packages that were not created by a
+	// controller.
+	uncontrolled int
+}
+
+// summarize how the packages in the supplied list use their remote
+// namespaces. Packages that are being deleted no longer use them.
+func summarize(l *workloadv1alpha1.KubernetesApplicationList) usage {
+	u := usage{namespaces: map[string]*workloadv1alpha1.KubernetesApplication{}, used: map[string]bool{}}
+	for i := range l.Items {
+		app := &l.Items[i]
+		switch {
+		case app.GetLabels()[LabelNamespacePackage] != "":
+			if !meta.WasDeleted(app) {
+				u.namespaces[targetName(app)] = app
+			}
+		case meta.WasDeleted(app):
+		case metav1.GetControllerOf(app) == nil:
+			u.uncontrolled++
+		case app.Spec.Target == nil:
+			u.unscheduled++
+		default:
+			u.used[app.Spec.Target.Name] = true
+		}
+	}
+	return u
+}
+
+// targets returns the sorted names of the targets controlled packages are
+// scheduled to.
+func (u usage) targets() []string {
+	t := make([]string, 0, len(u.used))
+	for name := range u.used {
+		t = append(t, name)
+	}
+	sort.Strings(t)
+	return t
+}
+
+// unused returns the namespace packages of targets that no controlled package
+// is scheduled to, sorted by name.
+func (u usage) unused() []*workloadv1alpha1.KubernetesApplication {
+	ns := []*workloadv1alpha1.KubernetesApplication{}
+	for target, app := range u.namespaces {
+		if !u.used[target] {
+			ns = append(ns, app)
+		}
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].GetName() < ns[j].GetName() })
+	return ns
+}
+
+func targetName(app *workloadv1alpha1.KubernetesApplication) string {
+	if app.Spec.Target == nil {
+		return ""
+	}
+	return app.Spec.Target.Name
+}
+
+// Package returns the KubernetesApplication that packages the remote namespace
+// for the supplied namespace on the supplied target.
+func Package(namespace string, target *workloadv1alpha1.KubernetesTargetReference) (*workloadv1alpha1.KubernetesApplication, error) {
+	n := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       namespaceKind,
+			APIVersion: namespaceAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}
+	b, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// The resources of a KubernetesApplication are named after its
+	// templates, so each namespace package names its template after itself.
+	name := PackageName + "-" + target.Name
+	labels := map[string]string{LabelNamespacePackage: namespace, LabelNamespaceTarget: target.Name}
+	app := &workloadv1alpha1.KubernetesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: workloadv1alpha1.KubernetesApplicationSpec{
+			ResourceSelector: &metav1.LabelSelector{MatchLabels: labels},
+			Target:           target,
+			ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: labels,
+					},
+					Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+						Template: runtime.RawExtension{Raw: b},
+					},
+				},
+			},
+		},
+	}
+	return app, nil
+}
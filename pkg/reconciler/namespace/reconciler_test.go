@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func TestReconciler(t *testing.T) {
+	type args struct {
+		c client.Client
+		o []ReconcilerOption
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+	ns := "cool-namespace"
+	truth := true
+
+	withPackages := func(apps ...workloadv1alpha1.KubernetesApplication) func(context.Context, runtime.Object, ...client.ListOption) error {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*workloadv1alpha1.KubernetesApplicationList)
+			l.Items = apps
+			return nil
+		}
+	}
+
+	target := &workloadv1alpha1.KubernetesTargetReference{Name: "cool-target"}
+	nsPkg, _ := Package(ns, target)
+	controlled := workloadv1alpha1.KubernetesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cool-workload",
+			OwnerReferences: []metav1.OwnerReference{{Controller: &truth}},
+		},
+		Spec: workloadv1alpha1.KubernetesApplicationSpec{Target: target},
+	}
+	unscheduled := workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{
+		Name:            "cool-workload",
+		OwnerReferences: []metav1.OwnerReference{{Controller: &truth}},
+	}}
+	uncontrolled := workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Name: "hand-made"}}
+	unowned := WithRemoteInspector(RemoteInspectorFn(func(_ context.Context, _ types.NamespacedName, _ string) ([]string, error) {
+		return nil, nil
+	}))
+
+	otherPkg, _ := Package(ns, &workloadv1alpha1.KubernetesTargetReference{Name: "other-target"})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing packages should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errListPackages)},
+		},
+		"EnsureNamespace": {
+			reason: "The namespace package should be applied to the target of a controlled package while it exists.",
+			args: args{
+				c: &test.MockClient{MockList: withPackages(controlled)},
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+					if diff := cmp.Diff(nsPkg, o); diff != "" {
+						t.Errorf("Apply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ApplyError": {
+			reason: "Errors applying the namespace package should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: withPackages(controlled)},
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					return errBoom
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}, err: errors.Wrap(errBoom, errApplyNamespacePackage)},
+		},
+		"KeepNamespaceWithUnscheduledPackages": {
+			reason: "The namespace package should not be deleted while controlled packages may yet be scheduled to its target.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg, unscheduled),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				o: []ReconcilerOption{unowned},
+			},
+			want: want{},
+		},
+		"KeepNamespaceWithUncontrolledPackages": {
+			reason: "The namespace package should not be deleted while packages that were not created by a controller exist.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg, uncontrolled),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				o: []ReconcilerOption{unowned},
+			},
+			want: want{},
+		},
+		"KeepNamespaceWithUncontrolledRemoteObjects": {
+			reason: "The namespace package should not be deleted while the remote namespace contains objects that were not created by a controller.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				o: []ReconcilerOption{WithRemoteInspector(RemoteInspectorFn(func(_ context.Context, got types.NamespacedName, _ string) ([]string, error) {
+					if diff := cmp.Diff(types.NamespacedName{Namespace: ns, Name: target.Name}, got); diff != "" {
+						t.Errorf("Uncontrolled(...): -want, +got:\n%s", diff)
+					}
+					return []string{"configmap/hand-made"}, nil
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"InspectRemoteNamespaceError": {
+			reason: "Errors inspecting the remote namespace should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				o: []ReconcilerOption{WithRemoteInspector(RemoteInspectorFn(func(_ context.Context, _ types.NamespacedName, _ string) ([]string, error) {
+					return nil, errBoom
+				}))},
+			},
+			want: want{err: errors.Wrap(errBoom, errInspectRemoteNamespace)},
+		},
+		"DeleteNamespace": {
+			reason: "The namespace package should be deleted when the last controlled package leaves.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				o: []ReconcilerOption{unowned},
+			},
+			want: want{},
+		},
+		"DeleteNamespaceOfUnusedTarget": {
+			reason: "Only the namespace package of the target no controlled package is scheduled to should be deleted.",
+			args: args{
+				c: &test.MockClient{
+					MockList: withPackages(*nsPkg, *otherPkg, controlled),
+					MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+						if diff := cmp.Diff(otherPkg.GetName(), obj.(*workloadv1alpha1.KubernetesApplication).GetName()); diff != "" {
+							t.Errorf("Delete(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				o: []ReconcilerOption{
+					unowned,
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"DeleteNamespaceError": {
+			reason: "Errors deleting the namespace package should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withPackages(*nsPkg),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				o: []ReconcilerOption{unowned},
+			},
+			want: want{err: errors.Wrap(errBoom, errDeleteNamespacePackage)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{Client: tc.args.c}, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: "cool-workload"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
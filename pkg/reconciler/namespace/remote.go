@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+// Remote inspection error strings.
+const (
+	errGetTarget           = "cannot get target"
+	errNoConnectionSecret  = "target has no connection secret"
+	errGetConnectionSecret = "cannot get target connection secret"
+	errParseEndpoint       = "cannot parse target endpoint as URL"
+	errParseKubeconfig     = "cannot parse target kubeconfig"
+	errNewRemoteClient     = "cannot create remote cluster client"
+	errDiscoverResources   = "cannot discover remote resources"
+	errListRemoteObjects   = "cannot list remote objects"
+)
+
+// AnnotationRemoteControllerUID is set by the KubernetesApplicationResource
+// controller on each remote object it creates. Its value is the UID of the
+// KubernetesApplicationResource that controls the object.
+var AnnotationRemoteControllerUID = workloadv1alpha1.KubernetesApplicationGroupVersionKind.GroupKind().String() + "/uid"
+
+// Remote resources that are never created by users, and that therefore never
+// keep a remote namespace alive.
+var ignoredResources = map[string]bool{
+	"events":    true,
+	"endpoints": true,
+}
+
+// A RemoteInspector finds the objects in a remote namespace that were not
+// created by a controller.
+type RemoteInspector interface {
+	// Uncontrolled returns a description of each object in the supplied
+	// namespace of the supplied KubernetesTarget that was not created by a
+	// controller.
+	Uncontrolled(ctx context.Context, target types.NamespacedName, namespace string) ([]string, error)
+}
+
+// A RemoteInspectorFn is a function that satisfies the RemoteInspector
+// interface.
+type RemoteInspectorFn func(ctx context.Context, target types.NamespacedName, namespace string) ([]string, error)
+
+// Uncontrolled returns a description of each object in the supplied namespace
+// of the supplied KubernetesTarget that was not created by a controller.
+func (fn RemoteInspectorFn) Uncontrolled(ctx context.Context, target types.NamespacedName, namespace string) ([]string, error) {
+	return fn(ctx, target, namespace)
+}
+
+// A RemoteClientFn returns clients of the remote cluster described by the
+// supplied configuration.
+type RemoteClientFn func(cfg *rest.Config) (discovery.DiscoveryInterface, dynamic.Interface, error)
+
+// NewRemoteClients returns clients of the remote cluster described by the
+// supplied configuration.
+func NewRemoteClients(cfg *rest.Config) (discovery.DiscoveryInterface, dynamic.Interface, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	return dc, dyn, err
+}
+
+// A TargetInspector inspects remote namespaces using the credentials that the
+// KubernetesApplicationResource controller uses, which are read from the
+// connection secret of a KubernetesTarget.
+type TargetInspector struct {
+	client    client.Reader
+	newClient RemoteClientFn
+}
+
+// NewTargetInspector returns a RemoteInspector that reads KubernetesTargets
+// and their connection secrets using the supplied client.
+func NewTargetInspector(c client.Reader) *TargetInspector {
+	return &TargetInspector{client: c, newClient: NewRemoteClients}
+}
+
+// Uncontrolled returns a description of each object in the supplied namespace
+// of the supplied KubernetesTarget that was not created by a controller. Every
+// kind of namespaced resource that the remote cluster serves is listed.
+func (i *TargetInspector) Uncontrolled(ctx context.Context, target types.NamespacedName, namespace string) ([]string, error) {
+	cfg, err := i.config(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	dc, dyn, err := i.newClient(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRemoteClient)
+	}
+
+	// We cannot tell whether a namespace is unused if we cannot discover
+	// every kind of resource it may contain, so partial discovery failures
+	// are errors too.
+	lists, err := discovery.ServerPreferredNamespacedResources(dc)
+	if err != nil {
+		return nil, errors.Wrap(err, errDiscoverResources)
+	}
+
+	found := []string{}
+	for _, l := range lists {
+		gv, err := schema.ParseGroupVersion(l.GroupVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, errDiscoverResources)
+		}
+		for _, r := range l.APIResources {
+			if ignoredResources[r.Name] || !listable(r) {
+				continue
+			}
+			ul, err := dyn.Resource(gv.WithResource(r.Name)).Namespace(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return nil, errors.Wrap(err, errListRemoteObjects)
+			}
+			for i := range ul.Items {
+				if !Controlled(&ul.Items[i]) {
+					found = append(found, fmt.Sprintf("%s/%s", strings.ToLower(ul.Items[i].GetKind()), ul.Items[i].GetName()))
+				}
+			}
+		}
+	}
+	sort.Strings(found)
+	return found, nil
+}
+
+// config returns the configuration of a client of the supplied
+// KubernetesTarget, per the KubernetesApplicationResource controller.
+func (i *TargetInspector) config(ctx context.Context, target types.NamespacedName) (*rest.Config, error) {
+	k := &workloadv1alpha1.KubernetesTarget{}
+	if err := i.client.Get(ctx, target, k); err != nil {
+		return nil, errors.Wrap(err, errGetTarget)
+	}
+	ref := k.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return nil, errors.New(errNoConnectionSecret)
+	}
+	s := &corev1.Secret{}
+	if err := i.client.Get(ctx, types.NamespacedName{Namespace: k.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetConnectionSecret)
+	}
+	return RemoteConfig(s)
+}
+
+// RemoteConfig returns the configuration of a client of the remote cluster
+// described by the supplied connection secret.
+func RemoteConfig(s *corev1.Secret) (*rest.Config, error) {
+	if kc := s.Data[runtimev1alpha1.ResourceCredentialsSecretKubeconfigKey]; len(kc) != 0 {
+		cfg, err := clientcmd.RESTConfigFromKubeConfig(kc)
+		return cfg, errors.Wrap(err, errParseKubeconfig)
+	}
+
+	u, err := url.Parse(string(s.Data[runtimev1alpha1.ResourceCredentialsSecretEndpointKey]))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseEndpoint)
+	}
+	return &rest.Config{
+		Host:     u.String(),
+		Username: string(s.Data[runtimev1alpha1.ResourceCredentialsSecretUserKey]),
+		Password: string(s.Data[runtimev1alpha1.ResourceCredentialsSecretPasswordKey]),
+		TLSClientConfig: rest.TLSClientConfig{
+			ServerName: u.Hostname(),
+			CAData:     s.Data[runtimev1alpha1.ResourceCredentialsSecretCAKey],
+			CertData:   s.Data[runtimev1alpha1.ResourceCredentialsSecretClientCertKey],
+			KeyData:    s.Data[runtimev1alpha1.ResourceCredentialsSecretClientKeyKey],
+		},
+		BearerToken: string(s.Data[runtimev1alpha1.ResourceCredentialsSecretTokenKey]),
+	}, nil
+}
+
+// Controlled returns true if the supplied remote object was created by a
+// controller, either by the KubernetesApplicationResource controller, by the
+// controller of its owner, or by the remote control plane for every namespace.
+func Controlled(u *unstructured.Unstructured) bool {
+	if u.GetAnnotations()[AnnotationRemoteControllerUID] != "" || len(u.GetOwnerReferences()) > 0 {
+		return true
+	}
+	switch u.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "ServiceAccount"}:
+		return u.GetName() == "default"
+	case schema.GroupKind{Kind: "ConfigMap"}:
+		return u.GetName() == "kube-root-ca.crt"
+	case schema.GroupKind{Kind: "Secret"}:
+		t, _, _ := unstructured.NestedString(u.Object, "type")
+		return t == string(corev1.SecretTypeServiceAccountToken)
+	}
+	return false
+}
+
+// listable returns true if the supplied resource may be listed.
+func listable(r metav1.APIResource) bool {
+	for _, v := range r.Verbs {
+		if v == "list" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package namespace
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestControlled(t *testing.T) {
+	object := func(kind, name string, fields map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		for k, v := range fields {
+			u.Object[k] = v
+		}
+		u.SetAPIVersion("v1")
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+	withAnnotations := func(u *unstructured.Unstructured, a map[string]string) *unstructured.Unstructured {
+		u.SetAnnotations(a)
+		return u
+	}
+
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		want   bool
+	}{
+		"RemoteControlled": {
+			reason: "Objects created by a KubernetesApplicationResource are controlled.",
+			u:      withAnnotations(object("ConfigMap", "cool", nil), map[string]string{AnnotationRemoteControllerUID: "cool-uid"}),
+			want:   true,
+		},
+		"Owned": {
+			reason: "Objects with an owner are created by the controller of their owner.",
+			u: object("Pod", "cool", map[string]interface{}{"metadata": map[string]interface{}{
+				"ownerReferences": []interface{}{map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "cool", "uid": "cool-uid"}},
+			}}),
+			want: true,
+		},
+		"DefaultServiceAccount": {
+			reason: "The default service account is created by the remote control plane.",
+			u:      object("ServiceAccount", "default", nil),
+			want:   true,
+		},
+		"RootCA": {
+			reason: "The root CA config map is created by the remote control plane.",
+			u:      object("ConfigMap", "kube-root-ca.crt", nil),
+			want:   true,
+		},
+		"ServiceAccountToken": {
+			reason: "Service account tokens are created by the remote control plane.",
+			u:      object("Secret", "default-token-cool", map[string]interface{}{"type": string(corev1.SecretTypeServiceAccountToken)}),
+			want:   true,
+		},
+		"HandMade": {
+			reason: "Objects created by hand are not controlled.",
+			u:      object("Secret", "hand-made", map[string]interface{}{"type": string(corev1.SecretTypeOpaque)}),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Controlled(tc.u)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nControlled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preview stamps copies of template workloads into preview
+// environments.
+package preview
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetPreview         = "cannot get preview environment"
+	errGetTemplate        = "cannot get template workload"
+	errApplyCopy          = "cannot apply copy of template workload"
+	errDeleteCopy         = "cannot delete copy of template workload"
+	errAddFinalizer       = "cannot add finalizer to preview environment"
+	errRemoveFinalizer    = "cannot remove finalizer from preview environment"
+	errUpdatePreviewState = "cannot update preview environment status"
+)
+
+// Reconcile event reasons.
+const (
+	reasonStamp        = "StampedWorkload"
+	reasonCannotStamp  = "CannotStampWorkload"
+	reasonCannotDelete = "CannotDeleteWorkload"
+)
+
+// Finalizer is added to PreviewEnvironments whose copy is created in another
+// namespace, which therefore cannot be garbage collected when the
+// PreviewEnvironment is deleted.
+const Finalizer = "preview.oam.crossplane.io/finalizer"
+
+// LabelPreviewEnvironment identifies a workload as the copy stamped by a
+// PreviewEnvironment. Its value is the name of the PreviewEnvironment.
+const LabelPreviewEnvironment = "preview.oam.crossplane.io/environment"
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithApplicator specifies how the Reconciler should apply the copy of the
+// template workload.
+func WithApplicator(a resource.Applicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applicator = a
+	}
+}
+
+// A Reconciler reconciles PreviewEnvironments by stamping a copy of their
+// template workload.
+type Reconciler struct {
+	client     client.Client
+	applicator resource.Applicator
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler that reconciles PreviewEnvironments.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:     m.GetClient(),
+		applicator: resource.ApplyFn(resource.Apply),
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a PreviewEnvironment by stamping a copy of its template workload.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	pe := &remotev1alpha1.PreviewEnvironment{}
+	if err := r.client.Get(ctx, req.NamespacedName, pe); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPreview)
+	}
+
+	ref := CopyReference(pe)
+
+	if meta.WasDeleted(pe) {
+		if !meta.FinalizerExists(pe, Finalizer) {
+			return reconcile.Result{}, nil
+		}
+		cw := &oamv1alpha2.ContainerizedWorkload{}
+		cw.SetNamespace(ref.Namespace)
+		cw.SetName(ref.Name)
+		if err := r.client.Delete(ctx, cw); resource.IgnoreNotFound(err) != nil {
+			log.Debug("Cannot delete copy of template workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(pe, event.Warning(reasonCannotDelete, err))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+		meta.RemoveFinalizer(pe, Finalizer)
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, pe), errRemoveFinalizer)
+	}
+
+	// Copies in another namespace can't be owned by the PreviewEnvironment,
+	// so they are deleted by the Reconciler.
+	if ref.Namespace != pe.GetNamespace() && !meta.FinalizerExists(pe, Finalizer) {
+		meta.AddFinalizer(pe, Finalizer)
+		if err := r.client.Update(ctx, pe); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	tmpl := &oamv1alpha2.ContainerizedWorkload{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: pe.GetNamespace(), Name: pe.Spec.TemplateReference.Name}, tmpl); err != nil {
+		log.Debug("Cannot get template workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(pe, event.Warning(reasonCannotStamp, err))
+		pe.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errGetTemplate)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, pe), errUpdatePreviewState)
+	}
+
+	cw := Stamp(pe, tmpl)
+	if err := r.applicator.Apply(ctx, r.client, cw, resource.ControllersMustMatch()); err != nil {
+		log.Debug("Cannot apply copy of template workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(pe, event.Warning(reasonCannotStamp, err))
+		pe.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyCopy)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, pe), errUpdatePreviewState)
+	}
+
+	if pe.Status.WorkloadReference == nil {
+		r.record.Event(pe, event.Normal(reasonStamp, "Stamped copy of template workload"))
+	}
+	pe.Status.WorkloadReference = &ref
+
+	// The copy's package is torn down once its TTL elapses, which is
+	// surfaced by the PreviewEnvironment.
+	ready := v1alpha1.Available()
+	if cw.GetCondition(v1alpha1.TypeReady).Reason == workload.ReasonExpired {
+		ready = workload.Expired()
+	}
+	pe.SetConditions(ready)
+
+	pe.SetConditions(v1alpha1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, pe), errUpdatePreviewState)
+}
+
+// CopyReference returns a reference to the copy of the template workload of
+// the supplied PreviewEnvironment.
+func CopyReference(pe *remotev1alpha1.PreviewEnvironment) remotev1alpha1.PreviewWorkloadReference {
+	ref := remotev1alpha1.PreviewWorkloadReference{
+		Namespace: pe.Spec.TargetNamespace,
+		Name:      pe.Spec.TemplateReference.Name + "-" + pe.Spec.Suffix,
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = pe.GetNamespace()
+	}
+	return ref
+}
+
+// Stamp returns a copy of the supplied template workload, with the overrides
+// of the supplied PreviewEnvironment applied.
+func Stamp(pe *remotev1alpha1.PreviewEnvironment, tmpl *oamv1alpha2.ContainerizedWorkload) *oamv1alpha2.ContainerizedWorkload {
+	ref := CopyReference(pe)
+
+	cw := &oamv1alpha2.ContainerizedWorkload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+			Kind:       oamv1alpha2.ContainerizedWorkloadKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		Spec: *tmpl.Spec.DeepCopy(),
+	}
+	meta.AddLabels(cw, tmpl.GetLabels())
+	meta.AddLabels(cw, map[string]string{LabelPreviewEnvironment: pe.GetName()})
+
+	if ref.Namespace == pe.GetNamespace() {
+		meta.AddOwnerReference(cw, *metav1.NewControllerRef(pe, remotev1alpha1.PreviewEnvironmentGroupVersionKind))
+	}
+
+	if pe.Spec.TTL != nil {
+		meta.AddAnnotations(cw, map[string]string{workload.AnnotationTTL: pe.Spec.TTL.Duration.String()})
+	}
+
+	for _, e := range pe.Spec.Env {
+		for i := range cw.Spec.Containers {
+			c := &cw.Spec.Containers[i]
+			if len(e.Containers) > 0 && !contains(e.Containers, c.Name) {
+				continue
+			}
+			setEnv(c, oamv1alpha2.ContainerEnvVar{Name: e.Name, Value: e.Value})
+		}
+	}
+
+	return cw
+}
+
+// setEnv adds or replaces the supplied environment variable in the container.
+func setEnv(c *oamv1alpha2.Container, e oamv1alpha2.ContainerEnvVar) {
+	for i := range c.Environment {
+		if c.Environment[i].Name == e.Name {
+			c.Environment[i] = e
+			return
+		}
+	}
+	c.Environment = append(c.Environment, e)
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func TestReconciler(t *testing.T) {
+	type args struct {
+		c client.Client
+		o []ReconcilerOption
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	previewEnv := func(fn func(pe *remotev1alpha1.PreviewEnvironment)) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			pe, ok := obj.(*remotev1alpha1.PreviewEnvironment)
+			if !ok {
+				return nil
+			}
+			pe.SetNamespace("cool")
+			pe.SetName("pr-42")
+			pe.Spec.TemplateReference.Name = "web"
+			pe.Spec.Suffix = "pr-42"
+			if fn != nil {
+				fn(pe)
+			}
+			return nil
+		}
+	}
+	wantStatus := func(reason v1alpha1.ConditionReason, message string) func(context.Context, runtime.Object, ...client.UpdateOption) error {
+		return func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			got := obj.(*remotev1alpha1.PreviewEnvironment)
+			if diff := cmp.Diff(reason, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+				return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(message, got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+				return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetPreviewError": {
+			reason: "Any error (except not found) encountered while getting the PreviewEnvironment should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetPreview)},
+		},
+		"PreviewNotFound": {
+			reason: "Not found errors encountered while getting the PreviewEnvironment should be ignored.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			},
+			want: want{},
+		},
+		"AddFinalizerError": {
+			reason: "Errors adding a finalizer to a PreviewEnvironment that stamps into another namespace should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, previewEnv(func(pe *remotev1alpha1.PreviewEnvironment) {
+						pe.Spec.TargetNamespace = "previews"
+					})),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errAddFinalizer)},
+		},
+		"GetTemplateError": {
+			reason: "Errors getting the template workload should be reflected in the PreviewEnvironment's status.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+						if _, ok := obj.(*oamv1alpha2.ContainerizedWorkload); ok {
+							return errBoom
+						}
+						return previewEnv(nil)(obj)
+					},
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileError, errors.Wrap(errBoom, errGetTemplate).Error()),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"ApplyError": {
+			reason: "Errors applying the copy of the template workload should be reflected in the PreviewEnvironment's status.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil, previewEnv(nil)),
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileError, errors.Wrap(errBoom, errApplyCopy).Error()),
+				},
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					return errBoom
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Stamped": {
+			reason: "A copy of the template workload should be applied.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil, previewEnv(nil)),
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileSuccess, ""),
+				},
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+					want := types.NamespacedName{Namespace: "cool", Name: "web-pr-42"}
+					cw := o.(*oamv1alpha2.ContainerizedWorkload)
+					if diff := cmp.Diff(want, types.NamespacedName{Namespace: cw.GetNamespace(), Name: cw.GetName()}); diff != "" {
+						t.Errorf("Apply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"DeleteCopyError": {
+			reason: "Errors deleting the copy of a deleted PreviewEnvironment should cause a requeue.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, previewEnv(func(pe *remotev1alpha1.PreviewEnvironment) {
+						pe.SetDeletionTimestamp(&now)
+						pe.SetFinalizers([]string{Finalizer})
+					})),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"RemoveFinalizer": {
+			reason: "The finalizer of a deleted PreviewEnvironment should be removed once its copy is deleted.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, previewEnv(func(pe *remotev1alpha1.PreviewEnvironment) {
+						pe.SetDeletionTimestamp(&now)
+						pe.SetFinalizers([]string{Finalizer})
+					})),
+					MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						if diff := cmp.Diff([]string{}, obj.(metav1.Object).GetFinalizers(), cmpopts.EquateEmpty()); diff != "" {
+							t.Errorf("Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{Client: tc.args.c}, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "cool", Name: "pr-42"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStamp(t *testing.T) {
+	truth := true
+	tmpl := &oamv1alpha2.ContainerizedWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cool",
+			Name:      "web",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+			Containers: []oamv1alpha2.Container{
+				{Name: "web", Environment: []oamv1alpha2.ContainerEnvVar{{Name: "BRANCH", Value: "master"}}},
+				{Name: "sidecar"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		pe     *remotev1alpha1.PreviewEnvironment
+		want   *oamv1alpha2.ContainerizedWorkload
+	}{
+		"SameNamespace": {
+			reason: "Copies in the PreviewEnvironment's namespace should be controlled by it, and have their overrides applied.",
+			pe: &remotev1alpha1.PreviewEnvironment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cool", Name: "pr-42", UID: "very-unique"},
+				Spec: remotev1alpha1.PreviewEnvironmentSpec{
+					TemplateReference: corev1.LocalObjectReference{Name: "web"},
+					Suffix:            "pr-42",
+					Env:               []remotev1alpha1.EnvOverride{{Name: "BRANCH", Value: "feature", Containers: []string{"web"}}},
+					TTL:               &metav1.Duration{Duration: 72 * time.Hour},
+				},
+			},
+			want: &oamv1alpha2.ContainerizedWorkload{
+				TypeMeta: metav1.TypeMeta{APIVersion: oamv1alpha2.SchemeGroupVersion.String(), Kind: oamv1alpha2.ContainerizedWorkloadKind},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "cool",
+					Name:        "web-pr-42",
+					Labels:      map[string]string{"app": "web", LabelPreviewEnvironment: "pr-42"},
+					Annotations: map[string]string{workload.AnnotationTTL: "72h0m0s"},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion:         remotev1alpha1.SchemeGroupVersion.String(),
+						Kind:               remotev1alpha1.PreviewEnvironmentKind,
+						Name:               "pr-42",
+						UID:                "very-unique",
+						Controller:         &truth,
+						BlockOwnerDeletion: &truth,
+					}},
+				},
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{
+						{Name: "web", Environment: []oamv1alpha2.ContainerEnvVar{{Name: "BRANCH", Value: "feature"}}},
+						{Name: "sidecar"},
+					},
+				},
+			},
+		},
+		"OtherNamespace": {
+			reason: "Copies in another namespace should not be controlled by the PreviewEnvironment.",
+			pe: &remotev1alpha1.PreviewEnvironment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cool", Name: "pr-42"},
+				Spec: remotev1alpha1.PreviewEnvironmentSpec{
+					TemplateReference: corev1.LocalObjectReference{Name: "web"},
+					Suffix:            "pr-42",
+					TargetNamespace:   "previews",
+					Env:               []remotev1alpha1.EnvOverride{{Name: "PREVIEW", Value: "true"}},
+				},
+			},
+			want: &oamv1alpha2.ContainerizedWorkload{
+				TypeMeta: metav1.TypeMeta{APIVersion: oamv1alpha2.SchemeGroupVersion.String(), Kind: oamv1alpha2.ContainerizedWorkloadKind},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "previews",
+					Name:      "web-pr-42",
+					Labels:    map[string]string{"app": "web", LabelPreviewEnvironment: "pr-42"},
+				},
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{
+						{Name: "web", Environment: []oamv1alpha2.ContainerEnvVar{{Name: "BRANCH", Value: "master"}, {Name: "PREVIEW", Value: "true"}}},
+						{Name: "sidecar", Environment: []oamv1alpha2.ContainerEnvVar{{Name: "PREVIEW", Value: "true"}}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Stamp(tc.pe, tmpl)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nStamp(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
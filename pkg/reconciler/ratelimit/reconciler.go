@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits how often objects are reconciled, so that a
+// controller that restarts in a cluster of thousands of objects does not
+// reconcile all of them at once.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultMaxDelay is the default maximum delay before an object whose
+// reconcile failed is reconciled again.
+const DefaultMaxDelay = 1000 * time.Second
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithFailureBackoff specifies how long the Reconciler should wait before
+// passing an object whose reconcile failed to the wrapped reconciler again.
+// The delay starts at the supplied base and doubles with each consecutive
+// failure up to the supplied maximum, or DefaultMaxDelay if it is zero.
+func WithFailureBackoff(base, max time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.base = base
+		r.max = max
+		if max <= 0 {
+			r.max = DefaultMaxDelay
+		}
+	}
+}
+
+// WithBucket specifies the rate in reconciles per second at which the
+// Reconciler should pass objects to the wrapped reconciler once a bucket of
+// the supplied size is empty. The bucket holds at least one reconcile.
+func WithBucket(qps float64, size int) ReconcilerOption {
+	return func(r *Reconciler) {
+		if size < 1 {
+			size = 1
+		}
+		r.bucket = rate.NewLimiter(rate.Limit(qps), size)
+	}
+}
+
+// A Reconciler wraps another reconciler. Reconciles that exceed its rate limit
+// are not passed to the wrapped reconciler, but requeued after the delay
+// required to honor the limit.
+//
+// Failed reconciles are counted in memory, so backoff is reset when the
+// controller restarts.
+type Reconciler struct {
+	wrapped reconcile.Reconciler
+	base    time.Duration
+	max     time.Duration
+	bucket  *rate.Limiter
+	now     func() time.Time
+
+	mx       sync.Mutex
+	failures map[types.NamespacedName]failure
+	admitted map[types.NamespacedName]bool
+}
+
+type failure struct {
+	count   int
+	retryAt time.Time
+}
+
+// NewReconciler returns a Reconciler that limits how often the supplied
+// reconciler reconciles each object. It does not limit reconciles unless it
+// is configured with a backoff or bucket.
+func NewReconciler(r reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	rl := &Reconciler{
+		wrapped:  r,
+		now:      time.Now,
+		failures: map[types.NamespacedName]failure{},
+		admitted: map[types.NamespacedName]bool{},
+	}
+	for _, ro := range o {
+		ro(rl)
+	}
+	return rl
+}
+
+// Reconcile the supplied request using the wrapped reconciler, unless it must
+// be delayed to honor the rate limit.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if d := r.delay(req.NamespacedName); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+	result, err := r.wrapped.Reconcile(req)
+	r.record(req.NamespacedName, err)
+	return result, err
+}
+
+// delay returns how long a reconcile of the supplied object must be delayed.
+// An object delayed by the bucket has already reserved a token, so it is
+// admitted when it is next reconciled.
+func (r *Reconciler) delay(nn types.NamespacedName) time.Duration {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	now := r.now()
+	if f, ok := r.failures[nn]; ok && now.Before(f.retryAt) {
+		return f.retryAt.Sub(now)
+	}
+	if r.bucket == nil {
+		return 0
+	}
+	if r.admitted[nn] {
+		delete(r.admitted, nn)
+		return 0
+	}
+	d := r.bucket.ReserveN(now, 1).DelayFrom(now)
+	if d > 0 {
+		r.admitted[nn] = true
+	}
+	return d
+}
+
+// record the outcome of a reconcile of the supplied object.
+func (r *Reconciler) record(nn types.NamespacedName, err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if err == nil {
+		delete(r.failures, nn)
+		return
+	}
+	if r.base <= 0 {
+		return
+	}
+	f := r.failures[nn]
+	d := r.base
+	for i := 0; i < f.count && d < r.max; i++ {
+		d *= 2
+	}
+	if d > r.max {
+		d = r.max
+	}
+	f.count++
+	f.retryAt = r.now().Add(d)
+	r.failures[nn] = f
+}
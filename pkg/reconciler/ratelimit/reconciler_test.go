@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type reconcileFn func(reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(req reconcile.Request) (reconcile.Result, error) { return fn(req) }
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Now()
+	cool := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cool"}}
+	other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+
+	// A step reconciles a request at an offset from the start of a case.
+	type step struct {
+		at      time.Duration
+		req     reconcile.Request
+		err     error
+		result  reconcile.Result
+		called  bool
+		wantErr error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []ReconcilerOption
+		steps  []step
+	}{
+		"Unlimited": {
+			reason: "A Reconciler without a backoff or bucket should pass every reconcile to the wrapped reconciler.",
+			steps: []step{
+				{req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{req: other, called: true},
+			},
+		},
+		"FailureBackoff": {
+			reason: "Objects whose reconcile failed should be delayed by an exponential, capped backoff.",
+			o:      []ReconcilerOption{WithFailureBackoff(time.Second, 3*time.Second)},
+			steps: []step{
+				{req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{at: 500 * time.Millisecond, req: cool, result: reconcile.Result{RequeueAfter: 500 * time.Millisecond}},
+				{at: 500 * time.Millisecond, req: other, called: true},
+				{at: time.Second, req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{at: time.Second, req: cool, result: reconcile.Result{RequeueAfter: 2 * time.Second}},
+				{at: 3 * time.Second, req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{at: 3 * time.Second, req: cool, result: reconcile.Result{RequeueAfter: 3 * time.Second}},
+				{at: 6 * time.Second, req: cool, called: true},
+				{at: 6 * time.Second, req: cool, err: errBoom, called: true, wantErr: errBoom},
+				{at: 6 * time.Second, req: cool, result: reconcile.Result{RequeueAfter: time.Second}},
+			},
+		},
+		"Bucket": {
+			reason: "Reconciles that exceed the bucket should be delayed, then admitted when requeued.",
+			o:      []ReconcilerOption{WithBucket(1, 1)},
+			steps: []step{
+				{req: cool, called: true},
+				{req: other, result: reconcile.Result{RequeueAfter: time.Second}},
+				{at: time.Second, req: other, called: true},
+				{at: 2 * time.Second, req: cool, called: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				err    error
+				called bool
+			)
+			r := NewReconciler(reconcileFn(func(_ reconcile.Request) (reconcile.Result, error) {
+				called = true
+				return reconcile.Result{}, err
+			}), tc.o...)
+
+			for i, s := range tc.steps {
+				err, called = s.err, false
+				r.now = func() time.Time { return start.Add(s.at) }
+
+				got, gotErr := r.Reconcile(s.req)
+				if diff := cmp.Diff(s.wantErr, gotErr, test.EquateErrors()); diff != "" {
+					t.Errorf("\nReason: %s\nStep %d: r.Reconcile(...): -want error, +got error:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(s.result, got); diff != "" {
+					t.Errorf("\nReason: %s\nStep %d: r.Reconcile(...): -want, +got:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(s.called, called); diff != "" {
+					t.Errorf("\nReason: %s\nStep %d: r.Reconcile(...): -want called, +got called:\n%s", tc.reason, i, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scope groups the workloads of ApplicationConfiguration components
+// into the NetworkScopes they reference.
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+	longWait         = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetScope         = "cannot get network scope"
+	errListAppConfigs   = "cannot list application configurations"
+	errParseAppConfig   = "cannot parse application configuration"
+	errApplyPackage     = "cannot apply network scope package"
+	errRenderPackage    = "cannot render network scope package"
+	errJoinWorkload     = "cannot add workload to network scope"
+	errReleaseWorkload  = "cannot remove workload from network scope"
+	errClaimedWorkload  = "workload is in another network scope"
+	errAddFinalizer     = "cannot add finalizer to network scope"
+	errRemoveFinalizer  = "cannot remove finalizer from network scope"
+	errUpdateScopeState = "cannot update network scope status"
+)
+
+// Reconcile event reasons.
+const (
+	reasonJoin          = "AddedWorkload"
+	reasonRelease       = "RemovedWorkload"
+	reasonCannotJoin    = "CannotAddWorkload"
+	reasonCannotRelease = "CannotRemoveWorkload"
+)
+
+// Finalizer is added to NetworkScopes so that their workloads are removed from
+// their shared remote namespace before it is deleted.
+const Finalizer = "scope.oam.crossplane.io/finalizer"
+
+// LabelNetworkScope identifies a KubernetesApplication as the package of the
+// remote namespace of a NetworkScope. Its value is the name of the
+// NetworkScope.
+const LabelNetworkScope = "scope.oam.crossplane.io/network"
+
+var (
+	namespaceKind       = reflect.TypeOf(corev1.Namespace{}).Name()
+	namespaceAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithApplicator specifies how the Reconciler should apply the package of the
+// remote namespace of a NetworkScope.
+func WithApplicator(a resource.Applicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applicator = a
+	}
+}
+
+// A Reconciler reconciles NetworkScopes by moving the workloads of the
+// components that reference them into a shared remote namespace.
+type Reconciler struct {
+	client     client.Client
+	applicator resource.Applicator
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler that reconciles NetworkScopes.
+func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:     m.GetClient(),
+		applicator: resource.ApplyFn(resource.Apply),
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a NetworkScope by annotating the workloads of the components that
+// reference it, so that they are packaged into its remote namespace and are
+// isolated from pods outside it.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	s := &remotev1alpha1.NetworkScope{}
+	if err := r.client.Get(ctx, req.NamespacedName, s); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetScope)
+	}
+
+	if meta.WasDeleted(s) {
+		if !meta.FinalizerExists(s, Finalizer) {
+			return reconcile.Result{}, nil
+		}
+		// The remote namespace is deleted along with the package, which is
+		// garbage collected once the finalizer is removed, so workloads must
+		// first be moved out of it.
+		for _, ref := range s.Status.Workloads {
+			if err := r.release(ctx, s, ref); err != nil {
+				log.Debug("Cannot remove workload from network scope", "error", err, "requeue-after", time.Now().Add(shortWait))
+				r.record.Event(s, event.Warning(reasonCannotRelease, err))
+				return reconcile.Result{RequeueAfter: shortWait}, nil
+			}
+		}
+		meta.RemoveFinalizer(s, Finalizer)
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, s), errRemoveFinalizer)
+	}
+
+	if !meta.FinalizerExists(s, Finalizer) {
+		meta.AddFinalizer(s, Finalizer)
+		if err := r.client.Update(ctx, s); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	members, err := r.members(ctx, s)
+	if err != nil {
+		log.Debug("Cannot determine workloads in network scope", "error", err, "requeue-after", time.Now().Add(shortWait))
+		s.SetConditions(v1alpha1.ReconcileError(err))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeState)
+	}
+
+	pkg, err := Package(s)
+	if err != nil {
+		s.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errRenderPackage)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeState)
+	}
+	if err := r.applicator.Apply(ctx, r.client, pkg, resource.ControllersMustMatch()); err != nil {
+		log.Debug("Cannot apply network scope package", "error", err, "requeue-after", time.Now().Add(shortWait))
+		s.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyPackage)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeState)
+	}
+
+	joined := make([]oamv1alpha2.WorkloadReference, 0, len(members))
+	for _, ref := range members {
+		if err := r.join(ctx, s, ref); err != nil {
+			log.Debug("Cannot add workload to network scope", "error", err, "workload", ref.Name)
+			r.record.Event(s, event.Warning(reasonCannotJoin, err))
+			if contains(s.Status.Workloads, ref) {
+				joined = append(joined, ref)
+			}
+			continue
+		}
+		if !contains(s.Status.Workloads, ref) {
+			r.record.Event(s, event.Normal(reasonJoin, "Added workload to network scope", "workload", ref.Name))
+		}
+		joined = append(joined, ref)
+	}
+
+	for _, ref := range s.Status.Workloads {
+		if contains(joined, ref) {
+			continue
+		}
+		if err := r.release(ctx, s, ref); err != nil {
+			// We keep tracking workloads we could not release, so that we
+			// try again next time.
+			log.Debug("Cannot remove workload from network scope", "error", err, "workload", ref.Name)
+			r.record.Event(s, event.Warning(reasonCannotRelease, err))
+			joined = append(joined, ref)
+			continue
+		}
+		r.record.Event(s, event.Normal(reasonRelease, "Removed workload from network scope", "workload", ref.Name))
+	}
+
+	s.Status.Workloads = joined
+	s.SetConditions(v1alpha1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeState)
+}
+
+// The parts of an ApplicationConfiguration that reference the scopes of its
+// components, and the workloads it rendered for them.
+type appConfig struct {
+	Spec struct {
+		Components []struct {
+			ComponentName string `json:"componentName"`
+			Scopes        []struct {
+				Reference oamv1alpha2.ScopeReference `json:"scopeRef"`
+			} `json:"scopes,omitempty"`
+		} `json:"components,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Workloads []struct {
+			ComponentName string                        `json:"componentName"`
+			Reference     oamv1alpha2.WorkloadReference `json:"workloadRef"`
+		} `json:"workloads,omitempty"`
+	} `json:"status"`
+}
+
+// members returns the workloads of the components that reference the supplied
+// NetworkScope in the ApplicationConfigurations of its namespace.
+func (r *Reconciler) members(ctx context.Context, s *remotev1alpha1.NetworkScope) ([]oamv1alpha2.WorkloadReference, error) {
+	l := &oamv1alpha2.ApplicationConfigurationList{}
+	if err := r.client.List(ctx, l, client.InNamespace(s.GetNamespace())); err != nil {
+		return nil, errors.Wrap(err, errListAppConfigs)
+	}
+
+	members := []oamv1alpha2.WorkloadReference{}
+	for i := range l.Items {
+		ac := appConfig{}
+		if err := convert(&l.Items[i], &ac); err != nil {
+			return nil, errors.Wrap(err, errParseAppConfig)
+		}
+
+		scoped := map[string]bool{}
+		for _, c := range ac.Spec.Components {
+			for _, sc := range c.Scopes {
+				if IsNetworkScope(sc.Reference) && sc.Reference.Name == s.GetName() {
+					scoped[c.ComponentName] = true
+				}
+			}
+		}
+		for _, w := range ac.Status.Workloads {
+			if scoped[w.ComponentName] {
+				members = append(members, w.Reference)
+			}
+		}
+	}
+	return members, nil
+}
+
+// IsNetworkScope returns true if the supplied reference is to a NetworkScope.
+func IsNetworkScope(ref oamv1alpha2.ScopeReference) bool {
+	return ref.APIVersion == remotev1alpha1.SchemeGroupVersion.String() && ref.Kind == remotev1alpha1.NetworkScopeKind
+}
+
+// join annotates the referenced workload so that it is packaged into the
+// remote namespace of the supplied NetworkScope.
+func (r *Reconciler) join(ctx context.Context, s *remotev1alpha1.NetworkScope, ref oamv1alpha2.WorkloadReference) error {
+	u, err := r.get(ctx, s.GetNamespace(), ref)
+	if err != nil {
+		return errors.Wrap(err, errJoinWorkload)
+	}
+	if other := u.GetAnnotations()[workload.AnnotationNetworkScope]; other != "" && other != s.GetName() {
+		return errors.Errorf("%s %s: %s", errClaimedWorkload, other, ref.Name)
+	}
+
+	want := map[string]string{
+		workload.AnnotationNetworkScope:    s.GetName(),
+		workload.AnnotationRemoteNamespace: RemoteNamespace(s),
+	}
+	if hasAnnotations(u, want) {
+		return nil
+	}
+	p := client.MergeFrom(u.DeepCopy())
+	meta.AddAnnotations(u, want)
+	return errors.Wrap(r.client.Patch(ctx, u, p), errJoinWorkload)
+}
+
+// release removes the annotations that package the referenced workload into
+// the remote namespace of the supplied NetworkScope. Workloads that no longer
+// exist, or that have since joined another NetworkScope, are ignored.
+func (r *Reconciler) release(ctx context.Context, s *remotev1alpha1.NetworkScope, ref oamv1alpha2.WorkloadReference) error {
+	u, err := r.get(ctx, s.GetNamespace(), ref)
+	if err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errReleaseWorkload)
+	}
+	if u.GetAnnotations()[workload.AnnotationNetworkScope] != s.GetName() {
+		return nil
+	}
+
+	p := client.MergeFrom(u.DeepCopy())
+	a := u.GetAnnotations()
+	delete(a, workload.AnnotationNetworkScope)
+	delete(a, workload.AnnotationRemoteNamespace)
+	u.SetAnnotations(a)
+	return errors.Wrap(r.client.Patch(ctx, u, p), errReleaseWorkload)
+}
+
+func (r *Reconciler) get(ctx context.Context, namespace string, ref oamv1alpha2.WorkloadReference) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, u)
+	return u, err
+}
+
+// RemoteNamespace returns the remote namespace shared by the workloads of the
+// supplied NetworkScope.
+func RemoteNamespace(s *remotev1alpha1.NetworkScope) string {
+	if s.Spec.RemoteNamespace != "" {
+		return s.Spec.RemoteNamespace
+	}
+	return s.GetName()
+}
+
+// Package returns the KubernetesApplication that packages the remote namespace
+// of the supplied NetworkScope. The namespace is packaged once for the scope
+// rather than with each of its workloads, so that it is not deleted when one
+// of them leaves the scope.
+func Package(s *remotev1alpha1.NetworkScope) (*workloadv1alpha1.KubernetesApplication, error) {
+	n := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       namespaceKind,
+			APIVersion: namespaceAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: RemoteNamespace(s),
+		},
+	}
+	b, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+
+	name := s.GetName() + "-network-scope"
+	labels := map[string]string{LabelNetworkScope: s.GetName()}
+	app := &workloadv1alpha1.KubernetesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: workloadv1alpha1.KubernetesApplicationSpec{
+			ResourceSelector: &metav1.LabelSelector{MatchLabels: labels},
+			ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: labels,
+					},
+					Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+						Template: runtime.RawExtension{Raw: b},
+					},
+				},
+			},
+		},
+	}
+	meta.AddOwnerReference(app, *metav1.NewControllerRef(s, remotev1alpha1.NetworkScopeGroupVersionKind))
+	return app, nil
+}
+
+func hasAnnotations(o metav1.Object, want map[string]string) bool {
+	a := o.GetAnnotations()
+	for k, v := range want {
+		if a[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(refs []oamv1alpha2.WorkloadReference, ref oamv1alpha2.WorkloadReference) bool {
+	for _, r := range refs {
+		if r.APIVersion == ref.APIVersion && r.Kind == ref.Kind && r.Name == ref.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// convert the supplied object into the supplied struct.
+func convert(o interface{}, into interface{}) error {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, into)
+}
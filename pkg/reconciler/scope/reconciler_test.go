@@ -0,0 +1,354 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+var (
+	scopeRef = oamv1alpha2.ScopeReference{
+		APIVersion: remotev1alpha1.SchemeGroupVersion.String(),
+		Kind:       remotev1alpha1.NetworkScopeKind,
+		Name:       "backend",
+	}
+	apiRef = oamv1alpha2.WorkloadReference{
+		APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+		Kind:       oamv1alpha2.ContainerizedWorkloadKind,
+		Name:       "api",
+	}
+	dbRef = oamv1alpha2.WorkloadReference{
+		APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+		Kind:       oamv1alpha2.ContainerizedWorkloadKind,
+		Name:       "db",
+	}
+)
+
+func appConfigs(obj runtime.Object) error {
+	*obj.(*oamv1alpha2.ApplicationConfigurationList) = oamv1alpha2.ApplicationConfigurationList{
+		Items: []oamv1alpha2.ApplicationConfiguration{{
+			Spec: oamv1alpha2.ApplicationConfigurationSpec{
+				Components: []oamv1alpha2.ApplicationConfigurationComponent{
+					{ComponentName: "api", Scopes: []oamv1alpha2.ComponentScope{{ScopeReference: scopeRef}}},
+					{ComponentName: "frontend"},
+				},
+			},
+			Status: oamv1alpha2.ApplicationConfigurationStatus{
+				Workloads: []oamv1alpha2.WorkloadStatus{
+					{ComponentName: "api", Reference: apiRef},
+					{ComponentName: "frontend", Reference: oamv1alpha2.WorkloadReference{Name: "frontend"}},
+				},
+			},
+		}},
+	}
+	return nil
+}
+
+func TestReconciler(t *testing.T) {
+	type args struct {
+		c client.Client
+		o []ReconcilerOption
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	networkScope := func(fn func(s *remotev1alpha1.NetworkScope)) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *remotev1alpha1.NetworkScope:
+				o.SetNamespace("cool")
+				o.SetName("backend")
+				o.SetFinalizers([]string{Finalizer})
+				if fn != nil {
+					fn(o)
+				}
+			case *unstructured.Unstructured:
+				o.SetAnnotations(map[string]string{workload.AnnotationNetworkScope: "backend"})
+			}
+			return nil
+		}
+	}
+	wantStatus := func(reason v1alpha1.ConditionReason, message string, refs []oamv1alpha2.WorkloadReference) func(context.Context, runtime.Object, ...client.UpdateOption) error {
+		return func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			got := obj.(*remotev1alpha1.NetworkScope)
+			if diff := cmp.Diff(reason, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+				return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(message, got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+				return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(refs, got.Status.Workloads, cmpopts.EquateEmpty()); diff != "" {
+				return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+			}
+			return nil
+		}
+	}
+	applied := WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+		return nil
+	}))
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetScopeError": {
+			reason: "Any error (except not found) encountered while getting the NetworkScope should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetScope)},
+		},
+		"ScopeNotFound": {
+			reason: "Not found errors encountered while getting the NetworkScope should be ignored.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			},
+			want: want{},
+		},
+		"AddFinalizerError": {
+			reason: "Errors adding a finalizer to the NetworkScope should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, networkScope(func(s *remotev1alpha1.NetworkScope) {
+						s.SetFinalizers(nil)
+					})),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errAddFinalizer)},
+		},
+		"ListAppConfigsError": {
+			reason: "Errors listing ApplicationConfigurations should be reflected in the NetworkScope's status.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil, networkScope(nil)),
+					MockList:         test.NewMockListFn(errBoom),
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileError, errors.Wrap(errBoom, errListAppConfigs).Error(), nil),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"ApplyPackageError": {
+			reason: "Errors applying the package of the remote namespace should be reflected in the NetworkScope's status.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil, networkScope(nil)),
+					MockList:         test.NewMockListFn(nil, appConfigs),
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileError, errors.Wrap(errBoom, errApplyPackage).Error(), nil),
+				},
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					return errBoom
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"JoinWorkload": {
+			reason: "Workloads of components that reference the NetworkScope should be annotated with it and its remote namespace.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+						if s, ok := obj.(*remotev1alpha1.NetworkScope); ok {
+							return networkScope(nil)(s)
+						}
+						return nil
+					},
+					MockList: test.NewMockListFn(nil, appConfigs),
+					MockPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+						want := map[string]string{
+							workload.AnnotationNetworkScope:    "backend",
+							workload.AnnotationRemoteNamespace: "backend",
+						}
+						if diff := cmp.Diff(want, obj.(metav1.Object).GetAnnotations()); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileSuccess, "", []oamv1alpha2.WorkloadReference{apiRef}),
+				},
+				o: []ReconcilerOption{applied},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"JoinClaimedWorkload": {
+			reason: "Workloads that are already in another NetworkScope should not be added to this one.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							u.SetAnnotations(map[string]string{workload.AnnotationNetworkScope: "frontend"})
+							return nil
+						}
+						return networkScope(nil)(obj)
+					},
+					MockList:         test.NewMockListFn(nil, appConfigs),
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileSuccess, "", nil),
+				},
+				o: []ReconcilerOption{applied},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ReleaseWorkload": {
+			reason: "Workloads that no longer belong to the NetworkScope should have its annotations removed.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, networkScope(func(s *remotev1alpha1.NetworkScope) {
+						s.Status.Workloads = []oamv1alpha2.WorkloadReference{dbRef}
+					})),
+					MockList: test.NewMockListFn(nil),
+					MockPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if diff := cmp.Diff(map[string]string{}, obj.(metav1.Object).GetAnnotations(), cmpopts.EquateEmpty()); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+					MockStatusUpdate: wantStatus(v1alpha1.ReasonReconcileSuccess, "", nil),
+				},
+				o: []ReconcilerOption{applied},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"DeletedReleaseError": {
+			reason: "Errors removing workloads from a deleted NetworkScope should cause a requeue.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, networkScope(func(s *remotev1alpha1.NetworkScope) {
+						s.SetDeletionTimestamp(&now)
+						s.Status.Workloads = []oamv1alpha2.WorkloadReference{dbRef}
+					})),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"RemoveFinalizer": {
+			reason: "The finalizer of a deleted NetworkScope should be removed once its workloads are removed from it.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, networkScope(func(s *remotev1alpha1.NetworkScope) {
+						s.SetDeletionTimestamp(&now)
+						s.Status.Workloads = []oamv1alpha2.WorkloadReference{dbRef}
+					})),
+					MockPatch: test.NewMockPatchFn(nil),
+					MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						if diff := cmp.Diff([]string{}, obj.(metav1.Object).GetFinalizers(), cmpopts.EquateEmpty()); diff != "" {
+							t.Errorf("Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{Client: tc.args.c}, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "cool", Name: "backend"}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNetworkScopes(t *testing.T) {
+	ac := &oamv1alpha2.ApplicationConfiguration{
+		Spec: oamv1alpha2.ApplicationConfigurationSpec{
+			Components: []oamv1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "api", Scopes: []oamv1alpha2.ComponentScope{{ScopeReference: scopeRef}}},
+				{ComponentName: "db", Scopes: []oamv1alpha2.ComponentScope{
+					{ScopeReference: scopeRef},
+					{ScopeReference: oamv1alpha2.ScopeReference{APIVersion: "example.org/v1", Kind: "HealthScope", Name: "health"}},
+				}},
+			},
+		},
+	}
+
+	got, err := NetworkScopes(ac)
+	if err != nil {
+		t.Fatalf("NetworkScopes(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{"backend"}, got); diff != "" {
+		t.Errorf("NetworkScopes(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRemoteNamespace(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      *remotev1alpha1.NetworkScope
+		want   string
+	}{
+		"Default": {
+			reason: "The remote namespace should default to the name of the NetworkScope.",
+			s:      &remotev1alpha1.NetworkScope{ObjectMeta: metav1.ObjectMeta{Name: "backend"}},
+			want:   "backend",
+		},
+		"Specified": {
+			reason: "The specified remote namespace should be used.",
+			s: &remotev1alpha1.NetworkScope{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+				Spec:       remotev1alpha1.NetworkScopeSpec{RemoteNamespace: "shared"},
+			},
+			want: "shared",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, RemoteNamespace(tc.s)); diff != "" {
+				t.Errorf("\nReason: %s\nRemoteNamespace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
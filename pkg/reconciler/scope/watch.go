@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// EnqueueRequestsForNetworkScopes returns an event handler that enqueues a
+// reconcile of each NetworkScope referenced by the components of an
+// ApplicationConfiguration, so that workloads join or leave a scope as soon
+// as their components reference it or stop doing so. Both the old and new
+// versions of an updated ApplicationConfiguration are mapped, so scopes that
+// are no longer referenced are reconciled too.
+func EnqueueRequestsForNetworkScopes() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			// An event handler has no way to return errors. Scopes of
+			// ApplicationConfigurations that cannot be parsed pick up their
+			// workloads when they are next reconciled.
+			names, err := NetworkScopes(o.Object)
+			if err != nil {
+				return nil
+			}
+
+			reqs := make([]reconcile.Request, 0, len(names))
+			for _, n := range names {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: n}})
+			}
+			return reqs
+		}),
+	}
+}
+
+// NetworkScopes returns the names of the NetworkScopes referenced by the
+// components of the supplied ApplicationConfiguration.
+func NetworkScopes(ac runtime.Object) ([]string, error) {
+	c := appConfig{}
+	if err := convert(ac, &c); err != nil {
+		return nil, errors.Wrap(err, errParseAppConfig)
+	}
+	names := []string{}
+	for _, comp := range c.Spec.Components {
+		for _, sc := range comp.Scopes {
+			if IsNetworkScope(sc.Reference) && !containsString(names, sc.Reference.Name) {
+				names = append(names, sc.Reference.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errMarshalSpans = "cannot marshal spans"
+	errExportSpans  = "cannot export spans"
+	errExportStatus = "OTLP collector returned unexpected status"
+)
+
+// Defaults of an OTLPTracer.
+const (
+	DefaultServiceName    = "addon-oam-kubernetes-remote"
+	DefaultExportInterval = 5 * time.Second
+	DefaultMaxQueuedSpans = 2048
+)
+
+// OTLP span kinds and status codes, as defined by the OpenTelemetry protocol.
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+// An OTLPOption configures an OTLPTracer.
+type OTLPOption func(*OTLPTracer)
+
+// WithServiceName specifies the service name spans are exported with.
+func WithServiceName(name string) OTLPOption {
+	return func(t *OTLPTracer) {
+		t.service = name
+	}
+}
+
+// WithExportInterval specifies how often spans are exported.
+func WithExportInterval(d time.Duration) OTLPOption {
+	return func(t *OTLPTracer) {
+		t.interval = d
+	}
+}
+
+// WithMaxQueuedSpans specifies how many ended spans may be queued for export.
+// Spans that end while the queue is full are dropped.
+func WithMaxQueuedSpans(n int) OTLPOption {
+	return func(t *OTLPTracer) {
+		t.max = n
+	}
+}
+
+// WithHTTPClient specifies the HTTP client used to export spans.
+func WithHTTPClient(c *http.Client) OTLPOption {
+	return func(t *OTLPTracer) {
+		t.client = c
+	}
+}
+
+// WithLogger specifies how the OTLPTracer should log failed exports.
+func WithLogger(l logging.Logger) OTLPOption {
+	return func(t *OTLPTracer) {
+		t.log = l
+	}
+}
+
+// An OTLPTracer exports spans to an OpenTelemetry collector using the OTLP/HTTP
+// protocol with JSON encoding. Ended spans are queued and exported in batches
+// while the tracer is started.
+type OTLPTracer struct {
+	endpoint string
+	service  string
+	interval time.Duration
+	max      int
+	client   *http.Client
+	log      logging.Logger
+
+	mx     sync.Mutex
+	queued []*otlpSpan
+}
+
+// NewOTLPTracer returns an OTLPTracer that exports spans to the OTLP/HTTP
+// collector at the supplied endpoint, for example http://collector:4318.
+func NewOTLPTracer(endpoint string, o ...OTLPOption) *OTLPTracer {
+	t := &OTLPTracer{
+		endpoint: strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		service:  DefaultServiceName,
+		interval: DefaultExportInterval,
+		max:      DefaultMaxQueuedSpans,
+		client:   http.DefaultClient,
+		log:      logging.NewNopLogger(),
+	}
+
+	for _, to := range o {
+		to(t)
+	}
+
+	return t
+}
+
+type spanKey struct{}
+
+// StartSpan starts a span with the supplied name. Spans started with a context
+// that carries no span begin a new trace.
+func (t *OTLPTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	s := &otlpSpan{tracer: t, name: name, start: time.Now(), attributes: map[string]string{}, spanID: newID(8)}
+	if p, ok := ctx.Value(spanKey{}).(*otlpSpan); ok {
+		s.traceID = p.traceID
+		s.parentID = p.spanID
+	} else {
+		s.traceID = newID(16)
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Start exporting spans until the supplied channel is closed. Spans that are
+// queued when the channel is closed are exported before Start returns.
+func (t *OTLPTracer) Start(stop <-chan struct{}) error {
+	tk := time.NewTicker(t.interval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-stop:
+			ctx, cancel := context.WithTimeout(context.Background(), t.interval)
+			defer cancel()
+			return t.Export(ctx)
+		case <-tk.C:
+			// A failed export drops the spans it was exporting. Tracing is
+			// best effort, so it does not stop the tracer.
+			if err := t.Export(context.Background()); err != nil {
+				t.log.Debug("Cannot export spans", "error", err)
+			}
+		}
+	}
+}
+
+// Export all queued spans.
+func (t *OTLPTracer) Export(ctx context.Context) error {
+	t.mx.Lock()
+	spans := t.queued
+	t.queued = nil
+	t.mx.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return errors.Wrap(err, errMarshalSpans)
+	}
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, errExportSpans)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := t.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, errExportSpans)
+	}
+	defer func() { _ = rsp.Body.Close() }()
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return errors.Errorf("%s: %s", errExportStatus, rsp.Status)
+	}
+	return nil
+}
+
+func (t *OTLPTracer) enqueue(s *otlpSpan) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	if len(t.queued) >= t.max {
+		return
+	}
+	t.queued = append(t.queued, s)
+}
+
+func (t *OTLPTracer) request(spans []*otlpSpan) otlpRequest {
+	ss := make([]otlpJSONSpan, 0, len(spans))
+	for _, s := range spans {
+		ss = append(ss, s.json())
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: keyValues(map[string]string{"service.name": t.service})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trace"},
+			Spans: ss,
+		}},
+	}}}
+}
+
+type otlpSpan struct {
+	tracer *OTLPTracer
+
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+
+	mx         sync.Mutex
+	start      time.Time
+	end        time.Time
+	attributes map[string]string
+	err        error
+}
+
+func (s *otlpSpan) SetAttributes(keysAndValues ...interface{}) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for k, v := range attributes(keysAndValues...) {
+		s.attributes[k] = v
+	}
+}
+
+func (s *otlpSpan) End(err error) {
+	s.mx.Lock()
+	s.end = time.Now()
+	s.err = err
+	s.mx.Unlock()
+	s.tracer.enqueue(s)
+}
+
+func (s *otlpSpan) json() otlpJSONSpan {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	j := otlpJSONSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        keyValues(s.attributes),
+		Status:            otlpStatus{Code: otlpStatusOK},
+	}
+	if s.err != nil {
+		j.Status = otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+	}
+	return j
+}
+
+// newID returns a random hex encoded ID of the supplied number of bytes.
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// keyValues returns the supplied attributes sorted by key.
+func keyValues(a map[string]string) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(a))
+	for k, v := range a {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+// The OTLP/HTTP JSON encoding of an ExportTraceServiceRequest.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope      `json:"scope"`
+	Spans []otlpJSONSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpJSONSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
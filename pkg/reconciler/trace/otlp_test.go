@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ Tracer = NopTracer{}
+	_ Tracer = &OTLPTracer{}
+)
+
+func TestOTLPTracer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var got otlpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &got)
+	}))
+	defer srv.Close()
+
+	tr := NewOTLPTracer(srv.URL+"/", WithServiceName("cool-service"))
+
+	ctx, parent := tr.StartSpan(context.Background(), "parent")
+	parent.SetAttributes("request", "cool-ns/cool-name", "dangling")
+	_, child := tr.StartSpan(ctx, "child")
+	child.End(errBoom)
+	parent.End(nil)
+
+	if err := tr.Export(context.Background()); err != nil {
+		t.Fatalf("Export(...): %s", err)
+	}
+
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Export(...): want one resource and scope, got %+v", got)
+	}
+	if diff := cmp.Diff([]otlpKeyValue{{Key: "service.name", Value: otlpAnyValue{StringValue: "cool-service"}}}, got.ResourceSpans[0].Resource.Attributes); diff != "" {
+		t.Errorf("Export(...): -want resource attributes, +got:\n%s", diff)
+	}
+
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Export(...): want two spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+
+	want := []otlpJSONSpan{
+		{
+			Name:         "child",
+			Kind:         otlpSpanKindInternal,
+			TraceID:      p.TraceID,
+			ParentSpanID: p.SpanID,
+			Status:       otlpStatus{Code: otlpStatusError, Message: errBoom.Error()},
+		},
+		{
+			Name: "parent",
+			Kind: otlpSpanKindInternal,
+			Attributes: []otlpKeyValue{
+				{Key: "dangling", Value: otlpAnyValue{StringValue: ""}},
+				{Key: "request", Value: otlpAnyValue{StringValue: "cool-ns/cool-name"}},
+			},
+			Status: otlpStatus{Code: otlpStatusOK},
+		},
+	}
+	ignore := cmpopts.IgnoreFields(otlpJSONSpan{}, "SpanID", "StartTimeUnixNano", "EndTimeUnixNano")
+	want[1].TraceID = p.TraceID
+	if diff := cmp.Diff(want, []otlpJSONSpan{c, p}, ignore); diff != "" {
+		t.Errorf("Export(...): -want, +got:\n%s", diff)
+	}
+	if len(p.TraceID) != 32 || len(p.SpanID) != 16 || p.ParentSpanID != "" {
+		t.Errorf("Export(...): want a root span with 16 byte trace ID and 8 byte span ID, got %+v", p)
+	}
+
+	// Exported spans are no longer queued.
+	got = otlpRequest{}
+	if err := tr.Export(context.Background()); err != nil {
+		t.Errorf("Export(...): %s", err)
+	}
+	if got.ResourceSpans != nil {
+		t.Errorf("Export(...): want no spans exported again, got %+v", got)
+	}
+}
+
+func TestOTLPTracerExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tr := NewOTLPTracer(srv.URL)
+	_, s := tr.StartSpan(context.Background(), "cool")
+	s.End(nil)
+
+	want := errors.Errorf("%s: %s", errExportStatus, "503 Service Unavailable")
+	if diff := cmp.Diff(want, tr.Export(context.Background()), test.EquateErrors()); diff != "" {
+		t.Errorf("Export(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestOTLPTracerMaxQueuedSpans(t *testing.T) {
+	tr := NewOTLPTracer("http://example.org", WithMaxQueuedSpans(1))
+	for i := 0; i < 3; i++ {
+		_, s := tr.StartSpan(context.Background(), "cool")
+		s.End(nil)
+	}
+	if len(tr.queued) != 1 {
+		t.Errorf("End(...): want spans ended while the queue is full to be dropped, got %d queued", len(tr.queued))
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"fmt"
+)
+
+// A Tracer starts spans that record how long each phase of a reconcile took.
+type Tracer interface {
+	// StartSpan starts a span with the supplied name. The span is a child
+	// of the span of the supplied context, if any. The returned context
+	// carries the new span, so that spans started with it are its children.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A Span records a single phase of a reconcile.
+type Span interface {
+	// SetAttributes of the span. Attributes are supplied as alternating keys
+	// and values, like the values of a log line.
+	SetAttributes(keysAndValues ...interface{})
+
+	// End the span, recording the supplied error if it is not nil.
+	End(err error)
+}
+
+// A NopTracer starts spans that record nothing.
+type NopTracer struct{}
+
+// StartSpan starts a span that records nothing.
+func (t NopTracer) StartSpan(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (s nopSpan) SetAttributes(_ ...interface{}) {}
+func (s nopSpan) End(_ error)                    {}
+
+// attributes returns the supplied alternating keys and values as a map. A key
+// without a value is recorded with an empty value.
+func attributes(keysAndValues ...interface{}) map[string]string {
+	a := make(map[string]string, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		k := fmt.Sprint(keysAndValues[i])
+		a[k] = ""
+		if i+1 < len(keysAndValues) {
+			a[k] = fmt.Sprint(keysAndValues[i+1])
+		}
+	}
+	return a
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trace correlates the log lines emitted by a single reconcile.
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// LogKey is the key of the trace ID attached to log lines.
+const LogKey = "trace-id"
+
+// NewID returns a random ID that identifies a single reconcile.
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// Logger returns the supplied logger with a new trace ID attached, such that
+// all lines logged by a single reconcile may be correlated.
+func Logger(l logging.Logger) logging.Logger {
+	return l.WithValues(LogKey, NewID())
+}
+
+// An object that may be described.
+type object interface {
+	runtime.Object
+	GetNamespace() string
+	GetName() string
+}
+
+// Describe returns a compact description of the supplied objects, suitable
+// for logging.
+func Describe(objs ...runtime.Object) string {
+	d := make([]string, 0, len(objs))
+	for _, o := range objs {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		name := ""
+		if m, ok := o.(object); ok {
+			name = m.GetName()
+			if m.GetNamespace() != "" {
+				name = m.GetNamespace() + "/" + name
+			}
+		}
+		d = append(d, fmt.Sprintf("%s %s", strings.ToLower(gvk.GroupKind().String()), name))
+	}
+	return strings.Join(d, ", ")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 16 {
+		t.Errorf("NewID(): want a 16 character ID, got %q", a)
+	}
+	if a == b {
+		t.Errorf("NewID(): want unique IDs, got %q twice", a)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		objs   []runtime.Object
+		want   string
+	}{
+		"None": {
+			reason: "No objects should be described as an empty string.",
+			want:   "",
+		},
+		"Objects": {
+			reason: "Each object should be described by its kind, namespace, and name.",
+			objs: []runtime.Object{
+				&appsv1.Deployment{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "cool"},
+				},
+				&corev1.Namespace{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+					ObjectMeta: metav1.ObjectMeta{Name: "coolns"},
+				},
+			},
+			want: "deployment.apps coolns/cool, namespace coolns",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Describe(tc.objs...)); diff != "" {
+				t.Errorf("\nReason: %s\nDescribe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+// AppliesToAllWorkloads may be listed in a TraitDefinition's
+// appliesToWorkloads to permit its trait to modify workloads of any kind.
+const AppliesToAllWorkloads = "*"
+
+// ReasonNotApplicable indicates that a trait references a kind of workload
+// that its TraitDefinition does not permit it to modify.
+const ReasonNotApplicable v1alpha1.ConditionReason = "NotApplicable"
+
+// TraitDefinitionListGroupVersionKind is the kind of a list of OAM
+// TraitDefinitions.
+var TraitDefinitionListGroupVersionKind = oamv1alpha2.SchemeGroupVersion.WithKind("TraitDefinitionList")
+
+// NotApplicable returns a condition that indicates a trait cannot be synced
+// because its TraitDefinition does not permit it to modify workloads of the
+// referenced kind.
+func NotApplicable(workload, kind string, appliesTo []string) v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Type:               v1alpha1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotApplicable,
+		Message:            fmt.Sprintf("workload %s is a %s, but trait applies only to %s", workload, kind, strings.Join(appliesTo, ", ")),
+	}
+}
+
+// An ApplicabilityChecker determines whether a trait may modify a referenced
+// workload.
+type ApplicabilityChecker interface {
+	// Applies returns true if traits of the supplied kind may modify the
+	// referenced workload, along with the workload kinds they may modify.
+	Applies(ctx context.Context, trait schema.GroupVersionKind, ref oamv1alpha2.WorkloadReference) ([]string, bool)
+}
+
+// An ApplicabilityCheckFn determines whether a trait may modify a referenced
+// workload.
+type ApplicabilityCheckFn func(ctx context.Context, trait schema.GroupVersionKind, ref oamv1alpha2.WorkloadReference) ([]string, bool)
+
+// Applies returns true if traits of the supplied kind may modify the
+// referenced workload.
+func (fn ApplicabilityCheckFn) Applies(ctx context.Context, trait schema.GroupVersionKind, ref oamv1alpha2.WorkloadReference) ([]string, bool) {
+	return fn(ctx, trait, ref)
+}
+
+var _ ApplicabilityChecker = ApplicabilityCheckFn(AppliesToAll)
+
+// AppliesToAll permits traits to modify workloads of any kind.
+func AppliesToAll(_ context.Context, _ schema.GroupVersionKind, _ oamv1alpha2.WorkloadReference) ([]string, bool) {
+	return nil, true
+}
+
+// A DefinitionApplicabilityChecker permits traits to modify the kinds of
+// workload listed in the appliesToWorkloads of their TraitDefinition. Each
+// entry may be a CustomResourceDefinition name (e.g.
+// containerizedworkloads.core.oam.dev), a group qualified kind (e.g.
+// ContainerizedWorkload.core.oam.dev), a kind, or '*'. Traits that have no
+// TraitDefinition, or whose TraitDefinition lists no workloads, may modify
+// workloads of any kind.
+type DefinitionApplicabilityChecker struct {
+	client client.Reader
+	mapper meta.RESTMapper
+}
+
+// NewDefinitionApplicabilityChecker returns an ApplicabilityChecker that
+// consults TraitDefinitions.
+func NewDefinitionApplicabilityChecker(c client.Reader, m meta.RESTMapper) *DefinitionApplicabilityChecker {
+	return &DefinitionApplicabilityChecker{client: c, mapper: m}
+}
+
+// Applies returns true if the TraitDefinition of the supplied kind of trait
+// permits it to modify the referenced workload. TraitDefinitions that cannot
+// be read are treated as if they permit any kind of workload.
+func (c *DefinitionApplicabilityChecker) Applies(ctx context.Context, trait schema.GroupVersionKind, ref oamv1alpha2.WorkloadReference) ([]string, bool) {
+	name, ok := c.crdName(trait)
+	if !ok {
+		return nil, true
+	}
+
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(TraitDefinitionListGroupVersionKind)
+	if err := c.client.List(ctx, l); err != nil {
+		return nil, true
+	}
+
+	for _, d := range l.Items {
+		if n, _, _ := unstructured.NestedString(d.Object, "spec", "reference", "name"); n != name {
+			continue
+		}
+		appliesTo, _, _ := unstructured.NestedStringSlice(d.Object, "spec", "appliesToWorkloads")
+		if len(appliesTo) == 0 {
+			return nil, true
+		}
+		return appliesTo, c.matches(appliesTo, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	}
+
+	return nil, true
+}
+
+// matches returns true if any of the supplied appliesToWorkloads entries
+// matches the supplied kind of workload.
+func (c *DefinitionApplicabilityChecker) matches(appliesTo []string, gvk schema.GroupVersionKind) bool {
+	names := []string{AppliesToAllWorkloads, gvk.Kind, gvk.GroupKind().String()}
+	if crd, ok := c.crdName(gvk); ok {
+		names = append(names, crd)
+	}
+	for _, a := range appliesTo {
+		for _, n := range names {
+			if strings.EqualFold(a, n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// crdName returns the name of the CustomResourceDefinition that defines the
+// supplied kind, and true if the kind is known to the API server.
+func (c *DefinitionApplicabilityChecker) crdName(gvk schema.GroupVersionKind) (string, bool) {
+	m, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return "", false
+	}
+	return m.Resource.GroupResource().String(), true
+}
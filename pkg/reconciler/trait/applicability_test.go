@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+func TestDefinitionApplicabilityChecker(t *testing.T) {
+	traitKind := schema.GroupVersionKind{Group: "core.oam.dev", Version: "v1alpha2", Kind: "ManualScalerTrait"}
+	cwKind := schema.GroupVersionKind{Group: "core.oam.dev", Version: "v1alpha2", Kind: "ContainerizedWorkload"}
+	unknownKind := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Unknown"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(traitKind, meta.RESTScopeNamespace)
+	mapper.Add(cwKind, meta.RESTScopeNamespace)
+
+	definition := func(reference string, appliesTo ...string) unstructured.Unstructured {
+		d := unstructured.Unstructured{Object: map[string]interface{}{}}
+		_ = unstructured.SetNestedField(d.Object, reference, "spec", "reference", "name")
+		if len(appliesTo) > 0 {
+			_ = unstructured.SetNestedStringSlice(d.Object, appliesTo, "spec", "appliesToWorkloads")
+		}
+		return d
+	}
+
+	list := func(err error, d ...unstructured.Unstructured) client.Reader {
+		return &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			obj.(*unstructured.UnstructuredList).Items = d
+			return err
+		}}
+	}
+
+	ref := func(gvk schema.GroupVersionKind) oamv1alpha2.WorkloadReference {
+		return oamv1alpha2.WorkloadReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: "coolworkload"}
+	}
+
+	type args struct {
+		client client.Reader
+		trait  schema.GroupVersionKind
+		ref    oamv1alpha2.WorkloadReference
+	}
+	type want struct {
+		appliesTo []string
+		ok        bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownTraitKind": {
+			reason: "Traits whose kind is unknown to the API server should apply to any workload.",
+			args: args{
+				client: list(nil, definition("unknowns.example.org", "Nothing")),
+				trait:  unknownKind,
+				ref:    ref(cwKind),
+			},
+			want: want{ok: true},
+		},
+		"ListError": {
+			reason: "Traits whose definitions cannot be listed should apply to any workload.",
+			args: args{
+				client: list(errors.New("boom")),
+				trait:  traitKind,
+				ref:    ref(cwKind),
+			},
+			want: want{ok: true},
+		},
+		"NoDefinition": {
+			reason: "Traits that have no TraitDefinition should apply to any workload.",
+			args: args{
+				client: list(nil, definition("othertraits.core.oam.dev", "Nothing")),
+				trait:  traitKind,
+				ref:    ref(cwKind),
+			},
+			want: want{ok: true},
+		},
+		"Unrestricted": {
+			reason: "Traits whose TraitDefinition lists no workloads should apply to any workload.",
+			args: args{
+				client: list(nil, definition("manualscalertraits.core.oam.dev")),
+				trait:  traitKind,
+				ref:    ref(cwKind),
+			},
+			want: want{ok: true},
+		},
+		"Wildcard": {
+			reason: "Traits whose TraitDefinition lists '*' should apply to any workload.",
+			args: args{
+				client: list(nil, definition("manualscalertraits.core.oam.dev", "*")),
+				trait:  traitKind,
+				ref:    ref(unknownKind),
+			},
+			want: want{appliesTo: []string{"*"}, ok: true},
+		},
+		"CRDName": {
+			reason: "Traits should apply to workloads whose CRD name is listed.",
+			args: args{
+				client: list(nil, definition("manualscalertraits.core.oam.dev", "containerizedworkloads.core.oam.dev")),
+				trait:  traitKind,
+				ref:    ref(cwKind),
+			},
+			want: want{appliesTo: []string{"containerizedworkloads.core.oam.dev"}, ok: true},
+		},
+		"GroupKind": {
+			reason: "Traits should apply to workloads whose group qualified kind is listed.",
+			args: args{
+				client: list(nil, definition("manualscalertraits.core.oam.dev", "ContainerizedWorkload.core.oam.dev")),
+				trait:  traitKind,
+				ref:    ref(cwKind),
+			},
+			want: want{appliesTo: []string{"ContainerizedWorkload.core.oam.dev"}, ok: true},
+		},
+		"NotApplicable": {
+			reason: "Traits should not apply to workloads whose kind is not listed.",
+			args: args{
+				client: list(nil, definition("manualscalertraits.core.oam.dev", "containerizedworkloads.core.oam.dev")),
+				trait:  traitKind,
+				ref:    ref(unknownKind),
+			},
+			want: want{appliesTo: []string{"containerizedworkloads.core.oam.dev"}, ok: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewDefinitionApplicabilityChecker(tc.args.client, mapper)
+			appliesTo, ok := c.Applies(context.Background(), tc.args.trait, tc.args.ref)
+			if diff := cmp.Diff(tc.want.appliesTo, appliesTo); diff != "" {
+				t.Errorf("\nReason: %s\nc.Applies(...): -want appliesTo, +got appliesTo:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\nReason: %s\nc.Applies(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// Defaults of the Reconciler's backoff.
+const (
+	DefaultMaxBackoff    = 10 * time.Minute
+	DefaultDegradedAfter = 5
+)
+
+// TypeDegraded indicates whether a trait has failed to reconcile repeatedly.
+const TypeDegraded v1alpha1.ConditionType = "Degraded"
+
+// Reasons a trait is or is not degraded.
+const (
+	ReasonPersistentFailure v1alpha1.ConditionReason = "PersistentFailure"
+	ReasonRecovered         v1alpha1.ConditionReason = "Recovered"
+)
+
+// Degraded returns a condition that indicates a trait failed to reconcile the
+// supplied number of consecutive times.
+func Degraded(failures int) v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Type:               TypeDegraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPersistentFailure,
+		Message:            fmt.Sprintf("failed to reconcile %d consecutive times", failures),
+	}
+}
+
+// Recovered returns a condition that indicates a trait that was degraded was
+// successfully reconciled.
+func Recovered() v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Type:               TypeDegraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRecovered,
+	}
+}
+
+// A backoff counts the consecutive failed reconciles of each object, and
+// returns an exponentially increasing duration to wait before the next.
+// Failures are counted in memory, so the count is reset when the controller
+// restarts.
+type backoff struct {
+	base time.Duration
+	max  time.Duration
+
+	mx       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+func newBackoff(base, max time.Duration) *backoff {
+	return &backoff{base: base, max: max, failures: map[types.NamespacedName]int{}}
+}
+
+// fail records a failed reconcile of the supplied object, returning how long to
+// wait before reconciling it again and how many times in a row it has failed.
+func (b *backoff) fail(nn types.NamespacedName) (time.Duration, int) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.failures[nn]++
+	n := b.failures[nn]
+
+	wait := b.base
+	for i := 1; i < n && wait < b.max; i++ {
+		wait *= 2
+	}
+	if wait > b.max {
+		wait = b.max
+	}
+	return wait, n
+}
+
+// reset the failures of the supplied object.
+func (b *backoff) reset(nn types.NamespacedName) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	delete(b.failures, nn)
+}
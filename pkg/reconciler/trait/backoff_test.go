@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBackoff(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "coolns", Name: "cool"}
+
+	type want struct {
+		wait     time.Duration
+		failures int
+	}
+
+	cases := map[string]struct {
+		reason string
+		fails  int
+		reset  bool
+		want   want
+	}{
+		"FirstFailure": {
+			reason: "The first failure should wait the base duration.",
+			fails:  1,
+			want:   want{wait: time.Second, failures: 1},
+		},
+		"ConsecutiveFailures": {
+			reason: "Each consecutive failure should double the wait.",
+			fails:  3,
+			want:   want{wait: 4 * time.Second, failures: 3},
+		},
+		"Capped": {
+			reason: "The wait should never exceed the maximum.",
+			fails:  10,
+			want:   want{wait: 10 * time.Second, failures: 10},
+		},
+		"Reset": {
+			reason: "Failures should be counted from zero after a reset.",
+			fails:  3,
+			reset:  true,
+			want:   want{wait: time.Second, failures: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := newBackoff(time.Second, 10*time.Second)
+
+			var wait time.Duration
+			var failures int
+			for i := 0; i < tc.fails; i++ {
+				wait, failures = b.fail(nn)
+			}
+			if tc.reset {
+				b.reset(nn)
+				wait, failures = b.fail(nn)
+			}
+
+			if diff := cmp.Diff(tc.want, want{wait: wait, failures: failures}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\nReason: %s\nb.fail(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
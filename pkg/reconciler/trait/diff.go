@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package trait
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	errDiffTranslation   = "cannot determine modifications to workload translation"
+	errGetModifiedFields = "cannot get fields modified by traits"
+	errSetModifiedFields = "cannot set fields modified by traits"
+)
+
+// AnnotationModifiedFields records the fields of a workload translation that
+// each trait has modified since the translation was last rendered, so that
+// the modifications of a trait may be reported after the reconcile in which
+// they were made.
+const AnnotationModifiedFields = "trait.oam.crossplane.io/modified-fields"
+
+// A ModificationRecorder is a Trait that records the modifications it made to
+// its workload's translation in its status.
+type ModificationRecorder interface {
+	SetModifications(m []string)
+}
+
+// A modifiedField is a field of a resource template of a workload
+// translation. A field with no path is a template that was created or
+// deleted.
+type modifiedField struct {
+	Template string   `json:"template"`
+	Path     []string `json:"path,omitempty"`
+}
+
+// Modifications returns the fields that differ between two versions of a
+// workload translation, formatted as "kind/name field=value". The resource
+// templates of packages are compared individually.
+func Modifications(before, after runtime.Object) ([]string, error) {
+	fields, err := modifiedFields(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return effective(after, fields)
+}
+
+// recordModifications records the fields of the supplied translation that the
+// supplied trait modified, in addition to those it modified in earlier
+// reconciles of the same rendering, and returns their current values
+// formatted as "kind/name field=value".
+func recordModifications(before runtime.Object, after Object, t Trait) ([]string, error) {
+	fields, err := modifiedFields(before, after)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := getModifiedFields(after)
+	if err != nil {
+		return nil, err
+	}
+	if mf == nil {
+		mf = map[string][]modifiedField{}
+	}
+	mf[traitKey(t)] = mergeFields(mf[traitKey(t)], fields)
+	if err := setModifiedFields(after, mf); err != nil {
+		return nil, err
+	}
+	return effective(after, mf[traitKey(t)])
+}
+
+// forgetModifications removes the fields modified by the supplied trait from
+// the supplied translation's record.
+func forgetModifications(translation Object, t Trait) error {
+	mf, err := getModifiedFields(translation)
+	if err != nil || mf == nil {
+		return err
+	}
+	delete(mf, traitKey(t))
+	return setModifiedFields(translation, mf)
+}
+
+func getModifiedFields(o metav1.Object) (map[string][]modifiedField, error) {
+	raw, ok := o.GetAnnotations()[AnnotationModifiedFields]
+	if !ok {
+		return nil, nil
+	}
+	mf := map[string][]modifiedField{}
+	return mf, errors.Wrap(json.Unmarshal([]byte(raw), &mf), errGetModifiedFields)
+}
+
+func setModifiedFields(o metav1.Object, mf map[string][]modifiedField) error {
+	raw, err := json.Marshal(mf)
+	if err != nil {
+		return errors.Wrap(err, errSetModifiedFields)
+	}
+	meta.AddAnnotations(o, map[string]string{AnnotationModifiedFields: string(raw)})
+	return nil
+}
+
+// mergeFields returns the union of the supplied fields.
+func mergeFields(a, b []modifiedField) []modifiedField {
+	seen := map[string]bool{}
+	out := []modifiedField{}
+	for _, f := range append(append([]modifiedField{}, a...), b...) {
+		k := f.Template + "\x00" + strings.Join(f.Path, "\x00")
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, f)
+	}
+	return out
+}
+
+// modifiedFields returns the fields that differ between two versions of a
+// workload translation.
+func modifiedFields(before, after runtime.Object) ([]modifiedField, error) {
+	b, err := templates(before)
+	if err != nil {
+		return nil, errors.Wrap(err, errDiffTranslation)
+	}
+	a, err := templates(after)
+	if err != nil {
+		return nil, errors.Wrap(err, errDiffTranslation)
+	}
+
+	fields := []modifiedField{}
+	for id, at := range a {
+		bt, ok := b[id]
+		if !ok {
+			fields = append(fields, modifiedField{Template: id})
+			continue
+		}
+		for _, p := range fieldDiff(nil, bt, at) {
+			fields = append(fields, modifiedField{Template: id, Path: p})
+		}
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			fields = append(fields, modifiedField{Template: id})
+		}
+	}
+	return fields, nil
+}
+
+// effective returns the current values of the supplied fields of the supplied
+// translation, formatted as "kind/name field=value".
+func effective(translation runtime.Object, fields []modifiedField) ([]string, error) {
+	tt, err := templates(translation)
+	if err != nil {
+		return nil, errors.Wrap(err, errDiffTranslation)
+	}
+
+	mods := []string{}
+	for _, f := range fields {
+		t, ok := tt[f.Template]
+		switch {
+		case len(f.Path) == 0 && ok:
+			mods = append(mods, f.Template+" created")
+		case len(f.Path) == 0:
+			mods = append(mods, f.Template+" deleted")
+		case !ok:
+			continue
+		default:
+			v, found, err := unstructured.NestedFieldNoCopy(t, f.Path...)
+			if err != nil || !found {
+				mods = append(mods, f.Template+" "+strings.Join(f.Path, ".")+" removed")
+				continue
+			}
+			mods = append(mods, f.Template+" "+strings.Join(f.Path, ".")+"="+format(v))
+		}
+	}
+	sort.Strings(mods)
+	return mods, nil
+}
+
+// templates returns the supplied object as unstructured content keyed by its
+// lower case kind and name. The resource templates of a package are returned
+// instead of the package itself.
+func templates(obj runtime.Object) (map[string]map[string]interface{}, error) {
+	out := map[string]map[string]interface{}{}
+
+	if !isPackage(obj) {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		t := &unstructured.Unstructured{Object: u}
+		out[id(t)] = u
+		return out, nil
+	}
+
+	tt, err := Templates(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tt {
+		out[id(t)] = t.Object
+	}
+	return out, nil
+}
+
+func id(u *unstructured.Unstructured) string {
+	return strings.ToLower(u.GetKind()) + "/" + u.GetName()
+}
+
+// fieldDiff returns the paths of the leaf fields of after that differ from
+// before.
+func fieldDiff(prefix []string, before, after map[string]interface{}) [][]string {
+	diff := [][]string{}
+	for k, av := range after {
+		path := append(append([]string{}, prefix...), k)
+		bv := before[k]
+		am, aok := av.(map[string]interface{})
+		bm, bok := bv.(map[string]interface{})
+		if aok && bok {
+			diff = append(diff, fieldDiff(path, bm, am)...)
+			continue
+		}
+		if !reflect.DeepEqual(av, bv) {
+			diff = append(diff, path)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; ok {
+			continue
+		}
+		diff = append(diff, append(append([]string{}, prefix...), k))
+	}
+	return diff
+}
+
+func format(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package trait
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+)
+
+func TestModifications(t *testing.T) {
+	type args struct {
+		before runtime.Object
+		after  runtime.Object
+	}
+
+	type want struct {
+		mods []string
+		err  error
+	}
+
+	app := func(templates ...string) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, t := range templates {
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte(t)}},
+			})
+		}
+		return a
+	}
+
+	uapp := func(templates ...string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGroupVersionKind(workloadv1alpha1.KubernetesApplicationGroupVersionKind)
+		entries := []interface{}{}
+		for _, t := range templates {
+			tmpl := map[string]interface{}{}
+			_ = json.Unmarshal([]byte(t), &tmpl)
+			entries = append(entries, map[string]interface{}{"spec": map[string]interface{}{"template": tmpl}})
+		}
+		_ = unstructured.SetNestedSlice(u.Object, entries, "spec", "resourceTemplates")
+		return u
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "No modifications should be returned for identical translations.",
+			args: args{
+				before: app(`{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":1}}`),
+				after:  app(`{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":1}}`),
+			},
+			want: want{mods: []string{}},
+		},
+		"ModifiedAndCreated": {
+			reason: "Modified fields and created templates should be returned.",
+			args: args{
+				before: app(`{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":1,"paused":true}}`),
+				after: app(
+					`{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5}}`,
+					`{"kind":"PersistentVolumeClaim","metadata":{"name":"data"}}`,
+				),
+			},
+			want: want{mods: []string{
+				"deployment/web spec.paused removed",
+				"deployment/web spec.replicas=5",
+				"persistentvolumeclaim/data created",
+			}},
+		},
+		"UnstructuredKubeApp": {
+			reason: "The templates of Unstructured KubernetesApplications should be compared individually.",
+			args: args{
+				before: uapp(`{"kind":"Service","metadata":{"name":"web"},"spec":{"type":"ClusterIP"}}`),
+				after:  uapp(`{"kind":"Service","metadata":{"name":"web"},"spec":{"type":"LoadBalancer"}}`),
+			},
+			want: want{mods: []string{"service/web spec.type=LoadBalancer"}},
+		},
+		"NotKubeApp": {
+			reason: "Objects that are not KubernetesApplications should be compared as a whole.",
+			args: args{
+				before: &workloadv1alpha1.KubernetesApplicationResource{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+				after:  &workloadv1alpha1.KubernetesApplicationResource{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"cool": "label"}}},
+			},
+			want: want{mods: []string{"/web metadata.labels={\"cool\":\"label\"}"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Modifications(tc.args.before, tc.args.after)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nModifications(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mods, got); diff != "" {
+				t.Errorf("\nReason: %s\nModifications(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordModifications(t *testing.T) {
+	tr := &traitfake.Trait{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+
+	type args struct {
+		before runtime.Object
+		after  Object
+	}
+
+	type want struct {
+		mods []string
+		err  error
+	}
+
+	app := func(recorded string, templates ...string) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		if recorded != "" {
+			a.SetAnnotations(map[string]string{AnnotationModifiedFields: `{"` + traitKey(tr) + `":` + recorded + `}`})
+		}
+		for _, t := range templates {
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte(t)}},
+			})
+		}
+		return a
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FirstModification": {
+			reason: "Fields modified by the trait should be returned with their values.",
+			args: args{
+				before: app("", `{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":1}}`),
+				after:  app("", `{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5}}`),
+			},
+			want: want{mods: []string{"deployment/web spec.replicas=5"}},
+		},
+		"AlreadyModified": {
+			reason: "Fields modified by the trait in an earlier reconcile should still be returned when nothing changed.",
+			args: args{
+				before: app(`[{"template":"deployment/web","path":["spec","replicas"]}]`, `{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5}}`),
+				after:  app(`[{"template":"deployment/web","path":["spec","replicas"]}]`, `{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5}}`),
+			},
+			want: want{mods: []string{"deployment/web spec.replicas=5"}},
+		},
+		"MergeModifications": {
+			reason: "Fields modified by the trait in this and earlier reconciles should be returned.",
+			args: args{
+				before: app(`[{"template":"deployment/web","path":["spec","replicas"]}]`, `{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5,"paused":true}}`),
+				after: app(`[{"template":"deployment/web","path":["spec","replicas"]}]`,
+					`{"kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":5}}`,
+					`{"kind":"PersistentVolumeClaim","metadata":{"name":"data"}}`,
+				),
+			},
+			want: want{mods: []string{
+				"deployment/web spec.paused removed",
+				"deployment/web spec.replicas=5",
+				"persistentvolumeclaim/data created",
+			}},
+		},
+		"InvalidRecord": {
+			reason: "An error should be returned if the recorded fields cannot be parsed.",
+			args: args{
+				before: app(""),
+				after:  app(`"nope"`),
+			},
+			want: want{err: errors.Wrap(json.Unmarshal([]byte(`{"`+traitKey(tr)+`":"nope"}`), &map[string][]modifiedField{}), errGetModifiedFields)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := recordModifications(tc.args.before, tc.args.after, tr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nrecordModifications(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mods, got); diff != "" {
+				t.Errorf("\nReason: %s\nrecordModifications(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+// ModificationLatency is the time between a workload generation being rendered
+// and a trait first applying its modification to that rendering.
+var ModificationLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "oam_trait_modification_latency_seconds",
+	Help:    "Time from a workload generation being rendered until a trait first modifies its translation.",
+	Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+}, []string{"trait"})
+
+func init() {
+	metrics.Registry.MustRegister(ModificationLatency)
+}
+
+// traitKey returns the key under which the generation of the supplied trait is
+// recorded in the render inputs of a translation.
+func traitKey(t Trait) string {
+	return strings.ToLower(t.GetObjectKind().GroupVersionKind().GroupKind().String()) + "/" + t.GetName()
+}
+
+// renderedAt returns the time at which the supplied translation was rendered,
+// and whether the supplied trait has yet to modify that rendering.
+func renderedAt(translation Object, t Trait) (time.Time, bool) {
+	ri, err := workload.GetRenderInputs(translation)
+	if err != nil || ri == nil {
+		return time.Time{}, false
+	}
+	if _, ok := ri.TraitGenerations[traitKey(t)]; ok {
+		return time.Time{}, false
+	}
+	if ri.RenderedAt != nil {
+		return ri.RenderedAt.Time, true
+	}
+	return translation.GetCreationTimestamp().Time, true
+}
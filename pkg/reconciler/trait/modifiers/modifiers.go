@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package modifiers contains helpers that make modifications common to many
+// traits. Each helper accepts the typed workload objects the addon produces,
+// for example a *appsv1.Deployment, as well as their Unstructured equivalents.
+package modifiers
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNoReplicas        = "object does not have replicas"
+	errNoPodTemplate     = "object does not have a pod template"
+	errGetPodTemplate    = "cannot get pod template"
+	errSetPodTemplate    = "cannot set pod template"
+	errSetReplicas       = "cannot set replicas"
+	errNoContainer       = "no container found"
+	errNotPackage        = "object is not a package of resource templates"
+	errConvertPackage    = "cannot convert package"
+	errGetManifests      = "cannot get manifests of package"
+	errSetManifests      = "cannot set manifests of package"
+	errManifestNotObject = "manifest is not an object"
+)
+
+// AllContainers may be supplied as the container name to modify all
+// containers of a pod template.
+const AllContainers = ""
+
+// SetReplicas sets the replicas of the supplied Deployment, StatefulSet, or
+// ReplicaSet, or of an Unstructured object with a spec.replicas field.
+func SetReplicas(obj runtime.Object, replicas int32) error {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		o.Spec.Replicas = &replicas
+	case *appsv1.StatefulSet:
+		o.Spec.Replicas = &replicas
+	case *appsv1.ReplicaSet:
+		o.Spec.Replicas = &replicas
+	case *unstructured.Unstructured:
+		return errors.Wrap(unstructured.SetNestedField(o.Object, int64(replicas), "spec", "replicas"), errSetReplicas)
+	default:
+		return errors.New(errNoReplicas)
+	}
+	return nil
+}
+
+// AddEnvVar adds the supplied environment variable to the named container of
+// the supplied object's pod template, or to all of its containers if the name
+// is AllContainers. A variable of the same name is replaced.
+func AddEnvVar(obj runtime.Object, container string, env corev1.EnvVar) error {
+	return withPodTemplate(obj, func(pt *corev1.PodTemplateSpec) error {
+		return withContainers(pt, container, func(c *corev1.Container) {
+			for i := range c.Env {
+				if c.Env[i].Name == env.Name {
+					c.Env[i] = env
+					return
+				}
+			}
+			c.Env = append(c.Env, env)
+		})
+	})
+}
+
+// AddLabelToPodTemplate adds the supplied label to the supplied object's pod
+// template, replacing any existing value. The object's selector is not
+// modified.
+func AddLabelToPodTemplate(obj runtime.Object, k, v string) error {
+	return withPodTemplate(obj, func(pt *corev1.PodTemplateSpec) error {
+		if pt.Labels == nil {
+			pt.Labels = map[string]string{}
+		}
+		pt.Labels[k] = v
+		return nil
+	})
+}
+
+// SetImageTag sets the tag of the image of the named container of the
+// supplied object's pod template, or of all of its containers if the name is
+// AllContainers. Any existing tag or digest is removed.
+func SetImageTag(obj runtime.Object, container, tag string) error {
+	return withPodTemplate(obj, func(pt *corev1.PodTemplateSpec) error {
+		return withContainers(pt, container, func(c *corev1.Container) {
+			c.Image = imageRepository(c.Image) + ":" + tag
+		})
+	})
+}
+
+// AppendTemplate adds the supplied object to the supplied package as a
+// resource template, replacing any existing template of the same kind,
+// namespace, and name, so that it may be called each time a trait modifies
+// the package. The object's kind must be set. Packages may be
+// KubernetesApplications, or Unstructured KubernetesApplications or
+// ManifestWorks.
+func AppendTemplate(pkg runtime.Object, o trait.Object) error {
+	switch p := pkg.(type) {
+	case *workloadv1alpha1.KubernetesApplication:
+		return trait.SetKubeAppTemplate(p, o)
+	case *unstructured.Unstructured:
+		switch p.GroupVersionKind().GroupKind() {
+		case workloadv1alpha1.KubernetesApplicationGroupVersionKind.GroupKind():
+			return appendUnstructuredKubeAppTemplate(p, o)
+		case workload.ManifestWorkGroupVersionKind.GroupKind():
+			return appendManifest(p, o)
+		}
+	}
+	return errors.New(errNotPackage)
+}
+
+// imageRepository returns the supplied image without its tag or digest.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// A colon before the last slash separates a registry host from its
+	// port, not a repository from its tag.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// withPodTemplate calls the supplied function with the pod template of the
+// supplied object, and stores any modifications it makes.
+func withPodTemplate(obj runtime.Object, fn func(*corev1.PodTemplateSpec) error) error {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return fn(&o.Spec.Template)
+	case *appsv1.StatefulSet:
+		return fn(&o.Spec.Template)
+	case *appsv1.DaemonSet:
+		return fn(&o.Spec.Template)
+	case *appsv1.ReplicaSet:
+		return fn(&o.Spec.Template)
+	case *batchv1.Job:
+		return fn(&o.Spec.Template)
+	case *unstructured.Unstructured:
+		// Unstructured pod templates are converted to their typed
+		// equivalent so that they are modified exactly as typed pod
+		// templates are.
+		m, found, err := unstructured.NestedMap(o.Object, "spec", "template")
+		if err != nil {
+			return errors.Wrap(err, errGetPodTemplate)
+		}
+		if !found {
+			return errors.New(errNoPodTemplate)
+		}
+		pt := &corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, pt); err != nil {
+			return errors.Wrap(err, errGetPodTemplate)
+		}
+		if err := fn(pt); err != nil {
+			return err
+		}
+		m, err = runtime.DefaultUnstructuredConverter.ToUnstructured(pt)
+		if err != nil {
+			return errors.Wrap(err, errSetPodTemplate)
+		}
+		return errors.Wrap(unstructured.SetNestedMap(o.Object, m, "spec", "template"), errSetPodTemplate)
+	default:
+		return errors.New(errNoPodTemplate)
+	}
+}
+
+// withContainers calls the supplied function with the named container of the
+// supplied pod template, or with each of its containers if the name is
+// AllContainers.
+func withContainers(pt *corev1.PodTemplateSpec, name string, fn func(*corev1.Container)) error {
+	found := false
+	for i := range pt.Spec.Containers {
+		if name != AllContainers && pt.Spec.Containers[i].Name != name {
+			continue
+		}
+		fn(&pt.Spec.Containers[i])
+		found = true
+	}
+	if !found {
+		return errors.Errorf("%s: %s", errNoContainer, name)
+	}
+	return nil
+}
+
+// appendUnstructuredKubeAppTemplate adds the supplied object to the supplied
+// Unstructured KubernetesApplication as a resource template.
+func appendUnstructuredKubeAppTemplate(p *unstructured.Unstructured, o trait.Object) error {
+	a := &workloadv1alpha1.KubernetesApplication{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(p.Object, a); err != nil {
+		return errors.Wrap(err, errConvertPackage)
+	}
+	if err := trait.SetKubeAppTemplate(a, o); err != nil {
+		return err
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(a)
+	if err != nil {
+		return errors.Wrap(err, errConvertPackage)
+	}
+	p.Object = m
+	return nil
+}
+
+// appendManifest adds the supplied object to the supplied ManifestWork as a
+// manifest.
+func appendManifest(p *unstructured.Unstructured, o trait.Object) error {
+	m, err := toUnstructured(o)
+	if err != nil {
+		return errors.Wrap(err, errSetManifests)
+	}
+	add := &unstructured.Unstructured{Object: m}
+
+	manifests, _, err := unstructured.NestedSlice(p.Object, "spec", "workload", "manifests")
+	if err != nil {
+		return errors.Wrap(err, errGetManifests)
+	}
+
+	replaced := false
+	for i, e := range manifests {
+		em, ok := e.(map[string]interface{})
+		if !ok {
+			return errors.New(errManifestNotObject)
+		}
+		existing := &unstructured.Unstructured{Object: em}
+		if existing.GroupVersionKind().GroupKind() != add.GroupVersionKind().GroupKind() ||
+			existing.GetNamespace() != add.GetNamespace() ||
+			existing.GetName() != add.GetName() {
+			continue
+		}
+		manifests[i] = m
+		replaced = true
+		break
+	}
+	if !replaced {
+		manifests = append(manifests, m)
+	}
+
+	return errors.Wrap(unstructured.SetNestedSlice(p.Object, manifests, "spec", "workload", "manifests"), errSetManifests)
+}
+
+// toUnstructured returns the content of the supplied object.
+func toUnstructured(o runtime.Object) (map[string]interface{}, error) {
+	if u, ok := o.(*unstructured.Unstructured); ok {
+		return u.DeepCopy().UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+}
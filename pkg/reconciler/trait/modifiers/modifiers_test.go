@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modifiers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func deployment(containers ...corev1.Container) *appsv1.Deployment {
+	return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: containers},
+	}}}
+}
+
+func unstructuredDeployment(t *testing.T, d *appsv1.Deployment) *unstructured.Unstructured {
+	t.Helper()
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: m}
+}
+
+func TestSetReplicas(t *testing.T) {
+	three := int32(3)
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   runtime.Object
+		err    error
+	}{
+		"NoReplicas": {
+			reason: "Objects without replicas should return an error.",
+			obj:    &appsv1.DaemonSet{},
+			want:   &appsv1.DaemonSet{},
+			err:    errors.New(errNoReplicas),
+		},
+		"Deployment": {
+			reason: "The replicas of a Deployment should be set.",
+			obj:    &appsv1.Deployment{},
+			want:   &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &three}},
+		},
+		"Unstructured": {
+			reason: "The spec.replicas of an Unstructured object should be set.",
+			obj:    &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(3)}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetReplicas(tc.obj, three)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSetReplicas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.obj); diff != "" {
+				t.Errorf("\nReason: %s\nSetReplicas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAddEnvVar(t *testing.T) {
+	env := corev1.EnvVar{Name: "COOL", Value: "very"}
+
+	cases := map[string]struct {
+		reason    string
+		obj       runtime.Object
+		container string
+		want      runtime.Object
+		err       error
+	}{
+		"NoPodTemplate": {
+			reason: "Objects without a pod template should return an error.",
+			obj:    &corev1.Service{},
+			want:   &corev1.Service{},
+			err:    errors.New(errNoPodTemplate),
+		},
+		"NoContainer": {
+			reason:    "Naming a container that does not exist should return an error.",
+			obj:       deployment(corev1.Container{Name: "a"}),
+			container: "b",
+			want:      deployment(corev1.Container{Name: "a"}),
+			err:       errors.Errorf("%s: %s", errNoContainer, "b"),
+		},
+		"NamedContainer": {
+			reason:    "The variable should be added only to the named container.",
+			obj:       deployment(corev1.Container{Name: "a"}, corev1.Container{Name: "b"}),
+			container: "b",
+			want:      deployment(corev1.Container{Name: "a"}, corev1.Container{Name: "b", Env: []corev1.EnvVar{env}}),
+		},
+		"AllContainers": {
+			reason:    "The variable should be added to all containers, replacing a variable of the same name.",
+			obj:       deployment(corev1.Container{Name: "a", Env: []corev1.EnvVar{{Name: "COOL", Value: "not"}}}, corev1.Container{Name: "b"}),
+			container: AllContainers,
+			want:      deployment(corev1.Container{Name: "a", Env: []corev1.EnvVar{env}}, corev1.Container{Name: "b", Env: []corev1.EnvVar{env}}),
+		},
+		"Unstructured": {
+			reason:    "The variable should be added to the containers of an Unstructured pod template.",
+			obj:       unstructuredDeployment(t, deployment(corev1.Container{Name: "a"})),
+			container: "a",
+			want:      unstructuredDeployment(t, deployment(corev1.Container{Name: "a", Env: []corev1.EnvVar{env}})),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := AddEnvVar(tc.obj, tc.container, env)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nAddEnvVar(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.obj); diff != "" {
+				t.Errorf("\nReason: %s\nAddEnvVar(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAddLabelToPodTemplate(t *testing.T) {
+	labelled := deployment()
+	labelled.Spec.Template.Labels = map[string]string{"cool": "very"}
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   runtime.Object
+		err    error
+	}{
+		"NoPodTemplate": {
+			reason: "Objects without a pod template should return an error.",
+			obj:    &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   &unstructured.Unstructured{Object: map[string]interface{}{}},
+			err:    errors.New(errNoPodTemplate),
+		},
+		"Deployment": {
+			reason: "The label should be added to the pod template of a Deployment.",
+			obj:    deployment(),
+			want:   labelled,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := AddLabelToPodTemplate(tc.obj, "cool", "very")
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nAddLabelToPodTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.obj); diff != "" {
+				t.Errorf("\nReason: %s\nAddLabelToPodTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetImageTag(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		image  string
+		want   string
+	}{
+		"Untagged": {
+			reason: "Untagged images should be tagged.",
+			image:  "nginx",
+			want:   "nginx:v2",
+		},
+		"Tagged": {
+			reason: "The tag of tagged images should be replaced.",
+			image:  "example.org/cool/nginx:v1",
+			want:   "example.org/cool/nginx:v2",
+		},
+		"RegistryPort": {
+			reason: "The port of a registry should not be mistaken for a tag.",
+			image:  "example.org:5000/nginx",
+			want:   "example.org:5000/nginx:v2",
+		},
+		"Digest": {
+			reason: "The digest of an image should be removed.",
+			image:  "example.org:5000/nginx:v1@sha256:abc",
+			want:   "example.org:5000/nginx:v2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := deployment(corev1.Container{Name: "a", Image: tc.image})
+			if err := SetImageTag(d, "a", "v2"); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, d.Spec.Template.Spec.Containers[0].Image); diff != "" {
+				t.Errorf("\nReason: %s\nSetImageTag(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAppendTemplate(t *testing.T) {
+	cm := func(name, data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cool-ns", Name: name},
+			Data:       map[string]string{"cool": data},
+		}
+	}
+	manifest := func(name, data string) interface{} {
+		m, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(cm(name, data))
+		return m
+	}
+	manifestWork := func(manifests ...interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGroupVersionKind(workload.ManifestWorkGroupVersionKind)
+		_ = unstructured.SetNestedSlice(u.Object, manifests, "spec", "workload", "manifests")
+		return u
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkg    runtime.Object
+		o      trait.Object
+		want   runtime.Object
+		err    error
+	}{
+		"NotPackage": {
+			reason: "Objects that are not packages should return an error.",
+			pkg:    &appsv1.Deployment{},
+			o:      cm("cool-cm", "very"),
+			want:   &appsv1.Deployment{},
+			err:    errors.New(errNotPackage),
+		},
+		"AppendManifest": {
+			reason: "Objects should be appended to the manifests of a ManifestWork.",
+			pkg:    manifestWork(manifest("cool-cm", "very")),
+			o:      cm("other-cm", "very"),
+			want:   manifestWork(manifest("cool-cm", "very"), manifest("other-cm", "very")),
+		},
+		"ReplaceManifest": {
+			reason: "Objects should replace a manifest of the same kind, namespace, and name.",
+			pkg:    manifestWork(manifest("cool-cm", "not")),
+			o:      cm("cool-cm", "very"),
+			want:   manifestWork(manifest("cool-cm", "very")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := AppendTemplate(tc.pkg, tc.o)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nAppendTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.pkg); diff != "" {
+				t.Errorf("\nReason: %s\nAppendTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAppendKubeAppTemplate(t *testing.T) {
+	a := &workloadv1alpha1.KubernetesApplication{}
+	c := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-cm"},
+	}
+
+	// Appending the same object twice should not duplicate its template.
+	for i := 0; i < 2; i++ {
+		if err := AppendTemplate(a, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff(1, len(a.Spec.ResourceTemplates)); diff != "" {
+		t.Errorf("AppendTemplate(...): -want templates, +got templates:\n%s", diff)
+	}
+
+	got := &corev1.ConfigMap{}
+	if err := json.Unmarshal(a.Spec.ResourceTemplates[0].Spec.Template.Raw, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(c, got); diff != "" {
+		t.Errorf("AppendTemplate(...): -want, +got:\n%s", diff)
+	}
+}
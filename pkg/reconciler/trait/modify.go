@@ -19,21 +19,50 @@ package trait
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 const (
 	errNotKubeApp           = "object passed to KubernetesApplication accessor is not KubernetesApplication"
 	errNoDeploymentForTrait = "no deployment found for trait in KubernetesApplication"
+	errSetTemplate          = "cannot set resource template in KubernetesApplication"
+	errTemplateNotInShard   = "resource templates may only be added to the first shard of a KubernetesApplication"
 )
 
+// errNoTarget indicates that a package does not contain the object a trait
+// modifies.
+type errNoTarget struct{ msg string }
+
+func (e errNoTarget) Error() string {
+	return e.msg
+}
+
+// NewNoTargetError returns an error indicating that a package does not
+// contain the object a trait modifies.
+func NewNoTargetError(msg string) error {
+	return errNoTarget{msg: msg}
+}
+
+// IsNoTarget returns true if the supplied error indicates that a package does
+// not contain the object a trait modifies. Shards of a sharded package are
+// left unchanged rather than failing the trait when this is so.
+func IsNoTarget(err error) bool {
+	_, ok := errors.Cause(err).(errNoTarget)
+	return ok
+}
+
 var (
 	deploymentKind = reflect.TypeOf(appsv1.Deployment{}).Name()
 )
@@ -44,6 +73,32 @@ type Modifier interface {
 	Modify(context.Context, runtime.Object, Trait) error
 }
 
+// A Reverter is responsible for removing the modifications a Modifier made to
+// a workload translation, for example when the trait that made them is
+// deleted.
+type Reverter interface {
+	Revert(context.Context, runtime.Object, Trait) error
+}
+
+// A RevertibleModifier is a Modifier whose modifications can be reverted.
+type RevertibleModifier struct {
+	Modifier
+
+	revert Modifier
+}
+
+// NewRevertibleModifier returns a Modifier that modifies a workload translation
+// using the first supplied Modifier, and reverts its modifications using the
+// second.
+func NewRevertibleModifier(m, revert Modifier) *RevertibleModifier {
+	return &RevertibleModifier{Modifier: m, revert: revert}
+}
+
+// Revert the modifications made to a workload translation.
+func (m *RevertibleModifier) Revert(ctx context.Context, obj runtime.Object, t Trait) error {
+	return m.revert.Modify(ctx, obj, t)
+}
+
 // WorkloadModifier is a concrete implementation of a Modifier.
 type WorkloadModifier struct {
 	ModifyFn
@@ -117,5 +172,57 @@ func DeploymentFromKubeAppAccessor(ctx context.Context, obj runtime.Object, t Tr
 		}
 	}
 
-	return errors.New(errNoDeploymentForTrait)
+	return errNoTarget{msg: errNoDeploymentForTrait}
+}
+
+// SetKubeAppTemplate adds the supplied object to a KubernetesApplication as a
+// resource template, replacing any existing template for the same object. The
+// template is named and labelled the same way as templates produced by the
+// workload reconciler so that it is selected by the KubernetesApplication.
+// Templates are only added to the first shard of a sharded package, so that
+// the same object is not templated by several shards.
+func SetKubeAppTemplate(a *workloadv1alpha1.KubernetesApplication, o Object) error {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return errors.Wrap(err, errSetTemplate)
+	}
+
+	kart := workloadv1alpha1.KubernetesApplicationResourceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)),
+		},
+		Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+			Template: runtime.RawExtension{Raw: b},
+		},
+	}
+	if a.Spec.ResourceSelector != nil && len(a.Spec.ResourceSelector.MatchLabels) > 0 {
+		kart.SetLabels(make(map[string]string, len(a.Spec.ResourceSelector.MatchLabels)))
+		for k, v := range a.Spec.ResourceSelector.MatchLabels {
+			kart.GetLabels()[k] = v
+		}
+	}
+
+	for i, t := range a.Spec.ResourceTemplates {
+		if t.GetName() == kart.GetName() {
+			a.Spec.ResourceTemplates[i] = kart
+			return nil
+		}
+	}
+	if i := a.GetAnnotations()[workload.AnnotationShard]; i != "" && i != "0" {
+		return errNoTarget{msg: errTemplateNotInShard}
+	}
+	a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, kart)
+	return nil
+}
+
+// RemoveKubeAppTemplate removes the template for the supplied object that was
+// added to a KubernetesApplication by SetKubeAppTemplate, if any.
+func RemoveKubeAppTemplate(a *workloadv1alpha1.KubernetesApplication, o Object) {
+	name := fmt.Sprintf("%s-%s", o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind))
+	for i, t := range a.Spec.ResourceTemplates {
+		if t.GetName() == name {
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates[:i], a.Spec.ResourceTemplates[i+1:]...)
+			return
+		}
+	}
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -30,6 +31,7 @@ import (
 	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
 
 	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 var (
@@ -65,7 +67,7 @@ func TestKubeAppWrapper(t *testing.T) {
 				o: &workloadv1alpha1.KubernetesApplication{},
 				t: &traitfake.Trait{},
 			},
-			want: want{err: errors.New(errNoDeploymentForTrait)},
+			want: want{err: errNoTarget{msg: errNoDeploymentForTrait}},
 		},
 		"SuccessfulNoopModifier": {
 			reason: "KubernetesApplication has matching Deployment and is modified successfully.",
@@ -111,3 +113,178 @@ func TestKubeAppWrapper(t *testing.T) {
 		})
 	}
 }
+
+func TestSetKubeAppTemplate(t *testing.T) {
+	type args struct {
+		a *workloadv1alpha1.KubernetesApplication
+		o Object
+	}
+
+	type want struct {
+		templates []string
+		err       error
+	}
+
+	labels := map[string]string{"cool": "label"}
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Append": {
+			reason: "An object that is not yet templated should be appended.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceSelector: &metav1.LabelSelector{MatchLabels: labels},
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-deployment"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: want{templates: []string{"cool-deployment", "cool-configmap"}},
+		},
+		"Replace": {
+			reason: "An object that is already templated should be replaced.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-configmap"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: want{templates: []string{"cool-configmap"}},
+		},
+		"ReplaceInShard": {
+			reason: "An object that is already templated by a shard other than the first should be replaced.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationShard: "1"}},
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-configmap"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: want{templates: []string{"cool-configmap"}},
+		},
+		"AppendToShard": {
+			reason: "An object should not be appended to a shard other than the first.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workload.AnnotationShard: "1"}},
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-deployment"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: want{
+				templates: []string{"cool-deployment"},
+				err:       errNoTarget{msg: errTemplateNotInShard},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetKubeAppTemplate(tc.args.a, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSetKubeAppTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			got := make([]string, 0, len(tc.args.a.Spec.ResourceTemplates))
+			for _, kart := range tc.args.a.Spec.ResourceTemplates {
+				got = append(got, kart.GetName())
+			}
+			if diff := cmp.Diff(tc.want.templates, got); diff != "" {
+				t.Errorf("\nReason: %s\nSetKubeAppTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			for _, kart := range tc.args.a.Spec.ResourceTemplates {
+				if kart.GetName() != "cool-configmap" || tc.args.a.Spec.ResourceSelector == nil {
+					continue
+				}
+				if diff := cmp.Diff(labels, kart.GetLabels()); diff != "" {
+					t.Errorf("\nReason: %s\nSetKubeAppTemplate(...): -want labels, +got labels:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveKubeAppTemplate(t *testing.T) {
+	type args struct {
+		a *workloadv1alpha1.KubernetesApplication
+		o Object
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Remove": {
+			reason: "The template for the supplied object should be removed.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-deployment"}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-configmap"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: []string{"cool-deployment"},
+		},
+		"NotTemplated": {
+			reason: "Removing an object that is not templated should be a no-op.",
+			args: args{
+				a: &workloadv1alpha1.KubernetesApplication{
+					Spec: workloadv1alpha1.KubernetesApplicationSpec{
+						ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+							{ObjectMeta: metav1.ObjectMeta{Name: "cool-deployment"}},
+						},
+					},
+				},
+				o: cm,
+			},
+			want: []string{"cool-deployment"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			RemoveKubeAppTemplate(tc.args.a, tc.args.o)
+
+			got := make([]string, 0, len(tc.args.a.Spec.ResourceTemplates))
+			for _, kart := range tc.args.a.Spec.ResourceTemplates {
+				got = append(got, kart.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nRemoveKubeAppTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
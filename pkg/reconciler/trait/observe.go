@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+)
+
+const (
+	errParseTemplate       = "cannot parse resource template of KubernetesApplication"
+	errGetResource         = "cannot get KubernetesApplicationResource"
+	errMarshalRemoteStatus = "cannot marshal remote status of KubernetesApplicationResource"
+)
+
+// An Observer observes the state of the remote objects of a workload
+// translation, so that traits can reflect the effect of their modifications.
+type Observer interface {
+	Observe(ctx context.Context, translation runtime.Object) ([]remotev1alpha1.RemoteObservation, error)
+}
+
+// An ObserveFn observes the state of the remote objects of a workload
+// translation.
+type ObserveFn func(ctx context.Context, translation runtime.Object) ([]remotev1alpha1.RemoteObservation, error)
+
+// Observe the state of the remote objects of a workload translation.
+func (fn ObserveFn) Observe(ctx context.Context, translation runtime.Object) ([]remotev1alpha1.RemoteObservation, error) {
+	return fn(ctx, translation)
+}
+
+var _ Observer = ObserveFn(NopObserve)
+
+// NopObserve observes nothing and returns no errors.
+func NopObserve(_ context.Context, _ runtime.Object) ([]remotev1alpha1.RemoteObservation, error) {
+	return nil, nil
+}
+
+// An ObservationRecorder is a Trait that records the observed states of the
+// remote objects it modifies in its status.
+type ObservationRecorder interface {
+	SetObservations(o []remotev1alpha1.RemoteObservation)
+}
+
+// An ObservationHandler reflects the observed states of the remote objects a
+// trait modifies in the trait's status.
+type ObservationHandler func(t Trait, o []remotev1alpha1.RemoteObservation)
+
+var _ ObservationHandler = RecordObservations
+
+// RecordObservations records the supplied observations if the supplied trait
+// is an ObservationRecorder.
+func RecordObservations(t Trait, o []remotev1alpha1.RemoteObservation) {
+	if or, ok := t.(ObservationRecorder); ok {
+		or.SetObservations(o)
+	}
+}
+
+// A KubeAppObserver observes the remote objects of a KubernetesApplication by
+// reading the remote status of each of its KubernetesApplicationResources.
+type KubeAppObserver struct {
+	client client.Reader
+}
+
+// NewKubeAppObserver returns an Observer of KubernetesApplications.
+func NewKubeAppObserver(c client.Reader) *KubeAppObserver {
+	return &KubeAppObserver{client: c}
+}
+
+// Observe the remote objects of the supplied KubernetesApplication. Objects
+// whose KubernetesApplicationResource does not yet exist or has no remote
+// status are observed without a status.
+func (o *KubeAppObserver) Observe(ctx context.Context, translation runtime.Object) ([]remotev1alpha1.RemoteObservation, error) {
+	a, ok := translation.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return nil, errors.New(errNotKubeApp)
+	}
+
+	obs := make([]remotev1alpha1.RemoteObservation, 0, len(a.Spec.ResourceTemplates))
+	for _, rt := range a.Spec.ResourceTemplates {
+		template := &unstructured.Unstructured{}
+		if err := json.Unmarshal(rt.Spec.Template.Raw, template); err != nil {
+			return nil, errors.Wrap(err, errParseTemplate)
+		}
+		ob := remotev1alpha1.RemoteObservation{
+			APIVersion: template.GetAPIVersion(),
+			Kind:       template.GetKind(),
+			Name:       template.GetName(),
+		}
+
+		// The remote status is read without assuming its schema, which
+		// depends on the kind of the remote object.
+		kar := &unstructured.Unstructured{}
+		kar.SetGroupVersionKind(workloadv1alpha1.KubernetesApplicationResourceGroupVersionKind)
+		if err := o.client.Get(ctx, types.NamespacedName{Namespace: a.GetNamespace(), Name: rt.GetName()}, kar); resource.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, errGetResource)
+		}
+		if remote, found, _ := unstructured.NestedFieldNoCopy(kar.Object, "status", "remote"); found {
+			b, err := json.Marshal(remote)
+			if err != nil {
+				return nil, errors.Wrap(err, errMarshalRemoteStatus)
+			}
+			ob.Status = runtime.RawExtension{Raw: b}
+		}
+
+		obs = append(obs, ob)
+	}
+	return obs, nil
+}
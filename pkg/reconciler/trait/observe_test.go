@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+)
+
+func TestKubeAppObserver(t *testing.T) {
+	type args struct {
+		c           client.Reader
+		translation runtime.Object
+	}
+
+	type want struct {
+		obs []remotev1alpha1.RemoteObservation
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	app := &workloadv1alpha1.KubernetesApplication{
+		Spec: workloadv1alpha1.KubernetesApplicationSpec{
+			ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-deployment"},
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+					Template: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"}}`)},
+				},
+			}},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotKubeApp": {
+			reason: "Translations that are not KubernetesApplications should return an error.",
+			args: args{
+				translation: &unstructured.Unstructured{},
+			},
+			want: want{err: errors.New(errNotKubeApp)},
+		},
+		"GetResourceError": {
+			reason: "Errors getting a KubernetesApplicationResource should be returned.",
+			args: args{
+				c:           &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				translation: app,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetResource)},
+		},
+		"ResourceNotFound": {
+			reason: "Remote objects whose KubernetesApplicationResource does not exist should be observed without a status.",
+			args: args{
+				c:           &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				translation: app,
+			},
+			want: want{obs: []remotev1alpha1.RemoteObservation{{APIVersion: "apps/v1", Kind: "Deployment", Name: "cool"}}},
+		},
+		"Success": {
+			reason: "The remote status of each KubernetesApplicationResource should be observed.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					u := obj.(*unstructured.Unstructured)
+					return unstructured.SetNestedField(u.Object, map[string]interface{}{"readyReplicas": int64(3)}, "status", "remote")
+				})},
+				translation: app,
+			},
+			want: want{obs: []remotev1alpha1.RemoteObservation{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "cool",
+				Status:     runtime.RawExtension{Raw: []byte(`{"readyReplicas":3}`)},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewKubeAppObserver(tc.args.c)
+			got, err := o.Observe(context.Background(), tc.args.translation)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\no.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.obs, got); diff != "" {
+				t.Errorf("\nReason: %s\no.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+// IndexPackageControllers is the name of a field index of packages by the
+// workload that controls them.
+const IndexPackageControllers = "metadata.controller"
+
+// ReasonPackagingModeMismatch indicates that a trait modifies a kind of
+// package that its workload was not packaged as.
+const ReasonPackagingModeMismatch v1alpha1.ConditionReason = "PackagingModeMismatch"
+
+// PackagingModeMismatch returns a condition that indicates a trait cannot be
+// synced because it modifies a kind of package that its workload was not
+// packaged as.
+func PackagingModeMismatch(workload, modifies string, packagedAs []string) v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Type:               v1alpha1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPackagingModeMismatch,
+		Message:            fmt.Sprintf("trait modifies %s, but workload %s is packaged as %s", modifies, workload, strings.Join(packagedAs, ", ")),
+	}
+}
+
+// PackageControllerKey returns the IndexPackageControllers key of the
+// packages of the referenced workload, for example
+// ContainerizedWorkload.core.oam.dev/cool. Keys include the workload's group
+// and kind, but not its version, so that packages are found regardless of
+// the version of the workload they were translated from.
+func PackageControllerKey(ref oamv1alpha2.WorkloadReference) string {
+	return schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind().String() + "/" + ref.Name
+}
+
+// PackageControllers returns the key of the workload that controls the
+// supplied package, for use as the IndexPackageControllers field index.
+// Packages without a controller are not indexed.
+func PackageControllers(o runtime.Object) []string {
+	m, ok := o.(metav1.Object)
+	if !ok {
+		return nil
+	}
+	c := metav1.GetControllerOf(m)
+	if c == nil {
+		return nil
+	}
+	return []string{PackageControllerKey(oamv1alpha2.WorkloadReference{APIVersion: c.APIVersion, Kind: c.Kind, Name: c.Name})}
+}
+
+// AddPackageControllerIndex adds the IndexPackageControllers field index of
+// the supplied kind of package to the supplied indexer.
+func AddPackageControllerIndex(i client.FieldIndexer, pkg Object) error {
+	return i.IndexField(pkg, IndexPackageControllers, PackageControllers)
+}
+
+// packagedAs returns the kinds the referenced workload was most recently
+// packaged as, and true if they are known. Workloads that cannot be read are
+// treated as if their kinds are unknown.
+func (r *Reconciler) packagedAs(ctx context.Context, namespace string, ref oamv1alpha2.WorkloadReference) ([]string, bool) {
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion(ref.APIVersion)
+	w.SetKind(ref.Kind)
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, w); err != nil {
+		return nil, false
+	}
+	return annotations.PackageKinds(w)
+}
+
+// packageNameOf returns the name and namespace of the package of the
+// referenced workload. Packages are named after their workload unless the
+// Reconciler has a PackageNamer, in which case the workload must be read in
+// order to name its package. A workload that does not exist has no package, so
+// its not found error is returned as is.
+func (r *Reconciler) packageNameOf(ctx context.Context, namespace string, ref oamv1alpha2.WorkloadReference) (types.NamespacedName, error) {
+	nn := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	if r.packageName == nil {
+		return nn, nil
+	}
+
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion(ref.APIVersion)
+	w.SetKind(ref.Kind)
+	if err := r.client.Get(ctx, nn, w); err != nil {
+		return types.NamespacedName{}, err
+	}
+	return r.packageName(w), nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// getTranslation returns the package of the referenced workload, and its
+// name and namespace. The first shard of a sharded package is returned. If the
+// Reconciler has a package index the package the workload controls is looked
+// up in it, regardless of how the package is named. Packages that are not in
+// the index, for example because they have not yet been adopted by their
+// workload, are found by name.
+func (r *Reconciler) getTranslation(ctx context.Context, namespace string, ref oamv1alpha2.WorkloadReference) (types.NamespacedName, Object, error) {
+	if r.packageIndex {
+		l := r.newTranslationList()
+		if err := r.client.List(ctx, l, client.InNamespace(namespace), client.MatchingFields{IndexPackageControllers: PackageControllerKey(ref)}); err != nil {
+			return types.NamespacedName{}, nil, err
+		}
+		items, err := kmeta.ExtractList(l)
+		if err != nil {
+			return types.NamespacedName{}, nil, err
+		}
+		for _, i := range items {
+			o, ok := i.(Object)
+			if !ok {
+				continue
+			}
+			if s := o.GetAnnotations()[workload.AnnotationShard]; s == "" || s == "0" {
+				return types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, o, nil
+			}
+		}
+	}
+
+	nn, err := r.packageNameOf(ctx, namespace, ref)
+	if err != nil {
+		return nn, nil, err
+	}
+	translation := r.newTranslation()
+	return nn, translation, r.client.Get(ctx, nn, translation)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func TestPackageNameOf(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := oamv1alpha2.WorkloadReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "cool-workload"}
+	prefixed := func(w metav1.Object) types.NamespacedName {
+		return types.NamespacedName{Namespace: w.GetNamespace(), Name: "team-a-" + w.GetName()}
+	}
+
+	type want struct {
+		nn  types.NamespacedName
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		n      workload.PackageNamer
+		want   want
+	}{
+		"NoPackageNamer": {
+			reason: "Packages should be named after their workload by default.",
+			c:      &test.MockClient{},
+			want:   want{nn: types.NamespacedName{Namespace: "cool-ns", Name: "cool-workload"}},
+		},
+		"GetWorkloadError": {
+			reason: "Errors getting the workload should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			n:      prefixed,
+			want:   want{err: errBoom},
+		},
+		"PackageNamer": {
+			reason: "Packages should be named by the PackageNamer.",
+			c: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+				o := obj.(metav1.Object)
+				o.SetNamespace(key.Namespace)
+				o.SetName(key.Name)
+				return nil
+			}},
+			n:    prefixed,
+			want: want{nn: types.NamespacedName{Namespace: "cool-ns", Name: "team-a-cool-workload"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{client: tc.c, packageName: tc.n}
+			got, err := r.packageNameOf(context.Background(), "cool-ns", ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.packageNameOf(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nn, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.packageNameOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageControllers(t *testing.T) {
+	controller := true
+
+	cases := map[string]struct {
+		reason string
+		o      runtime.Object
+		want   []string
+	}{
+		"NotAnObject": {
+			reason: "Objects without metadata should not be indexed.",
+			o:      &workloadv1alpha1.KubernetesApplicationList{},
+		},
+		"NoController": {
+			reason: "Packages without a controller should not be indexed.",
+			o: &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "cool-workload"},
+			}}},
+		},
+		"Controlled": {
+			reason: "Packages should be indexed by the group, kind, and name of their controller.",
+			o: &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "cool-workload", Controller: &controller},
+			}}},
+			want: []string{"ContainerizedWorkload.core.oam.dev/cool-workload"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PackageControllers(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nPackageControllers(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetTranslation(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := oamv1alpha2.WorkloadReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "cool-workload"}
+
+	pkg := func(name, shard string) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Namespace: "cool-ns", Name: name}}
+		if shard != "" {
+			a.SetAnnotations(map[string]string{workload.AnnotationShard: shard})
+		}
+		return a
+	}
+	list := func(items ...workloadv1alpha1.KubernetesApplication) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "cool-ns" || lo.FieldSelector.String() != IndexPackageControllers+"=ContainerizedWorkload.core.oam.dev/cool-workload" {
+				return errors.New("unexpected list options")
+			}
+			obj.(*workloadv1alpha1.KubernetesApplicationList).Items = items
+			return nil
+		}
+	}
+	get := func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		o := obj.(metav1.Object)
+		o.SetNamespace(key.Namespace)
+		o.SetName(key.Name)
+		return nil
+	}
+
+	type want struct {
+		nn  types.NamespacedName
+		o   Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		index  bool
+		want   want
+	}{
+		"NoIndex": {
+			reason: "Packages should be found by name if the Reconciler has no package index.",
+			c:      &test.MockClient{MockGet: get},
+			want: want{
+				nn: types.NamespacedName{Namespace: "cool-ns", Name: "cool-workload"},
+				o:  pkg("cool-workload", ""),
+			},
+		},
+		"ListError": {
+			reason: "Errors listing packages should be returned.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			index:  true,
+			want:   want{err: errBoom},
+		},
+		"Indexed": {
+			reason: "The first shard of the package the workload controls should be returned, regardless of its name.",
+			c:      &test.MockClient{MockList: list(*pkg("team-a-cool-1", "1"), *pkg("team-a-cool", "0"))},
+			index:  true,
+			want: want{
+				nn: types.NamespacedName{Namespace: "cool-ns", Name: "team-a-cool"},
+				o:  pkg("team-a-cool", "0"),
+			},
+		},
+		"NotIndexed": {
+			reason: "Packages that are not in the index should be found by name.",
+			c:      &test.MockClient{MockList: list(), MockGet: get},
+			index:  true,
+			want: want{
+				nn: types.NamespacedName{Namespace: "cool-ns", Name: "cool-workload"},
+				o:  pkg("cool-workload", ""),
+			},
+		},
+		"GetError": {
+			reason: "Errors getting a package by name should be returned.",
+			c:      &test.MockClient{MockList: list(), MockGet: test.NewMockGetFn(errBoom)},
+			index:  true,
+			want: want{
+				nn:  types.NamespacedName{Namespace: "cool-ns", Name: "cool-workload"},
+				o:   &workloadv1alpha1.KubernetesApplication{},
+				err: errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				client:             tc.c,
+				packageIndex:       tc.index,
+				newTranslation:     func() Object { return &workloadv1alpha1.KubernetesApplication{} },
+				newTranslationList: func() runtime.Object { return &workloadv1alpha1.KubernetesApplicationList{} },
+			}
+			nn, o, err := r.getTranslation(context.Background(), "cool-ns", ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.getTranslation(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nn, nn); diff != "" {
+				t.Errorf("\nReason: %s\nr.getTranslation(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\nReason: %s\nr.getTranslation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+const (
+	errRunStage    = "cannot run modifier stage"
+	errRevertStage = "cannot revert modifier stage"
+)
+
+var (
+	_ Modifier = Pipeline{}
+	_ Reverter = Pipeline{}
+)
+
+// Reasons a stage of a modifier pipeline did or did not succeed.
+const (
+	ReasonStageSucceeded v1alpha1.ConditionReason = "StageSucceeded"
+	ReasonStageFailed    v1alpha1.ConditionReason = "StageFailed"
+	ReasonStageSkipped   v1alpha1.ConditionReason = "StageSkipped"
+)
+
+// StageConditionType returns the type of the condition that indicates whether
+// the named stage of a modifier pipeline succeeded.
+func StageConditionType(name string) v1alpha1.ConditionType {
+	return v1alpha1.ConditionType("Stage" + name)
+}
+
+// A Stage is a named step of a modifier Pipeline.
+type Stage struct {
+	// Name of the stage. Unnamed stages are named by their one-based position
+	// in the pipeline.
+	Name string
+
+	// Modifier run by the stage.
+	Modifier Modifier
+}
+
+// A Pipeline is an ordered series of modifier stages. A Pipeline allows a
+// complex trait to be composed from reusable modifiers.
+type Pipeline []Stage
+
+// Modify runs each stage of the pipeline in order, stopping at the first stage
+// that fails. An empty Pipeline does not modify the object.
+func (p Pipeline) Modify(ctx context.Context, obj runtime.Object, t Trait) error {
+	_, err := p.Run(ctx, obj, t)
+	return err
+}
+
+// Run each stage of the pipeline in order, stopping at the first stage that
+// fails. Run returns the index of the failed stage, or the length of the
+// pipeline if all stages succeeded. Errors returned by a pipeline of more than
+// one stage are wrapped with the name of the failed stage.
+func (p Pipeline) Run(ctx context.Context, obj runtime.Object, t Trait) (int, error) {
+	for i := range p {
+		err := p[i].Modifier.Modify(ctx, obj, t)
+		if err == nil {
+			continue
+		}
+		if len(p) == 1 {
+			return i, err
+		}
+		return i, errors.Wrapf(err, "%s %s", errRunStage, p.name(i))
+	}
+	return len(p), nil
+}
+
+// Revertible returns true if any stage of the pipeline can revert the
+// modifications it made.
+func (p Pipeline) Revertible() bool {
+	for i := range p {
+		if _, ok := p[i].Modifier.(Reverter); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Revert the modifications made by each stage of the pipeline that can revert
+// them, in the reverse order of the stages. Modifications made by stages that
+// cannot be reverted are left in place.
+func (p Pipeline) Revert(ctx context.Context, obj runtime.Object, t Trait) error {
+	for i := len(p) - 1; i >= 0; i-- {
+		rv, ok := p[i].Modifier.(Reverter)
+		if !ok {
+			continue
+		}
+		if err := rv.Revert(ctx, obj, t); err != nil {
+			if len(p) == 1 {
+				return err
+			}
+			return errors.Wrapf(err, "%s %s", errRevertStage, p.name(i))
+		}
+	}
+	return nil
+}
+
+// Conditions returns a condition for each stage of the pipeline, given the
+// index of the stage that failed and its error as returned by Run. Stages
+// before the failed stage succeeded, while those after it were skipped. No
+// conditions are returned for a pipeline consisting of a single unnamed stage.
+func (p Pipeline) Conditions(failed int, err error) []v1alpha1.Condition {
+	if len(p) == 0 || (len(p) == 1 && p[0].Name == "") {
+		return nil
+	}
+
+	c := make([]v1alpha1.Condition, len(p))
+	for i := range p {
+		c[i] = v1alpha1.Condition{
+			Type:               StageConditionType(p.name(i)),
+			LastTransitionTime: metav1.Now(),
+		}
+		switch {
+		case i < failed:
+			c[i].Status = corev1.ConditionTrue
+			c[i].Reason = ReasonStageSucceeded
+		case i == failed && err != nil:
+			c[i].Status = corev1.ConditionFalse
+			c[i].Reason = ReasonStageFailed
+			c[i].Message = err.Error()
+		default:
+			c[i].Status = corev1.ConditionUnknown
+			c[i].Reason = ReasonStageSkipped
+		}
+	}
+	return c
+}
+
+func (p Pipeline) name(i int) string {
+	if p[i].Name != "" {
+		return p[i].Name
+	}
+	return strconv.Itoa(i + 1)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPipeline(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var ran []string
+	stage := func(name string, err error) Stage {
+		return Stage{Name: name, Modifier: ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+			ran = append(ran, name)
+			return err
+		})}
+	}
+
+	type want struct {
+		failed     int
+		err        error
+		ran        []string
+		conditions []v1alpha1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      Pipeline
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty pipeline should succeed without running anything.",
+			p:      Pipeline{},
+			want:   want{failed: 0},
+		},
+		"SingleUnnamedStage": {
+			reason: "Errors from a single unnamed stage should be returned unwrapped, without conditions.",
+			p:      Pipeline{stage("", errBoom)},
+			want: want{
+				failed: 0,
+				err:    errBoom,
+				ran:    []string{""},
+			},
+		},
+		"AllSucceeded": {
+			reason: "All stages should run in order, and each should be reported as succeeded.",
+			p:      Pipeline{stage("a", nil), stage("b", nil)},
+			want: want{
+				failed: 2,
+				ran:    []string{"a", "b"},
+				conditions: []v1alpha1.Condition{
+					{Type: StageConditionType("a"), Status: corev1.ConditionTrue, Reason: ReasonStageSucceeded},
+					{Type: StageConditionType("b"), Status: corev1.ConditionTrue, Reason: ReasonStageSucceeded},
+				},
+			},
+		},
+		"ShortCircuit": {
+			reason: "Stages after a failed stage should be skipped, and the failed stage should be recorded.",
+			p:      Pipeline{stage("a", nil), stage("b", errBoom), stage("c", nil)},
+			want: want{
+				failed: 1,
+				err:    errors.Wrapf(errBoom, "%s %s", errRunStage, "b"),
+				ran:    []string{"a", "b"},
+				conditions: []v1alpha1.Condition{
+					{Type: StageConditionType("a"), Status: corev1.ConditionTrue, Reason: ReasonStageSucceeded},
+					{Type: StageConditionType("b"), Status: corev1.ConditionFalse, Reason: ReasonStageFailed, Message: errors.Wrapf(errBoom, "%s %s", errRunStage, "b").Error()},
+					{Type: StageConditionType("c"), Status: corev1.ConditionUnknown, Reason: ReasonStageSkipped},
+				},
+			},
+		},
+		"UnnamedStages": {
+			reason: "Unnamed stages should be named by their position in the pipeline.",
+			p:      Pipeline{stage("", nil), stage("", errBoom)},
+			want: want{
+				failed: 1,
+				err:    errors.Wrapf(errBoom, "%s %s", errRunStage, "2"),
+				ran:    []string{"", ""},
+				conditions: []v1alpha1.Condition{
+					{Type: StageConditionType("1"), Status: corev1.ConditionTrue, Reason: ReasonStageSucceeded},
+					{Type: StageConditionType("2"), Status: corev1.ConditionFalse, Reason: ReasonStageFailed, Message: errors.Wrapf(errBoom, "%s %s", errRunStage, "2").Error()},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ran = nil
+			failed, err := tc.p.Run(context.Background(), nil, nil)
+
+			if diff := cmp.Diff(tc.want.failed, failed); diff != "" {
+				t.Errorf("\nReason: %s\np.Run(...): -want failed, +got failed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\np.Run(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ran, ran); diff != "" {
+				t.Errorf("\nReason: %s\np.Run(...): -want ran, +got ran:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, tc.p.Conditions(failed, err)); diff != "" {
+				t.Errorf("\nReason: %s\np.Conditions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineRevert(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var reverted []string
+	nop := ModifyFn(NoopModifier)
+	revertible := func(name string, err error) Stage {
+		return Stage{Name: name, Modifier: NewRevertibleModifier(nop, ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+			reverted = append(reverted, name)
+			return err
+		}))}
+	}
+
+	type want struct {
+		revertible bool
+		err        error
+		reverted   []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      Pipeline
+		want   want
+	}{
+		"NotRevertible": {
+			reason: "A pipeline with no revertible stages should not be revertible, and should revert nothing.",
+			p:      Pipeline{{Name: "a", Modifier: nop}},
+			want:   want{revertible: false},
+		},
+		"SingleStageError": {
+			reason: "Errors from a single stage should be returned unwrapped.",
+			p:      Pipeline{revertible("", errBoom)},
+			want: want{
+				revertible: true,
+				err:        errBoom,
+				reverted:   []string{""},
+			},
+		},
+		"StageError": {
+			reason: "Reverting should stop at the first stage that fails, and its error should be wrapped with its name.",
+			p:      Pipeline{revertible("a", nil), revertible("b", errBoom)},
+			want: want{
+				revertible: true,
+				err:        errors.Wrapf(errBoom, "%s %s", errRevertStage, "b"),
+				reverted:   []string{"b"},
+			},
+		},
+		"Success": {
+			reason: "Revertible stages should be reverted in reverse order, skipping those that cannot be reverted.",
+			p:      Pipeline{revertible("a", nil), {Name: "b", Modifier: nop}, revertible("c", nil)},
+			want: want{
+				revertible: true,
+				reverted:   []string{"c", "a"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reverted = nil
+
+			if diff := cmp.Diff(tc.want.revertible, tc.p.Revertible()); diff != "" {
+				t.Errorf("\nReason: %s\np.Revertible(): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			err := tc.p.Revert(context.Background(), nil, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\np.Revert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reverted, reverted); diff != "" {
+				t.Errorf("\nReason: %s\np.Revert(...): -want reverted, +got reverted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
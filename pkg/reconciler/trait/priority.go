@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errParsePriority = "cannot parse trait priority"
+	errGetLock       = "cannot get trait priority lock"
+	errSetLock       = "cannot set trait priority lock"
+)
+
+// AnnotationPriority may be set on a trait to an integer priority, for example
+// in the trait templates of an ApplicationConfiguration. When traits modify
+// the same fields of a package, the modifications of the trait with the
+// highest priority win. Traits have a priority of zero by default.
+const AnnotationPriority = "trait.oam.crossplane.io/priority"
+
+// AnnotationPriorityLock is set on a package to the priorities of the traits
+// that have modified it, keyed by lower case group kind and name. A trait
+// releases the traits of higher priority from the lock when it modifies the
+// package, so that they modify it again after it.
+const AnnotationPriorityLock = "trait.oam.crossplane.io/priority-lock"
+
+// Priority returns the priority of the supplied trait.
+func Priority(t metav1.Object) (int, error) {
+	v, ok := t.GetAnnotations()[AnnotationPriority]
+	if !ok {
+		return 0, nil
+	}
+	p, err := strconv.Atoi(v)
+	return p, errors.Wrap(err, errParsePriority)
+}
+
+func getLock(o metav1.Object) (map[string]int, error) {
+	raw, ok := o.GetAnnotations()[AnnotationPriorityLock]
+	if !ok {
+		return nil, nil
+	}
+	l := map[string]int{}
+	return l, errors.Wrap(json.Unmarshal([]byte(raw), &l), errGetLock)
+}
+
+func setLock(o metav1.Object, l map[string]int) error {
+	raw, err := json.Marshal(l)
+	if err != nil {
+		return errors.Wrap(err, errSetLock)
+	}
+	meta.AddAnnotations(o, map[string]string{AnnotationPriorityLock: string(raw)})
+	return nil
+}
+
+// deferred returns true if the supplied trait of the supplied priority should
+// not modify the supplied translation, because it has already modified it at
+// its current generation and a trait of higher priority has modified it since.
+func deferred(translation Object, t Trait, priority int) (bool, error) {
+	l, err := getLock(translation)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := l[traitKey(t)]; !ok {
+		return false, nil
+	}
+	ri, err := workload.GetRenderInputs(translation)
+	if err != nil {
+		return false, err
+	}
+	if ri == nil || ri.TraitGenerations[traitKey(t)] != t.GetGeneration() {
+		return false, nil
+	}
+	for k, p := range l {
+		if k != traitKey(t) && p > priority {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lock records that the supplied trait of the supplied priority modified the
+// supplied translation, and releases any traits of higher priority so that
+// they modify it again. Translations that are not locked are locked only by
+// traits of non-zero priority.
+func lock(translation Object, t Trait, priority int) error {
+	l, err := getLock(translation)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		if priority == 0 {
+			return nil
+		}
+		l = map[string]int{}
+	}
+	for k, p := range l {
+		if p > priority {
+			delete(l, k)
+		}
+	}
+	l[traitKey(t)] = priority
+	return setLock(translation, l)
+}
+
+// unlock removes the supplied trait from the lock of the supplied translation.
+func unlock(translation Object, t Trait) error {
+	l, err := getLock(translation)
+	if err != nil || l == nil {
+		return err
+	}
+	delete(l, traitKey(t))
+	return setLock(translation, l)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func TestPriority(t *testing.T) {
+	_, errAtoi := strconv.Atoi("high")
+
+	type want struct {
+		p   int
+		err error
+	}
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        want
+	}{
+		"Default": {
+			reason: "A trait without a priority annotation should have priority zero.",
+			want:   want{p: 0},
+		},
+		"Annotated": {
+			reason: "A trait's priority should be read from its annotation.",
+			annotations: map[string]string{
+				AnnotationPriority: "-5",
+			},
+			want: want{p: -5},
+		},
+		"Invalid": {
+			reason: "A priority that is not an integer should return an error.",
+			annotations: map[string]string{
+				AnnotationPriority: "high",
+			},
+			want: want{err: errors.Wrap(errAtoi, errParsePriority)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Priority(&traitfake.Trait{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPriority(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, got); diff != "" {
+				t.Errorf("\nReason: %s\nPriority(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeferred(t *testing.T) {
+	tr := &traitfake.Trait{ObjectMeta: metav1.ObjectMeta{Name: "cool", Generation: 2}}
+
+	translation := func(l map[string]int, generation int64) Object {
+		o := &traitfake.Object{}
+		if l != nil {
+			if err := setLock(o, l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := workload.SetRenderInputs(o, &workload.RenderInputs{TraitGenerations: map[string]int64{traitKey(tr): generation}}); err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	cases := map[string]struct {
+		reason      string
+		translation Object
+		priority    int
+		want        bool
+	}{
+		"NotLocked": {
+			reason:      "A trait should not defer modifying a translation that is not locked.",
+			translation: translation(nil, 2),
+		},
+		"NotInLock": {
+			reason:      "A trait should not defer modifying a translation it has not modified since it was released.",
+			translation: translation(map[string]int{"/other": 10}, 2),
+		},
+		"NewGeneration": {
+			reason:      "A trait should not defer modifying a translation it modified at a previous generation.",
+			translation: translation(map[string]int{traitKey(tr): 0, "/other": 10}, 1),
+		},
+		"NoHigherPriority": {
+			reason:      "A trait should not defer to traits of lower priority.",
+			translation: translation(map[string]int{traitKey(tr): 5, "/other": 1}, 2),
+			priority:    5,
+		},
+		"HigherPriority": {
+			reason:      "A trait should defer to traits of higher priority that modified the translation since it did.",
+			translation: translation(map[string]int{traitKey(tr): 0, "/other": 10}, 2),
+			want:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := deferred(tc.translation, tr, tc.priority)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\ndeferred(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLock(t *testing.T) {
+	tr := &traitfake.Trait{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+
+	translation := func(l map[string]int) Object {
+		o := &traitfake.Object{}
+		if l != nil {
+			if err := setLock(o, l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return o
+	}
+
+	cases := map[string]struct {
+		reason      string
+		translation Object
+		priority    int
+		unlock      bool
+		want        map[string]int
+	}{
+		"ZeroPriorityNotLocked": {
+			reason:      "A trait of zero priority should not lock a translation that is not locked.",
+			translation: translation(nil),
+		},
+		"PriorityNotLocked": {
+			reason:      "A trait of non-zero priority should lock a translation that is not locked.",
+			translation: translation(nil),
+			priority:    1,
+			want:        map[string]int{traitKey(tr): 1},
+		},
+		"ReleaseHigherPriority": {
+			reason:      "A trait should release traits of higher priority from the lock.",
+			translation: translation(map[string]int{"/low": -1, "/high": 10}),
+			want:        map[string]int{"/low": -1, traitKey(tr): 0},
+		},
+		"Unlock": {
+			reason:      "Unlocking should remove the trait from the lock.",
+			translation: translation(map[string]int{"/high": 10, traitKey(tr): 0}),
+			unlock:      true,
+			want:        map[string]int{"/high": 10},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fn := func() error { return lock(tc.translation, tr, tc.priority) }
+			if tc.unlock {
+				fn = func() error { return unlock(tc.translation, tr) }
+			}
+			if err := fn(); err != nil {
+				t.Fatal(err)
+			}
+			got, err := getLock(tc.translation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nlock(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,14 +18,21 @@ package trait
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -33,8 +40,17 @@ import (
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/changelog"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/message"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trace"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 const (
@@ -43,25 +59,60 @@ const (
 	longWait         = 1 * time.Minute
 )
 
+// Names of the spans recorded by the Reconciler.
+const (
+	spanReconcile      = "trait.Reconcile"
+	spanGetTrait       = "trait.GetTrait"
+	spanGetTranslation = "trait.GetTranslation"
+	spanModify         = "trait.Modify"
+	spanUpdate         = "trait.Update"
+)
+
 // Reconcile error strings.
 const (
+	errClearReconcileRequest  = "cannot clear reconcile request"
+	errRecordChangelog        = "cannot record trait changelog"
 	errGetTrait               = "cannot get trait"
 	errUpdateTraitStatus      = "cannot update trait status"
 	errTraitModify            = "cannot apply trait modification"
 	errGetTranslation         = "cannot get translation for workload reference in trait"
 	errApplyTraitModification = "cannot apply trait modification to workload translation"
+	errClaimFields            = "cannot claim fields of workload translation"
+	errAddFinalizer           = "cannot add finalizer to trait"
+	errRemoveFinalizer        = "cannot remove finalizer from trait"
+	errRevertModification     = "cannot revert trait modification"
+	errApplyTraitRevert       = "cannot apply reverted workload translation"
+)
+
+// Constructor error strings.
+const (
+	errTraitKindNotRegistered       = "trait kind is not registered with the manager's scheme"
+	errTranslationKindNotRegistered = "translation kind is not registered with the manager's scheme"
+	errTraitKindNotTrait            = "trait kind does not implement Trait"
+	errTranslationKindNotObject     = "translation kind does not implement Object"
 )
 
 // Reconcile event reasons.
 const (
 	reasonTraitWait   = "WaitingForWorkloadTranslation"
 	reasonTraitModify = "PackageModified"
+	reasonSpecChanged = "SpecChanged"
+	reasonPaused      = "ReconciliationPaused"
+	reasonResumed     = "ReconciliationResumed"
+	reasonTraitRevert = "PackageReverted"
 
-	reasonCannotGetTranslation    = "CannotGetReferencedWorkloadTranslation"
-	reasonCannotModifyTranslation = "CannotModifyTranslation"
-	reasonCannotApplyModification = "CannotApplyModification"
+	reasonCannotGetTranslation     = "CannotGetReferencedWorkloadTranslation"
+	reasonCannotModifyTranslation  = "CannotModifyTranslation"
+	reasonCannotApplyModification  = "CannotApplyModification"
+	reasonPackagingModeMismatch    = "PackagingModeMismatch"
+	reasonNotApplicable            = "TraitNotApplicable"
+	reasonCannotRevertModification = "CannotRevertModification"
 )
 
+// Finalizer is added to traits whose modifiers can revert the modifications
+// they made, so that they may be reverted before the trait is deleted.
+const Finalizer = "trait.oam.crossplane.io/revert"
+
 // A ReconcilerOption configures a Reconciler.
 type ReconcilerOption func(*Reconciler)
 
@@ -79,10 +130,47 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
-// WithModifier specifies how the Reconciler should modify the workload translation.
+// WithModifier specifies how the Reconciler should modify the workload
+// translation. WithModifier may be supplied multiple times; modifiers are run
+// in the order they are supplied.
 func WithModifier(m Modifier) ReconcilerOption {
 	return func(r *Reconciler) {
-		r.trait = m
+		r.stages = append(r.stages, Stage{Modifier: m})
+	}
+}
+
+// WithModifierStage adds a named stage to the modifiers the Reconciler runs to
+// modify the workload translation. Whether each named stage succeeded is
+// recorded as a condition of the trait.
+func WithModifierStage(name string, m Modifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.stages = append(r.stages, Stage{Name: name, Modifier: m})
+	}
+}
+
+// WithUnstructured specifies that the Reconciler should reconcile its trait
+// kind as an Unstructured trait. This allows kinds that are not registered
+// with the manager's scheme to be reconciled.
+func WithUnstructured() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = workload.NewUnstructuredClient(r.client)
+		r.newTrait = func() Trait { return NewUnstructured(schema.GroupVersionKind(r.kind)) }
+		r.unstructured = true
+	}
+}
+
+// WithUnstructuredPackage specifies that the Reconciler should read and modify
+// its translation kind as Unstructured. This allows packages of kinds that are
+// not registered with the manager's scheme to be modified. Use
+// NewTemplateAccessor to modify their resource templates.
+func WithUnstructuredPackage() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.newTranslation = func() Object {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(schema.GroupVersionKind(r.translationKind))
+			return u
+		}
+		r.unstructuredPackage = true
 	}
 }
 
@@ -94,17 +182,174 @@ func WithApplicator(a resource.Applicator) ReconcilerOption {
 	}
 }
 
+// WithOwner specifies the name of the controller that owns the fields of the
+// workload translation the Reconciler modifies.
+func WithOwner(owner string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.owner = owner
+	}
+}
+
+// WithChangelog specifies that the Reconciler should record a changelog of the
+// changes users make to each trait's spec.
+func WithChangelog() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.changelog = true
+	}
+}
+
+// WithSelector specifies which traits the Reconciler should reconcile. Traits
+// whose labels do not match the supplied selector are ignored, so that they
+// may be reconciled by another instance of the addon. A nil selector selects
+// every trait.
+func WithSelector(s labels.Selector) ReconcilerOption {
+	return func(r *Reconciler) {
+		if s == nil {
+			s = labels.Everything()
+		}
+		r.selector = s
+	}
+}
+
+// WithMessageCatalog specifies how the Reconciler should replace the reasons
+// and messages of the events and conditions it emits.
+func WithMessageCatalog(c message.Catalog) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.messages = c
+	}
+}
+
+// WithObserver specifies how the Reconciler should observe the remote objects
+// of the workload translations a trait modifies.
+func WithObserver(o Observer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.observer = o
+	}
+}
+
+// WithObservationHandler specifies how the Reconciler should reflect the
+// observed remote objects a trait modifies in the trait's status.
+func WithObservationHandler(h ObservationHandler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.observed = h
+	}
+}
+
+// WithScheduler specifies when the modifications a trait makes next change.
+// Traits are reconciled at that time if it is sooner than usual.
+func WithScheduler(s Scheduler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.schedule = s
+	}
+}
+
+// WithApplicabilityChecker specifies how the Reconciler should determine
+// whether a trait may modify the workloads it references. Traits are not
+// permitted to modify the packages of workloads they do not apply to.
+func WithApplicabilityChecker(c ApplicabilityChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applies = c
+	}
+}
+
+// WithDefinitionHasher specifies how the Reconciler should hash the
+// TraitDefinition of its trait kind, which is recorded in the render inputs of
+// each translation a trait modifies.
+func WithDefinitionHasher(h workload.DefinitionHasher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.definition = h
+	}
+}
+
+// WithPackageNamer specifies how the Reconciler should determine the name and
+// namespace of the package of each workload a trait references. It must match
+// the PackageNamer of the workload's reconciler.
+func WithPackageNamer(n workload.PackageNamer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.packageName = n
+	}
+}
+
+// WithPackageIndex specifies that the Reconciler should find the package of
+// each workload a trait references in the IndexPackageControllers field index,
+// rather than by its name. The index must have been added for the
+// Reconciler's translation kind, which must not be Unstructured.
+func WithPackageIndex() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.packageIndex = true
+	}
+}
+
+// WithBackoff specifies how long the Reconciler should wait before reconciling
+// a trait that failed to reconcile. The wait starts at the supplied base and
+// doubles with each consecutive failure, up to the supplied maximum.
+func WithBackoff(base, max time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.backoff = newBackoff(base, max)
+	}
+}
+
+// WithDegradedAfter specifies the number of consecutive failed reconciles
+// after which a trait is marked as degraded.
+func WithDegradedAfter(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.degradedAfter = n
+	}
+}
+
+// WithConflictBackoff specifies how often, and how quickly, the Reconciler
+// should read and modify a translation again when applying its modification
+// conflicts with a concurrent update, for example by the workload reconciler.
+// The trait is requeued once the supplied backoff is exhausted.
+func WithConflictBackoff(b wait.Backoff) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.conflictBackoff = b
+	}
+}
+
+// WithTracer specifies how the Reconciler should record spans of the phases
+// of each reconcile. A nil Tracer records no spans.
+func WithTracer(t trace.Tracer) ReconcilerOption {
+	return func(r *Reconciler) {
+		if t == nil {
+			t = trace.NopTracer{}
+		}
+		r.tracer = t
+	}
+}
+
 // A Reconciler reconciles OAM traits by modifying the object that a workload
 // has been translated into.
 type Reconciler struct {
-	client         client.Client
-	newTrait       func() Trait
-	newTranslation func() Object
-	trait          Modifier
-	applicator     resource.Applicator
+	client              client.Client
+	kind                Kind
+	newTrait            func() Trait
+	newTranslation      func() Object
+	newTranslationList  func() runtime.Object
+	translationKind     Kind
+	unstructured        bool
+	unstructuredPackage bool
+	packageKind         string
+	packageName         workload.PackageNamer
+	packageIndex        bool
+	stages              Pipeline
+	applicator          resource.Applicator
+	observer            Observer
+	observed            ObservationHandler
+	schedule            Scheduler
+	applies             ApplicabilityChecker
+	definition          workload.DefinitionHasher
+	owner               string
+	changelog           bool
+	backoff             *backoff
+	degradedAfter       int
+	conflictBackoff     wait.Backoff
+	tracer              trace.Tracer
+	selector            labels.Selector
 
-	log    logging.Logger
-	record event.Recorder
+	log      logging.Logger
+	record   event.Recorder
+	messages message.Catalog
 }
 
 // Kind is an OAM trait kind.
@@ -131,7 +376,9 @@ type Object interface {
 }
 
 // NewReconciler returns a Reconciler that reconciles OAM traits by fetching
-// their referenced workload's translation and applying modifications.
+// their referenced workload's translation and applying modifications. It
+// panics if the trait or translation kind is not registered with the supplied
+// manager's scheme, unless that kind is reconciled as Unstructured.
 func NewReconciler(m ctrl.Manager, trait Kind, trans Kind, o ...ReconcilerOption) *Reconciler {
 	nt := func() Trait {
 		return resource.MustCreateObject(schema.GroupVersionKind(trait), m.GetScheme()).(Trait)
@@ -141,12 +388,32 @@ func NewReconciler(m ctrl.Manager, trait Kind, trans Kind, o ...ReconcilerOption
 		return resource.MustCreateObject(schema.GroupVersionKind(trans), m.GetScheme()).(Object)
 	}
 
+	nl := func() runtime.Object {
+		gvk := schema.GroupVersionKind(trans)
+		gvk.Kind += "List"
+		return resource.MustCreateObject(gvk, m.GetScheme())
+	}
+
 	r := &Reconciler{
-		client:         m.GetClient(),
-		newTrait:       nt,
-		newTranslation: nr,
-		trait:          ModifyFn(NoopModifier),
-		applicator:     resource.ApplyFn(resource.Apply),
+		client:             m.GetClient(),
+		kind:               trait,
+		newTrait:           nt,
+		newTranslation:     nr,
+		newTranslationList: nl,
+		translationKind:    trans,
+		packageKind:        strings.ToLower(schema.GroupVersionKind(trans).GroupKind().String()),
+		applicator:         resource.ApplyFn(resource.Apply),
+		observer:           ObserveFn(NopObserve),
+		observed:           RecordObservations,
+		schedule:           Unscheduled,
+		applies:            ApplicabilityCheckFn(AppliesToAll),
+		definition:         workload.DefinitionHasherFn(workload.NopHashDefinition),
+		owner:              "oam/" + strings.ToLower(schema.GroupVersionKind(trait).GroupKind().String()),
+		backoff:            newBackoff(shortWait, DefaultMaxBackoff),
+		degradedAfter:      DefaultDegradedAfter,
+		conflictBackoff:    retry.DefaultRetry,
+		tracer:             trace.NopTracer{},
+		selector:           labels.Everything(),
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
@@ -156,67 +423,602 @@ func NewReconciler(m ctrl.Manager, trait Kind, trans Kind, o ...ReconcilerOption
 		ro(r)
 	}
 
+	// A kind that is missing from the scheme would otherwise only be noticed
+	// when a trait is first reconciled, as a confusing error deep inside
+	// Reconcile. Such a controller can never work, so we fail as it is set up.
+	if err := r.checkKinds(m.GetScheme(), trans); err != nil {
+		panic(err.Error())
+	}
+
 	return r
 }
 
+// checkKinds returns an error if the trait or translation kind of the
+// Reconciler cannot be created using the supplied scheme, or if objects of
+// either kind are not of the type the Reconciler expects. Unstructured traits
+// and packages need not be registered.
+func (r *Reconciler) checkKinds(s *runtime.Scheme, trans Kind) error {
+	if !r.unstructured {
+		gvk := schema.GroupVersionKind(r.kind)
+		o, err := s.New(gvk)
+		if err != nil {
+			return errors.Errorf("%s: %s", errTraitKindNotRegistered, gvk)
+		}
+		if _, ok := o.(Trait); !ok {
+			return errors.Errorf("%s: %s", errTraitKindNotTrait, gvk)
+		}
+	}
+
+	if r.unstructuredPackage {
+		return nil
+	}
+
+	gvk := schema.GroupVersionKind(trans)
+	o, err := s.New(gvk)
+	if err != nil {
+		return errors.Errorf("%s: %s", errTranslationKindNotRegistered, gvk)
+	}
+	if _, ok := o.(Object); !ok {
+		return errors.Errorf("%s: %s", errTranslationKindNotObject, gvk)
+	}
+	return nil
+}
+
 // Reconcile an OAM trait type by modifying its referenced workload's
 // KubernetesApplication.
 func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
-	log := r.log.WithValues("request", req)
+	log := trace.Logger(r.log).WithValues("request", req)
 	log.Debug("Reconciling")
 
 	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
 	defer cancel()
 
+	ctx, span := r.tracer.StartSpan(ctx, spanReconcile)
+	span.SetAttributes("kind", schema.GroupVersionKind(r.kind).String(), "request", req.String())
+	defer span.End(nil)
+
 	trait := r.newTrait()
-	if err := r.client.Get(ctx, req.NamespacedName, trait); err != nil {
+	sctx, s := r.tracer.StartSpan(ctx, spanGetTrait)
+	err := r.client.Get(sctx, req.NamespacedName, trait)
+	s.End(resource.IgnoreNotFound(err))
+	if err != nil {
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetTrait)
 	}
 
 	log = log.WithValues("uid", trait.GetUID(), "version", trait.GetResourceVersion())
 
-	translation := r.newTranslation()
+	// Traits that another instance of the addon is responsible for are left
+	// untouched.
+	if !r.selector.Matches(labels.Set(trait.GetLabels())) {
+		log.Debug("Trait is not selected")
+		return reconcile.Result{}, nil
+	}
+
+	// A reconcile that was explicitly requested is happening now, so we clear
+	// the request before we do anything else.
+	if annotations.ReconcileRequested(trait) {
+		log.Debug("Reconcile requested", "requested-at", trait.GetAnnotations()[annotations.ReconcileNow])
+		annotations.ClearReconcileRequest(trait)
+		if err := r.client.Update(ctx, trait); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errClearReconcileRequest)
+		}
+	}
+
+	// A trait whose modifications can be reverted reverts them before it is
+	// deleted, rather than leaving them in its workload's translation.
+	if meta.WasDeleted(trait) {
+		if !meta.FinalizerExists(trait, Finalizer) {
+			return reconcile.Result{}, nil
+		}
+		if err := r.revert(ctx, log, trait); err != nil {
+			log.Debug("Cannot revert modifications to workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotRevertModification, err)))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+		r.record.Event(trait, r.messages.Event(event.Normal(reasonTraitRevert, "Successfully reverted workload translation")))
+		meta.RemoveFinalizer(trait, Finalizer)
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, trait), errRemoveFinalizer)
+	}
+	if r.stages.Revertible() && !meta.FinalizerExists(trait, Finalizer) {
+		meta.AddFinalizer(trait, Finalizer)
+		if err := r.client.Update(ctx, trait); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	// A paused trait does not modify its workload's translation, which is left
+	// as it was last modified until the trait is resumed.
+	if annotations.IsPaused(trait) {
+		log.Debug("Reconciliation is paused")
+		if trait.GetCondition(workload.TypePaused).Status != corev1.ConditionTrue {
+			r.record.Event(trait, r.messages.Event(event.Normal(reasonPaused, "Reconciliation paused")))
+		}
+		trait.SetConditions(r.messages.Conditions(workload.Paused())...)
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+	}
+	// The changelog is recorded in an annotation, so it must be recorded
+	// before the status of the trait is modified.
+	if r.changelog {
+		if err := r.recordChangelog(ctx, trait); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errRecordChangelog)
+		}
+	}
+
+	if trait.GetCondition(workload.TypePaused).Status == corev1.ConditionTrue {
+		r.record.Event(trait, r.messages.Event(event.Normal(reasonResumed, "Reconciliation resumed")))
+		trait.SetConditions(r.messages.Conditions(workload.Resumed())...)
+	}
+
+	priority, err := Priority(trait)
+	if err != nil {
+		log.Debug("Cannot modify workload translation", "error", err)
+		r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotModifyTranslation, err)))
+		trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(err))...)
+		return r.failed(ctx, log, req.NamespacedName, trait)
+	}
+
+	refs := workloadReferences(trait)
+	targets := make([]target, 0, len(refs))
+	for _, ref := range refs {
+		// A trait that modified a kind of workload it does not apply to would
+		// produce a nonsensical patch, so we refuse to modify its package.
+		if appliesTo, ok := r.applies.Applies(ctx, schema.GroupVersionKind(r.kind), ref); !ok {
+			c := NotApplicable(ref.Name, ref.Kind, appliesTo)
+			log.Debug("Trait does not apply to referenced workload's kind", "workload", ref.Name, "kind", ref.Kind, "applies-to", appliesTo)
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonNotApplicable, errors.New(c.Message))))
+			r.recovered(req.NamespacedName, trait)
+			trait.SetConditions(r.messages.Conditions(c)...)
+			return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+		}
+
+		// TODO(hasheddan): we make the assumption here that the workload
+		// translation object that we are modifying has the same name as the
+		// workload itself. This would not work if a translation produced
+		// multiple objects of the same kind as they would not be permitted to
+		// have the same name.
+		sctx, s := r.tracer.StartSpan(ctx, spanGetTranslation)
+		s.SetAttributes("workload", ref.Name)
+		nn, translation, err := r.getTranslation(sctx, trait.GetNamespace(), ref)
+		s.End(resource.IgnoreNotFound(err))
+		if kerrors.IsNotFound(err) {
+			// A translation that does not exist because the workload was
+			// packaged as a different kind will never exist, so there is no
+			// point waiting for it.
+			if kinds, ok := r.packagedAs(ctx, trait.GetNamespace(), ref); ok && !contains(kinds, r.packageKind) {
+				c := PackagingModeMismatch(ref.Name, r.packageKind, kinds)
+				log.Debug("Referenced workload is not packaged as the kind this trait modifies", "workload", ref.Name, "packaged-as", kinds)
+				r.record.Event(trait, r.messages.Event(event.Warning(reasonPackagingModeMismatch, errors.New(c.Message))))
+				r.recovered(req.NamespacedName, trait)
+				trait.SetConditions(r.messages.Conditions(c)...)
+				return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+			}
+
+			log.Debug("Waiting for referenced workload's translation", "kind", trait.GetObjectKind().GroupVersionKind().String(), "workload", ref.Name)
+			r.record.Event(trait, r.messages.Event(event.Normal(reasonTraitWait, "Waiting for workload translation to exist")))
+			r.recovered(req.NamespacedName, trait)
+			trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileSuccess())...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+		}
+		if err != nil {
+			log.Debug("Cannot get workload translation", "error", err, "workload", ref.Name)
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotGetTranslation, err)))
+			trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errGetTranslation)))...)
+			return r.failed(ctx, log, req.NamespacedName, trait)
+		}
+
+		log.Debug("Got workload translation", "workload", ref.Name, "translation", trace.Describe(translation), "version", translation.GetResourceVersion())
+
+		// A workload whose package was too large for a single translation
+		// was sharded into several, all of which the trait may modify.
+		shards, err := r.shardsOf(ctx, nn, translation)
+		if err != nil {
+			log.Debug("Cannot get workload translation", "error", err, "workload", ref.Name)
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotGetTranslation, err)))
+			trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errGetTranslation)))...)
+			return r.failed(ctx, log, req.NamespacedName, trait)
+		}
+		targets = append(targets, packageTargets(ref, append([]Object{translation}, shards...))...)
+	}
+
+	// A trait that references multiple workloads modifies all of their
+	// translations before applying any of them, so that a modification that
+	// fails for one workload is applied to none.
+	diffOK := true
+	for i := range targets {
+		t := &targets[i]
+		if err := r.modify(ctx, t, trait, priority, len(targets) > 1); err != nil {
+			log.Debug("Cannot modify workload translation", "error", err, "workload", t.ref.Name)
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotModifyTranslation, err)))
+			setTargetStatuses(trait, targets, failedTargets(targets, t.name, err))
+			if se, ok := err.(*stageError); ok {
+				trait.SetConditions(r.stages.Conditions(se.stage, se.cause)...)
+			}
+			trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(err))...)
+			return r.failed(ctx, log, req.NamespacedName, trait)
+		}
+		if t.deferred {
+			log.Debug("Deferring to traits of higher priority", "workload", t.ref.Name, "priority", priority)
+			continue
+		}
+		if t.skipped != nil {
+			log.Debug("Shard does not contain what the trait modifies", "workload", t.ref.Name, "shard", t.name)
+			continue
+		}
+
+		// Recording which fields were modified is best effort; failing to do
+		// so does not mean the modification was not applied.
+		if t.diffErr != nil {
+			log.Debug("Cannot determine modifications to workload translation", "error", t.diffErr, "workload", t.ref.Name)
+			diffOK = false
+		}
+	}
+
+	// A trait must modify at least one shard of each workload's package.
+	if i, ok := unmodified(targets); ok {
+		t := &targets[i]
+		log.Debug("Cannot modify workload translation", "error", t.skipped, "workload", t.ref.Name)
+		r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotModifyTranslation, t.skipped)))
+		setTargetStatuses(trait, targets, failedTargets(targets, t.name, t.skipped))
+		if se, ok := t.skipped.(*stageError); ok {
+			trait.SetConditions(r.stages.Conditions(se.stage, se.cause)...)
+		}
+		trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(t.skipped))...)
+		return r.failed(ctx, log, req.NamespacedName, trait)
+	}
+
+	for i := range targets {
+		t := &targets[i]
+		if t.deferred || t.skipped != nil {
+			continue
+		}
+
+		// The trait's referenced workload should always be translated in an
+		// object(s) that is controlled by the workload. In the case where an
+		// object(s) already exists in the same namespace and with the same
+		// name before it is created, this wll guard against modifying it.
+		sctx, s := r.tracer.StartSpan(ctx, spanUpdate)
+		s.SetAttributes("workload", t.ref.Name)
+		err := r.apply(sctx, log, t, trait, priority, len(targets) > 1)
+		s.End(err)
+		if err != nil {
+			log.Debug("Cannot apply workload translation", "error", err, "workload", t.ref.Name)
+			r.record.Event(trait, r.messages.Event(event.Warning(reasonCannotApplyModification, err)))
+
+			// Translations that were already modified are restored so that
+			// the trait is applied to all or none of its workloads.
+			r.restore(ctx, log, targets[:i])
+
+			setTargetStatuses(trait, targets, failedTargets(targets, t.name, errors.Wrap(err, errApplyTraitModification)))
+			trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyTraitModification)))...)
+			return r.failed(ctx, log, req.NamespacedName, trait)
+		}
+	}
+
+	// Observing the remote effect of the trait is best effort; failing to do
+	// so does not mean the modification was not applied.
+	observed := []remotev1alpha1.RemoteObservation{}
+	observeOK := true
+	for _, t := range targets {
+		o, err := r.observer.Observe(ctx, t.translation)
+		if err != nil {
+			log.Debug("Cannot observe remote objects of workload translation", "error", err, "workload", t.ref.Name)
+			observeOK = false
+			break
+		}
+		observed = append(observed, o...)
+	}
+	if observeOK {
+		r.observed(trait, observed)
+	}
+
+	mods := []string{}
+	statuses := make(map[string]remotev1alpha1.TargetStatus, len(targets))
+	for _, t := range targets {
+		if t.first {
+			ModificationLatency.WithLabelValues(strings.ToLower(trait.GetObjectKind().GroupVersionKind().GroupKind().String())).Observe(time.Since(t.renderedAt).Seconds())
+		}
+		if t.skipped != nil {
+			statuses[t.name] = remotev1alpha1.TargetStatus{Synced: true, Message: errTargetUnmodified}
+			continue
+		}
+		statuses[t.name] = remotev1alpha1.TargetStatus{Synced: true, Modifications: t.modifications}
+		mods = append(mods, t.modifications...)
+	}
+	sort.Strings(mods)
+	setTargetStatuses(trait, targets, statuses)
+
+	if mr, ok := trait.(ModificationRecorder); ok && diffOK {
+		mr.SetModifications(mods)
+	}
+
+	r.record.Event(trait, r.messages.Event(event.Normal(reasonTraitModify, "Successfully modifed workload translation")))
+	log.Debug("Successfully modified referenced workload", "kind", trait.GetObjectKind().GroupVersionKind().String(), "modifications", mods)
+
+	r.recovered(req.NamespacedName, trait)
+	trait.SetConditions(r.stages.Conditions(len(r.stages), nil)...)
+	trait.SetConditions(r.messages.Conditions(v1alpha1.ReconcileSuccess())...)
+
+	now := time.Now()
+	wait := requeueAfter(now, r.schedule(trait, now))
+	return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+}
+
+// failed records a failed reconcile of the supplied trait, and requeues it
+// with exponential backoff so that a trait that fails persistently does not
+// hot-loop against the API server. Traits that fail too many times in a row
+// are marked as degraded.
+func (r *Reconciler) failed(ctx context.Context, log logging.Logger, nn types.NamespacedName, trait Trait) (reconcile.Result, error) {
+	wait, n := r.backoff.fail(nn)
+	log.Debug("Requeueing failed reconcile", "failures", n, "requeue-after", time.Now().Add(wait))
+	if r.degradedAfter > 0 && n >= r.degradedAfter {
+		trait.SetConditions(r.messages.Conditions(Degraded(n))...)
+	}
+	return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+}
+
+// recovered resets the failed reconciles of the supplied trait, and marks it
+// as no longer degraded if it was.
+func (r *Reconciler) recovered(nn types.NamespacedName, trait Trait) {
+	r.backoff.reset(nn)
+	if trait.GetCondition(TypeDegraded).Status == corev1.ConditionTrue {
+		trait.SetConditions(r.messages.Conditions(Recovered())...)
+	}
+}
+
+// modify the translation of the supplied target. The target is deferred
+// rather than modified if a trait of higher priority has overridden the
+// supplied trait since it last modified the translation. Errors returned by
+// the modifier pipeline are returned as a *stageError, unless the target is a
+// shard that does not contain what the trait modifies, in which case the
+// target is skipped.
+func (r *Reconciler) modify(ctx context.Context, t *target, trait Trait, priority int, multiple bool) error {
+	// A trait that was overridden by a trait of higher priority since it
+	// last modified a translation leaves it as it is, so that the
+	// modifications of the higher priority trait win.
+	d, err := deferred(t.translation, trait, priority)
+	if err != nil {
+		return errors.Wrap(err, errTraitModify)
+	}
+	t.deferred = d
+	if d {
+		return nil
+	}
 
-	// TODO(hasheddan): we make the assumption here that the workload
-	// translation object that we are modifying has the same name as the
-	// workload itself. This would not work if a translation produced multiple
-	// objects of the same kind as they would not be permitted to have the same
-	// name.
-	err := r.client.Get(ctx, types.NamespacedName{Name: trait.GetWorkloadReference().Name, Namespace: trait.GetNamespace()}, translation)
-	if kerrors.IsNotFound(err) {
-		log.Debug("Waiting for referenced workload's translation", "kind", trait.GetObjectKind().GroupVersionKind().String())
-		r.record.Event(trait, event.Normal(reasonTraitWait, "Waiting for workload translation to exist"))
-		trait.SetConditions(v1alpha1.ReconcileSuccess())
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+	// Modifiers only know about a single workload reference, so each is
+	// passed a copy of the trait that references the target workload.
+	tt := trait
+	if multiple {
+		tt = trait.DeepCopyObject().(Trait)
+		tt.SetWorkloadReference(t.ref)
 	}
+
+	sctx, s := r.tracer.StartSpan(ctx, spanModify)
+	s.SetAttributes("workload", t.ref.Name)
+	failed, err := r.stages.Run(sctx, t.translation, tt)
+	s.End(err)
 	if err != nil {
-		log.Debug("Cannot get workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
-		r.record.Event(trait, event.Warning(reasonCannotGetTranslation, err))
-		trait.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errGetTranslation)))
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+		se := &stageError{error: errors.Wrap(err, errTraitModify), stage: failed, cause: err}
+
+		// A shard that does not contain what the trait modifies is left as
+		// it was, including any modifications made by earlier stages.
+		if t.shard && IsNoTarget(err) {
+			if o, ok := t.original.DeepCopyObject().(Object); ok {
+				t.translation = o
+			}
+			t.skipped = se
+			return nil
+		}
+		return se
 	}
 
-	if err := r.trait.Modify(ctx, translation, trait); err != nil {
-		log.Debug("Cannot modify workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
-		r.record.Event(trait, event.Warning(reasonCannotModifyTranslation, err))
-		trait.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errTraitModify)))
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+	// Fields of a KubernetesApplication's resource templates may only be
+	// modified by the controller that owns them.
+	if err := r.claim(t.original, t.translation); err != nil {
+		return errors.Wrap(err, errClaimFields)
 	}
 
-	// The trait's referenced workload should always be translated in an
-	// object(s) that is controlled by the workload. In the case where an
-	// object(s) already exists in the same namespace and with the same name
-	// before it is created, this wll guard against modifying it.
-	if err := r.applicator.Apply(ctx, r.client, translation, resource.ControllersMustMatch()); err != nil {
-		log.Debug("Cannot apply workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
-		r.record.Event(trait, event.Warning(reasonCannotApplyModification, err))
-		trait.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyTraitModification)))
-		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+	// The translation records every field the trait has modified since it
+	// was last rendered, so that its modifications are still reported once
+	// the trait has nothing left to change.
+	t.modifications, t.diffErr = recordModifications(t.original, t.translation, trait)
+
+	// We only observe modification latency the first time this trait
+	// modifies a rendering of the workload's current generation.
+	t.renderedAt, t.first = renderedAt(t.translation, trait)
+
+	if err := lock(t.translation, trait, priority); err != nil {
+		return errors.Wrap(err, errTraitModify)
 	}
+	h, err := r.definition.HashDefinition(ctx, schema.GroupVersionKind(r.kind))
+	if err != nil {
+		return errors.Wrap(err, errTraitModify)
+	}
+	return errors.Wrap(recordTraitInputs(t.translation, trait, h), errTraitModify)
+}
 
-	r.record.Event(trait, event.Normal(reasonTraitModify, "Successfully modifed workload translation"))
-	log.Debug("Successfully modified referenced workload", "kind", trait.GetObjectKind().GroupVersionKind().String())
+// apply the modified translation of the supplied target. Translations that
+// were updated since they were read, for example because their workload was
+// translated again, are read and modified again rather than requeueing the
+// trait.
+func (r *Reconciler) apply(ctx context.Context, log logging.Logger, t *target, trait Trait, priority int, multiple bool) error {
+	return retry.RetryOnConflict(r.conflictBackoff, func() error {
+		if t.deferred {
+			return nil
+		}
+		err := r.applicator.Apply(ctx, r.client, t.translation, resource.ControllersMustMatch())
+		if !kerrors.IsConflict(errors.Cause(err)) {
+			return err
+		}
+		log.Debug("Workload translation was updated concurrently; modifying it again", "workload", t.ref.Name)
 
-	trait.SetConditions(v1alpha1.ReconcileSuccess())
-	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, trait), errUpdateTraitStatus)
+		translation := r.newTranslation()
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: t.translation.GetNamespace(), Name: t.translation.GetName()}, translation); err != nil {
+			return errors.Wrap(err, errGetTranslation)
+		}
+		t.translation, t.original = translation, translation.DeepCopyObject()
+		if err := r.modify(ctx, t, trait, priority, multiple); err != nil {
+			return err
+		}
+
+		// A conflict is returned so that the freshly modified translation
+		// is applied by the next attempt.
+		return errors.Cause(err)
+	})
+}
+
+// A stageError is an error returned by a stage of the modifier pipeline.
+type stageError struct {
+	error
+
+	stage int
+	cause error
+}
+
+// claim the fields of the supplied translation that were modified.
+func (r *Reconciler) claim(original runtime.Object, translation Object) error {
+	before, ok := original.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return nil
+	}
+	after, ok := translation.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return nil
+	}
+	return workload.ClaimFields(before, after, r.owner)
+}
+
+// restore applies the original state of the supplied targets' translations.
+// Restoring translations is best effort; the trait will try to modify them
+// again when it is next reconciled.
+func (r *Reconciler) restore(ctx context.Context, log logging.Logger, targets []target) {
+	for _, t := range targets {
+		o, ok := t.original.(Object)
+		if !ok {
+			continue
+		}
+		o.SetResourceVersion("")
+		if err := r.applicator.Apply(ctx, r.client, o, resource.ControllersMustMatch()); err != nil {
+			log.Debug("Cannot restore workload translation", "error", err, "workload", t.ref.Name)
+		}
+	}
+}
+
+// revert the modifications the supplied trait made to the translations of the
+// workloads it references. Translations that no longer exist have nothing to
+// revert.
+func (r *Reconciler) revert(ctx context.Context, log logging.Logger, trait Trait) error {
+	refs := workloadReferences(trait)
+	for _, ref := range refs {
+		nn, translation, err := r.getTranslation(ctx, trait.GetNamespace(), ref)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetTranslation)
+		}
+		shards, err := r.shardsOf(ctx, nn, translation)
+		if err != nil {
+			return errors.Wrap(err, errGetTranslation)
+		}
+
+		tt := trait
+		if len(refs) > 1 {
+			tt = trait.DeepCopyObject().(Trait)
+			tt.SetWorkloadReference(ref)
+		}
+		for _, t := range packageTargets(ref, append([]Object{translation}, shards...)) {
+			err := r.stages.Revert(ctx, t.translation, tt)
+			if t.shard && IsNoTarget(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrap(err, errRevertModification)
+			}
+			if err := r.release(t.translation); err != nil {
+				return errors.Wrap(err, errRevertModification)
+			}
+			if err := forgetTraitInputs(t.translation, trait); err != nil {
+				return errors.Wrap(err, errRevertModification)
+			}
+			if err := unlock(t.translation, trait); err != nil {
+				return errors.Wrap(err, errRevertModification)
+			}
+			if err := forgetModifications(t.translation, trait); err != nil {
+				return errors.Wrap(err, errRevertModification)
+			}
+			if err := r.applicator.Apply(ctx, r.client, t.translation, resource.ControllersMustMatch()); err != nil {
+				return errors.Wrap(err, errApplyTraitRevert)
+			}
+			log.Debug("Reverted workload translation", "workload", ref.Name, "translation", t.name)
+		}
+	}
+	return nil
+}
+
+// release the fields of the supplied translation that were claimed by the
+// Reconciler.
+func (r *Reconciler) release(translation Object) error {
+	a, ok := translation.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return nil
+	}
+	return workload.ReleaseFields(a, r.owner)
+}
+
+// recordTraitInputs records the generation of the supplied trait, and the
+// supplied hash of its TraitDefinition, in the render inputs of the supplied
+// translation.
+func recordTraitInputs(translation Object, t Trait, definitionHash string) error {
+	ri, err := workload.GetRenderInputs(translation)
+	if err != nil {
+		return err
+	}
+	if ri == nil {
+		ri = &workload.RenderInputs{}
+	}
+	if ri.TraitGenerations == nil {
+		ri.TraitGenerations = map[string]int64{}
+	}
+	ri.TraitGenerations[traitKey(t)] = t.GetGeneration()
+	delete(ri.TraitDefinitionHashes, traitKey(t))
+	if definitionHash != "" {
+		if ri.TraitDefinitionHashes == nil {
+			ri.TraitDefinitionHashes = map[string]string{}
+		}
+		ri.TraitDefinitionHashes[traitKey(t)] = definitionHash
+	}
+	return workload.SetRenderInputs(translation, ri)
+}
+
+// forgetTraitInputs removes the generation and TraitDefinition hash of the
+// supplied trait from the render inputs of the supplied translation.
+func forgetTraitInputs(translation Object, t Trait) error {
+	ri, err := workload.GetRenderInputs(translation)
+	if err != nil || ri == nil {
+		return err
+	}
+	delete(ri.TraitGenerations, traitKey(t))
+	delete(ri.TraitDefinitionHashes, traitKey(t))
+	return workload.SetRenderInputs(translation, ri)
+}
+
+// recordChangelog records any changes to the spec of the supplied trait since
+// it was last reconciled, and emits an event describing them.
+func (r *Reconciler) recordChangelog(ctx context.Context, trait Trait) error {
+	e, updated, err := changelog.Record(trait)
+	if err != nil {
+		return err
+	}
+	if updated {
+		if err := r.client.Update(ctx, trait); err != nil {
+			return err
+		}
+	}
+	if e != nil {
+		r.record.Event(trait, r.messages.Event(event.Normal(reasonSpecChanged, strings.Join(e.Changes, ", "))))
+	}
+	if cr, ok := trait.(changelog.Recorder); ok {
+		cr.SetChangelog(changelog.Get(trait))
+	}
+	return nil
 }
@@ -18,22 +18,32 @@ package trait
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
 
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
 	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
 )
 
 var _ reconcile.Reconciler = &Reconciler{}
@@ -52,6 +62,25 @@ func TestReconciler(t *testing.T) {
 	}
 
 	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	// conflicts returns an applicator that fails with a conflict the supplied
+	// number of times before succeeding.
+	conflicts := func(n int) resource.Applicator {
+		return resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+			if n > 0 {
+				n--
+				return errors.Wrap(kerrors.NewConflict(schema.GroupResource{}, "cool", errBoom), "cannot patch object")
+			}
+			return nil
+		})
+	}
+
+	revertible := func(revert error) ReconcilerOption {
+		return WithModifier(NewRevertibleModifier(ModifyFn(NoopModifier), ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+			return revert
+		})))
+	}
 
 	cases := map[string]struct {
 		reason string
@@ -70,12 +99,146 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{err: errors.Wrap(errBoom, errGetTrait)},
 		},
+		"ClearReconcileRequestError": {
+			reason: "Errors clearing a reconcile request should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.ReconcileNow: "now"})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+			},
+			want: want{err: errors.Wrap(errBoom, errClearReconcileRequest)},
+		},
+		"AddFinalizerError": {
+			reason: "Errors adding a finalizer to a trait whose modifications can be reverted should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{revertible(nil)},
+			},
+			want: want{err: errors.Wrap(errBoom, errAddFinalizer)},
+		},
+		"RevertError": {
+			reason: "A deleted trait whose modifications cannot be reverted should keep its finalizer and be requeued.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							if t, ok := obj.(Trait); ok {
+								t.SetDeletionTimestamp(&now)
+								meta.AddFinalizer(t, Finalizer)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{revertible(errBoom)},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Reverted": {
+			reason: "A deleted trait should revert its modifications, then remove its finalizer.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							if t, ok := obj.(Trait); ok {
+								t.SetDeletionTimestamp(&now)
+								meta.AddFinalizer(t, Finalizer)
+							}
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							if meta.FinalizerExists(obj.(Trait), Finalizer) {
+								return errors.New("MockUpdate: finalizer was not removed")
+							}
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{
+					revertible(nil),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"Paused": {
+			reason: "Paused traits should not modify their workload's translation.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							if _, ok := obj.(Trait); !ok {
+								return errBoom
+							}
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.Paused: "true"})
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(workload.ReasonPaused, got.GetCondition(workload.TypePaused).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"NotSelected": {
+			reason: "Traits whose labels do not match the selector should be left untouched.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetLabels(map[string]string{"tenant": "b"})
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{WithSelector(labels.SelectorFromSet(labels.Set{"tenant": "a"}))},
+			},
+			want: want{result: reconcile.Result{}},
+		},
 		"TraitNotFound": {
 			reason: "Not found errors encountered while getting the resource under reconciliation should be ignored.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
-					Scheme: fake.SchemeWith(&traitfake.Trait{}),
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
 				},
 				t: Kind(fake.GVK(&traitfake.Trait{})),
 				p: Kind(fake.GVK(&traitfake.Object{})),
@@ -110,6 +273,69 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
 		},
+		"PackagingModeMismatch": {
+			reason: "Traits that modify a kind of package their workload was not packaged as should report the mismatch rather than wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+							switch o := obj.(type) {
+							case Trait:
+								return nil
+							case *unstructured.Unstructured:
+								o.SetAnnotations(map[string]string{annotations.PackagedAs: "manifestwork.work.open-cluster-management.io"})
+								return nil
+							}
+							return kerrors.NewNotFound(schema.GroupResource{}, "")
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(ReasonPackagingModeMismatch, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"NotApplicable": {
+			reason: "Traits that do not apply to their workload's kind should report that rather than modify its package.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(ReasonNotApplicable, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{
+					WithApplicabilityChecker(ApplicabilityCheckFn(func(_ context.Context, _ schema.GroupVersionKind, _ oamv1alpha2.WorkloadReference) ([]string, bool) {
+						return []string{"containerizedworkloads.core.oam.dev"}, false
+					})),
+					WithModifier(ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
 		"GetPackageError": {
 			reason: "",
 			args: args{
@@ -172,6 +398,89 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
 		},
+		"ApplyConflictRetried": {
+			reason: "A translation that was updated concurrently should be read and modified again rather than requeueing the trait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileSuccess, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{
+					WithConflictBackoff(wait.Backoff{Steps: 2}),
+					WithApplicator(conflicts(1)),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ApplyConflictExhausted": {
+			reason: "A translation that is updated concurrently more often than the conflict backoff allows should requeue the trait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileError, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{
+					WithConflictBackoff(wait.Backoff{Steps: 2}),
+					WithApplicator(conflicts(2)),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"ModifyErrorDegraded": {
+			reason: "Traits that fail to reconcile too many consecutive times should be marked as degraded.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Trait)
+
+							if diff := cmp.Diff(ReasonPersistentFailure, got.GetCondition(TypeDegraded).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}),
+				},
+				t: Kind(fake.GVK(&traitfake.Trait{})),
+				p: Kind(fake.GVK(&traitfake.Object{})),
+				o: []ReconcilerOption{
+					WithDegradedAfter(1),
+					WithModifier(ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
 	}
 
 	for name, tc := range cases {
@@ -189,3 +498,102 @@ func TestReconciler(t *testing.T) {
 		})
 	}
 }
+
+func TestNewReconciler(t *testing.T) {
+	traitGVK := fake.GVK(&traitfake.Trait{})
+	objectGVK := fake.GVK(&traitfake.Object{})
+
+	type args struct {
+		s *runtime.Scheme
+		t Kind
+		p Kind
+		o []ReconcilerOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   interface{}
+	}{
+		"Registered": {
+			reason: "A Reconciler of registered kinds should be returned.",
+			args:   args{s: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{}), t: Kind(traitGVK), p: Kind(objectGVK)},
+		},
+		"TraitKindNotRegistered": {
+			reason: "A trait kind that is not registered should cause a panic.",
+			args:   args{s: fake.SchemeWith(&traitfake.Object{}), t: Kind(traitGVK), p: Kind(objectGVK)},
+			want:   errors.Errorf("%s: %s", errTraitKindNotRegistered, traitGVK).Error(),
+		},
+		"TraitKindNotTrait": {
+			reason: "A trait kind that does not implement Trait should cause a panic.",
+			args:   args{s: fake.SchemeWith(&traitfake.Object{}), t: Kind(objectGVK), p: Kind(objectGVK)},
+			want:   errors.Errorf("%s: %s", errTraitKindNotTrait, objectGVK).Error(),
+		},
+		"TranslationKindNotRegistered": {
+			reason: "A translation kind that is not registered should cause a panic.",
+			args:   args{s: fake.SchemeWith(&traitfake.Trait{}), t: Kind(traitGVK), p: Kind(objectGVK)},
+			want:   errors.Errorf("%s: %s", errTranslationKindNotRegistered, objectGVK).Error(),
+		},
+		"UnstructuredTrait": {
+			reason: "An unstructured trait kind need not be registered.",
+			args:   args{s: fake.SchemeWith(&traitfake.Object{}), t: Kind(traitGVK), p: Kind(objectGVK), o: []ReconcilerOption{WithUnstructured()}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := func() (p interface{}) {
+				defer func() { p = recover() }()
+				NewReconciler(&fake.Manager{Client: &test.MockClient{}, Scheme: tc.args.s}, tc.args.t, tc.args.p, tc.args.o...)
+				return nil
+			}()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nNewReconciler(...): -want panic, +got panic:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcilerFixtures(t *testing.T) {
+	fixtures, err := traitfake.LoadFixtures(filepath.Join("testdata", "reconciler"))
+	if err != nil {
+		t.Fatalf("LoadFixtures(...): %s", err)
+	}
+
+	for name, f := range fixtures {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			updated := &traitfake.Trait{}
+			m := &fake.Manager{Client: f.Client(updated), Scheme: fake.SchemeWith(&traitfake.Trait{}, &traitfake.Object{})}
+			r := NewReconciler(m, Kind(fake.GVK(&traitfake.Trait{})), Kind(fake.GVK(&traitfake.Object{})))
+			got, err := r.Reconcile(reconcile.Request{})
+
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(f.Want.Error, gotErr); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", f.Reason, diff)
+			}
+
+			if diff := cmp.Diff(reconcile.Result{RequeueAfter: f.Want.RequeueAfter.Duration}, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", f.Reason, diff)
+			}
+
+			if f.Want.Synced == nil {
+				return
+			}
+			synced := updated.GetCondition(v1alpha1.TypeSynced)
+			if diff := cmp.Diff(f.Want.Synced.Reason, synced.Reason); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want synced reason, +got synced reason:\n%s", f.Reason, diff)
+			}
+			if f.Want.Synced.Message == "" {
+				return
+			}
+			if diff := cmp.Diff(f.Want.Synced.Message, synced.Message); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want synced message, +got synced message:\n%s", f.Reason, diff)
+			}
+		})
+	}
+}
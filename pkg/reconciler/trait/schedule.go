@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"time"
+)
+
+// MinScheduledWait is the shortest time the Reconciler waits before
+// reconciling a trait again at its next scheduled time.
+const MinScheduledWait = 1 * time.Second
+
+// A Scheduler returns the next time at which the modifications the supplied
+// trait makes change, for example because they follow a cron schedule, and
+// may record it in the trait's status. It returns the zero time if the
+// modifications do not depend on the time.
+type Scheduler func(t Trait, now time.Time) time.Time
+
+var _ Scheduler = Unscheduled
+
+// Unscheduled returns the zero time; the modifications of the supplied trait
+// do not depend on the time.
+func Unscheduled(_ Trait, _ time.Time) time.Time {
+	return time.Time{}
+}
+
+// requeueAfter returns how long to wait before reconciling a trait whose
+// modifications next change at the supplied time. Traits are reconciled at
+// their next scheduled time if that is sooner than usual.
+func requeueAfter(now, next time.Time) time.Duration {
+	if next.IsZero() {
+		return longWait
+	}
+	wait := next.Sub(now)
+	switch {
+	case wait > longWait:
+		return longWait
+	case wait < MinScheduledWait:
+		return MinScheduledWait
+	}
+	return wait
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Date(2020, 3, 20, 9, 30, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		next   time.Time
+		want   time.Duration
+	}{
+		"Unscheduled": {
+			reason: "Traits that are not scheduled should be reconciled as usual.",
+			next:   time.Time{},
+			want:   longWait,
+		},
+		"ScheduledLater": {
+			reason: "Traits scheduled later than usual should be reconciled as usual.",
+			next:   now.Add(time.Hour),
+			want:   longWait,
+		},
+		"ScheduledSooner": {
+			reason: "Traits scheduled sooner than usual should be reconciled at their next scheduled time.",
+			next:   now.Add(10 * time.Second),
+			want:   10 * time.Second,
+		},
+		"ScheduledInPast": {
+			reason: "Traits whose next scheduled time has passed should be reconciled promptly.",
+			next:   now.Add(-10 * time.Second),
+			want:   MinScheduledWait,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := requeueAfter(now, tc.next)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nrequeueAfter(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
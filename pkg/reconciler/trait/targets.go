@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errTargetNotApplied = "not applied because trait could not be applied to workload"
+	errTargetUnmodified = "not modified because shard does not contain what the trait modifies"
+)
+
+// A MultiWorkloadReferencer is a Trait that may reference multiple workloads.
+// The trait is applied to all or none of the referenced workloads.
+type MultiWorkloadReferencer interface {
+	GetWorkloadReferences() []oamv1alpha2.WorkloadReference
+}
+
+// A TargetStatusRecorder is a Trait that records the status of its
+// modification of each of the workloads it references in its status.
+type TargetStatusRecorder interface {
+	SetTargetStatuses(s map[string]remotev1alpha1.TargetStatus)
+}
+
+// A target is a workload translation that a trait modifies. A workload whose
+// package was sharded has a target for each shard.
+type target struct {
+	ref           oamv1alpha2.WorkloadReference
+	name          string
+	shard         bool
+	skipped       error
+	translation   Object
+	original      runtime.Object
+	modifications []string
+	diffErr       error
+	renderedAt    time.Time
+	first         bool
+	deferred      bool
+}
+
+// workloadReferences returns the workloads referenced by the supplied trait.
+func workloadReferences(t Trait) []oamv1alpha2.WorkloadReference {
+	if mr, ok := t.(MultiWorkloadReferencer); ok && len(mr.GetWorkloadReferences()) > 0 {
+		return mr.GetWorkloadReferences()
+	}
+	return []oamv1alpha2.WorkloadReference{t.GetWorkloadReference()}
+}
+
+// shardsOf returns the shards of the supplied package other than the first,
+// in order of their index. A package that was not sharded has no other shards.
+func (r *Reconciler) shardsOf(ctx context.Context, nn types.NamespacedName, pkg Object) ([]Object, error) {
+	if _, ok := pkg.GetAnnotations()[workload.AnnotationShard]; !ok {
+		return nil, nil
+	}
+
+	shards := []Object{}
+	for i := 1; ; i++ {
+		s := r.newTranslation()
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: fmt.Sprintf("%s-%d", nn.Name, i)}, s)
+		if kerrors.IsNotFound(err) {
+			return shards, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// An object that happens to be named like a shard, but is not
+		// annotated as one, is not part of this package.
+		if s.GetAnnotations()[workload.AnnotationShard] != strconv.Itoa(i) {
+			return shards, nil
+		}
+		shards = append(shards, s)
+	}
+}
+
+// packageTargets returns a target for each of the supplied shards of the
+// package of the referenced workload. Targets of a sharded package are named
+// after their shard rather than after their workload.
+func packageTargets(ref oamv1alpha2.WorkloadReference, shards []Object) []target {
+	targets := make([]target, 0, len(shards))
+	for _, s := range shards {
+		t := target{ref: ref, name: ref.Name, translation: s, original: s.DeepCopyObject()}
+		if len(shards) > 1 {
+			t.name, t.shard = s.GetName(), true
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// unmodified returns the index of the first target whose workload has no
+// target that was modified, because none of its shards contain what the
+// trait modifies.
+func unmodified(targets []target) (int, bool) {
+	modified := map[string]bool{}
+	for _, t := range targets {
+		modified[t.ref.Name] = modified[t.ref.Name] || t.skipped == nil
+	}
+	for i, t := range targets {
+		if !modified[t.ref.Name] {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// failedTargets returns the statuses of the supplied targets when the trait
+// could not be applied to the named target, and thus was applied to none.
+func failedTargets(targets []target, failed string, err error) map[string]remotev1alpha1.TargetStatus {
+	s := make(map[string]remotev1alpha1.TargetStatus, len(targets))
+	for _, t := range targets {
+		s[t.name] = remotev1alpha1.TargetStatus{Message: errTargetNotApplied + " " + failed}
+	}
+	s[failed] = remotev1alpha1.TargetStatus{Message: err.Error()}
+	return s
+}
+
+// setTargetStatuses records the supplied statuses if the trait references
+// multiple workloads, or a workload whose package was sharded, and is a
+// TargetStatusRecorder.
+func setTargetStatuses(t Trait, targets []target, s map[string]remotev1alpha1.TargetStatus) {
+	if len(targets) < 2 {
+		return
+	}
+	if tr, ok := t.(TargetStatusRecorder); ok {
+		tr.SetTargetStatuses(s)
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+var (
+	_ MultiWorkloadReferencer = &remotev1alpha1.BundleTrait{}
+	_ TargetStatusRecorder    = &remotev1alpha1.BundleTrait{}
+)
+
+type multiTrait struct {
+	traitfake.Trait
+	refs    []oamv1alpha2.WorkloadReference
+	targets map[string]remotev1alpha1.TargetStatus
+}
+
+func (t *multiTrait) GetWorkloadReferences() []oamv1alpha2.WorkloadReference {
+	return t.refs
+}
+
+func (t *multiTrait) SetTargetStatuses(s map[string]remotev1alpha1.TargetStatus) {
+	t.targets = s
+}
+
+func (t *multiTrait) DeepCopyObject() runtime.Object {
+	out := *t
+	return &out
+}
+
+func TestReconcileMultipleWorkloads(t *testing.T) {
+	errBoom := errors.New("boom")
+	refs := []oamv1alpha2.WorkloadReference{{Name: "frontend"}, {Name: "backend"}}
+
+	get := func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+		if mt, ok := obj.(*multiTrait); ok {
+			mt.refs = refs
+		}
+		return nil
+	}
+
+	type want struct {
+		targets map[string]remotev1alpha1.TargetStatus
+		applied []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		m      Modifier
+		a      func(o runtime.Object) error
+		want   want
+	}{
+		"ModifyError": {
+			reason: "A trait that cannot modify one of its workloads' translations should apply none of them.",
+			m: ModifyFn(func(_ context.Context, _ runtime.Object, t Trait) error {
+				if t.GetWorkloadReference().Name == "backend" {
+					return errBoom
+				}
+				return nil
+			}),
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"frontend": {Message: errTargetNotApplied + " backend"},
+					"backend":  {Message: errors.Wrap(errBoom, errTraitModify).Error()},
+				},
+			},
+		},
+		"ApplyError": {
+			reason: "A trait that cannot apply one of its workloads' translations should restore those it already applied.",
+			m:      ModifyFn(NoopModifier),
+			a: func(o runtime.Object) error {
+				if o.(Object).GetName() == "backend" {
+					return errBoom
+				}
+				return nil
+			},
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"frontend": {Message: errTargetNotApplied + " backend"},
+					"backend":  {Message: errors.Wrap(errBoom, errApplyTraitModification).Error()},
+				},
+				applied: []string{"frontend", "backend", "frontend"},
+			},
+		},
+		"Success": {
+			reason: "A trait should record that it was applied to each of its workloads.",
+			m:      ModifyFn(NoopModifier),
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"frontend": {Synced: true},
+					"backend":  {Synced: true},
+				},
+				applied: []string{"frontend", "backend"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *multiTrait
+			var applied []string
+
+			m := &fake.Manager{
+				Client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+						if o, ok := obj.(Object); ok {
+							if _, isTrait := obj.(Trait); !isTrait {
+								// Translations are named after their workload.
+								o.SetName(key.Name)
+							}
+						}
+						return get(ctx, key, obj)
+					},
+					MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						got = obj.(*multiTrait)
+						return nil
+					},
+				},
+				Scheme: fake.SchemeWith(&multiTrait{}, &traitfake.Object{}),
+			}
+			a := resource.ApplyFn(func(_ context.Context, _ client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+				applied = append(applied, o.(Object).GetName())
+				if tc.a != nil {
+					return tc.a(o)
+				}
+				return nil
+			})
+
+			r := NewReconciler(m, Kind(fake.GVK(&multiTrait{})), Kind(fake.GVK(&traitfake.Object{})), WithModifier(tc.m), WithApplicator(a))
+			if _, err := r.Reconcile(reconcile.Request{}); err != nil {
+				t.Fatalf("\nReason: %s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want.targets, got.targets, cmpopts.IgnoreFields(remotev1alpha1.TargetStatus{}, "Modifications")); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want targets, +got targets:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileShardedWorkload(t *testing.T) {
+	errNoDeployment := errNoTarget{msg: errNoDeploymentForTrait}
+
+	type want struct {
+		targets map[string]remotev1alpha1.TargetStatus
+		applied []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		m      Modifier
+		want   want
+	}{
+		"ModifyEveryShard": {
+			reason: "A trait should modify every shard of its workload's package.",
+			m:      ModifyFn(NoopModifier),
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"big":   {Synced: true},
+					"big-1": {Synced: true},
+				},
+				applied: []string{"big", "big-1"},
+			},
+		},
+		"SkipShard": {
+			reason: "A shard that does not contain what the trait modifies should be left unchanged.",
+			m: ModifyFn(func(_ context.Context, obj runtime.Object, _ Trait) error {
+				if obj.(Object).GetName() != "big-1" {
+					return errNoDeployment
+				}
+				return nil
+			}),
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"big":   {Synced: true, Message: errTargetUnmodified},
+					"big-1": {Synced: true},
+				},
+				applied: []string{"big-1"},
+			},
+		},
+		"NoShardModified": {
+			reason: "A trait should fail if no shard of its workload's package contains what it modifies.",
+			m: ModifyFn(func(_ context.Context, _ runtime.Object, _ Trait) error {
+				return errNoDeployment
+			}),
+			want: want{
+				targets: map[string]remotev1alpha1.TargetStatus{
+					"big":   {Message: errors.Wrap(errNoDeployment, errTraitModify).Error()},
+					"big-1": {Message: errTargetNotApplied + " big"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *multiTrait
+			var applied []string
+
+			m := &fake.Manager{
+				Client: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+						switch o := obj.(type) {
+						case *multiTrait:
+							o.refs = []oamv1alpha2.WorkloadReference{{Name: "big"}}
+						case *traitfake.Object:
+							shards := map[string]string{"big": "0", "big-1": "1"}
+							i, ok := shards[key.Name]
+							if !ok {
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							}
+							o.SetName(key.Name)
+							o.SetAnnotations(map[string]string{workload.AnnotationShard: i})
+						}
+						return nil
+					},
+					MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+						got = obj.(*multiTrait)
+						return nil
+					},
+				},
+				Scheme: fake.SchemeWith(&multiTrait{}, &traitfake.Object{}),
+			}
+			a := resource.ApplyFn(func(_ context.Context, _ client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+				applied = append(applied, o.(Object).GetName())
+				return nil
+			})
+
+			r := NewReconciler(m, Kind(fake.GVK(&multiTrait{})), Kind(fake.GVK(&traitfake.Object{})), WithModifier(tc.m), WithApplicator(a))
+			if _, err := r.Reconcile(reconcile.Request{}); err != nil {
+				t.Fatalf("\nReason: %s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want.targets, got.targets, cmpopts.IgnoreFields(remotev1alpha1.TargetStatus{}, "Modifications")); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want targets, +got targets:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
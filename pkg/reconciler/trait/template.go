@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotPackage       = "object is not a package of resource templates"
+	errGetTemplates     = "cannot get resource templates of package"
+	errSetTemplates     = "cannot set resource templates of package"
+	errTemplateCount    = "number of resource templates does not match package"
+	errNoTemplateOfKind = "no resource template of kind"
+)
+
+// A templatePath locates the resource templates of an Unstructured package.
+type templatePath struct {
+	// list is the path to the list of entries that contain templates.
+	list []string
+
+	// template is the path to the template within each entry. Entries are
+	// templates if it is empty.
+	template []string
+}
+
+// templatePaths of each kind of package that may be Unstructured.
+var templatePaths = map[schema.GroupKind]templatePath{
+	workloadv1alpha1.KubernetesApplicationGroupVersionKind.GroupKind(): {
+		list:     []string{"spec", "resourceTemplates"},
+		template: []string{"spec", "template"},
+	},
+	workload.ManifestWorkGroupVersionKind.GroupKind(): {
+		list: []string{"spec", "workload", "manifests"},
+	},
+}
+
+// Templates returns the resource templates of the supplied package. Packages
+// may be KubernetesApplications, or Unstructured KubernetesApplications or
+// ManifestWorks.
+func Templates(pkg runtime.Object) ([]*unstructured.Unstructured, error) {
+	switch p := pkg.(type) {
+	case *workloadv1alpha1.KubernetesApplication:
+		out := make([]*unstructured.Unstructured, 0, len(p.Spec.ResourceTemplates))
+		for _, r := range p.Spec.ResourceTemplates {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(r.Spec.Template.Raw, t); err != nil {
+				return nil, errors.Wrap(err, errGetTemplates)
+			}
+			out = append(out, t)
+		}
+		return out, nil
+	case *unstructured.Unstructured:
+		path, ok := templatePaths[p.GroupVersionKind().GroupKind()]
+		if !ok {
+			return nil, errors.New(errNotPackage)
+		}
+		entries, _, err := unstructured.NestedSlice(p.Object, path.list...)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetTemplates)
+		}
+		out := make([]*unstructured.Unstructured, 0, len(entries))
+		for _, e := range entries {
+			t, err := entryTemplate(e, path)
+			if err != nil {
+				return nil, errors.Wrap(err, errGetTemplates)
+			}
+			out = append(out, t)
+		}
+		return out, nil
+	default:
+		return nil, errors.New(errNotPackage)
+	}
+}
+
+// SetTemplates replaces the resource templates of the supplied package with
+// the supplied templates, which must be those returned by Templates, in the
+// same order, once modified.
+func SetTemplates(pkg runtime.Object, templates []*unstructured.Unstructured) error {
+	switch p := pkg.(type) {
+	case *workloadv1alpha1.KubernetesApplication:
+		if len(templates) != len(p.Spec.ResourceTemplates) {
+			return errors.New(errTemplateCount)
+		}
+		for i, t := range templates {
+			// Templates that were not modified keep their encoding.
+			current := &unstructured.Unstructured{}
+			if err := json.Unmarshal(p.Spec.ResourceTemplates[i].Spec.Template.Raw, current); err == nil && reflect.DeepEqual(current.Object, t.Object) {
+				continue
+			}
+			b, err := json.Marshal(t)
+			if err != nil {
+				return errors.Wrap(err, errSetTemplates)
+			}
+			p.Spec.ResourceTemplates[i].Spec.Template = runtime.RawExtension{Raw: b}
+		}
+		return nil
+	case *unstructured.Unstructured:
+		path, ok := templatePaths[p.GroupVersionKind().GroupKind()]
+		if !ok {
+			return errors.New(errNotPackage)
+		}
+		entries, _, err := unstructured.NestedSlice(p.Object, path.list...)
+		if err != nil {
+			return errors.Wrap(err, errSetTemplates)
+		}
+		if len(templates) != len(entries) {
+			return errors.New(errTemplateCount)
+		}
+		for i, t := range templates {
+			if len(path.template) == 0 {
+				entries[i] = t.UnstructuredContent()
+				continue
+			}
+			e, ok := entries[i].(map[string]interface{})
+			if !ok {
+				return errors.New(errSetTemplates)
+			}
+			if err := unstructured.SetNestedMap(e, t.UnstructuredContent(), path.template...); err != nil {
+				return errors.Wrap(err, errSetTemplates)
+			}
+		}
+		return errors.Wrap(unstructured.SetNestedSlice(p.Object, entries, path.list...), errSetTemplates)
+	default:
+		return errors.New(errNotPackage)
+	}
+}
+
+// isPackage returns true if the supplied object is a package of resource
+// templates.
+func isPackage(obj runtime.Object) bool {
+	switch p := obj.(type) {
+	case *workloadv1alpha1.KubernetesApplication:
+		return true
+	case *unstructured.Unstructured:
+		_, ok := templatePaths[p.GroupVersionKind().GroupKind()]
+		return ok
+	default:
+		return false
+	}
+}
+
+// entryTemplate returns the template at the supplied path of an entry.
+func entryTemplate(entry interface{}, path templatePath) (*unstructured.Unstructured, error) {
+	e, ok := entry.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errNotPackage)
+	}
+	if len(path.template) == 0 {
+		return &unstructured.Unstructured{Object: e}, nil
+	}
+	t, _, err := unstructured.NestedMap(e, path.template...)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: t}, nil
+}
+
+// NewTemplateAccessor returns a ModifyAccessor that passes each resource
+// template of the supplied kind in a package to its ModifyFn as an
+// *unstructured.Unstructured, so that kinds that are not compiled into the
+// addon, for example Knative Services, may be modified. It returns an error
+// if the package contains no templates of the supplied kind.
+func NewTemplateAccessor(gk schema.GroupKind) ModifyAccessor {
+	return func(ctx context.Context, obj runtime.Object, t Trait, m ModifyFn) error {
+		templates, err := Templates(obj)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, tmpl := range templates {
+			if tmpl.GroupVersionKind().GroupKind() != gk {
+				continue
+			}
+			found = true
+			if err := m(ctx, tmpl, t); err != nil {
+				return err
+			}
+		}
+		if !found {
+			return errNoTarget{msg: fmt.Sprintf("%s %s", errNoTemplateOfKind, gk)}
+		}
+		return SetTemplates(obj, templates)
+	}
+}
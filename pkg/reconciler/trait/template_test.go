@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	traitfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trait/fake"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+func TestTemplateAccessor(t *testing.T) {
+	knative := schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"}
+
+	ksvc := func(scale string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "cool"},
+			"spec":       map[string]interface{}{"minScale": scale},
+		}
+	}
+	deployment := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "cool"},
+	}
+	raw := func(o map[string]interface{}) runtime.RawExtension {
+		b, _ := json.Marshal(o)
+		return runtime.RawExtension{Raw: b}
+	}
+	kubeApp := func(templates ...map[string]interface{}) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		for _, t := range templates {
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: raw(t)},
+			})
+		}
+		return a
+	}
+	unstructuredKubeApp := func(templates ...map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGroupVersionKind(workloadv1alpha1.KubernetesApplicationGroupVersionKind)
+		entries := make([]interface{}, 0, len(templates))
+		for _, t := range templates {
+			entries = append(entries, map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "cool-template"},
+				"spec":     map[string]interface{}{"template": t},
+			})
+		}
+		_ = unstructured.SetNestedSlice(u.Object, entries, "spec", "resourceTemplates")
+		return u
+	}
+	manifestWork := func(manifests ...map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGroupVersionKind(workload.ManifestWorkGroupVersionKind)
+		entries := make([]interface{}, 0, len(manifests))
+		for _, m := range manifests {
+			entries = append(entries, m)
+		}
+		_ = unstructured.SetNestedSlice(u.Object, entries, "spec", "workload", "manifests")
+		return u
+	}
+
+	scale := func(_ context.Context, obj runtime.Object, _ Trait) error {
+		return unstructured.SetNestedField(obj.(*unstructured.Unstructured).Object, "3", "spec", "minScale")
+	}
+
+	type want struct {
+		obj runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   want
+	}{
+		"KubeApp": {
+			reason: "Templates of the supplied kind in a KubernetesApplication should be modified.",
+			obj:    kubeApp(deployment, ksvc("1")),
+			want:   want{obj: kubeApp(deployment, ksvc("3"))},
+		},
+		"UnstructuredKubeApp": {
+			reason: "Templates of the supplied kind in an Unstructured KubernetesApplication should be modified.",
+			obj:    unstructuredKubeApp(deployment, ksvc("1")),
+			want:   want{obj: unstructuredKubeApp(deployment, ksvc("3"))},
+		},
+		"UnstructuredManifestWork": {
+			reason: "Manifests of the supplied kind in an Unstructured ManifestWork should be modified.",
+			obj:    manifestWork(ksvc("1"), deployment),
+			want:   want{obj: manifestWork(ksvc("3"), deployment)},
+		},
+		"NoTemplateOfKind": {
+			reason: "An error should be returned if the package contains no templates of the supplied kind.",
+			obj:    kubeApp(deployment),
+			want: want{
+				obj: kubeApp(deployment),
+				err: errNoTarget{msg: fmt.Sprintf("%s %s", errNoTemplateOfKind, knative)},
+			},
+		},
+		"NotPackage": {
+			reason: "An error should be returned if the supplied object is not a package.",
+			obj:    &appsv1.Deployment{},
+			want: want{
+				obj: &appsv1.Deployment{},
+				err: errors.New(errNotPackage),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewTemplateAccessor(knative)(context.Background(), tc.obj, &traitfake.Trait{}, scale)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewTemplateAccessor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.obj); diff != "" {
+				t.Errorf("\nReason: %s\nNewTemplateAccessor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
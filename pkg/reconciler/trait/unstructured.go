@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload"
+)
+
+const (
+	errNotUnstructured = "trait is not unstructured"
+	errForwardTrait    = "cannot forward trait to KubernetesApplication"
+)
+
+// An Unstructured trait is a trait of a kind that is not known to the addon
+// at compile time. Its workload reference is read from and written to its
+// spec.workloadRef field.
+type Unstructured struct {
+	workload.Unstructured
+}
+
+// NewUnstructured returns an empty Unstructured trait of the supplied kind.
+func NewUnstructured(gvk schema.GroupVersionKind) *Unstructured {
+	u := &Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	return u
+}
+
+// GetWorkloadReference of this trait.
+func (u *Unstructured) GetWorkloadReference() oamv1alpha2.WorkloadReference {
+	ref := oamv1alpha2.WorkloadReference{}
+	ref.APIVersion, _, _ = unstructured.NestedString(u.Object, "spec", "workloadRef", "apiVersion")
+	ref.Kind, _, _ = unstructured.NestedString(u.Object, "spec", "workloadRef", "kind")
+	ref.Name, _, _ = unstructured.NestedString(u.Object, "spec", "workloadRef", "name")
+	return ref
+}
+
+// SetWorkloadReference of this trait.
+func (u *Unstructured) SetWorkloadReference(ref oamv1alpha2.WorkloadReference) {
+	_ = unstructured.SetNestedStringMap(u.Object, map[string]string{
+		"apiVersion": ref.APIVersion,
+		"kind":       ref.Kind,
+		"name":       ref.Name,
+	}, "spec", "workloadRef")
+}
+
+// DeepCopyObject returns a deep copy of this trait.
+func (u *Unstructured) DeepCopyObject() runtime.Object {
+	return &Unstructured{Unstructured: workload.Unstructured{Unstructured: *u.Unstructured.Unstructured.DeepCopy()}}
+}
+
+// ForwardModifier adds a copy of an Unstructured trait to a
+// KubernetesApplication, so that the remote cluster, which is expected to have
+// the trait's CRD installed, reconciles it. Only the trait's spec, name, and
+// labels are forwarded.
+func ForwardModifier(_ context.Context, obj runtime.Object, t Trait) error {
+	a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+	if !ok {
+		return errors.New(errNotKubeApp)
+	}
+
+	u, ok := t.(*Unstructured)
+	if !ok {
+		return errors.New(errNotUnstructured)
+	}
+
+	out := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	out.SetGroupVersionKind(u.GroupVersionKind())
+	out.SetName(u.GetName())
+	out.SetLabels(u.GetLabels())
+	if spec, ok := u.Object["spec"]; ok {
+		out.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+	return errors.Wrap(SetKubeAppTemplate(a, out), errForwardTrait)
+}
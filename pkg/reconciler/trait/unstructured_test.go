@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ Trait = &Unstructured{}
+
+func TestUnstructuredWorkloadReference(t *testing.T) {
+	ref := oamv1alpha2.WorkloadReference{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool"}
+
+	u := NewUnstructured(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "CoolTrait"})
+	u.SetWorkloadReference(ref)
+
+	if diff := cmp.Diff(ref, u.DeepCopyObject().(Trait).GetWorkloadReference()); diff != "" {
+		t.Errorf("u.GetWorkloadReference(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestForwardModifier(t *testing.T) {
+	u := NewUnstructured(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "CoolTrait"})
+	u.SetName("cool")
+	u.Object["spec"] = map[string]interface{}{"cool": true}
+
+	cases := map[string]struct {
+		reason string
+		obj    Object
+		t      Trait
+		want   error
+		count  int
+	}{
+		"NotKubeApp": {
+			reason: "Translations that are not KubernetesApplications should return an error.",
+			obj:    &Unstructured{},
+			t:      u,
+			want:   errors.New(errNotKubeApp),
+		},
+		"Forwarded": {
+			reason: "The trait should be added to the KubernetesApplication as a resource template.",
+			obj:    &workloadv1alpha1.KubernetesApplication{},
+			t:      u,
+			count:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ForwardModifier(context.Background(), tc.obj, tc.t)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nForwardModifier(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if a, ok := tc.obj.(*workloadv1alpha1.KubernetesApplication); ok {
+				if diff := cmp.Diff(tc.count, len(a.Spec.ResourceTemplates)); diff != "" {
+					t.Errorf("\nReason: %s\nForwardModifier(...): -want templates, +got templates:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
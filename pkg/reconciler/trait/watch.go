@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IndexWorkloadReferences is the name of a field index of the names of the
+// workloads a trait references.
+const IndexWorkloadReferences = "spec.workloadRef.name"
+
+// WorkloadReferenceNames returns the names of the workloads the supplied
+// trait references, for use as the IndexWorkloadReferences field index.
+func WorkloadReferenceNames(o runtime.Object) []string {
+	t, ok := o.(Trait)
+	if !ok {
+		return nil
+	}
+	refs := workloadReferences(t)
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name != "" {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// AddWorkloadReferenceIndex adds the IndexWorkloadReferences field index of
+// the supplied kind of trait to the supplied indexer.
+func AddWorkloadReferenceIndex(i client.FieldIndexer, t Trait) error {
+	return i.IndexField(t, IndexWorkloadReferences, WorkloadReferenceNames)
+}
+
+// EnqueueRequestsForReferencingTraits returns an event handler that enqueues
+// a reconcile of each trait of the supplied kind that references the workload
+// a package was translated from, so that traits modify their package as soon
+// as it changes, for example because their workload was translated again.
+// A package is mapped to the workload that controls it, or to the workload
+// it is named after if it has no controller. Traits are listed using the
+// IndexWorkloadReferences field index, which must have been added for the
+// supplied kind of trait.
+func EnqueueRequestsForReferencingTraits(c client.Reader, s runtime.ObjectCreater, of Kind) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			gvk := schema.GroupVersionKind(of)
+			gvk.Kind += "List"
+			l, err := s.New(gvk)
+			if err != nil {
+				return nil
+			}
+
+			// An event handler has no way to return errors. Packages whose
+			// traits cannot be listed are modified when the traits are next
+			// reconciled.
+			workload := o.Meta.GetName()
+			if ref := metav1.GetControllerOf(o.Meta); ref != nil {
+				workload = ref.Name
+			}
+			if err := c.List(context.Background(), l, client.InNamespace(o.Meta.GetNamespace()), client.MatchingFields{IndexWorkloadReferences: workload}); err != nil {
+				return nil
+			}
+			items, err := kmeta.ExtractList(l)
+			if err != nil {
+				return nil
+			}
+
+			reqs := make([]reconcile.Request, 0, len(items))
+			for _, i := range items {
+				t, ok := i.(Trait)
+				if !ok {
+					continue
+				}
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: t.GetNamespace(), Name: t.GetName()}})
+			}
+			return reqs
+		}),
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+func TestWorkloadReferenceNames(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      runtime.Object
+		want   []string
+	}{
+		"NotATrait": {
+			reason: "Objects that are not traits reference no workloads.",
+			o:      &workloadv1alpha1.KubernetesApplication{},
+		},
+		"NoReference": {
+			reason: "Traits that reference no workload should not be indexed.",
+			o:      &oamv1alpha2.ManualScalerTrait{},
+			want:   []string{},
+		},
+		"Reference": {
+			reason: "The name of the referenced workload should be returned.",
+			o: &oamv1alpha2.ManualScalerTrait{Spec: oamv1alpha2.ManualScalerTraitSpec{
+				WorkloadReference: oamv1alpha2.WorkloadReference{Name: "cool-workload"},
+			}},
+			want: []string{"cool-workload"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WorkloadReferenceNames(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nWorkloadReferenceNames(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueRequestsForReferencingTraits(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	if err := oamv1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Namespace: "cool-ns", Name: "cool-workload"}}
+	list := func(obj runtime.Object, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		if lo.Namespace != "cool-ns" || lo.FieldSelector.String() != IndexWorkloadReferences+"=cool-workload" {
+			return errBoom
+		}
+		obj.(*oamv1alpha2.ManualScalerTraitList).Items = []oamv1alpha2.ManualScalerTrait{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "cool-ns", Name: "cool-trait"}},
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		of     Kind
+		pkg    *workloadv1alpha1.KubernetesApplication
+		want   []reconcile.Request
+	}{
+		"UnknownKind": {
+			reason: "Traits of kinds the scheme does not know should not be enqueued.",
+			c:      &test.MockClient{},
+			of:     Kind(workloadv1alpha1.KubernetesApplicationGroupVersionKind),
+		},
+		"ListError": {
+			reason: "Traits that cannot be listed should not be enqueued.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			of:     Kind(oamv1alpha2.ManualScalerTraitGroupVersionKind),
+		},
+		"Success": {
+			reason: "Traits that reference the package's workload should be enqueued.",
+			c: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				return list(obj, opts...)
+			}},
+			of: Kind(oamv1alpha2.ManualScalerTraitGroupVersionKind),
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "cool-ns", Name: "cool-trait"}},
+			},
+		},
+		"ControlledPackage": {
+			reason: "Traits that reference the workload controlling a package should be enqueued, whatever the package is named.",
+			c: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				return list(obj, opts...)
+			}},
+			of: Kind(oamv1alpha2.ManualScalerTraitGroupVersionKind),
+			pkg: &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cool-ns",
+				Name:      "team-a-cool-workload",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&oamv1alpha2.ContainerizedWorkload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload"}}, oamv1alpha2.ContainerizedWorkloadGroupVersionKind),
+				},
+			}},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "cool-ns", Name: "cool-trait"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := EnqueueRequestsForReferencingTraits(tc.c, s, tc.of).(*handler.EnqueueRequestsFromMapFunc)
+			pkg := a
+			if tc.pkg != nil {
+				pkg = tc.pkg
+			}
+			got := h.ToRequests.Map(handler.MapObject{Meta: pkg, Object: pkg})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nEnqueueRequestsForReferencingTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
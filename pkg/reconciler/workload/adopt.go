@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errControlledByOther = "existing package is controlled by another object"
+	errNotControlled     = "existing package has no controller; set the adoption policy to Adopt or Orphan to apply it"
+)
+
+// An AdoptionPolicy determines how a package that already exists but has no
+// controller, for example because it was created by a previous version of the
+// addon, is handled.
+type AdoptionPolicy string
+
+// Adoption policies.
+const (
+	// AdoptionPolicyFail refuses to apply a package over an existing package
+	// that has no controller. This is the default.
+	AdoptionPolicyFail AdoptionPolicy = "Fail"
+
+	// AdoptionPolicyAdopt makes the workload the controller of an existing
+	// package that has no controller, then applies the package over it.
+	AdoptionPolicyAdopt AdoptionPolicy = "Adopt"
+
+	// AdoptionPolicyOrphan leaves an existing package that has no controller
+	// unchanged rather than applying the package over it.
+	AdoptionPolicyOrphan AdoptionPolicy = "Orphan"
+)
+
+// errOrphaned indicates that an existing package was left unchanged.
+type errOrphaned struct{ name string }
+
+func (e errOrphaned) Error() string {
+	return "existing package " + e.name + " has no controller and was left unchanged"
+}
+
+// IsOrphaned returns true if the supplied error indicates that an existing
+// package was left unchanged per the AdoptionPolicyOrphan policy.
+func IsOrphaned(err error) bool {
+	_, ok := errors.Cause(err).(errOrphaned)
+	return ok
+}
+
+// ControllersMustMatch returns an ApplyOption that returns an error if the
+// current package is controlled by an object other than the controller of the
+// desired package. Current packages that have no controller are handled per
+// the supplied AdoptionPolicy. Adopted packages keep their owner references
+// other than their controller.
+func ControllersMustMatch(p AdoptionPolicy) resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, ok := current.(metav1.Object)
+		if !ok {
+			return errors.New(errObjectMeta)
+		}
+		d, ok := desired.(metav1.Object)
+		if !ok {
+			return errors.New(errObjectMeta)
+		}
+
+		if meta.HaveSameController(c, d) {
+			return nil
+		}
+		if metav1.GetControllerOf(c) != nil {
+			return errors.New(errControlledByOther)
+		}
+
+		switch p {
+		case AdoptionPolicyAdopt:
+			for _, ref := range c.GetOwnerReferences() {
+				meta.AddOwnerReference(d, ref)
+			}
+			return nil
+		case AdoptionPolicyOrphan:
+			return errOrphaned{name: c.GetName()}
+		default:
+			return errors.New(errNotControlled)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+func kaWithOwner(uid string, controller bool) kubeAppModifier {
+	return func(a *workloadv1alpha1.KubernetesApplication) {
+		a.SetOwnerReferences(append(a.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: "example.org/v1",
+			Kind:       "Owner",
+			Name:       uid,
+			UID:        types.UID(uid),
+			Controller: &controller,
+		}))
+	}
+}
+
+func TestControllersMustMatch(t *testing.T) {
+	type args struct {
+		p AdoptionPolicy
+		c runtime.Object
+		d runtime.Object
+	}
+
+	type want struct {
+		o        runtime.Object
+		err      error
+		orphaned bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SameController": {
+			reason: "No error should be returned if the current and desired packages have the same controller.",
+			args: args{
+				c: kubeApp(kaWithOwner("workload", true)),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o: kubeApp(kaWithOwner("workload", true)),
+			},
+		},
+		"OtherController": {
+			reason: "An error should be returned if the current package has a different controller, regardless of the adoption policy.",
+			args: args{
+				p: AdoptionPolicyAdopt,
+				c: kubeApp(kaWithOwner("other", true)),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o:   kubeApp(kaWithOwner("workload", true)),
+				err: errors.New(errControlledByOther),
+			},
+		},
+		"NoControllerFail": {
+			reason: "An error should be returned if the current package has no controller and the policy is Fail.",
+			args: args{
+				p: AdoptionPolicyFail,
+				c: kubeApp(),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o:   kubeApp(kaWithOwner("workload", true)),
+				err: errors.New(errNotControlled),
+			},
+		},
+		"NoControllerDefault": {
+			reason: "Packages with no controller should not be adopted if no policy is specified.",
+			args: args{
+				c: kubeApp(),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o:   kubeApp(kaWithOwner("workload", true)),
+				err: errors.New(errNotControlled),
+			},
+		},
+		"NoControllerAdopt": {
+			reason: "A package with no controller should be adopted, keeping its other owner references, if the policy is Adopt.",
+			args: args{
+				p: AdoptionPolicyAdopt,
+				c: kubeApp(kaWithOwner("other", false)),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o: kubeApp(kaWithOwner("workload", true), kaWithOwner("other", false)),
+			},
+		},
+		"NoControllerOrphan": {
+			reason: "A package with no controller should be left unchanged if the policy is Orphan.",
+			args: args{
+				p: AdoptionPolicyOrphan,
+				c: kubeApp(),
+				d: kubeApp(kaWithOwner("workload", true)),
+			},
+			want: want{
+				o:        kubeApp(kaWithOwner("workload", true)),
+				err:      errOrphaned{name: "cool-kapp"},
+				orphaned: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ControllersMustMatch(tc.args.p)(context.Background(), tc.args.c, tc.args.d)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nControllersMustMatch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got := IsOrphaned(err); got != tc.want.orphaned {
+				t.Errorf("\nReason: %s\nIsOrphaned(...): want %t, got %t", tc.reason, tc.want.orphaned, got)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.d); diff != "" {
+				t.Errorf("\nReason: %s\nControllersMustMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
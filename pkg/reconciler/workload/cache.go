@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// A TranslationCache caches the packaged translation of workloads, so that
+// reconciles of workloads that have not changed need not translate and
+// package them again.
+type TranslationCache interface {
+	// Get the cached translation of the supplied workload, if any.
+	Get(w Workload) ([]Object, bool)
+
+	// Set the cached translation of the supplied workload.
+	Set(w Workload, objs []Object)
+
+	// Forget the cached translation of the supplied workload.
+	Forget(nn types.NamespacedName)
+}
+
+// A NopTranslationCache does not cache translations.
+type NopTranslationCache struct{}
+
+// Get always returns false.
+func (c NopTranslationCache) Get(_ Workload) ([]Object, bool) { return nil, false }
+
+// Set does nothing.
+func (c NopTranslationCache) Set(_ Workload, _ []Object) {}
+
+// Forget does nothing.
+func (c NopTranslationCache) Forget(_ types.NamespacedName) {}
+
+// A GenerationCache caches the translation of each workload until its UID,
+// generation, labels, or annotations change. Workloads may be translated from
+// their labels and annotations, which do not change their generation. Changes
+// to other inputs of a translation, such as the Secrets or targets a workload
+// references, do not invalidate it until the reconcile of the workload is
+// explicitly requested.
+type GenerationCache struct {
+	mx      sync.RWMutex
+	entries map[types.NamespacedName]cacheEntry
+}
+
+type cacheEntry struct {
+	key  string
+	objs []Object
+}
+
+// NewGenerationCache returns an empty GenerationCache.
+func NewGenerationCache() *GenerationCache {
+	return &GenerationCache{entries: map[types.NamespacedName]cacheEntry{}}
+}
+
+// Get the cached translation of the supplied workload. Copies of the cached
+// objects are returned, so callers may modify them.
+func (c *GenerationCache) Get(w Workload) ([]Object, bool) {
+	c.mx.RLock()
+	e, ok := c.entries[types.NamespacedName{Namespace: w.GetNamespace(), Name: w.GetName()}]
+	c.mx.RUnlock()
+
+	if !ok || e.key != cacheKey(w) {
+		return nil, false
+	}
+	return deepCopy(e.objs), true
+}
+
+// Set the cached translation of the supplied workload. Copies of the supplied
+// objects are cached, so callers may modify them.
+func (c *GenerationCache) Set(w Workload, objs []Object) {
+	e := cacheEntry{key: cacheKey(w), objs: deepCopy(objs)}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.entries[types.NamespacedName{Namespace: w.GetNamespace(), Name: w.GetName()}] = e
+}
+
+// Forget the cached translation of the supplied workload.
+func (c *GenerationCache) Forget(nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	delete(c.entries, nn)
+}
+
+// cacheKey returns a key that changes when the UID, generation, labels, or
+// annotations of the supplied workload change.
+func cacheKey(w Workload) string {
+	h := fnv.New64a()
+	for _, m := range []map[string]string{w.GetLabels(), w.GetAnnotations()} {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s=%s\n", k, m[k])
+		}
+		fmt.Fprint(h, "\n")
+	}
+	return fmt.Sprintf("%s/%d/%x", w.GetUID(), w.GetGeneration(), h.Sum64())
+}
+
+func deepCopy(objs []Object) []Object {
+	if objs == nil {
+		return nil
+	}
+	out := make([]Object, len(objs))
+	for i := range objs {
+		out[i] = objs[i].DeepCopyObject().(Object)
+	}
+	return out
+}
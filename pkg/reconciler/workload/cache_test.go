@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+var _ TranslationCache = &GenerationCache{}
+var _ TranslationCache = NopTranslationCache{}
+
+func TestGenerationCache(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "cool", Name: "workload"}
+	cached := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   nn.Namespace,
+		Name:        nn.Name,
+		UID:         "very-unique",
+		Generation:  1,
+		Annotations: map[string]string{"cool": "true"},
+	}}
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "workload"}}
+
+	type want struct {
+		objs []Object
+		ok   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      Workload
+		forget bool
+		want   want
+	}{
+		"Hit": {
+			reason: "The cached translation of an unchanged workload should be returned.",
+			w:      cached.DeepCopyObject().(Workload),
+			want:   want{objs: []Object{d}, ok: true},
+		},
+		"GenerationChanged": {
+			reason: "Workloads whose generation changed should not hit the cache.",
+			w: func() Workload {
+				w := cached.DeepCopyObject().(Workload)
+				w.SetGeneration(2)
+				return w
+			}(),
+			want: want{},
+		},
+		"UIDChanged": {
+			reason: "Workloads that were deleted and recreated should not hit the cache.",
+			w: func() Workload {
+				w := cached.DeepCopyObject().(Workload)
+				w.SetUID("other")
+				return w
+			}(),
+			want: want{},
+		},
+		"AnnotationsChanged": {
+			reason: "Workloads whose annotations changed should not hit the cache.",
+			w: func() Workload {
+				w := cached.DeepCopyObject().(Workload)
+				w.SetAnnotations(map[string]string{"cool": "false"})
+				return w
+			}(),
+			want: want{},
+		},
+		"Forgotten": {
+			reason: "Forgotten workloads should not hit the cache.",
+			w:      cached.DeepCopyObject().(Workload),
+			forget: true,
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewGenerationCache()
+			c.Set(cached, []Object{d.DeepCopy()})
+			if tc.forget {
+				c.Forget(nn)
+			}
+
+			objs, ok := c.Get(tc.w)
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\nReason: %s\nc.Get(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// Callers may modify the objects they get without changing
+			// the cache.
+			for _, o := range objs {
+				o.SetName("modified")
+			}
+			if again, _ := c.Get(tc.w); len(again) > 0 && again[0].GetName() != d.GetName() {
+				t.Errorf("\nReason: %s\nc.Get(...): cached object was modified", tc.reason)
+			}
+		})
+	}
+}
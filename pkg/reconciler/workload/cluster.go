@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errParseKubernetesVersion = "cannot parse kubernetes version of kubernetes target"
+	errGetTranslationContext  = "cannot get translation context"
+	errHashTranslationContext = "cannot hash translation context"
+)
+
+// AnnotationKubernetesVersion may be set on a KubernetesTarget to the version
+// of Kubernetes its cluster runs, for example "v1.17.3".
+const AnnotationKubernetesVersion = "workload.oam.crossplane.io/kubernetes-version"
+
+// AnnotationAPIGroups may be set on a KubernetesTarget to a comma separated
+// list of the API groups its cluster serves, for example
+// "apps,networking.k8s.io,policy".
+const AnnotationAPIGroups = "workload.oam.crossplane.io/api-groups"
+
+// AnnotationTranslationContextHash is set to a hash of the TranslationContext
+// of a workload when it is resolved, so that its cached translation is
+// invalidated when the clusters it may be scheduled to change.
+const AnnotationTranslationContextHash = "workload.oam.crossplane.io/translation-context-hash"
+
+// A TranslationContext describes the clusters a workload may be scheduled to.
+// The zero TranslationContext describes an unknown cluster.
+type TranslationContext struct {
+	// KubernetesVersion is the lowest version of Kubernetes run by any of the
+	// clusters. It is empty if the version of any of them is unknown.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// APIGroups are the sorted API groups served by all of the clusters. They
+	// are nil if the API groups of any of them are unknown.
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Labels are the labels shared by all of the clusters' targets.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AtLeast returns true if all of the clusters are known to run at least the
+// supplied version of Kubernetes.
+func (tc TranslationContext) AtLeast(v string) bool {
+	if tc.KubernetesVersion == "" {
+		return false
+	}
+	min, err := version.ParseGeneric(v)
+	if err != nil {
+		return false
+	}
+	cur, err := version.ParseGeneric(tc.KubernetesVersion)
+	if err != nil {
+		return false
+	}
+	return cur.AtLeast(min)
+}
+
+// HasAPIGroup returns true if all of the clusters are known to serve the
+// supplied API group. The core API group, "", is always served.
+func (tc TranslationContext) HasAPIGroup(g string) bool {
+	if g == "" {
+		return true
+	}
+	i := sort.SearchStrings(tc.APIGroups, g)
+	return i < len(tc.APIGroups) && tc.APIGroups[i] == g
+}
+
+// Region returns the region all of the clusters' targets are labelled with,
+// if any.
+func (tc TranslationContext) Region() string {
+	return tc.Labels[corev1.LabelZoneRegion]
+}
+
+func (tc TranslationContext) isZero() bool {
+	return tc.KubernetesVersion == "" && tc.APIGroups == nil && len(tc.Labels) == 0
+}
+
+// A TranslationContextFn returns the TranslationContext of a workload.
+type TranslationContextFn func(context.Context, Workload) (TranslationContext, error)
+
+// A ContextTranslateFn translates a workload into an object or objects
+// appropriate to the supplied TranslationContext.
+type ContextTranslateFn func(context.Context, TranslationContext, Workload) ([]Object, error)
+
+// WrapTranslator returns a TranslateFn that translates a workload using the
+// supplied ContextTranslateFn, passing it the TranslationContext returned by
+// the supplied TranslationContextFn.
+func WrapTranslator(c TranslationContextFn, fn ContextTranslateFn) TranslateFn {
+	return func(ctx context.Context, w Workload) ([]Object, error) {
+		tc, err := c(ctx, w)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetTranslationContext)
+		}
+		return fn(ctx, tc, w)
+	}
+}
+
+// ResolveTranslationContext returns a ParameterResolver that annotates a copy
+// of a workload with a hash of its TranslationContext. Workloads translated by
+// WrapTranslator should be resolved by it, so that they are translated again
+// when their TranslationContext changes.
+func ResolveTranslationContext(c TranslationContextFn) ParameterResolver {
+	return ParameterResolverFn(func(ctx context.Context, w Workload) (Workload, error) {
+		tc, err := c(ctx, w)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetTranslationContext)
+		}
+		if tc.isZero() {
+			return w, nil
+		}
+		b, err := json.Marshal(tc)
+		if err != nil {
+			return nil, errors.Wrap(err, errHashTranslationContext)
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(b)
+
+		out := w.DeepCopyObject().(Workload)
+		meta.AddAnnotations(out, map[string]string{AnnotationTranslationContextHash: fmt.Sprintf("%x", h.Sum64())})
+		return out, nil
+	})
+}
+
+// TargetTranslationContext returns a TranslationContextFn that describes the
+// KubernetesTargets a workload may be scheduled to, per its target
+// annotations. A workload that specifies no target may be scheduled to any
+// cluster, so its TranslationContext is unknown.
+func TargetTranslationContext(c client.Reader) TranslationContextFn {
+	return func(ctx context.Context, w Workload) (TranslationContext, error) {
+		if name := w.GetAnnotations()[AnnotationTarget]; name != "" {
+			t := &workloadv1alpha1.KubernetesTarget{}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: w.GetNamespace(), Name: name}, t); err != nil {
+				return TranslationContext{}, errors.Wrapf(err, "%s %s", errGetTarget, name)
+			}
+			return targetContext([]workloadv1alpha1.KubernetesTarget{*t})
+		}
+
+		s := w.GetAnnotations()[AnnotationTargetSelector]
+		if s == "" {
+			return TranslationContext{}, nil
+		}
+		ls, err := metav1.ParseToLabelSelector(s)
+		if err != nil {
+			return TranslationContext{}, errors.Wrap(err, errParseTargetSelector)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(ls)
+		if err != nil {
+			return TranslationContext{}, errors.Wrap(err, errParseTargetSelector)
+		}
+		l := &workloadv1alpha1.KubernetesTargetList{}
+		if err := c.List(ctx, l, client.InNamespace(w.GetNamespace()), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return TranslationContext{}, errors.Wrap(err, errListTargets)
+		}
+		return targetContext(l.Items)
+	}
+}
+
+// targetContext returns a TranslationContext that holds for all of the supplied
+// targets, any of which a workload may be scheduled to.
+func targetContext(targets []workloadv1alpha1.KubernetesTarget) (TranslationContext, error) {
+	if len(targets) == 0 {
+		return TranslationContext{}, nil
+	}
+
+	var min *version.Version
+	knownVersion := true
+	var groups map[string]int
+	knownGroups := true
+	labels := map[string]string{}
+	for k, v := range targets[0].GetLabels() {
+		labels[k] = v
+	}
+
+	for _, t := range targets {
+		a := t.GetAnnotations()
+
+		if v, ok := a[AnnotationKubernetesVersion]; ok && knownVersion {
+			pv, err := version.ParseGeneric(v)
+			if err != nil {
+				return TranslationContext{}, errors.Wrapf(err, "%s %s", errParseKubernetesVersion, t.GetName())
+			}
+			if min == nil || pv.LessThan(min) {
+				min = pv
+			}
+		} else {
+			knownVersion = false
+		}
+
+		if g, ok := a[AnnotationAPIGroups]; ok && knownGroups {
+			if groups == nil {
+				groups = map[string]int{}
+			}
+			seen := map[string]bool{}
+			for _, group := range strings.Split(g, ",") {
+				group = strings.TrimSpace(group)
+				if group == "" || seen[group] {
+					continue
+				}
+				seen[group] = true
+				groups[group]++
+			}
+		} else {
+			knownGroups = false
+		}
+
+		for k, v := range labels {
+			if t.GetLabels()[k] != v {
+				delete(labels, k)
+			}
+		}
+	}
+
+	tc := TranslationContext{}
+	if knownVersion && min != nil {
+		tc.KubernetesVersion = "v" + min.String()
+	}
+	if knownGroups {
+		tc.APIGroups = []string{}
+		for g, n := range groups {
+			if n == len(targets) {
+				tc.APIGroups = append(tc.APIGroups, g)
+			}
+		}
+		sort.Strings(tc.APIGroups)
+	}
+	if len(labels) > 0 {
+		tc.Labels = labels
+	}
+	return tc, nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+func TestTranslationContext(t *testing.T) {
+	known := TranslationContext{
+		KubernetesVersion: "v1.16.4",
+		APIGroups:         []string{"apps", "networking.k8s.io"},
+		Labels:            map[string]string{corev1.LabelZoneRegion: "us-west"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		got    bool
+		want   bool
+	}{
+		"AtLeastOlder": {
+			reason: "A context should run at least an older version of Kubernetes.",
+			got:    known.AtLeast("v1.14"),
+			want:   true,
+		},
+		"AtLeastNewer": {
+			reason: "A context should not run at least a newer version of Kubernetes.",
+			got:    known.AtLeast("1.17.0"),
+			want:   false,
+		},
+		"AtLeastUnknown": {
+			reason: "A context of an unknown version should not run at least any version of Kubernetes.",
+			got:    TranslationContext{}.AtLeast("v1.0"),
+			want:   false,
+		},
+		"HasAPIGroup": {
+			reason: "A context should serve its API groups.",
+			got:    known.HasAPIGroup("networking.k8s.io"),
+			want:   true,
+		},
+		"HasCoreAPIGroup": {
+			reason: "A context should always serve the core API group.",
+			got:    TranslationContext{}.HasAPIGroup(""),
+			want:   true,
+		},
+		"MissingAPIGroup": {
+			reason: "A context should not serve API groups it does not list.",
+			got:    known.HasAPIGroup("policy"),
+			want:   false,
+		},
+		"Region": {
+			reason: "A context should report the region its targets are labelled with.",
+			got:    known.Region() == "us-west",
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("\nReason: %s\n-want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWrapTranslator(t *testing.T) {
+	errBoom := errors.New("boom")
+	known := TranslationContext{KubernetesVersion: "v1.17.0"}
+
+	cases := map[string]struct {
+		reason string
+		c      TranslationContextFn
+		want   []Object
+		err    error
+	}{
+		"ContextError": {
+			reason: "Errors getting the translation context should be returned.",
+			c: func(_ context.Context, _ Workload) (TranslationContext, error) {
+				return TranslationContext{}, errBoom
+			},
+			err: errors.Wrap(errBoom, errGetTranslationContext),
+		},
+		"Success": {
+			reason: "The translation context should be passed to the translator.",
+			c: func(_ context.Context, _ Workload) (TranslationContext, error) {
+				return known, nil
+			},
+			want: []Object{&workloadv1alpha1.KubernetesApplication{
+				ObjectMeta: metav1.ObjectMeta{Name: known.KubernetesVersion},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fn := WrapTranslator(tc.c, func(_ context.Context, c TranslationContext, _ Workload) ([]Object, error) {
+				return []Object{&workloadv1alpha1.KubernetesApplication{
+					ObjectMeta: metav1.ObjectMeta{Name: c.KubernetesVersion},
+				}}, nil
+			})
+			got, err := fn(context.Background(), NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWrapTranslator(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nWrapTranslator(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveTranslationContext(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason   string
+		c        TranslationContextFn
+		wantHash bool
+		err      error
+	}{
+		"ContextError": {
+			reason: "Errors getting the translation context should be returned.",
+			c: func(_ context.Context, _ Workload) (TranslationContext, error) {
+				return TranslationContext{}, errBoom
+			},
+			err: errors.Wrap(errBoom, errGetTranslationContext),
+		},
+		"UnknownContext": {
+			reason: "A workload with an unknown translation context should not be annotated.",
+			c: func(_ context.Context, _ Workload) (TranslationContext, error) {
+				return TranslationContext{}, nil
+			},
+		},
+		"KnownContext": {
+			reason: "A workload with a known translation context should be annotated with its hash.",
+			c: func(_ context.Context, _ Workload) (TranslationContext, error) {
+				return TranslationContext{KubernetesVersion: "v1.17.0"}, nil
+			},
+			wantHash: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTranslationContext(tc.c).Resolve(context.Background(), NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nResolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			_, hashed := got.GetAnnotations()[AnnotationTranslationContextHash]
+			if diff := cmp.Diff(tc.wantHash, hashed); diff != "" {
+				t.Errorf("\nReason: %s\nResolve(...): -want hash, +got hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTargetTranslationContext(t *testing.T) {
+	errBoom := errors.New("boom")
+	_, errVersion := version.ParseGeneric("cool")
+
+	workload := func(annotations map[string]string) Workload {
+		w := NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind)
+		w.SetAnnotations(annotations)
+		return w
+	}
+
+	target := func(name string, labels, annotations map[string]string) workloadv1alpha1.KubernetesTarget {
+		return workloadv1alpha1.KubernetesTarget{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	targets := func(t ...workloadv1alpha1.KubernetesTarget) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*workloadv1alpha1.KubernetesTargetList)
+			l.Items = t
+			return nil
+		}
+	}
+
+	type want struct {
+		tc  TranslationContext
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      Workload
+		want   want
+	}{
+		"NoTarget": {
+			reason: "A workload that specifies no target should have an unknown translation context.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(nil),
+			want:   want{tc: TranslationContext{}},
+		},
+		"GetTargetError": {
+			reason: "A named target that cannot be got should return an error.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(map[string]string{AnnotationTarget: "cool-target"}),
+			want:   want{err: errors.Wrapf(errBoom, "%s %s", errGetTarget, "cool-target")},
+		},
+		"NamedTarget": {
+			reason: "A named target should be described by its annotations and labels.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				t := target("cool-target", map[string]string{"region": "us-west"}, map[string]string{
+					AnnotationKubernetesVersion: "v1.17.3",
+					AnnotationAPIGroups:         "policy, apps,apps",
+				})
+				*obj.(*workloadv1alpha1.KubernetesTarget) = t
+				return nil
+			})},
+			w: workload(map[string]string{AnnotationTarget: "cool-target"}),
+			want: want{tc: TranslationContext{
+				KubernetesVersion: "v1.17.3",
+				APIGroups:         []string{"apps", "policy"},
+				Labels:            map[string]string{"region": "us-west"},
+			}},
+		},
+		"InvalidVersion": {
+			reason: "A target whose Kubernetes version cannot be parsed should return an error.",
+			c: &test.MockClient{MockList: targets(
+				target("cool-target", nil, map[string]string{AnnotationKubernetesVersion: "cool"}),
+			)},
+			w:    workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			want: want{err: errors.Wrapf(errVersion, "%s %s", errParseKubernetesVersion, "cool-target")},
+		},
+		"ListTargetsError": {
+			reason: "Targets that cannot be listed should return an error.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			w:      workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			want:   want{err: errors.Wrap(errBoom, errListTargets)},
+		},
+		"SelectedTargets": {
+			reason: "Selected targets should be described by what holds for all of them.",
+			c: &test.MockClient{MockList: targets(
+				target("a", map[string]string{"region": "us-west", "tier": "prod"}, map[string]string{
+					AnnotationKubernetesVersion: "v1.17.3",
+					AnnotationAPIGroups:         "apps,policy",
+				}),
+				target("b", map[string]string{"region": "us-west", "tier": "canary"}, map[string]string{
+					AnnotationKubernetesVersion: "v1.15.0",
+					AnnotationAPIGroups:         "apps,networking.k8s.io",
+				}),
+			)},
+			w: workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			want: want{tc: TranslationContext{
+				KubernetesVersion: "v1.15.0",
+				APIGroups:         []string{"apps"},
+				Labels:            map[string]string{"region": "us-west"},
+			}},
+		},
+		"PartiallyKnownTargets": {
+			reason: "The version and API groups of selected targets should be unknown if those of any target are.",
+			c: &test.MockClient{MockList: targets(
+				target("a", map[string]string{"region": "us-west"}, map[string]string{
+					AnnotationKubernetesVersion: "v1.17.3",
+					AnnotationAPIGroups:         "apps",
+				}),
+				target("b", map[string]string{"region": "us-west"}, nil),
+			)},
+			w:    workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			want: want{tc: TranslationContext{Labels: map[string]string{"region": "us-west"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := TargetTranslationContext(tc.c)(context.Background(), tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nTargetTranslationContext(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tc, got); diff != "" {
+				t.Errorf("\nReason: %s\nTargetTranslationContext(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errListResources         = "cannot list KubernetesApplicationResources"
+	errApplyConnectionSecret = "cannot apply connection secret"
+)
+
+// AnnotationConnectionSecret may be set on a workload to publish the endpoint
+// of its remote Service as a connection secret in the host cluster, using the
+// keys of Crossplane's connection secret conventions. Its value is the name of
+// the secret, optionally prefixed by a namespace, i.e. "namespace/name". The
+// secret is written to the workload's namespace if no namespace is specified.
+const AnnotationConnectionSecret = "workload.oam.crossplane.io/connection-secret"
+
+// A ConnectionPublisher publishes the connection details of a workload.
+type ConnectionPublisher interface {
+	PublishConnection(ctx context.Context, w Workload) error
+}
+
+// A ConnectionPublisherFn publishes the connection details of a workload.
+type ConnectionPublisherFn func(ctx context.Context, w Workload) error
+
+// PublishConnection publishes the connection details of the supplied workload.
+func (fn ConnectionPublisherFn) PublishConnection(ctx context.Context, w Workload) error {
+	return fn(ctx, w)
+}
+
+// NopPublishConnection does not publish connection details.
+func NopPublishConnection(_ context.Context, _ Workload) error { return nil }
+
+// An APIServiceEndpointPublisher publishes the endpoint of the remote Service
+// of a workload that is packaged as a KubernetesApplication.
+type APIServiceEndpointPublisher struct {
+	client client.Client
+}
+
+// NewAPIServiceEndpointPublisher returns a ConnectionPublisher that publishes
+// the endpoint of a workload's remote Service by applying a Secret using the
+// supplied client.
+func NewAPIServiceEndpointPublisher(c client.Client) *APIServiceEndpointPublisher {
+	return &APIServiceEndpointPublisher{client: c}
+}
+
+// PublishConnection publishes the endpoint of the remote Service of the
+// supplied workload once it is known. The endpoint of a LoadBalancer Service
+// is known once its load balancer ingress is observed in the remote cluster.
+// Only the port of a NodePort Service is published, and only if its node port
+// is specified by the workload's translation.
+func (p *APIServiceEndpointPublisher) PublishConnection(ctx context.Context, w Workload) error {
+	nn, ok := connectionSecret(w)
+	if !ok {
+		return nil
+	}
+
+	l := &workloadv1alpha1.KubernetesApplicationResourceList{}
+	if err := p.client.List(ctx, l, client.InNamespace(w.GetNamespace()), client.MatchingLabels{labelKey: string(w.GetUID())}); err != nil {
+		return errors.Wrap(err, errListResources)
+	}
+
+	var data map[string][]byte
+	for i := range l.Items {
+		if data = serviceEndpoint(&l.Items[i]); data != nil {
+			break
+		}
+	}
+	if data == nil {
+		return nil
+	}
+
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name},
+		Data:       data,
+	}
+
+	// Kubernetes does not allow an object to be owned by an object in
+	// another namespace.
+	if nn.Namespace == w.GetNamespace() {
+		meta.AddOwnerReference(s, *metav1.NewControllerRef(w, w.GetObjectKind().GroupVersionKind()))
+	}
+
+	return errors.Wrap(resource.Apply(ctx, p.client, s), errApplyConnectionSecret)
+}
+
+// connectionSecret returns the namespace and name of the connection secret of
+// the supplied workload, if any.
+func connectionSecret(w Workload) (types.NamespacedName, bool) {
+	v := w.GetAnnotations()[AnnotationConnectionSecret]
+	if v == "" {
+		return types.NamespacedName{}, false
+	}
+	if i := strings.Index(v, "/"); i >= 0 {
+		return types.NamespacedName{Namespace: v[:i], Name: v[i+1:]}, true
+	}
+	return types.NamespacedName{Namespace: w.GetNamespace(), Name: v}, true
+}
+
+// serviceEndpoint returns the connection details of the Service templated by
+// the supplied KubernetesApplicationResource, or nil if it does not template a
+// Service or the Service's endpoint is not yet known.
+func serviceEndpoint(kar *workloadv1alpha1.KubernetesApplicationResource) map[string][]byte {
+	svc := &corev1.Service{}
+	if err := json.Unmarshal(kar.Spec.Template.Raw, svc); err != nil || svc.Kind != serviceKind || len(svc.Spec.Ports) == 0 {
+		return nil
+	}
+	if kar.Status.Remote != nil {
+		if err := json.Unmarshal(kar.Status.Remote.Raw, &svc.Status); err != nil {
+			return nil
+		}
+	}
+
+	port := svc.Spec.Ports[0]
+	if ing := svc.Status.LoadBalancer.Ingress; len(ing) > 0 {
+		host := ing[0].IP
+		if host == "" {
+			host = ing[0].Hostname
+		}
+		return map[string][]byte{
+			runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host),
+			runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte(strconv.Itoa(int(port.Port))),
+		}
+	}
+	if svc.Spec.Type == corev1.ServiceTypeNodePort && port.NodePort != 0 {
+		return map[string][]byte{
+			runtimev1alpha1.ResourceCredentialsSecretPortKey: []byte(strconv.Itoa(int(port.NodePort))),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestAPIServiceEndpointPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withSecret := func(v string) *workloadfake.Workload {
+		return &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{
+			Name:        workloadName,
+			Namespace:   workloadNamespace,
+			UID:         types.UID(workloadUID),
+			Annotations: map[string]string{AnnotationConnectionSecret: v},
+		}}
+	}
+
+	svc, _ := json.Marshal(&corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: serviceKind, APIVersion: serviceAPIVersion},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{{Port: 80}},
+		},
+	})
+	lb, _ := json.Marshal(&corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+		Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+	}})
+
+	// list returns a MockListFn that lists a KubernetesApplicationResource
+	// that templates a LoadBalancer Service with the supplied remote status.
+	list := func(remote []byte) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			kar := workloadv1alpha1.KubernetesApplicationResource{
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: svc}},
+			}
+			if remote != nil {
+				kar.Status.Remote = &workloadv1alpha1.RemoteStatus{Raw: remote}
+			}
+			obj.(*workloadv1alpha1.KubernetesApplicationResourceList).Items = []workloadv1alpha1.KubernetesApplicationResource{kar}
+			return nil
+		}
+	}
+
+	type args struct {
+		c client.Client
+		w Workload
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoConnectionSecret": {
+			reason: "Nothing should be published for a workload without a connection secret.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				w: &workloadfake.Workload{},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing KubernetesApplicationResources should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				w: withSecret("cool"),
+			},
+			want: errors.Wrap(errBoom, errListResources),
+		},
+		"EndpointUnknown": {
+			reason: "Nothing should be published until the remote Service's endpoint is known.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   list(nil),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				w: withSecret("cool"),
+			},
+		},
+		"ApplyError": {
+			reason: "Errors applying the connection secret should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   list(lb),
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				w: withSecret("cool"),
+			},
+			want: errors.Wrap(errBoom, errApplyConnectionSecret),
+		},
+		"Success": {
+			reason: "The remote Service's endpoint should be published to the connection secret.",
+			args: args{
+				c: &test.MockClient{
+					MockList: list(lb),
+					MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+						want := &corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "cool"},
+							Data: map[string][]byte{
+								runtimev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("10.0.0.1"),
+								runtimev1alpha1.ResourceCredentialsSecretPortKey:     []byte("80"),
+							},
+						}
+						if diff := cmp.Diff(want, obj); diff != "" {
+							return errors.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				w: withSecret("other/cool"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewAPIServiceEndpointPublisher(tc.args.c).PublishConnection(context.Background(), tc.args.w)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\np.PublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package workload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errMapDefinedKind  = "cannot determine CustomResourceDefinition of defined kind"
+	errListDefinitions = "cannot list definitions"
+	errHashDefinition  = "cannot hash definition"
+)
+
+// A DefinitionHasher returns a hash of the OAM definition of the supplied
+// kind, for example its WorkloadDefinition or TraitDefinition. The hash is
+// empty if the kind has no definition.
+type DefinitionHasher interface {
+	HashDefinition(ctx context.Context, kind schema.GroupVersionKind) (string, error)
+}
+
+// A DefinitionHasherFn is a function that satisfies the DefinitionHasher
+// interface.
+type DefinitionHasherFn func(ctx context.Context, kind schema.GroupVersionKind) (string, error)
+
+// HashDefinition returns a hash of the OAM definition of the supplied kind.
+func (fn DefinitionHasherFn) HashDefinition(ctx context.Context, kind schema.GroupVersionKind) (string, error) {
+	return fn(ctx, kind)
+}
+
+// NopHashDefinition returns an empty hash.
+func NopHashDefinition(_ context.Context, _ schema.GroupVersionKind) (string, error) {
+	return "", nil
+}
+
+// An APIDefinitionHasher hashes OAM definitions read from the API server. A
+// definition defines the kind whose CustomResourceDefinition its
+// spec.reference.name names.
+type APIDefinitionHasher struct {
+	client client.Reader
+	mapper meta.RESTMapper
+	list   schema.GroupVersionKind
+}
+
+// NewAPIDefinitionHasher returns a DefinitionHasher that hashes definitions
+// of the supplied kind, for example WorkloadDefinitions.
+func NewAPIDefinitionHasher(c client.Reader, m meta.RESTMapper, definition schema.GroupVersionKind) *APIDefinitionHasher {
+	return &APIDefinitionHasher{client: c, mapper: m, list: definition.GroupVersion().WithKind(definition.Kind + "List")}
+}
+
+// NewWorkloadDefinitionHasher returns a DefinitionHasher that hashes
+// WorkloadDefinitions.
+func NewWorkloadDefinitionHasher(c client.Reader, m meta.RESTMapper) *APIDefinitionHasher {
+	return NewAPIDefinitionHasher(c, m, oamv1alpha2.WorkloadDefinitionGroupVersionKind)
+}
+
+// NewTraitDefinitionHasher returns a DefinitionHasher that hashes
+// TraitDefinitions.
+func NewTraitDefinitionHasher(c client.Reader, m meta.RESTMapper) *APIDefinitionHasher {
+	return NewAPIDefinitionHasher(c, m, oamv1alpha2.TraitDefinitionGroupVersionKind)
+}
+
+// HashDefinition returns a hash of the definition of the supplied kind. The
+// hash covers the definition's spec and annotations, which may hold its
+// translation template.
+func (h *APIDefinitionHasher) HashDefinition(ctx context.Context, kind schema.GroupVersionKind) (string, error) {
+	m, err := h.mapper.RESTMapping(kind.GroupKind(), kind.Version)
+	if err != nil {
+		return "", errors.Wrap(err, errMapDefinedKind)
+	}
+	name := m.Resource.GroupResource().String()
+
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(h.list)
+	if err := h.client.List(ctx, l); err != nil {
+		return "", errors.Wrap(err, errListDefinitions)
+	}
+	for i := range l.Items {
+		if n, _, _ := unstructured.NestedString(l.Items[i].Object, "spec", "reference", "name"); n == name {
+			return HashDefinition(&l.Items[i])
+		}
+	}
+	return "", nil
+}
+
+// HashDefinition returns a hash of the spec and annotations of the supplied
+// definition.
+func HashDefinition(d *unstructured.Unstructured) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"annotations": d.GetAnnotations(),
+		"spec":        d.Object["spec"],
+	})
+	if err != nil {
+		return "", errors.Wrap(err, errHashDefinition)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAPIDefinitionHasher(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	definition := func(name, template string) unstructured.Unstructured {
+		d := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"reference": map[string]interface{}{"name": name}},
+		}}
+		d.SetAnnotations(map[string]string{AnnotationTranslationTemplate: template})
+		return d
+	}
+	withDefinitions := func(defs ...unstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GetKind() != "WorkloadDefinitionList" {
+				return errors.New("listed the wrong kind")
+			}
+			l.Items = defs
+			return nil
+		}
+	}
+	hash := func(d unstructured.Unstructured) string {
+		h, _ := HashDefinition(&d)
+		return h
+	}
+
+	cool := definition("cools.example.org", "apiVersion: v1")
+	other := definition("others.example.org", "apiVersion: v1")
+
+	type args struct {
+		c    client.Reader
+		kind schema.GroupVersionKind
+	}
+	type want struct {
+		hash string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownKind": {
+			reason: "Kinds whose CustomResourceDefinition cannot be determined should return an error.",
+			args:   args{kind: schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Unknown"}},
+			want: want{err: errors.Wrap(func() error {
+				_, err := mapper.RESTMapping(schema.GroupKind{Group: "example.org", Kind: "Unknown"}, "v1")
+				return err
+			}(), errMapDefinedKind)},
+		},
+		"ListError": {
+			reason: "Errors listing definitions should be returned.",
+			args: args{
+				c:    &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				kind: gvk,
+			},
+			want: want{err: errors.Wrap(errBoom, errListDefinitions)},
+		},
+		"NoDefinition": {
+			reason: "Kinds without a definition should have an empty hash.",
+			args: args{
+				c:    &test.MockClient{MockList: withDefinitions(other)},
+				kind: gvk,
+			},
+			want: want{},
+		},
+		"Definition": {
+			reason: "The hash of the definition of the kind should be returned.",
+			args: args{
+				c:    &test.MockClient{MockList: withDefinitions(other, cool)},
+				kind: gvk,
+			},
+			want: want{hash: hash(cool)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewWorkloadDefinitionHasher(tc.args.c, mapper)
+			got, err := h.HashDefinition(context.Background(), tc.args.kind)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nh.HashDefinition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hash, got); diff != "" {
+				t.Errorf("\nReason: %s\nh.HashDefinition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHashDefinition(t *testing.T) {
+	definition := func(template string) *unstructured.Unstructured {
+		d := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"reference": map[string]interface{}{"name": "cools.example.org"}},
+		}}
+		d.SetAnnotations(map[string]string{AnnotationTranslationTemplate: template})
+		return d
+	}
+
+	a, _ := HashDefinition(definition("apiVersion: v1"))
+	b, _ := HashDefinition(definition("apiVersion: v1"))
+	c, _ := HashDefinition(definition("apiVersion: v2"))
+
+	if diff := cmp.Diff(a, b); diff != "" {
+		t.Errorf("HashDefinition(...): identical definitions should have the same hash: -want, +got:\n%s", diff)
+	}
+	if a == c {
+		t.Errorf("HashDefinition(...): definitions with different translation templates should have different hashes")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errDeletePackage = "cannot delete workload package"
+)
+
+// Finalizer is added to workloads whose packages are deleted by a
+// PackageDeleter, so that packages that cannot be garbage collected are
+// deleted before their workload.
+const Finalizer = "workload.oam.crossplane.io/finalizer"
+
+// A PackageDeleter deletes the package of a workload, for example because the
+// workload was deleted or its TTL elapsed.
+type PackageDeleter interface {
+	Delete(ctx context.Context, w Workload, objs []Object, o ...client.DeleteOption) error
+}
+
+// A PackageDeleterFn deletes the package of a workload.
+type PackageDeleterFn func(ctx context.Context, w Workload, objs []Object, o ...client.DeleteOption) error
+
+// Delete the supplied package of the supplied workload.
+func (fn PackageDeleterFn) Delete(ctx context.Context, w Workload, objs []Object, o ...client.DeleteOption) error {
+	return fn(ctx, w, objs, o...)
+}
+
+// An APIPackageDeleter deletes the top-level objects of a workload's package
+// from the API server.
+type APIPackageDeleter struct {
+	client client.Writer
+}
+
+// NewAPIPackageDeleter returns a PackageDeleter that deletes the top-level
+// objects of a workload's package using the supplied client.
+func NewAPIPackageDeleter(c client.Writer) *APIPackageDeleter {
+	return &APIPackageDeleter{client: c}
+}
+
+// Delete the supplied top-level objects of a workload's package. Objects that
+// do not exist are ignored.
+func (d *APIPackageDeleter) Delete(ctx context.Context, _ Workload, objs []Object, do ...client.DeleteOption) error {
+	for _, o := range objs {
+		if err := d.client.Delete(ctx, o, do...); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeletePackage)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestAPIPackageDeleter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		c    client.Writer
+		objs []Object
+		o    []client.DeleteOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"DeleteError": {
+			reason: "Errors deleting an object of the package should be returned.",
+			args: args{
+				c:    &test.MockClient{MockDelete: test.NewMockDeleteFn(errBoom)},
+				objs: []Object{&appsv1.Deployment{}},
+			},
+			want: errors.Wrap(errBoom, errDeletePackage),
+		},
+		"NotFound": {
+			reason: "Objects of the package that do not exist should be ignored.",
+			args: args{
+				c:    &test.MockClient{MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				objs: []Object{&appsv1.Deployment{}, &appsv1.Deployment{}},
+			},
+		},
+		"DeleteOptions": {
+			reason: "The supplied delete options should be used to delete each object of the package.",
+			args: args{
+				c: &test.MockClient{MockDelete: func(_ context.Context, _ runtime.Object, opts ...client.DeleteOption) error {
+					do := &client.DeleteOptions{}
+					do.ApplyOptions(opts)
+					if do.PropagationPolicy == nil || *do.PropagationPolicy != metav1.DeletePropagationOrphan {
+						return errBoom
+					}
+					return nil
+				}},
+				objs: []Object{&appsv1.Deployment{}},
+				o:    []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationOrphan)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewAPIPackageDeleter(tc.args.c).Delete(context.Background(), &workloadfake.Workload{}, tc.args.objs, tc.args.o...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
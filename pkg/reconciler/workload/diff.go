@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errObjectMeta     = "cannot access object metadata"
+	errGetCurrent     = "cannot get current object"
+	errDiffObject     = "cannot compare current and desired object"
+	errUnknownApplied = "applied object is not of the same type as its current state"
+)
+
+// serverFields are the metadata fields that are set by the API server. They
+// differ between an object as it was rendered and as it is stored, and are
+// never changed by applying an object.
+var serverFields = []string{"creationTimestamp", "resourceVersion", "generation", "uid", "selfLink", "managedFields"}
+
+// A DiffSuppressingApplicator applies objects using another Applicator only if
+// doing so would meaningfully change them. Applying an object that exists
+// merge patches it, so an object is unchanged if merge patching its current
+// state with its desired state does not change anything but its status and
+// the metadata set by the API server.
+type DiffSuppressingApplicator struct {
+	wrapped resource.Applicator
+}
+
+// NewDiffSuppressingApplicator returns an Applicator that applies objects
+// using the supplied Applicator only if doing so would change them.
+func NewDiffSuppressingApplicator(a resource.Applicator) *DiffSuppressingApplicator {
+	return &DiffSuppressingApplicator{wrapped: a}
+}
+
+// Apply the supplied object if doing so would change it. The supplied options
+// are passed to the wrapped Applicator, and are also used to determine the
+// object's desired state. The object is updated to reflect the state of the
+// API server, whether or not it is applied.
+func (a *DiffSuppressingApplicator) Apply(ctx context.Context, c client.Client, o runtime.Object, ao ...resource.ApplyOption) error {
+	m, ok := o.(metav1.Object)
+	if !ok {
+		return errors.New(errObjectMeta)
+	}
+
+	current := o.DeepCopyObject()
+	err := c.Get(ctx, types.NamespacedName{Namespace: m.GetNamespace(), Name: m.GetName()}, current)
+	if kerrors.IsNotFound(err) {
+		return a.wrapped.Apply(ctx, c, o, ao...)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetCurrent)
+	}
+
+	desired := o.DeepCopyObject()
+	for _, fn := range ao {
+		if err := fn(ctx, current.DeepCopyObject(), desired); err != nil {
+			return err
+		}
+	}
+
+	changed, err := changes(current, desired)
+	if err != nil {
+		return errors.Wrap(err, errDiffObject)
+	}
+	if changed {
+		return a.wrapped.Apply(ctx, c, o, ao...)
+	}
+
+	ov, cv := reflect.ValueOf(o), reflect.ValueOf(current)
+	if ov.Kind() != reflect.Ptr || ov.Type() != cv.Type() {
+		return errors.New(errUnknownApplied)
+	}
+	ov.Elem().Set(cv.Elem())
+	return nil
+}
+
+// changes returns true if merge patching the supplied current object with the
+// supplied desired object would change anything but its status and the
+// metadata set by the API server.
+func changes(current, desired runtime.Object) (bool, error) {
+	cj, err := json.Marshal(current)
+	if err != nil {
+		return false, err
+	}
+	dj, err := json.Marshal(desired)
+	if err != nil {
+		return false, err
+	}
+	pj, err := jsonpatch.MergePatch(cj, dj)
+	if err != nil {
+		return false, err
+	}
+
+	cm, pm := map[string]interface{}{}, map[string]interface{}{}
+	if err := json.Unmarshal(cj, &cm); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(pj, &pm); err != nil {
+		return false, err
+	}
+	withoutServerFields(cm)
+	withoutServerFields(pm)
+	return !reflect.DeepEqual(cm, pm), nil
+}
+
+func withoutServerFields(o map[string]interface{}) {
+	delete(o, "status")
+	md, ok := o["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, f := range serverFields {
+		delete(md, f)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ resource.Applicator = &DiffSuppressingApplicator{}
+
+func TestDiffSuppressingApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	kubeApp := func(labels map[string]string) *workloadv1alpha1.KubernetesApplication {
+		return &workloadv1alpha1.KubernetesApplication{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cool-app", Labels: labels},
+			Spec: workloadv1alpha1.KubernetesApplicationSpec{
+				Target: &workloadv1alpha1.KubernetesTargetReference{Name: "cool-target"},
+			},
+		}
+	}
+
+	// stored returns the supplied application as it is stored by the API
+	// server, with metadata set by the server and a status.
+	stored := func(a *workloadv1alpha1.KubernetesApplication) *workloadv1alpha1.KubernetesApplication {
+		a.SetResourceVersion("42")
+		a.SetUID(types.UID("cool-uid"))
+		a.SetGeneration(2)
+		a.SetCreationTimestamp(metav1.Now())
+		a.Status.State = workloadv1alpha1.KubernetesApplicationStateSubmitted
+		return a
+	}
+
+	current := stored(kubeApp(map[string]string{"cool": "label"}))
+
+	getCurrent := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		current.DeepCopyInto(obj.(*workloadv1alpha1.KubernetesApplication))
+		return nil
+	})
+
+	type args struct {
+		c  client.Client
+		o  runtime.Object
+		ao []resource.ApplyOption
+	}
+
+	type want struct {
+		o       runtime.Object
+		applied bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "An object that does not exist should be applied.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-app"))},
+				o: kubeApp(nil),
+			},
+			want: want{o: kubeApp(nil), applied: true},
+		},
+		"GetError": {
+			reason: "Errors getting the current object should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				o: kubeApp(nil),
+			},
+			want: want{o: kubeApp(nil), err: errors.Wrap(errBoom, errGetCurrent)},
+		},
+		"ApplyOptionError": {
+			reason: "Errors returned by apply options should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: getCurrent},
+				o: kubeApp(nil),
+				ao: []resource.ApplyOption{func(_ context.Context, _, _ runtime.Object) error {
+					return errBoom
+				}},
+			},
+			want: want{o: kubeApp(nil), err: errBoom},
+		},
+		"Unchanged": {
+			reason: "An object that differs only in fields set by the API server should not be applied, but updated to reflect them.",
+			args: args{
+				c: &test.MockClient{MockGet: getCurrent},
+				o: kubeApp(map[string]string{"cool": "label"}),
+			},
+			want: want{o: current},
+		},
+		"OmittedFields": {
+			reason: "Fields that are omitted from the desired object are not removed by applying it, so they should not be a change.",
+			args: args{
+				c: &test.MockClient{MockGet: getCurrent},
+				o: func() runtime.Object {
+					a := kubeApp(nil)
+					a.Spec.Target = nil
+					return a
+				}(),
+			},
+			want: want{o: current},
+		},
+		"Changed": {
+			reason: "An object whose desired state differs from its current state should be applied.",
+			args: args{
+				c: &test.MockClient{MockGet: getCurrent},
+				o: kubeApp(map[string]string{"cool": "new-label"}),
+			},
+			want: want{o: kubeApp(map[string]string{"cool": "new-label"}), applied: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applied := false
+			a := NewDiffSuppressingApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+				applied = true
+				return nil
+			}))
+
+			err := a.Apply(context.Background(), tc.args.c, tc.args.o, tc.args.ao...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\na.Apply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\nReason: %s\na.Apply(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.o); diff != "" {
+				t.Errorf("\nReason: %s\na.Apply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	errReadFixtures = "cannot read fixtures"
+	errReadFixture  = "cannot read fixture"
+	errParseFixture = "cannot parse fixture"
+)
+
+// A Fixture is a workload reconciler test case declared in YAML, for example:
+//
+//	reason: A workload with an invalid TTL should not be translated.
+//	workload:
+//	  name: cool
+//	  annotations:
+//	    workload.oam.crossplane.io/ttl: forever
+//	want:
+//	  requeueAfter: 30s
+//	  synced:
+//	    reason: ReconcileError
+type Fixture struct {
+	// Reason the test case exists.
+	Reason string `json:"reason"`
+
+	// Workload returned when the workload under reconciliation is read. The
+	// workload is not found if it is omitted.
+	Workload *Workload `json:"workload,omitempty"`
+
+	// Errors returned by the client, if any.
+	Errors FixtureErrors `json:"errors,omitempty"`
+
+	// Want is the expected outcome of the reconcile.
+	Want FixtureWant `json:"want"`
+}
+
+// FixtureErrors are the errors returned by a fixture's client.
+type FixtureErrors struct {
+	Get          string `json:"get,omitempty"`
+	Update       string `json:"update,omitempty"`
+	StatusUpdate string `json:"statusUpdate,omitempty"`
+}
+
+// FixtureWant is the expected outcome of a fixture's reconcile.
+type FixtureWant struct {
+	// RequeueAfter is the expected requeue delay.
+	RequeueAfter metav1.Duration `json:"requeueAfter,omitempty"`
+
+	// Error is the expected error message, if any.
+	Error string `json:"error,omitempty"`
+
+	// Synced is the expected Synced condition of the workload's most recent
+	// status update, if any. Only its reason and message are compared.
+	Synced *v1alpha1.Condition `json:"synced,omitempty"`
+}
+
+// LoadFixtures loads each YAML file in the supplied directory as a Fixture,
+// keyed by its file name without extension, so that fixtures may be used as
+// the cases of a table-driven test.
+func LoadFixtures(dir string) (map[string]Fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFixtures)
+	}
+
+	fixtures := make(map[string]Fixture, len(files))
+	for _, file := range files {
+		b, err := ioutil.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, errors.Wrap(err, errReadFixture)
+		}
+		f := Fixture{}
+		if err := yaml.Unmarshal(b, &f); err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errParseFixture, file)
+		}
+		fixtures[strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))] = f
+	}
+	return fixtures, nil
+}
+
+// Client returns a mock client that behaves as the fixture declares. The
+// workload passed to the client's most recent status update is stored in the
+// supplied workload.
+func (f Fixture) Client(updated *Workload) *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			if f.Errors.Get != "" {
+				return errors.New(f.Errors.Get)
+			}
+			w, ok := obj.(*Workload)
+			if !ok || f.Workload == nil {
+				return kerrors.NewNotFound(schema.GroupResource{}, "")
+			}
+			*w = *f.Workload.DeepCopyObject().(*Workload)
+			return nil
+		},
+		MockUpdate: test.NewMockUpdateFn(fixtureError(f.Errors.Update)),
+		MockCreate: test.NewMockCreateFn(nil),
+		MockDelete: test.NewMockDeleteFn(nil),
+		MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			if w, ok := obj.(*Workload); ok && updated != nil {
+				*updated = *w.DeepCopyObject().(*Workload)
+			}
+			return fixtureError(f.Errors.StatusUpdate)
+		},
+	}
+}
+
+func fixtureError(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
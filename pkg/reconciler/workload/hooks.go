@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+)
+
+// A PrePackageHook is called with the translation of a workload before it is
+// packaged, for example to check the translation against a policy. Returning
+// an error stops the workload from being packaged and applied.
+type PrePackageHook interface {
+	PrePackage(ctx context.Context, w Workload, objs []Object) error
+}
+
+// A PrePackageHookFn is called with the translation of a workload before it is
+// packaged.
+type PrePackageHookFn func(ctx context.Context, w Workload, objs []Object) error
+
+// PrePackage calls the function with the supplied translation of the supplied
+// workload.
+func (fn PrePackageHookFn) PrePackage(ctx context.Context, w Workload, objs []Object) error {
+	return fn(ctx, w, objs)
+}
+
+// A PostApplyHook is called with the package of a workload once it has been
+// applied, for example to send a notification. Returning an error marks the
+// workload as failing to reconcile, but does not undo the apply.
+type PostApplyHook interface {
+	PostApply(ctx context.Context, w Workload, objs []Object) error
+}
+
+// A PostApplyHookFn is called with the package of a workload once it has been
+// applied.
+type PostApplyHookFn func(ctx context.Context, w Workload, objs []Object) error
+
+// PostApply calls the function with the supplied package of the supplied
+// workload.
+func (fn PostApplyHookFn) PostApply(ctx context.Context, w Workload, objs []Object) error {
+	return fn(ctx, w, objs)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package workload
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errGetRenderInputs = "cannot get render inputs"
+	errSetRenderInputs = "cannot set render inputs"
+)
+
+// AnnotationRenderInputs records the RenderInputs used to produce a workload
+// translation.
+const AnnotationRenderInputs = "workload.oam.crossplane.io/render-inputs"
+
+// RenderInputs are the inputs that were used to render a workload translation.
+// Rendering the same inputs again produces the same translation.
+type RenderInputs struct {
+	// ControllerVersion is the version of the controller that rendered the
+	// workload.
+	ControllerVersion string `json:"controllerVersion"`
+
+	// FeatureGates that were enabled when the workload was rendered.
+	FeatureGates []string `json:"featureGates,omitempty"`
+
+	// WorkloadGeneration is the generation of the rendered workload.
+	WorkloadGeneration int64 `json:"workloadGeneration"`
+
+	// WorkloadDefinitionHash is a hash of the WorkloadDefinition of the
+	// rendered workload's kind, if it has one.
+	WorkloadDefinitionHash string `json:"workloadDefinitionHash,omitempty"`
+
+	// TraitGenerations are the generations of the traits that modified the
+	// translation, keyed by lower case group kind and name.
+	TraitGenerations map[string]int64 `json:"traitGenerations,omitempty"`
+
+	// TraitDefinitionHashes are hashes of the TraitDefinitions of the kinds
+	// of the traits that modified the translation, keyed like
+	// TraitGenerations. Traits whose kind has no TraitDefinition are omitted.
+	TraitDefinitionHashes map[string]string `json:"traitDefinitionHashes,omitempty"`
+
+	// RenderedAt is the time at which the workload generation was first
+	// rendered.
+	RenderedAt *metav1.Time `json:"renderedAt,omitempty"`
+}
+
+// GetRenderInputs returns the RenderInputs recorded on the supplied object, if
+// any.
+func GetRenderInputs(o metav1.Object) (*RenderInputs, error) {
+	raw, ok := o.GetAnnotations()[AnnotationRenderInputs]
+	if !ok {
+		return nil, nil
+	}
+	ri := &RenderInputs{}
+	return ri, errors.Wrap(json.Unmarshal([]byte(raw), ri), errGetRenderInputs)
+}
+
+// SetRenderInputs records the supplied RenderInputs on the supplied object.
+func SetRenderInputs(o metav1.Object, ri *RenderInputs) error {
+	b, err := json.Marshal(ri)
+	if err != nil {
+		return errors.Wrap(err, errSetRenderInputs)
+	}
+	meta.AddAnnotations(o, map[string]string{AnnotationRenderInputs: string(b)})
+	return nil
+}
+
+// PreserveRenderInputs preserves the time at which a workload generation was
+// first rendered, and the generations and definition hashes of the traits that
+// have modified its translation since, when the translation of that workload generation is
+// applied again.
+func PreserveRenderInputs() resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, ok := current.(metav1.Object)
+		if !ok {
+			return nil
+		}
+		d, ok := desired.(metav1.Object)
+		if !ok {
+			return nil
+		}
+
+		dri, err := GetRenderInputs(d)
+		if err != nil || dri == nil {
+			return err
+		}
+		cri, err := GetRenderInputs(c)
+		if err != nil {
+			return err
+		}
+
+		now := metav1.Now()
+		dri.RenderedAt = &now
+		if cri != nil && cri.WorkloadGeneration == dri.WorkloadGeneration {
+			if cri.RenderedAt != nil {
+				dri.RenderedAt = cri.RenderedAt
+			}
+			dri.TraitGenerations = cri.TraitGenerations
+			dri.TraitDefinitionHashes = cri.TraitDefinitionHashes
+		}
+		return SetRenderInputs(d, dri)
+	}
+}
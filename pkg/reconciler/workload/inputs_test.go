@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package workload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRenderInputs(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ri     *RenderInputs
+	}{
+		"NotRecorded": {
+			reason: "No RenderInputs should be returned when none were recorded.",
+		},
+		"Recorded": {
+			reason: "Recorded RenderInputs should be returned unchanged.",
+			ri: &RenderInputs{
+				ControllerVersion:  "v0.1.0",
+				FeatureGates:       []string{"CoolFeature"},
+				WorkloadGeneration: 3,
+				TraitGenerations:   map[string]int64{"manualscalertrait.core.oam.dev/cool": 2},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{}
+			if tc.ri != nil {
+				if err := SetRenderInputs(o, tc.ri); err != nil {
+					t.Fatalf("SetRenderInputs(...): %s", err)
+				}
+			}
+
+			got, err := GetRenderInputs(o)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nGetRenderInputs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.ri, got); diff != "" {
+				t.Errorf("\nReason: %s\nGetRenderInputs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPreserveRenderInputs(t *testing.T) {
+	rendered := metav1.NewTime(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	traits := map[string]int64{"manualscalertrait.core.oam.dev/cool": 2}
+	definitions := map[string]string{"manualscalertrait.core.oam.dev/cool": "cool-hash"}
+
+	withInputs := func(ri *RenderInputs) *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		_ = SetRenderInputs(d, ri)
+		return d
+	}
+
+	cases := map[string]struct {
+		reason  string
+		current *appsv1.Deployment
+		desired *appsv1.Deployment
+		want    *RenderInputs
+	}{
+		"SameGeneration": {
+			reason:  "The render time and trait generations and definition hashes of the current generation should be preserved.",
+			current: withInputs(&RenderInputs{WorkloadGeneration: 3, TraitGenerations: traits, TraitDefinitionHashes: definitions, RenderedAt: &rendered}),
+			desired: withInputs(&RenderInputs{WorkloadGeneration: 3, WorkloadDefinitionHash: "cool-hash"}),
+			want:    &RenderInputs{WorkloadGeneration: 3, WorkloadDefinitionHash: "cool-hash", TraitGenerations: traits, TraitDefinitionHashes: definitions, RenderedAt: &rendered},
+		},
+		"NoRenderInputs": {
+			reason:  "Objects without render inputs should be unchanged.",
+			current: withInputs(&RenderInputs{WorkloadGeneration: 3, RenderedAt: &rendered}),
+			desired: &appsv1.Deployment{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PreserveRenderInputs()(context.Background(), tc.current, tc.desired)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPreserveRenderInputs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			got, _ := GetRenderInputs(tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nPreserveRenderInputs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	t.Run("NewGeneration", func(t *testing.T) {
+		desired := withInputs(&RenderInputs{WorkloadGeneration: 4})
+		if err := PreserveRenderInputs()(context.Background(), withInputs(&RenderInputs{WorkloadGeneration: 3, TraitGenerations: traits, RenderedAt: &rendered}), desired); err != nil {
+			t.Fatalf("PreserveRenderInputs(...): %s", err)
+		}
+		got, _ := GetRenderInputs(desired)
+		if got.RenderedAt == nil || !got.RenderedAt.After(rendered.Time) {
+			t.Errorf("PreserveRenderInputs(...): a new workload generation should be stamped with a new render time, got %v", got.RenderedAt)
+		}
+		if got.TraitGenerations != nil {
+			t.Errorf("PreserveRenderInputs(...): trait generations of a previous workload generation should not be preserved, got %v", got.TraitGenerations)
+		}
+	})
+}
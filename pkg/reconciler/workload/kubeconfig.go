@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errParseSecretReference = "secret reference must be of the form namespace/name"
+	errGetKubeconfigSecret  = "cannot get kubeconfig secret"
+	errNoKubeconfig         = "kubeconfig secret has no kubeconfig key"
+	errParseKubeconfig      = "cannot parse kubeconfig"
+	errNewRemoteClient      = "cannot create remote cluster client"
+)
+
+// KubeconfigKey is the key of a kubeconfig Secret under which the kubeconfig
+// of a remote cluster is stored.
+const KubeconfigKey = "kubeconfig"
+
+// ParseSecretReference parses a reference to a Secret of the form
+// namespace/name.
+func ParseSecretReference(ref string) (types.NamespacedName, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, errors.Errorf("%s: %q", errParseSecretReference, ref)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// A ClientFn returns a client of the cluster described by the supplied
+// kubeconfig.
+type ClientFn func(kubeconfig []byte) (client.Client, error)
+
+// NewKubeconfigClient returns a ClientFn that returns clients using the
+// supplied scheme.
+func NewKubeconfigClient(s *runtime.Scheme) ClientFn {
+	return func(kubeconfig []byte) (client.Client, error) {
+		cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseKubeconfig)
+		}
+		c, err := client.New(cfg, client.Options{Scheme: s})
+		return c, errors.Wrap(err, errNewRemoteClient)
+	}
+}
+
+// A KubeconfigApplicatorOption configures a KubeconfigApplicator.
+type KubeconfigApplicatorOption func(*KubeconfigApplicator)
+
+// WithClientFn specifies how the KubeconfigApplicator should create clients
+// of the remote cluster.
+func WithClientFn(fn ClientFn) KubeconfigApplicatorOption {
+	return func(a *KubeconfigApplicator) {
+		a.newClient = fn
+	}
+}
+
+// WithRemoteApplicator specifies how the KubeconfigApplicator should apply
+// objects to the remote cluster.
+func WithRemoteApplicator(ra resource.Applicator) KubeconfigApplicatorOption {
+	return func(a *KubeconfigApplicator) {
+		a.wrapped = ra
+	}
+}
+
+// A KubeconfigApplicator applies objects directly to the remote cluster
+// described by the kubeconfig stored in a Secret, rather than to the cluster
+// of the client it is passed. This allows workloads to be deployed without
+// the Crossplane workload API. The remote client is created again whenever
+// the Secret changes.
+type KubeconfigApplicator struct {
+	local     client.Reader
+	secret    types.NamespacedName
+	newClient ClientFn
+	wrapped   resource.Applicator
+
+	mx      sync.Mutex
+	version string
+	remote  client.Client
+}
+
+// NewKubeconfigApplicator returns an Applicator that applies objects to the
+// remote cluster described by the kubeconfig in the supplied Secret, which is
+// read using the supplied client.
+func NewKubeconfigApplicator(c client.Reader, s *runtime.Scheme, secret types.NamespacedName, o ...KubeconfigApplicatorOption) *KubeconfigApplicator {
+	a := &KubeconfigApplicator{
+		local:     c,
+		secret:    secret,
+		newClient: NewKubeconfigClient(s),
+		wrapped:   NewDiffSuppressingApplicator(resource.ApplyFn(resource.Apply)),
+	}
+
+	for _, ao := range o {
+		ao(a)
+	}
+
+	return a
+}
+
+// Apply the supplied object to the remote cluster. The supplied client is
+// ignored. Owner references are removed from the object before it is applied,
+// because they refer to objects in the local cluster; the remote cluster would
+// garbage collect an object whose owner it cannot find.
+func (a *KubeconfigApplicator) Apply(ctx context.Context, _ client.Client, o runtime.Object, ao ...resource.ApplyOption) error {
+	rc, err := a.client(ctx)
+	if err != nil {
+		return err
+	}
+	if m, ok := o.(metav1.Object); ok {
+		m.SetOwnerReferences(nil)
+	}
+	return a.wrapped.Apply(ctx, rc, o, ao...)
+}
+
+// Delete the supplied top-level objects of a workload's package from the
+// remote cluster. Objects that do not exist are ignored. Objects applied by a
+// KubeconfigApplicator have no owner references, so they must be deleted by it
+// rather than garbage collected.
+func (a *KubeconfigApplicator) Delete(ctx context.Context, w Workload, objs []Object, o ...client.DeleteOption) error {
+	rc, err := a.client(ctx)
+	if err != nil {
+		return err
+	}
+	return NewAPIPackageDeleter(rc).Delete(ctx, w, objs, o...)
+}
+
+// client returns a client of the remote cluster, creating it if the
+// kubeconfig Secret has changed since the client was last created.
+func (a *KubeconfigApplicator) client(ctx context.Context) (client.Client, error) {
+	s := &corev1.Secret{}
+	if err := a.local.Get(ctx, a.secret, s); err != nil {
+		return nil, errors.Wrap(err, errGetKubeconfigSecret)
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if a.remote != nil && a.version == s.GetResourceVersion() {
+		return a.remote, nil
+	}
+	kc, ok := s.Data[KubeconfigKey]
+	if !ok {
+		return nil, errors.New(errNoKubeconfig)
+	}
+	rc, err := a.newClient(kc)
+	if err != nil {
+		return nil, err
+	}
+	a.remote, a.version = rc, s.GetResourceVersion()
+	return rc, nil
+}
+
+// NameRemoteObjects is an ObjectNamer for objects that are applied directly to
+// a remote cluster by a KubeconfigApplicator. Objects keep the names they were
+// translated with, and are created in the remote namespace with the same name
+// as their workload's namespace unless they specify one.
+func NameRemoteObjects(w Workload, o Object) {
+	if o.GetNamespace() == "" {
+		o.SetNamespace(w.GetNamespace())
+	}
+}
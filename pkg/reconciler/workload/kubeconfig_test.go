@@ -0,0 +1,269 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	type want struct {
+		nn  types.NamespacedName
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ref    string
+		want   want
+	}{
+		"Valid": {
+			reason: "A namespace/name reference should be parsed.",
+			ref:    "cool-namespace/cool-secret",
+			want:   want{nn: types.NamespacedName{Namespace: "cool-namespace", Name: "cool-secret"}},
+		},
+		"NoNamespace": {
+			reason: "A reference without a namespace should return an error.",
+			ref:    "cool-secret",
+			want:   want{err: errors.Errorf("%s: %q", errParseSecretReference, "cool-secret")},
+		},
+		"EmptyName": {
+			reason: "A reference with an empty name should return an error.",
+			ref:    "cool-namespace/",
+			want:   want{err: errors.Errorf("%s: %q", errParseSecretReference, "cool-namespace/")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			nn, err := ParseSecretReference(tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nParseSecretReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nn, nn); diff != "" {
+				t.Errorf("\nReason: %s\nParseSecretReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeconfigApplicator(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := types.NamespacedName{Namespace: "cool-namespace", Name: "cool-secret"}
+	remote := &test.MockClient{}
+
+	secret := func(data map[string][]byte) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			s := obj.(*corev1.Secret)
+			s.SetResourceVersion("1")
+			s.Data = data
+			return nil
+		})
+	}
+	kubeconfig := map[string][]byte{KubeconfigKey: []byte("cool-kubeconfig")}
+
+	owned := func() *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool-cm"}}
+		cm.SetOwnerReferences([]metav1.OwnerReference{{Name: "cool-workload"}})
+		return cm
+	}
+
+	// applyToRemote returns an error unless it is passed the remote client
+	// and an object without owner references.
+	applyToRemote := resource.ApplyFn(func(_ context.Context, c client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+		if c != remote {
+			return errors.New("applied with the wrong client")
+		}
+		if refs := o.(metav1.Object).GetOwnerReferences(); len(refs) != 0 {
+			return errors.New("applied with owner references")
+		}
+		return nil
+	})
+
+	type args struct {
+		local client.Reader
+		o     []KubeconfigApplicatorOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"GetSecretError": {
+			reason: "Errors getting the kubeconfig Secret should be returned.",
+			args: args{
+				local: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: errors.Wrap(errBoom, errGetKubeconfigSecret),
+		},
+		"NoKubeconfig": {
+			reason: "A Secret without a kubeconfig should return an error.",
+			args: args{
+				local: &test.MockClient{MockGet: secret(nil)},
+			},
+			want: errors.New(errNoKubeconfig),
+		},
+		"NewClientError": {
+			reason: "Errors creating a client of the remote cluster should be returned.",
+			args: args{
+				local: &test.MockClient{MockGet: secret(kubeconfig)},
+				o: []KubeconfigApplicatorOption{
+					WithClientFn(func(_ []byte) (client.Client, error) { return nil, errBoom }),
+				},
+			},
+			want: errBoom,
+		},
+		"Success": {
+			reason: "Objects should be applied to the remote cluster without owner references.",
+			args: args{
+				local: &test.MockClient{MockGet: secret(kubeconfig)},
+				o: []KubeconfigApplicatorOption{
+					WithClientFn(func(kc []byte) (client.Client, error) {
+						if string(kc) != "cool-kubeconfig" {
+							return nil, errors.New("wrong kubeconfig")
+						}
+						return remote, nil
+					}),
+					WithRemoteApplicator(applyToRemote),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewKubeconfigApplicator(tc.args.local, runtime.NewScheme(), ref, tc.args.o...)
+			err := a.Apply(context.Background(), &test.MockClient{}, owned())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\na.Apply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeconfigApplicatorDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := types.NamespacedName{Namespace: "cool-namespace", Name: "cool-secret"}
+	local := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{KubeconfigKey: []byte("cool-kubeconfig")}
+		return nil
+	})}
+
+	type args struct {
+		local client.Reader
+		o     []KubeconfigApplicatorOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"GetSecretError": {
+			reason: "Errors getting the kubeconfig Secret should be returned.",
+			args: args{
+				local: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: errors.Wrap(errBoom, errGetKubeconfigSecret),
+		},
+		"DeleteError": {
+			reason: "Errors deleting an object from the remote cluster should be returned.",
+			args: args{
+				local: local,
+				o: []KubeconfigApplicatorOption{
+					WithClientFn(func(_ []byte) (client.Client, error) {
+						return &test.MockClient{MockDelete: test.NewMockDeleteFn(errBoom)}, nil
+					}),
+				},
+			},
+			want: errors.Wrap(errBoom, errDeletePackage),
+		},
+		"Success": {
+			reason: "Objects should be deleted from the remote cluster.",
+			args: args{
+				local: local,
+				o: []KubeconfigApplicatorOption{
+					WithClientFn(func(_ []byte) (client.Client, error) {
+						return &test.MockClient{MockDelete: test.NewMockDeleteFn(nil)}, nil
+					}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewKubeconfigApplicator(tc.args.local, runtime.NewScheme(), ref, tc.args.o...)
+			err := a.Delete(context.Background(), nil, []Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool-cm"}}})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\na.Delete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeconfigApplicatorCachesClient(t *testing.T) {
+	version := "1"
+	local := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetResourceVersion(version)
+		s.Data = map[string][]byte{KubeconfigKey: []byte("cool-kubeconfig")}
+		return nil
+	})}
+
+	created := 0
+	a := NewKubeconfigApplicator(local, runtime.NewScheme(), types.NamespacedName{Namespace: "cool-namespace", Name: "cool-secret"},
+		WithClientFn(func(_ []byte) (client.Client, error) {
+			created++
+			return &test.MockClient{}, nil
+		}),
+		WithRemoteApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+			return nil
+		})),
+	)
+
+	apply := func() {
+		if err := a.Apply(context.Background(), nil, &corev1.ConfigMap{}); err != nil {
+			t.Fatalf("a.Apply(...): %s", err)
+		}
+	}
+
+	apply()
+	apply()
+	if created != 1 {
+		t.Errorf("\nReason: A client should be reused until the Secret changes.\ncreated: want 1, got %d", created)
+	}
+
+	version = "2"
+	apply()
+	if created != 2 {
+		t.Errorf("\nReason: A client should be created again when the Secret changes.\ncreated: want 2, got %d", created)
+	}
+}
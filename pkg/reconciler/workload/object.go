@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errWrapInObject      = "unable to wrap object in provider-kubernetes Object"
+	errListObjects       = "cannot list provider-kubernetes Objects of workload"
+	errDeleteObject      = "cannot delete provider-kubernetes Object of workload"
+	errObjectOfOther     = "existing provider-kubernetes Object belongs to another workload"
+	errObjectNotLabelled = "existing provider-kubernetes Object has no workload label; set the adoption policy to Adopt or Orphan to apply it"
+)
+
+// ObjectGroupVersionKind is the GroupVersionKind of a provider-kubernetes
+// Object.
+var ObjectGroupVersionKind = schema.GroupVersionKind{Group: "kubernetes.crossplane.io", Version: "v1alpha1", Kind: "Object"}
+
+// ObjectWrapper returns a TranslationWrapper that wraps each translated object
+// in a provider-kubernetes Object that uses the supplied ProviderConfig.
+// Objects are cluster scoped and must be named by NameObjects rather than
+// after their workload. Kubernetes does not allow a cluster scoped object to be
+// owned by a namespaced object, so Objects are labelled with the UID of their
+// workload instead, and must be deleted by an ObjectDeleter.
+func ObjectWrapper(providerConfig string) TranslationWrapper {
+	return func(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+		if objs == nil {
+			return nil, nil
+		}
+
+		wrapped := make([]Object, 0, len(objs))
+		for _, o := range objs {
+			// The manifest of an Object must specify the namespace of
+			// a namespaced object.
+			if o.GetNamespace() == "" {
+				o.SetNamespace(w.GetNamespace())
+			}
+
+			b, err := json.Marshal(o)
+			if err != nil {
+				return nil, errors.Wrap(err, errWrapInObject)
+			}
+			manifest := map[string]interface{}{}
+			if err := json.Unmarshal(b, &manifest); err != nil {
+				return nil, errors.Wrap(err, errWrapInObject)
+			}
+
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"forProvider":       map[string]interface{}{"manifest": manifest},
+					"providerConfigRef": map[string]interface{}{"name": providerConfig},
+				},
+			}}
+			u.SetGroupVersionKind(ObjectGroupVersionKind)
+			u.SetName(fmt.Sprintf("%s-%s-%s-%s", w.GetNamespace(), w.GetName(), o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)))
+			u.SetLabels(map[string]string{labelKey: string(w.GetUID())})
+			wrapped = append(wrapped, u)
+		}
+
+		return wrapped, nil
+	}
+}
+
+// NameObjects is an ObjectNamer for the provider-kubernetes Objects produced
+// by ObjectWrapper. Objects keep the names they were given when they were
+// wrapped, and are cluster scoped.
+func NameObjects(_ Workload, o Object) {
+	o.SetNamespace("")
+}
+
+// ObjectsMustMatch returns an ApplyOption that returns an error if the current
+// provider-kubernetes Object is labelled with the UID of a workload other than
+// that of the desired Object. Objects cannot be controlled by their workload,
+// so current Objects that have no workload label are handled per the supplied
+// AdoptionPolicy. The controller reference that earlier versions of the addon
+// set on Objects is removed.
+func ObjectsMustMatch(p AdoptionPolicy) resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, ok := current.(metav1.Object)
+		if !ok {
+			return errors.New(errObjectMeta)
+		}
+		d, ok := desired.(metav1.Object)
+		if !ok {
+			return errors.New(errObjectMeta)
+		}
+
+		uid, labelled := c.GetLabels()[labelKey]
+		switch {
+		case uid == d.GetLabels()[labelKey]:
+			withoutController(c, d)
+			return nil
+		case labelled:
+			return errors.New(errObjectOfOther)
+		}
+
+		switch p {
+		case AdoptionPolicyAdopt:
+			withoutController(c, d)
+			return nil
+		case AdoptionPolicyOrphan:
+			return errOrphaned{name: c.GetName()}
+		default:
+			return errors.New(errObjectNotLabelled)
+		}
+	}
+}
+
+// withoutController sets the owner references of the desired object to those
+// of the current object other than its controller, if it has one. An empty
+// list is set rather than none so that merge patching the current object
+// removes its controller.
+func withoutController(current, desired metav1.Object) {
+	refs := []metav1.OwnerReference{}
+	for _, ref := range current.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) != len(current.GetOwnerReferences()) {
+		desired.SetOwnerReferences(refs)
+	}
+}
+
+// An ObjectDeleter deletes the provider-kubernetes Objects of a workload,
+// which are not garbage collected when the workload is deleted.
+type ObjectDeleter struct {
+	client client.Client
+}
+
+// NewObjectDeleter returns a PackageDeleter that deletes the
+// provider-kubernetes Objects of a workload using the supplied client.
+func NewObjectDeleter(c client.Client) *ObjectDeleter {
+	return &ObjectDeleter{client: c}
+}
+
+// Delete every provider-kubernetes Object labelled with the UID of the
+// supplied workload, including Objects its current package no longer
+// contains. The supplied objects are ignored.
+func (d *ObjectDeleter) Delete(ctx context.Context, w Workload, _ []Object, o ...client.DeleteOption) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(ObjectGroupVersionKind.GroupVersion().WithKind(ObjectGroupVersionKind.Kind + "List"))
+	if err := d.client.List(ctx, l, client.MatchingLabels{labelKey: string(w.GetUID())}); err != nil {
+		return errors.Wrap(err, errListObjects)
+	}
+	for i := range l.Items {
+		if err := d.client.Delete(ctx, &l.Items[i], o...); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteObject)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestObjectWrapper(t *testing.T) {
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: workloadNamespace, UID: types.UID(workloadUID)}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilObject": {
+			reason: "Nil object should immediately return nil.",
+			args: args{
+				w: w,
+			},
+			want: want{},
+		},
+		"Success": {
+			reason: "Each object should be wrapped in an Object in the workload's namespace.",
+			args: args{
+				w: w,
+				o: []Object{&appsv1.Deployment{
+					TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				}},
+			},
+			want: want{result: []Object{&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kubernetes.crossplane.io/v1alpha1",
+				"kind":       "Object",
+				"metadata": map[string]interface{}{
+					"name":   workloadNamespace + "-" + workloadName + "-cool-deployment",
+					"labels": map[string]interface{}{labelKey: workloadUID},
+				},
+				"spec": map[string]interface{}{
+					"forProvider": map[string]interface{}{"manifest": map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name":              "cool",
+							"namespace":         workloadNamespace,
+							"creationTimestamp": nil,
+						},
+						"spec": map[string]interface{}{
+							"selector": nil,
+							"strategy": map[string]interface{}{},
+							"template": map[string]interface{}{
+								"metadata": map[string]interface{}{"creationTimestamp": nil},
+								"spec":     map[string]interface{}{"containers": nil},
+							},
+						},
+						"status": map[string]interface{}{},
+					}},
+					"providerConfigRef": map[string]interface{}{"name": "remote"},
+				},
+			}}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ObjectWrapper("remote")(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nObjectWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nObjectWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// providerObject returns a provider-kubernetes Object labelled with the
+// supplied workload UID, if any, and with the supplied owner references.
+func providerObject(uid string, refs ...metav1.OwnerReference) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ObjectGroupVersionKind)
+	u.SetName("ns-cool-obj-deployment")
+	if uid != "" {
+		u.SetLabels(map[string]string{labelKey: uid})
+	}
+	if refs != nil {
+		u.SetOwnerReferences(refs)
+	}
+	return u
+}
+
+func TestObjectsMustMatch(t *testing.T) {
+	controller := true
+	ref := metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "Workload", Name: "cool", UID: "workload", Controller: &controller}
+	other := metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "Other", Name: "other", UID: "other"}
+
+	type args struct {
+		p AdoptionPolicy
+		c runtime.Object
+		d runtime.Object
+	}
+
+	type want struct {
+		o        runtime.Object
+		err      error
+		orphaned bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SameWorkload": {
+			reason: "No error should be returned if the current and desired Objects are labelled with the same workload.",
+			args: args{
+				c: providerObject("workload"),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o: providerObject("workload"),
+			},
+		},
+		"SameWorkloadWithController": {
+			reason: "The controller reference earlier versions of the addon set should be removed from an Object, keeping its other owner references.",
+			args: args{
+				c: providerObject("workload", ref, other),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o: providerObject("workload", other),
+			},
+		},
+		"SameWorkloadWithOnlyController": {
+			reason: "An empty list of owner references should be desired if an Object's only owner reference is its controller, so that merge patching removes it.",
+			args: args{
+				c: providerObject("workload", ref),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o: providerObject("workload", []metav1.OwnerReference{}...),
+			},
+		},
+		"OtherWorkload": {
+			reason: "An error should be returned if the current Object is labelled with another workload, regardless of the adoption policy.",
+			args: args{
+				p: AdoptionPolicyAdopt,
+				c: providerObject("other"),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o:   providerObject("workload"),
+				err: errors.New(errObjectOfOther),
+			},
+		},
+		"NotLabelledDefault": {
+			reason: "Objects with no workload label should not be adopted if no policy is specified.",
+			args: args{
+				c: providerObject(""),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o:   providerObject("workload"),
+				err: errors.New(errObjectNotLabelled),
+			},
+		},
+		"NotLabelledAdopt": {
+			reason: "An Object with no workload label should be adopted if the policy is Adopt.",
+			args: args{
+				p: AdoptionPolicyAdopt,
+				c: providerObject(""),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o: providerObject("workload"),
+			},
+		},
+		"NotLabelledOrphan": {
+			reason: "An Object with no workload label should be left unchanged if the policy is Orphan.",
+			args: args{
+				p: AdoptionPolicyOrphan,
+				c: providerObject(""),
+				d: providerObject("workload"),
+			},
+			want: want{
+				o:        providerObject("workload"),
+				err:      errOrphaned{name: "ns-cool-obj-deployment"},
+				orphaned: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ObjectsMustMatch(tc.args.p)(context.Background(), tc.args.c, tc.args.d)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nObjectsMustMatch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got := IsOrphaned(err); got != tc.want.orphaned {
+				t.Errorf("\nReason: %s\nIsOrphaned(...): want %t, got %t", tc.reason, tc.want.orphaned, got)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.args.d); diff != "" {
+				t.Errorf("\nReason: %s\nObjectsMustMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObjectDeleter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool", UID: "workload"}}
+
+	type args struct {
+		c client.Client
+		w Workload
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ListError": {
+			reason: "Errors listing the Objects of a workload should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				w: w,
+			},
+			want: errors.Wrap(errBoom, errListObjects),
+		},
+		"DeleteError": {
+			reason: "Errors deleting an Object of a workload should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*providerObject("workload")}
+						return nil
+					}),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				w: w,
+			},
+			want: errors.Wrap(errBoom, errDeleteObject),
+		},
+		"Success": {
+			reason: "Every Object labelled with the workload's UID should be deleted, ignoring those that no longer exist.",
+			args: args{
+				c: &test.MockClient{
+					MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if diff := cmp.Diff(labelKey+"=workload", lo.LabelSelector.String()); diff != "" {
+							return errors.Errorf("MockList: -want, +got: %s", diff)
+						}
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*providerObject("workload"), *providerObject("workload")}
+						return nil
+					},
+					MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				w: w,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewObjectDeleter(tc.args.c).Delete(context.Background(), tc.args.w, nil)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errParseApplyWave      = "cannot parse apply wave"
+	errDecodeResourceTempl = "cannot decode resource template"
+)
+
+// AnnotationApplyWave may be set on an object of a workload's translation, for
+// example a raw template, to specify the wave in which it is applied to the
+// remote cluster. Objects are applied in ascending order of wave. Objects
+// without the annotation are assigned a wave according to their kind.
+const AnnotationApplyWave = "workload.oam.crossplane.io/apply-wave"
+
+// Default apply waves.
+const (
+	// WaveDefinitions contains the objects that other objects are created
+	// in or are instances of, such as Namespaces and CRDs.
+	WaveDefinitions = -2
+
+	// WaveConfiguration contains the objects that pods consume, such as
+	// ConfigMaps, Secrets, and ServiceAccounts.
+	WaveConfiguration = -1
+
+	// WaveDefault contains all other objects, such as Deployments.
+	WaveDefault = 0
+)
+
+// applyWaves of kinds that are not applied in WaveDefault.
+var applyWaves = map[schema.GroupKind]int{
+	{Kind: "Namespace"}: WaveDefinitions,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: WaveDefinitions,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:               WaveDefinitions,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                   WaveDefinitions,
+
+	{Kind: "ConfigMap"}:                                              WaveConfiguration,
+	{Kind: "Secret"}:                                                 WaveConfiguration,
+	{Kind: "ServiceAccount"}:                                         WaveConfiguration,
+	{Kind: "PersistentVolumeClaim"}:                                  WaveConfiguration,
+	{Kind: "LimitRange"}:                                             WaveConfiguration,
+	{Kind: "ResourceQuota"}:                                          WaveConfiguration,
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:               WaveConfiguration,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:        WaveConfiguration,
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        WaveConfiguration,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: WaveConfiguration,
+}
+
+// ApplyWave returns the wave in which the supplied object should be applied.
+func ApplyWave(o Object) (int, error) {
+	if raw, ok := o.GetAnnotations()[AnnotationApplyWave]; ok {
+		w, err := strconv.Atoi(raw)
+		return w, errors.Wrapf(err, "%s of %s", errParseApplyWave, o.GetName())
+	}
+	return applyWaves[o.GetObjectKind().GroupVersionKind().GroupKind()], nil
+}
+
+var _ TranslationWrapper = OrderByWave
+
+// OrderByWave orders the objects of a translation by their apply wave, so that
+// they are packaged in an order that lets each find the objects it depends on.
+// Objects of the same wave keep their order. The resource templates of a
+// KubernetesApplication are applied to the remote cluster independently of
+// their order, so the waves of a KubernetesApplication are enforced by
+// WithholdWaves.
+func OrderByWave(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	type waved struct {
+		o    Object
+		wave int
+	}
+
+	ordered := make([]waved, len(objs))
+	for i, o := range objs {
+		wave, err := ApplyWave(o)
+		if err != nil {
+			return nil, err
+		}
+		ordered[i] = waved{o: o, wave: wave}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].wave < ordered[j].wave })
+
+	for i := range ordered {
+		objs[i] = ordered[i].o
+	}
+	return objs, nil
+}
+
+// WithholdWaves removes each resource template of the supplied desired
+// KubernetesApplications whose apply wave follows that of a template that has
+// not yet been submitted to the remote cluster, as reported by the supplied
+// KubernetesApplicationResources. For example the Deployment whose pods
+// consume a ConfigMap is not applied until the ConfigMap has been submitted,
+// rather than leaving its first pods unable to start. Templates of the
+// supplied current KubernetesApplications are never withheld, so that
+// templates that were already applied keep being updated. It returns true if
+// any template was withheld.
+func WithholdWaves(desired, current []*workloadv1alpha1.KubernetesApplication, resources []workloadv1alpha1.KubernetesApplicationResource) (bool, error) {
+	applied := map[string]bool{}
+	for _, a := range current {
+		for _, t := range a.Spec.ResourceTemplates {
+			applied[t.GetName()] = true
+		}
+	}
+	submitted := map[string]bool{}
+	for _, r := range resources {
+		submitted[r.GetName()] = r.Status.State == workloadv1alpha1.KubernetesApplicationResourceStateSubmitted
+	}
+
+	// Templates are released up to and including the earliest wave that has
+	// a template that has not been submitted.
+	waves := map[string]int{}
+	gated, gate := false, 0
+	for _, a := range desired {
+		for _, t := range a.Spec.ResourceTemplates {
+			wave, err := templateWave(t)
+			if err != nil {
+				return false, err
+			}
+			waves[t.GetName()] = wave
+			if !submitted[t.GetName()] && (!gated || wave < gate) {
+				gated, gate = true, wave
+			}
+		}
+	}
+	if !gated {
+		return false, nil
+	}
+
+	withheld := false
+	for _, a := range desired {
+		released := make([]workloadv1alpha1.KubernetesApplicationResourceTemplate, 0, len(a.Spec.ResourceTemplates))
+		for _, t := range a.Spec.ResourceTemplates {
+			if waves[t.GetName()] > gate && !applied[t.GetName()] {
+				withheld = true
+				continue
+			}
+			released = append(released, t)
+		}
+		a.Spec.ResourceTemplates = released
+	}
+	return withheld, nil
+}
+
+// templateWave returns the apply wave of the object templated by the supplied
+// resource template.
+func templateWave(t workloadv1alpha1.KubernetesApplicationResourceTemplate) (int, error) {
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(t.Spec.Template.Raw, &u.Object); err != nil {
+		return 0, errors.Wrapf(err, "%s %s", errDecodeResourceTempl, t.GetName())
+	}
+	return ApplyWave(u)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestOrderByWave(t *testing.T) {
+	obj := func(apiVersion, kind, name string, annotations map[string]string) Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetAnnotations(annotations)
+		return u
+	}
+	wave := func(w int) map[string]string {
+		return map[string]string{AnnotationApplyWave: strconv.Itoa(w)}
+	}
+
+	deploy := obj("apps/v1", "Deployment", "deploy", nil)
+	svc := obj("v1", "Service", "svc", nil)
+	cm := obj("v1", "ConfigMap", "cm", nil)
+	secret := obj("v1", "Secret", "secret", nil)
+	ns := obj("v1", "Namespace", "ns", nil)
+	crd := obj("apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "crd", nil)
+	late := obj("v1", "ConfigMap", "late", wave(1))
+	early := obj("monitoring.coreos.com/v1", "ServiceMonitor", "early", wave(-3))
+
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []Object
+		want   want
+	}{
+		"InvalidWave": {
+			reason: "Objects whose apply wave is not an integer should return an error.",
+			objs:   []Object{obj("v1", "ConfigMap", "cm", map[string]string{AnnotationApplyWave: "first"})},
+			want:   want{err: errors.Wrapf(errors.New(`strconv.Atoi: parsing "first": invalid syntax`), "%s of %s", errParseApplyWave, "cm")},
+		},
+		"ByKind": {
+			reason: "Objects should be ordered by the wave of their kind, keeping the order of objects of the same wave.",
+			objs:   []Object{deploy, svc, cm, ns, secret, crd},
+			want:   want{objs: []Object{ns, crd, cm, secret, deploy, svc}},
+		},
+		"ByAnnotation": {
+			reason: "The apply wave annotation should take precedence over an object's kind.",
+			objs:   []Object{late, deploy, cm, early},
+			want:   want{objs: []Object{early, cm, deploy, late}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := OrderByWave(context.Background(), &workloadfake.Workload{}, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nOrderByWave(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nOrderByWave(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithholdWaves(t *testing.T) {
+	obj := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+	kar := func(name string, state workloadv1alpha1.KubernetesApplicationResourceState) workloadv1alpha1.KubernetesApplicationResource {
+		return workloadv1alpha1.KubernetesApplicationResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     workloadv1alpha1.KubernetesApplicationResourceStatus{State: state},
+		}
+	}
+
+	ns := kaWithTemplate("ns", obj("v1", "Namespace", "ns"))
+	cm := kaWithTemplate("cm", obj("v1", "ConfigMap", "cm"))
+	deploy := kaWithTemplate("deploy", obj("apps/v1", "Deployment", "deploy"))
+
+	type args struct {
+		desired   []*workloadv1alpha1.KubernetesApplication
+		current   []*workloadv1alpha1.KubernetesApplication
+		resources []workloadv1alpha1.KubernetesApplicationResource
+	}
+
+	type want struct {
+		desired  []*workloadv1alpha1.KubernetesApplication
+		withheld bool
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DecodeError": {
+			reason: "Errors decoding a resource template should be returned.",
+			args: args{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(func(a *workloadv1alpha1.KubernetesApplication) {
+					a.Spec.ResourceTemplates = []workloadv1alpha1.KubernetesApplicationResourceTemplate{{
+						ObjectMeta: metav1.ObjectMeta{Name: "bad"},
+						Spec:       workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte("{")}},
+					}}
+				})},
+			},
+			want: want{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(func(a *workloadv1alpha1.KubernetesApplication) {
+					a.Spec.ResourceTemplates = []workloadv1alpha1.KubernetesApplicationResourceTemplate{{
+						ObjectMeta: metav1.ObjectMeta{Name: "bad"},
+						Spec:       workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte("{")}},
+					}}
+				})},
+				err: errors.Wrap(errors.New("unexpected end of JSON input"), errDecodeResourceTempl+" bad"),
+			},
+		},
+		"NothingSubmitted": {
+			reason: "Only the first wave should be released if nothing has been submitted to the remote cluster.",
+			args: args{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+			},
+			want: want{
+				desired:  []*workloadv1alpha1.KubernetesApplication{kubeApp(ns)},
+				withheld: true,
+			},
+		},
+		"EarlierWaveNotSubmitted": {
+			reason: "Waves that follow a wave with a template that has not been submitted should be withheld.",
+			args: args{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+				resources: []workloadv1alpha1.KubernetesApplicationResource{
+					kar("ns", workloadv1alpha1.KubernetesApplicationResourceStateSubmitted),
+					kar("cm", workloadv1alpha1.KubernetesApplicationResourceStateScheduled),
+				},
+			},
+			want: want{
+				desired:  []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm)},
+				withheld: true,
+			},
+		},
+		"EarlierWavesSubmitted": {
+			reason: "Every wave should be released once the waves before the last have been submitted.",
+			args: args{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+				resources: []workloadv1alpha1.KubernetesApplicationResource{
+					kar("ns", workloadv1alpha1.KubernetesApplicationResourceStateSubmitted),
+					kar("cm", workloadv1alpha1.KubernetesApplicationResourceStateSubmitted),
+				},
+			},
+			want: want{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+			},
+		},
+		"AlreadyApplied": {
+			reason: "Templates that were already applied should not be withheld, so that they keep being updated.",
+			args: args{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+				current: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+				resources: []workloadv1alpha1.KubernetesApplicationResource{
+					kar("ns", workloadv1alpha1.KubernetesApplicationResourceStatePending),
+				},
+			},
+			want: want{
+				desired: []*workloadv1alpha1.KubernetesApplication{kubeApp(ns, cm, deploy)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			withheld, err := WithholdWaves(tc.args.desired, tc.args.current, tc.args.resources)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWithholdWaves(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.withheld, withheld); diff != "" {
+				t.Errorf("\nReason: %s\nWithholdWaves(...): -want withheld, +got withheld:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.desired, tc.args.desired); diff != "" {
+				t.Errorf("\nReason: %s\nWithholdWaves(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+// AnnotationOwnership records which controllers own the resource templates of
+// a KubernetesApplication, and which fields of those templates.
+const AnnotationOwnership = "workload.oam.crossplane.io/owners"
+
+const (
+	errGetOwnership    = "cannot get ownership of KubernetesApplication resource templates"
+	errSetOwnership    = "cannot set ownership of KubernetesApplication resource templates"
+	errTemplateOwned   = "resource template is owned by another controller"
+	errFieldOwned      = "resource template field is owned by another controller"
+	errDiffTemplates   = "cannot determine modified resource template fields"
+	errUnownedTemplate = "resource template was removed"
+)
+
+// TemplateOwnership records the controller that owns a resource template, and
+// the controllers that own fields of the template.
+type TemplateOwnership struct {
+	// Owner of the template. Only the owner of a template may replace or
+	// remove it.
+	Owner string `json:"owner"`
+
+	// Fields of the template that were modified by controllers other than
+	// its owner, keyed by JSON pointer. Elements of lists of named objects,
+	// such as containers, are identified by a "name=<name>" segment rather
+	// than their index. Only the owner of a field may modify it.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Ownership of the resource templates of a KubernetesApplication, keyed by
+// template name. Ownership is held for the lifetime of the
+// KubernetesApplication.
+type Ownership map[string]TemplateOwnership
+
+// GetOwnership returns the Ownership recorded on the supplied object, if any.
+func GetOwnership(o metav1.Object) (Ownership, error) {
+	v, ok := o.GetAnnotations()[AnnotationOwnership]
+	if !ok {
+		return nil, nil
+	}
+	own := Ownership{}
+	return own, errors.Wrap(json.Unmarshal([]byte(v), &own), errGetOwnership)
+}
+
+// SetOwnership records the supplied Ownership on the supplied object.
+func SetOwnership(o metav1.Object, own Ownership) error {
+	b, err := json.Marshal(own)
+	if err != nil {
+		return errors.Wrap(err, errSetOwnership)
+	}
+	meta.AddAnnotations(o, map[string]string{AnnotationOwnership: string(b)})
+	return nil
+}
+
+// ClaimTemplates returns an ApplyOption that claims the resource templates of
+// the desired KubernetesApplication for the supplied owner. It returns an
+// error if a desired template is owned by another controller. Templates owned
+// by other controllers that are not desired are preserved rather than
+// removed, while unowned templates that are not desired are removed.
+func ClaimTemplates(owner string) resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, ok := current.(*workloadv1alpha1.KubernetesApplication)
+		if !ok {
+			return errors.New(errNotKubeApp)
+		}
+		d, ok := desired.(*workloadv1alpha1.KubernetesApplication)
+		if !ok {
+			return errors.New(errNotKubeApp)
+		}
+
+		own, err := GetOwnership(c)
+		if err != nil {
+			return err
+		}
+		if own == nil {
+			own = Ownership{}
+		}
+
+		want := map[string]bool{}
+		for _, t := range d.Spec.ResourceTemplates {
+			o, ok := own[t.GetName()]
+			if ok && o.Owner != owner {
+				return errors.Errorf("%s: %s is owned by %s", errTemplateOwned, t.GetName(), o.Owner)
+			}
+			own[t.GetName()] = TemplateOwnership{Owner: owner, Fields: o.Fields}
+			want[t.GetName()] = true
+		}
+
+		for _, t := range c.Spec.ResourceTemplates {
+			if want[t.GetName()] {
+				continue
+			}
+			if o, ok := own[t.GetName()]; ok && o.Owner != owner {
+				d.Spec.ResourceTemplates = append(d.Spec.ResourceTemplates, t)
+				continue
+			}
+			delete(own, t.GetName())
+		}
+
+		return SetOwnership(d, own)
+	}
+}
+
+// ClaimFields claims the resource template fields the supplied owner modified
+// between the before and after versions of a KubernetesApplication, and the
+// templates it added. It returns an error if the owner modified a field that
+// is owned by another controller, or removed a template it does not own.
+// Ownership is recorded on the after version.
+func ClaimFields(before, after *workloadv1alpha1.KubernetesApplication, owner string) error {
+	own, err := GetOwnership(before)
+	if err != nil {
+		return err
+	}
+	if own == nil {
+		own = Ownership{}
+	}
+
+	b := map[string]workloadv1alpha1.KubernetesApplicationResourceTemplate{}
+	for _, t := range before.Spec.ResourceTemplates {
+		b[t.GetName()] = t
+	}
+
+	seen := map[string]bool{}
+	for _, at := range after.Spec.ResourceTemplates {
+		name := at.GetName()
+		seen[name] = true
+
+		bt, ok := b[name]
+		if !ok {
+			if o, exists := own[name]; exists && o.Owner != owner {
+				return errors.Errorf("%s: %s is owned by %s", errTemplateOwned, name, o.Owner)
+			}
+			own[name] = TemplateOwnership{Owner: owner}
+			continue
+		}
+
+		fields, err := changedFields(bt.Spec.Template.Raw, at.Spec.Template.Raw)
+		if err != nil {
+			return errors.Wrap(err, errDiffTemplates)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		o := own[name]
+		if o.Owner == owner {
+			// The owner of a template may modify any of its fields.
+			continue
+		}
+		if o.Fields == nil {
+			o.Fields = map[string]string{}
+		}
+		for _, f := range fields {
+			if fo, ok := o.Fields[f]; ok && fo != owner {
+				return errors.Errorf("%s: %s %s is owned by %s", errFieldOwned, name, f, fo)
+			}
+			o.Fields[f] = owner
+		}
+		own[name] = o
+	}
+
+	for name := range b {
+		if seen[name] {
+			continue
+		}
+		if o, ok := own[name]; !ok || o.Owner != owner {
+			return errors.Errorf("%s: %s is not owned by %s", errUnownedTemplate, name, owner)
+		}
+		delete(own, name)
+	}
+
+	return SetOwnership(after, own)
+}
+
+// ReleaseFields releases the resource template fields of the supplied
+// KubernetesApplication that are owned by the supplied owner, so that they may
+// be modified by other controllers. Templates owned by the owner are not
+// released.
+func ReleaseFields(a *workloadv1alpha1.KubernetesApplication, owner string) error {
+	own, err := GetOwnership(a)
+	if err != nil || own == nil {
+		return err
+	}
+	for name, o := range own {
+		for f, fo := range o.Fields {
+			if fo == owner {
+				delete(o.Fields, f)
+			}
+		}
+		if len(o.Fields) == 0 {
+			o.Fields = nil
+		}
+		own[name] = o
+	}
+	return SetOwnership(a, own)
+}
+
+// changedFields returns JSON pointers to the fields that differ between the
+// supplied JSON documents.
+func changedFields(before, after []byte) ([]string, error) {
+	var b, a interface{}
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil, err
+	}
+	f := fieldPaths("", b, a)
+	sort.Strings(f)
+	return f, nil
+}
+
+func fieldPaths(path string, before, after interface{}) []string {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	var b, a map[string]interface{}
+	switch av := after.(type) {
+	case map[string]interface{}:
+		bv, ok := before.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		b, a = bv, av
+	case []interface{}:
+		bv, ok := before.([]interface{})
+		if !ok {
+			return []string{path}
+		}
+		var bok, aok bool
+		b, bok = byName(bv)
+		a, aok = byName(av)
+		if !bok || !aok {
+			return []string{path}
+		}
+	default:
+		return []string{path}
+	}
+
+	out := []string{}
+	for k, av := range a {
+		out = append(out, fieldPaths(path+"/"+escape(k), b[k], av)...)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, path+"/"+escape(k))
+		}
+	}
+	return out
+}
+
+// byName returns the supplied list keyed by "name=<name>", if every element
+// of the list is an object with a unique name.
+func byName(l []interface{}) (map[string]interface{}, bool) {
+	out := make(map[string]interface{}, len(l))
+	for _, e := range l {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		n, ok := m["name"].(string)
+		if !ok || n == "" {
+			return nil, false
+		}
+		k := "name=" + n
+		if _, dup := out[k]; dup {
+			return nil, false
+		}
+		out[k] = m
+	}
+	return out, true
+}
+
+// escape a JSON pointer reference token per RFC 6901.
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
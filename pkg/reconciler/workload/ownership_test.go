@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+func kubeAppWithTemplates(own Ownership, templates map[string]string) *workloadv1alpha1.KubernetesApplication {
+	a := &workloadv1alpha1.KubernetesApplication{}
+	if own != nil {
+		_ = SetOwnership(a, own)
+	}
+	for _, name := range []string{"cool-deployment", "cool-service", "cool-pvc"} {
+		raw, ok := templates[name]
+		if !ok {
+			continue
+		}
+		a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte(raw)}},
+		})
+	}
+	return a
+}
+
+func TestClaimTemplates(t *testing.T) {
+	type args struct {
+		current *workloadv1alpha1.KubernetesApplication
+		desired *workloadv1alpha1.KubernetesApplication
+	}
+
+	type want struct {
+		desired *workloadv1alpha1.KubernetesApplication
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"TemplateOwnedByAnother": {
+			reason: "A desired template that is owned by another controller should not be claimed.",
+			args: args{
+				current: kubeAppWithTemplates(Ownership{"cool-deployment": {Owner: "oam/other"}}, map[string]string{"cool-deployment": `{}`}),
+				desired: kubeAppWithTemplates(nil, map[string]string{"cool-deployment": `{}`}),
+			},
+			want: want{
+				desired: kubeAppWithTemplates(nil, map[string]string{"cool-deployment": `{}`}),
+				err:     errors.Errorf("%s: %s is owned by %s", errTemplateOwned, "cool-deployment", "oam/other"),
+			},
+		},
+		"Success": {
+			reason: "Desired templates should be claimed, templates owned by others preserved, and unowned templates removed.",
+			args: args{
+				current: kubeAppWithTemplates(
+					Ownership{
+						"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/replicas": "oam/scaler"}},
+						"cool-pvc":        {Owner: "oam/volumes"},
+					},
+					map[string]string{"cool-deployment": `{}`, "cool-service": `{}`, "cool-pvc": `{}`},
+				),
+				desired: kubeAppWithTemplates(nil, map[string]string{"cool-deployment": `{}`}),
+			},
+			want: want{
+				desired: kubeAppWithTemplates(
+					Ownership{
+						"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/replicas": "oam/scaler"}},
+						"cool-pvc":        {Owner: "oam/volumes"},
+					},
+					map[string]string{"cool-deployment": `{}`, "cool-pvc": `{}`},
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ClaimTemplates("oam/workload")(context.Background(), tc.args.current, tc.args.desired)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nClaimTemplates(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.desired, tc.args.desired); diff != "" {
+				t.Errorf("\nReason: %s\nClaimTemplates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClaimFields(t *testing.T) {
+	deployment := `{"spec":{"template":{"spec":{"containers":[{"name":"cool","image":"cool:latest"}]}}}}`
+	withEnv := `{"spec":{"template":{"spec":{"containers":[{"name":"cool","image":"cool:latest","env":[{"name":"PROXY","value":"cool"}]}]}}}}`
+	withReplicas := `{"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"cool","image":"cool:latest"}]}}}}`
+
+	type args struct {
+		before *workloadv1alpha1.KubernetesApplication
+		after  *workloadv1alpha1.KubernetesApplication
+	}
+
+	type want struct {
+		own Ownership
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FieldOwnedByAnother": {
+			reason: "A field that is owned by another controller should not be claimed.",
+			args: args{
+				before: kubeAppWithTemplates(Ownership{"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/replicas": "oam/other"}}}, map[string]string{"cool-deployment": deployment}),
+				after:  kubeAppWithTemplates(nil, map[string]string{"cool-deployment": withReplicas}),
+			},
+			want: want{err: errors.Errorf("%s: %s %s is owned by %s", errFieldOwned, "cool-deployment", "/spec/replicas", "oam/other")},
+		},
+		"RemovedTemplate": {
+			reason: "A template that is not owned by the controller should not be removed.",
+			args: args{
+				before: kubeAppWithTemplates(Ownership{"cool-deployment": {Owner: "oam/workload"}}, map[string]string{"cool-deployment": deployment}),
+				after:  kubeAppWithTemplates(nil, nil),
+			},
+			want: want{err: errors.Errorf("%s: %s is not owned by %s", errUnownedTemplate, "cool-deployment", "oam/trait")},
+		},
+		"Success": {
+			reason: "Modified fields of named list elements and added templates should be claimed.",
+			args: args{
+				before: kubeAppWithTemplates(Ownership{"cool-deployment": {Owner: "oam/workload"}}, map[string]string{"cool-deployment": deployment}),
+				after:  kubeAppWithTemplates(nil, map[string]string{"cool-deployment": withEnv, "cool-pvc": `{}`}),
+			},
+			want: want{own: Ownership{
+				"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/template/spec/containers/name=cool/env": "oam/trait"}},
+				"cool-pvc":        {Owner: "oam/trait"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ClaimFields(tc.args.before, tc.args.after, "oam/trait")
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nClaimFields(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			own, _ := GetOwnership(tc.args.after)
+			if diff := cmp.Diff(tc.want.own, own); diff != "" {
+				t.Errorf("\nReason: %s\nClaimFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReleaseFields(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      *workloadv1alpha1.KubernetesApplication
+		want   Ownership
+	}{
+		"NoOwnership": {
+			reason: "A KubernetesApplication with no recorded ownership should be left unchanged.",
+			a:      kubeAppWithTemplates(nil, nil),
+		},
+		"Success": {
+			reason: "Only the fields owned by the owner should be released.",
+			a: kubeAppWithTemplates(Ownership{
+				"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/replicas": "oam/trait", "/spec/paused": "oam/other"}},
+				"cool-service":    {Owner: "oam/workload", Fields: map[string]string{"/spec/type": "oam/trait"}},
+				"cool-pvc":        {Owner: "oam/trait"},
+			}, nil),
+			want: Ownership{
+				"cool-deployment": {Owner: "oam/workload", Fields: map[string]string{"/spec/paused": "oam/other"}},
+				"cool-service":    {Owner: "oam/workload"},
+				"cool-pvc":        {Owner: "oam/trait"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := ReleaseFields(tc.a, "oam/trait"); err != nil {
+				t.Errorf("\nReason: %s\nReleaseFields(...): %s", tc.reason, err)
+			}
+
+			own, _ := GetOwnership(tc.a)
+			if diff := cmp.Diff(tc.want, own); diff != "" {
+				t.Errorf("\nReason: %s\nReleaseFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
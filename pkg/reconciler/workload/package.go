@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	errWrapInManifestWork = "unable to wrap objects in ManifestWork"
+	errWrapInSecret       = "unable to wrap objects in Secret"
+)
+
+// ManifestWorkGroupVersionKind is the GroupVersionKind of an Open Cluster
+// Management ManifestWork.
+var ManifestWorkGroupVersionKind = schema.GroupVersionKind{Group: "work.open-cluster-management.io", Version: "v1", Kind: "ManifestWork"}
+
+// SecretTypeManifests is the type of the Secrets produced by SecretWrapper.
+const SecretTypeManifests corev1.SecretType = "oam.crossplane.io/manifests"
+
+// SecretKeyManifests is the key under which SecretWrapper stores manifests.
+const SecretKeyManifests = "manifests.yaml"
+
+var (
+	secretKind       = reflect.TypeOf(corev1.Secret{}).Name()
+	secretAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// A Packager wraps the objects a workload was translated into in the package
+// that is applied on their behalf, for example a KubernetesApplication.
+type Packager interface {
+	Package(context.Context, Workload, []Object) ([]Object, error)
+}
+
+// A PackageFn wraps the objects a workload was translated into in a package.
+type PackageFn func(context.Context, Workload, []Object) ([]Object, error)
+
+// Package the supplied objects.
+func (fn PackageFn) Package(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	return fn(ctx, w, objs)
+}
+
+var _ Packager = PackageFn(NoopPackage)
+
+// NoopPackage does not package the supplied objects and does not return error.
+func NoopPackage(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	return objs, nil
+}
+
+// NewPackagerWithWrappers returns a Packager that packages objects using the
+// supplied Packager, then wraps the package.
+func NewPackagerWithWrappers(p Packager, wp ...TranslationWrapper) Packager {
+	return PackageFn(func(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+		objs, err := p.Package(ctx, w, objs)
+		if err != nil {
+			return nil, err
+		}
+		for _, wrap := range wp {
+			if objs, err = wrap(ctx, w, objs); err != nil {
+				return nil, err
+			}
+		}
+		return objs, nil
+	})
+}
+
+var _ TranslationWrapper = ManifestWorkWrapper
+
+// ManifestWorkWrapper wraps a set of translated objects in an Open Cluster
+// Management ManifestWork. ManifestWorks are created in the namespace of their
+// workload, which must be the namespace of a managed cluster on the hub.
+func ManifestWorkWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	if objs == nil {
+		return nil, nil
+	}
+
+	manifests := make([]interface{}, 0, len(objs))
+	for _, o := range objs {
+		// The objects of a ManifestWork are created in the managed cluster,
+		// so a namespaced object must specify its namespace.
+		if o.GetNamespace() == "" {
+			o.SetNamespace(w.GetNamespace())
+		}
+
+		b, err := json.Marshal(o)
+		if err != nil {
+			return nil, errors.Wrap(err, errWrapInManifestWork)
+		}
+		manifest := map[string]interface{}{}
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, errors.Wrap(err, errWrapInManifestWork)
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"workload": map[string]interface{}{"manifests": manifests},
+		},
+	}}
+	u.SetGroupVersionKind(ManifestWorkGroupVersionKind)
+	u.SetName(w.GetName())
+	u.SetLabels(map[string]string{labelKey: string(w.GetUID())})
+
+	return []Object{u}, nil
+}
+
+var _ TranslationWrapper = SecretWrapper
+
+// SecretWrapper wraps a set of translated objects in a Secret as a stream of
+// YAML manifests, for consumption by tools that apply plain manifests.
+func SecretWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	if objs == nil {
+		return nil, nil
+	}
+
+	buf := &bytes.Buffer{}
+	for _, o := range objs {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, errors.Wrap(err, errWrapInSecret)
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+
+	s := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       secretKind,
+			APIVersion: secretAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   w.GetName(),
+			Labels: map[string]string{labelKey: string(w.GetUID())},
+		},
+		Type: SecretTypeManifests,
+		Data: map[string][]byte{SecretKeyManifests: buf.Bytes()},
+	}
+
+	return []Object{s}, nil
+}
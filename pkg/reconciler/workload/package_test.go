@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestManifestWorkWrapper(t *testing.T) {
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: workloadNamespace, UID: types.UID(workloadUID)}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilObject": {
+			reason: "Nil object should immediately return nil.",
+			args: args{
+				w: w,
+			},
+			want: want{},
+		},
+		"Success": {
+			reason: "All objects should be wrapped in a single ManifestWork named after the workload.",
+			args: args{
+				w: w,
+				o: []Object{&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				}},
+			},
+			want: want{result: []Object{&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "work.open-cluster-management.io/v1",
+				"kind":       "ManifestWork",
+				"metadata": map[string]interface{}{
+					"name":   workloadName,
+					"labels": map[string]interface{}{labelKey: workloadUID},
+				},
+				"spec": map[string]interface{}{
+					"workload": map[string]interface{}{"manifests": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata": map[string]interface{}{
+								"name":              "cool",
+								"namespace":         workloadNamespace,
+								"creationTimestamp": nil,
+							},
+						},
+					}},
+				},
+			}}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ManifestWorkWrapper(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nManifestWorkWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nManifestWorkWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecretWrapper(t *testing.T) {
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: workloadNamespace, UID: types.UID(workloadUID)}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilObject": {
+			reason: "Nil object should immediately return nil.",
+			args: args{
+				w: w,
+			},
+			want: want{},
+		},
+		"Success": {
+			reason: "All objects should be written to a single Secret as a stream of YAML manifests.",
+			args: args{
+				w: w,
+				o: []Object{
+					&corev1.ConfigMap{
+						TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+						ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+					},
+					&corev1.ConfigMap{
+						TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+						ObjectMeta: metav1.ObjectMeta{Name: "cooler"},
+					},
+				},
+			},
+			want: want{result: []Object{&corev1.Secret{
+				TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   workloadName,
+					Labels: map[string]string{labelKey: workloadUID},
+				},
+				Type: SecretTypeManifests,
+				Data: map[string][]byte{SecretKeyManifests: []byte(
+					"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  creationTimestamp: null\n  name: cool\n" +
+						"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  creationTimestamp: null\n  name: cooler\n",
+				)},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SecretWrapper(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSecretWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nSecretWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewPackagerWithWrappers(t *testing.T) {
+	errBoom := errors.New("boom")
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName}}
+	packaged := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "package"}}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      Packager
+		wp     []TranslationWrapper
+		want   want
+	}{
+		"PackageError": {
+			reason: "Errors packaging objects should be returned.",
+			p: PackageFn(func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+				return nil, errBoom
+			}),
+			want: want{err: errBoom},
+		},
+		"WrapError": {
+			reason: "Errors wrapping a package should be returned.",
+			p: PackageFn(func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+				return []Object{packaged}, nil
+			}),
+			wp: []TranslationWrapper{func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+				return nil, errBoom
+			}},
+			want: want{err: errBoom},
+		},
+		"Success": {
+			reason: "Wrappers should be passed the package.",
+			p: PackageFn(func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+				return []Object{packaged}, nil
+			}),
+			wp:   []TranslationWrapper{NoopWrapper},
+			want: want{result: []Object{packaged}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewPackagerWithWrappers(tc.p, tc.wp...).Package(context.Background(), w, []Object{&corev1.ConfigMap{}})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nPackage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
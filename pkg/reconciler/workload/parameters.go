@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errMarshalWorkload    = "cannot marshal workload"
+	errUnmarshalWorkload  = "cannot unmarshal workload"
+	errGetParameterValues = "cannot get parameter values"
+	errGetAppConfig       = "cannot get application configuration"
+	errNoAppConfig        = "workload is not controlled by an application configuration"
+	errNoComponent        = "application configuration has no component for workload"
+	errNoParameterValue   = "no value for parameter"
+)
+
+// AnnotationParameters records the parameter values that were resolved into a
+// workload before it was translated, so that the workload is translated again
+// when they change.
+const AnnotationParameters = "workload.oam.crossplane.io/parameters"
+
+// LabelComponent is the name of the Component a workload was rendered from,
+// as labelled by the OAM Kubernetes runtime. Workloads that are not labelled
+// are assumed to be named after their Component.
+const LabelComponent = "app.oam.dev/component"
+
+// A reference to a parameter in a string field of a workload's spec or in one
+// of its annotations, for example "nginx:[fromParam(tag)]".
+var parameterReference = regexp.MustCompile(`\[fromParam\(([^)\]]+)\)\]`)
+
+// Only annotations that configure how a workload is translated may reference
+// parameters. Other annotations, such as the changelog, may contain copies of
+// the workload's unresolved spec.
+var parameterAnnotation = regexp.MustCompile(`workload\.oam\.crossplane\.io/`)
+
+// A ParameterResolver resolves the parameters a workload references before it
+// is translated.
+type ParameterResolver interface {
+	// Resolve returns a copy of the supplied workload in which the
+	// parameters it references are replaced by their values. The supplied
+	// workload is returned unchanged if it references no parameters.
+	Resolve(ctx context.Context, w Workload) (Workload, error)
+}
+
+// A ParameterResolverFn resolves the parameters a workload references.
+type ParameterResolverFn func(ctx context.Context, w Workload) (Workload, error)
+
+// Resolve the parameters a workload references.
+func (fn ParameterResolverFn) Resolve(ctx context.Context, w Workload) (Workload, error) {
+	return fn(ctx, w)
+}
+
+// NopResolve does not resolve parameters.
+func NopResolve(_ context.Context, w Workload) (Workload, error) { return w, nil }
+
+// ParameterValues returns the values of the parameters supplied to a
+// workload, keyed by parameter name.
+type ParameterValues func(ctx context.Context, w Workload) (map[string]intstr.IntOrString, error)
+
+// A ParameterSubstitutor resolves parameter references in the string fields of
+// a workload's spec and in its annotations. A spec field whose value is a
+// single reference to an integer parameter, for example a replica count,
+// becomes an integer. Other references are replaced by the string form of the
+// parameter's value.
+type ParameterSubstitutor struct {
+	values ParameterValues
+}
+
+// NewParameterSubstitutor returns a ParameterSubstitutor that resolves
+// parameter references using the supplied parameter values.
+func NewParameterSubstitutor(v ParameterValues) *ParameterSubstitutor {
+	return &ParameterSubstitutor{values: v}
+}
+
+// Resolve the parameters the supplied workload references. Parameter values
+// are only fetched for workloads that reference parameters.
+func (s *ParameterSubstitutor) Resolve(ctx context.Context, w Workload) (Workload, error) {
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalWorkload)
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, errors.Wrap(err, errMarshalWorkload)
+	}
+
+	annotations := map[string]interface{}{}
+	for k, v := range w.GetAnnotations() {
+		if parameterAnnotation.MatchString(k) {
+			annotations[k] = v
+		}
+	}
+	targets := []interface{}{obj["spec"], annotations}
+	refs := map[string]bool{}
+	for _, t := range targets {
+		walkStrings(t, func(s string) interface{} {
+			for _, m := range parameterReference.FindAllStringSubmatch(s, -1) {
+				refs[m[1]] = true
+			}
+			return s
+		})
+	}
+	if len(refs) == 0 {
+		return w, nil
+	}
+
+	values, err := s.values(ctx, w)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetParameterValues)
+	}
+	resolved := make(map[string]intstr.IntOrString, len(refs))
+	for name := range refs {
+		v, ok := values[name]
+		if !ok {
+			return nil, errors.Errorf("%s: %s", errNoParameterValue, name)
+		}
+		resolved[name] = v
+	}
+
+	walkStrings(obj["spec"], func(s string) interface{} {
+		if m := parameterReference.FindStringSubmatch(s); m != nil && m[0] == s && resolved[m[1]].Type == intstr.Int {
+			return int64(resolved[m[1]].IntVal)
+		}
+		return substitute(s, resolved)
+	})
+	walkStrings(annotations, func(s string) interface{} { return substitute(s, resolved) })
+
+	b, err = json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalWorkload)
+	}
+	out := w.DeepCopyObject().(Workload)
+	if err := json.Unmarshal(b, out); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalWorkload)
+	}
+	for k, v := range annotations {
+		meta.AddAnnotations(out, map[string]string{k: v.(string)})
+	}
+
+	// Recording the resolved values changes the workload's annotations, and
+	// thus invalidates any cached translation, when they change. Marshalling
+	// a map sorts its keys, so the annotation is stable.
+	rv, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalWorkload)
+	}
+	meta.AddAnnotations(out, map[string]string{AnnotationParameters: string(rv)})
+	return out, nil
+}
+
+// substitute the supplied parameter values for the references in the supplied
+// string.
+func substitute(s string, values map[string]intstr.IntOrString) string {
+	return parameterReference.ReplaceAllStringFunc(s, func(ref string) string {
+		v := values[parameterReference.FindStringSubmatch(ref)[1]]
+		return v.String()
+	})
+}
+
+// walkStrings replaces each string value nested within the supplied value
+// with the result of the supplied function.
+func walkStrings(v interface{}, fn func(string) interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			if s, ok := e.(string); ok {
+				t[k] = fn(s)
+				continue
+			}
+			walkStrings(e, fn)
+		}
+	case []interface{}:
+		for i, e := range t {
+			if s, ok := e.(string); ok {
+				t[i] = fn(s)
+				continue
+			}
+			walkStrings(e, fn)
+		}
+	}
+}
+
+// ApplicationConfigurationParameters returns the parameter values supplied to
+// a workload by the component of the ApplicationConfiguration that controls
+// it.
+func ApplicationConfigurationParameters(c client.Reader) ParameterValues {
+	return func(ctx context.Context, w Workload) (map[string]intstr.IntOrString, error) {
+		ref := metav1.GetControllerOf(w)
+		if ref == nil || ref.Kind != oamv1alpha2.ApplicationConfigurationKind {
+			return nil, errors.New(errNoAppConfig)
+		}
+
+		ac := &oamv1alpha2.ApplicationConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: w.GetNamespace(), Name: ref.Name}, ac); err != nil {
+			return nil, errors.Wrap(err, errGetAppConfig)
+		}
+
+		component := w.GetName()
+		if name, ok := w.GetLabels()[LabelComponent]; ok {
+			component = name
+		}
+		for _, acc := range ac.Spec.Components {
+			if acc.ComponentName != component {
+				continue
+			}
+			values := make(map[string]intstr.IntOrString, len(acc.ParameterValues))
+			for _, pv := range acc.ParameterValues {
+				values[pv.Name] = pv.Value
+			}
+			return values, nil
+		}
+		return nil, errors.Errorf("%s: %s", errNoComponent, component)
+	}
+}
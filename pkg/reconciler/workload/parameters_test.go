@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+var _ ParameterResolver = &ParameterSubstitutor{}
+
+func TestParameterSubstitutor(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	cool := func(spec map[string]interface{}, annotations map[string]string) *Unstructured {
+		u := NewUnstructured(gvk)
+		u.SetName("cool")
+		u.SetAnnotations(annotations)
+		u.Object["spec"] = spec
+		return u
+	}
+	values := func(v map[string]intstr.IntOrString, err error) ParameterValues {
+		return func(_ context.Context, _ Workload) (map[string]intstr.IntOrString, error) { return v, err }
+	}
+
+	type args struct {
+		values ParameterValues
+		w      Workload
+	}
+
+	type want struct {
+		w   Workload
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoReferences": {
+			reason: "A workload that references no parameters should be returned unchanged, without getting parameter values.",
+			args: args{
+				values: values(nil, errBoom),
+				w:      cool(map[string]interface{}{"image": "nginx:1.19"}, nil),
+			},
+			want: want{w: cool(map[string]interface{}{"image": "nginx:1.19"}, nil)},
+		},
+		"GetValuesError": {
+			reason: "Errors getting parameter values should be returned.",
+			args: args{
+				values: values(nil, errBoom),
+				w:      cool(map[string]interface{}{"image": "nginx:[fromParam(tag)]"}, nil),
+			},
+			want: want{err: errors.Wrap(errBoom, errGetParameterValues)},
+		},
+		"NoValue": {
+			reason: "A reference to a parameter that has no value should return an error.",
+			args: args{
+				values: values(map[string]intstr.IntOrString{}, nil),
+				w:      cool(map[string]interface{}{"image": "nginx:[fromParam(tag)]"}, nil),
+			},
+			want: want{err: errors.Errorf("%s: %s", errNoParameterValue, "tag")},
+		},
+		"Success": {
+			reason: "References in the spec and in translation annotations should be replaced by their parameter values.",
+			args: args{
+				values: values(map[string]intstr.IntOrString{"tag": intstr.FromString("1.19"), "replicas": intstr.FromInt(3)}, nil),
+				w: cool(
+					map[string]interface{}{
+						"image":    "nginx:[fromParam(tag)]",
+						"replicas": "[fromParam(replicas)]",
+						"env":      []interface{}{map[string]interface{}{"name": "WORKERS", "value": "[fromParam(replicas)]-workers"}},
+					},
+					map[string]string{
+						"containerizedworkload.oam.crossplane.io/replicas": "[fromParam(replicas)]",
+						"oam.crossplane.io/last-reconciled-spec":           `{"image":"nginx:[fromParam(tag)]"}`,
+					},
+				),
+			},
+			want: want{w: cool(
+				map[string]interface{}{
+					"image":    "nginx:1.19",
+					"replicas": int64(3),
+					"env":      []interface{}{map[string]interface{}{"name": "WORKERS", "value": "3-workers"}},
+				},
+				map[string]string{
+					"containerizedworkload.oam.crossplane.io/replicas": "3",
+					"oam.crossplane.io/last-reconciled-spec":           `{"image":"nginx:[fromParam(tag)]"}`,
+					AnnotationParameters:                               `{"replicas":3,"tag":"1.19"}`,
+				},
+			)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewParameterSubstitutor(tc.args.values).Resolve(context.Background(), tc.args.w)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ns.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\nReason: %s\ns.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplicationConfigurationParameters(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	controlled := func(labels map[string]string) Workload {
+		w := NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind)
+		w.SetName("cool-workload")
+		w.SetLabels(labels)
+		c := true
+		w.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+			Kind:       oamv1alpha2.ApplicationConfigurationKind,
+			Name:       "cool-app",
+			Controller: &c,
+		}})
+		return w
+	}
+
+	ac := func(obj runtime.Object) error {
+		*obj.(*oamv1alpha2.ApplicationConfiguration) = oamv1alpha2.ApplicationConfiguration{
+			Spec: oamv1alpha2.ApplicationConfigurationSpec{
+				Components: []oamv1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "cool-component",
+					ParameterValues: []oamv1alpha2.ComponentParameterValue{
+						{Name: "tag", Value: intstr.FromString("1.19")},
+					},
+				}},
+			},
+		}
+		return nil
+	}
+
+	type want struct {
+		values map[string]intstr.IntOrString
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      Workload
+		want   want
+	}{
+		"NotControlled": {
+			reason: "A workload that is not controlled by an ApplicationConfiguration has no parameter values.",
+			c:      &test.MockClient{},
+			w:      NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind),
+			want:   want{err: errors.New(errNoAppConfig)},
+		},
+		"GetAppConfigError": {
+			reason: "Errors getting the ApplicationConfiguration should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      controlled(map[string]string{LabelComponent: "cool-component"}),
+			want:   want{err: errors.Wrap(errBoom, errGetAppConfig)},
+		},
+		"NoComponent": {
+			reason: "A workload whose component is not part of its ApplicationConfiguration should return an error.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil, ac)},
+			w:      controlled(nil),
+			want:   want{err: errors.Errorf("%s: %s", errNoComponent, "cool-workload")},
+		},
+		"Success": {
+			reason: "The parameter values of the workload's component should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil, ac)},
+			w:      controlled(map[string]string{LabelComponent: "cool-component"}),
+			want:   want{values: map[string]intstr.IntOrString{"tag": intstr.FromString("1.19")}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ApplicationConfigurationParameters(tc.c)(context.Background(), tc.w)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplicationConfigurationParameters(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.values, got); diff != "" {
+				t.Errorf("\nReason: %s\nApplicationConfigurationParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+)
+
+// TypePaused indicates whether the reconciliation of a workload or trait is
+// paused.
+const TypePaused runtimev1alpha1.ConditionType = "Paused"
+
+// Reasons a workload or trait is or is not paused.
+const (
+	ReasonPaused  runtimev1alpha1.ConditionReason = "PausedByAnnotation"
+	ReasonResumed runtimev1alpha1.ConditionReason = "Resumed"
+)
+
+// Paused returns a condition that indicates the reconciliation of a workload
+// or trait is paused, and that its remote package is left untouched.
+func Paused() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPaused,
+		Message:            fmt.Sprintf("reconciliation is paused until the %s annotation is removed", annotations.Paused),
+	}
+}
+
+// Resumed returns a condition that indicates the reconciliation of a workload
+// or trait that was paused has resumed.
+func Resumed() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResumed,
+	}
+}
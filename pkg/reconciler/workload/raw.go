@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	errDecodeRawTemplates     = "cannot decode raw templates"
+	errInvalidRawTemplate     = "raw template must specify an apiVersion, kind, and name"
+	errNamespacedRawTemplate  = "raw template must not specify a namespace"
+	errConflictingRawTemplate = "raw template conflicts with translated object"
+)
+
+// AnnotationRawTemplates may be set on a workload to a multi document YAML
+// stream of manifests that are appended to its translation, for example to
+// add a PodDisruptionBudget or ServiceMonitor that the translator does not
+// produce. Manifests are sent to the remote cluster as is, in the namespace of
+// the workload's other remote objects, and thus must not specify a namespace.
+const AnnotationRawTemplates = "workload.oam.crossplane.io/raw-templates"
+
+var _ TranslationWrapper = RawTemplateWrapper
+
+// RawTemplateWrapper appends the raw templates of a workload annotated with
+// AnnotationRawTemplates to its translation. It returns an error if a raw
+// template is of the same kind and name as an object of the translation. The
+// translations of workloads without raw templates are returned unchanged.
+func RawTemplateWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	raw := w.GetAnnotations()[AnnotationRawTemplates]
+	if strings.TrimSpace(raw) == "" {
+		return objs, nil
+	}
+
+	type key struct{ kind, name string }
+	exists := map[key]bool{}
+	for _, o := range objs {
+		exists[key{kind: o.GetObjectKind().GroupVersionKind().Kind, name: o.GetName()}] = true
+	}
+
+	d := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(raw), 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errDecodeRawTemplates)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		if u.GetAPIVersion() == "" || u.GetKind() == "" || u.GetName() == "" {
+			return nil, errors.New(errInvalidRawTemplate)
+		}
+		if u.GetNamespace() != "" {
+			return nil, errors.Errorf("%s: %s %s", errNamespacedRawTemplate, u.GetKind(), u.GetName())
+		}
+		k := key{kind: u.GetKind(), name: u.GetName()}
+		if exists[k] {
+			return nil, errors.Errorf("%s: %s %s", errConflictingRawTemplate, u.GetKind(), u.GetName())
+		}
+		exists[k] = true
+		objs = append(objs, u)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestRawTemplateWrapper(t *testing.T) {
+	withRaw := func(raw string) Workload {
+		return &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationRawTemplates: raw}}}
+	}
+	d := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+	}
+	pdb := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy/v1beta1",
+		"kind":       "PodDisruptionBudget",
+		"metadata":   map[string]interface{}{"name": "cool"},
+		"spec":       map[string]interface{}{"minAvailable": "50%"},
+	}}
+
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRawTemplates": {
+			reason: "The translation of a workload without raw templates should be returned unchanged.",
+			args:   args{w: &workloadfake.Workload{}, o: []Object{d}},
+			want:   want{result: []Object{d}},
+		},
+		"DecodeError": {
+			reason: "Raw templates that cannot be decoded should return an error.",
+			args:   args{w: withRaw("{"), o: []Object{d}},
+			want:   want{err: errors.Wrap(errors.New("unexpected EOF"), errDecodeRawTemplates)},
+		},
+		"Invalid": {
+			reason: "Raw templates without a kind should return an error.",
+			args:   args{w: withRaw("apiVersion: v1\nmetadata:\n  name: cool\n"), o: []Object{d}},
+			want:   want{err: errors.New(errInvalidRawTemplate)},
+		},
+		"Namespaced": {
+			reason: "Raw templates that specify a namespace should return an error.",
+			args:   args{w: withRaw("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cool\n  namespace: coolns\n"), o: []Object{d}},
+			want:   want{err: errors.Errorf("%s: %s %s", errNamespacedRawTemplate, "ConfigMap", "cool")},
+		},
+		"Conflict": {
+			reason: "Raw templates of the same kind and name as a translated object should return an error.",
+			args:   args{w: withRaw("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: cool\n"), o: []Object{d}},
+			want:   want{err: errors.Errorf("%s: %s %s", errConflictingRawTemplate, "Deployment", "cool")},
+		},
+		"Appended": {
+			reason: "Raw templates should be appended to the translation, skipping empty documents.",
+			args: args{
+				w: withRaw("---\napiVersion: policy/v1beta1\nkind: PodDisruptionBudget\nmetadata:\n  name: cool\nspec:\n  minAvailable: 50%\n---\n"),
+				o: []Object{d},
+			},
+			want: want{result: []Object{d, pdb}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := RawTemplateWrapper(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRawTemplateWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\nReason: %s\nRawTemplateWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,13 +18,19 @@ package workload
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -34,6 +40,14 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/changelog"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/expiry"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/message"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/trace"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/version"
 )
 
 const (
@@ -42,20 +56,71 @@ const (
 	longWait         = 1 * time.Minute
 )
 
+// Names of the spans recorded by the Reconciler.
+const (
+	spanReconcile   = "workload.Reconcile"
+	spanGetWorkload = "workload.GetWorkload"
+	spanTranslate   = "workload.Translate"
+	spanPackage     = "workload.Package"
+	spanApply       = "workload.Apply"
+)
+
 // Reconcile error strings.
 const (
+	errClearReconcileRequest    = "cannot clear reconcile request"
+	errRecordChangelog          = "cannot record workload changelog"
 	errGetWorkload              = "cannot get workload"
 	errUpdateWorkloadStatus     = "cannot update workload status"
+	errResolveParameters        = "cannot resolve workload parameters"
 	errTranslateWorkload        = "cannot translate workload"
+	errValidateTranslation      = "cannot validate workload translation"
+	errPackageWorkload          = "cannot package workload translation"
 	errApplyWorkloadTranslation = "cannot apply workload translation"
+	errRecordRevision           = "cannot record workload revision"
+	errPublishConnection        = "cannot publish workload connection details"
+	errRollbackWorkload         = "cannot roll back workload"
+	errGetTTL                   = "cannot get workload TTL"
+	errExpireWorkload           = "cannot tear down expired workload"
+	errRecordPackageKinds       = "cannot record workload package kinds"
+	errRecordPackageReferences  = "cannot record workload package references"
+	errStorePackage             = "cannot store workload package"
+	errPinShards                = "cannot pin workload package shards to the target of the first shard"
+	errGateWaves                = "cannot withhold workload package apply waves"
+	errPruneShards              = "cannot delete unused workload package shards"
+	errRecordPackage            = "cannot record workload package"
+	errObserveRollout           = "cannot observe workload rollout"
+	errPrePackageHook           = "pre-package hook failed"
+	errPostApplyHook            = "post-apply hook failed"
+	errHashWorkloadDefinition   = "cannot hash workload definition"
+	errAddFinalizer             = "cannot add finalizer to workload"
+	errRemoveFinalizer          = "cannot remove finalizer from workload"
+	errFinalizeWorkload         = "cannot delete package of deleted workload"
 )
 
 // Reconcile event reasons.
 const (
 	reasonTranslateWorkload = "WorkloadTranslated"
+	reasonRollbackWorkload  = "WorkloadRolledBack"
+	reasonSpecChanged       = "SpecChanged"
+	reasonExpireWorkload    = "WorkloadExpired"
+	reasonPaused            = "ReconciliationPaused"
+	reasonResumed           = "ReconciliationResumed"
 
+	reasonCannotResolveParameters        = "CannotResolveWorkloadParameters"
 	reasonCannotTranslateWorkload        = "CannotTranslateWorkload"
+	reasonCannotValidateTranslation      = "CannotValidateWorkloadTranslation"
+	reasonCannotPackageWorkload          = "CannotPackageWorkload"
 	reasonCannotApplyWorkloadTranslation = "CannotApplyWorkloadTranslation"
+	reasonCannotRecordRevision           = "CannotRecordRevision"
+	reasonCannotPublishConnection        = "CannotPublishConnectionDetails"
+	reasonCannotRollbackWorkload         = "CannotRollbackWorkload"
+	reasonCannotGetTTL                   = "CannotGetWorkloadTTL"
+	reasonCannotExpireWorkload           = "CannotExpireWorkload"
+	reasonCannotDeletePackage            = "CannotDeleteWorkloadPackage"
+	reasonCannotStorePackage             = "CannotStorePackage"
+	reasonCannotObserveRollout           = "CannotObserveRollout"
+	reasonPrePackageHookFailed           = "PrePackageHookFailed"
+	reasonPostApplyHookFailed            = "PostApplyHookFailed"
 )
 
 // A ReconcilerOption configures a Reconciler.
@@ -82,6 +147,30 @@ func WithTranslator(t Translator) ReconcilerOption {
 	}
 }
 
+// WithParameterResolver specifies how the Reconciler should resolve the
+// parameters a workload references before it is translated.
+func WithParameterResolver(p ParameterResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.params = p
+	}
+}
+
+// WithValidator specifies how the Reconciler should validate the workload
+// translation before it is packaged.
+func WithValidator(v Validator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.validator = v
+	}
+}
+
+// WithPackager specifies how the Reconciler should package the objects a
+// workload was translated into.
+func WithPackager(p Packager) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.packager = p
+	}
+}
+
 // WithApplicator specifies how the Reconciler should apply the workload
 // translation.
 func WithApplicator(a resource.Applicator) ReconcilerOption {
@@ -90,6 +179,14 @@ func WithApplicator(a resource.Applicator) ReconcilerOption {
 	}
 }
 
+// WithMaxConcurrentApplies specifies the maximum number of objects of a
+// workload's translation the Reconciler may apply concurrently.
+func WithMaxConcurrentApplies(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.workers = n
+	}
+}
+
 // WithApplyOptions specifies options to pass to the applicator.
 func WithApplyOptions(a ...resource.ApplyOption) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -97,17 +194,298 @@ func WithApplyOptions(a ...resource.ApplyOption) ReconcilerOption {
 	}
 }
 
+// WithFeatureGates specifies the feature gates that are recorded in the render
+// inputs of each workload translation.
+func WithFeatureGates(gates ...string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.featureGates = gates
+	}
+}
+
+// WithDefinitionHasher specifies how the Reconciler should hash the
+// WorkloadDefinition of its workload kind, which is recorded in the render
+// inputs of each workload translation.
+func WithDefinitionHasher(h DefinitionHasher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.definition = h
+	}
+}
+
+// WithRemoteNamespace specifies the remote namespace in which the translation
+// of each workload should be created.
+func WithRemoteNamespace(n RemoteNamespacer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.remoteNamespace = n
+	}
+}
+
+// WithDeletionPropagation specifies the propagation policy that should be used
+// when the Reconciler deletes the package of a workload, for example when the
+// workload expires. The remote objects of a KubernetesApplication are deleted
+// by Crossplane, which does not support a propagation policy.
+func WithDeletionPropagation(p metav1.DeletionPropagation) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.propagation = p
+	}
+}
+
+// WithRevisionTracker specifies how the Reconciler should record the revisions
+// of a workload's translation.
+func WithRevisionTracker(t RevisionTracker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.revisions = t
+	}
+}
+
+// WithObjectNamer specifies how the Reconciler should name the top-level
+// objects of a workload's translation.
+func WithObjectNamer(n ObjectNamer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.name = n
+	}
+}
+
+// WithPackageNamer specifies how the Reconciler should name and namespace
+// the package of each workload, for example to enforce a naming convention.
+// Kubernetes does not permit a package to be controlled by a workload in
+// another namespace, so namespaced packages should be named in the namespace
+// of their workload. Traits must be configured with the same PackageNamer in
+// order to find the packages they modify.
+func WithPackageNamer(n PackageNamer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.name = NamePackages(n)
+	}
+}
+
+// WithConnectionPublisher specifies how the Reconciler should publish the
+// connection details of each workload.
+func WithConnectionPublisher(p ConnectionPublisher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.connection = p
+	}
+}
+
+// WithRolloutObserver specifies how the Reconciler should observe the rollout
+// of the remote Deployments of each workload's package.
+func WithRolloutObserver(o RolloutObserver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.rollout = o
+	}
+}
+
+// WithChangelog specifies that the Reconciler should record a changelog of the
+// changes users make to each workload's spec.
+func WithChangelog() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.changelog = true
+	}
+}
+
+// WithSelector specifies which workloads the Reconciler should reconcile.
+// Workloads whose labels do not match the supplied selector are ignored, so
+// that they may be reconciled by another instance of the addon. A nil selector
+// selects every workload.
+func WithSelector(s labels.Selector) ReconcilerOption {
+	return func(r *Reconciler) {
+		if s == nil {
+			s = labels.Everything()
+		}
+		r.selector = s
+	}
+}
+
+// WithMessageCatalog specifies how the Reconciler should replace the reasons
+// and messages of the events and conditions it emits.
+func WithMessageCatalog(c message.Catalog) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.messages = c
+	}
+}
+
+// WithPackageKinds specifies that the Reconciler should record the kinds each
+// workload was packaged as, so that traits can detect that they modify a kind
+// of package the workload was not packaged as.
+func WithPackageKinds() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.packageKinds = true
+	}
+}
+
+// WithWaveGate specifies that the Reconciler should withhold the resource
+// templates of each KubernetesApplication it applies until every template of
+// an earlier apply wave has been submitted to the remote cluster.
+func WithWaveGate() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.waves = true
+	}
+}
+
+// WithPackageSink specifies where the Reconciler should store the package of
+// each workload before it is applied, for example so that it may be reviewed.
+func WithPackageSink(s PackageSink) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.sink = s
+	}
+}
+
+// WithoutApply specifies that the Reconciler should store the package of each
+// workload in its package sink instead of applying it, for example because it
+// is applied by a GitOps tool once it has been reviewed.
+func WithoutApply() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.skipApply = true
+	}
+}
+
+// WithPackageDeleter specifies how the Reconciler should delete the package of
+// each workload that is deleted or whose TTL has elapsed. Workloads are given
+// a finalizer so that their package is deleted before they are, because it is
+// assumed that the package cannot be garbage collected.
+func WithPackageDeleter(d PackageDeleter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.deleter = d
+		r.finalizer = true
+	}
+}
+
+// WithExpiryScheduler specifies how the Reconciler should schedule reconciles
+// of workloads at the time their TTL elapses.
+func WithExpiryScheduler(s expiry.Scheduler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.expiry = s
+	}
+}
+
+// WithUnstructured specifies that the Reconciler should reconcile its workload
+// kind as an Unstructured workload. This allows kinds that are not registered
+// with the manager's scheme to be reconciled.
+func WithUnstructured() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = NewUnstructuredClient(r.client)
+		r.newWorkload = func() Workload { return NewUnstructured(schema.GroupVersionKind(r.kind)) }
+	}
+}
+
+// WithTranslationCache specifies how the Reconciler should cache the packaged
+// translation of each workload.
+func WithTranslationCache(c TranslationCache) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.cache = c
+	}
+}
+
+// WithTracer specifies how the Reconciler should record spans of the phases
+// of each reconcile. A nil Tracer records no spans.
+func WithTracer(t trace.Tracer) ReconcilerOption {
+	return func(r *Reconciler) {
+		if t == nil {
+			t = trace.NopTracer{}
+		}
+		r.tracer = t
+	}
+}
+
+// WithPrePackageHook specifies a hook the Reconciler should call with the
+// translation of each workload before it is packaged. Hooks are called in the
+// order they were supplied, and are not called for cached translations.
+func WithPrePackageHook(h PrePackageHook) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.prePackage = append(r.prePackage, h)
+	}
+}
+
+// WithPostApplyHook specifies a hook the Reconciler should call with the
+// package of each workload once it has been applied. Hooks are called in the
+// order they were supplied, and are not called if the Reconciler does not
+// apply packages.
+func WithPostApplyHook(h PostApplyHook) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.postApply = append(r.postApply, h)
+	}
+}
+
 // A Reconciler reconciles an OAM workload type by packaging it into a
 // KubernetesApplication.
 type Reconciler struct {
-	client      client.Client
-	newWorkload func() Workload
-	workload    Translator
-	applicator  resource.Applicator
-	applyOpts   []resource.ApplyOption
+	client       client.Client
+	kind         Kind
+	newWorkload  func() Workload
+	workload     Translator
+	params       ParameterResolver
+	validator    Validator
+	packager     Packager
+	applicator   resource.Applicator
+	applyOpts    []resource.ApplyOption
+	workers      int
+	featureGates []string
+	definition   DefinitionHasher
+	changelog    bool
+	packageKinds bool
+	skipApply    bool
+	waves        bool
+	typer        runtime.ObjectTyper
 
-	log    logging.Logger
-	record event.Recorder
+	remoteNamespace RemoteNamespacer
+	propagation     metav1.DeletionPropagation
+	revisions       RevisionTracker
+	connection      ConnectionPublisher
+	rollout         RolloutObserver
+	expiry          expiry.Scheduler
+	deleter         PackageDeleter
+	finalizer       bool
+	cache           TranslationCache
+	tracer          trace.Tracer
+	sink            PackageSink
+	name            ObjectNamer
+	prePackage      []PrePackageHook
+	postApply       []PostApplyHook
+	selector        labels.Selector
+
+	log      logging.Logger
+	record   event.Recorder
+	messages message.Catalog
+}
+
+// An ObjectNamer names a top-level object of the supplied workload's
+// translation.
+type ObjectNamer func(w Workload, o Object)
+
+// A PackageNamer returns the name and namespace of the package of the
+// supplied workload. Packages with an empty namespace are cluster scoped.
+type PackageNamer func(w metav1.Object) types.NamespacedName
+
+// WorkloadName names packages after their workload, in the workload's
+// namespace.
+func WorkloadName(w metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: w.GetNamespace(), Name: w.GetName()}
+}
+
+// NamePackages returns an ObjectNamer that names each top-level object of a
+// workload's translation using the supplied PackageNamer. Shards of a package
+// other than the first are suffixed with their index.
+func NamePackages(n PackageNamer) ObjectNamer {
+	return func(w Workload, o Object) {
+		nn := n(w)
+		o.SetNamespace(nn.Namespace)
+
+		// All top-level objects must have the same name.
+		// TODO(hasheddan): this restriction means that you can only have one
+		// top-level object of a given kind per workload translation. In the
+		// future, it would be ideal to allow for multiple instances of a
+		// single object kind per workload translation. At that time, this
+		// naming restriction should be removed, and the trait reconciler
+		// should list objects by the workload label the Reconciler adds. The
+		// shards of a package that was too large to apply as one object are
+		// the exception.
+		o.SetName(shardName(nn.Name, o))
+	}
+}
+
+// NameAfterWorkload names each top-level object of a workload's translation
+// after the workload, in the workload's namespace. Shards of a package other
+// than the first are suffixed with their index.
+func NameAfterWorkload(w Workload, o Object) {
+	NamePackages(WorkloadName)(w, o)
 }
 
 // Kind is a kind of OAM workload.
@@ -135,10 +513,26 @@ func NewReconciler(m ctrl.Manager, workload Kind, o ...ReconcilerOption) *Reconc
 
 	r := &Reconciler{
 		client:      m.GetClient(),
+		kind:        workload,
 		newWorkload: nw,
+		typer:       m.GetScheme(),
 		workload:    TranslateFn(NoopTranslate),
-		applicator:  resource.ApplyFn(resource.Apply),
-		applyOpts:   []resource.ApplyOption{resource.ControllersMustMatch()},
+		params:      ParameterResolverFn(NopResolve),
+		definition:  DefinitionHasherFn(NopHashDefinition),
+		validator:   ValidateFn(NopValidate),
+		packager:    PackageFn(NoopPackage),
+		applicator:  NewDiffSuppressingApplicator(resource.ApplyFn(resource.Apply)),
+		applyOpts:   []resource.ApplyOption{ControllersMustMatch(AdoptionPolicyFail)},
+		revisions:   NopRevisionTracker{},
+		connection:  ConnectionPublisherFn(NopPublishConnection),
+		rollout:     RolloutObserverFn(NopObserveRollout),
+		expiry:      expiry.NopScheduler{},
+		deleter:     NewAPIPackageDeleter(m.GetClient()),
+		cache:       NopTranslationCache{},
+		tracer:      trace.NopTracer{},
+		sink:        PackageSinkFn(NopStorePackage),
+		name:        NameAfterWorkload,
+		selector:    labels.Everything(),
 		log:         logging.NewNopLogger(),
 		record:      event.NewNopRecorder(),
 	}
@@ -152,42 +546,190 @@ func NewReconciler(m ctrl.Manager, workload Kind, o ...ReconcilerOption) *Reconc
 
 // Reconcile an OAM workload type by packaging it into a KubernetesApplication.
 func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
-	log := r.log.WithValues("request", req)
+	log := trace.Logger(r.log).WithValues("request", req)
 	log.Debug("Reconciling")
 
 	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
 	defer cancel()
 
+	ctx, span := r.tracer.StartSpan(ctx, spanReconcile)
+	span.SetAttributes("kind", schema.GroupVersionKind(r.kind).String(), "request", req.String())
+	defer span.End(nil)
+
 	workload := r.newWorkload()
-	if err := r.client.Get(ctx, req.NamespacedName, workload); err != nil {
+	sctx, s := r.tracer.StartSpan(ctx, spanGetWorkload)
+	err := r.client.Get(sctx, req.NamespacedName, workload)
+	s.End(resource.IgnoreNotFound(err))
+	if err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			r.cache.Forget(req.NamespacedName)
+		}
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetWorkload)
 	}
 
 	log = log.WithValues("uid", workload.GetUID(), "version", workload.GetResourceVersion())
 
-	objs, err := r.workload.Translate(ctx, workload)
+	// Workloads that another instance of the addon is responsible for are
+	// left untouched.
+	if !r.selector.Matches(labels.Set(workload.GetLabels())) {
+		log.Debug("Workload is not selected")
+		return reconcile.Result{}, nil
+	}
+
+	// A workload that is being deleted has its package deleted first if the
+	// package cannot be garbage collected.
+	if meta.WasDeleted(workload) {
+		return r.finalize(ctx, log, workload)
+	}
+	if r.finalizer && !meta.FinalizerExists(workload, Finalizer) {
+		meta.AddFinalizer(workload, Finalizer)
+		if err := r.client.Update(ctx, workload); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	// A reconcile that was explicitly requested is happening now, so we clear
+	// the request before we do anything else.
+	requested := annotations.ReconcileRequested(workload)
+	if requested {
+		log.Debug("Reconcile requested", "requested-at", workload.GetAnnotations()[annotations.ReconcileNow])
+		annotations.ClearReconcileRequest(workload)
+		if err := r.client.Update(ctx, workload); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errClearReconcileRequest)
+		}
+	}
+
+	// A paused workload is not translated, and its package is left untouched
+	// until it is resumed. Removing the annotation updates the workload, so
+	// there is no need to requeue.
+	if annotations.IsPaused(workload) {
+		log.Debug("Reconciliation is paused")
+		if workload.GetCondition(TypePaused).Status != corev1.ConditionTrue {
+			r.record.Event(workload, r.messages.Event(event.Normal(reasonPaused, "Reconciliation paused")))
+		}
+		workload.SetConditions(r.messages.Conditions(Paused())...)
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+	// The changelog is recorded in an annotation, so it must be recorded
+	// before the status of the workload is modified.
+	if r.changelog {
+		if err := r.recordChangelog(ctx, workload); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errRecordChangelog)
+		}
+	}
+
+	if workload.GetCondition(TypePaused).Status == corev1.ConditionTrue {
+		r.record.Event(workload, r.messages.Event(event.Normal(reasonResumed, "Reconciliation resumed")))
+		workload.SetConditions(r.messages.Conditions(Resumed())...)
+	}
+
+	// A workload whose TTL has elapsed has its package torn down instead of
+	// translated. Workloads that have not yet expired are reconciled again
+	// when they expire, regardless of the sync period.
+	at, expires, err := ExpiresAt(workload)
+	switch {
+	case err != nil:
+		log.Debug("Cannot get workload TTL", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotGetTTL, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errGetTTL)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	case !expires:
+		r.expiry.Cancel(req.NamespacedName)
+	case !time.Now().Before(at):
+		return r.expire(ctx, log, workload)
+	default:
+		r.expiry.Schedule(req.NamespacedName, at)
+	}
+
+	// A workload that is being rolled back has the translation recorded as
+	// the requested revision applied instead of a new translation.
+	if rev, rollback, err := rollbackRevision(workload); rollback {
+		return r.rollback(ctx, log, workload, rev, err)
+	}
+
+	// The parameters a workload references are resolved in a copy of the
+	// workload that is only used to render its package.
+	resolved, err := r.params.Resolve(ctx, workload)
 	if err != nil {
-		log.Debug("Cannot translate workload", "error", err, "requeue-after", time.Now().Add(shortWait))
-		r.record.Event(workload, event.Warning(reasonCannotTranslateWorkload, err))
-		workload.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errTranslateWorkload)))
+		log.Debug("Cannot resolve workload parameters", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotResolveParameters, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errResolveParameters)))...)
 		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
 	}
 
-	for _, o := range objs {
-		// A workload's translation must be controlled by the workload.
-		meta.AddOwnerReference(o, *metav1.NewControllerRef(workload, workload.GetObjectKind().GroupVersionKind()))
+	// Reconciles of workloads that have not changed since they were last
+	// translated, for example due to status updates or resyncs, use the
+	// cached translation. A workload whose reconcile was explicitly requested
+	// is always translated again, so that inputs the cache does not track,
+	// such as the Secrets and targets it references, are read again.
+	var objs []Object
+	cached := false
+	if !requested {
+		objs, cached = r.cache.Get(resolved)
+	}
+	span.SetAttributes("cached", cached)
+	if !cached {
+		sctx, s = r.tracer.StartSpan(ctx, spanTranslate)
+		objs, err = r.workload.Translate(sctx, resolved)
+		s.End(err)
+		if err != nil {
+			log.Debug("Cannot translate workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotTranslateWorkload, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errTranslateWorkload)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+		log.Debug("Translated workload", "generation", workload.GetGeneration(), "objects", describe(objs))
 
-		// All top-level objects must be in the same namespace as the workload.
-		o.SetNamespace(workload.GetNamespace())
+		if err := r.validator.Validate(ctx, objs); err != nil {
+			log.Debug("Cannot validate workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotValidateTranslation, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errValidateTranslation)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
 
-		// All top-level objects must have the same name as the workload.
-		// TODO(hasheddan): this restriction means that you can only have one
-		// top-level object of a given kind per workload translation. In the
-		// future, it would be ideal to allow for multiple instances of a single
-		// object kind per workload translation. At that time, this naming
-		// restriction should be removed, and the trait reconciler should list
-		// objects by labels added below.
-		o.SetName(workload.GetName())
+		if err := r.runPrePackageHooks(ctx, workload, objs); err != nil {
+			log.Debug("Pre-package hook failed", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonPrePackageHookFailed, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPrePackageHook)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+
+		sctx, s = r.tracer.StartSpan(ctx, spanPackage)
+		objs, err = r.packager.Package(sctx, resolved, objs)
+		s.End(err)
+		if err != nil {
+			log.Debug("Cannot package workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotPackageWorkload, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPackageWorkload)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+		log.Debug("Packaged workload translation", "objects", describe(objs))
+		r.cache.Set(resolved, objs)
+	} else {
+		log.Debug("Using cached workload translation", "generation", workload.GetGeneration(), "objects", describe(objs))
+	}
+
+	// The WorkloadDefinition of the workload's kind may change how it is
+	// rendered, so its hash is recorded in the render inputs.
+	defHash, err := r.definition.HashDefinition(ctx, schema.GroupVersionKind(r.kind))
+	if err != nil {
+		log.Debug("Cannot hash workload definition", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotTranslateWorkload, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errHashWorkloadDefinition)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	rendered := make([]Object, 0, len(objs))
+	for _, o := range objs {
+		r.name(workload, o)
+
+		// A workload's translation must be controlled by the workload.
+		// Kubernetes does not allow a namespaced workload to own cluster
+		// scoped objects or objects in other namespaces, so those are deleted
+		// by the PackageDeleter instead.
+		if o.GetNamespace() == workload.GetNamespace() {
+			meta.AddOwnerReference(o, *metav1.NewControllerRef(workload, workload.GetObjectKind().GroupVersionKind()))
+		}
 
 		// All top-level objects must have the workload label so that they can
 		// be listed by traits.
@@ -196,21 +738,611 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 		// so this label is not being used.
 		meta.AddLabels(o, map[string]string{lowerGroupKind(workload.GetObjectKind()): string(workload.GetUID())})
 
-		if err := r.applicator.Apply(ctx, r.client, o, r.applyOpts...); err != nil {
-			log.Debug("Cannot apply workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
-			r.record.Event(workload, event.Warning(reasonCannotApplyWorkloadTranslation, err))
-			workload.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyWorkloadTranslation)))
+		if a, ok := o.(*workloadv1alpha1.KubernetesApplication); ok {
+			if err := r.configureRemote(workload, a); err != nil {
+				log.Debug("Cannot configure remote resources", "error", err, "requeue-after", time.Now().Add(shortWait))
+				r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotTranslateWorkload, err)))
+				workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errTranslateWorkload)))...)
+				return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+			}
+		}
+
+		// All top-level objects record the inputs they were rendered from so
+		// that a render can be reproduced.
+		ri := &RenderInputs{
+			ControllerVersion:      version.Version,
+			FeatureGates:           r.featureGates,
+			WorkloadGeneration:     workload.GetGeneration(),
+			WorkloadDefinitionHash: defHash,
+		}
+		if err := SetRenderInputs(o, ri); err != nil {
+			log.Debug("Cannot record render inputs", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotTranslateWorkload, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errTranslateWorkload)))...)
 			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
 		}
+
+		// Applying an object updates it to reflect the state of the API
+		// server, so we record the object as it was rendered.
+		rendered = append(rendered, o.DeepCopyObject().(Object))
 	}
 
-	r.record.Event(workload, event.Normal(reasonTranslateWorkload, "Successfully translated workload"))
+	if err := r.sink.Store(ctx, workload, rendered); err != nil {
+		log.Debug("Cannot store workload package", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotStorePackage, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errStorePackage)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	// Every shard of a package must be scheduled to the same remote cluster,
+	// so shards other than the first are withheld until it is scheduled.
+	pinned, err := r.pinShards(ctx, objs)
+	if err != nil {
+		log.Debug("Cannot pin package shards", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPinShards)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+	withheld := len(pinned) < len(objs)
+	all := objs
+	objs = pinned
+
+	// Resource templates are applied to the remote cluster independently,
+	// so templates of later apply waves are withheld until earlier waves
+	// have been submitted.
+	objs, gated, err := r.gateWaves(ctx, workload, objs)
+	if err != nil {
+		log.Debug("Cannot withhold package apply waves", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errGateWaves)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+	withheld = withheld || gated
+
+	// Packages that are deleted, rather than garbage collected, are deleted
+	// using references recorded before they are applied, so that they can be
+	// deleted even if the workload can no longer be translated.
+	if r.finalizer || expires {
+		if err := r.recordPackageReferences(ctx, workload, all); err != nil {
+			log.Debug("Cannot record package references", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errRecordPackageReferences)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+	}
+
+	sctx, s = r.tracer.StartSpan(ctx, spanApply)
+	err = r.apply(sctx, log, objs)
+	s.End(err)
+	if err != nil {
+		log.Debug("Cannot apply workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyWorkloadTranslation)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	// Withheld shards are not pruned, so that shards that were already applied
+	// keep running until they can be pinned.
+	if err := r.pruneShards(ctx, workload, all); err != nil {
+		log.Debug("Cannot delete unused package shards", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPruneShards)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	if err := r.runPostApplyHooks(ctx, workload, objs); err != nil {
+		log.Debug("Post-apply hook failed", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonPostApplyHookFailed, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPostApplyHook)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	// Package kinds are recorded in an annotation, so they must be recorded
+	// before the status of the workload is modified below.
+	if r.packageKinds {
+		if err := r.recordPackageKinds(ctx, workload, objs); err != nil {
+			log.Debug("Cannot record package kinds", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errRecordPackageKinds)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+	}
+
+	if pr, ok := workload.(PackageRecorder); ok {
+		refs, err := r.references(objs)
+		if err != nil {
+			log.Debug("Cannot record package", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+			workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errRecordPackage)))...)
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+		}
+		pr.SetPackageReferences(refs)
+	}
+
+	h, err := r.revisions.Record(ctx, workload, rendered)
+	if err != nil {
+		log.Debug("Cannot record workload revision", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotRecordRevision, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errRecordRevision)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+	if rr, ok := workload.(RevisionRecorder); ok {
+		rr.SetRevisionHistory(h)
+	}
+
+	if err := r.connection.PublishConnection(ctx, workload); err != nil {
+		log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotPublishConnection, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPublishConnection)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	// Applied packages reflect their state in the API server, so we can tell
+	// whether they have been scheduled to a remote cluster yet. Packages that
+	// are stored rather than applied are never scheduled.
+	if !r.skipApply {
+		if c, ok := schedulingCondition(objs); ok {
+			workload.SetConditions(r.messages.Conditions(c)...)
+		}
+	}
+
+	// Workloads whose remote Deployments are still rolling out are
+	// reconciled again sooner, so that their rollout is reflected promptly.
+	// Workloads whose package shards or apply waves were withheld are also
+	// reconciled again sooner, so that they are applied soon after the objects
+	// they wait for.
+	wait := longWait
+	if withheld {
+		wait = shortWait
+	}
+	c, ok, err := r.rollout.ObserveRollout(ctx, objs)
+	if err != nil {
+		log.Debug("Cannot observe workload rollout", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotObserveRollout, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errObserveRollout)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+	if ok {
+		workload.SetConditions(r.messages.Conditions(c)...)
+		if c.Reason == ReasonRolloutProgressing {
+			wait = shortWait
+		}
+	}
+
+	r.record.Event(workload, r.messages.Event(event.Normal(reasonTranslateWorkload, "Successfully translated workload")))
 	log.Debug("Successfully translated workload", "kind", workload.GetObjectKind().GroupVersionKind().String())
 
-	workload.SetConditions(v1alpha1.ReconcileSuccess())
+	workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileSuccess())...)
+	return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+}
+
+// recordChangelog records any changes to the spec of the supplied workload
+// since it was last reconciled, and emits an event describing them.
+func (r *Reconciler) recordChangelog(ctx context.Context, workload Workload) error {
+	e, updated, err := changelog.Record(workload)
+	if err != nil {
+		return err
+	}
+	if updated {
+		if err := r.client.Update(ctx, workload); err != nil {
+			return err
+		}
+	}
+	if e != nil {
+		r.record.Event(workload, r.messages.Event(event.Normal(reasonSpecChanged, strings.Join(e.Changes, ", "))))
+	}
+	if cr, ok := workload.(changelog.Recorder); ok {
+		cr.SetChangelog(changelog.Get(workload))
+	}
+	return nil
+}
+
+// recordPackageKinds records the kinds of the supplied top-level objects on the
+// supplied workload, updating the workload only if they changed.
+func (r *Reconciler) recordPackageKinds(ctx context.Context, workload Workload, objs []Object) error {
+	kinds := make([]string, 0, len(objs))
+	for _, o := range objs {
+		gvk, err := r.kindOf(o)
+		if err != nil {
+			return err
+		}
+		kinds = append(kinds, strings.ToLower(gvk.GroupKind().String()))
+	}
+	if !annotations.SetPackageKinds(workload, kinds) {
+		return nil
+	}
+
+	// Updating the workload would reset any changes to its status that have
+	// yet to be persisted, such as its conditions, so we update a copy.
+	u := workload.DeepCopyObject().(Workload)
+	if err := r.client.Update(ctx, u); err != nil {
+		return err
+	}
+	workload.SetResourceVersion(u.GetResourceVersion())
+	return nil
+}
+
+// recordPackageReferences records references to the supplied top-level
+// objects in an annotation of the supplied workload.
+func (r *Reconciler) recordPackageReferences(ctx context.Context, workload Workload, objs []Object) error {
+	refs, err := r.references(objs)
+	if err != nil {
+		return err
+	}
+	if !annotations.SetPackageReferences(workload, refs) {
+		return nil
+	}
+
+	// Updating the workload would reset any changes to its status that have
+	// yet to be persisted, such as its conditions, so we update a copy.
+	u := workload.DeepCopyObject().(Workload)
+	if err := r.client.Update(ctx, u); err != nil {
+		return err
+	}
+	workload.SetResourceVersion(u.GetResourceVersion())
+	return nil
+}
+
+// references returns references to the supplied top-level objects.
+func (r *Reconciler) references(objs []Object) ([]corev1.ObjectReference, error) {
+	refs := make([]corev1.ObjectReference, 0, len(objs))
+	for _, o := range objs {
+		gvk, err := r.kindOf(o)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  o.GetNamespace(),
+			Name:       o.GetName(),
+		})
+	}
+	return refs, nil
+}
+
+// kindOf returns the kind of the supplied object. Typed objects do not
+// necessarily know their own kind.
+func (r *Reconciler) kindOf(o Object) (schema.GroupVersionKind, error) {
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if !gvk.Empty() {
+		return gvk, nil
+	}
+	gvks, _, err := r.typer.ObjectKinds(o)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return gvks[0], nil
+}
+
+// pinShards schedules every shard of the supplied package to the
+// KubernetesTarget its first shard is scheduled to, so that a package is never
+// split across remote clusters. Shards other than the first are withheld until
+// the first is scheduled. Packages that were not sharded, or that are not
+// applied, are returned unchanged.
+func (r *Reconciler) pinShards(ctx context.Context, objs []Object) ([]Object, error) {
+	if r.skipApply || !sharded(objs) {
+		return objs, nil
+	}
+
+	var first *workloadv1alpha1.KubernetesApplication
+	for _, o := range objs {
+		if a, ok := o.(*workloadv1alpha1.KubernetesApplication); ok && a.GetAnnotations()[AnnotationShard] == "0" {
+			first = a
+		}
+	}
+	if first == nil {
+		return objs, nil
+	}
+
+	// A target named by the workload is pinned when it is packaged. Otherwise
+	// the first shard is scheduled by Crossplane once it has been applied.
+	target := first.Spec.Target
+	if target == nil {
+		current := &workloadv1alpha1.KubernetesApplication{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: first.GetNamespace(), Name: first.GetName()}, current)
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		target = current.Spec.Target
+	}
+
+	out := make([]Object, 0, len(objs))
+	for _, o := range objs {
+		a, ok := o.(*workloadv1alpha1.KubernetesApplication)
+		if !ok || a.GetAnnotations()[AnnotationShard] == "" {
+			out = append(out, o)
+			continue
+		}
+		if target == nil {
+			if a == first {
+				out = append(out, a)
+			}
+			continue
+		}
+		a.Spec.Target = &workloadv1alpha1.KubernetesTargetReference{Name: target.Name}
+		a.Spec.TargetSelector = nil
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// gateWaves withholds the resource templates of the KubernetesApplications of
+// the supplied package that must wait for an earlier apply wave to be
+// submitted to the remote cluster, and returns true if any were withheld.
+// Packages are returned unchanged if waves are not gated, or packages are not
+// applied.
+func (r *Reconciler) gateWaves(ctx context.Context, workload Workload, objs []Object) ([]Object, bool, error) {
+	if !r.waves || r.skipApply {
+		return objs, false, nil
+	}
+
+	out := make([]Object, len(objs))
+	desired := []*workloadv1alpha1.KubernetesApplication{}
+	current := []*workloadv1alpha1.KubernetesApplication{}
+	for i, o := range objs {
+		out[i] = o
+		a, ok := o.(*workloadv1alpha1.KubernetesApplication)
+		if !ok {
+			continue
+		}
+
+		// Cached packages must not be modified.
+		a = a.DeepCopy()
+		out[i] = a
+		desired = append(desired, a)
+
+		c := &workloadv1alpha1.KubernetesApplication{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}, c); resource.IgnoreNotFound(err) != nil {
+			return nil, false, err
+		}
+		current = append(current, c)
+	}
+	if len(desired) == 0 {
+		return objs, false, nil
+	}
+
+	l := &workloadv1alpha1.KubernetesApplicationResourceList{}
+	if err := r.client.List(ctx, l, client.InNamespace(desired[0].GetNamespace()), client.MatchingLabels{labelKey: string(workload.GetUID())}); err != nil {
+		return nil, false, err
+	}
+
+	withheld, err := WithholdWaves(desired, current, l.Items)
+	return out, withheld, err
+}
+
+// pruneShards deletes the shards of the supplied workload's package that are
+// not among the supplied top-level objects, for example because its package
+// has shrunk. Nothing is deleted if the package was not sharded, or if the
+// Reconciler does not apply packages.
+func (r *Reconciler) pruneShards(ctx context.Context, workload Workload, objs []Object) error {
+	if r.skipApply || !sharded(objs) {
+		return nil
+	}
+
+	current := map[string]bool{}
+	for _, o := range objs {
+		current[o.GetName()] = true
+	}
+
+	l := &workloadv1alpha1.KubernetesApplicationList{}
+	if err := r.client.List(ctx, l, client.InNamespace(objs[0].GetNamespace()), client.MatchingLabels{lowerGroupKind(workload.GetObjectKind()): string(workload.GetUID())}); err != nil {
+		return err
+	}
+	for i := range l.Items {
+		a := &l.Items[i]
+		if _, ok := a.GetAnnotations()[AnnotationShard]; !ok || current[a.GetName()] || !metav1.IsControlledBy(a, workload) {
+			continue
+		}
+		if err := resource.IgnoreNotFound(r.client.Delete(ctx, a)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expire tears down the package of the supplied workload, whose TTL has
+// elapsed.
+func (r *Reconciler) expire(ctx context.Context, log logging.Logger, workload Workload) (reconcile.Result, error) {
+	if err := r.deletePackage(ctx, workload); err != nil {
+		log.Debug("Cannot tear down expired workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotExpireWorkload, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errExpireWorkload)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	if workload.GetCondition(v1alpha1.TypeReady).Reason != ReasonExpired {
+		r.record.Event(workload, r.messages.Event(event.Normal(reasonExpireWorkload, "Tore down package of expired workload")))
+		log.Debug("Tore down package of expired workload")
+	}
+
+	workload.SetConditions(r.messages.Conditions(Expired(), v1alpha1.ReconcileSuccess())...)
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+}
+
+// finalize deletes the package of the supplied workload, which is being
+// deleted, then removes its finalizer.
+func (r *Reconciler) finalize(ctx context.Context, log logging.Logger, workload Workload) (reconcile.Result, error) {
+	if !meta.FinalizerExists(workload, Finalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.deletePackage(ctx, workload); err != nil {
+		log.Debug("Cannot delete package of deleted workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotDeletePackage, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errFinalizeWorkload)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	meta.RemoveFinalizer(workload, Finalizer)
+	return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, workload), errRemoveFinalizer)
+}
+
+// deletePackage deletes the package of the supplied workload. The top-level
+// objects that must be deleted are those recorded when the package was last
+// applied; the workload is not translated again, because it may no longer
+// translate.
+func (r *Reconciler) deletePackage(ctx context.Context, workload Workload) error {
+	refs, err := annotations.PackageReferences(workload)
+	if err != nil {
+		return err
+	}
+	objs := make([]Object, 0, len(refs))
+	for _, ref := range refs {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		u.SetNamespace(ref.Namespace)
+		u.SetName(ref.Name)
+		objs = append(objs, u)
+	}
+	if r.propagation != "" {
+		return r.deleter.Delete(ctx, workload, objs, client.PropagationPolicy(r.propagation))
+	}
+	return r.deleter.Delete(ctx, workload, objs)
+}
+
+// rollback applies the translation recorded as the supplied revision of the
+// supplied workload.
+func (r *Reconciler) rollback(ctx context.Context, log logging.Logger, workload Workload, rev int64, err error) (reconcile.Result, error) {
+	log = log.WithValues("revision", rev)
+
+	var objs []Object
+	if err == nil {
+		objs, err = r.revisions.Get(ctx, workload, rev)
+	}
+	if err != nil {
+		log.Debug("Cannot roll back workload", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotRollbackWorkload, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errRollbackWorkload)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	if err := r.sink.Store(ctx, workload, objs); err != nil {
+		log.Debug("Cannot store workload package", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotStorePackage, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errStorePackage)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	if err := r.apply(ctx, log, objs); err != nil {
+		log.Debug("Cannot apply workload translation", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonCannotApplyWorkloadTranslation, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyWorkloadTranslation)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	if err := r.runPostApplyHooks(ctx, workload, objs); err != nil {
+		log.Debug("Post-apply hook failed", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(workload, r.messages.Event(event.Warning(reasonPostApplyHookFailed, err)))
+		workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileError(errors.Wrap(err, errPostApplyHook)))...)
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
+	}
+
+	r.record.Event(workload, r.messages.Event(event.Normal(reasonRollbackWorkload, fmt.Sprintf("Rolled back to revision %d", rev))))
+	log.Debug("Successfully rolled back workload")
+
+	workload.SetConditions(r.messages.Conditions(v1alpha1.ReconcileSuccess())...)
 	return reconcile.Result{RequeueAfter: longWait}, errors.Wrap(r.client.Status().Update(ctx, workload), errUpdateWorkloadStatus)
 }
 
+// apply the supplied objects using up to r.workers concurrent workers. Objects
+// are applied in order by a single worker, which stops at the first error.
+// When there are multiple workers the first error in order of the supplied
+// objects is returned once all objects have been applied. Nothing is applied
+// if the Reconciler does not apply packages. Existing packages that are left
+// unchanged per the AdoptionPolicyOrphan policy are skipped.
+func (r *Reconciler) apply(ctx context.Context, log logging.Logger, objs []Object) error {
+	if r.skipApply {
+		return nil
+	}
+	if r.workers <= 1 {
+		for _, o := range objs {
+			if err := r.applyOne(ctx, log, o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(objs))
+	sem := make(chan struct{}, r.workers)
+	wg := sync.WaitGroup{}
+	for i := range objs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = r.applyOne(ctx, log, objs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) applyOne(ctx context.Context, log logging.Logger, o Object) error {
+	err := r.applicator.Apply(ctx, r.client, o, r.applyOpts...)
+	if IsOrphaned(err) {
+		log.Debug("Existing package has no controller and was left unchanged", "name", o.GetName(), "namespace", o.GetNamespace())
+		return nil
+	}
+	return err
+}
+
+// runPrePackageHooks calls each pre-package hook with the supplied translation
+// of the supplied workload, stopping at the first error.
+func (r *Reconciler) runPrePackageHooks(ctx context.Context, workload Workload, objs []Object) error {
+	for _, h := range r.prePackage {
+		if err := h.PrePackage(ctx, workload, objs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPostApplyHooks calls each post-apply hook with the supplied package of the
+// supplied workload, stopping at the first error. No hooks are called if the
+// Reconciler does not apply packages.
+func (r *Reconciler) runPostApplyHooks(ctx context.Context, workload Workload, objs []Object) error {
+	if r.skipApply {
+		return nil
+	}
+	for _, h := range r.postApply {
+		if err := h.PostApply(ctx, workload, objs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureRemote configures the remote namespace of the resource templates of
+// the supplied KubernetesApplication.
+func (r *Reconciler) configureRemote(w Workload, a *workloadv1alpha1.KubernetesApplication) error {
+	if r.remoteNamespace == nil {
+		return nil
+	}
+	ns := r.remoteNamespace(w)
+	if ns == "" {
+		return nil
+	}
+	return setRemoteNamespace(a, ns)
+}
+
 func lowerGroupKind(gk schema.ObjectKind) string {
 	return strings.ToLower(gk.GroupVersionKind().GroupKind().String())
 }
+
+// describe the supplied objects for logging.
+func describe(objs []Object) string {
+	ro := make([]runtime.Object, len(objs))
+	for i := range objs {
+		ro[i] = objs[i]
+	}
+	return trace.Describe(ro...)
+}
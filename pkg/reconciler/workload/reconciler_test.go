@@ -18,12 +18,17 @@ package workload
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,12 +39,27 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
 
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
 	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
 )
 
 var _ reconcile.Reconciler = &Reconciler{}
 
+type mockRevisionTracker struct {
+	objs []Object
+	err  error
+}
+
+func (m *mockRevisionTracker) Record(_ context.Context, _ Workload, _ []Object) (RevisionHistory, error) {
+	return RevisionHistory{}, m.err
+}
+
+func (m *mockRevisionTracker) Get(_ context.Context, _ Workload, _ int64) ([]Object, error) {
+	return m.objs, m.err
+}
+
 func TestReconciler(t *testing.T) {
 	type args struct {
 		m manager.Manager
@@ -54,6 +74,9 @@ func TestReconciler(t *testing.T) {
 
 	errBoom := errors.New("boom")
 
+	cache := NewGenerationCache()
+	cache.Set(&workloadfake.Workload{}, []Object{&appsv1.Deployment{}})
+
 	cases := map[string]struct {
 		reason string
 		args   args
@@ -70,6 +93,38 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{err: errors.Wrap(errBoom, errGetWorkload)},
 		},
+		"ClearReconcileRequestError": {
+			reason: "Errors clearing a reconcile request should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.ReconcileNow: "now"})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+			},
+			want: want{err: errors.Wrap(errBoom, errClearReconcileRequest)},
+		},
+		"RecordChangelogError": {
+			reason: "Errors recording a workload's changelog should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithChangelog()},
+			},
+			want: want{err: errors.Wrap(errBoom, errRecordChangelog)},
+		},
 		"WorkloadNotFound": {
 			reason: "Not found errors encountered while getting the resource under reconciliation should be ignored.",
 			args: args{
@@ -143,8 +198,8 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
 		},
-		"ApplyError": {
-			reason: "Failure to apply Workload translate should be returned.",
+		"ValidateTranslationError": {
+			reason: "Failure to validate a Workload's translation should be returned.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
@@ -156,7 +211,65 @@ func TestReconciler(t *testing.T) {
 								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
 							}
 
-							if diff := cmp.Diff(errors.Wrap(errBoom, errApplyWorkloadTranslation).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+							if diff := cmp.Diff(errors.Wrap(errBoom, errValidateTranslation).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithValidator(ValidateFn(func(_ context.Context, _ []Object) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PackageWorkloadError": {
+			reason: "Failure to package a Workload's translation should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileError, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errPackageWorkload).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithPackager(PackageFn(func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PrePackageHookError": {
+			reason: "Failure of a pre-package hook should be reported, and the translation should not be packaged.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errPrePackageHook).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
 								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
 							}
 
@@ -168,67 +281,1210 @@ func TestReconciler(t *testing.T) {
 				w: Kind(fake.GVK(&workloadfake.Workload{})),
 				o: []ReconcilerOption{
 					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
-						return []Object{
-							&appsv1.Deployment{},
-							&appsv1.Deployment{},
-						}, nil
+						return []Object{&appsv1.Deployment{}}, nil
 					})),
-					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					WithPrePackageHook(PrePackageHookFn(func(_ context.Context, _ Workload, objs []Object) error {
+						if len(objs) != 1 {
+							return errors.Errorf("PrePackage: want 1 object, got %d", len(objs))
+						}
 						return errBoom
-					}))},
+					})),
+					WithPackager(PackageFn(func(_ context.Context, _ Workload, _ []Object) ([]Object, error) {
+						return nil, errors.New("translation should not be packaged")
+					})),
+				},
 			},
 			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
 		},
-		"Successful": {
-			reason: "Successful reconciliaton should result in requeue after long wait.",
+		"NotSelected": {
+			reason: "Workloads whose labels do not match the selector should not be translated.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
-						MockGet:          test.NewMockGetFn(nil),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetLabels(map[string]string{"tenant": "b"})
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithSelector(labels.SelectorFromSet(labels.Set{"tenant": "a"})),
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"Paused": {
+			reason: "Paused workloads should not be translated.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.Paused: "true"})
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonPaused, got.GetCondition(TypePaused).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"Resumed": {
+			reason: "Workloads that are no longer paused should be reconciled and marked as resumed.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(Workload).SetConditions(Paused())
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonResumed, got.GetCondition(TypePaused).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileSuccess, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
 					},
 					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
 				},
 				w: Kind(fake.GVK(&workloadfake.Workload{})),
-				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
-					return nil
-				}))},
 			},
 			want: want{result: reconcile.Result{RequeueAfter: longWait}},
 		},
-		"SuccessfulStatusUpdateError": {
-			reason: "Successful reconciliaton should result in requeue after long wait.",
+		"ResumedWithChangelog": {
+			reason: "Recording the changelog should not reset the resumed condition of the workload.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
-						MockGet:          test.NewMockGetFn(nil),
-						MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(Workload).SetConditions(Paused())
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							// The API server returns the status it persisted.
+							obj.(Workload).SetConditions(Paused())
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonResumed, got.GetCondition(TypePaused).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
 					},
 					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
 				},
 				w: Kind(fake.GVK(&workloadfake.Workload{})),
-				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
-					return nil
-				}))},
+				o: []ReconcilerOption{WithChangelog()},
 			},
-			want: want{
-				result: reconcile.Result{RequeueAfter: longWait},
-				err:    errors.Wrap(errBoom, errUpdateWorkloadStatus),
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ResumedWithPackageKinds": {
+			reason: "Recording package kinds should not reset the status of the workload.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(Workload).SetConditions(Paused())
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							// The API server returns the status it persisted.
+							obj.(Workload).SetConditions(Paused())
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonResumed, got.GetCondition(TypePaused).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithPackageKinds(),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
 			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
 		},
-	}
+		"StorePackageError": {
+			reason: "Failure to store the Workload package should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
 
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.m, tc.args.w, tc.args.o...)
-			got, err := r.Reconcile(reconcile.Request{})
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileError, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
 
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
+							if diff := cmp.Diff(errors.Wrap(errBoom, errStorePackage).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
 
-			if diff := cmp.Diff(tc.want.result, got); diff != "" {
-				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&appsv1.Deployment{}}, nil
+					})),
+					WithPackageSink(PackageSinkFn(func(_ context.Context, _ Workload, _ []Object) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"SkipApply": {
+			reason: "Workload packages should be stored but not applied when the Reconciler does not apply packages.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileSuccess, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&appsv1.Deployment{}}, nil
+					})),
+					WithPackageSink(PackageSinkFn(func(_ context.Context, _ Workload, objs []Object) error {
+						if len(objs) != 1 {
+							return errors.Errorf("Store: want 1 object, got %d", len(objs))
+						}
+						return nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return errBoom
+					})),
+					WithoutApply(),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ApplyError": {
+			reason: "Failure to apply Workload translate should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileError, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errApplyWorkloadTranslation).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{
+							&appsv1.Deployment{},
+							&appsv1.Deployment{},
+						}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return errBoom
+					}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"ConcurrentApplyError": {
+			reason: "Failure to concurrently apply a Workload translation should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileError, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errApplyWorkloadTranslation).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{
+							&appsv1.Deployment{},
+							&appsv1.Deployment{},
+						}, nil
+					})),
+					WithMaxConcurrentApplies(2),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return errBoom
+					}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PruneShardsError": {
+			reason: "Failure to delete unused shards of a workload's package should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:  test.NewMockGetFn(nil),
+						MockList: test.NewMockListFn(errBoom),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errPruneShards).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&workloadv1alpha1.KubernetesApplication{
+							ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationShard: "0"}},
+						}}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PruneShards": {
+			reason: "Shards of a workload's package that are no longer required should be deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+							shard := func(name, index string) workloadv1alpha1.KubernetesApplication {
+								return workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{
+									Name:            name,
+									Annotations:     map[string]string{AnnotationShard: index},
+									OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&workloadfake.Workload{}, fake.GVK(&workloadfake.Workload{}))},
+								}}
+							}
+							obj.(*workloadv1alpha1.KubernetesApplicationList).Items = []workloadv1alpha1.KubernetesApplication{
+								shard("", "0"),
+								shard("-1", "1"),
+							}
+							return nil
+						},
+						MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+							if diff := cmp.Diff("-1", obj.(Object).GetName()); diff != "" {
+								return errors.Errorf("MockDelete: -want, +got: %s", diff)
+							}
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(v1alpha1.ReasonReconcileSuccess, got.GetCondition(v1alpha1.TypeSynced).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&workloadv1alpha1.KubernetesApplication{
+							ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationShard: "0"}},
+						}}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"PinShardsError": {
+			reason: "Failure to get the first shard of a workload's package should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+							if _, ok := obj.(*workloadv1alpha1.KubernetesApplication); ok {
+								return errBoom
+							}
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errPinShards).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&workloadv1alpha1.KubernetesApplication{
+							ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationShard: "0"}},
+						}}, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PinShards": {
+			reason: "Every shard of a workload's package should be pinned to the target its first shard is scheduled to.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+							if a, ok := obj.(*workloadv1alpha1.KubernetesApplication); ok {
+								a.Spec.Target = &workloadv1alpha1.KubernetesTargetReference{Name: "coolcluster"}
+							}
+							return nil
+						},
+						MockList:         test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						shard := func(index string) Object {
+							return &workloadv1alpha1.KubernetesApplication{
+								ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationShard: index}},
+								Spec:       workloadv1alpha1.KubernetesApplicationSpec{TargetSelector: &metav1.LabelSelector{}},
+							}
+						}
+						return []Object{shard("0"), shard("1")}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, obj runtime.Object, _ ...resource.ApplyOption) error {
+						want := workloadv1alpha1.KubernetesApplicationSpec{Target: &workloadv1alpha1.KubernetesTargetReference{Name: "coolcluster"}}
+						if diff := cmp.Diff(want, obj.(*workloadv1alpha1.KubernetesApplication).Spec); diff != "" {
+							return errors.Errorf("Apply: -want, +got: %s", diff)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"WithholdShards": {
+			reason: "Shards other than the first should not be applied until the first is scheduled.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockList:         test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						shard := func(index string) Object {
+							return &workloadv1alpha1.KubernetesApplication{
+								ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationShard: index}},
+							}
+						}
+						return []Object{shard("0"), shard("1")}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, obj runtime.Object, _ ...resource.ApplyOption) error {
+						if i := obj.(Object).GetAnnotations()[AnnotationShard]; i != "0" {
+							return errors.Errorf("Apply: unexpected shard %s", i)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"GateWavesError": {
+			reason: "Failure to list the resources of a workload's package should be reported when its apply waves are gated.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:  test.NewMockGetFn(nil),
+						MockList: test.NewMockListFn(errBoom),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errGateWaves).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithWaveGate(),
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{&workloadv1alpha1.KubernetesApplication{}}, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"GateWaves": {
+			reason: "Resource templates should not be applied until every template of an earlier apply wave has been submitted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockList:         test.NewMockListFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithWaveGate(),
+					WithTranslator(TranslateFn(func(ctx context.Context, w Workload) ([]Object, error) {
+						return []Object{kubeApp(
+							kaWithTemplate("cm", &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}}),
+							kaWithTemplate("deploy", &appsv1.Deployment{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}}),
+						)}, nil
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, obj runtime.Object, _ ...resource.ApplyOption) error {
+						a := obj.(*workloadv1alpha1.KubernetesApplication)
+						if len(a.Spec.ResourceTemplates) != 1 || a.Spec.ResourceTemplates[0].GetName() != "cm" {
+							return errors.Errorf("Apply: unexpected resource templates %v", a.Spec.ResourceTemplates)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PostApplyHookError": {
+			reason: "Failure of a post-apply hook should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errPostApplyHook).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+					WithPostApplyHook(PostApplyHookFn(func(_ context.Context, _ Workload, _ []Object) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"PostApplyHookSkipped": {
+			reason: "Post-apply hooks should not be called when the Reconciler does not apply packages.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithoutApply(),
+					WithPostApplyHook(PostApplyHookFn(func(_ context.Context, _ Workload, _ []Object) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"RecordPackageKindsError": {
+			reason: "Failure to record the kinds a workload was packaged as should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errRecordPackageKinds).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithPackageKinds(),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"RecordPackageReferencesError": {
+			reason: "Failure to record references to the package of a workload whose package is deleted by a PackageDeleter should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetFinalizers([]string{Finalizer})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errRecordPackageReferences).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, _ []Object, _ ...client.DeleteOption) error { return nil })),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return errors.New("the package should not be applied before it is recorded")
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"RecordRevisionError": {
+			reason: "Failure to record a revision of the workload translation should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errRecordRevision).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+					WithRevisionTracker(&mockRevisionTracker{err: errBoom}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"GetTTLError": {
+			reason: "Failure to get the TTL of a workload should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{AnnotationTTL: "-1h"})
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errors.New(errNegativeTTL), errGetTTL).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"ExpireWorkloadError": {
+			reason: "Failure to tear down the package of an expired workload should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{
+								AnnotationTTL:               "1h",
+								annotations.PackagedObjects: `[{"apiVersion":"apps/v1","kind":"Deployment","name":"coolname"}]`,
+							})
+							return nil
+						}),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errors.Wrap(errBoom, errDeletePackage), errExpireWorkload).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+					return nil, errBoom
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Expired": {
+			reason: "The package of a workload whose TTL has elapsed should be torn down, and the workload marked expired.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{
+								AnnotationTTL:               "1h",
+								annotations.PackagedObjects: `[{"apiVersion":"apps/v1","kind":"Deployment","name":"coolname"}]`,
+							})
+							return nil
+						}),
+						MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonExpired, got.GetCondition(v1alpha1.TypeReady).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+					return nil, errBoom
+				}))},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"ExpiredWithDeletionPropagation": {
+			reason: "The package of an expired workload should be deleted with the configured propagation policy.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{
+								AnnotationTTL:               "1h",
+								annotations.PackagedObjects: `[{"apiVersion":"apps/v1","kind":"Deployment","name":"coolname"}]`,
+							})
+							return nil
+						}),
+						MockDelete: func(_ context.Context, _ runtime.Object, opts ...client.DeleteOption) error {
+							do := &client.DeleteOptions{}
+							do.ApplyOptions(opts)
+							if do.PropagationPolicy == nil || *do.PropagationPolicy != metav1.DeletePropagationForeground {
+								return errors.New("want foreground deletion propagation")
+							}
+							return nil
+						},
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonExpired, got.GetCondition(v1alpha1.TypeReady).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithDeletionPropagation(metav1.DeletePropagationForeground)},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"AddFinalizerError": {
+			reason: "Errors adding the finalizer of a workload whose package is deleted by a PackageDeleter should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, _ []Object, _ ...client.DeleteOption) error { return nil }))},
+			},
+			want: want{err: errors.Wrap(errBoom, errAddFinalizer)},
+		},
+		"DeletedWithoutFinalizer": {
+			reason: "A deleted workload that has no finalizer should be left to be garbage collected.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							now := metav1.Now()
+							obj.(metav1.Object).SetDeletionTimestamp(&now)
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, _ []Object, _ ...client.DeleteOption) error { return errBoom }))},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"FinalizeWorkloadError": {
+			reason: "Failure to delete the package of a deleted workload should be reported, and the finalizer kept.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							now := metav1.Now()
+							obj.(metav1.Object).SetDeletionTimestamp(&now)
+							obj.(metav1.Object).SetFinalizers([]string{Finalizer})
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errFinalizeWorkload).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, _ []Object, _ ...client.DeleteOption) error { return errBoom }))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"FinalizeInvalidReferencesError": {
+			reason: "Failure to parse the recorded package references of a deleted workload should be reported, and the finalizer kept.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							now := metav1.Now()
+							obj.(metav1.Object).SetDeletionTimestamp(&now)
+							obj.(metav1.Object).SetFinalizers([]string{Finalizer})
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.PackagedObjects: "{"})
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, _ []Object, _ ...client.DeleteOption) error { return nil }))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Finalized": {
+			reason: "The recorded package of a deleted workload should be deleted without translating the workload, then its finalizer removed.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							now := metav1.Now()
+							obj.(metav1.Object).SetDeletionTimestamp(&now)
+							obj.(metav1.Object).SetFinalizers([]string{Finalizer})
+							obj.(metav1.Object).SetAnnotations(map[string]string{
+								annotations.PackagedObjects: `[{"apiVersion":"apps/v1","kind":"Deployment","namespace":"coolns","name":"coolname"}]`,
+							})
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							if diff := cmp.Diff([]string{}, obj.(metav1.Object).GetFinalizers()); diff != "" {
+								return errors.Errorf("MockUpdate: -want, +got: %s", diff)
+							}
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+					WithPackageDeleter(PackageDeleterFn(func(_ context.Context, _ Workload, objs []Object, _ ...client.DeleteOption) error {
+						want := &unstructured.Unstructured{}
+						want.SetAPIVersion("apps/v1")
+						want.SetKind("Deployment")
+						want.SetNamespace("coolns")
+						want.SetName("coolname")
+						if diff := cmp.Diff([]Object{want}, objs); diff != "" {
+							return errors.Errorf("PackageDeleter: -want, +got: %s", diff)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"RollbackError": {
+			reason: "Failure to get the revision a workload is being rolled back to should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{AnnotationRollbackTo: "1"})
+							return nil
+						}),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errors.New(errRevisionsDisabled), errRollbackWorkload).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"SuccessfulRollback": {
+			reason: "A workload that is being rolled back should have the recorded revision applied.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{AnnotationRollbackTo: "1"})
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+					WithRevisionTracker(&mockRevisionTracker{objs: []Object{&appsv1.Deployment{}}}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"ObserveRolloutError": {
+			reason: "Failure to observe the rollout of the workload's package should be reported.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errObserveRollout).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+					WithRolloutObserver(RolloutObserverFn(func(_ context.Context, _ []Object) (v1alpha1.Condition, bool, error) {
+						return v1alpha1.Condition{}, false, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"RolloutProgressing": {
+			reason: "Workloads whose package is still rolling out should be requeued after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(ReasonRolloutProgressing, got.GetCondition(TypeRolledOut).Reason); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+					WithRolloutObserver(RolloutObserverFn(func(_ context.Context, _ []Object) (v1alpha1.Condition, bool, error) {
+						return RolloutProgressing("cool"), true, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"Successful": {
+			reason: "Successful reconciliaton should result in requeue after long wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					return nil
+				}))},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"CachedTranslation": {
+			reason: "Workloads that have not changed since they were last translated should not be translated again.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslationCache(cache),
+					WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+					WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+						return nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: longWait}},
+		},
+		"CachedTranslationReconcileRequested": {
+			reason: "Workloads whose reconcile was explicitly requested should be translated again.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(metav1.Object).SetAnnotations(map[string]string{annotations.ReconcileNow: "now"})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							got := obj.(Workload)
+
+							if diff := cmp.Diff(errors.Wrap(errBoom, errTranslateWorkload).Error(), got.GetCondition(v1alpha1.TypeSynced).Message); diff != "" {
+								return errors.Errorf("MockStatusUpdate: -want, +got: %s", diff)
+							}
+
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{
+					WithTranslationCache(cache),
+					WithTranslator(TranslateFn(func(_ context.Context, _ Workload) ([]Object, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: shortWait}},
+		},
+		"SuccessfulStatusUpdateError": {
+			reason: "Successful reconciliaton should result in requeue after long wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&workloadfake.Workload{}),
+				},
+				w: Kind(fake.GVK(&workloadfake.Workload{})),
+				o: []ReconcilerOption{WithApplicator(resource.ApplyFn(func(_ context.Context, _ client.Client, _ runtime.Object, _ ...resource.ApplyOption) error {
+					return nil
+				}))},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: longWait},
+				err:    errors.Wrap(errBoom, errUpdateWorkloadStatus),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m, tc.args.w, tc.args.o...)
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcilerFixtures(t *testing.T) {
+	fixtures, err := workloadfake.LoadFixtures(filepath.Join("testdata", "reconciler"))
+	if err != nil {
+		t.Fatalf("LoadFixtures(...): %s", err)
+	}
+
+	for name, f := range fixtures {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			updated := &workloadfake.Workload{}
+			m := &fake.Manager{Client: f.Client(updated), Scheme: fake.SchemeWith(&workloadfake.Workload{})}
+			r := NewReconciler(m, Kind(fake.GVK(&workloadfake.Workload{})))
+			got, err := r.Reconcile(reconcile.Request{})
+
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(f.Want.Error, gotErr); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", f.Reason, diff)
+			}
+
+			if diff := cmp.Diff(reconcile.Result{RequeueAfter: f.Want.RequeueAfter.Duration}, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", f.Reason, diff)
+			}
+
+			if f.Want.Synced == nil {
+				return
+			}
+			synced := updated.GetCondition(v1alpha1.TypeSynced)
+			if diff := cmp.Diff(f.Want.Synced.Reason, synced.Reason); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want synced reason, +got synced reason:\n%s", f.Reason, diff)
+			}
+			if f.Want.Synced.Message == "" {
+				return
+			}
+			if diff := cmp.Diff(f.Want.Synced.Message, synced.Message); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want synced message, +got synced message:\n%s", f.Reason, diff)
 			}
 		})
 	}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errSetRemoteNamespace = "cannot set remote namespace of resource template"
+)
+
+// AnnotationRemoteNamespace may be set on a workload to specify the remote
+// namespace its translation should be created in.
+const AnnotationRemoteNamespace = "workload.oam.crossplane.io/remote-namespace"
+
+// A RemoteNamespacer returns the remote namespace in which the translation of
+// the supplied workload should be created. An empty namespace leaves the
+// namespace of its resource templates unset, so that their remote objects are
+// created in the default namespace of the remote cluster.
+type RemoteNamespacer func(w Workload) string
+
+// FixedNamespace creates the translations of all workloads in the supplied
+// remote namespace.
+func FixedNamespace(namespace string) RemoteNamespacer {
+	return func(_ Workload) string {
+		return namespace
+	}
+}
+
+// SourceNamespace creates the translation of a workload in the remote
+// namespace with the same name as the workload's namespace.
+func SourceNamespace(w Workload) string {
+	return w.GetNamespace()
+}
+
+// AnnotatedNamespace creates the translation of a workload in the remote
+// namespace specified by its AnnotationRemoteNamespace annotation, falling
+// back to the supplied RemoteNamespacer for workloads without it.
+func AnnotatedNamespace(fallback RemoteNamespacer) RemoteNamespacer {
+	return func(w Workload) string {
+		if ns := w.GetAnnotations()[AnnotationRemoteNamespace]; ns != "" {
+			return ns
+		}
+		return fallback(w)
+	}
+}
+
+// setRemoteNamespace sets the namespace of each resource template of the
+// supplied KubernetesApplication.
+func setRemoteNamespace(a *workloadv1alpha1.KubernetesApplication, namespace string) error {
+	for i := range a.Spec.ResourceTemplates {
+		t := &a.Spec.ResourceTemplates[i]
+		u := &unstructured.Unstructured{}
+		if err := json.Unmarshal(t.Spec.Template.Raw, u); err != nil {
+			return errors.Wrap(err, errSetRemoteNamespace)
+		}
+		u.SetNamespace(namespace)
+		b, err := json.Marshal(u)
+		if err != nil {
+			return errors.Wrap(err, errSetRemoteNamespace)
+		}
+		t.Spec.Template.Raw = b
+	}
+	return nil
+}
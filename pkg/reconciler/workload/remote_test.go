@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestRemoteNamespacer(t *testing.T) {
+	annotated := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "source",
+		Annotations: map[string]string{AnnotationRemoteNamespace: "annotated"},
+	}}
+	plain := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: "source"}}
+
+	cases := map[string]struct {
+		reason string
+		n      RemoteNamespacer
+		w      Workload
+		want   string
+	}{
+		"Fixed": {
+			reason: "A fixed namespace should be used regardless of the workload.",
+			n:      FixedNamespace("fixed"),
+			w:      annotated,
+			want:   "fixed",
+		},
+		"Source": {
+			reason: "The namespace of the workload should be mirrored.",
+			n:      SourceNamespace,
+			w:      plain,
+			want:   "source",
+		},
+		"Annotated": {
+			reason: "The annotated namespace should be preferred.",
+			n:      AnnotatedNamespace(SourceNamespace),
+			w:      annotated,
+			want:   "annotated",
+		},
+		"AnnotatedFallback": {
+			reason: "The fallback should be used when the workload is not annotated.",
+			n:      AnnotatedNamespace(FixedNamespace("fixed")),
+			w:      plain,
+			want:   "fixed",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.n(tc.w)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nRemoteNamespacer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetRemoteNamespace(t *testing.T) {
+	type args struct {
+		a  *workloadv1alpha1.KubernetesApplication
+		ns string
+	}
+
+	type want struct {
+		a   *workloadv1alpha1.KubernetesApplication
+		err error
+	}
+
+	app := func(raw string) *workloadv1alpha1.KubernetesApplication {
+		return &workloadv1alpha1.KubernetesApplication{
+			Spec: workloadv1alpha1.KubernetesApplicationSpec{
+				ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{{
+					Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+						Template: runtime.RawExtension{Raw: []byte(raw)},
+					},
+				}},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SetNamespace": {
+			reason: "The namespace of each resource template should be set.",
+			args: args{
+				a:  app(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"cool"}}`),
+				ns: "remote",
+			},
+			want: want{
+				a: app(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"cool","namespace":"remote"}}`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setRemoteNamespace(tc.args.a, tc.args.ns)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nsetRemoteNamespace(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.a, tc.args.a); diff != "" {
+				t.Errorf("\nReason: %s\nsetRemoteNamespace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
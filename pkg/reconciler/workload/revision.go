@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errListRevisions     = "cannot list revisions"
+	errCreateRevision    = "cannot create revision"
+	errPruneRevision     = "cannot prune revision"
+	errEncodeRevision    = "cannot encode revision"
+	errDecodeRevision    = "cannot decode revision"
+	errRevisionNotFound  = "revision not found"
+	errRevisionsDisabled = "revision tracking is disabled"
+	errParseRollback     = "cannot parse rollback revision"
+)
+
+// AnnotationRollbackTo may be set on a workload to the number of a previous
+// revision. The translation recorded as that revision is applied instead of a
+// new translation until the annotation is removed.
+const AnnotationRollbackTo = "workload.oam.crossplane.io/rollback-to"
+
+// LabelRevisionOf is added to each revision of a workload. Its value is the
+// UID of the workload.
+const LabelRevisionOf = "workload.oam.crossplane.io/revision-of"
+
+// DefaultRevisionHistoryLimit is the number of revisions that are kept for
+// each workload by default.
+const DefaultRevisionHistoryLimit = 10
+
+// A RevisionHistory describes the revisions of a workload.
+type RevisionHistory struct {
+	// Current is the revision that was most recently applied.
+	Current int64
+
+	// Revisions that are available to roll back to, oldest first.
+	Revisions []int64
+}
+
+// A RevisionRecorder is a workload that can record its revision history.
+type RevisionRecorder interface {
+	SetRevisionHistory(h RevisionHistory)
+}
+
+// A RevisionTracker records each successfully applied translation of a
+// workload as a numbered revision.
+type RevisionTracker interface {
+	// Record the supplied translation as the latest revision of the supplied
+	// workload. A new revision is only recorded if the translation differs
+	// from the latest revision.
+	Record(ctx context.Context, w Workload, objs []Object) (RevisionHistory, error)
+
+	// Get the translation that was recorded as the supplied revision of the
+	// supplied workload.
+	Get(ctx context.Context, w Workload, revision int64) ([]Object, error)
+}
+
+// A NopRevisionTracker does not track revisions.
+type NopRevisionTracker struct{}
+
+// Record does nothing.
+func (t NopRevisionTracker) Record(_ context.Context, _ Workload, _ []Object) (RevisionHistory, error) {
+	return RevisionHistory{}, nil
+}
+
+// Get always returns an error.
+func (t NopRevisionTracker) Get(_ context.Context, _ Workload, _ int64) ([]Object, error) {
+	return nil, errors.New(errRevisionsDisabled)
+}
+
+// A ControllerRevisionTracker records the revisions of a workload as
+// ControllerRevisions in the workload's namespace.
+type ControllerRevisionTracker struct {
+	client  client.Client
+	creator runtime.ObjectCreater
+	limit   int
+}
+
+// NewControllerRevisionTracker returns a RevisionTracker that records
+// revisions as ControllerRevisions, keeping at most limit revisions per
+// workload. Recorded objects are decoded into the types known to the supplied
+// ObjectCreater.
+func NewControllerRevisionTracker(c client.Client, oc runtime.ObjectCreater, limit int) *ControllerRevisionTracker {
+	return &ControllerRevisionTracker{client: c, creator: oc, limit: limit}
+}
+
+// revisionData is the data of a ControllerRevision.
+type revisionData struct {
+	Objects []runtime.RawExtension `json:"objects"`
+}
+
+// Record the supplied translation as the latest revision of the supplied
+// workload.
+func (t *ControllerRevisionTracker) Record(ctx context.Context, w Workload, objs []Object) (RevisionHistory, error) {
+	data, err := encodeRevision(objs)
+	if err != nil {
+		return RevisionHistory{}, err
+	}
+
+	revs, err := t.list(ctx, w)
+	if err != nil {
+		return RevisionHistory{}, err
+	}
+
+	if len(revs) > 0 && bytes.Equal(revs[len(revs)-1].Data.Raw, data) {
+		return history(revs), nil
+	}
+
+	var next int64 = 1
+	if len(revs) > 0 {
+		next = revs[len(revs)-1].Revision + 1
+	}
+
+	cr := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", w.GetName(), next),
+			Namespace: w.GetNamespace(),
+			Labels:    map[string]string{LabelRevisionOf: string(w.GetUID())},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: next,
+	}
+	meta.AddOwnerReference(cr, *metav1.NewControllerRef(w, w.GetObjectKind().GroupVersionKind()))
+	if err := t.client.Create(ctx, cr); err != nil {
+		return RevisionHistory{}, errors.Wrap(err, errCreateRevision)
+	}
+	revs = append(revs, *cr)
+
+	for len(revs) > t.limit {
+		if err := t.client.Delete(ctx, &revs[0]); resource.IgnoreNotFound(err) != nil {
+			return RevisionHistory{}, errors.Wrap(err, errPruneRevision)
+		}
+		revs = revs[1:]
+	}
+
+	return history(revs), nil
+}
+
+// Get the translation that was recorded as the supplied revision of the
+// supplied workload.
+func (t *ControllerRevisionTracker) Get(ctx context.Context, w Workload, revision int64) ([]Object, error) {
+	revs, err := t.list(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	for _, cr := range revs {
+		if cr.Revision == revision {
+			return decodeRevision(t.creator, cr.Data.Raw)
+		}
+	}
+	return nil, errors.Errorf("%s: %d", errRevisionNotFound, revision)
+}
+
+// list the revisions of the supplied workload, oldest first.
+func (t *ControllerRevisionTracker) list(ctx context.Context, w Workload) ([]appsv1.ControllerRevision, error) {
+	l := &appsv1.ControllerRevisionList{}
+	if err := t.client.List(ctx, l, client.InNamespace(w.GetNamespace()), client.MatchingLabels{LabelRevisionOf: string(w.GetUID())}); err != nil {
+		return nil, errors.Wrap(err, errListRevisions)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].Revision < l.Items[j].Revision })
+	return l.Items, nil
+}
+
+func history(revs []appsv1.ControllerRevision) RevisionHistory {
+	h := RevisionHistory{}
+	for _, cr := range revs {
+		h.Revisions = append(h.Revisions, cr.Revision)
+	}
+	if len(revs) > 0 {
+		h.Current = revs[len(revs)-1].Revision
+	}
+	return h
+}
+
+func encodeRevision(objs []Object) ([]byte, error) {
+	d := revisionData{}
+	for _, o := range objs {
+		b, err := json.Marshal(o)
+		if err != nil {
+			return nil, errors.Wrap(err, errEncodeRevision)
+		}
+		d.Objects = append(d.Objects, runtime.RawExtension{Raw: b})
+	}
+	b, err := json.Marshal(d)
+	return b, errors.Wrap(err, errEncodeRevision)
+}
+
+// decodeRevision decodes each recorded object into its Go type, if known, so
+// that the same apply options may be used to apply it as were used when it
+// was recorded.
+func decodeRevision(oc runtime.ObjectCreater, data []byte) ([]Object, error) {
+	d := revisionData{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, errors.Wrap(err, errDecodeRevision)
+	}
+
+	objs := make([]Object, 0, len(d.Objects))
+	for _, raw := range d.Objects {
+		u := &unstructured.Unstructured{}
+		if err := json.Unmarshal(raw.Raw, u); err != nil {
+			return nil, errors.Wrap(err, errDecodeRevision)
+		}
+
+		typed, err := oc.New(u.GroupVersionKind())
+		if err != nil {
+			objs = append(objs, u)
+			continue
+		}
+		o, ok := typed.(Object)
+		if !ok {
+			objs = append(objs, u)
+			continue
+		}
+		if err := json.Unmarshal(raw.Raw, o); err != nil {
+			return nil, errors.Wrap(err, errDecodeRevision)
+		}
+		objs = append(objs, o)
+	}
+	return objs, nil
+}
+
+// rollbackRevision returns the revision the supplied workload should be rolled
+// back to, if any.
+func rollbackRevision(w Workload) (int64, bool, error) {
+	v, ok := w.GetAnnotations()[AnnotationRollbackTo]
+	if !ok {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	return n, true, errors.Wrap(err, errParseRollback)
+}
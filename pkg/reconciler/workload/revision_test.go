@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+var _ RevisionTracker = &ControllerRevisionTracker{}
+var _ RevisionTracker = NopRevisionTracker{}
+
+func TestControllerRevisionTrackerRecord(t *testing.T) {
+	type args struct {
+		c     client.Client
+		limit int
+		objs  []Object
+	}
+
+	type want struct {
+		h   RevisionHistory
+		err error
+	}
+
+	errBoom := errors.New("boom")
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: workloadNamespace, UID: types.UID(workloadUID)}}
+	d := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, ObjectMeta: metav1.ObjectMeta{Name: workloadName}}
+	data, _ := encodeRevision([]Object{d})
+
+	withRevisions := func(revs ...appsv1.ControllerRevision) func(context.Context, runtime.Object, ...client.ListOption) error {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			obj.(*appsv1.ControllerRevisionList).Items = revs
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing revisions should be returned.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errListRevisions)},
+		},
+		"Unchanged": {
+			reason: "A translation that matches the latest revision should not be recorded again.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withRevisions(appsv1.ControllerRevision{Revision: 2, Data: runtime.RawExtension{Raw: data}}, appsv1.ControllerRevision{Revision: 1}),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				limit: DefaultRevisionHistoryLimit,
+				objs:  []Object{d},
+			},
+			want: want{h: RevisionHistory{Current: 2, Revisions: []int64{1, 2}}},
+		},
+		"CreateError": {
+			reason: "Errors creating a revision should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withRevisions(),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				limit: DefaultRevisionHistoryLimit,
+				objs:  []Object{d},
+			},
+			want: want{err: errors.Wrap(errBoom, errCreateRevision)},
+		},
+		"RecordAndPrune": {
+			reason: "A changed translation should be recorded as the next revision, and the oldest revisions pruned.",
+			args: args{
+				c: &test.MockClient{
+					MockList:   withRevisions(appsv1.ControllerRevision{Revision: 1}, appsv1.ControllerRevision{Revision: 2}),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				limit: 2,
+				objs:  []Object{d},
+			},
+			want: want{h: RevisionHistory{Current: 3, Revisions: []int64{2, 3}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := NewControllerRevisionTracker(tc.args.c, runtime.NewScheme(), tc.args.limit)
+			got, err := tr.Record(context.Background(), w, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Record(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.h, got); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Record(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestControllerRevisionTrackerGet(t *testing.T) {
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: workloadNamespace, UID: types.UID(workloadUID)}}
+	d := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, ObjectMeta: metav1.ObjectMeta{Name: workloadName}}
+	data, _ := encodeRevision([]Object{d})
+
+	s := runtime.NewScheme()
+	_ = appsv1.AddToScheme(s)
+
+	c := &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+		obj.(*appsv1.ControllerRevisionList).Items = []appsv1.ControllerRevision{{Revision: 1, Data: runtime.RawExtension{Raw: data}}}
+		return nil
+	}}
+
+	cases := map[string]struct {
+		reason   string
+		revision int64
+		want     want
+	}{
+		"NotFound": {
+			reason:   "Getting a revision that does not exist should return an error.",
+			revision: 2,
+			want:     want{err: errors.Errorf("%s: %d", errRevisionNotFound, 2)},
+		},
+		"Found": {
+			reason:   "Recorded objects should be decoded into their Go types.",
+			revision: 1,
+			want:     want{objs: []Object{d}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := NewControllerRevisionTracker(c, s, DefaultRevisionHistoryLimit)
+			got, err := tr.Get(context.Background(), w, tc.revision)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Get(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errParseProgressDeadline    = "cannot parse progress deadline annotation"
+	errNegativeProgressDeadline = "progress deadline annotation must not be negative"
+	errParseRolloutTemplate     = "cannot parse resource template of KubernetesApplication"
+	errGetRolloutResource       = "cannot get KubernetesApplicationResource"
+	errParseRolloutStatus       = "cannot parse remote Deployment status"
+)
+
+var deploymentGroupKind = appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind()
+
+// AnnotationProgressDeadline may be set on a workload to the duration, e.g.
+// "5m", a rollout of its Deployments may take to make progress before it is
+// considered to have failed. It sets the progressDeadlineSeconds of each
+// Deployment in the workload's translation.
+const AnnotationProgressDeadline = "workload.oam.crossplane.io/progress-deadline"
+
+// reasonProgressDeadlineExceeded is the reason of the Progressing condition of
+// a Deployment whose rollout exceeded its progress deadline.
+const reasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// TypeRolledOut indicates whether the remote Deployments of a workload have
+// finished rolling out.
+const TypeRolledOut runtimev1alpha1.ConditionType = "RolledOut"
+
+// Reasons a workload is or is not rolled out.
+const (
+	ReasonRolloutProgressing runtimev1alpha1.ConditionReason = "Progressing"
+	ReasonRolloutAvailable   runtimev1alpha1.ConditionReason = "Available"
+	ReasonRolloutFailed      runtimev1alpha1.ConditionReason = "Failed"
+)
+
+// RolloutProgressing returns a condition that indicates the remote
+// Deployments of a workload are still rolling out.
+func RolloutProgressing(msg string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeRolledOut,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolloutProgressing,
+		Message:            msg,
+	}
+}
+
+// RolledOut returns a condition that indicates the remote Deployments of a
+// workload have finished rolling out and are available.
+func RolledOut() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeRolledOut,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolloutAvailable,
+		Message:            "all Deployments successfully rolled out",
+	}
+}
+
+// RolloutFailed returns a condition that indicates a rollout of the remote
+// Deployments of a workload exceeded its progress deadline.
+func RolloutFailed(msg string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeRolledOut,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolloutFailed,
+		Message:            msg,
+	}
+}
+
+// ProgressDeadline returns the progress deadline of the supplied workload, and
+// false if it does not specify one.
+func ProgressDeadline(w metav1.Object) (time.Duration, bool, error) {
+	v, ok := w.GetAnnotations()[AnnotationProgressDeadline]
+	if !ok {
+		return 0, false, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, false, errors.Wrap(err, errParseProgressDeadline)
+	}
+	if d < 0 {
+		return 0, false, errors.New(errNegativeProgressDeadline)
+	}
+	return d, true, nil
+}
+
+var _ TranslationWrapper = ProgressDeadlineWrapper
+
+// ProgressDeadlineWrapper sets the progress deadline of the Deployments in the
+// translation of a workload that specifies one. The remote cluster tracks the
+// deadline, so that it is reset whenever a new rollout starts.
+func ProgressDeadlineWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	d, ok, err := ProgressDeadline(w)
+	if err != nil || !ok {
+		return objs, err
+	}
+
+	// Deployments accept only whole seconds, so the deadline is rounded up.
+	s := int32(math.Ceil(d.Seconds()))
+	for _, o := range objs {
+		if dp, ok := o.(*appsv1.Deployment); ok {
+			dp.Spec.ProgressDeadlineSeconds = &s
+		}
+	}
+
+	return objs, nil
+}
+
+// A RolloutObserver observes the rollout of the remote Deployments of a
+// workload's package.
+type RolloutObserver interface {
+	// ObserveRollout returns a condition that reflects the rollout of the
+	// supplied package, or false if it contains no Deployments.
+	ObserveRollout(ctx context.Context, objs []Object) (runtimev1alpha1.Condition, bool, error)
+}
+
+// A RolloutObserverFn observes the rollout of the remote Deployments of a
+// workload's package.
+type RolloutObserverFn func(ctx context.Context, objs []Object) (runtimev1alpha1.Condition, bool, error)
+
+// ObserveRollout of the remote Deployments of a workload's package.
+func (fn RolloutObserverFn) ObserveRollout(ctx context.Context, objs []Object) (runtimev1alpha1.Condition, bool, error) {
+	return fn(ctx, objs)
+}
+
+var _ RolloutObserver = RolloutObserverFn(NopObserveRollout)
+
+// NopObserveRollout observes no rollout and returns no errors.
+func NopObserveRollout(_ context.Context, _ []Object) (runtimev1alpha1.Condition, bool, error) {
+	return runtimev1alpha1.Condition{}, false, nil
+}
+
+// A KubeAppRolloutObserver observes the rollout of the Deployments templated
+// by KubernetesApplications by reading the remote status of their
+// KubernetesApplicationResources.
+type KubeAppRolloutObserver struct {
+	client client.Reader
+}
+
+// NewKubeAppRolloutObserver returns a RolloutObserver of KubernetesApplications.
+func NewKubeAppRolloutObserver(c client.Reader) *KubeAppRolloutObserver {
+	return &KubeAppRolloutObserver{client: c}
+}
+
+// ObserveRollout of the Deployments templated by the KubernetesApplications of
+// the supplied package, roughly as kubectl rollout status would. The rollout
+// fails if any Deployment exceeded its progress deadline, and is progressing
+// until all of them have been updated and are available.
+func (o *KubeAppRolloutObserver) ObserveRollout(ctx context.Context, objs []Object) (runtimev1alpha1.Condition, bool, error) {
+	waiting := []string{}
+	found := false
+	for _, obj := range objs {
+		a, ok := obj.(*workloadv1alpha1.KubernetesApplication)
+		if !ok {
+			continue
+		}
+		for _, rt := range a.Spec.ResourceTemplates {
+			template := &unstructured.Unstructured{}
+			if err := json.Unmarshal(rt.Spec.Template.Raw, template); err != nil {
+				return runtimev1alpha1.Condition{}, false, errors.Wrap(err, errParseRolloutTemplate)
+			}
+			if template.GroupVersionKind().GroupKind() != deploymentGroupKind {
+				continue
+			}
+			found = true
+
+			d := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.UnstructuredContent(), d); err != nil {
+				return runtimev1alpha1.Condition{}, false, errors.Wrap(err, errParseRolloutTemplate)
+			}
+
+			s, err := o.remoteStatus(ctx, a.GetNamespace(), rt.GetName())
+			if err != nil {
+				return runtimev1alpha1.Condition{}, false, err
+			}
+			if s == nil {
+				waiting = append(waiting, fmt.Sprintf("waiting for Deployment %q status", d.GetName()))
+				continue
+			}
+
+			msg, done, failed := deploymentRollout(d, s)
+			if failed {
+				return RolloutFailed(msg), true, nil
+			}
+			if !done {
+				waiting = append(waiting, msg)
+			}
+		}
+	}
+
+	switch {
+	case !found:
+		return runtimev1alpha1.Condition{}, false, nil
+	case len(waiting) > 0:
+		return RolloutProgressing(strings.Join(waiting, "; ")), true, nil
+	default:
+		return RolledOut(), true, nil
+	}
+}
+
+// remoteStatus returns the remote status of the named
+// KubernetesApplicationResource, or nil if it does not yet exist or has no
+// remote status.
+func (o *KubeAppRolloutObserver) remoteStatus(ctx context.Context, namespace, name string) (*appsv1.DeploymentStatus, error) {
+	kar := &unstructured.Unstructured{}
+	kar.SetGroupVersionKind(workloadv1alpha1.KubernetesApplicationResourceGroupVersionKind)
+	if err := o.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, kar); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetRolloutResource)
+	}
+	remote, found, err := unstructured.NestedMap(kar.Object, "status", "remote")
+	if err != nil || !found {
+		return nil, errors.Wrap(err, errParseRolloutStatus)
+	}
+	s := &appsv1.DeploymentStatus{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(remote, s); err != nil {
+		return nil, errors.Wrap(err, errParseRolloutStatus)
+	}
+	return s, nil
+}
+
+// deploymentRollout describes the rollout of the supplied Deployment given its
+// remote status, and returns whether it is done or has failed.
+func deploymentRollout(d *appsv1.Deployment, s *appsv1.DeploymentStatus) (string, bool, bool) {
+	for _, c := range s.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == reasonProgressDeadlineExceeded {
+			return fmt.Sprintf("Deployment %q exceeded its progress deadline", d.GetName()), false, true
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	switch {
+	case s.UpdatedReplicas < replicas:
+		return fmt.Sprintf("Deployment %q: %d out of %d new replicas have been updated", d.GetName(), s.UpdatedReplicas, replicas), false, false
+	case s.Replicas > s.UpdatedReplicas:
+		return fmt.Sprintf("Deployment %q: %d old replicas are pending termination", d.GetName(), s.Replicas-s.UpdatedReplicas), false, false
+	case s.AvailableReplicas < s.UpdatedReplicas:
+		return fmt.Sprintf("Deployment %q: %d of %d updated replicas are available", d.GetName(), s.AvailableReplicas, s.UpdatedReplicas), false, false
+	}
+	return fmt.Sprintf("Deployment %q successfully rolled out", d.GetName()), true, false
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestProgressDeadlineWrapper(t *testing.T) {
+	deadline := int32(90)
+
+	withDeadline := func(v string) Workload {
+		w := &workloadfake.Workload{}
+		w.SetAnnotations(map[string]string{AnnotationProgressDeadline: v})
+		return w
+	}
+
+	type args struct {
+		w    Workload
+		objs []Object
+	}
+
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoDeadline": {
+			reason: "The translation of a workload without a progress deadline should be returned unchanged.",
+			args:   args{w: &workloadfake.Workload{}, objs: []Object{&appsv1.Deployment{}}},
+			want:   want{objs: []Object{&appsv1.Deployment{}}},
+		},
+		"NegativeDeadline": {
+			reason: "A negative progress deadline should return an error.",
+			args:   args{w: withDeadline("-1m"), objs: []Object{&appsv1.Deployment{}}},
+			want:   want{objs: []Object{&appsv1.Deployment{}}, err: errors.New(errNegativeProgressDeadline)},
+		},
+		"Deadline": {
+			reason: "The progress deadline of each Deployment should be set, rounded up to whole seconds.",
+			args:   args{w: withDeadline("89.5s"), objs: []Object{&appsv1.Deployment{}, &corev1.Service{}}},
+			want: want{objs: []Object{
+				&appsv1.Deployment{Spec: appsv1.DeploymentSpec{ProgressDeadlineSeconds: &deadline}},
+				&corev1.Service{},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ProgressDeadlineWrapper(context.Background(), tc.args.w, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nProgressDeadlineWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nProgressDeadlineWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppRolloutObserver(t *testing.T) {
+	errBoom := errors.New("boom")
+	three := int32(3)
+
+	kubeApp := func() *workloadv1alpha1.KubernetesApplication {
+		d := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &three},
+		}
+		cm := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: corev1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		}
+		a := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Namespace: "ns"}}
+		for _, o := range []runtime.Object{d, cm} {
+			b, _ := json.Marshal(o)
+			a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-" + o.GetObjectKind().GroupVersionKind().Kind},
+				Spec:       workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: b}},
+			})
+		}
+		return a
+	}
+
+	withStatus := func(s appsv1.DeploymentStatus) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			remote, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&s)
+			if err != nil {
+				return err
+			}
+			return unstructured.SetNestedMap(obj.(*unstructured.Unstructured).Object, remote, "status", "remote")
+		}
+	}
+
+	type want struct {
+		c   runtimev1alpha1.Condition
+		ok  bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		objs   []Object
+		want   want
+	}{
+		"NoDeployments": {
+			reason: "A package that templates no Deployments has no rollout.",
+			client: &test.MockClient{},
+			objs:   []Object{&appsv1.Deployment{}},
+			want:   want{ok: false},
+		},
+		"GetResourceError": {
+			reason: "Errors getting a KubernetesApplicationResource should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			objs:   []Object{kubeApp()},
+			want:   want{err: errors.Wrap(errBoom, errGetRolloutResource)},
+		},
+		"NoResource": {
+			reason: "A Deployment whose KubernetesApplicationResource does not yet exist should be progressing.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool"))},
+			objs:   []Object{kubeApp()},
+			want:   want{c: RolloutProgressing(`waiting for Deployment "cool" status`), ok: true},
+		},
+		"Updating": {
+			reason: "A Deployment that has not updated all of its replicas should be progressing.",
+			client: &test.MockClient{MockGet: withStatus(appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1})},
+			objs:   []Object{kubeApp()},
+			want:   want{c: RolloutProgressing(`Deployment "cool": 1 out of 3 new replicas have been updated`), ok: true},
+		},
+		"Terminating": {
+			reason: "A Deployment with old replicas pending termination should be progressing.",
+			client: &test.MockClient{MockGet: withStatus(appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 3})},
+			objs:   []Object{kubeApp()},
+			want:   want{c: RolloutProgressing(`Deployment "cool": 1 old replicas are pending termination`), ok: true},
+		},
+		"Unavailable": {
+			reason: "A Deployment whose updated replicas are not all available should be progressing.",
+			client: &test.MockClient{MockGet: withStatus(appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2})},
+			objs:   []Object{kubeApp()},
+			want:   want{c: RolloutProgressing(`Deployment "cool": 2 of 3 updated replicas are available`), ok: true},
+		},
+		"Failed": {
+			reason: "A Deployment that exceeded its progress deadline should fail the rollout.",
+			client: &test.MockClient{MockGet: withStatus(appsv1.DeploymentStatus{
+				Replicas:        3,
+				UpdatedReplicas: 1,
+				Conditions: []appsv1.DeploymentCondition{{
+					Type:   appsv1.DeploymentProgressing,
+					Status: corev1.ConditionFalse,
+					Reason: reasonProgressDeadlineExceeded,
+				}},
+			})},
+			objs: []Object{kubeApp()},
+			want: want{c: RolloutFailed(`Deployment "cool" exceeded its progress deadline`), ok: true},
+		},
+		"RolledOut": {
+			reason: "A Deployment whose replicas are all updated and available should be rolled out.",
+			client: &test.MockClient{MockGet: withStatus(appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3})},
+			objs:   []Object{kubeApp()},
+			want:   want{c: RolledOut(), ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewKubeAppRolloutObserver(tc.client)
+			c, ok, err := o.ObserveRollout(context.Background(), tc.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\no.ObserveRollout(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, c, cmpopts.IgnoreFields(runtimev1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\nReason: %s\no.ObserveRollout(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\nReason: %s\no.ObserveRollout(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
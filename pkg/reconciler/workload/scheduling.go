@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+// TypeScheduled indicates whether the KubernetesApplications a workload is
+// packaged as have been scheduled to a remote cluster.
+const TypeScheduled runtimev1alpha1.ConditionType = "Scheduled"
+
+// Reasons a workload is or is not scheduled.
+const (
+	ReasonSchedulingPending runtimev1alpha1.ConditionReason = "SchedulingPending"
+	ReasonScheduled         runtimev1alpha1.ConditionReason = "Scheduled"
+	ReasonSchedulingFailed  runtimev1alpha1.ConditionReason = "SchedulingFailed"
+)
+
+// SchedulingPending returns a condition that indicates the package of a
+// workload has not yet been scheduled to a remote cluster.
+func SchedulingPending() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeScheduled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSchedulingPending,
+		Message:            "package is waiting to be scheduled to a KubernetesTarget",
+	}
+}
+
+// Scheduled returns a condition that indicates the package of a workload has
+// been scheduled to the supplied KubernetesTargets.
+func Scheduled(targets ...string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeScheduled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonScheduled,
+		Message:            fmt.Sprintf("package is scheduled to KubernetesTarget %s", strings.Join(targets, ", ")),
+	}
+}
+
+// SchedulingFailed returns a condition that indicates the package of a
+// workload could not be submitted to the remote cluster it was scheduled to.
+func SchedulingFailed(msg string) runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               TypeScheduled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSchedulingFailed,
+		Message:            msg,
+	}
+}
+
+// schedulingCondition returns the condition that reflects the scheduling state
+// of the supplied package, as last observed when it was applied. It returns
+// false if the package contains no KubernetesApplications. The package fails
+// to schedule if any of its KubernetesApplications failed, and is pending
+// until all of them have been scheduled.
+func schedulingCondition(objs []Object) (runtimev1alpha1.Condition, bool) {
+	targets := []string{}
+	pending := false
+	found := false
+	for _, o := range objs {
+		a, ok := o.(*workloadv1alpha1.KubernetesApplication)
+		if !ok {
+			continue
+		}
+		found = true
+
+		switch a.Status.State {
+		case workloadv1alpha1.KubernetesApplicationStateFailed:
+			msg := fmt.Sprintf("KubernetesApplication %s failed", a.GetName())
+			if m := a.Status.GetCondition(runtimev1alpha1.TypeSynced).Message; m != "" {
+				msg = fmt.Sprintf("%s: %s", msg, m)
+			}
+			return SchedulingFailed(msg), true
+		case workloadv1alpha1.KubernetesApplicationStateUnknown, workloadv1alpha1.KubernetesApplicationStatePending:
+			pending = true
+		default:
+			if a.Spec.Target != nil {
+				targets = append(targets, a.Spec.Target.Name)
+			}
+		}
+	}
+
+	switch {
+	case !found:
+		return runtimev1alpha1.Condition{}, false
+	case pending:
+		return SchedulingPending(), true
+	default:
+		return Scheduled(targets...), true
+	}
+}
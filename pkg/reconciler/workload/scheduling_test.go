@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+func TestSchedulingCondition(t *testing.T) {
+	kubeApp := func(name string, state workloadv1alpha1.KubernetesApplicationState, target string) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		a.Status.State = state
+		if target != "" {
+			a.Spec.Target = &workloadv1alpha1.KubernetesTargetReference{Name: target}
+		}
+		return a
+	}
+	failed := kubeApp("cool", workloadv1alpha1.KubernetesApplicationStateFailed, "")
+	failed.Status.SetConditions(runtimev1alpha1.ReconcileError(errors.New("boom")))
+
+	type want struct {
+		c  runtimev1alpha1.Condition
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []Object
+		want   want
+	}{
+		"NoKubernetesApplication": {
+			reason: "A package without KubernetesApplications has no scheduling state.",
+			objs:   []Object{&appsv1.Deployment{}},
+			want:   want{c: runtimev1alpha1.Condition{}, ok: false},
+		},
+		"Pending": {
+			reason: "A KubernetesApplication without a state should be pending.",
+			objs:   []Object{kubeApp("cool", workloadv1alpha1.KubernetesApplicationStateUnknown, "")},
+			want:   want{c: SchedulingPending(), ok: true},
+		},
+		"Scheduled": {
+			reason: "Submitted KubernetesApplications should be scheduled to their targets.",
+			objs: []Object{
+				kubeApp("cool", workloadv1alpha1.KubernetesApplicationStateSubmitted, "west"),
+				kubeApp("cooler", workloadv1alpha1.KubernetesApplicationStateScheduled, "east"),
+			},
+			want: want{c: Scheduled("west", "east"), ok: true},
+		},
+		"PartiallyPending": {
+			reason: "A package should be pending until all of its KubernetesApplications are scheduled.",
+			objs: []Object{
+				kubeApp("cool", workloadv1alpha1.KubernetesApplicationStateSubmitted, "west"),
+				kubeApp("cooler", workloadv1alpha1.KubernetesApplicationStatePending, ""),
+			},
+			want: want{c: SchedulingPending(), ok: true},
+		},
+		"Failed": {
+			reason: "A failed KubernetesApplication should fail the package, with the reason it failed.",
+			objs:   []Object{kubeApp("cooler", workloadv1alpha1.KubernetesApplicationStatePending, ""), failed},
+			want:   want{c: SchedulingFailed("KubernetesApplication cool failed: boom"), ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, ok := schedulingCondition(tc.objs)
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\nReason: %s\nschedulingCondition(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, c, cmpopts.IgnoreFields(runtimev1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\nReason: %s\nschedulingCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationNetworkScope is set on the workloads of a NetworkScope by its
+// controller. Its value is the name of the NetworkScope. The remote namespace
+// of a workload in a NetworkScope is shared by the other workloads in the
+// scope, and is specified by its AnnotationRemoteNamespace annotation.
+const AnnotationNetworkScope = "workload.oam.crossplane.io/network-scope"
+
+var (
+	networkPolicyKind       = reflect.TypeOf(networkingv1.NetworkPolicy{}).Name()
+	networkPolicyAPIVersion = networkingv1.SchemeGroupVersion.String()
+)
+
+var _ TranslationWrapper = NetworkScopeWrapper
+
+// NetworkScopeWrapper adds a NetworkPolicy to the translation of a workload in
+// a NetworkScope for each of its Deployments and DaemonSets. The pods of each
+// are isolated, accepting traffic only from other pods in their remote
+// namespace, which is shared by the workloads in the scope. The translations
+// of workloads that are not in a NetworkScope are returned unchanged.
+func NetworkScopeWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	scope := w.GetAnnotations()[AnnotationNetworkScope]
+	if scope == "" {
+		return objs, nil
+	}
+
+	for _, o := range objs {
+		var sel *metav1.LabelSelector
+		switch obj := o.(type) {
+		case *appsv1.Deployment:
+			sel = obj.Spec.Selector
+		case *appsv1.DaemonSet:
+			sel = obj.Spec.Selector
+		default:
+			continue
+		}
+		if sel == nil {
+			continue
+		}
+		objs = append(objs, networkPolicy(o.GetName()+"-"+scope, *sel))
+	}
+
+	return objs, nil
+}
+
+// networkPolicy returns a NetworkPolicy that isolates the selected pods,
+// accepting traffic only from pods in their namespace.
+func networkPolicy(name string, sel metav1.LabelSelector) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       networkPolicyKind,
+			APIVersion: networkPolicyAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: sel,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+			}},
+		},
+	}
+}
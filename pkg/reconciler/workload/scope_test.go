@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestNetworkScopeWrapper(t *testing.T) {
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "example"}}
+	scoped := map[string]string{AnnotationNetworkScope: "backend"}
+
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotScoped": {
+			reason: "The translation of a workload that is not in a NetworkScope should be returned unchanged.",
+			args: args{
+				w: &workloadfake.Workload{},
+				o: []Object{&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Selector: sel}}},
+			},
+			want: want{result: []Object{&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Selector: sel}}}},
+		},
+		"Scoped": {
+			reason: "A NetworkPolicy should be added for each Deployment and DaemonSet of a workload in a NetworkScope.",
+			args: args{
+				w: &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Annotations: scoped}},
+				o: []Object{
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Spec: appsv1.DeploymentSpec{Selector: sel}},
+					&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent"}, Spec: appsv1.DaemonSetSpec{Selector: sel}},
+					&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+				},
+			},
+			want: want{result: []Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Spec: appsv1.DeploymentSpec{Selector: sel}},
+				&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent"}, Spec: appsv1.DaemonSetSpec{Selector: sel}},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+				&networkingv1.NetworkPolicy{
+					TypeMeta:   metav1.TypeMeta{Kind: networkPolicyKind, APIVersion: networkPolicyAPIVersion},
+					ObjectMeta: metav1.ObjectMeta{Name: "api-backend"},
+					Spec: networkingv1.NetworkPolicySpec{
+						PodSelector: *sel,
+						PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+						Ingress: []networkingv1.NetworkPolicyIngressRule{{
+							From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+						}},
+					},
+				},
+				&networkingv1.NetworkPolicy{
+					TypeMeta:   metav1.TypeMeta{Kind: networkPolicyKind, APIVersion: networkPolicyAPIVersion},
+					ObjectMeta: metav1.ObjectMeta{Name: "agent-backend"},
+					Spec: networkingv1.NetworkPolicySpec{
+						PodSelector: *sel,
+						PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+						Ingress: []networkingv1.NetworkPolicyIngressRule{{
+							From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NetworkScopeWrapper(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNetworkScopeWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nNetworkScopeWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
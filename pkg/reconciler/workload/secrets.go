@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const errPropagateSecrets = "Secrets can only be propagated to the remote cluster by workloads packaged as KubernetesApplications"
+
+// AnnotationPropagateSecrets may be set on a translated object to a comma
+// separated list of the Secrets in its workload's namespace that it depends
+// on. When the object is packaged as a KubernetesApplication its resource
+// template references the Secrets, which are propagated to the remote cluster
+// by its KubernetesApplicationResource rather than copied into the package.
+// Each Secret is named RemoteSecretName in the remote cluster.
+const AnnotationPropagateSecrets = "workload.oam.crossplane.io/propagate-secrets"
+
+// templateName returns the name of the KubernetesApplication resource template
+// of the supplied object.
+func templateName(o Object) string {
+	return fmt.Sprintf("%s-%s", o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind))
+}
+
+// RemoteSecretName returns the name the supplied Secret is given in the remote
+// cluster when it is propagated by the resource template of the supplied
+// object.
+func RemoteSecretName(o Object, secret string) string {
+	return templateName(o) + "-" + secret
+}
+
+// PropagateSecrets records that the supplied object depends on the supplied
+// Secrets, in addition to any it already depends on.
+func PropagateSecrets(o Object, secrets ...string) {
+	names := append(propagatedSecrets(o), secrets...)
+	if len(names) == 0 {
+		return
+	}
+	seen := map[string]bool{}
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Strings(out)
+
+	a := o.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[AnnotationPropagateSecrets] = strings.Join(out, ",")
+	o.SetAnnotations(a)
+}
+
+// propagatedSecrets returns the Secrets the supplied object depends on.
+func propagatedSecrets(o Object) []string {
+	raw := o.GetAnnotations()[AnnotationPropagateSecrets]
+	if raw == "" {
+		return nil
+	}
+	names := []string{}
+	for _, n := range strings.Split(raw, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// secretReferences removes AnnotationPropagateSecrets from a copy of the
+// supplied object, and returns the copy and references to the Secrets the
+// annotation listed. The object is returned as is if it has no such
+// annotation.
+func secretReferences(o Object) (Object, []corev1.LocalObjectReference) {
+	names := propagatedSecrets(o)
+	if _, ok := o.GetAnnotations()[AnnotationPropagateSecrets]; !ok {
+		return o, nil
+	}
+	c := o.DeepCopyObject().(Object)
+	a := c.GetAnnotations()
+	delete(a, AnnotationPropagateSecrets)
+	c.SetAnnotations(a)
+
+	refs := make([]corev1.LocalObjectReference, 0, len(names))
+	for _, n := range names {
+		refs = append(refs, corev1.LocalObjectReference{Name: n})
+	}
+	return c, refs
+}
+
+var _ TranslationWrapper = RejectSecretPropagation
+
+// RejectSecretPropagation returns an error if any of the supplied objects
+// depends on Secrets that must be propagated to the remote cluster. It should
+// be used by controllers that do not package workloads as
+// KubernetesApplications, which cannot propagate Secrets.
+func RejectSecretPropagation(_ context.Context, _ Workload, objs []Object) ([]Object, error) {
+	for _, o := range objs {
+		if _, ok := o.GetAnnotations()[AnnotationPropagateSecrets]; ok {
+			return nil, errors.Errorf("%s: %s %s", errPropagateSecrets, o.GetObjectKind().GroupVersionKind().Kind, o.GetName())
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPropagateSecrets(t *testing.T) {
+	type args struct {
+		annotations map[string]string
+		secrets     []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"NoSecrets": {
+			reason: "An object that depends on no Secrets should not be annotated.",
+			args:   args{},
+		},
+		"NewSecrets": {
+			reason: "The Secrets an object depends on should be recorded, sorted and without duplicates.",
+			args: args{
+				secrets: []string{"regcred", "creds", "regcred"},
+			},
+			want: map[string]string{AnnotationPropagateSecrets: "creds,regcred"},
+		},
+		"AdditionalSecrets": {
+			reason: "Secrets should be recorded in addition to those the object already depends on.",
+			args: args{
+				annotations: map[string]string{AnnotationPropagateSecrets: "regcred"},
+				secrets:     []string{"creds"},
+			},
+			want: map[string]string{AnnotationPropagateSecrets: "creds,regcred"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: tc.args.annotations}}
+			PropagateSecrets(d, tc.args.secrets...)
+			if diff := cmp.Diff(tc.want, d.GetAnnotations()); diff != "" {
+				t.Errorf("\nReason: %s\nPropagateSecrets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoteSecretName(t *testing.T) {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	d.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+
+	want := "web-deployment-regcred"
+	if diff := cmp.Diff(want, RemoteSecretName(d, "regcred")); diff != "" {
+		t.Errorf("\nReason: %s\nRemoteSecretName(...): -want, +got:\n%s", "A propagated Secret should be prefixed with the name of the resource template that propagates it.", diff)
+	}
+}
+
+func TestRejectSecretPropagation(t *testing.T) {
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	propagates := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: map[string]string{AnnotationPropagateSecrets: "regcred"}},
+	}
+	other := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+
+	cases := map[string]struct {
+		reason string
+		objs   []Object
+		want   want
+	}{
+		"NoSecrets": {
+			reason: "Objects that depend on no Secrets should be returned unchanged.",
+			objs:   []Object{other},
+			want:   want{objs: []Object{other}},
+		},
+		"Secrets": {
+			reason: "An error should be returned if an object depends on Secrets.",
+			objs:   []Object{other, propagates},
+			want:   want{err: errors.Errorf("%s: %s %s", errPropagateSecrets, "Deployment", "web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RejectSecretPropagation(context.Background(), nil, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRejectSecretPropagation(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nRejectSecretPropagation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
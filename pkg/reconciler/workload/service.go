@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	errUnknownServiceType      = "unknown service type"
+	errParseServiceAnnotations = "cannot parse service annotations"
+)
+
+// AnnotationServiceType may be set on a workload to choose the type of the
+// Service injected into its translation. Its value must be one of ClusterIP,
+// NodePort, or LoadBalancer. Services are of type LoadBalancer by default.
+const AnnotationServiceType = "workload.oam.crossplane.io/service-type"
+
+// AnnotationServiceAnnotations may be set on a workload to annotate the
+// Service injected into its translation, for example to request an internal
+// load balancer from a cloud provider. Its value is a JSON object of
+// annotations.
+const AnnotationServiceAnnotations = "workload.oam.crossplane.io/service-annotations"
+
+// AnnotationServicePerContainer may be set to "true" on a workload to inject
+// one Service for each of its containers that exposes ports, instead of one
+// Service that exposes the ports of all of its containers. Each Service is
+// named after the workload's Deployment and its container.
+const AnnotationServicePerContainer = "workload.oam.crossplane.io/service-per-container"
+
+// Characters that may not appear in a DNS label.
+var notDNSLabel = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// serviceType returns the type of the Service injected into the translation
+// of the supplied workload.
+func serviceType(o metav1.Object) (corev1.ServiceType, error) {
+	t, ok := o.GetAnnotations()[AnnotationServiceType]
+	if !ok {
+		return corev1.ServiceTypeLoadBalancer, nil
+	}
+	switch st := corev1.ServiceType(t); st {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return st, nil
+	default:
+		return "", errors.Errorf("%s: %s", errUnknownServiceType, t)
+	}
+}
+
+// serviceAnnotations returns the annotations of the Service injected into the
+// translation of the supplied workload.
+func serviceAnnotations(o metav1.Object) (map[string]string, error) {
+	raw, ok := o.GetAnnotations()[AnnotationServiceAnnotations]
+	if !ok {
+		return nil, nil
+	}
+	a := map[string]string{}
+	return a, errors.Wrap(json.Unmarshal([]byte(raw), &a), errParseServiceAnnotations)
+}
+
+// servicePerContainer returns true if one Service should be injected for each
+// container of the supplied workload.
+func servicePerContainer(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationServicePerContainer] == "true"
+}
+
+// servicePorts returns the ports of the named Service that exposes the ports
+// of the supplied containers. A port number and protocol is only exposed once,
+// for the first container that exposes it. Ports are named by servicePortName.
+func servicePorts(service string, containers []corev1.Container) []corev1.ServicePort {
+	type key struct {
+		port     int32
+		protocol corev1.Protocol
+	}
+
+	ports := []corev1.ServicePort{}
+	exposed := map[key]bool{}
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			k := key{port: p.ContainerPort, protocol: p.Protocol}
+			if k.protocol == "" {
+				k.protocol = corev1.ProtocolTCP
+			}
+			if exposed[k] {
+				continue
+			}
+			exposed[k] = true
+			ports = append(ports, corev1.ServicePort{
+				Name:       servicePortName(c, p),
+				Protocol:   p.Protocol,
+				Port:       p.ContainerPort,
+				TargetPort: intstr.FromInt(int(p.ContainerPort)),
+			})
+		}
+	}
+
+	// A Service with a single port is named after the Service, as Services
+	// exposing only the first port of the first container always were.
+	if len(ports) == 1 {
+		ports[0].Name = service
+		return ports
+	}
+
+	// Port names must be unique within a Service. Later ports whose names are
+	// taken are suffixed with their port number, and if need be a counter.
+	used := map[string]bool{}
+	for i := range ports {
+		name := ports[i].Name
+		if used[name] {
+			name = withSuffix(ports[i].Name, fmt.Sprintf("-%d", ports[i].Port))
+		}
+		for n := 2; used[name]; n++ {
+			name = withSuffix(ports[i].Name, fmt.Sprintf("-%d-%d", ports[i].Port, n))
+		}
+		used[name] = true
+		ports[i].Name = name
+	}
+	return ports
+}
+
+// servicePortName returns the name of the Service port that exposes the
+// supplied port of the supplied container. Ports are named after the container
+// port, or after the container and port number if the container port is not
+// named.
+func servicePortName(c corev1.Container, p corev1.ContainerPort) string {
+	if p.Name != "" {
+		return dnsLabel(p.Name)
+	}
+	return dnsLabel(fmt.Sprintf("%s-%d", c.Name, p.ContainerPort))
+}
+
+// dnsLabel converts the supplied string to a valid DNS label by lowercasing
+// it, replacing any invalid characters with hyphens, and truncating it.
+func dnsLabel(s string) string {
+	s = notDNSLabel.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > validation.DNS1123LabelMaxLength {
+		s = s[:validation.DNS1123LabelMaxLength]
+	}
+	return strings.Trim(s, "-")
+}
+
+// withSuffix appends the supplied suffix to the supplied DNS label, truncating
+// the label so that the result is a valid DNS label.
+func withSuffix(label, suffix string) string {
+	if max := validation.DNS1123LabelMaxLength - len(suffix); len(label) > max {
+		label = strings.TrimRight(label[:max], "-")
+	}
+	return label + suffix
+}
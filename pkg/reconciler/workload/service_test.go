@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServicePorts(t *testing.T) {
+	long := strings.Repeat("a", 70)
+
+	cases := map[string]struct {
+		reason     string
+		containers []corev1.Container
+		want       []corev1.ServicePort
+	}{
+		"NoPorts": {
+			reason:     "Containers that expose no ports should produce no Service ports.",
+			containers: []corev1.Container{{Name: "cool"}},
+			want:       []corev1.ServicePort{},
+		},
+		"SinglePort": {
+			reason: "A single port should be named after the Service.",
+			containers: []corev1.Container{{Name: "cool", Ports: []corev1.ContainerPort{
+				{Name: "http", ContainerPort: 8080},
+			}}},
+			want: []corev1.ServicePort{
+				{Name: "cool-service", Port: 8080, TargetPort: intstr.FromInt(8080)},
+			},
+		},
+		"NamedAndUnnamedPorts": {
+			reason: "Named ports should be named after their container port, and unnamed ports after their container and port number.",
+			containers: []corev1.Container{
+				{Name: "web", Ports: []corev1.ContainerPort{{Name: "HTTP", ContainerPort: 8080}}},
+				{Name: "Metrics_Sidecar", Ports: []corev1.ContainerPort{{ContainerPort: 9090}}},
+			},
+			want: []corev1.ServicePort{
+				{Name: "http", Port: 8080, TargetPort: intstr.FromInt(8080)},
+				{Name: "metrics-sidecar-9090", Port: 9090, TargetPort: intstr.FromInt(9090)},
+			},
+		},
+		"DuplicatePorts": {
+			reason: "A port number and protocol should only be exposed once, but may be exposed for each protocol.",
+			containers: []corev1.Container{
+				{Name: "web", Ports: []corev1.ContainerPort{{ContainerPort: 53}}},
+				{Name: "dns", Ports: []corev1.ContainerPort{
+					{ContainerPort: 53, Protocol: corev1.ProtocolTCP},
+					{ContainerPort: 53, Protocol: corev1.ProtocolUDP},
+				}},
+			},
+			want: []corev1.ServicePort{
+				{Name: "web-53", Port: 53, TargetPort: intstr.FromInt(53)},
+				{Name: "dns-53", Protocol: corev1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt(53)},
+			},
+		},
+		"DuplicateNames": {
+			reason: "Ports whose names are taken should be suffixed with their port number, and if need be a counter.",
+			containers: []corev1.Container{
+				{Name: "web", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80}}},
+				{Name: "api", Ports: []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 81},
+					{Name: "http-81", ContainerPort: 82},
+				}},
+			},
+			want: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(80)},
+				{Name: "http-81", Port: 81, TargetPort: intstr.FromInt(81)},
+				{Name: "http-81-82", Port: 82, TargetPort: intstr.FromInt(82)},
+			},
+		},
+		"LongNames": {
+			reason: "Port names should be truncated to valid DNS labels.",
+			containers: []corev1.Container{{Name: long, Ports: []corev1.ContainerPort{
+				{ContainerPort: 80},
+				{Name: long, ContainerPort: 81},
+			}}},
+			want: []corev1.ServicePort{
+				{Name: long[:63], Port: 80, TargetPort: intstr.FromInt(80)},
+				{Name: long[:60] + "-81", Port: 81, TargetPort: intstr.FromInt(81)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := servicePorts("cool-service", tc.containers)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nservicePorts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errShardKubeApp     = "cannot shard KubernetesApplication"
+	errTemplateTooLarge = "resource template exceeds the maximum package size"
+)
+
+// AnnotationShard is the index of a KubernetesApplication among the shards of
+// a package. The first shard is named after its workload, and every other
+// shard after its workload and its index.
+const AnnotationShard = "workload.oam.crossplane.io/shard"
+
+// DefaultMaxPackageBytes is the default maximum size of the JSON encoding of a
+// KubernetesApplication. etcd rejects objects larger than 1.5MiB by default,
+// so this leaves room for the annotations and status added to a package once
+// it has been applied.
+const DefaultMaxPackageBytes = 1 << 20
+
+// A PackageRecorder is a workload that can record the top-level objects it
+// was packaged as, for example each shard of its package.
+type PackageRecorder interface {
+	SetPackageReferences(refs []corev1.ObjectReference)
+}
+
+// ShardKubeApps returns a TranslationWrapper that splits each
+// KubernetesApplication whose JSON encoding is larger than the supplied number
+// of bytes into as few shards as possible. Each shard is a copy of the
+// original KubernetesApplication with a contiguous subset of its resource
+// templates. Every KubernetesApplication is annotated with its shard index, so
+// that shards that are no longer required may be deleted, and so that the
+// Reconciler may schedule every shard to the target of the first.
+// KubernetesApplications are not split if the number of bytes is zero.
+func ShardKubeApps(maxBytes int) TranslationWrapper {
+	return func(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+		if maxBytes <= 0 {
+			return objs, nil
+		}
+
+		out := make([]Object, 0, len(objs))
+		for _, o := range objs {
+			a, ok := o.(*workloadv1alpha1.KubernetesApplication)
+			if !ok {
+				out = append(out, o)
+				continue
+			}
+			shards, err := shard(a, maxBytes)
+			if err != nil {
+				return nil, errors.Wrap(err, errShardKubeApp)
+			}
+			for _, s := range shards {
+				out = append(out, s)
+			}
+		}
+		return out, nil
+	}
+}
+
+// shard splits the supplied KubernetesApplication into shards whose JSON
+// encoding is no larger than the supplied number of bytes, preserving the
+// order of its resource templates.
+func shard(a *workloadv1alpha1.KubernetesApplication, maxBytes int) ([]*workloadv1alpha1.KubernetesApplication, error) {
+	empty := a.DeepCopy()
+	empty.Spec.ResourceTemplates = nil
+	meta.AddAnnotations(empty, map[string]string{AnnotationShard: strconv.Itoa(len(a.Spec.ResourceTemplates))})
+	b, err := json.Marshal(empty)
+	if err != nil {
+		return nil, err
+	}
+	emptyBytes := len(b)
+
+	shards := []*workloadv1alpha1.KubernetesApplication{}
+	current, currentBytes := empty.DeepCopy(), emptyBytes
+	for _, t := range a.Spec.ResourceTemplates {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+
+		// Templates are separated by a comma.
+		n := len(b) + 1
+		if emptyBytes+n > maxBytes {
+			return nil, errors.Errorf("%s: %s is %d bytes", errTemplateTooLarge, t.GetName(), len(b))
+		}
+		if currentBytes+n > maxBytes {
+			shards = append(shards, current)
+			current, currentBytes = empty.DeepCopy(), emptyBytes
+		}
+		current.Spec.ResourceTemplates = append(current.Spec.ResourceTemplates, t)
+		currentBytes += n
+	}
+	shards = append(shards, current)
+
+	for i, s := range shards {
+		meta.AddAnnotations(s, map[string]string{AnnotationShard: strconv.Itoa(i)})
+	}
+	return shards, nil
+}
+
+// shardName returns the name of the supplied top-level object of a workload of
+// the supplied name. Shards other than the first are suffixed with their
+// index; all other objects are named after the workload.
+func shardName(name string, o Object) string {
+	i := o.GetAnnotations()[AnnotationShard]
+	if i == "" || i == "0" {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, i)
+}
+
+// sharded returns true if the supplied package was sharded.
+func sharded(objs []Object) bool {
+	for _, o := range objs {
+		if _, ok := o.GetAnnotations()[AnnotationShard]; ok {
+			return true
+		}
+	}
+	return false
+}
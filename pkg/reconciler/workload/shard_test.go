@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestShardKubeApps(t *testing.T) {
+	template := func(name string, size int) workloadv1alpha1.KubernetesApplicationResourceTemplate {
+		return workloadv1alpha1.KubernetesApplicationResourceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+				Template: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"data":%q}`, strings.Repeat("a", size)))},
+			},
+		}
+	}
+	kubeApp := func(shard string, templates ...workloadv1alpha1.KubernetesApplicationResourceTemplate) *workloadv1alpha1.KubernetesApplication {
+		a := &workloadv1alpha1.KubernetesApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: workloadName},
+			Spec: workloadv1alpha1.KubernetesApplicationSpec{
+				ResourceSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{labelKey: workloadUID}},
+				TargetSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east-1"}},
+				ResourceTemplates: templates,
+			},
+		}
+		if shard != "" {
+			a.SetAnnotations(map[string]string{AnnotationShard: shard})
+		}
+		return a
+	}
+
+	huge := template("huge", 3000)
+	hugeBytes, _ := json.Marshal(huge)
+
+	type args struct {
+		maxBytes int
+		objs     []Object
+	}
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "KubernetesApplications should not be sharded if the maximum number of bytes is zero.",
+			args: args{
+				objs: []Object{kubeApp("", template("a", 1000), template("b", 1000))},
+			},
+			want: want{
+				objs: []Object{kubeApp("", template("a", 1000), template("b", 1000))},
+			},
+		},
+		"NotKubeApp": {
+			reason: "Objects other than KubernetesApplications should not be sharded.",
+			args: args{
+				maxBytes: 10,
+				objs:     []Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: workloadName}}},
+			},
+			want: want{
+				objs: []Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: workloadName}}},
+			},
+		},
+		"Fits": {
+			reason: "A KubernetesApplication that is small enough should be annotated as the first and only shard.",
+			args: args{
+				maxBytes: DefaultMaxPackageBytes,
+				objs:     []Object{kubeApp("", template("a", 1000), template("b", 1000))},
+			},
+			want: want{
+				objs: []Object{kubeApp("0", template("a", 1000), template("b", 1000))},
+			},
+		},
+		"TooLarge": {
+			reason: "A KubernetesApplication that is too large should be split into shards with the same selectors.",
+			args: args{
+				maxBytes: 2000,
+				objs:     []Object{kubeApp("", template("a", 1000), template("b", 1000), template("c", 1000))},
+			},
+			want: want{
+				objs: []Object{
+					kubeApp("0", template("a", 1000)),
+					kubeApp("1", template("b", 1000)),
+					kubeApp("2", template("c", 1000)),
+				},
+			},
+		},
+		"TemplateTooLarge": {
+			reason: "A resource template that would not fit in a shard on its own should return an error.",
+			args: args{
+				maxBytes: 2000,
+				objs:     []Object{kubeApp("", template("a", 1000), huge)},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf("%s: %s is %d bytes", errTemplateTooLarge, "huge", len(hugeBytes)), errShardKubeApp),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ShardKubeApps(tc.args.maxBytes)(context.Background(), &workloadfake.Workload{}, tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nShardKubeApps(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nShardKubeApps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNameAfterWorkload(t *testing.T) {
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: workloadNamespace, Name: workloadName}}
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        string
+	}{
+		"NotSharded": {
+			reason: "Top-level objects should be named after their workload.",
+			want:   workloadName,
+		},
+		"FirstShard": {
+			reason:      "The first shard of a package should be named after its workload.",
+			annotations: map[string]string{AnnotationShard: "0"},
+			want:        workloadName,
+		},
+		"LaterShard": {
+			reason:      "Later shards of a package should be suffixed with their index.",
+			annotations: map[string]string{AnnotationShard: "2"},
+			want:        workloadName + "-2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			NameAfterWorkload(w, o)
+			if diff := cmp.Diff(tc.want, o.GetName()); diff != "" {
+				t.Errorf("\nReason: %s\nNameAfterWorkload(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(workloadNamespace, o.GetNamespace()); diff != "" {
+				t.Errorf("\nReason: %s\nNameAfterWorkload(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNamePackages(t *testing.T) {
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: workloadNamespace, Name: workloadName}}
+	prefixed := func(w metav1.Object) types.NamespacedName {
+		return types.NamespacedName{Namespace: "packages", Name: w.GetNamespace() + "-" + w.GetName()}
+	}
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        types.NamespacedName
+	}{
+		"NotSharded": {
+			reason: "Top-level objects should be named and namespaced by the PackageNamer.",
+			want:   types.NamespacedName{Namespace: "packages", Name: workloadNamespace + "-" + workloadName},
+		},
+		"LaterShard": {
+			reason:      "Later shards of a package should be suffixed with their index.",
+			annotations: map[string]string{AnnotationShard: "2"},
+			want:        types.NamespacedName{Namespace: "packages", Name: workloadNamespace + "-" + workloadName + "-2"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &workloadv1alpha1.KubernetesApplication{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			NamePackages(prefixed)(w, o)
+			got := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nNamePackages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
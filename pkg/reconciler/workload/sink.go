@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errMarshalPackage = "cannot marshal workload package"
+	errApplyConfigMap = "cannot apply workload package ConfigMap"
+)
+
+// ConfigMapKeyPackage is the key under which ConfigMapPackageSink stores the
+// YAML manifests of a workload's package.
+const ConfigMapKeyPackage = "package.yaml"
+
+// ConfigMapSuffix is appended to the name of a workload to name the ConfigMap
+// in which ConfigMapPackageSink stores its package.
+const ConfigMapSuffix = "-package"
+
+var (
+	configMapKind       = reflect.TypeOf(corev1.ConfigMap{}).Name()
+	configMapAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// A PackageSink stores the rendered package of a workload, for example so that
+// it may be reviewed before it is applied.
+type PackageSink interface {
+	Store(ctx context.Context, w Workload, objs []Object) error
+}
+
+// A PackageSinkFn stores the rendered package of a workload.
+type PackageSinkFn func(ctx context.Context, w Workload, objs []Object) error
+
+// Store the supplied package of the supplied workload.
+func (fn PackageSinkFn) Store(ctx context.Context, w Workload, objs []Object) error {
+	return fn(ctx, w, objs)
+}
+
+// NopStorePackage does not store the supplied package.
+func NopStorePackage(_ context.Context, _ Workload, _ []Object) error { return nil }
+
+// A ConfigMapPackageSink stores the rendered package of a workload as a stream
+// of YAML manifests in a ConfigMap, from which it may be committed to a git
+// repository for review.
+type ConfigMapPackageSink struct {
+	client client.Client
+}
+
+// NewConfigMapPackageSink returns a PackageSink that stores the package of a
+// workload by applying a ConfigMap using the supplied client.
+func NewConfigMapPackageSink(c client.Client) *ConfigMapPackageSink {
+	return &ConfigMapPackageSink{client: c}
+}
+
+// Store the supplied package of the supplied workload in a ConfigMap in the
+// workload's namespace. The ConfigMap is named after the workload and is
+// controlled by it.
+func (s *ConfigMapPackageSink) Store(ctx context.Context, w Workload, objs []Object) error {
+	buf := &bytes.Buffer{}
+	for _, o := range objs {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return errors.Wrap(err, errMarshalPackage)
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       configMapKind,
+			APIVersion: configMapAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: w.GetNamespace(),
+			Name:      w.GetName() + ConfigMapSuffix,
+			Labels:    map[string]string{labelKey: string(w.GetUID())},
+		},
+		Data: map[string]string{ConfigMapKeyPackage: buf.String()},
+	}
+	meta.AddOwnerReference(cm, *metav1.NewControllerRef(w, w.GetObjectKind().GroupVersionKind()))
+
+	return errors.Wrap(resource.Apply(ctx, s.client, cm, resource.ControllersMustMatch()), errApplyConfigMap)
+}
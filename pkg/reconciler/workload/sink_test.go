@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestConfigMapPackageSink(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	w := &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cool", UID: "uid"}}
+	objs := []Object{&corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: configMapKind, APIVersion: configMapAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+	}}
+
+	type args struct {
+		c    client.Client
+		w    Workload
+		objs []Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ApplyError": {
+			reason: "Errors applying the package ConfigMap should be returned.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				w:    w,
+				objs: objs,
+			},
+			want: errors.Wrap(errBoom, errApplyConfigMap),
+		},
+		"Success": {
+			reason: "The package should be stored as a stream of YAML manifests in a ConfigMap named after the workload.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+						cm := obj.(*corev1.ConfigMap)
+						if diff := cmp.Diff("cool"+ConfigMapSuffix, cm.GetName()); diff != "" {
+							return errors.Errorf("MockCreate: -want name, +got name: %s", diff)
+						}
+						if diff := cmp.Diff("ns", cm.GetNamespace()); diff != "" {
+							return errors.Errorf("MockCreate: -want namespace, +got namespace: %s", diff)
+						}
+						want := "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  creationTimestamp: null\n  name: cool\n"
+						if diff := cmp.Diff(want, cm.Data[ConfigMapKeyPackage]); diff != "" {
+							return errors.Errorf("MockCreate: -want package, +got package: %s", diff)
+						}
+						return nil
+					},
+				},
+				w:    w,
+				objs: objs,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewConfigMapPackageSink(tc.args.c)
+			got := s.Store(context.Background(), tc.args.w, tc.args.objs)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ns.Store(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
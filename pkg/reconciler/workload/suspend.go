@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// AnnotationSuspend may be set to "true" on a workload to hibernate it. The
+// Deployments of a suspended workload's translation are scaled to zero and its
+// CronJobs are suspended, while all of its other remote resources are kept.
+// The workload resumes once the annotation is removed. Suspended objects of a
+// workload's translation are annotated too, so that traits do not scale them
+// back up.
+const AnnotationSuspend = "workload.oam.crossplane.io/suspend"
+
+// Suspended returns true if the supplied workload, or object of a workload's
+// translation, is suspended.
+func Suspended(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationSuspend] == "true"
+}
+
+var _ TranslationWrapper = SuspendWrapper
+
+// SuspendWrapper scales the Deployments of the translation of a suspended
+// workload to zero and suspends its CronJobs. The translations of workloads
+// that are not suspended are returned unchanged.
+func SuspendWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+	if !Suspended(w) {
+		return objs, nil
+	}
+
+	for _, o := range objs {
+		switch obj := o.(type) {
+		case *appsv1.Deployment:
+			zero := int32(0)
+			obj.Spec.Replicas = &zero
+		case *batchv1beta1.CronJob:
+			suspend := true
+			obj.Spec.Suspend = &suspend
+		default:
+			continue
+		}
+		meta.AddAnnotations(o, map[string]string{AnnotationSuspend: "true"})
+	}
+
+	return objs, nil
+}
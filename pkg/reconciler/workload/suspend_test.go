@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestSuspendWrapper(t *testing.T) {
+	replicas := int32(3)
+	zero := int32(0)
+	suspend := true
+	suspended := map[string]string{AnnotationSuspend: "true"}
+
+	type args struct {
+		w Workload
+		o []Object
+	}
+
+	type want struct {
+		result []Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotSuspended": {
+			reason: "The translation of a workload that is not suspended should be returned unchanged.",
+			args: args{
+				w: &workloadfake.Workload{},
+				o: []Object{&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}},
+			},
+			want: want{result: []Object{&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}}},
+		},
+		"Suspended": {
+			reason: "The Deployments of a suspended workload should be scaled to zero and its CronJobs suspended, while its other objects are kept.",
+			args: args{
+				w: &workloadfake.Workload{ObjectMeta: metav1.ObjectMeta{Annotations: suspended}},
+				o: []Object{
+					&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}},
+					&batchv1beta1.CronJob{},
+					&corev1.Service{},
+				},
+			},
+			want: want{result: []Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Annotations: suspended},
+					Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+				},
+				&batchv1beta1.CronJob{
+					ObjectMeta: metav1.ObjectMeta{Annotations: suspended},
+					Spec:       batchv1beta1.CronJobSpec{Suspend: &suspend},
+				},
+				&corev1.Service{},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := SuspendWrapper(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSuspendWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nSuspendWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errGetTarget           = "cannot get kubernetes target"
+	errListTargets         = "cannot list kubernetes targets"
+	errParseTargetSelector = "cannot parse kubernetes target selector"
+	errNoMatchingTarget    = "no kubernetes target matches selector"
+)
+
+// AnnotationTarget may be set on a workload, or on the ApplicationConfiguration
+// that controls it, to schedule its KubernetesApplication to the named
+// KubernetesTarget instead of letting Crossplane choose one.
+const AnnotationTarget = "workload.oam.crossplane.io/target"
+
+// AnnotationTargetSelector may be set on a workload, or on the
+// ApplicationConfiguration that controls it, to schedule its
+// KubernetesApplication to a KubernetesTarget matching the supplied label
+// selector, for example "region=us-west,tier!=canary".
+const AnnotationTargetSelector = "workload.oam.crossplane.io/target-selector"
+
+// A ResolverChain resolves a workload using each of its resolvers in order.
+type ResolverChain []ParameterResolver
+
+// NewResolverChain returns a ResolverChain of the supplied resolvers.
+func NewResolverChain(r ...ParameterResolver) ResolverChain {
+	return ResolverChain(r)
+}
+
+// Resolve the supplied workload using each resolver of the chain in order.
+func (rc ResolverChain) Resolve(ctx context.Context, w Workload) (Workload, error) {
+	for _, r := range rc {
+		var err error
+		if w, err = r.Resolve(ctx, w); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// InheritTarget returns a ParameterResolver that copies the target
+// annotations of the ApplicationConfiguration that controls a workload onto a
+// copy of the workload, unless the workload specifies a target itself.
+// Inherited annotations are part of the resolved workload, so changing them
+// invalidates any cached translation.
+func InheritTarget(c client.Reader) ParameterResolver {
+	return ParameterResolverFn(func(ctx context.Context, w Workload) (Workload, error) {
+		if hasTarget(w) {
+			return w, nil
+		}
+		ref := metav1.GetControllerOf(w)
+		if ref == nil || ref.Kind != oamv1alpha2.ApplicationConfigurationKind {
+			return w, nil
+		}
+
+		ac := &oamv1alpha2.ApplicationConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: w.GetNamespace(), Name: ref.Name}, ac); err != nil {
+			return nil, errors.Wrap(err, errGetAppConfig)
+		}
+		if !hasTarget(ac) {
+			return w, nil
+		}
+
+		out := w.DeepCopyObject().(Workload)
+		for _, k := range []string{AnnotationTarget, AnnotationTargetSelector} {
+			if v, ok := ac.GetAnnotations()[k]; ok {
+				meta.AddAnnotations(out, map[string]string{k: v})
+			}
+		}
+		return out, nil
+	})
+}
+
+func hasTarget(o metav1.Object) bool {
+	a := o.GetAnnotations()
+	return a[AnnotationTarget] != "" || a[AnnotationTargetSelector] != ""
+}
+
+// PinTarget returns a TranslationWrapper that schedules the KubernetesApplication
+// a workload is packaged as to the KubernetesTarget its annotations specify.
+// A named target must exist in the workload's namespace, and at least one
+// target there must match a target selector. A named target takes precedence
+// over a selector. Applications of workloads that specify no target are
+// scheduled by Crossplane.
+func PinTarget(c client.Reader) TranslationWrapper {
+	return func(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
+		if !hasTarget(w) {
+			return objs, nil
+		}
+
+		for _, o := range objs {
+			a, ok := o.(*workloadv1alpha1.KubernetesApplication)
+			if !ok {
+				continue
+			}
+
+			if name := w.GetAnnotations()[AnnotationTarget]; name != "" {
+				t := &workloadv1alpha1.KubernetesTarget{}
+				if err := c.Get(ctx, types.NamespacedName{Namespace: w.GetNamespace(), Name: name}, t); err != nil {
+					return nil, errors.Wrapf(err, "%s %s", errGetTarget, name)
+				}
+				a.Spec.Target = &workloadv1alpha1.KubernetesTargetReference{Name: name}
+				continue
+			}
+
+			s := w.GetAnnotations()[AnnotationTargetSelector]
+			ls, err := metav1.ParseToLabelSelector(s)
+			if err != nil {
+				return nil, errors.Wrap(err, errParseTargetSelector)
+			}
+			sel, err := metav1.LabelSelectorAsSelector(ls)
+			if err != nil {
+				return nil, errors.Wrap(err, errParseTargetSelector)
+			}
+			l := &workloadv1alpha1.KubernetesTargetList{}
+			if err := c.List(ctx, l, client.InNamespace(w.GetNamespace()), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+				return nil, errors.Wrap(err, errListTargets)
+			}
+			if len(l.Items) == 0 {
+				return nil, errors.Errorf("%s: %s", errNoMatchingTarget, s)
+			}
+			a.Spec.TargetSelector = ls
+		}
+		return objs, nil
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ ParameterResolver = ResolverChain{}
+
+func TestResolverChain(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	annotate := func(k string) ParameterResolver {
+		return ParameterResolverFn(func(_ context.Context, w Workload) (Workload, error) {
+			out := w.DeepCopyObject().(Workload)
+			out.SetAnnotations(map[string]string{k: "true"})
+			return out, nil
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		rc     ResolverChain
+		want   Workload
+		err    error
+	}{
+		"Empty": {
+			reason: "An empty chain should return the workload unchanged.",
+			rc:     NewResolverChain(),
+			want:   NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind),
+		},
+		"Error": {
+			reason: "Errors resolving the workload should be returned.",
+			rc: NewResolverChain(ParameterResolverFn(func(_ context.Context, _ Workload) (Workload, error) {
+				return nil, errBoom
+			})),
+			err: errBoom,
+		},
+		"Chained": {
+			reason: "Each resolver should resolve the workload returned by the previous resolver.",
+			rc:     NewResolverChain(annotate("first"), annotate("second")),
+			want: func() Workload {
+				w := NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind)
+				w.SetAnnotations(map[string]string{"second": "true"})
+				return w
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.rc.Resolve(context.Background(), NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nResolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nResolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInheritTarget(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := func(controlled bool, annotations map[string]string) Workload {
+		w := NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind)
+		w.SetName("cool-workload")
+		w.SetAnnotations(annotations)
+		if controlled {
+			c := true
+			w.SetOwnerReferences([]metav1.OwnerReference{{
+				APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+				Kind:       oamv1alpha2.ApplicationConfigurationKind,
+				Name:       "cool-app",
+				Controller: &c,
+			}})
+		}
+		return w
+	}
+
+	ac := func(annotations map[string]string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			obj.(*oamv1alpha2.ApplicationConfiguration).SetAnnotations(annotations)
+			return nil
+		}
+	}
+
+	type want struct {
+		w   Workload
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      Workload
+		want   want
+	}{
+		"WorkloadHasTarget": {
+			reason: "A workload that specifies a target should not inherit one.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(true, map[string]string{AnnotationTarget: "cool-target"}),
+			want:   want{w: workload(true, map[string]string{AnnotationTarget: "cool-target"})},
+		},
+		"NotControlled": {
+			reason: "A workload that is not controlled by an ApplicationConfiguration should be returned unchanged.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(false, nil),
+			want:   want{w: workload(false, nil)},
+		},
+		"GetAppConfigError": {
+			reason: "Errors getting the ApplicationConfiguration should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(true, nil),
+			want:   want{err: errors.Wrap(errBoom, errGetAppConfig)},
+		},
+		"AppConfigHasNoTarget": {
+			reason: "A workload whose ApplicationConfiguration specifies no target should be returned unchanged.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil, ac(map[string]string{"cool": "annotation"}))},
+			w:      workload(true, nil),
+			want:   want{w: workload(true, nil)},
+		},
+		"Inherited": {
+			reason: "The target annotations of the ApplicationConfiguration should be copied to the workload.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, ac(map[string]string{
+				AnnotationTargetSelector: "region=us-west",
+				"cool":                   "annotation",
+			}))},
+			w:    workload(true, nil),
+			want: want{w: workload(true, map[string]string{AnnotationTargetSelector: "region=us-west"})},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := InheritTarget(tc.c).Resolve(context.Background(), tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nInheritTarget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\nReason: %s\nInheritTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPinTarget(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := func(annotations map[string]string) Workload {
+		w := NewUnstructured(oamv1alpha2.ContainerizedWorkloadGroupVersionKind)
+		w.SetAnnotations(annotations)
+		return w
+	}
+
+	targets := func(n int) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*workloadv1alpha1.KubernetesTargetList)
+			l.Items = make([]workloadv1alpha1.KubernetesTarget, n)
+			return nil
+		}
+	}
+
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		w      Workload
+		objs   []Object
+		want   want
+	}{
+		"NoTarget": {
+			reason: "The package of a workload that specifies no target should be returned unchanged.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(nil),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want:   want{objs: []Object{&workloadv1alpha1.KubernetesApplication{}}},
+		},
+		"GetTargetError": {
+			reason: "A named target that cannot be got should return an error.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			w:      workload(map[string]string{AnnotationTarget: "cool-target"}),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want:   want{err: errors.Wrapf(errBoom, "%s %s", errGetTarget, "cool-target")},
+		},
+		"NamedTarget": {
+			reason: "An existing named target should be set as the application's target.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			w: workload(map[string]string{
+				AnnotationTarget:         "cool-target",
+				AnnotationTargetSelector: "region=us-west",
+			}),
+			objs: []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want: want{objs: []Object{&workloadv1alpha1.KubernetesApplication{
+				Spec: workloadv1alpha1.KubernetesApplicationSpec{
+					Target: &workloadv1alpha1.KubernetesTargetReference{Name: "cool-target"},
+				},
+			}}},
+		},
+		"InvalidSelector": {
+			reason: "A target selector that cannot be parsed should return an error.",
+			c:      &test.MockClient{},
+			w:      workload(map[string]string{AnnotationTargetSelector: "region in us-west"}),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want: want{err: func() error {
+				_, err := metav1.ParseToLabelSelector("region in us-west")
+				return errors.Wrap(err, errParseTargetSelector)
+			}()},
+		},
+		"ListTargetsError": {
+			reason: "Errors listing targets should be returned.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			w:      workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want:   want{err: errors.Wrap(errBoom, errListTargets)},
+		},
+		"NoMatchingTarget": {
+			reason: "A target selector that matches no target should return an error.",
+			c:      &test.MockClient{MockList: targets(0)},
+			w:      workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want:   want{err: errors.Errorf("%s: %s", errNoMatchingTarget, "region=us-west")},
+		},
+		"TargetSelector": {
+			reason: "A target selector that matches a target should be set as the application's target selector.",
+			c:      &test.MockClient{MockList: targets(1)},
+			w:      workload(map[string]string{AnnotationTargetSelector: "region=us-west"}),
+			objs:   []Object{&workloadv1alpha1.KubernetesApplication{}},
+			want: want{objs: []Object{&workloadv1alpha1.KubernetesApplication{
+				Spec: workloadv1alpha1.KubernetesApplicationSpec{
+					TargetSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-west"}},
+				},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PinTarget(tc.c)(context.Background(), tc.w, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPinTarget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nPinTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
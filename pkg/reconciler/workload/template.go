@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	gotemplate "text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errMapWorkloadKind       = "cannot determine CustomResourceDefinition of workload kind"
+	errGetWorkloadDefinition = "cannot get WorkloadDefinition of workload kind"
+	errParseTemplate         = "cannot parse translation template"
+	errExecuteTemplate       = "cannot execute translation template"
+	errDecodeTemplate        = "cannot decode output of translation template"
+	errInvalidTemplate       = "translation template output must specify an apiVersion, kind, and name"
+	errNamespacedTemplate    = "translation template output must not specify a namespace"
+)
+
+// AnnotationTranslationTemplate may be set on a WorkloadDefinition to a Go
+// template that renders a workload of the kind it defines into a multi
+// document YAML stream of remote manifests. The template is executed with the
+// workload, e.g. {{ .metadata.name }} or {{ .spec.image }}, and may use the
+// toJSON function to render nested fields. Manifests are sent to the remote
+// cluster in the namespace of the workload's other remote objects, and thus
+// must not specify a namespace.
+const AnnotationTranslationTemplate = "workloaddefinition.oam.crossplane.io/translation-template"
+
+// A DefinitionTemplateTranslator translates unstructured workloads using the
+// translation template of the WorkloadDefinition of their kind, so that
+// platform teams may support a new workload kind without writing a
+// translator. By OAM convention a WorkloadDefinition is named after the
+// CustomResourceDefinition of the kind it defines.
+type DefinitionTemplateTranslator struct {
+	client   client.Reader
+	mapper   meta.RESTMapper
+	fallback TranslateFn
+}
+
+// NewDefinitionTemplateTranslator returns a DefinitionTemplateTranslator.
+// Workloads whose WorkloadDefinition does not exist or has no translation
+// template are translated by the supplied fallback.
+func NewDefinitionTemplateTranslator(c client.Reader, m meta.RESTMapper, fallback TranslateFn) *DefinitionTemplateTranslator {
+	return &DefinitionTemplateTranslator{client: c, mapper: m, fallback: fallback}
+}
+
+// Translate the supplied workload using the translation template of its
+// WorkloadDefinition. The template is read each time a workload is
+// translated, so that changes to it take effect without restarting the addon.
+func (t *DefinitionTemplateTranslator) Translate(ctx context.Context, w Workload) ([]Object, error) {
+	u, ok := w.(unstructuredWrapper)
+	if !ok {
+		return nil, errors.New(errNotUnstructured)
+	}
+	src := u.GetUnstructured()
+
+	gvk := src.GroupVersionKind()
+	m, err := t.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, errMapWorkloadKind)
+	}
+
+	def := &unstructured.Unstructured{}
+	def.SetGroupVersionKind(oamv1alpha2.WorkloadDefinitionGroupVersionKind)
+	if err := t.client.Get(ctx, types.NamespacedName{Name: m.Resource.GroupResource().String()}, def); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetWorkloadDefinition)
+	}
+	tmpl := def.GetAnnotations()[AnnotationTranslationTemplate]
+	if strings.TrimSpace(tmpl) == "" {
+		return t.fallback(ctx, w)
+	}
+
+	return RenderTranslationTemplate(tmpl, src)
+}
+
+// RenderTranslationTemplate renders the supplied workload into objects using
+// the supplied translation template.
+func RenderTranslationTemplate(tmpl string, w *unstructured.Unstructured) ([]Object, error) {
+	tp, err := gotemplate.New(AnnotationTranslationTemplate).Funcs(gotemplate.FuncMap{"toJSON": toJSON}).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseTemplate)
+	}
+	out := &bytes.Buffer{}
+	if err := tp.Execute(out, w.Object); err != nil {
+		return nil, errors.Wrap(err, errExecuteTemplate)
+	}
+
+	objs := []Object{}
+	d := utilyaml.NewYAMLOrJSONDecoder(out, 4096)
+	for {
+		o := &unstructured.Unstructured{}
+		err := d.Decode(&o.Object)
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errDecodeTemplate)
+		}
+		if len(o.Object) == 0 {
+			continue
+		}
+		if o.GetAPIVersion() == "" || o.GetKind() == "" || o.GetName() == "" {
+			return nil, errors.New(errInvalidTemplate)
+		}
+		if o.GetNamespace() != "" {
+			return nil, errors.Errorf("%s: %s %s", errNamespacedTemplate, o.GetKind(), o.GetName())
+		}
+		objs = append(objs, o)
+	}
+}
+
+// toJSON renders the supplied value as JSON, which is also valid YAML, so that
+// templates may copy nested fields of a workload as they are.
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
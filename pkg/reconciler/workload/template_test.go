@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestRenderTranslationTemplate(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	w := NewUnstructured(gvk)
+	w.SetName("cool")
+	w.Object["spec"] = map[string]interface{}{
+		"image": "cool/image:1",
+		"ports": []interface{}{"http"},
+	}
+
+	deployment := func() Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetName("cool")
+		u.Object["spec"] = map[string]interface{}{
+			"image": "cool/image:1",
+			"ports": []interface{}{"http"},
+		}
+		return u
+	}
+
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		tmpl   string
+		want   want
+	}{
+		"ParseError": {
+			reason: "A template that cannot be parsed should return an error.",
+			tmpl:   "{{ .metadata.name",
+			want: want{err: errors.Wrap(func() error {
+				_, err := RenderTranslationTemplate("{{ .metadata.name", w.GetUnstructured())
+				return errors.Cause(err)
+			}(), errParseTemplate)},
+		},
+		"InvalidObject": {
+			reason: "Rendered manifests without a name should return an error.",
+			tmpl:   "apiVersion: v1\nkind: ConfigMap\n",
+			want:   want{err: errors.New(errInvalidTemplate)},
+		},
+		"NamespacedObject": {
+			reason: "Rendered manifests that specify a namespace should return an error.",
+			tmpl:   "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: cool, namespace: cool}\n",
+			want:   want{err: errors.Errorf("%s: %s %s", errNamespacedTemplate, "ConfigMap", "cool")},
+		},
+		"Rendered": {
+			reason: "Every manifest rendered from the workload should be returned.",
+			tmpl: `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .metadata.name }}
+spec:
+  image: {{ .spec.image }}
+  ports: {{ toJSON .spec.ports }}
+---
+`,
+			want: want{objs: []Object{deployment()}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenderTranslationTemplate(tc.tmpl, w.GetUnstructured())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRenderTranslationTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nRenderTranslationTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefinitionTemplateTranslator(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	w := NewUnstructured(gvk)
+	w.SetName("cool")
+
+	forwarded := []Object{&unstructured.Unstructured{}}
+	fallback := func(_ context.Context, _ Workload) ([]Object, error) { return forwarded, nil }
+
+	definition := func(annotations map[string]string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			if u.GetKind() != "WorkloadDefinition" {
+				return errors.New("got the wrong kind")
+			}
+			u.SetAnnotations(annotations)
+			return nil
+		})
+	}
+
+	configMap := func() Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetName("cool")
+		return u
+	}
+
+	type args struct {
+		c client.Reader
+		w Workload
+	}
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "Workloads that are not unstructured should return an error.",
+			args:   args{w: &workloadfake.Workload{}},
+			want:   want{err: errors.New(errNotUnstructured)},
+		},
+		"UnknownKind": {
+			reason: "Workloads whose CustomResourceDefinition cannot be determined should return an error.",
+			args:   args{w: NewUnstructured(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Unknown"})},
+			want: want{err: errors.Wrap(func() error {
+				_, err := mapper.RESTMapping(schema.GroupKind{Group: "example.org", Kind: "Unknown"}, "v1")
+				return err
+			}(), errMapWorkloadKind)},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition should be returned.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				w: w,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetWorkloadDefinition)},
+		},
+		"NoDefinition": {
+			reason: "Workloads whose WorkloadDefinition does not exist should be translated by the fallback.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cools.example.org"))},
+				w: w,
+			},
+			want: want{objs: forwarded},
+		},
+		"NoTemplate": {
+			reason: "Workloads whose WorkloadDefinition has no translation template should be translated by the fallback.",
+			args: args{
+				c: &test.MockClient{MockGet: definition(nil)},
+				w: w,
+			},
+			want: want{objs: forwarded},
+		},
+		"Template": {
+			reason: "Workloads whose WorkloadDefinition has a translation template should be rendered by it.",
+			args: args{
+				c: &test.MockClient{MockGet: definition(map[string]string{
+					AnnotationTranslationTemplate: "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: {{ .metadata.name }}}\n",
+				})},
+				w: w,
+			},
+			want: want{objs: []Object{configMap()}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := NewDefinitionTemplateTranslator(tc.args.c, mapper, fallback)
+			got, err := tr.Translate(context.Background(), tc.args.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Translate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\ntr.Translate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
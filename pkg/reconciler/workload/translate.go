@@ -19,16 +19,13 @@ package workload
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"reflect"
-	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
 )
@@ -115,6 +112,9 @@ func KubeAppWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, e
 	app := &workloadv1alpha1.KubernetesApplication{}
 
 	for _, o := range objs {
+		// Secrets an object depends on are referenced by its template, so
+		// that their data is never copied into the package.
+		o, secrets := secretReferences(o)
 		b, err := json.Marshal(o)
 		if err != nil {
 			return nil, errors.Wrap(err, errWrapInKubeApp)
@@ -122,13 +122,14 @@ func KubeAppWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, e
 
 		kart := workloadv1alpha1.KubernetesApplicationResourceTemplate{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: fmt.Sprintf("%s-%s", o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)),
+				Name: templateName(o),
 				Labels: map[string]string{
 					labelKey: string(w.GetUID()),
 				},
 			},
 			Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
 				Template: runtime.RawExtension{Raw: b},
+				Secrets:  secrets,
 			},
 		}
 
@@ -148,48 +149,69 @@ func KubeAppWrapper(ctx context.Context, w Workload, objs []Object) ([]Object, e
 
 var _ TranslationWrapper = ServiceInjector
 
-// ServiceInjector adds a Service object for the first Port on the first
-// Container for the first Deployment observed in a workload translation.
+// ServiceInjector adds a Service object that exposes the ports of all
+// Containers of the first Deployment or DaemonSet observed in a workload
+// translation, or one Service per Container if the workload is annotated with
+// AnnotationServicePerContainer. The Service's type and annotations may be
+// chosen by annotating the workload.
 func ServiceInjector(ctx context.Context, w Workload, objs []Object) ([]Object, error) {
 	if objs == nil {
 		return nil, nil
 	}
 
+	st, err := serviceType(w)
+	if err != nil {
+		return nil, err
+	}
+	sa, err := serviceAnnotations(w)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, o := range objs {
-		d, ok := o.(*appsv1.Deployment)
-		if !ok {
+		var selector *metav1.LabelSelector
+		var containers []corev1.Container
+		switch d := o.(type) {
+		case *appsv1.Deployment:
+			selector, containers = d.Spec.Selector, d.Spec.Template.Spec.Containers
+		case *appsv1.DaemonSet:
+			selector, containers = d.Spec.Selector, d.Spec.Template.Spec.Containers
+		default:
 			continue
 		}
 
-		if len(d.Spec.Template.Spec.Containers) > 0 {
-			s := &corev1.Service{
-				TypeMeta: metav1.TypeMeta{
-					Kind:       serviceKind,
-					APIVersion: serviceAPIVersion,
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name: d.GetName(),
-					Labels: map[string]string{
-						labelKey: string(w.GetUID()),
+		if len(containers) > 0 {
+			newService := func(name string, containers ...corev1.Container) *corev1.Service {
+				return &corev1.Service{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       serviceKind,
+						APIVersion: serviceAPIVersion,
 					},
-				},
-				Spec: corev1.ServiceSpec{
-					Selector: d.Spec.Selector.MatchLabels,
-					Ports:    []corev1.ServicePort{},
-					Type:     corev1.ServiceTypeLoadBalancer,
-				},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+						Labels: map[string]string{
+							labelKey: string(w.GetUID()),
+						},
+						Annotations: sa,
+					},
+					Spec: corev1.ServiceSpec{
+						Selector: selector.MatchLabels,
+						Ports:    servicePorts(name, containers),
+						Type:     st,
+					},
+				}
 			}
 
-			if len(d.Spec.Template.Spec.Containers[0].Ports) > 0 {
-				s.Spec.Ports = []corev1.ServicePort{
-					{
-						Name:       d.GetName(),
-						Port:       d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort,
-						TargetPort: intstr.FromInt(int(d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)),
-					},
+			if !servicePerContainer(w) {
+				objs = append(objs, newService(o.GetName(), containers...))
+				break
+			}
+			for _, c := range containers {
+				if len(c.Ports) == 0 {
+					continue
 				}
+				objs = append(objs, newService(dnsLabel(o.GetName()+"-"+c.Name), c))
 			}
-			objs = append(objs, s)
 			break
 		}
 	}
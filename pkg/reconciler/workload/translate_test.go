@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -125,6 +126,16 @@ func sWithContainerPort(target int) serviceModifier {
 	}
 }
 
+func sWithPorts(ports ...corev1.ServicePort) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Ports = append(s.Spec.Ports, ports...)
+	}
+}
+
+func servicePort(name string, port int) corev1.ServicePort {
+	return corev1.ServicePort{Name: name, Port: int32(port), TargetPort: intstr.FromInt(port)}
+}
+
 func service(mod ...serviceModifier) *corev1.Service {
 	s := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -210,8 +221,49 @@ func TestKubeAppWrapper(t *testing.T) {
 						},
 					},
 				},
-			}},
-			}},
+			}}},
+		},
+		"SuccessfulWrapDeploymentWithSecrets": {
+			reason: "The Secrets a Deployment depends on should be referenced by its resource template rather than copied.",
+			args: args{
+				w: &workloadfake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []Object{func() Object {
+					d := deployment()
+					d.SetAnnotations(map[string]string{AnnotationPropagateSecrets: "creds,regcred"})
+					return d
+				}()},
+			},
+			want: want{result: []Object{&workloadv1alpha1.KubernetesApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: workloadName,
+				},
+				Spec: workloadv1alpha1.KubernetesApplicationSpec{
+					ResourceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							labelKey: workloadUID,
+						},
+					},
+					ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   fmt.Sprintf("%s-%s", workloadName, "deployment"),
+								Labels: map[string]string{labelKey: workloadUID},
+							},
+							Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+								Template: runtime.RawExtension{Raw: deployBytes},
+								Secrets:  []corev1.LocalObjectReference{{Name: "creds"}, {Name: "regcred"}},
+							},
+						},
+					},
+				},
+			}}},
+		},
 	}
 
 	for name, tc := range cases {
@@ -269,7 +321,7 @@ func TestServiceInjector(t *testing.T) {
 			}},
 		},
 		"SuccessfulInjectService_1D_1C_2P": {
-			reason: "A Deployment with a port(s) should have a Service injected for first defined port on the first container.",
+			reason: "A Deployment with a port(s) should have a Service injected for all ports of its container, with unique port names.",
 			args: args{
 				w: &workloadfake.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -282,7 +334,7 @@ func TestServiceInjector(t *testing.T) {
 			},
 			want: want{result: []Object{
 				deployment(dmWithContainerPorts(3000, 3001)),
-				service(sWithContainerPort(3000)),
+				service(sWithPorts(servicePort(portName, 3000), servicePort(portName+"-3001", 3001))),
 			}},
 		},
 		"SuccessfulInjectService_2D_1C_1P": {
@@ -307,7 +359,7 @@ func TestServiceInjector(t *testing.T) {
 			}},
 		},
 		"SuccessfulInjectService_2D_2C_2P": {
-			reason: "The first Deployment with a port(s) should have a Service injected for first defined port on the first container.",
+			reason: "The first Deployment with a port(s) should have a Service injected for all ports of all of its containers.",
 			args: args{
 				w: &workloadfake.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -324,7 +376,83 @@ func TestServiceInjector(t *testing.T) {
 			want: want{result: []Object{
 				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
 				deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
-				service(sWithContainerPort(3000)),
+				service(sWithPorts(
+					servicePort(portName, 3000),
+					servicePort(portName+"-3001", 3001),
+					servicePort(portName+"-4000", 4000),
+					servicePort(portName+"-4001", 4001),
+				)),
+			}},
+		},
+		"SuccessfulInjectServicePerContainer": {
+			reason: "A workload annotated to have a Service per container should have a Service injected for each container that exposes ports.",
+			args: args{
+				w: &workloadfake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationServicePerContainer: "true"},
+					},
+				},
+				o: []Object{deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts())},
+			},
+			want: want{result: []Object{
+				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts()),
+				service(
+					func(s *corev1.Service) { s.SetName(workloadName + "-" + containerName) },
+					sWithPorts(servicePort(portName, 3000), servicePort(portName+"-3001", 3001)),
+				),
+			}},
+		},
+		"UnknownServiceType": {
+			reason: "An unknown Service type should return an error.",
+			args: args{
+				w: &workloadfake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Annotations: map[string]string{AnnotationServiceType: "Cool"},
+					},
+				},
+				o: []Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf("%s: %s", errUnknownServiceType, "Cool")},
+		},
+		"InvalidServiceAnnotations": {
+			reason: "Service annotations that are not a JSON object should return an error.",
+			args: args{
+				w: &workloadfake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Annotations: map[string]string{AnnotationServiceAnnotations: "{"},
+					},
+				},
+				o: []Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseServiceAnnotations)},
+		},
+		"SuccessfulInjectAnnotatedService": {
+			reason: "The injected Service should have the type and annotations chosen by the workload.",
+			args: args{
+				w: &workloadfake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationServiceType:        string(corev1.ServiceTypeClusterIP),
+							AnnotationServiceAnnotations: `{"cloud.example.org/internal":"true"}`,
+						},
+					},
+				},
+				o: []Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), func(s *corev1.Service) {
+					s.Spec.Type = corev1.ServiceTypeClusterIP
+					s.SetAnnotations(map[string]string{"cloud.example.org/internal": "true"})
+				}),
 			}},
 		},
 	}
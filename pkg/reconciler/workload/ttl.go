@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+const (
+	errParseTTL    = "cannot parse TTL annotation"
+	errNegativeTTL = "TTL annotation must not be negative"
+)
+
+// AnnotationTTL may be set on a workload to tear down its package once the
+// supplied duration, e.g. "72h", has elapsed since the workload was created.
+// This is useful for ephemeral workloads such as preview environments.
+const AnnotationTTL = "workload.oam.crossplane.io/ttl"
+
+// ReasonExpired indicates that a workload's TTL elapsed and its package was
+// torn down.
+const ReasonExpired runtimev1alpha1.ConditionReason = "Expired"
+
+// Expired returns a condition that indicates a workload's TTL elapsed and its
+// package was torn down.
+func Expired() runtimev1alpha1.Condition {
+	return runtimev1alpha1.Condition{
+		Type:               runtimev1alpha1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExpired,
+	}
+}
+
+// ExpiresAt returns the time at which the supplied workload expires, and false
+// if it never expires.
+func ExpiresAt(w Workload) (time.Time, bool, error) {
+	v, ok := w.GetAnnotations()[AnnotationTTL]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, errParseTTL)
+	}
+	if ttl < 0 {
+		return time.Time{}, false, errors.New(errNegativeTTL)
+	}
+	return w.GetCreationTimestamp().Add(ttl), true, nil
+}
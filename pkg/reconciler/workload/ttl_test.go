@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+func TestExpiresAt(t *testing.T) {
+	created := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	withTTL := func(ttl string) Workload {
+		w := &workloadfake.Workload{}
+		w.SetCreationTimestamp(metav1.NewTime(created))
+		if ttl != "" {
+			w.SetAnnotations(map[string]string{AnnotationTTL: ttl})
+		}
+		return w
+	}
+
+	type want struct {
+		at      time.Time
+		expires bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      Workload
+		want   want
+	}{
+		"NoTTL": {
+			reason: "Workloads without a TTL should never expire.",
+			w:      withTTL(""),
+			want:   want{},
+		},
+		"TTL": {
+			reason: "Workloads should expire once their TTL has elapsed since they were created.",
+			w:      withTTL("72h"),
+			want:   want{at: created.Add(72 * time.Hour), expires: true},
+		},
+		"NegativeTTL": {
+			reason: "Negative TTLs should be rejected.",
+			w:      withTTL("-1h"),
+			want:   want{err: errors.New(errNegativeTTL)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			at, expires, err := ExpiresAt(tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nExpiresAt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.at, at); diff != "" {
+				t.Errorf("\nReason: %s\nExpiresAt(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.expires, expires); diff != "" {
+				t.Errorf("\nReason: %s\nExpiresAt(...): -want expires, +got expires:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
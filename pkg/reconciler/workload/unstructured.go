@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+const errNotUnstructured = "workload is not unstructured"
+
+// An Unstructured workload is a workload of a kind that is not known to the
+// addon at compile time. Its conditions are read from and written to its
+// status.conditions field.
+type Unstructured struct {
+	unstructured.Unstructured
+}
+
+// NewUnstructured returns an empty Unstructured workload of the supplied kind.
+func NewUnstructured(gvk schema.GroupVersionKind) *Unstructured {
+	u := &Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	return u
+}
+
+// GetUnstructured returns the underlying unstructured object.
+func (u *Unstructured) GetUnstructured() *unstructured.Unstructured {
+	return &u.Unstructured
+}
+
+// GetCondition of this workload.
+func (u *Unstructured) GetCondition(ct v1alpha1.ConditionType) v1alpha1.Condition {
+	s := v1alpha1.ConditionedStatus{}
+	_ = getConditions(&u.Unstructured, &s)
+	return s.GetCondition(ct)
+}
+
+// SetConditions of this workload.
+func (u *Unstructured) SetConditions(c ...v1alpha1.Condition) {
+	s := v1alpha1.ConditionedStatus{}
+	_ = getConditions(&u.Unstructured, &s)
+	s.SetConditions(c...)
+	_ = setConditions(&u.Unstructured, s)
+}
+
+// SetPackageReferences records the top-level objects this workload was
+// packaged as in its status.packages field.
+func (u *Unstructured) SetPackageReferences(refs []corev1.ObjectReference) {
+	packages := make([]interface{}, 0, len(refs))
+	for i := range refs {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&refs[i])
+		if err != nil {
+			return
+		}
+		packages = append(packages, m)
+	}
+	_ = unstructured.SetNestedSlice(u.Object, packages, "status", "packages")
+}
+
+// DeepCopyObject returns a deep copy of this workload.
+func (u *Unstructured) DeepCopyObject() runtime.Object {
+	return &Unstructured{Unstructured: *u.Unstructured.DeepCopy()}
+}
+
+func getConditions(u *unstructured.Unstructured, s *v1alpha1.ConditionedStatus) error {
+	c, ok, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil || !ok {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{"conditions": c}, s)
+}
+
+func setConditions(u *unstructured.Unstructured, s v1alpha1.ConditionedStatus) error {
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&s)
+	if err != nil {
+		return err
+	}
+	c, _, _ := unstructured.NestedSlice(m, "conditions")
+	return unstructured.SetNestedSlice(u.Object, c, "status", "conditions")
+}
+
+// An unstructuredWrapper wraps an unstructured object.
+type unstructuredWrapper interface {
+	GetUnstructured() *unstructured.Unstructured
+}
+
+func unwrap(obj runtime.Object) runtime.Object {
+	if w, ok := obj.(unstructuredWrapper); ok {
+		return w.GetUnstructured()
+	}
+	return obj
+}
+
+// An UnstructuredClient unwraps Unstructured objects before passing them to
+// the underlying client. The controller-runtime client only treats objects of
+// type *unstructured.Unstructured as unstructured, and would otherwise attempt
+// to find the wrapper's kind in its scheme.
+type UnstructuredClient struct {
+	client.Client
+}
+
+// NewUnstructuredClient returns a client that unwraps Unstructured objects.
+func NewUnstructuredClient(c client.Client) *UnstructuredClient {
+	return &UnstructuredClient{Client: c}
+}
+
+// Get the supplied object.
+func (c *UnstructuredClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.Client.Get(ctx, key, unwrap(obj))
+}
+
+// Create the supplied object.
+func (c *UnstructuredClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, unwrap(obj), opts...)
+}
+
+// Update the supplied object.
+func (c *UnstructuredClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, unwrap(obj), opts...)
+}
+
+// Delete the supplied object.
+func (c *UnstructuredClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	return c.Client.Delete(ctx, unwrap(obj), opts...)
+}
+
+// Patch the supplied object.
+func (c *UnstructuredClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, unwrap(obj), patch, opts...)
+}
+
+// Status returns a client for the status subresource that unwraps
+// Unstructured objects.
+func (c *UnstructuredClient) Status() client.StatusWriter {
+	return &unstructuredStatusWriter{StatusWriter: c.Client.Status()}
+}
+
+type unstructuredStatusWriter struct {
+	client.StatusWriter
+}
+
+func (w *unstructuredStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return w.StatusWriter.Update(ctx, unwrap(obj), opts...)
+}
+
+func (w *unstructuredStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.StatusWriter.Patch(ctx, unwrap(obj), patch, opts...)
+}
+
+// Forward translates an Unstructured workload into a copy of itself, so that
+// the remote cluster, which is expected to have the workload's CRD installed,
+// reconciles it. Only the workload's spec, name, and labels are forwarded.
+func Forward(_ context.Context, w Workload) ([]Object, error) {
+	u, ok := w.(unstructuredWrapper)
+	if !ok {
+		return nil, errors.New(errNotUnstructured)
+	}
+	src := u.GetUnstructured()
+
+	out := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	out.SetGroupVersionKind(src.GroupVersionKind())
+	out.SetName(src.GetName())
+	out.SetLabels(src.GetLabels())
+	if spec, ok := src.Object["spec"]; ok {
+		out.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+	return []Object{out}, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	workloadfake "github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/workload/fake"
+)
+
+var _ Workload = &Unstructured{}
+var _ PackageRecorder = &Unstructured{}
+
+func TestUnstructuredConditions(t *testing.T) {
+	u := NewUnstructured(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"})
+	u.SetConditions(v1alpha1.ReconcileSuccess(), v1alpha1.Available())
+
+	if diff := cmp.Diff(v1alpha1.ReconcileSuccess(), u.GetCondition(v1alpha1.TypeSynced)); diff != "" {
+		t.Errorf("u.GetCondition(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(v1alpha1.Available(), u.DeepCopyObject().(*Unstructured).GetCondition(v1alpha1.TypeReady)); diff != "" {
+		t.Errorf("u.DeepCopyObject().GetCondition(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestUnstructuredPackageReferences(t *testing.T) {
+	u := NewUnstructured(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"})
+	u.SetPackageReferences([]corev1.ObjectReference{
+		{APIVersion: "workload.crossplane.io/v1alpha1", Kind: "KubernetesApplication", Namespace: "default", Name: "cool"},
+		{APIVersion: "workload.crossplane.io/v1alpha1", Kind: "KubernetesApplication", Namespace: "default", Name: "cool-1"},
+	})
+
+	want := []interface{}{
+		map[string]interface{}{"apiVersion": "workload.crossplane.io/v1alpha1", "kind": "KubernetesApplication", "namespace": "default", "name": "cool"},
+		map[string]interface{}{"apiVersion": "workload.crossplane.io/v1alpha1", "kind": "KubernetesApplication", "namespace": "default", "name": "cool-1"},
+	}
+	got, _, _ := unstructured.NestedSlice(u.Object, "status", "packages")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("u.SetPackageReferences(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestForward(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+
+	type want struct {
+		objs []Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      Workload
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "Workloads that are not unstructured should return an error.",
+			w:      &workloadfake.Workload{},
+			want:   want{err: errors.New(errNotUnstructured)},
+		},
+		"Forwarded": {
+			reason: "The spec, name, and labels of the workload should be forwarded.",
+			w: func() Workload {
+				u := NewUnstructured(gvk)
+				u.SetName("cool")
+				u.SetNamespace("coolns")
+				u.SetLabels(map[string]string{"cool": "true"})
+				u.Object["spec"] = map[string]interface{}{"replicas": int64(2)}
+				u.SetConditions(v1alpha1.ReconcileSuccess())
+				return u
+			}(),
+			want: want{objs: []Object{func() Object {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetGroupVersionKind(gvk)
+				u.SetName("cool")
+				u.SetLabels(map[string]string{"cool": "true"})
+				u.Object["spec"] = map[string]interface{}{"replicas": int64(2)}
+				return u
+			}()}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Forward(context.Background(), tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nForward(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nForward(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
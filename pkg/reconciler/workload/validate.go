@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	errReadSchema      = "cannot read remote OpenAPI schema"
+	errParseSchema     = "cannot parse remote OpenAPI schema"
+	errMarshalObject   = "cannot marshal translated object"
+	errKindUnsupported = "kind is not supported by the remote cluster"
+	errFieldUnknown    = "field is not supported by the remote cluster"
+)
+
+// A Validator validates the objects of a workload translation before they are
+// packaged.
+type Validator interface {
+	Validate(ctx context.Context, objs []Object) error
+}
+
+// A ValidateFn validates the objects of a workload translation.
+type ValidateFn func(ctx context.Context, objs []Object) error
+
+// Validate the objects of a workload translation.
+func (fn ValidateFn) Validate(ctx context.Context, objs []Object) error {
+	return fn(ctx, objs)
+}
+
+// NopValidate does not validate the objects of a workload translation.
+func NopValidate(_ context.Context, _ []Object) error { return nil }
+
+// An openAPISchema is the subset of an OpenAPI v2 schema that is used to
+// validate objects.
+type openAPISchema struct {
+	Ref                  string                   `json:"$ref,omitempty"`
+	Properties           map[string]openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema           `json:"additionalProperties,omitempty"`
+	Items                *openAPISchema           `json:"items,omitempty"`
+	GroupVersionKinds    []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// A SchemaValidator validates that the objects of a workload translation are
+// of kinds, and only use fields, that are supported by the remote cluster, as
+// described by the remote cluster's OpenAPI v2 document. This surfaces
+// translations that the remote cluster's version cannot accept when the
+// workload is translated, rather than when its package is applied remotely.
+type SchemaValidator struct {
+	definitions map[string]openAPISchema
+	kinds       map[schema.GroupVersionKind]string
+}
+
+// NewSchemaValidator returns a SchemaValidator that validates objects against
+// the supplied OpenAPI v2 document, as served by a Kubernetes API server at
+// /openapi/v2.
+func NewSchemaValidator(document []byte) (*SchemaValidator, error) {
+	doc := struct {
+		Definitions map[string]openAPISchema `json:"definitions"`
+	}{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, errors.Wrap(err, errParseSchema)
+	}
+
+	v := &SchemaValidator{definitions: doc.Definitions, kinds: map[schema.GroupVersionKind]string{}}
+	for name, d := range doc.Definitions {
+		for _, gvk := range d.GroupVersionKinds {
+			v.kinds[schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}] = name
+		}
+	}
+	return v, nil
+}
+
+// NewSchemaValidatorFromFile returns a SchemaValidator that validates objects
+// against the OpenAPI v2 document at the supplied path.
+func NewSchemaValidatorFromFile(path string) (*SchemaValidator, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadSchema)
+	}
+	return NewSchemaValidator(b)
+}
+
+// Validate the supplied objects against the remote cluster's schema. Status
+// fields are not validated, because they are never applied remotely.
+func (v *SchemaValidator) Validate(_ context.Context, objs []Object) error {
+	for _, o := range objs {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		name, ok := v.kinds[gvk]
+		if !ok {
+			return errors.Errorf("%s: %s", errKindUnsupported, gvk.String())
+		}
+
+		b, err := json.Marshal(o)
+		if err != nil {
+			return errors.Wrap(err, errMarshalObject)
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return errors.Wrap(err, errMarshalObject)
+		}
+		delete(m, "status")
+
+		if path := v.unknown(v.definitions[name], m, ""); path != "" {
+			return errors.Errorf("%s: %s %s: %s", errFieldUnknown, strings.ToLower(gvk.Kind), o.GetName(), path)
+		}
+	}
+	return nil
+}
+
+// unknown returns the path of the first field of the supplied value that is
+// not described by the supplied schema, or an empty string if all are.
+func (v *SchemaValidator) unknown(s openAPISchema, value interface{}, path string) string {
+	if s.Ref != "" {
+		s = v.definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			var fs *openAPISchema
+			switch {
+			case s.Properties != nil:
+				p, ok := s.Properties[k]
+				if !ok {
+					return path + "." + k
+				}
+				fs = &p
+			case s.AdditionalProperties != nil:
+				fs = s.AdditionalProperties
+			}
+			// Fields of objects with no described properties, for example
+			// those of custom resources without a structural schema, are
+			// not validated.
+			if fs == nil {
+				continue
+			}
+			if p := v.unknown(*fs, val[k], path+"."+k); p != "" {
+				return p
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return ""
+		}
+		for i := range val {
+			if p := v.unknown(*s.Items, val[i], path+"["+strconv.Itoa(i)+"]"); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// A heavily trimmed OpenAPI v2 document, in the style of one served by a
+// Kubernetes API server at /openapi/v2.
+const openAPIDocument = `{
+  "definitions": {
+    "io.k8s.api.apps.v1.Deployment": {
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.apps.v1.DeploymentSpec"}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "properties": {
+        "replicas": {"type": "integer"},
+        "selector": {"type": "object"},
+        "template": {
+          "properties": {
+            "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+            "spec": {
+              "properties": {
+                "containers": {"type": "array", "items": {"properties": {"name": {"type": "string"}, "image": {"type": "string"}, "resources": {"type": "object"}}}}
+              }
+            }
+          }
+        },
+        "strategy": {"type": "object"}
+      }
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "properties": {
+        "name": {"type": "string"},
+        "creationTimestamp": {"type": "string"},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    }
+  }
+}`
+
+func TestSchemaValidator(t *testing.T) {
+	d := func(ps corev1.PodSpec) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{"cool": "true"}},
+			Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: ps}},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []Object
+		want   error
+	}{
+		"Valid": {
+			reason: "Objects that only use supported fields should be valid.",
+			objs:   []Object{d(corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Image: "cool:latest"}}})},
+		},
+		"UnsupportedKind": {
+			reason: "Objects of kinds the remote cluster does not support should be invalid.",
+			objs:   []Object{&corev1.Service{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}}},
+			want:   errors.Errorf("%s: %s", errKindUnsupported, "/v1, Kind=Service"),
+		},
+		"UnknownField": {
+			reason: "Objects that use fields the remote cluster does not support should be invalid.",
+			objs:   []Object{d(corev1.PodSpec{Containers: []corev1.Container{{Name: "cool", Image: "cool:latest", WorkingDir: "/cool"}}})},
+			want:   errors.Errorf("%s: %s %s: %s", errFieldUnknown, "deployment", "cool", ".spec.template.spec.containers[0].workingDir"),
+		},
+	}
+
+	v, err := NewSchemaValidator([]byte(openAPIDocument))
+	if err != nil {
+		t.Fatalf("NewSchemaValidator(...): %s", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := v.Validate(context.Background(), tc.objs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nv.Validate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewSchemaValidator(t *testing.T) {
+	_, err := NewSchemaValidator([]byte("{"))
+	want := errors.Wrap(errors.New("unexpected end of JSON input"), errParseSchema)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("NewSchemaValidator(...): -want error, +got error:\n%s", diff)
+	}
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errNewRequest           = "cannot create registry request"
+	errRequest              = "cannot send registry request"
+	errStatus               = "unexpected registry response to"
+	errReadResponse         = "cannot read registry response"
+	errGetManifest          = "cannot get image manifest"
+	errNoCredentials        = "registry requires credentials, but none were found"
+	errUnsupportedChallenge = "unsupported registry authentication challenge"
+	errGetToken             = "cannot get registry token"
+	errDecodeToken          = "cannot decode registry token"
+	errEmptyToken           = "registry token response contained no token"
+)
+
+// Media types of the manifests the Client accepts. Manifest lists and image
+// indexes are preferred, so that an image resolves to the same digest on
+// every platform.
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+)
+
+const (
+	headerContentDigest = "Docker-Content-Digest"
+	defaultTimeout      = 30 * time.Second
+
+	// maxResponseBytes is the largest registry response the Client reads.
+	maxResponseBytes = 4 << 20
+)
+
+var manifestMediaTypes = []string{mediaTypeDockerManifestList, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeOCIManifest}
+
+// A Resolver resolves an image reference to the digest of its manifest.
+type Resolver interface {
+	Resolve(ctx context.Context, ref Reference, k Keychain) (string, error)
+}
+
+// A ResolverFn resolves an image reference to the digest of its manifest.
+type ResolverFn func(ctx context.Context, ref Reference, k Keychain) (string, error)
+
+// Resolve the supplied reference using the supplied credentials.
+func (fn ResolverFn) Resolve(ctx context.Context, ref Reference, k Keychain) (string, error) {
+	return fn(ctx, ref, k)
+}
+
+// A ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient specifies the HTTP client the Client should use to make
+// requests of registries.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithVerificationKey specifies that the Client should only resolve images
+// that have a cosign signature made by the supplied public key.
+func WithVerificationKey(k *ecdsa.PublicKey) ClientOption {
+	return func(c *Client) {
+		c.key = k
+	}
+}
+
+// A Client resolves images using the Docker Registry HTTP API V2 of the
+// registries that serve them.
+type Client struct {
+	http *http.Client
+	key  *ecdsa.PublicKey
+}
+
+// NewClient returns a Client that resolves images using the Docker Registry
+// HTTP API V2.
+func NewClient(o ...ClientOption) *Client {
+	c := &Client{http: &http.Client{Timeout: defaultTimeout}}
+	for _, co := range o {
+		co(c)
+	}
+	return c
+}
+
+// Resolve returns the digest of the manifest the supplied reference refers
+// to, authenticating to its registry using the supplied credentials if it
+// requires them. References that specify a digest resolve to it. If the
+// Client has a verification key the image must also have a cosign signature
+// made by that key.
+func (c *Client) Resolve(ctx context.Context, ref Reference, k Keychain) (string, error) {
+	d := ref.Digest
+	if d == "" {
+		var err error
+		if d, err = c.digest(ctx, ref, k); err != nil {
+			return "", errors.Wrapf(err, "%s %s", errGetManifest, ref)
+		}
+	}
+	if c.key == nil {
+		return d, nil
+	}
+	if err := c.verify(ctx, ref, d, k); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+// digest returns the digest of the manifest the supplied reference's tag
+// refers to. Registries that do not report it are asked for the manifest,
+// whose digest is computed.
+func (c *Client) digest(ctx context.Context, ref Reference, k Keychain) (string, error) {
+	u := manifestURL(ref, ref.Tag)
+	resp, err := c.do(ctx, http.MethodHead, u, ref, k, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	if d := resp.Header.Get(headerContentDigest); d != "" {
+		return d, nil
+	}
+	b, err := c.get(ctx, u, ref, k, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	return digestOf(b), nil
+}
+
+// get the body of the supplied URL of the supplied reference's registry.
+func (c *Client) get(ctx context.Context, u string, ref Reference, k Keychain, accept []string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, u, ref, k, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	return b, errors.Wrap(err, errReadResponse)
+}
+
+// do sends a request to the supplied reference's registry, authenticating
+// and retrying once if the registry challenges it. Responses other than 2xx
+// are returned as errors.
+func (c *Client) do(ctx context.Context, method, u string, ref Reference, k Keychain, accept []string) (*http.Response, error) {
+	resp, err := c.send(ctx, method, u, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		auth, err := c.authorize(ctx, challenge, ref, k)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = c.send(ctx, method, u, accept, auth); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("%s %s %s: %s", errStatus, method, u, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, method, u string, accept []string, auth string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRequest)
+	}
+	req = req.WithContext(ctx)
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := c.http.Do(req)
+	return resp, errors.Wrap(err, errRequest)
+}
+
+// authorize returns the Authorization header with which to answer the
+// supplied WWW-Authenticate challenge of the supplied reference's registry.
+func (c *Client) authorize(ctx context.Context, challenge string, ref Reference, k Keychain) (string, error) {
+	creds, ok := k[ref.Registry]
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !ok {
+			return "", errors.Errorf("%s: %s", errNoCredentials, ref.Registry)
+		}
+		return "Basic " + basicAuth(creds), nil
+	case "bearer":
+		t, err := c.token(ctx, params, ref, creds, ok)
+		return "Bearer " + t, errors.Wrap(err, errGetToken)
+	}
+	return "", errors.Errorf("%s: %q", errUnsupportedChallenge, challenge)
+}
+
+// token gets a bearer token with which to pull the supplied reference's
+// repository from the realm of the supplied challenge parameters. Tokens are
+// requested anonymously if there are no credentials.
+func (c *Client) token(ctx context.Context, params map[string]string, ref Reference, creds Credentials, hasCreds bool) (string, error) {
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	if s := params["service"]; s != "" {
+		q.Set("service", s)
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	u.RawQuery = q.Encode()
+
+	auth := ""
+	if hasCreds {
+		auth = "Basic " + basicAuth(creds)
+	}
+	resp, err := c.send(ctx, http.MethodGet, u.String(), nil, auth)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", errors.Errorf("%s %s %s: %s", errStatus, http.MethodGet, u, resp.Status)
+	}
+
+	t := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(t); err != nil {
+		return "", errors.Wrap(err, errDecodeToken)
+	}
+	if t.Token != "" {
+		return t.Token, nil
+	}
+	if t.AccessToken != "" {
+		return t.AccessToken, nil
+	}
+	return "", errors.New(errEmptyToken)
+}
+
+// parseChallenge parses the scheme and parameters of the supplied
+// WWW-Authenticate challenge, for example
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io".
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	rest := parts[1]
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+		params[key] = value
+	}
+	return parts[0], params
+}
+
+func basicAuth(c Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
+
+func manifestURL(ref Reference, tagOrDigest string) string {
+	return fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.host(), ref.Repository, tagOrDigest)
+}
+
+func blobURL(ref Reference, digest string) string {
+	return fmt.Sprintf("https://%s/v2/%s/blobs/%s", ref.host(), ref.Repository, digest)
+}
+
+// digestOf returns the sha256 digest of the supplied content.
+func digestOf(b []byte) string {
+	h := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Resolver = &Client{}
+
+// newKey returns a new ECDSA key, and its public key as parsed from PEM.
+func newKey(t *testing.T) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k, pub
+}
+
+// sign returns the base64 encoded ASN.1 ECDSA signature of the supplied
+// payload.
+func sign(t *testing.T, k *ecdsa.PrivateKey, payload []byte) string {
+	t.Helper()
+	h := sha256.Sum256(payload)
+	r, s, err := ecdsa.Sign(rand.Reader, k, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestClientResolve(t *testing.T) {
+	key, pub := newKey(t)
+	_, otherPub := newKey(t)
+
+	manifest := []byte(`{"schemaVersion":2}`)
+	digest := digestOf(manifest)
+	payload := []byte(fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":%q}}}`, digest))
+	signatures, _ := json.Marshal(map[string]interface{}{
+		"layers": []map[string]interface{}{{
+			"digest":      digestOf(payload),
+			"annotations": map[string]string{cosignSignatureAnnotation: sign(t, key, payload)},
+		}},
+	})
+
+	// The registry serves a single image and its cosign signatures to those
+	// that present a bearer token, which it only issues to cool-user.
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if u, p, ok := r.BasicAuth(); !ok || u != "cool-user" || p != "cool-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"cool-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer cool-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="cool"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/team/app/manifests/1.0":
+			w.Header().Set(headerContentDigest, digest)
+			_, _ = w.Write(manifest)
+		case "/v2/team/app/manifests/" + strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix:
+			_, _ = w.Write(signatures)
+		case "/v2/team/app/blobs/" + digestOf(payload):
+			_, _ = w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+	keychain := Keychain{host: {Username: "cool-user", Password: "cool-password"}}
+	tagged, _ := ParseReference(host + "/team/app:1.0")
+	pinned, _ := ParseReference(host + "/team/app@" + digest)
+	missing, _ := ParseReference(host + "/team/app:2.0")
+	tokenURL := fmt.Sprintf("%s/token?scope=repository%%3Ateam%%2Fapp%%3Apull&service=cool", srv.URL)
+
+	type args struct {
+		ref Reference
+		k   Keychain
+		o   []ClientOption
+	}
+
+	type want struct {
+		digest string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Tag": {
+			reason: "A tag should resolve to the digest the registry reports for it.",
+			args:   args{ref: tagged, k: keychain},
+			want:   want{digest: digest},
+		},
+		"Pinned": {
+			reason: "A reference that specifies a digest should resolve to it without contacting the registry.",
+			args:   args{ref: pinned},
+			want:   want{digest: digest},
+		},
+		"MissingTag": {
+			reason: "A tag the registry does not serve should return error.",
+			args:   args{ref: missing, k: keychain},
+			want: want{err: errors.Wrapf(
+				errors.Errorf("%s %s %s: %s", errStatus, http.MethodHead, manifestURL(missing, "2.0"), "404 Not Found"),
+				"%s %s", errGetManifest, missing)},
+		},
+		"NoCredentials": {
+			reason: "A registry that refuses to issue a token anonymously should return error.",
+			args:   args{ref: tagged},
+			want: want{err: errors.Wrapf(
+				errors.Wrap(errors.Errorf("%s %s %s: %s", errStatus, http.MethodGet, tokenURL, "401 Unauthorized"), errGetToken),
+				"%s %s", errGetManifest, tagged)},
+		},
+		"Verified": {
+			reason: "An image signed by the verification key should resolve to its digest.",
+			args:   args{ref: tagged, k: keychain, o: []ClientOption{WithVerificationKey(pub)}},
+			want:   want{digest: digest},
+		},
+		"VerifiedPinned": {
+			reason: "An image pinned to a digest should still have its signature verified.",
+			args:   args{ref: pinned, k: keychain, o: []ClientOption{WithVerificationKey(pub)}},
+			want:   want{digest: digest},
+		},
+		"NotSignedByKey": {
+			reason: "An image that is not signed by the verification key should return error.",
+			args:   args{ref: tagged, k: keychain, o: []ClientOption{WithVerificationKey(otherPub)}},
+			want:   want{err: errors.Errorf("%s %s", errNoValidSignature, tagged)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewClient(append([]ClientOption{WithHTTPClient(srv.Client())}, tc.args.o...)...)
+			got, err := c.Resolve(context.Background(), tc.args.ref, tc.args.k)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nc.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, got); diff != "" {
+				t.Errorf("\nReason: %s\nc.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:team/app:pull,push"`)
+	if scheme != "Bearer" {
+		t.Errorf("parseChallenge(...): want scheme Bearer, got %s", scheme)
+	}
+	want := map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:team/app:pull,push",
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("parseChallenge(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errDecodePEM        = "cannot decode PEM block of public key"
+	errParsePublicKey   = "cannot parse public key"
+	errNotECDSAKey      = "public key is not an ECDSA key"
+	errGetSignatures    = "cannot get cosign signatures of image"
+	errDecodeSignatures = "cannot decode cosign signature manifest of image"
+	errGetPayload       = "cannot get cosign signature payload of image"
+	errNoValidSignature = "no cosign signature made by the verification key was found for image"
+)
+
+// Cosign stores the signatures of an image as the layers of a manifest tagged
+// after the image's digest, in the image's repository. Each layer is a simple
+// signing payload that names the digest it signs, and is annotated with its
+// signature.
+const (
+	cosignSignatureTagSuffix  = ".sig"
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+type signatureManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	} `json:"layers"`
+}
+
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ParsePublicKey parses the supplied PEM encoded ECDSA public key, as
+// generated by cosign generate-key-pair.
+func ParsePublicKey(b []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New(errDecodePEM)
+	}
+	k, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePublicKey)
+	}
+	ek, ok := k.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New(errNotECDSAKey)
+	}
+	return ek, nil
+}
+
+// verify that the image with the supplied digest has a cosign signature made
+// by the Client's verification key.
+func (c *Client) verify(ctx context.Context, ref Reference, digest string, k Keychain) error {
+	tag := strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix
+	b, err := c.get(ctx, manifestURL(ref, tag), ref, k, []string{mediaTypeOCIManifest, mediaTypeDockerManifest})
+	if err != nil {
+		return errors.Wrapf(err, "%s %s", errGetSignatures, ref)
+	}
+	m := &signatureManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return errors.Wrapf(err, "%s %s", errDecodeSignatures, ref)
+	}
+
+	for _, l := range m.Layers {
+		sig, ok := l.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := c.get(ctx, blobURL(ref, l.Digest), ref, k, nil)
+		if err != nil {
+			return errors.Wrapf(err, "%s %s", errGetPayload, ref)
+		}
+		if digestOf(payload) == l.Digest && verifySignature(c.key, payload, sig) && signs(payload, digest) {
+			return nil
+		}
+	}
+	return errors.Errorf("%s %s", errNoValidSignature, ref)
+}
+
+// verifySignature returns true if the supplied base64 encoded ASN.1 ECDSA
+// signature of the supplied payload was made by the supplied key.
+func verifySignature(k *ecdsa.PublicKey, payload []byte, sig string) bool {
+	der, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	s := &ecdsaSignature{}
+	if rest, err := asn1.Unmarshal(der, s); err != nil || len(rest) > 0 {
+		return false
+	}
+	h := sha256.Sum256(payload)
+	return ecdsa.Verify(k, h[:], s.R, s.S)
+}
+
+// signs returns true if the supplied simple signing payload signs the
+// supplied digest.
+func signs(payload []byte, digest string) bool {
+	p := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return false
+	}
+	return p.Critical.Image.DockerManifestDigest == digest
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseDockerConfig = "cannot parse docker config"
+	errDecodeAuth        = "cannot decode registry credentials"
+)
+
+// Registries under which docker config files record credentials for the
+// default registry.
+var defaultRegistryAliases = []string{"index.docker.io", defaultRegistryHost}
+
+// Credentials with which to authenticate to a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// A Keychain holds the credentials with which to authenticate to registries,
+// keyed by registry, for example docker.io or registry.example.com:5000.
+type Keychain map[string]Credentials
+
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// AddDockerConfigJSON adds the credentials of the supplied docker config, as
+// stored under the .dockerconfigjson key of a kubernetes.io/dockerconfigjson
+// Secret, to the Keychain. Existing credentials for the same registry are
+// replaced.
+func (k Keychain) AddDockerConfigJSON(b []byte) error {
+	dc := &dockerConfig{}
+	if err := json.Unmarshal(b, dc); err != nil {
+		return errors.Wrap(err, errParseDockerConfig)
+	}
+	for server, a := range dc.Auths {
+		c := Credentials{Username: a.Username, Password: a.Password}
+		if a.Auth != "" {
+			raw, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return errors.Wrapf(err, "%s for %s", errDecodeAuth, server)
+			}
+			parts := strings.SplitN(string(raw), ":", 2)
+			if len(parts) != 2 {
+				return errors.Errorf("%s for %s", errDecodeAuth, server)
+			}
+			c = Credentials{Username: parts[0], Password: parts[1]}
+		}
+		k[registryOf(server)] = c
+	}
+	return nil
+}
+
+// registryOf returns the registry of the supplied docker config server, which
+// may be a URL such as https://index.docker.io/v1/.
+func registryOf(server string) string {
+	r := server
+	if i := strings.Index(r, "://"); i >= 0 {
+		r = r[i+3:]
+	}
+	if i := strings.Index(r, "/"); i >= 0 {
+		r = r[:i]
+	}
+	for _, alias := range defaultRegistryAliases {
+		if r == alias {
+			return DefaultRegistry
+		}
+	}
+	return r
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAddDockerConfigJSON(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("cool-user:cool:password"))
+	errJSON := json.Unmarshal([]byte("cool"), &dockerConfig{})
+	_, errBase64 := base64.StdEncoding.DecodeString("!")
+
+	type want struct {
+		k   Keychain
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		config string
+		want   want
+	}{
+		"InvalidJSON": {
+			reason: "A docker config that is not JSON should return error.",
+			config: "cool",
+			want:   want{k: Keychain{}, err: errors.Wrap(errJSON, errParseDockerConfig)},
+		},
+		"InvalidAuth": {
+			reason: "An auth that is not base64 encoded should return error.",
+			config: `{"auths":{"registry.example.com":{"auth":"!"}}}`,
+			want:   want{k: Keychain{}, err: errors.Wrapf(errBase64, "%s for %s", errDecodeAuth, "registry.example.com")},
+		},
+		"UsernameAndPassword": {
+			reason: "Credentials specified as a username and password should be added.",
+			config: `{"auths":{"registry.example.com":{"username":"cool-user","password":"cool-password"}}}`,
+			want:   want{k: Keychain{"registry.example.com": {Username: "cool-user", Password: "cool-password"}}},
+		},
+		"Auth": {
+			reason: "Credentials specified as an auth should be decoded, splitting at the first colon.",
+			config: `{"auths":{"https://index.docker.io/v1/":{"auth":"` + auth + `"}}}`,
+			want:   want{k: Keychain{DefaultRegistry: {Username: "cool-user", Password: "cool:password"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			k := Keychain{}
+			err := k.AddDockerConfigJSON([]byte(tc.config))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nAddDockerConfigJSON(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.k, k); diff != "" {
+				t.Errorf("\nReason: %s\nAddDockerConfigJSON(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry resolves container images to the digests of their
+// manifests using the Docker Registry HTTP API V2, and verifies their cosign
+// signatures.
+package registry
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const errParseReference = "cannot parse image reference"
+
+// The registry to which image references without a domain refer, and the
+// host at which its API is served.
+const (
+	DefaultRegistry     = "docker.io"
+	defaultRegistryHost = "registry-1.docker.io"
+	officialRepoPrefix  = "library/"
+)
+
+// A Reference to an image in a registry.
+type Reference struct {
+	// Name of the image as it was referenced, excluding its tag and digest,
+	// for example nginx or registry.example.com/team/app.
+	Name string
+
+	// Registry that serves the image, for example docker.io.
+	Registry string
+
+	// Repository of the image within its registry, for example
+	// library/nginx.
+	Repository string
+
+	// Tag of the image. Defaults to latest if the reference specifies
+	// neither a tag nor a digest.
+	Tag string
+
+	// Digest of the image's manifest, if the reference specifies one.
+	Digest string
+}
+
+// ParseReference parses the supplied image reference, for example nginx:1.17
+// or registry.example.com:5000/team/app@sha256:<digest>.
+func ParseReference(image string) (Reference, error) {
+	r := Reference{Name: image}
+	if i := strings.Index(r.Name, "@"); i >= 0 {
+		r.Name, r.Digest = r.Name[:i], r.Name[i+1:]
+	}
+	if i := strings.LastIndex(r.Name, ":"); i > strings.LastIndex(r.Name, "/") {
+		r.Name, r.Tag = r.Name[:i], r.Name[i+1:]
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = "latest"
+	}
+
+	r.Registry, r.Repository = DefaultRegistry, r.Name
+	if i := strings.Index(r.Name, "/"); i >= 0 && isDomain(r.Name[:i]) {
+		r.Registry, r.Repository = r.Name[:i], r.Name[i+1:]
+	}
+	if r.Registry == DefaultRegistry && !strings.Contains(r.Repository, "/") {
+		r.Repository = officialRepoPrefix + r.Repository
+	}
+
+	if r.Repository == "" || strings.HasSuffix(r.Repository, "/") || (r.Digest != "" && !strings.Contains(r.Digest, ":")) {
+		return Reference{}, errors.Errorf("%s %q", errParseReference, image)
+	}
+	return r, nil
+}
+
+// Pinned returns the image reference, without its tag, pinned to the supplied
+// digest.
+func (r Reference) Pinned(digest string) string {
+	return r.Name + "@" + digest
+}
+
+// String returns the image reference.
+func (r Reference) String() string {
+	s := r.Name
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// host returns the host at which the API of the reference's registry is
+// served.
+func (r Reference) host() string {
+	if r.Registry == DefaultRegistry {
+		return defaultRegistryHost
+	}
+	return r.Registry
+}
+
+// isDomain returns true if the supplied first component of an image name is
+// a registry domain rather than part of the repository.
+func isDomain(c string) bool {
+	return strings.ContainsAny(c, ".:") || c == "localhost"
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	type want struct {
+		ref Reference
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		image  string
+		want   want
+	}{
+		"OfficialImage": {
+			reason: "Images without a registry or tag should refer to the latest tag of the official Docker Hub repository.",
+			image:  "nginx",
+			want:   want{ref: Reference{Name: "nginx", Registry: DefaultRegistry, Repository: "library/nginx", Tag: "latest"}},
+		},
+		"DockerHubImage": {
+			reason: "Images without a registry should refer to Docker Hub.",
+			image:  "team/app:1.0",
+			want:   want{ref: Reference{Name: "team/app", Registry: DefaultRegistry, Repository: "team/app", Tag: "1.0"}},
+		},
+		"RegistryWithPort": {
+			reason: "A first component with a port should be parsed as the registry.",
+			image:  "registry.example.com:5000/team/app:1.0",
+			want:   want{ref: Reference{Name: "registry.example.com:5000/team/app", Registry: "registry.example.com:5000", Repository: "team/app", Tag: "1.0"}},
+		},
+		"Localhost": {
+			reason: "A first component of localhost should be parsed as the registry.",
+			image:  "localhost/app",
+			want:   want{ref: Reference{Name: "localhost/app", Registry: "localhost", Repository: "app", Tag: "latest"}},
+		},
+		"Digest": {
+			reason: "Images pinned to a digest should not default to the latest tag.",
+			image:  "nginx@" + digest,
+			want:   want{ref: Reference{Name: "nginx", Registry: DefaultRegistry, Repository: "library/nginx", Digest: digest}},
+		},
+		"TagAndDigest": {
+			reason: "Images may specify both a tag and a digest.",
+			image:  "gcr.io/team/app:1.0@" + digest,
+			want:   want{ref: Reference{Name: "gcr.io/team/app", Registry: "gcr.io", Repository: "team/app", Tag: "1.0", Digest: digest}},
+		},
+		"InvalidDigest": {
+			reason: "Digests without an algorithm should return error.",
+			image:  "nginx@cool",
+			want:   want{err: errors.Errorf("%s %q", errParseReference, "nginx@cool")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseReference(tc.image)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nParseReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, got); diff != "" {
+				t.Errorf("\nReason: %s\nParseReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReferencePinned(t *testing.T) {
+	ref, err := ParseReference("registry.example.com:5000/team/app:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "registry.example.com:5000/team/app@sha256:cool"
+	if diff := cmp.Diff(want, ref.Pinned("sha256:cool")); diff != "" {
+		t.Errorf("Pinned(...): -want, +got:\n%s", diff)
+	}
+}
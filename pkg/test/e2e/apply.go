@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package e2e
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errApplyTemplate = "cannot apply resource template to remote cluster"
+)
+
+// An ApplyingRemoteOption configures an ApplyingRemote.
+type ApplyingRemoteOption func(*ApplyingRemote)
+
+// WithApplicator specifies how an ApplyingRemote should apply resource
+// templates to its remote cluster.
+func WithApplicator(a resource.Applicator) ApplyingRemoteOption {
+	return func(r *ApplyingRemote) {
+		r.applicator = a
+	}
+}
+
+// An ApplyingRemote stands in for the Crossplane controllers that submit
+// KubernetesApplications to a remote cluster. Unlike a FakeRemote, which only
+// records the objects that would be submitted, it applies the resource
+// templates of each KubernetesApplication to a real remote cluster, so that a
+// suite may run without installing Crossplane. Objects are never deleted from
+// the remote cluster.
+type ApplyingRemote struct {
+	client     client.Reader
+	remote     client.Client
+	applicator resource.Applicator
+}
+
+// NewApplyingRemote returns an ApplyingRemote that reads KubernetesApplications
+// using the supplied client and applies their resource templates using the
+// supplied remote client.
+func NewApplyingRemote(c client.Reader, remote client.Client, o ...ApplyingRemoteOption) *ApplyingRemote {
+	r := &ApplyingRemote{client: c, remote: remote, applicator: resource.ApplyFn(resource.Apply)}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile a KubernetesApplication by applying its resource templates to the
+// remote cluster.
+func (r *ApplyingRemote) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx := context.Background()
+
+	a := &workloadv1alpha1.KubernetesApplication{}
+	if err := r.client.Get(ctx, req.NamespacedName, a); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetKubeApp)
+	}
+
+	objs, err := Templates(a)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, o := range objs {
+		if err := r.applicator.Apply(ctx, r.remote, o); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "%s: %s %s", errApplyTemplate, o.GetKind(), o.GetName())
+		}
+	}
+	return reconcile.Result{}, nil
+}
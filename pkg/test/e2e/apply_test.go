@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ reconcile.Reconciler = &ApplyingRemote{}
+
+func TestApplyingRemote(t *testing.T) {
+	errBoom := errors.New("boom")
+	app := types.NamespacedName{Namespace: "default", Name: "cool"}
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool","namespace":"default"}}`
+	service := `{"apiVersion":"v1","kind":"Service","metadata":{"name":"cool","namespace":"default"}}`
+
+	get := func(templates ...string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			kubeApp(templates...).DeepCopyInto(obj.(*workloadv1alpha1.KubernetesApplication))
+			return nil
+		}
+	}
+
+	type want struct {
+		err     error
+		applied []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		a      resource.ApplyFn
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting a KubernetesApplication should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetKubeApp)},
+		},
+		"Deleted": {
+			reason: "A deleted KubernetesApplication should be ignored.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+		},
+		"ApplyError": {
+			reason: "Errors applying a resource template should be returned.",
+			c:      &test.MockClient{MockGet: get(deployment, service)},
+			a: func(_ context.Context, _ client.Client, o runtime.Object, _ ...resource.ApplyOption) error {
+				if o.(*unstructured.Unstructured).GetKind() == "Service" {
+					return errBoom
+				}
+				return nil
+			},
+			want: want{
+				err:     errors.Wrapf(errBoom, "%s: %s %s", errApplyTemplate, "Service", "cool"),
+				applied: []string{"Deployment"},
+			},
+		},
+		"Applied": {
+			reason: "Every resource template of a KubernetesApplication should be applied to the remote cluster.",
+			c:      &test.MockClient{MockGet: get(deployment, service)},
+			want:   want{applied: []string{"Deployment", "Service"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied []string
+			a := resource.ApplyFn(func(ctx context.Context, c client.Client, o runtime.Object, ao ...resource.ApplyOption) error {
+				if tc.a != nil {
+					if err := tc.a(ctx, c, o, ao...); err != nil {
+						return err
+					}
+				}
+				applied = append(applied, o.(*unstructured.Unstructured).GetKind())
+				return nil
+			})
+
+			r := NewApplyingRemote(tc.c, &test.MockClient{}, WithApplicator(a))
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: app})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
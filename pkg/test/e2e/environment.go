@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e provides a harness for integration tests of OAM workload and
+// trait controllers. It runs the OAM Kubernetes Remote controllers against a
+// local API server started by envtest, with a fake remote cluster standing in
+// for the Crossplane controllers that would submit KubernetesApplications to a
+// remote cluster. Downstream trait and workload authors may use it to test
+// their controllers alongside the real reconcilers.
+package e2e
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+)
+
+const (
+	errStartEnvironment = "cannot start test environment"
+	errStopEnvironment  = "cannot stop test environment"
+	errAddToScheme      = "cannot add APIs to scheme"
+	errCreateManager    = "cannot create controller manager"
+	errSetupControllers = "cannot setup controllers"
+	errSetupFakeRemote  = "cannot setup fake remote cluster"
+	errTimeout          = "timed out"
+)
+
+// Defaults for polling the test environment.
+const (
+	DefaultTimeout  = 30 * time.Second
+	DefaultInterval = 250 * time.Millisecond
+)
+
+// An EnvironmentOption configures an Environment.
+type EnvironmentOption func(*Environment)
+
+// WithCRDPaths specifies directories containing the CRDs that should be
+// installed in the test environment. The CRDs of the OAM core types, of
+// Crossplane's workload types, and of any trait or workload types under test
+// must be installed.
+func WithCRDPaths(paths ...string) EnvironmentOption {
+	return func(e *Environment) {
+		e.env.CRDDirectoryPaths = append(e.env.CRDDirectoryPaths, paths...)
+	}
+}
+
+// WithSetup specifies the controllers that should be run in the test
+// environment. All default OAM Kubernetes Remote controllers are run if none
+// are specified.
+func WithSetup(fns ...controller.SetupFn) EnvironmentOption {
+	return func(e *Environment) {
+		e.setup = append(e.setup, fns...)
+	}
+}
+
+// WithOptions specifies the options the controllers should be run with.
+func WithOptions(o controller.Options) EnvironmentOption {
+	return func(e *Environment) {
+		e.options = o
+	}
+}
+
+// WithSchemeBuilder specifies a function that adds the APIs of any trait or
+// workload types under test to the test environment's scheme.
+func WithSchemeBuilder(add func(*runtime.Scheme) error) EnvironmentOption {
+	return func(e *Environment) {
+		e.schemes = append(e.schemes, add)
+	}
+}
+
+// An Environment runs OAM controllers against a local API server.
+type Environment struct {
+	env     *envtest.Environment
+	setup   []controller.SetupFn
+	options controller.Options
+	schemes []func(*runtime.Scheme) error
+
+	client client.Client
+	remote *FakeRemote
+	stop   chan struct{}
+}
+
+// NewEnvironment returns a new test environment. It must be started before it
+// is used.
+func NewEnvironment(o ...EnvironmentOption) *Environment {
+	e := &Environment{
+		env:     &envtest.Environment{},
+		options: controller.Options{Logger: logging.NewNopLogger()},
+		schemes: []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, controller.AddToScheme},
+	}
+	for _, eo := range o {
+		eo(e)
+	}
+	if len(e.setup) == 0 {
+		e.setup = []controller.SetupFn{controller.SetupAll}
+	}
+	return e
+}
+
+// Start the test environment and its controllers.
+func (e *Environment) Start() error {
+	cfg, err := e.env.Start()
+	if err != nil {
+		return errors.Wrap(err, errStartEnvironment)
+	}
+
+	s := runtime.NewScheme()
+	for _, add := range e.schemes {
+		if err := add(s); err != nil {
+			return errors.Wrap(err, errAddToScheme)
+		}
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: s, MetricsBindAddress: "0"})
+	if err != nil {
+		return errors.Wrap(err, errCreateManager)
+	}
+	if err := controller.Setup(mgr, e.options, e.setup...); err != nil {
+		return errors.Wrap(err, errSetupControllers)
+	}
+
+	e.remote = NewFakeRemote(mgr.GetClient())
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("e2e/fakeremote").
+		For(&workloadv1alpha1.KubernetesApplication{}).
+		Complete(e.remote); err != nil {
+		return errors.Wrap(err, errSetupFakeRemote)
+	}
+
+	e.client = mgr.GetClient()
+	e.stop = make(chan struct{})
+	go func() {
+		// The manager only returns an error if it fails to start, in
+		// which case the test environment's assertions will time out.
+		_ = mgr.Start(e.stop)
+	}()
+
+	return nil
+}
+
+// Stop the test environment and its controllers.
+func (e *Environment) Stop() error {
+	if e.stop != nil {
+		close(e.stop)
+	}
+	return errors.Wrap(e.env.Stop(), errStopEnvironment)
+}
+
+// Client returns a client for the test environment's API server. It reads
+// from the controllers' cache.
+func (e *Environment) Client() client.Client {
+	return e.client
+}
+
+// Remote returns the fake remote cluster of the test environment.
+func (e *Environment) Remote() *FakeRemote {
+	return e.remote
+}
+
+// WaitForKubernetesApplication waits until the KubernetesApplication with the
+// supplied name exists and passes the supplied function.
+func (e *Environment) WaitForKubernetesApplication(ctx context.Context, nn types.NamespacedName, fn func(a *workloadv1alpha1.KubernetesApplication) error) error {
+	return Eventually(ctx, DefaultTimeout, DefaultInterval, func() error {
+		a := &workloadv1alpha1.KubernetesApplication{}
+		if err := e.client.Get(ctx, nn, a); err != nil {
+			return err
+		}
+		if fn == nil {
+			return nil
+		}
+		return fn(a)
+	})
+}
+
+// WaitForDeletion waits until the supplied object no longer exists.
+func (e *Environment) WaitForDeletion(ctx context.Context, obj runtime.Object) error {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+	return Eventually(ctx, DefaultTimeout, DefaultInterval, func() error {
+		err := e.client.Get(ctx, key, obj)
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("%s still exists", key)
+	})
+}
+
+// Eventually calls the supplied function at the supplied interval until it
+// returns nil. It returns the function's last error if it does not do so
+// before the supplied timeout.
+func Eventually(ctx context.Context, timeout, interval time.Duration, fn func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(err, errTimeout)
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+const (
+	errGetKubeApp     = "cannot get KubernetesApplication"
+	errDecodeTemplate = "cannot decode resource template"
+	errNoTemplate     = "no resource template found"
+)
+
+// A FakeRemote stands in for a remote cluster. It records the objects each
+// KubernetesApplication would submit to the remote cluster, as though they
+// had been applied.
+type FakeRemote struct {
+	client client.Reader
+
+	mx      sync.RWMutex
+	objects map[types.NamespacedName][]*unstructured.Unstructured
+}
+
+// NewFakeRemote returns a FakeRemote that reads KubernetesApplications using
+// the supplied client.
+func NewFakeRemote(c client.Reader) *FakeRemote {
+	return &FakeRemote{client: c, objects: make(map[types.NamespacedName][]*unstructured.Unstructured)}
+}
+
+// Reconcile a KubernetesApplication by recording its resource templates.
+func (f *FakeRemote) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	a := &workloadv1alpha1.KubernetesApplication{}
+	if err := f.client.Get(context.Background(), req.NamespacedName, a); err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			f.mx.Lock()
+			delete(f.objects, req.NamespacedName)
+			f.mx.Unlock()
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetKubeApp)
+	}
+
+	objs, err := Templates(a)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	f.mx.Lock()
+	f.objects[req.NamespacedName] = objs
+	f.mx.Unlock()
+	return reconcile.Result{}, nil
+}
+
+// Objects returns the objects the KubernetesApplication with the supplied
+// name has submitted to the remote cluster.
+func (f *FakeRemote) Objects(app types.NamespacedName) []*unstructured.Unstructured {
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+
+	out := make([]*unstructured.Unstructured, 0, len(f.objects[app]))
+	for _, o := range f.objects[app] {
+		out = append(out, o.DeepCopy())
+	}
+	return out
+}
+
+// Object returns the object of the supplied kind and name that the
+// KubernetesApplication with the supplied name has submitted to the remote
+// cluster, if any.
+func (f *FakeRemote) Object(app types.NamespacedName, kind, name string) (*unstructured.Unstructured, bool) {
+	for _, o := range f.Objects(app) {
+		if strings.EqualFold(o.GetKind(), kind) && o.GetName() == name {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// Templates returns the decoded resource templates of the supplied
+// KubernetesApplication.
+func Templates(a *workloadv1alpha1.KubernetesApplication) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, 0, len(a.Spec.ResourceTemplates))
+	for _, t := range a.Spec.ResourceTemplates {
+		u := &unstructured.Unstructured{}
+		if err := json.Unmarshal(t.Spec.Template.Raw, u); err != nil {
+			return nil, errors.Wrap(err, errDecodeTemplate)
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// Template returns the decoded resource template of the supplied kind and name
+// of the supplied KubernetesApplication.
+func Template(a *workloadv1alpha1.KubernetesApplication, kind, name string) (*unstructured.Unstructured, error) {
+	objs, err := Templates(a)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range objs {
+		if strings.EqualFold(o.GetKind(), kind) && o.GetName() == name {
+			return o, nil
+		}
+	}
+	return nil, errors.Errorf("%s: %s %s", errNoTemplate, kind, name)
+}
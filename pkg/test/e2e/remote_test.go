@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+)
+
+var _ reconcile.Reconciler = &FakeRemote{}
+
+func kubeApp(templates ...string) *workloadv1alpha1.KubernetesApplication {
+	a := &workloadv1alpha1.KubernetesApplication{}
+	for _, t := range templates {
+		a.Spec.ResourceTemplates = append(a.Spec.ResourceTemplates, workloadv1alpha1.KubernetesApplicationResourceTemplate{
+			Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{Template: runtime.RawExtension{Raw: []byte(t)}},
+		})
+	}
+	return a
+}
+
+func TestTemplate(t *testing.T) {
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"}}`
+
+	type args struct {
+		a    *workloadv1alpha1.KubernetesApplication
+		kind string
+		name string
+	}
+
+	type want struct {
+		o   *unstructured.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DecodeError": {
+			reason: "Errors decoding a resource template should be returned.",
+			args: args{
+				a:    kubeApp("{"),
+				kind: "Deployment",
+				name: "cool",
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errDecodeTemplate)},
+		},
+		"NotFound": {
+			reason: "An error should be returned if no template has the supplied kind and name.",
+			args: args{
+				a:    kubeApp(deployment),
+				kind: "Service",
+				name: "cool",
+			},
+			want: want{err: errors.Errorf("%s: %s %s", errNoTemplate, "Service", "cool")},
+		},
+		"Found": {
+			reason: "The template of the supplied kind and name should be returned.",
+			args: args{
+				a:    kubeApp(deployment),
+				kind: "deployment",
+				name: "cool",
+			},
+			want: want{o: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "cool"},
+			}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Template(tc.args.a, tc.args.kind, tc.args.name)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\nReason: %s\nTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFakeRemote(t *testing.T) {
+	errBoom := errors.New("boom")
+	app := types.NamespacedName{Namespace: "default", Name: "cool"}
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cool"}}`
+
+	type want struct {
+		err     error
+		objects int
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting a KubernetesApplication should be returned.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetKubeApp)},
+		},
+		"Deleted": {
+			reason: "The objects of a deleted KubernetesApplication should be forgotten.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			want:   want{objects: 0},
+		},
+		"Recorded": {
+			reason: "The resource templates of a KubernetesApplication should be recorded as remote objects.",
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				kubeApp(deployment).DeepCopyInto(obj.(*workloadv1alpha1.KubernetesApplication))
+				return nil
+			}},
+			want: want{objects: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewFakeRemote(tc.c)
+			_, err := f.Reconcile(reconcile.Request{NamespacedName: app})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nf.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.objects, len(f.Objects(app))); diff != "" {
+				t.Errorf("\nReason: %s\nf.Objects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
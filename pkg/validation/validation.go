@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package validation provides validators for the fields of workloads and
+// traits. They are used by the addon's admission webhooks, and may be used by
+// the authors of custom workloads and traits to validate their own kinds the
+// same way.
+package validation
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	errNegativeQuantity = "must not be negative"
+	errImageReference   = "must be a valid image reference, for example nginx:1.17 or registry.example.com/team/app@sha256:<digest>"
+)
+
+// MaxImageNameLength is the maximum length of the name of an image reference,
+// excluding its tag and digest.
+const MaxImageNameLength = 255
+
+// The grammar of an image reference, as accepted by container runtimes. A
+// reference is a name, optionally prefixed by a registry domain, followed by
+// an optional tag and an optional digest.
+var (
+	domainComponent = `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+	domain          = domainComponent + `(?:\.` + domainComponent + `)*(?::[0-9]+)?`
+	nameComponent   = `[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*`
+	name            = `(?:` + domain + `/)?` + nameComponent + `(?:/` + nameComponent + `)*`
+	tag             = `[\w][\w.-]{0,127}`
+	digest          = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[[:xdigit:]]{32,}`
+
+	imageReference = regexp.MustCompile(`^(` + name + `)(?::` + tag + `)?(?:@` + digest + `)?$`)
+)
+
+// DNS1123Label validates that the supplied value is a DNS-1123 label, as
+// required of the names of most namespaced objects and of containers.
+func DNS1123Label(v string, p *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(v) {
+		errs = append(errs, field.Invalid(p, v, msg))
+	}
+	return errs
+}
+
+// DNS1123Subdomain validates that the supplied value is a DNS-1123 subdomain,
+// as required of the names of most cluster scoped objects.
+func DNS1123Subdomain(v string, p *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(v) {
+		errs = append(errs, field.Invalid(p, v, msg))
+	}
+	return errs
+}
+
+// Port validates that the supplied port number is between 1 and 65535.
+func Port(port int32, p *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsValidPortNum(int(port)) {
+		errs = append(errs, field.Invalid(p, port, msg))
+	}
+	return errs
+}
+
+// PortName validates that the supplied value is a valid port name, which must
+// be an IANA service name of no more than 15 characters.
+func PortName(v string, p *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsValidPortName(v) {
+		errs = append(errs, field.Invalid(p, v, msg))
+	}
+	return errs
+}
+
+// ParseQuantity parses the supplied integer or string as a resource quantity.
+func ParseQuantity(v intstr.IntOrString) (resource.Quantity, error) {
+	if v.Type == intstr.Int {
+		return *resource.NewQuantity(int64(v.IntVal), resource.DecimalSI), nil
+	}
+	return resource.ParseQuantity(v.StrVal)
+}
+
+// Quantity validates that the supplied integer or string is a resource
+// quantity that is not negative, for example 2 or "500m".
+func Quantity(v intstr.IntOrString, p *field.Path) field.ErrorList {
+	q, err := ParseQuantity(v)
+	if err != nil {
+		return field.ErrorList{field.Invalid(p, v.String(), err.Error())}
+	}
+	if q.Sign() < 0 {
+		return field.ErrorList{field.Invalid(p, v.String(), errNegativeQuantity)}
+	}
+	return nil
+}
+
+// ImageReference validates that the supplied value is a container image
+// reference, for example nginx, nginx:1.17, or
+// registry.example.com:5000/team/app@sha256:<digest>.
+func ImageReference(v string, p *field.Path) field.ErrorList {
+	if strings.TrimSpace(v) == "" {
+		return field.ErrorList{field.Required(p, "")}
+	}
+	m := imageReference.FindStringSubmatch(v)
+	if m == nil {
+		return field.ErrorList{field.Invalid(p, v, errImageReference)}
+	}
+	if len(m[1]) > MaxImageNameLength {
+		return field.ErrorList{field.TooLong(p, m[1], MaxImageNameLength)}
+	}
+	return nil
+}
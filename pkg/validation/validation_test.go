@@ -0,0 +1,285 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDNS1123Label(t *testing.T) {
+	p := field.NewPath("name")
+
+	cases := map[string]struct {
+		reason string
+		v      string
+		valid  bool
+	}{
+		"Valid": {
+			reason: "A lowercase alphanumeric name with dashes should be valid.",
+			v:      "cool-container-1",
+			valid:  true,
+		},
+		"Uppercase": {
+			reason: "A name with uppercase characters should be invalid.",
+			v:      "Cool",
+		},
+		"Dotted": {
+			reason: "A name with dots is a subdomain, not a label.",
+			v:      "cool.container",
+		},
+		"TooLong": {
+			reason: "A name of more than 63 characters should be invalid.",
+			v:      strings.Repeat("a", 64),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DNS1123Label(tc.v, p)
+			if diff := cmp.Diff(tc.valid, len(got) == 0); diff != "" {
+				t.Errorf("\nReason: %s\nDNS1123Label(...): -want valid, +got valid:\n%s\n%s", tc.reason, diff, got.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestDNS1123Subdomain(t *testing.T) {
+	p := field.NewPath("name")
+
+	cases := map[string]struct {
+		reason string
+		v      string
+		valid  bool
+	}{
+		"Valid": {
+			reason: "A dotted lowercase name should be valid.",
+			v:      "containerizedworkloads.core.oam.dev",
+			valid:  true,
+		},
+		"Underscore": {
+			reason: "A name with underscores should be invalid.",
+			v:      "cool_name",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DNS1123Subdomain(tc.v, p)
+			if diff := cmp.Diff(tc.valid, len(got) == 0); diff != "" {
+				t.Errorf("\nReason: %s\nDNS1123Subdomain(...): -want valid, +got valid:\n%s\n%s", tc.reason, diff, got.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestPort(t *testing.T) {
+	p := field.NewPath("containerPort")
+
+	cases := map[string]struct {
+		reason string
+		port   int32
+		valid  bool
+	}{
+		"Valid": {
+			reason: "Port 8080 should be valid.",
+			port:   8080,
+			valid:  true,
+		},
+		"Highest": {
+			reason: "Port 65535 should be valid.",
+			port:   65535,
+			valid:  true,
+		},
+		"Zero": {
+			reason: "Port 0 should be invalid.",
+			port:   0,
+		},
+		"TooHigh": {
+			reason: "Ports above 65535 should be invalid.",
+			port:   65536,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Port(tc.port, p)
+			if diff := cmp.Diff(tc.valid, len(got) == 0); diff != "" {
+				t.Errorf("\nReason: %s\nPort(...): -want valid, +got valid:\n%s\n%s", tc.reason, diff, got.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestPortName(t *testing.T) {
+	p := field.NewPath("name")
+
+	cases := map[string]struct {
+		reason string
+		v      string
+		valid  bool
+	}{
+		"Valid": {
+			reason: "A name defaulted from a port's protocol and number should be valid.",
+			v:      "tcp-8080",
+			valid:  true,
+		},
+		"TooLong": {
+			reason: "A name of more than 15 characters should be invalid.",
+			v:      "a-very-long-port-name",
+		},
+		"NoLetters": {
+			reason: "A name must contain at least one letter.",
+			v:      "8080",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PortName(tc.v, p)
+			if diff := cmp.Diff(tc.valid, len(got) == 0); diff != "" {
+				t.Errorf("\nReason: %s\nPortName(...): -want valid, +got valid:\n%s\n%s", tc.reason, diff, got.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	p := field.NewPath("required")
+	_, errLots := resource.ParseQuantity("lots")
+
+	type want struct {
+		q    resource.Quantity
+		errs field.ErrorList
+	}
+
+	cases := map[string]struct {
+		reason string
+		v      intstr.IntOrString
+		want   want
+	}{
+		"Int": {
+			reason: "An integer should be parsed as a decimal quantity.",
+			v:      intstr.FromInt(2),
+			want:   want{q: resource.MustParse("2")},
+		},
+		"String": {
+			reason: "A string should be parsed as a quantity.",
+			v:      intstr.FromString("500m"),
+			want:   want{q: resource.MustParse("500m")},
+		},
+		"Negative": {
+			reason: "A negative quantity should be invalid.",
+			v:      intstr.FromString("-1"),
+			want: want{
+				q:    resource.MustParse("-1"),
+				errs: field.ErrorList{field.Invalid(p, "-1", errNegativeQuantity)},
+			},
+		},
+		"NotAQuantity": {
+			reason: "A string that is not a quantity should be invalid.",
+			v:      intstr.FromString("lots"),
+			want: want{
+				errs: field.ErrorList{field.Invalid(p, "lots", errLots.Error())},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q, _ := ParseQuantity(tc.v)
+			if q.Cmp(tc.want.q) != 0 {
+				t.Errorf("\nReason: %s\nParseQuantity(...): want %s, got %s", tc.reason, tc.want.q.String(), q.String())
+			}
+
+			got := Quantity(tc.v, p)
+			if diff := cmp.Diff(tc.want.errs, got); diff != "" {
+				t.Errorf("\nReason: %s\nQuantity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImageReference(t *testing.T) {
+	p := field.NewPath("image")
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	cases := map[string]struct {
+		reason string
+		v      string
+		want   field.ErrorList
+	}{
+		"Name": {
+			reason: "An image name without a tag should be valid.",
+			v:      "nginx",
+		},
+		"Tag": {
+			reason: "An image name with a tag should be valid.",
+			v:      "nginx:1.17",
+		},
+		"Registry": {
+			reason: "An image in a registry with a port, namespaced by team, should be valid.",
+			v:      "registry.example.com:5000/team/app:v1.0.0-rc.1",
+		},
+		"Digest": {
+			reason: "An image pinned to a digest should be valid.",
+			v:      "registry.example.com/team/app@" + digest,
+		},
+		"TagAndDigest": {
+			reason: "An image with both a tag and a digest should be valid.",
+			v:      "app:v1@" + digest,
+		},
+		"Empty": {
+			reason: "An image is required.",
+			v:      "",
+			want:   field.ErrorList{field.Required(p, "")},
+		},
+		"Uppercase": {
+			reason: "An image name with uppercase characters should be invalid.",
+			v:      "Team/App",
+			want:   field.ErrorList{field.Invalid(p, "Team/App", errImageReference)},
+		},
+		"Whitespace": {
+			reason: "An image with whitespace should be invalid.",
+			v:      "nginx 1.17",
+			want:   field.ErrorList{field.Invalid(p, "nginx 1.17", errImageReference)},
+		},
+		"ShortDigest": {
+			reason: "An image with a truncated digest should be invalid.",
+			v:      "nginx@sha256:abc",
+			want:   field.ErrorList{field.Invalid(p, "nginx@sha256:abc", errImageReference)},
+		},
+		"TooLong": {
+			reason: "An image name of more than 255 characters should be invalid.",
+			v:      strings.Repeat("a", 256) + ":v1",
+			want:   field.ErrorList{field.TooLong(p, strings.Repeat("a", 256), MaxImageNameLength)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ImageReference(tc.v, p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nImageReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
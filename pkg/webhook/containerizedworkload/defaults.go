@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package containerizedworkload implements admission webhooks for OAM
+// ContainerizedWorkloads.
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errDecodeWorkload = "cannot decode containerized workload"
+	errEncodeWorkload = "cannot encode containerized workload"
+)
+
+// Default resource requests of containers that do not specify them.
+var (
+	DefaultCPURequest    = resource.MustParse("100m")
+	DefaultMemoryRequest = resource.MustParse("128Mi")
+)
+
+// A DefaulterOption configures a Defaulter.
+type DefaulterOption func(*Defaulter)
+
+// WithDefaultRequests specifies the resource requests of containers that do
+// not specify them.
+func WithDefaultRequests(cpu, memory resource.Quantity) DefaulterOption {
+	return func(d *Defaulter) {
+		d.cpu = cpu
+		d.memory = memory
+	}
+}
+
+// A Defaulter is an admission handler that fills in the fields of a
+// ContainerizedWorkload that are left to the translator's discretion, so that
+// stored workloads are fully specified and always translated the same way.
+type Defaulter struct {
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+// NewDefaulter returns a Defaulter.
+func NewDefaulter(o ...DefaulterOption) *Defaulter {
+	d := &Defaulter{cpu: DefaultCPURequest, memory: DefaultMemoryRequest}
+	for _, do := range o {
+		do(d)
+	}
+	return d
+}
+
+// Handle an admission request for a ContainerizedWorkload.
+func (d *Defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	cw := &oamv1alpha2.ContainerizedWorkload{}
+	if err := json.Unmarshal(req.Object.Raw, cw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeWorkload))
+	}
+
+	d.Default(cw)
+
+	raw, err := json.Marshal(cw)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errEncodeWorkload))
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
+
+// Default the supplied ContainerizedWorkload. Ports that do not specify a
+// protocol use TCP, and ports that are not named are named after their
+// protocol and port number, which the Services of the workload's translation
+// name their ports after. Containers that do not specify CPU or memory
+// requests request the Defaulter's defaults.
+func (d *Defaulter) Default(cw *oamv1alpha2.ContainerizedWorkload) {
+	for i := range cw.Spec.Containers {
+		c := &cw.Spec.Containers[i]
+
+		for j := range c.Ports {
+			p := &c.Ports[j]
+			if p.Protocol == nil {
+				tcp := oamv1alpha2.TransportProtocolTCP
+				p.Protocol = &tcp
+			}
+			if p.Name == "" {
+				p.Name = fmt.Sprintf("%s-%d", strings.ToLower(string(*p.Protocol)), p.Port)
+			}
+		}
+
+		if c.Resources == nil {
+			c.Resources = &oamv1alpha2.ContainerResources{}
+		}
+		if c.Resources.CPU.Required.IsZero() {
+			c.Resources.CPU.Required = d.cpu.DeepCopy()
+		}
+		if c.Resources.Memory.Required.IsZero() {
+			c.Resources.Memory.Required = d.memory.DeepCopy()
+		}
+	}
+}
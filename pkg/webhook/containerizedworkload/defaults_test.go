@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+var _ admission.Handler = &Defaulter{}
+
+func TestDefault(t *testing.T) {
+	tcp := oamv1alpha2.TransportProtocolTCP
+	udp := oamv1alpha2.TransportProtocolUDP
+	cpu := resource.MustParse("1")
+	memory := resource.MustParse("1Gi")
+
+	cases := map[string]struct {
+		reason string
+		o      []DefaulterOption
+		cw     *oamv1alpha2.ContainerizedWorkload
+		want   *oamv1alpha2.ContainerizedWorkload
+	}{
+		"Defaulted": {
+			reason: "Unspecified protocols, port names, and resource requests should be defaulted.",
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name:  "cool",
+					Ports: []oamv1alpha2.ContainerPort{{Port: 8080}, {Port: 53, Protocol: &udp}},
+				}},
+			}},
+			want: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name:  "cool",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "tcp-8080", Port: 8080, Protocol: &tcp}, {Name: "udp-53", Port: 53, Protocol: &udp}},
+					Resources: &oamv1alpha2.ContainerResources{
+						CPU:    oamv1alpha2.CPUResources{Required: DefaultCPURequest},
+						Memory: oamv1alpha2.MemoryResources{Required: DefaultMemoryRequest},
+					},
+				}},
+			}},
+		},
+		"Specified": {
+			reason: "Specified protocols, port names, and resource requests should not be changed.",
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name:  "cool",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080, Protocol: &tcp}},
+					Resources: &oamv1alpha2.ContainerResources{
+						CPU:    oamv1alpha2.CPUResources{Required: cpu},
+						Memory: oamv1alpha2.MemoryResources{Required: memory},
+					},
+				}},
+			}},
+			want: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name:  "cool",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080, Protocol: &tcp}},
+					Resources: &oamv1alpha2.ContainerResources{
+						CPU:    oamv1alpha2.CPUResources{Required: cpu},
+						Memory: oamv1alpha2.MemoryResources{Required: memory},
+					},
+				}},
+			}},
+		},
+		"CustomRequests": {
+			reason: "Resource requests supplied as options should be used as defaults.",
+			o:      []DefaulterOption{WithDefaultRequests(cpu, memory)},
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{Name: "cool"}},
+			}},
+			want: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name: "cool",
+					Resources: &oamv1alpha2.ContainerResources{
+						CPU:    oamv1alpha2.CPUResources{Required: cpu},
+						Memory: oamv1alpha2.MemoryResources{Required: memory},
+					},
+				}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			NewDefaulter(tc.o...).Default(tc.cw)
+			if diff := cmp.Diff(tc.want, tc.cw); diff != "" {
+				t.Errorf("\nReason: %s\nDefault(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaulterHandle(t *testing.T) {
+	request := func(op admissionv1beta1.Operation, raw []byte) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+	defaulted := &oamv1alpha2.ContainerizedWorkload{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+			Containers: []oamv1alpha2.Container{{Name: "cool"}},
+		},
+	}
+	NewDefaulter().Default(defaulted)
+	raw := func(cw *oamv1alpha2.ContainerizedWorkload) []byte {
+		b, _ := json.Marshal(cw)
+		return b
+	}
+
+	type want struct {
+		allowed bool
+		code    int32
+		patched bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   want
+	}{
+		"Delete": {
+			reason: "Requests to delete a workload should be allowed unchanged.",
+			req:    request(admissionv1beta1.Delete, nil),
+			want:   want{allowed: true},
+		},
+		"DecodeError": {
+			reason: "Workloads that cannot be decoded should be rejected.",
+			req:    request(admissionv1beta1.Create, []byte("{")),
+			want:   want{code: http.StatusBadRequest},
+		},
+		"Defaulted": {
+			reason: "Workloads with unspecified fields should be patched.",
+			req: request(admissionv1beta1.Create, raw(&oamv1alpha2.ContainerizedWorkload{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{{Name: "cool"}},
+				},
+			})),
+			want: want{allowed: true, patched: true},
+		},
+		"AlreadyDefaulted": {
+			reason: "Workloads without unspecified fields should not be patched.",
+			req:    request(admissionv1beta1.Update, raw(defaulted)),
+			want:   want{allowed: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewDefaulter().Handle(context.Background(), tc.req)
+
+			if diff := cmp.Diff(tc.want.allowed, got.Allowed); diff != "" {
+				t.Errorf("\nReason: %s\nd.Handle(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+			if !got.Allowed {
+				if diff := cmp.Diff(tc.want.code, got.Result.Code); diff != "" {
+					t.Errorf("\nReason: %s\nd.Handle(...): -want code, +got code:\n%s", tc.reason, diff)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.patched, len(got.Patches) > 0); diff != "" {
+				t.Errorf("\nReason: %s\nd.Handle(...): -want patched, +got patched:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/validation"
+)
+
+// A Validator is an admission handler that rejects ContainerizedWorkloads
+// that could never be translated into valid Kubernetes objects, so that they
+// are surfaced when they are created rather than when they are reconciled.
+type Validator struct{}
+
+// NewValidator returns a Validator.
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Handle an admission request for a ContainerizedWorkload.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	cw := &oamv1alpha2.ContainerizedWorkload{}
+	if err := json.Unmarshal(req.Object.Raw, cw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeWorkload))
+	}
+
+	if errs := v.Validate(cw); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// Validate the supplied ContainerizedWorkload. Containers must be named by a
+// DNS-1123 label and reference a valid image, their ports must be valid port
+// numbers with valid names, and their extended resources must be quantities.
+func (v *Validator) Validate(cw *oamv1alpha2.ContainerizedWorkload) field.ErrorList {
+	var errs field.ErrorList
+	for i, c := range cw.Spec.Containers {
+		p := field.NewPath("spec", "containers").Index(i)
+		errs = append(errs, validation.DNS1123Label(c.Name, p.Child("name"))...)
+		errs = append(errs, validation.ImageReference(c.Image, p.Child("image"))...)
+
+		for j, port := range c.Ports {
+			pp := p.Child("ports").Index(j)
+			errs = append(errs, validation.Port(port.Port, pp.Child("containerPort"))...)
+			if port.Name != "" {
+				errs = append(errs, validation.PortName(port.Name, pp.Child("name"))...)
+			}
+		}
+
+		if c.Resources == nil {
+			continue
+		}
+		for j, e := range c.Resources.Extended {
+			errs = append(errs, validation.Quantity(e.Required, p.Child("resources", "extended").Index(j).Child("required"))...)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package containerizedworkload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+var _ admission.Handler = &Validator{}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cw     *oamv1alpha2.ContainerizedWorkload
+		want   []string
+	}{
+		"Valid": {
+			reason: "A workload with valid names, images, ports, and extended resources should be valid.",
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{{
+					Name:  "cool",
+					Image: "registry.example.com/team/cool:v1",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}, {Port: 53}},
+					Resources: &oamv1alpha2.ContainerResources{
+						Extended: []oamv1alpha2.ExtendedResource{{Name: "example.com/dongle", Required: intstr.FromInt(1)}},
+					},
+				}},
+			}},
+		},
+		"Invalid": {
+			reason: "Every invalid field of every container should be reported.",
+			cw: &oamv1alpha2.ContainerizedWorkload{Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+				Containers: []oamv1alpha2.Container{
+					{Name: "cool", Image: "nginx"},
+					{
+						Name:  "Uncool",
+						Image: "Nginx",
+						Ports: []oamv1alpha2.ContainerPort{{Name: "a-very-long-port-name", Port: 70000}},
+						Resources: &oamv1alpha2.ContainerResources{
+							Extended: []oamv1alpha2.ExtendedResource{{Name: "example.com/dongle", Required: intstr.FromString("lots")}},
+						},
+					},
+				},
+			}},
+			want: []string{
+				"spec.containers[1].name",
+				"spec.containers[1].image",
+				"spec.containers[1].ports[0].containerPort",
+				"spec.containers[1].ports[0].name",
+				"spec.containers[1].resources.extended[0].required",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := NewValidator().Validate(tc.cw)
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Field)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nv.Validate(...): -want invalid fields, +got invalid fields:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidatorHandle(t *testing.T) {
+	request := func(op admissionv1beta1.Operation, raw []byte) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+	raw := func(cw *oamv1alpha2.ContainerizedWorkload) []byte {
+		b, _ := json.Marshal(cw)
+		return b
+	}
+
+	type want struct {
+		allowed bool
+		code    int32
+	}
+
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   want
+	}{
+		"Delete": {
+			reason: "Requests to delete a workload should be allowed.",
+			req:    request(admissionv1beta1.Delete, nil),
+			want:   want{allowed: true},
+		},
+		"DecodeError": {
+			reason: "Workloads that cannot be decoded should be rejected.",
+			req:    request(admissionv1beta1.Create, []byte("{")),
+			want:   want{code: http.StatusBadRequest},
+		},
+		"Invalid": {
+			reason: "Invalid workloads should be denied.",
+			req: request(admissionv1beta1.Update, raw(&oamv1alpha2.ContainerizedWorkload{
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{{Name: "cool"}},
+				},
+			})),
+			want: want{code: http.StatusForbidden},
+		},
+		"Valid": {
+			reason: "Valid workloads should be allowed.",
+			req: request(admissionv1beta1.Create, raw(&oamv1alpha2.ContainerizedWorkload{
+				Spec: oamv1alpha2.ContainerizedWorkloadSpec{
+					Containers: []oamv1alpha2.Container{{Name: "cool", Image: "nginx:1.17"}},
+				},
+			})),
+			want: want{allowed: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewValidator().Handle(context.Background(), tc.req)
+
+			if diff := cmp.Diff(tc.want.allowed, got.Allowed); diff != "" {
+				t.Errorf("\nReason: %s\nv.Handle(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+			if !got.Allowed {
+				if diff := cmp.Diff(tc.want.code, got.Result.Code); diff != "" {
+					t.Errorf("\nReason: %s\nv.Handle(...): -want code, +got code:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
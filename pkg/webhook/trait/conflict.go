@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trait implements admission webhooks for OAM traits.
+package trait
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+
+	remotev1alpha1 "github.com/crossplane/addon-oam-kubernetes-remote/apis/remote/v1alpha1"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+)
+
+const (
+	errDecodeTrait = "cannot decode trait"
+	errListTraits  = "cannot list traits"
+)
+
+// DefaultModifiedFields are the fields of a workload that traits of each kind
+// modify, unless they declare otherwise using the annotations.Modifies
+// annotation.
+var DefaultModifiedFields = map[schema.GroupKind][]string{
+	oamv1alpha2.ManualScalerTraitGroupVersionKind.GroupKind():  {"replicas"},
+	remotev1alpha1.CronScalerTraitGroupVersionKind.GroupKind(): {"replicas"},
+}
+
+// A ConflictValidatorOption configures a ConflictValidator.
+type ConflictValidatorOption func(*ConflictValidator)
+
+// WithModifiedFields specifies the fields of a workload that traits of the
+// supplied kind modify, unless they declare otherwise.
+func WithModifiedFields(gk schema.GroupKind, fields ...string) ConflictValidatorOption {
+	return func(v *ConflictValidator) {
+		v.fields[gk] = fields
+	}
+}
+
+// A ConflictValidator is an admission handler that rejects traits that modify
+// fields of a workload that another trait bound to the same workload already
+// modifies. Conflicting traits are thus surfaced when they are created rather
+// than when they are reconciled.
+type ConflictValidator struct {
+	client client.Reader
+	kinds  []schema.GroupVersionKind
+	fields map[schema.GroupKind][]string
+}
+
+// NewConflictValidator returns a ConflictValidator that checks traits against
+// existing traits of the supplied kinds.
+func NewConflictValidator(c client.Reader, kinds []schema.GroupVersionKind, o ...ConflictValidatorOption) *ConflictValidator {
+	v := &ConflictValidator{client: c, kinds: kinds, fields: make(map[schema.GroupKind][]string)}
+	for gk, f := range DefaultModifiedFields {
+		v.fields[gk] = f
+	}
+	for _, vo := range o {
+		vo(v)
+	}
+	return v
+}
+
+// Handle an admission request for a trait.
+func (v *ConflictValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	t := &unstructured.Unstructured{}
+	if err := t.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeTrait))
+	}
+	if t.GetNamespace() == "" {
+		t.SetNamespace(req.Namespace)
+	}
+
+	fields := v.modified(t)
+	refs := workloadReferences(t)
+	if len(fields) == 0 || len(refs) == 0 {
+		return admission.Allowed("")
+	}
+
+	for _, gvk := range v.kinds {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := v.client.List(ctx, l, client.InNamespace(t.GetNamespace())); err != nil {
+			return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListTraits))
+		}
+
+		for i := range l.Items {
+			existing := &l.Items[i]
+			existing.SetGroupVersionKind(gvk)
+			if sameTrait(t, existing) {
+				continue
+			}
+			w, ok := shared(refs, workloadReferences(existing))
+			if !ok {
+				continue
+			}
+			if c := overlap(fields, v.modified(existing)); len(c) > 0 {
+				return admission.Denied(fmt.Sprintf("%s %q already modifies %s of workload %s", gvk.Kind, existing.GetName(), strings.Join(c, ", "), w))
+			}
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// modified returns the fields the supplied trait modifies.
+func (v *ConflictValidator) modified(t *unstructured.Unstructured) []string {
+	if f := annotations.ModifiedFields(t); len(f) > 0 {
+		return f
+	}
+	return v.fields[t.GroupVersionKind().GroupKind()]
+}
+
+// workloadReferences returns the workloads the supplied trait is bound to,
+// identified by their API version, kind, and name.
+func workloadReferences(t *unstructured.Unstructured) []string {
+	id := func(ref map[string]interface{}) string {
+		av, _, _ := unstructured.NestedString(ref, "apiVersion")
+		k, _, _ := unstructured.NestedString(ref, "kind")
+		n, _, _ := unstructured.NestedString(ref, "name")
+		if n == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s/%s/%s", av, k, n)
+	}
+
+	refs := []string{}
+	if l, ok, _ := unstructured.NestedSlice(t.Object, "spec", "workloadRefs"); ok && len(l) > 0 {
+		for _, r := range l {
+			m, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ref := id(m); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+		return refs
+	}
+	if m, ok, _ := unstructured.NestedMap(t.Object, "spec", "workloadRef"); ok {
+		if ref := id(m); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func sameTrait(a, b *unstructured.Unstructured) bool {
+	return a.GroupVersionKind().GroupKind() == b.GroupVersionKind().GroupKind() && a.GetName() == b.GetName()
+}
+
+// shared returns the first workload in a that is also in b.
+func shared(a, b []string) (string, bool) {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return x, true
+			}
+		}
+	}
+	return "", false
+}
+
+// overlap returns the sorted fields that are in both a and b.
+func overlap(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, f := range b {
+		in[f] = true
+	}
+	o := []string{}
+	for _, f := range a {
+		if in[f] {
+			o = append(o, f)
+			delete(in, f)
+		}
+	}
+	sort.Strings(o)
+	return o
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/reconciler/annotations"
+)
+
+var _ admission.Handler = &ConflictValidator{}
+
+func TestConflictValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	scaler := schema.GroupVersionKind{Group: "core.oam.dev", Version: "v1alpha2", Kind: "ManualScalerTrait"}
+	autoscaler := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "AutoscalerTrait"}
+
+	newTrait := func(gvk schema.GroupVersionKind, name, workload string, modifies string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"workloadRef": map[string]interface{}{
+					"apiVersion": "core.oam.dev/v1alpha2",
+					"kind":       "ContainerizedWorkload",
+					"name":       workload,
+				},
+			},
+		}}
+		u.SetGroupVersionKind(gvk)
+		u.SetNamespace("cool")
+		u.SetName(name)
+		if modifies != "" {
+			u.SetAnnotations(map[string]string{annotations.Modifies: modifies})
+		}
+		return u
+	}
+
+	request := func(op admissionv1beta1.Operation, u *unstructured.Unstructured) admission.Request {
+		raw, _ := json.Marshal(u)
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Namespace: u.GetNamespace(),
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	// list returns a MockListFn that lists the supplied traits when traits
+	// of their kind are listed.
+	list := func(traits ...*unstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			for _, t := range traits {
+				if t.GetKind()+"List" == l.GetKind() {
+					l.Items = append(l.Items, *t)
+				}
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		c   client.Reader
+		o   []ConflictValidatorOption
+		req admission.Request
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+		code   int32
+	}{
+		"Delete": {
+			reason: "Deleting a trait should always be allowed.",
+			args: args{
+				c:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				req: request(admissionv1beta1.Delete, newTrait(scaler, "scale", "wl", "")),
+			},
+			want: true,
+		},
+		"NoModifiedFields": {
+			reason: "A trait that modifies no declared fields should be allowed.",
+			args: args{
+				c:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				req: request(admissionv1beta1.Create, newTrait(autoscaler, "auto", "wl", "")),
+			},
+			want: true,
+		},
+		"ListError": {
+			reason: "Errors listing existing traits should be returned.",
+			args: args{
+				c:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				req: request(admissionv1beta1.Create, newTrait(scaler, "scale", "wl", "")),
+			},
+			want: false,
+			code: http.StatusInternalServerError,
+		},
+		"Conflict": {
+			reason: "A trait that modifies a field another trait bound to the same workload modifies should be denied.",
+			args: args{
+				c:   &test.MockClient{MockList: list(newTrait(scaler, "scale", "wl", ""))},
+				req: request(admissionv1beta1.Create, newTrait(autoscaler, "auto", "wl", "replicas, resources")),
+			},
+			want: false,
+			code: http.StatusForbidden,
+		},
+		"ConflictWithDefaultFields": {
+			reason: "Fields configured for a trait kind should be considered when a trait does not declare any.",
+			args: args{
+				c:   &test.MockClient{MockList: list(newTrait(scaler, "scale", "wl", ""))},
+				o:   []ConflictValidatorOption{WithModifiedFields(autoscaler.GroupKind(), "replicas")},
+				req: request(admissionv1beta1.Create, newTrait(autoscaler, "auto", "wl", "")),
+			},
+			want: false,
+			code: http.StatusForbidden,
+		},
+		"DifferentWorkload": {
+			reason: "Traits bound to different workloads should never conflict.",
+			args: args{
+				c:   &test.MockClient{MockList: list(newTrait(scaler, "scale", "other", ""))},
+				req: request(admissionv1beta1.Create, newTrait(autoscaler, "auto", "wl", "replicas")),
+			},
+			want: true,
+		},
+		"DifferentFields": {
+			reason: "Traits that modify different fields of the same workload should not conflict.",
+			args: args{
+				c:   &test.MockClient{MockList: list(newTrait(scaler, "scale", "wl", ""))},
+				req: request(admissionv1beta1.Create, newTrait(autoscaler, "auto", "wl", "ingress")),
+			},
+			want: true,
+		},
+		"UpdateSelf": {
+			reason: "A trait should not conflict with itself when it is updated.",
+			args: args{
+				c:   &test.MockClient{MockList: list(newTrait(scaler, "scale", "wl", ""))},
+				req: request(admissionv1beta1.Update, newTrait(scaler, "scale", "wl", "")),
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewConflictValidator(tc.args.c, []schema.GroupVersionKind{scaler, autoscaler}, tc.args.o...)
+			got := v.Handle(context.Background(), tc.args.req)
+
+			if diff := cmp.Diff(tc.want, got.Allowed); diff != "" {
+				t.Errorf("\nReason: %s\nv.Handle(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+
+			if got.Allowed {
+				return
+			}
+
+			if diff := cmp.Diff(tc.code, got.Result.Code); diff != "" {
+				t.Errorf("\nReason: %s\nv.Handle(...): -want code, +got code:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
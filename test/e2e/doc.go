@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e is a smoke suite that exercises the full pipeline from a
+// ContainerizedWorkload to a remote Deployment without installing Crossplane.
+// It stands up a host and a remote kind cluster, installs the CRDs of the OAM
+// core types, of Crossplane's workload types, and of the addon in the host
+// cluster, and runs the ContainerizedWorkload controller against it. A stub
+// KubernetesApplication controller stands in for Crossplane, applying the
+// resource templates of each KubernetesApplication directly to the remote
+// cluster.
+//
+// The suite requires docker, kind, and kubectl, and only builds with the e2e
+// build tag:
+//
+//	go test -tags e2e -timeout 20m ./test/e2e/...
+//
+// CRDs are read from the directories listed in E2E_CRD_DIRS, separated by the
+// system's path list separator. By default they are read from the Crossplane
+// module's chart and from config/crd, which is written by make generate. Set
+// E2E_KEEP_CLUSTERS to keep the kind clusters once the suite finishes, and
+// E2E_REUSE_CLUSTERS to run the suite against clusters that were kept.
+package e2e
@@ -0,0 +1,132 @@
+// +build e2e
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+
+	"github.com/crossplane/addon-oam-kubernetes-remote/internal/kind"
+	"github.com/crossplane/addon-oam-kubernetes-remote/pkg/controller"
+	harness "github.com/crossplane/addon-oam-kubernetes-remote/pkg/test/e2e"
+)
+
+const (
+	hostName   = "oam-remote-smoke-host"
+	remoteName = "oam-remote-smoke-remote"
+
+	// The directory of the Crossplane module in which its CRDs are
+	// generated, including those of the OAM core types.
+	crossplaneModule = "github.com/crossplane/crossplane"
+	crossplaneCRDs   = "cluster/charts/crossplane/crds"
+
+	timeout  = 3 * time.Minute
+	interval = 2 * time.Second
+)
+
+var suite = &kind.Suite{
+	HostName:    hostName,
+	RemoteName:  remoteName,
+	Setup:       setup,
+	Controllers: controllers,
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(suite.Run(m, 10*time.Minute))
+}
+
+// setup installs CRDs in the host cluster and waits for them to be
+// established.
+func setup(ctx context.Context, s *kind.Suite) error {
+	dirs, err := crdDirs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.Host.Apply(ctx, dirs...); err != nil {
+		return err
+	}
+	_, err = s.Host.Kubectl(ctx, "wait", "--for", "condition=established", "--timeout", "2m", "crd", "--all")
+	return err
+}
+
+// crdDirs returns the directories from which CRDs should be installed.
+func crdDirs(ctx context.Context) ([]string, error) {
+	if dirs := os.Getenv("E2E_CRD_DIRS"); dirs != "" {
+		return filepath.SplitList(dirs), nil
+	}
+
+	out, err := exec.CommandContext(ctx, "go", "list", "-m", "-f", "{{.Dir}}", crossplaneModule).Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot find directory of module %s", crossplaneModule)
+	}
+	return []string{
+		filepath.Join(strings.TrimSpace(string(out)), crossplaneCRDs),
+		filepath.Join("..", "..", "config", "crd"),
+	}, nil
+}
+
+// controllers sets up the ContainerizedWorkload controller, and a stub
+// KubernetesApplication controller that applies to the remote cluster.
+func controllers(mgr ctrl.Manager, s *kind.Suite) error {
+	if err := controller.Setup(mgr, controller.Options{Logger: logging.NewNopLogger()}, controller.SetupContainerizedWorkload); err != nil {
+		return err
+	}
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("e2e/applyingremote").
+		For(&workloadv1alpha1.KubernetesApplication{}).
+		Complete(harness.NewApplyingRemote(mgr.GetClient(), s.RemoteClient))
+	return errors.Wrap(err, "cannot setup stub KubernetesApplication controller")
+}
+
+func TestContainerizedWorkloadBecomesRemoteDeployment(t *testing.T) {
+	ctx := context.Background()
+	nn := types.NamespacedName{Namespace: "default", Name: "smoke-nginx"}
+
+	if err := suite.Host.Apply(ctx, "manifests/workload.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := harness.Eventually(ctx, timeout, interval, func() error {
+		d := &appsv1.Deployment{}
+		if err := suite.RemoteClient.Get(ctx, nn, d); err != nil {
+			return err
+		}
+		cs := d.Spec.Template.Spec.Containers
+		if len(cs) != 1 || cs[0].Image != "nginx:1.17" {
+			return errors.Errorf("remote deployment has containers %v, want a single nginx:1.17 container", cs)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("The workload's Deployment should exist in the remote cluster: %v", err)
+	}
+}